@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// runAPIKeyCreate makes a per-client API key and prints it; it can't be shown again
+func runAPIKeyCreate(database *db.DB, name string) error {
+	key, apiKey, err := database.CreateAPIKey(name)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created API key %q (%s...)\n\n", apiKey.Name, apiKey.Prefix)
+	fmt.Printf("  %s\n\n", key)
+	fmt.Println("Set it as remote.auth_key (or FOCUS_AGENT_AUTH_KEY) on the client. It won't be shown again.")
+	return nil
+}
+
+// runAPIKeyList prints every API key without the keys themselves
+func runAPIKeyList(database *db.DB) error {
+	keys, err := database.ListAPIKeys()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		fmt.Println("No API keys. Create one with -api-key-create <name>.")
+		return nil
+	}
+
+	for _, key := range keys {
+		lastUsed := "never used"
+		if key.LastUsedAt != nil {
+			lastUsed = "last used " + key.LastUsedAt.Format(time.DateTime)
+		}
+		status := ""
+		if key.RevokedAt != nil {
+			status = " (revoked " + key.RevokedAt.Format(time.DateOnly) + ")"
+		}
+		fmt.Printf("  %-20s %s...  created %s, %s%s\n",
+			key.Name, key.Prefix, key.CreatedAt.Format(time.DateOnly), lastUsed, status)
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/llm"
+)
+
+// benchResult is the throughput of one provider at one worker count
+type benchResult struct {
+	provider  string
+	workers   int
+	threads   int
+	failed    int
+	tasks     int
+	elapsed   time.Duration
+	latencies []time.Duration // Per processed thread
+	tokens    int
+	cost      float64
+	err       error // Set when the provider couldn't be benchmarked at all
+}
+
+// threadsPerMinute is the rate threads made it through the whole pipeline
+func (r *benchResult) threadsPerMinute() float64 {
+	if r.elapsed <= 0 {
+		return 0
+	}
+	return float64(r.threads-r.failed) / r.elapsed.Minutes()
+}
+
+// percentile returns the latency below which the given fraction of threads finished
+func (r *benchResult) percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), r.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[int(p*float64(len(sorted)-1))]
+}
+
+// runBench runs the thread processing pipeline (summary, task extraction and enrichment)
+// over synthetic threads with each provider and worker count, and prints a comparison. LLM
+// calls are real, but the cache and usage go to a scratch database so the benchmark neither
+// reuses nor pollutes cached responses.
+func runBench(ctx context.Context, cfg *config.Config, threadCount int, workerList, providerList string) error {
+	if threadCount <= 0 {
+		return fmt.Errorf("-bench-threads must be positive")
+	}
+	workerCounts, err := parseBenchWorkers(workerList)
+	if err != nil {
+		return err
+	}
+	providers := cfg.LLM.ProviderOrder
+	if providerList != "" {
+		providers = nil
+		for _, provider := range strings.Split(providerList, ",") {
+			providers = append(providers, strings.ToLower(strings.TrimSpace(provider)))
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "focus-agent-bench-")
+	if err != nil {
+		return fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	database, err := db.Init(filepath.Join(dir, "bench.db"))
+	if err != nil {
+		return fmt.Errorf("failed to create scratch database: %w", err)
+	}
+	defer database.Close()
+	if err := db.RunMigrations(database); err != nil {
+		return fmt.Errorf("failed to migrate scratch database: %w", err)
+	}
+
+	var results []*benchResult
+	for _, provider := range providers {
+		for _, workers := range workerCounts {
+			log.Printf("Benchmarking %s with %d worker(s) on %d threads...", provider, workers, threadCount)
+			result := benchProvider(ctx, database, cfg, provider, workers, threadCount)
+			if result.err != nil {
+				log.Printf("Skipping %s: %v", provider, result.err)
+				results = append(results, result)
+				break
+			}
+			results = append(results, result)
+		}
+	}
+
+	printBenchResults(results)
+	return nil
+}
+
+// parseBenchWorkers parses a comma-separated list of worker counts
+func parseBenchWorkers(list string) ([]int, error) {
+	var counts []int
+	for _, field := range strings.Split(list, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid -bench-workers value %q: expected positive numbers", field)
+		}
+		counts = append(counts, n)
+	}
+	return counts, nil
+}
+
+// benchProvider processes a fresh set of synthetic threads with only one provider enabled.
+// Each Ollama host gets as many workers as the pipeline has, so the host pool isn't the limit.
+func benchProvider(ctx context.Context, database *db.DB, cfg *config.Config, provider string, workers, threadCount int) *benchResult {
+	result := &benchResult{provider: provider, workers: workers, threads: threadCount}
+
+	benchCfg := *cfg
+	benchCfg.LLM.ProviderOrder = []string{provider}
+	benchCfg.LLM.Budgets = nil
+	benchCfg.Ollama.Hosts = append([]config.OllamaHost(nil), cfg.Ollama.Hosts...)
+	for i := range benchCfg.Ollama.Hosts {
+		benchCfg.Ollama.Hosts[i].Workers = workers
+	}
+
+	client, err := llm.NewHybridClient(cfg.Gemini.Keys(), database, &benchCfg)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	defer client.Close()
+
+	for _, health := range client.ProviderHealth(ctx) {
+		if health.Provider == provider && !health.Available {
+			result.err = fmt.Errorf("provider not available")
+			return result
+		}
+	}
+	if provider == config.ProviderOllama {
+		client.Warmup(ctx)
+	}
+
+	threads := benchThreads(cfg.Google.UserEmail, threadCount)
+	tokensBefore, costBefore := benchUsage(database)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan []*db.Message)
+	start := time.Now()
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for messages := range queue {
+				threadStart := time.Now()
+				tasks, err := benchThread(ctx, client, messages, len(threads))
+				latency := time.Since(threadStart)
+
+				mu.Lock()
+				if err != nil {
+					log.Printf("Bench thread failed (%s): %v", provider, err)
+					result.failed++
+				} else {
+					result.tasks += tasks
+					result.latencies = append(result.latencies, latency)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, messages := range threads {
+		queue <- messages
+	}
+	close(queue)
+	wg.Wait()
+	result.elapsed = time.Since(start)
+
+	tokensAfter, costAfter := benchUsage(database)
+	result.tokens = tokensAfter - tokensBefore
+	result.cost = costAfter - costBefore
+	return result
+}
+
+// benchThread runs one thread through the LLM steps of ProcessNewMessages and returns the
+// number of tasks extracted
+func benchThread(ctx context.Context, client llm.Client, messages []*db.Message, queueSize int) (int, error) {
+	metadata := llm.ThreadMetadata{
+		QueueSize:    queueSize,
+		SenderEmail:  messages[0].From,
+		Timestamp:    messages[0].Timestamp,
+		MessageCount: len(messages),
+	}
+	summary, err := client.SummarizeThreadWithModelSelection(ctx, messages, metadata)
+	if err != nil {
+		return 0, fmt.Errorf("summary: %w", err)
+	}
+
+	tasks, err := client.ExtractTasksFromMessages(ctx, summary, messages, nil, nil)
+	if err != nil {
+		return 0, fmt.Errorf("extraction: %w", err)
+	}
+	for _, task := range tasks {
+		if _, err := client.EnrichTaskDescription(ctx, task, messages); err != nil {
+			return 0, fmt.Errorf("enrichment: %w", err)
+		}
+	}
+	return len(tasks), nil
+}
+
+// benchUsage returns the tokens and cost logged to the scratch database so far
+func benchUsage(database *db.DB) (int, float64) {
+	var tokens int
+	var cost float64
+	database.QueryRow(`SELECT COALESCE(SUM(tokens), 0), COALESCE(SUM(cost), 0) FROM usage`).Scan(&tokens, &cost)
+	return tokens, cost
+}
+
+// Building blocks for synthetic threads: a mix of requests with deadlines, FYIs and
+// automated mail, like a typical inbox
+var (
+	benchSenders = []string{
+		"Sarah Chen <sarah.chen@example.com>",
+		"Tim Davis <tim.davis@example.com>",
+		"Priya Patel <priya@customer.example>",
+		"Marco Rossi <marco.rossi@example.com>",
+		"Billing <noreply@saas.example>",
+	}
+	benchTopics = []string{"Q4 budget", "hiring plan", "Acme renewal", "launch checklist", "board deck", "security review"}
+	benchBodies = []string{
+		"Could you review the %s and send me your comments by Friday? Finance needs the final numbers before the planning meeting.",
+		"Quick one on the %s: can you approve the latest draft today? We're blocked until it's signed off.",
+		"FYI, I've updated the %s with the changes we discussed. No action needed, just keeping you in the loop.",
+		"Following up on the %s. The customer is asking for a call next week; can you suggest a couple of times and prepare a short update?",
+		"Your invoice for the %s subscription is available. This is an automated message, please do not reply.",
+	}
+	benchReplies = []string{
+		"Thanks, I'll take a look and get back to you.",
+		"Adding a couple of points: we also need legal to check the terms, and the deadline may move to Monday.",
+		"Any update on this? It's holding up the rest of the team.",
+	}
+)
+
+// benchThreads generates synthetic threads of one to three messages, newest first as
+// ProcessNewMessages loads them. Each benchmark run gets new threads so responses cached by
+// an earlier run are never reused.
+func benchThreads(userEmail string, count int) [][]*db.Message {
+	if userEmail == "" {
+		userEmail = "me@example.com"
+	}
+	rng := rand.New(rand.NewSource(1))
+	runID := time.Now().UnixNano()
+	now := time.Now()
+
+	threads := make([][]*db.Message, 0, count)
+	for i := 0; i < count; i++ {
+		threadID := fmt.Sprintf("bench_%d_%d", runID, i)
+		sender := benchSenders[rng.Intn(len(benchSenders))]
+		topic := benchTopics[rng.Intn(len(benchTopics))]
+		subject := fmt.Sprintf("%s (#%d-%d)", strings.ToUpper(topic[:1])+topic[1:], runID%100000, i)
+		body := fmt.Sprintf(benchBodies[rng.Intn(len(benchBodies))], topic)
+
+		messages := []*db.Message{{
+			ID:        threadID + "_0",
+			ThreadID:  threadID,
+			From:      sender,
+			To:        userEmail,
+			Subject:   subject,
+			Snippet:   body,
+			Body:      body,
+			Timestamp: now.Add(-time.Duration(rng.Intn(72)) * time.Hour),
+		}}
+		for j := 1; j < 1+rng.Intn(3); j++ {
+			reply := benchReplies[rng.Intn(len(benchReplies))]
+			messages = append([]*db.Message{{
+				ID:        fmt.Sprintf("%s_%d", threadID, j),
+				ThreadID:  threadID,
+				From:      sender,
+				To:        userEmail,
+				Subject:   "Re: " + subject,
+				Snippet:   reply,
+				Body:      reply,
+				Timestamp: messages[0].Timestamp.Add(time.Duration(j) * time.Hour),
+			}}, messages...)
+		}
+		threads = append(threads, messages)
+	}
+	return threads
+}
+
+// printBenchResults prints a row per provider and worker count, with each provider's best
+// throughput marked
+func printBenchResults(results []*benchResult) {
+	best := make(map[string]float64)
+	for _, r := range results {
+		if r.err == nil && r.threadsPerMinute() > best[r.provider] {
+			best[r.provider] = r.threadsPerMinute()
+		}
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Provider\tWorkers\tThreads\tFailed\tTasks\tTime\tThreads/min\tp50\tp95\tTokens\tCost\t")
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(w, "%s\t-\t-\t-\t-\t-\t-\t-\t-\t-\t-\t%s\n", r.provider, r.err)
+			continue
+		}
+		marker := ""
+		if r.threadsPerMinute() > 0 && r.threadsPerMinute() == best[r.provider] {
+			marker = "← best"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%s\t%.1f\t%s\t%s\t%d\t$%.4f\t%s\n",
+			r.provider, r.workers, r.threads, r.failed, r.tasks, r.elapsed.Round(100*time.Millisecond),
+			r.threadsPerMinute(), r.percentile(0.5).Round(100*time.Millisecond),
+			r.percentile(0.95).Round(100*time.Millisecond), r.tokens, r.cost, marker)
+	}
+	w.Flush()
+}
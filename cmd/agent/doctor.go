@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/embeddings"
+)
+
+// runDoctor prints index health and recent slow queries. It returns false if problems were found.
+func runDoctor(database *db.DB) bool {
+	healthy := true
+
+	fmt.Println("focus-agent doctor")
+	fmt.Println()
+
+	if version, err := db.GetCurrentVersion(database); err == nil {
+		fmt.Printf("Schema version:        %d\n", version)
+	}
+
+	health, err := embeddings.CheckIndexHealth(database)
+	if err != nil {
+		fmt.Printf("✗ Index health check failed: %v\n", err)
+		return false
+	}
+
+	fmt.Println()
+	fmt.Println("Embedding index")
+	fmt.Printf("  Pending tasks:       %d\n", health.PendingTasks)
+	fmt.Printf("  Embeddings:          %d\n", health.EmbeddedTasks)
+	fmt.Printf("  %s Missing embeddings: %d\n", mark(health.MissingEmbeddings == 0), health.MissingEmbeddings)
+	fmt.Printf("  %s Stale embeddings:   %d\n", mark(health.StaleEmbeddings == 0), health.StaleEmbeddings)
+	fmt.Printf("  %s Orphan embeddings:  %d\n", mark(health.OrphanEmbeddings == 0), health.OrphanEmbeddings)
+	fmt.Printf("  %s HNSW index present\n", mark(health.HNSWIndex))
+	if health.MissingEmbeddings > 0 || health.StaleEmbeddings > 0 {
+		fmt.Println("    Run backfill-embeddings to bring the index up to date")
+	}
+	if !health.Healthy() {
+		healthy = false
+	}
+
+	fmt.Println()
+	fmt.Println("Full-text search")
+	fmt.Printf("  %s messages_fts index present\n", mark(health.FTSIndex))
+
+	fmt.Println()
+	fmt.Println("Slow queries (last 7 days)")
+	slow, err := database.GetSlowQueries(time.Now().AddDate(0, 0, -7))
+	if err != nil {
+		fmt.Printf("  ✗ Failed to load slow queries: %v\n", err)
+		return false
+	}
+	if len(slow) == 0 {
+		fmt.Println("  ✓ None recorded")
+	}
+	for _, summary := range slow {
+		fmt.Printf("  %-16s %4d slow  avg %6.0fms  max %6dms  last %s\n",
+			summary.Kind, summary.Count, summary.AvgMs, summary.MaxMs, summary.LastRun.Format("Jan 2 15:04"))
+	}
+
+	return healthy
+}
+
+// mark returns a check or cross for a doctor report line
+func mark(ok bool) string {
+	if ok {
+		return "✓"
+	}
+	return "✗"
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// runDryRun prints what pending migrations and the requested maintenance commands would change,
+// without changing anything
+func runDryRun(database *db.DB, cfg *config.Config, reprocess, cleanup bool) error {
+	fmt.Println("focus-agent dry run - nothing will be changed")
+	fmt.Println()
+
+	applied, err := db.GetAppliedMigrations(database)
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Println("New database: the schema would be created")
+		return nil
+	}
+
+	pending, err := db.PendingMigrations(database)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Migrations")
+	if len(pending) == 0 {
+		fmt.Println("  ✓ None pending")
+	}
+	for _, migration := range pending {
+		fmt.Printf("  %3d  %s\n", migration.Version, migration.Name)
+	}
+	if len(pending) > 0 {
+		fmt.Printf("  A snapshot would be written to %s first\n", cfg.Database.BackupDir)
+	}
+
+	if reprocess {
+		var threads int
+		err := database.QueryRow(`SELECT CAST(COUNT(*) AS INTEGER) FROM threads WHERE summary IS NOT NULL AND summary <> ''`).Scan(&threads)
+		if err != nil {
+			return fmt.Errorf("failed to count threads: %w", err)
+		}
+		preview, err := database.PreviewTaskDelete(db.ReprocessTasksFilter)
+		if err != nil {
+			return err
+		}
+		fmt.Println()
+		fmt.Println("-reprocess-tasks")
+		fmt.Printf("  Threads re-extracted with the LLM: %d\n", threads)
+		printTaskPreview(preview)
+		fmt.Printf("  A snapshot would be written to %s first\n", cfg.Database.BackupDir)
+	}
+
+	if cleanup {
+		userEmail := strings.ToLower(cfg.Google.UserEmail)
+		if userEmail == "" {
+			return fmt.Errorf("user email not set in config")
+		}
+		preview, err := database.PreviewTaskDelete(db.OtherPeoplesTasksFilter, "%"+userEmail+"%")
+		if err != nil {
+			return err
+		}
+		fmt.Println()
+		fmt.Println("-cleanup-other-tasks")
+		printTaskPreview(preview)
+		fmt.Printf("  A snapshot would be written to %s first\n", cfg.Database.BackupDir)
+	}
+
+	if len(pending) > 0 && (reprocess || cleanup) {
+		fmt.Println()
+		fmt.Println("Previews are of the current schema; pending migrations run first for real")
+	}
+	return nil
+}
+
+// printTaskPreview prints how many tasks would be deleted and a sample of them
+func printTaskPreview(preview *db.TaskPreview) {
+	fmt.Printf("  Tasks deleted: %d\n", preview.Count)
+	for _, task := range preview.Samples {
+		id := task.ID
+		if len(id) > 8 {
+			id = id[:8]
+		}
+		line := fmt.Sprintf("    - [%s] %s", id, task.Title)
+		if task.Stakeholder != "" {
+			line += " → " + task.Stakeholder
+		}
+		fmt.Println(line)
+	}
+	if more := preview.Count - len(preview.Samples); more > 0 {
+		fmt.Printf("    ... and %d more\n", more)
+	}
+}
@@ -0,0 +1,509 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v3"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+const (
+	// reproPassphraseEnv encrypts the bundle when set
+	reproPassphraseEnv = "FOCUS_AGENT_REPRO_PASSPHRASE"
+	reproMagic         = "FAREPRO1"
+	reproSampleRows    = 5
+	reproLogLines      = 500
+)
+
+// Columns whose values are enums or identifiers of the app's own making, so they're safe to
+// keep in samples. Every other string is replaced with its length and a salted hash.
+var reproSafeColumns = map[string]bool{
+	"status": true, "source": true, "kind": true, "service": true, "action": true,
+	"effort": true, "sensitivity": true, "recurrence": true, "model": true, "channel": true,
+	"state": true, "type": true, "version": true,
+}
+
+// Keys whose config values are credentials
+var reproSecretKey = regexp.MustCompile(`(?i)(secret|token|key|password|webhook|client_id)`)
+
+var reproRedactions = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`), "<email>"},
+	{regexp.MustCompile(`ya29\.[\w\-]+`), "<google-token>"},
+	{regexp.MustCompile(`AIza[\w\-]{35}`), "<google-api-key>"},
+	{regexp.MustCompile(`xox[abpr]-[\w\-]+`), "<slack-token>"},
+	{regexp.MustCompile(`(?i)bearer\s+[\w\-.=]+`), "Bearer <token>"},
+	{regexp.MustCompile(`https://hooks\.slack\.com/\S+`), "<slack-webhook>"},
+	{regexp.MustCompile(`https://chat\.googleapis\.com/\S+`), "<chat-webhook>"},
+}
+
+// reproBundle collects the files of an export-repro bundle
+type reproBundle struct {
+	files map[string][]byte
+	order []string
+	salt  []byte // Per-bundle hash salt so redacted values only correlate within one bundle
+}
+
+func (b *reproBundle) add(name string, data []byte) {
+	if _, ok := b.files[name]; !ok {
+		b.order = append(b.order, name)
+	}
+	b.files[name] = data
+}
+
+func (b *reproBundle) addJSON(name string, v any) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false) // Keep "<redacted ...>" readable
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	b.add(name, buf.Bytes())
+	return nil
+}
+
+// runExportRepro writes an anonymized bundle for attaching to an issue: schema, row counts,
+// redacted sample rows, config with secrets stripped and the tail of the service logs. The
+// bundle is encrypted when FOCUS_AGENT_REPRO_PASSPHRASE is set.
+func runExportRepro(database *db.DB, cfg *config.Config, configPath, outPath string) error {
+	bundle := &reproBundle{files: make(map[string][]byte), salt: make([]byte, 16)}
+	if _, err := rand.Read(bundle.salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	schemaVersion, _ := db.GetCurrentVersion(database)
+	if err := bundle.addJSON("manifest.json", map[string]any{
+		"focus_agent_version": VERSION,
+		"schema_version":      schemaVersion,
+		"created_at":          time.Now().UTC().Format(time.RFC3339),
+		"go_version":          runtime.Version(),
+		"os":                  runtime.GOOS,
+		"arch":                runtime.GOARCH,
+	}); err != nil {
+		return err
+	}
+
+	schema, tables, err := reproSchema(database)
+	if err != nil {
+		return err
+	}
+	bundle.add("schema.sql", []byte(schema))
+
+	counts := make(map[string]int64, len(tables))
+	for _, table := range tables {
+		var count int64
+		if err := database.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM "%s"`, table)).Scan(&count); err != nil {
+			return fmt.Errorf("failed to count %s: %w", table, err)
+		}
+		counts[table] = count
+
+		samples, err := bundle.sampleRows(database, table)
+		if err != nil {
+			return err
+		}
+		if len(samples) > 0 {
+			if err := bundle.addJSON("samples/"+table+".json", samples); err != nil {
+				return err
+			}
+		}
+	}
+	if err := bundle.addJSON("row_counts.json", counts); err != nil {
+		return err
+	}
+
+	errors, err := reproRecentErrors(database)
+	if err != nil {
+		return err
+	}
+	if err := bundle.addJSON("recent_errors.json", errors); err != nil {
+		return err
+	}
+
+	configYAML, err := bundle.redactConfig(cfg)
+	if err != nil {
+		return err
+	}
+	bundle.add("config.yaml", configYAML)
+
+	logDir := filepath.Join(filepath.Dir(configPath), "log")
+	for _, name := range []string{"out.log", "err.log"} {
+		lines, err := tailFile(filepath.Join(logDir, name), reproLogLines)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return fmt.Errorf("failed to read %s: %w", name, err)
+			}
+			continue
+		}
+		bundle.add("logs/"+name, []byte(redactText(strings.Join(lines, "\n"))+"\n"))
+	}
+
+	var archive bytes.Buffer
+	if err := bundle.writeTarGz(&archive); err != nil {
+		return err
+	}
+
+	data := archive.Bytes()
+	passphrase := os.Getenv(reproPassphraseEnv)
+	if passphrase != "" {
+		if data, err = encryptRepro(data, passphrase); err != nil {
+			return err
+		}
+		if !strings.HasSuffix(outPath, ".enc") {
+			outPath += ".enc"
+		}
+	}
+
+	if err := os.WriteFile(outPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	fmt.Printf("Wrote %s (%d tables, %d files)\n", outPath, len(tables), len(bundle.order))
+	if passphrase == "" {
+		fmt.Printf("Bundle is not encrypted; set %s to encrypt it\n", reproPassphraseEnv)
+	} else {
+		fmt.Println("Bundle is encrypted; share the passphrase separately")
+	}
+	fmt.Println("Review the bundle before attaching it to an issue - log lines may still contain subjects or names")
+	return nil
+}
+
+// reproSchema returns the DDL of every table, view and index, and the names of the base tables
+func reproSchema(database *db.DB) (string, []string, error) {
+	var schema strings.Builder
+	var tables []string
+
+	queries := []struct {
+		query  string
+		tables bool
+	}{
+		{`SELECT table_name, sql FROM duckdb_tables() WHERE schema_name = 'main' AND NOT internal ORDER BY table_name`, true},
+		{`SELECT view_name, sql FROM duckdb_views() WHERE schema_name = 'main' AND NOT internal ORDER BY view_name`, false},
+		{`SELECT index_name, sql FROM duckdb_indexes() WHERE schema_name = 'main' ORDER BY index_name`, false},
+	}
+	for _, q := range queries {
+		rows, err := database.Query(q.query)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read schema: %w", err)
+		}
+		for rows.Next() {
+			var name string
+			var sql *string
+			if err := rows.Scan(&name, &sql); err != nil {
+				rows.Close()
+				return "", nil, fmt.Errorf("failed to read schema: %w", err)
+			}
+			if q.tables {
+				tables = append(tables, name)
+			}
+			if sql != nil {
+				schema.WriteString(strings.TrimRight(*sql, ";\n") + ";\n\n")
+			}
+		}
+		rows.Close()
+	}
+
+	return schema.String(), tables, nil
+}
+
+// sampleRows returns the first few rows of a table with personal data redacted
+func (b *reproBundle) sampleRows(database *db.DB, table string) ([]map[string]any, error) {
+	rows, err := database.Query(fmt.Sprintf(`SELECT * FROM "%s" LIMIT %d`, table, reproSampleRows))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []map[string]any
+	for rows.Next() {
+		values := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("failed to sample %s: %w", table, err)
+		}
+
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			row[column] = b.redactValue(column, values[i])
+		}
+		samples = append(samples, row)
+	}
+	return samples, rows.Err()
+}
+
+// redactValue keeps numbers, booleans, timestamps and safe enum columns, and replaces
+// everything else with a description of its shape
+func (b *reproBundle) redactValue(column string, value any) any {
+	switch v := value.(type) {
+	case nil, bool, int8, int16, int32, int64, uint8, uint16, uint32, uint64, float32, float64, time.Time:
+		return v
+	case string:
+		if reproSafeColumns[strings.ToLower(column)] {
+			return v
+		}
+		return b.redactString(v)
+	case []byte:
+		return fmt.Sprintf("<bytes len=%d>", len(v))
+	case []any:
+		return fmt.Sprintf("<list len=%d>", len(v))
+	case map[string]any:
+		return fmt.Sprintf("<map len=%d>", len(v))
+	default:
+		return fmt.Sprintf("<%T>", v)
+	}
+}
+
+// redactString replaces a string with its length and a short salted hash, so equal values
+// can still be matched up within the bundle
+func (b *reproBundle) redactString(s string) string {
+	if s == "" {
+		return ""
+	}
+	sum := sha256.Sum256(append(append([]byte{}, b.salt...), s...))
+	return fmt.Sprintf("<redacted len=%d #%s>", len(s), hex.EncodeToString(sum[:4]))
+}
+
+// redactConfig returns the config as YAML with credentials removed and personal values
+// (priorities, email addresses, domains) hashed
+func (b *reproBundle) redactConfig(cfg *config.Config) ([]byte, error) {
+	raw, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	b.redactNode(&doc, "")
+
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return out, nil
+}
+
+// redactNode walks the config, where path is the dotted key of the node
+func (b *reproBundle) redactNode(node *yaml.Node, path string) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			b.redactNode(child, path)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			childPath := node.Content[i].Value
+			if path != "" {
+				childPath = path + "." + childPath
+			}
+			b.redactNode(node.Content[i+1], childPath)
+		}
+	case yaml.ScalarNode:
+		if node.Tag != "!!str" || node.Value == "" {
+			return
+		}
+		key := path[strings.LastIndex(path, ".")+1:]
+		switch {
+		case reproSecretKey.MatchString(key):
+			node.Value = "<redacted>"
+		case strings.HasPrefix(path, "priorities."), strings.Contains(key, "domain"), strings.Contains(key, "email"):
+			node.Value = b.redactString(node.Value)
+		default:
+			node.Value = redactText(node.Value)
+		}
+	}
+}
+
+// reproRecentErrors returns the last week's failed API calls with personal data redacted
+func reproRecentErrors(database *db.DB) ([]map[string]any, error) {
+	rows, err := database.Query(`
+		SELECT ts, service, action, error
+		FROM usage
+		WHERE error IS NOT NULL AND error != '' AND ts >= ?
+		ORDER BY ts DESC
+		LIMIT 100
+	`, time.Now().AddDate(0, 0, -7).Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent errors: %w", err)
+	}
+	defer rows.Close()
+
+	errors := []map[string]any{}
+	for rows.Next() {
+		var ts int64
+		var service, action, errStr string
+		if err := rows.Scan(&ts, &service, &action, &errStr); err != nil {
+			return nil, fmt.Errorf("failed to query recent errors: %w", err)
+		}
+		errors = append(errors, map[string]any{
+			"time":    time.Unix(ts, 0).UTC().Format(time.RFC3339),
+			"service": service,
+			"action":  action,
+			"error":   redactText(errStr),
+		})
+	}
+	return errors, rows.Err()
+}
+
+// redactText masks email addresses, tokens and webhook URLs in free text
+func redactText(s string) string {
+	for _, r := range reproRedactions {
+		s = r.pattern.ReplaceAllString(s, r.replacement)
+	}
+	return s
+}
+
+// tailFile returns up to the last n lines of a file
+func tailFile(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	return lines, scanner.Err()
+}
+
+func (b *reproBundle) writeTarGz(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+
+	for _, name := range b.order {
+		data := b.files[name]
+		header := &tar.Header{
+			Name:    "focus-agent-repro/" + name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return gz.Close()
+}
+
+// encryptRepro encrypts a bundle with AES-256-GCM under a scrypt-derived key. The output is
+// the magic header, salt, nonce and ciphertext.
+func encryptRepro(data []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := reproCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	out := append([]byte(reproMagic), salt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, data, []byte(reproMagic)), nil
+}
+
+// runDecryptRepro decrypts a bundle written by -export-repro, using FOCUS_AGENT_REPRO_PASSPHRASE
+func runDecryptRepro(path string) error {
+	passphrase := os.Getenv(reproPassphraseEnv)
+	if passphrase == "" {
+		return fmt.Errorf("%s must be set to decrypt a bundle", reproPassphraseEnv)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+	if len(data) < len(reproMagic)+16 || string(data[:len(reproMagic)]) != reproMagic {
+		return fmt.Errorf("%s is not an encrypted focus-agent bundle", path)
+	}
+	data = data[len(reproMagic):]
+
+	gcm, err := reproCipher(passphrase, data[:16])
+	if err != nil {
+		return err
+	}
+	data = data[16:]
+	if len(data) < gcm.NonceSize() {
+		return fmt.Errorf("%s is truncated", path)
+	}
+
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(reproMagic))
+	if err != nil {
+		return fmt.Errorf("failed to decrypt bundle (wrong passphrase?)")
+	}
+
+	outPath := strings.TrimSuffix(path, ".enc")
+	if outPath == path {
+		outPath += ".tar.gz"
+	}
+	if err := os.WriteFile(outPath, plain, 0600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	fmt.Printf("Wrote %s\n", outPath)
+	return nil
+}
+
+func reproCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
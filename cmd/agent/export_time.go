@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// runExportTime writes tracked time grouped by client and project to path ("-" for stdout)
+func runExportTime(database *db.DB, cfg *config.Config, path, format, from, to string) error {
+	start, end, err := planner.TimeExportRange(from, to)
+	if err != nil {
+		return err
+	}
+
+	entries, err := database.GetTimeEntries(start, end)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", path, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := planner.WriteTimeExport(w, entries, format, cfg.Google.UserEmail); err != nil {
+		return err
+	}
+	if path != "-" {
+		log.Printf("Wrote %d time entries from %s to %s to %s", len(entries),
+			start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02"), path)
+	}
+	return nil
+}
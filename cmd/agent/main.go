@@ -8,15 +8,19 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/alexrabarts/focus-agent/internal/api"
 	"github.com/alexrabarts/focus-agent/internal/config"
 	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/events"
 	"github.com/alexrabarts/focus-agent/internal/front"
 	"github.com/alexrabarts/focus-agent/internal/google"
 	"github.com/alexrabarts/focus-agent/internal/llm"
+	"github.com/alexrabarts/focus-agent/internal/msgraph"
 	"github.com/alexrabarts/focus-agent/internal/planner"
 	"github.com/alexrabarts/focus-agent/internal/scheduler"
 	"github.com/alexrabarts/focus-agent/internal/tui"
@@ -24,18 +28,38 @@ import (
 
 var (
 	configFile      = flag.String("config", os.ExpandEnv("$HOME/.focus-agent/config.yaml"), "Path to configuration file")
+	profile         = flag.String("profile", "", "Named profile to use instead of -config (comma-separated to run several profiles' schedulers in one process)")
 	runOnce         = flag.Bool("once", false, "Run once and exit (for testing)")
 	processOnly     = flag.Bool("process", false, "Process threads with AI and exit")
+	canaryMode      = flag.Bool("canary", false, "Run the pipeline canary once, alerting on failures or slow stages, and exit")
 	authOnly        = flag.Bool("auth", false, "Run OAuth flow only")
+	authFlow        = flag.String("auth-flow", "", "How -auth gets the first Google token: browser, manual or device (default: google.auth_flow)")
 	briefOnly       = flag.Bool("brief", false, "Generate and send brief immediately")
 	apiMode         = flag.Bool("api", false, "Run API server with scheduler (for remote TUI access)")
 	tuiMode         = flag.Bool("tui", false, "Run interactive TUI (Terminal User Interface)")
 	reprocessTasks      = flag.Bool("reprocess-tasks", false, "Re-extract tasks from existing thread summaries with updated parser")
 	enrichTasks         = flag.Bool("enrich-tasks", false, "Enrich descriptions for existing email-extracted tasks with AI context")
 	cleanupOthers       = flag.Bool("cleanup-other-tasks", false, "Delete tasks assigned to other people (one-time cleanup)")
+	backupPath          = flag.String("backup", "", "Copy the database to the given file or directory (checkpointed and verified), then exit")
+	restorePath         = flag.String("restore", "", "Replace the database with a backup made by -backup, keeping the current one alongside it, then exit")
+	apiKeyCreate        = flag.String("api-key-create", "", "Create a per-client API key with the given name, print it, then exit")
+	apiKeyList          = flag.Bool("api-key-list", false, "List API keys, then exit")
+	apiKeyRevoke        = flag.String("api-key-revoke", "", "Revoke the API key with the given name or prefix, then exit")
+	dryRun              = flag.Bool("dry-run", false, "Show what pending migrations, -reprocess-tasks and -cleanup-other-tasks would change, without changing anything, then exit")
 	recalculatePriorities = flag.Bool("recalculate-priorities", false, "Recalculate priority scores and populate matched priorities for all pending tasks")
 	migratePriorities   = flag.Bool("migrate-priorities", false, "Migrate strategic priorities from config.yaml to database (one-time migration)")
 	migrateToDuckDB     = flag.String("migrate-to-duckdb", "", "Migrate SQLite database to DuckDB (provide new DuckDB path)")
+	doctor              = flag.Bool("doctor", false, "Check embedding/search index health and recent slow queries, then exit")
+	exportRepro         = flag.String("export-repro", "", "Write an anonymized debugging bundle (schema, row counts, redacted samples, config, logs) to the given path")
+	decryptRepro        = flag.String("decrypt-repro", "", "Decrypt an encrypted -export-repro bundle using FOCUS_AGENT_REPRO_PASSPHRASE")
+	exportTime          = flag.String("export-time", "", "Write focus and task time grouped by client and project to the given path (- for stdout)")
+	timeFormat          = flag.String("time-format", "csv", "Format for -export-time: csv, harvest or toggl")
+	timeFrom            = flag.String("time-from", "", "First day (YYYY-MM-DD) for -export-time (default: start of this month)")
+	timeTo              = flag.String("time-to", "", "Last day (YYYY-MM-DD) for -export-time (default: today)")
+	benchMode           = flag.Bool("bench", false, "Benchmark thread processing throughput on synthetic threads for each LLM provider and worker count, then exit")
+	benchThreadCount    = flag.Int("bench-threads", 20, "Synthetic threads processed per -bench run")
+	benchWorkers        = flag.String("bench-workers", "1,2,4", "Comma-separated worker counts for -bench")
+	benchProviders      = flag.String("bench-providers", "", "Comma-separated providers for -bench (default: llm.provider_order)")
 	version             = flag.Bool("version", false, "Show version")
 )
 
@@ -49,14 +73,45 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle decrypt-repro mode - doesn't need config or database
+	if *decryptRepro != "" {
+		if err := runDecryptRepro(*decryptRepro); err != nil {
+			log.Fatalf("Failed to decrypt bundle: %v", err)
+		}
+		os.Exit(0)
+	}
+
 	// Setup logging
 	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
 
+	// Several profiles share one scheduler process, each with its own database and clients
+	profiles := profileNames(*profile)
+	if len(profiles) > 1 {
+		if err := runProfiles(profiles); err != nil {
+			log.Fatalf("Failed to run profiles: %v", err)
+		}
+		os.Exit(0)
+	}
+
 	// Load configuration
-	cfg, err := config.Load(*configFile)
+	configPath := *configFile
+	var cfg *config.Config
+	var err error
+	if len(profiles) == 1 {
+		configPath = config.ProfileConfigPath(profiles[0])
+		cfg, err = config.LoadProfile(profiles[0])
+	} else {
+		cfg, err = config.Load(configPath)
+	}
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	if *authFlow != "" {
+		if !slices.Contains(config.AuthFlows, *authFlow) {
+			log.Fatalf("-auth-flow must be one of %s", strings.Join(config.AuthFlows, ", "))
+		}
+		cfg.Google.AuthFlow = *authFlow
+	}
 
 	// Handle migrate-to-duckdb mode
 	if *migrateToDuckDB != "" {
@@ -70,12 +125,39 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Handle bench mode - uses a scratch database, not the configured one
+	if *benchMode {
+		if err := runBench(ctx, cfg, *benchThreadCount, *benchWorkers, *benchProviders); err != nil {
+			log.Fatalf("Benchmark failed: %v", err)
+		}
+		os.Exit(0)
+	}
+
 	// Handle TUI remote mode early - doesn't need database or other services
 	if *tuiMode && cfg.Remote.URL != "" {
 		log.Println("Starting TUI in remote mode...")
-		if err := tui.Start(nil, nil, nil, nil, nil, cfg); err != nil {
+		next, err := tui.Start(nil, nil, nil, nil, nil, cfg)
+		if err != nil {
 			log.Fatalf("TUI error: %v", err)
 		}
+		if next != "" {
+			if err := relaunchWithProfile(next); err != nil {
+				log.Fatalf("Failed to switch profile: %v", err)
+			}
+		}
+		os.Exit(0)
+	}
+
+	// Handle restore mode - the database must not be open
+	if *restorePath != "" {
+		previous, err := db.Restore(*restorePath, cfg.Database.Path)
+		if err != nil {
+			log.Fatalf("Restore failed: %v", err)
+		}
+		log.Printf("Restored %s from %s", cfg.Database.Path, *restorePath)
+		if previous != "" {
+			log.Printf("The database it replaced was kept at %s", previous)
+		}
 		os.Exit(0)
 	}
 
@@ -86,28 +168,117 @@ func main() {
 	}
 	defer database.Close()
 
+	// Handle backup mode - copies the database before migrations touch it
+	if *backupPath != "" {
+		result, err := database.Backup(*backupPath)
+		if err != nil {
+			log.Fatalf("Backup failed: %v", err)
+		}
+		log.Printf("Backed up to %s (%.1f MB, %d tables, %d rows verified)",
+			result.Path, float64(result.Bytes)/(1<<20), result.Tables, result.Rows)
+		os.Exit(0)
+	}
+
+	// Handle dry-run mode - previews destructive changes before migrations make any
+	if *dryRun {
+		if err := runDryRun(database, cfg, *reprocessTasks, *cleanupOthers); err != nil {
+			log.Fatalf("Dry run failed: %v", err)
+		}
+		os.Exit(0)
+	}
+
+	// Snapshot the database before migrations change it
+	if path, err := db.SnapshotBeforeMigrations(database, cfg.Database.BackupDir, cfg.Database.BackupKeep); err != nil {
+		log.Fatalf("Failed to snapshot database before migrations: %v", err)
+	} else if path != "" {
+		log.Printf("Snapshot written to %s", path)
+	}
+
 	// Run migrations
 	if err := db.RunMigrations(database); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
-	// Initialize Google clients
+	database.SetSlowQueryThreshold(time.Duration(cfg.Database.SlowQueryMs) * time.Millisecond)
+
+	// Handle doctor mode - only needs the database
+	if *doctor {
+		if !runDoctor(database) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	// Handle export-repro mode - only needs the database and config
+	if *exportRepro != "" {
+		if err := runExportRepro(database, cfg, configPath, *exportRepro); err != nil {
+			log.Fatalf("Failed to export bundle: %v", err)
+		}
+		os.Exit(0)
+	}
+
+	// Handle export-time mode - only needs the database and config
+	if *exportTime != "" {
+		if err := runExportTime(database, cfg, *exportTime, *timeFormat, *timeFrom, *timeTo); err != nil {
+			log.Fatalf("Failed to export time: %v", err)
+		}
+		os.Exit(0)
+	}
+
+	// Handle API key modes - only need the database
+	if *apiKeyCreate != "" {
+		if err := runAPIKeyCreate(database, *apiKeyCreate); err != nil {
+			log.Fatalf("Failed to create API key: %v", err)
+		}
+		os.Exit(0)
+	}
+	if *apiKeyList {
+		if err := runAPIKeyList(database); err != nil {
+			log.Fatalf("Failed to list API keys: %v", err)
+		}
+		os.Exit(0)
+	}
+	if *apiKeyRevoke != "" {
+		if err := database.RevokeAPIKey(*apiKeyRevoke); err != nil {
+			log.Fatalf("Failed to revoke API key: %v", err)
+		}
+		log.Printf("Revoked API key %s", *apiKeyRevoke)
+		os.Exit(0)
+	}
+
+	// Initialize Google clients. The token is only refreshed when a command first calls Google.
 	googleClients, err := google.NewClients(ctx, cfg)
 	if err != nil {
 		log.Fatalf("Failed to initialize Google clients: %v", err)
 	}
 
+	// Initialize Microsoft 365 client (conditional)
+	var msgraphClient *msgraph.Client
+	if cfg.MSGraph.Enabled {
+		msgraphClient, err = msgraph.NewClient(ctx, cfg)
+		if err != nil {
+			log.Fatalf("Failed to initialize Microsoft 365 client: %v", err)
+		}
+		log.Println("Microsoft 365 client initialized")
+	}
+
 	// Handle auth-only mode
 	if *authOnly {
+		// Clients don't touch the network until they're used, so check the token works now
+		if err := googleClients.Authenticate(ctx); err != nil {
+			log.Fatalf("Authentication failed: %v", err)
+		}
 		log.Println("Authentication successful!")
 		os.Exit(0)
 	}
 
 	// Initialize Hybrid LLM client (Claude primary, Gemini fallback)
-	llmClient, err := llm.NewHybridClient(cfg.Gemini.APIKey, database, cfg)
+	hybridClient, err := llm.NewHybridClient(cfg.Gemini.Keys(), database, cfg)
 	if err != nil {
 		log.Fatalf("Failed to initialize LLM client: %v", err)
 	}
+	// Generated text is sanitized before it's stored, since it's derived from untrusted email
+	llmClient := llm.Sanitized(hybridClient)
 	defer llmClient.Close()
 
 	// Initialize planner
@@ -119,6 +290,7 @@ func main() {
 		cfg.Front.Enabled, len(cfg.Front.APIToken), cfg.Front.InboxID)
 	if cfg.Front.Enabled {
 		frontClient = front.NewClient(cfg.Front.APIToken)
+		plannerService.SetFront(frontClient)
 		log.Println("Front client initialized")
 	} else {
 		log.Println("Front integration disabled in config")
@@ -142,6 +314,15 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Handle canary mode
+	if *canaryMode {
+		sched := scheduler.New(database, googleClients, llmClient, plannerService, frontClient, cfg)
+		if problems := sched.RunCanary(); len(problems) > 0 {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Handle reprocess-tasks mode
 	if *reprocessTasks {
 		log.Println("Re-extracting tasks from existing thread summaries...")
@@ -242,14 +423,26 @@ func main() {
 	// Handle TUI mode (local mode only - remote mode handled earlier)
 	if *tuiMode {
 		log.Println("Starting TUI in local mode...")
-		if err := tui.Start(database, googleClients, llmClient, plannerService, frontClient, cfg); err != nil {
+		next, err := tui.Start(database, googleClients, llmClient, plannerService, frontClient, cfg)
+		if err != nil {
 			log.Fatalf("TUI error: %v", err)
 		}
+		if next != "" {
+			// The new process opens its own database, so release this one first
+			llmClient.Close()
+			database.Close()
+			if err := relaunchWithProfile(next); err != nil {
+				log.Fatalf("Failed to switch profile: %v", err)
+			}
+		}
 		os.Exit(0)
 	}
 
 	// Initialize scheduler first
 	sched := scheduler.New(database, googleClients, llmClient, plannerService, frontClient, cfg)
+	if msgraphClient != nil {
+		sched.SetMSGraph(msgraphClient)
+	}
 
 	// Handle API mode or if API is enabled in config
 	if *apiMode || cfg.API.Enabled {
@@ -257,6 +450,12 @@ func main() {
 		apiServer := api.NewServer(database, googleClients, llmClient, plannerService, cfg)
 		apiServer.SetScheduler(sched)
 
+		// Push sync, processing and delivery events to /api/events subscribers
+		bus := events.NewBus()
+		sched.SetEvents(bus)
+		plannerService.SetEvents(bus)
+		apiServer.SetEvents(bus)
+
 		go func() {
 			log.Printf("API server starting on port %d", cfg.API.Port)
 			if err := apiServer.Start(cfg.API.Port); err != nil && err != http.ErrServerClosed {
@@ -271,7 +470,7 @@ func main() {
 	// Handle run-once mode
 	if *runOnce {
 		log.Println("Running sync once...")
-		if err := runSync(ctx, googleClients, database, llmClient); err != nil {
+		if err := runSync(ctx, googleClients, msgraphClient, database, llmClient); err != nil {
 			log.Fatalf("Sync failed: %v", err)
 		}
 		log.Println("Sync completed successfully!")
@@ -294,7 +493,7 @@ func main() {
 	time.Sleep(1 * time.Second)
 }
 
-func runSync(ctx context.Context, clients *google.Clients, database *db.DB, llm llm.Client) error {
+func runSync(ctx context.Context, clients *google.Clients, msgraphClient *msgraph.Client, database *db.DB, llm llm.Client) error {
 	log.Println("╔═══════════════════════════════════════════════════════╗")
 	log.Println("║           STARTING FULL SYNC                          ║")
 	log.Println("╚═══════════════════════════════════════════════════════╝")
@@ -323,6 +522,19 @@ func runSync(ctx context.Context, clients *google.Clients, database *db.DB, llm
 		return fmt.Errorf("tasks sync failed: %w", err)
 	}
 
+	// Microsoft 365 sync
+	if msgraphClient != nil {
+		log.Println("\n📨 Syncing Outlook mail...")
+		if err := msgraphClient.Mail.SyncMail(ctx, database); err != nil {
+			return fmt.Errorf("outlook mail sync failed: %w", err)
+		}
+
+		log.Println("\n📆 Syncing Outlook calendar...")
+		if err := msgraphClient.Calendar.SyncEvents(ctx, database); err != nil {
+			return fmt.Errorf("outlook calendar sync failed: %w", err)
+		}
+	}
+
 	// Show summary
 	log.Println("\n╔═══════════════════════════════════════════════════════╗")
 	log.Println("║           SYNC SUMMARY                                ║")
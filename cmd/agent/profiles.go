@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/api"
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/events"
+	"github.com/alexrabarts/focus-agent/internal/front"
+	"github.com/alexrabarts/focus-agent/internal/google"
+	"github.com/alexrabarts/focus-agent/internal/llm"
+	"github.com/alexrabarts/focus-agent/internal/msgraph"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+	"github.com/alexrabarts/focus-agent/internal/scheduler"
+)
+
+// profileNames splits the -profile flag into profile names
+func profileNames(flagValue string) []string {
+	var names []string
+	for _, name := range strings.Split(flagValue, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// relaunchWithProfile replaces this process with the same binary running the TUI for another profile
+func relaunchWithProfile(name string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+	return syscall.Exec(exe, []string{exe, "-tui", "-profile", name}, os.Environ())
+}
+
+// runProfiles runs the scheduler (and API server, where enabled) for several profiles in one
+// process until interrupted
+func runProfiles(names []string) error {
+	if *runOnce || *processOnly || *authOnly || *briefOnly || *tuiMode || *reprocessTasks || *enrichTasks ||
+		*cleanupOthers || *recalculatePriorities || *migratePriorities || *migrateToDuckDB != "" || *doctor || *exportRepro != "" ||
+		*exportTime != "" {
+		return fmt.Errorf("multiple profiles can only be run as a scheduler; pass a single -profile for other modes")
+	}
+
+	var cfgs []*config.Config
+	for _, name := range names {
+		cfg, err := config.LoadProfile(name)
+		if err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+		if *apiMode {
+			cfg.API.Enabled = true
+		}
+		cfgs = append(cfgs, cfg)
+	}
+	if err := config.CheckProfilesIsolated(cfgs); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var stops []func()
+	defer func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}()
+
+	for _, cfg := range cfgs {
+		stop, err := startProfile(ctx, cfg)
+		if err != nil {
+			return fmt.Errorf("profile %s: %w", cfg.Profile, err)
+		}
+		stops = append(stops, stop)
+		log.Printf("Profile %s started (database %s)", cfg.Profile, cfg.Database.Path)
+	}
+
+	// Wait for interrupt signal
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+
+	log.Println("Shutting down...")
+	return nil
+}
+
+// startProfile opens a profile's database and clients and starts its scheduler, returning a
+// function that stops it again
+func startProfile(ctx context.Context, cfg *config.Config) (func(), error) {
+	database, err := db.Init(cfg.Database.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	if path, err := db.SnapshotBeforeMigrations(database, cfg.Database.BackupDir, cfg.Database.BackupKeep); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("failed to snapshot database before migrations: %w", err)
+	} else if path != "" {
+		log.Printf("Snapshot written to %s", path)
+	}
+	if err := db.RunMigrations(database); err != nil {
+		database.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+	database.SetSlowQueryThreshold(time.Duration(cfg.Database.SlowQueryMs) * time.Millisecond)
+
+	googleClients, err := google.NewClients(ctx, cfg)
+	if err != nil {
+		database.Close()
+		return nil, fmt.Errorf("failed to initialize Google clients: %w", err)
+	}
+
+	var msgraphClient *msgraph.Client
+	if cfg.MSGraph.Enabled {
+		msgraphClient, err = msgraph.NewClient(ctx, cfg)
+		if err != nil {
+			database.Close()
+			return nil, fmt.Errorf("failed to initialize Microsoft 365 client: %w", err)
+		}
+	}
+
+	hybridClient, err := llm.NewHybridClient(cfg.Gemini.Keys(), database, cfg)
+	if err != nil {
+		database.Close()
+		return nil, fmt.Errorf("failed to initialize LLM client: %w", err)
+	}
+	llmClient := llm.Sanitized(hybridClient)
+
+	plannerService := planner.New(database, googleClients, llmClient, cfg)
+
+	var frontClient *front.Client
+	if cfg.Front.Enabled {
+		frontClient = front.NewClient(cfg.Front.APIToken)
+		plannerService.SetFront(frontClient)
+	}
+
+	sched := scheduler.New(database, googleClients, llmClient, plannerService, frontClient, cfg)
+	if msgraphClient != nil {
+		sched.SetMSGraph(msgraphClient)
+	}
+
+	if cfg.API.Enabled {
+		apiServer := api.NewServer(database, googleClients, llmClient, plannerService, cfg)
+		apiServer.SetScheduler(sched)
+
+		bus := events.NewBus()
+		sched.SetEvents(bus)
+		plannerService.SetEvents(bus)
+		apiServer.SetEvents(bus)
+
+		go func() {
+			log.Printf("API server for profile %s starting on port %d", cfg.Profile, cfg.API.Port)
+			if err := apiServer.Start(cfg.API.Port); err != nil && err != http.ErrServerClosed {
+				log.Printf("API server error (profile %s): %v", cfg.Profile, err)
+			}
+		}()
+	}
+
+	if err := sched.Start(); err != nil {
+		llmClient.Close()
+		database.Close()
+		return nil, fmt.Errorf("failed to start scheduler: %w", err)
+	}
+
+	return func() {
+		sched.Stop()
+		llmClient.Close()
+		database.Close()
+	}, nil
+}
@@ -35,7 +35,7 @@ func main() {
 		log.Fatalf("Failed to initialize Google clients: %v", err)
 	}
 
-	llmClient, err := llm.NewHybridClient(cfg.Gemini.APIKey, database, cfg)
+	llmClient, err := llm.NewHybridClient(cfg.Gemini.Keys(), database, cfg)
 	if err != nil {
 		log.Fatalf("Failed to initialize LLM client: %v", err)
 	}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// Analytics query response structure
+type AnalyticsQueryResponse struct {
+	Columns    []string        `json:"columns"`
+	Rows       [][]interface{} `json:"rows"`
+	Truncated  bool            `json:"truncated"`
+	DurationMs int64           `json:"duration_ms"`
+}
+
+// POST /api/analytics/query - Run a restricted read-only query for dashboards and saved reports
+func (s *Server) handleAnalyticsQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req struct {
+		SQL string `json:"sql"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	limits := db.AnalyticsLimits{
+		AllowedTables: s.config.Analytics.AllowedTables,
+		MaxRows:       s.config.Analytics.MaxRows,
+		Timeout:       time.Duration(s.config.Analytics.TimeoutSeconds) * time.Second,
+	}
+
+	result, err := s.database.RunAnalyticsQuery(r.Context(), req.SQL, limits)
+	if err != nil {
+		var queryErr *db.AnalyticsQueryError
+		if errors.As(err, &queryErr) {
+			log.Printf("Rejected analytics query: %v", err)
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AnalyticsQueryResponse{
+		Columns:    result.Columns,
+		Rows:       result.Rows,
+		Truncated:  result.Truncated,
+		DurationMs: result.Duration.Milliseconds(),
+	})
+}
+
+// GET /api/analytics/tables - List the tables and views analytics queries may read
+func (s *Server) handleAnalyticsTables(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"tables":   s.config.Analytics.AllowedTables,
+		"max_rows": s.config.Analytics.MaxRows,
+	})
+}
@@ -0,0 +1,453 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// CalDAV paths: the root doubles as the principal and calendar home, holding one task list
+const (
+	caldavRoot   = "/caldav/"
+	caldavTasks  = "/caldav/tasks/"
+	caldavSource = "caldav" // Source of tasks created in a task app
+)
+
+// maxCalDAVBody caps the size of an uploaded task
+const maxCalDAVBody = 1 << 20
+
+// davResponse is one resource in a multistatus reply, with its properties as rendered XML
+type davResponse struct {
+	href  string
+	props []string
+}
+
+// handleCalDAV serves open tasks as a CalDAV VTODO collection, so native task apps can list,
+// add, edit and complete them. Apps sign in with any username and an API key as the password.
+func (s *Server) handleCalDAV(w http.ResponseWriter, r *http.Request) {
+	// Discovery asks what the server supports before signing in
+	if r.Method == http.MethodOptions {
+		w.Header().Set("DAV", "1, 3, calendar-access")
+		w.Header().Set("Allow", "OPTIONS, GET, PUT, DELETE, PROPFIND, REPORT")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !s.isOwnerToken(caldavToken(r)) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="focus-agent"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	p := r.URL.Path
+	switch {
+	case p == caldavRoot || p == strings.TrimSuffix(caldavRoot, "/"):
+		if r.Method != "PROPFIND" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.caldavPropfindRoot(w, r)
+
+	case p == caldavTasks || p == strings.TrimSuffix(caldavTasks, "/"):
+		switch r.Method {
+		case "PROPFIND":
+			s.caldavPropfindTasks(w, r)
+		case "REPORT":
+			s.caldavReport(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+
+	case strings.HasPrefix(p, caldavTasks) && strings.HasSuffix(p, ".ics"):
+		name := strings.TrimSuffix(path.Base(p), ".ics")
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			s.caldavGet(w, r, name)
+		case http.MethodPut:
+			s.caldavPut(w, r, name)
+		case http.MethodDelete:
+			s.caldavDelete(w, r, name)
+		case "PROPFIND":
+			task := s.caldavTask(name)
+			if task == nil {
+				http.NotFound(w, r)
+				return
+			}
+			writeMultistatus(w, []davResponse{taskResponse(task, false)})
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// caldavToken takes the API key from Basic auth, which task apps use, or a bearer token
+func caldavToken(r *http.Request) string {
+	if _, password, ok := r.BasicAuth(); ok {
+		return password
+	}
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// caldavPropfindRoot describes the principal, which is also the calendar home
+func (s *Server) caldavPropfindRoot(w http.ResponseWriter, r *http.Request) {
+	responses := []davResponse{{
+		href: caldavRoot,
+		props: []string{
+			`<D:resourcetype><D:collection/><D:principal/></D:resourcetype>`,
+			`<D:displayname>focus-agent</D:displayname>`,
+			`<D:current-user-principal><D:href>` + caldavRoot + `</D:href></D:current-user-principal>`,
+			`<D:principal-URL><D:href>` + caldavRoot + `</D:href></D:principal-URL>`,
+			`<C:calendar-home-set><D:href>` + caldavRoot + `</D:href></C:calendar-home-set>`,
+		},
+	}}
+	if r.Header.Get("Depth") != "0" {
+		collection, err := s.tasksCollection()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		responses = append(responses, collection)
+	}
+	writeMultistatus(w, responses)
+}
+
+// caldavPropfindTasks describes the task list and, at depth 1, each task in it
+func (s *Server) caldavPropfindTasks(w http.ResponseWriter, r *http.Request) {
+	collection, err := s.tasksCollection()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	responses := []davResponse{collection}
+
+	if r.Header.Get("Depth") != "0" {
+		tasks, err := s.caldavTasks()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		for _, task := range tasks {
+			responses = append(responses, taskResponse(task, false))
+		}
+	}
+	writeMultistatus(w, responses)
+}
+
+// tasksCollection describes the task list. Its ctag changes whenever any task in it does, so
+// apps know when to look for changes.
+func (s *Server) tasksCollection() (davResponse, error) {
+	tasks, err := s.caldavTasks()
+	if err != nil {
+		return davResponse{}, err
+	}
+	h := sha256.New()
+	for _, task := range tasks {
+		fmt.Fprintf(h, "%s %s\n", task.ID, taskETag(task))
+	}
+
+	return davResponse{
+		href: caldavTasks,
+		props: []string{
+			`<D:resourcetype><D:collection/><C:calendar/></D:resourcetype>`,
+			`<D:displayname>` + xmlEscape(s.config.CalDAV.Name) + `</D:displayname>`,
+			`<C:supported-calendar-component-set><C:comp name="VTODO"/></C:supported-calendar-component-set>`,
+			fmt.Sprintf(`<CS:getctag>%x</CS:getctag>`, h.Sum(nil)[:8]),
+			`<D:current-user-privilege-set>` +
+				`<D:privilege><D:read/></D:privilege>` +
+				`<D:privilege><D:write/></D:privilege>` +
+				`<D:privilege><D:write-content/></D:privilege>` +
+				`<D:privilege><D:bind/></D:privilege>` +
+				`<D:privilege><D:unbind/></D:privilege>` +
+				`</D:current-user-privilege-set>`,
+			`<D:supported-report-set>` +
+				`<D:supported-report><D:report><C:calendar-query/></D:report></D:supported-report>` +
+				`<D:supported-report><D:report><C:calendar-multiget/></D:report></D:supported-report>` +
+				`</D:supported-report-set>`,
+		},
+	}, nil
+}
+
+// caldavReport answers calendar-multiget for the listed tasks and calendar-query for all of
+// them. There are only tasks here, so queries for events get nothing.
+func (s *Server) caldavReport(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxCalDAVBody))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	var report struct {
+		XMLName xml.Name
+		Hrefs   []string `xml:"DAV: href"`
+	}
+	if err := xml.Unmarshal(body, &report); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid REPORT body")
+		return
+	}
+	withData := strings.Contains(string(body), "calendar-data")
+
+	var responses []davResponse
+	switch report.XMLName.Local {
+	case "calendar-multiget":
+		for _, href := range report.Hrefs {
+			name := href
+			if unescaped, err := url.PathUnescape(href); err == nil {
+				name = unescaped
+			}
+			task := s.caldavTask(strings.TrimSuffix(path.Base(name), ".ics"))
+			if task == nil {
+				responses = append(responses, davResponse{href: href})
+				continue
+			}
+			responses = append(responses, taskResponse(task, true))
+		}
+
+	case "calendar-query":
+		if strings.Contains(string(body), `"VEVENT"`) {
+			break
+		}
+		tasks, err := s.caldavTasks()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		for _, task := range tasks {
+			responses = append(responses, taskResponse(task, withData))
+		}
+
+	default:
+		http.Error(w, "Unsupported report", http.StatusForbidden)
+		return
+	}
+	writeMultistatus(w, responses)
+}
+
+// caldavGet returns a task as iCalendar
+func (s *Server) caldavGet(w http.ResponseWriter, r *http.Request, name string) {
+	task := s.caldavTask(name)
+	if task == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("ETag", taskETag(task))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		io.WriteString(w, renderVTODO(task))
+	}
+}
+
+// caldavPut saves a task edited or added in a task app. Completing or reopening it there
+// completes or reopens it here, and a new task is scored like one added in the TUI. No ETag
+// is returned, since the stored task gains a priority and categories the app didn't send.
+func (s *Server) caldavPut(w http.ResponseWriter, r *http.Request, name string) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxCalDAVBody))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	todo, err := parseVTODO(string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	ctx := r.Context()
+	task := s.caldavTask(name)
+	if task == nil {
+		if match := r.Header.Get("If-Match"); match != "" {
+			http.Error(w, "Task not found", http.StatusPreconditionFailed)
+			return
+		}
+		in := planner.TaskInput{
+			Title:       todo.Summary,
+			Description: todo.Description,
+			DueTS:       todo.Due,
+			Impact:      priorityToImpact(todo.Priority),
+		}
+		task, err = s.planner.CreateTaskFrom(ctx, caldavSource, name, in)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := s.applyTodoStatus(r, task, todo.Status); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	if r.Header.Get("If-None-Match") == "*" {
+		http.Error(w, "Task already exists", http.StatusPreconditionFailed)
+		return
+	}
+	if match := r.Header.Get("If-Match"); match != "" && match != "*" && match != taskETag(task) {
+		http.Error(w, "Task changed since it was fetched", http.StatusPreconditionFailed)
+		return
+	}
+
+	in := planner.TaskInputFrom(task)
+	changed := false
+	if todo.Summary != "" && todo.Summary != task.Title {
+		in.Title = todo.Summary
+		changed = true
+	}
+	if todo.Description != task.Description {
+		in.Description = todo.Description
+		changed = true
+	}
+	if !sameTime(todo.Due, task.DueTS) {
+		in.DueTS = todo.Due
+		changed = true
+	}
+	if impact := priorityToImpact(todo.Priority); impact != 0 && impact != task.Impact {
+		in.Impact = impact
+		changed = true
+	}
+	if changed {
+		if _, err := s.planner.UpdateTask(ctx, task.ID, in); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := s.applyTodoStatus(r, task, todo.Status); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// applyTodoStatus completes, reopens or deletes a task to match the status set in a task app
+func (s *Server) applyTodoStatus(r *http.Request, task *db.Task, status string) error {
+	ctx := r.Context()
+	switch {
+	case status == "COMPLETED" && task.Status != "completed":
+		return s.planner.CompleteTask(ctx, task.ID)
+	case status == "CANCELLED" && task.Status != "cancelled":
+		_, err := s.planner.DeleteTask(ctx, task.ID)
+		return err
+	case (status == "NEEDS-ACTION" || status == "IN-PROCESS") && task.Status == "completed":
+		return s.planner.UncompleteTask(ctx, task.ID)
+	}
+	return nil
+}
+
+// caldavDelete deletes a task removed in a task app, as x does in the TUI
+func (s *Server) caldavDelete(w http.ResponseWriter, r *http.Request, name string) {
+	task := s.caldavTask(name)
+	if task == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if match := r.Header.Get("If-Match"); match != "" && match != "*" && match != taskETag(task) {
+		http.Error(w, "Task changed since it was fetched", http.StatusPreconditionFailed)
+		return
+	}
+	if _, err := s.planner.DeleteTask(r.Context(), task.ID); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("CalDAV: deleted task %s", task.Title)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// caldavTasks returns the tasks in the list: open ones and those completed in the last
+// caldav.completed_days
+func (s *Server) caldavTasks() ([]*db.Task, error) {
+	return s.database.GetCalDAVTasks(time.Now().AddDate(0, 0, -s.config.CalDAV.CompletedDays))
+}
+
+// caldavTask finds the task behind a resource name: a task app's own name for tasks it
+// created, otherwise the task ID. Deleted tasks are gone.
+func (s *Server) caldavTask(name string) *db.Task {
+	id, err := s.database.GetTaskIDBySource(caldavSource, name)
+	if err != nil {
+		log.Printf("CalDAV: %v", err)
+		return nil
+	}
+	if id == "" {
+		id = name
+	}
+	task, err := s.database.GetTaskByID(id)
+	if err != nil || task.Status == "cancelled" {
+		return nil
+	}
+	return task
+}
+
+// taskHref is a task's resource path
+func taskHref(task *db.Task) string {
+	return caldavTasks + url.PathEscape(taskUID(task)) + ".ics"
+}
+
+// taskUID names a task in task apps. Tasks created in one keep the app's name for them, which
+// apps take from the task's UID.
+func taskUID(task *db.Task) string {
+	if task.Source == caldavSource && task.SourceID != "" {
+		return task.SourceID
+	}
+	return task.ID
+}
+
+// taskResponse describes a task, with its iCalendar data if asked for
+func taskResponse(task *db.Task, withData bool) davResponse {
+	props := []string{
+		`<D:resourcetype/>`,
+		`<D:getcontenttype>text/calendar; charset=utf-8; component=vtodo</D:getcontenttype>`,
+		`<D:getetag>` + xmlEscape(taskETag(task)) + `</D:getetag>`,
+	}
+	if withData {
+		props = append(props, `<C:calendar-data>`+xmlEscape(renderVTODO(task))+`</C:calendar-data>`)
+	}
+	return davResponse{href: taskHref(task), props: props}
+}
+
+// writeMultistatus writes a 207 reply. Responses without properties are reported missing.
+func writeMultistatus(w http.ResponseWriter, responses []davResponse) {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav" xmlns:CS="http://calendarserver.org/ns/">`)
+	for _, response := range responses {
+		b.WriteString(`<D:response><D:href>` + xmlEscape(response.href) + `</D:href>`)
+		if len(response.props) == 0 {
+			b.WriteString(`<D:status>HTTP/1.1 404 Not Found</D:status>`)
+		} else {
+			b.WriteString(`<D:propstat><D:prop>` + strings.Join(response.props, "") + `</D:prop>`)
+			b.WriteString(`<D:status>HTTP/1.1 200 OK</D:status></D:propstat>`)
+		}
+		b.WriteString(`</D:response>`)
+	}
+	b.WriteString(`</D:multistatus>`)
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	io.WriteString(w, b.String())
+}
+
+func xmlEscape(text string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+// sameTime reports whether two optional times are the same to the second
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Unix() == b.Unix()
+}
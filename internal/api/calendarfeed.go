@@ -0,0 +1,161 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// calendarFeedAuthMiddleware accepts the feed token as well as the API key. Calendar apps
+// subscribe by URL and can't send headers, so the feed token is also accepted as ?token=; the
+// API key isn't, so it never ends up in a subscription URL.
+func (s *Server) calendarFeedAuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token := r.URL.Query().Get("token"); token != "" {
+			if !tokenMatches(token, s.config.CalendarFeed.Token) {
+				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				return
+			}
+			next(w, r)
+			return
+		}
+
+		// Some apps do support Basic auth, as with CalDAV
+		token := caldavToken(r)
+		if token == "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="focus-agent"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !s.isOwnerToken(token) && !tokenMatches(token, s.config.CalendarFeed.Token) {
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// GET /api/calendar.ics - Task deadlines and focus blocks as an iCalendar feed
+func (s *Server) handleCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	cfg := s.config.CalendarFeed
+	now := time.Now()
+	start := now.AddDate(0, 0, -cfg.PastDays)
+
+	tasks, err := s.database.GetOpenTasksDueBetween(start, now.AddDate(0, 0, cfg.AheadDays))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get tasks: %v", err))
+		return
+	}
+	// Blocks are only planned for the day they're in, so none lie further ahead than tomorrow
+	blocks, err := s.database.GetTimeBlocksBetween(start, now.AddDate(0, 0, 2))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get time blocks: %v", err))
+		return
+	}
+	for _, block := range blocks {
+		for _, id := range block.TaskIDs {
+			if task, err := s.database.GetTaskByID(id); err == nil {
+				block.TaskTitles = append(block.TaskTitles, task.Title)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="focus-agent.ics"`)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		refresh := time.Duration(cfg.RefreshMinutes) * time.Minute
+		w.Write([]byte(renderCalendarFeed(cfg.Name, s.config.Schedule.ActiveTimezone(), refresh, tasks, blocks, now)))
+	}
+}
+
+// renderCalendarFeed writes deadlines and focus blocks as an iCalendar object. Deadlines are
+// instants that don't show as busy; blocks that couldn't fit around meetings are left out.
+func renderCalendarFeed(name, timezone string, refresh time.Duration, tasks []*db.Task, blocks []*db.TimeBlock, now time.Time) string {
+	var b strings.Builder
+	line := func(name, value string) {
+		b.WriteString(foldICalLine(name + ":" + value))
+	}
+	stamp := now.UTC().Format(icalUTC)
+	ttl := fmt.Sprintf("PT%dM", int(refresh.Minutes()))
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//focus-agent//Calendar Feed//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeICalText(name))
+	line("X-WR-TIMEZONE", timezone)
+	line("REFRESH-INTERVAL;VALUE=DURATION", ttl)
+	line("X-PUBLISHED-TTL", ttl)
+
+	for _, task := range tasks {
+		if task.DueTS == nil {
+			continue
+		}
+		line("BEGIN", "VEVENT")
+		line("UID", "due-"+task.ID+"@focus-agent")
+		line("DTSTAMP", stamp)
+		line("DTSTART", task.DueTS.UTC().Format(icalUTC))
+		line("SUMMARY", escapeICalText("Due: "+task.Title))
+		line("DESCRIPTION", escapeICalText(deadlineDescription(task)))
+		if task.Project != "" {
+			line("CATEGORIES", escapeICalText(task.Project))
+		}
+		line("PRIORITY", fmt.Sprint(impactToPriority(task.Impact)))
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+
+	for _, block := range blocks {
+		if block.Status == db.TimeBlockConflict {
+			continue
+		}
+		line("BEGIN", "VEVENT")
+		line("UID", "block-"+block.ID+"@focus-agent")
+		line("DTSTAMP", stamp)
+		line("DTSTART", block.StartTS.UTC().Format(icalUTC))
+		line("DTEND", block.EndTS.UTC().Format(icalUTC))
+		line("SUMMARY", escapeICalText(block.Name))
+		if len(block.TaskTitles) > 0 {
+			line("DESCRIPTION", escapeICalText("- "+strings.Join(block.TaskTitles, "\n- ")))
+		}
+		// Accepted blocks are part of the plan; the rest are still only proposed
+		if block.Status == db.TimeBlockAccepted {
+			line("STATUS", "CONFIRMED")
+		} else {
+			line("STATUS", "TENTATIVE")
+		}
+		line("TRANSP", "OPAQUE")
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return b.String()
+}
+
+// deadlineDescription summarises a task for its deadline event
+func deadlineDescription(task *db.Task) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Impact %d, urgency %d, effort %s", task.Impact, task.Urgency, task.Effort)
+	if task.Stakeholder != "" {
+		fmt.Fprintf(&b, "\nFor %s", task.Stakeholder)
+	}
+	if task.Project != "" {
+		fmt.Fprintf(&b, "\nProject: %s", task.Project)
+	}
+	if task.Description != "" {
+		b.WriteString("\n\n" + task.Description)
+	}
+	return b.String()
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/events"
+	"github.com/alexrabarts/focus-agent/internal/google"
+)
+
+// POST /api/chat/events - Interaction events from the Chat app (chat.app), such as clicks on the
+// daily brief's task buttons. Requests carry Google Chat's signed token instead of the API key.
+func (s *Server) handleChatEvent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == r.Header.Get("Authorization") {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if err := google.VerifyChatEventToken(r.Context(), token, s.config.Chat.App.Audience); err != nil {
+		log.Printf("Rejected Chat app event: %v", err)
+		http.Error(w, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	var event google.ChatEvent
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Anyone can message the app, but only the agent's own user can change tasks or search
+	if !isChatOwner(event.User.Email, s.config.Google.UserEmail) {
+		text := "Sorry, this Focus Agent belongs to someone else."
+		if strings.TrimSpace(s.config.Google.UserEmail) == "" {
+			log.Printf("Rejected Chat app event from %s: google.user_email is not known yet", event.User.Email)
+			text = "Sorry, this Focus Agent doesn't know who it belongs to yet. Try again once it has synced with Gmail."
+		}
+		writeJSON(w, http.StatusOK, google.ChatEventResponse{Text: text})
+		return
+	}
+
+	switch event.Type {
+	case "CARD_CLICKED":
+		text := s.runChatAction(r.Context(), event.Common.InvokedFunction, event.Common.Parameters["task_id"])
+		writeJSON(w, http.StatusOK, google.ChatEventResponse{
+			Text:           text,
+			ActionResponse: &google.ChatActionResponse{Type: "NEW_MESSAGE"},
+		})
+	case "MESSAGE":
+		query := strings.TrimSpace(event.Message.ArgumentText)
+		if query == "" {
+			query = strings.TrimSpace(event.Message.Text)
+		}
+		writeJSON(w, http.StatusOK, google.ChatEventResponse{Text: s.chatSearch(r.Context(), query)})
+	case "ADDED_TO_SPACE":
+		writeJSON(w, http.StatusOK, google.ChatEventResponse{Text: "Your daily brief will be posted here, with buttons to act on each task. Message me to search your tasks, mail, events and documents."})
+	default:
+		writeJSON(w, http.StatusOK, google.ChatEventResponse{})
+	}
+}
+
+// isChatOwner reports whether a Chat event came from the agent's own user. Until the user's
+// email is known, e.g. while the Gmail profile lookup is deferred or failing, nobody is.
+func isChatOwner(eventEmail, userEmail string) bool {
+	userEmail = strings.TrimSpace(userEmail)
+	return userEmail != "" && strings.EqualFold(strings.TrimSpace(eventEmail), userEmail)
+}
+
+// chatSearchResults is how many search results a Chat reply lists
+const chatSearchResults = 5
+
+// chatSearch answers a message to the Chat app by searching for it
+func (s *Server) chatSearch(ctx context.Context, query string) string {
+	if query == "" {
+		return "Send me something to search for, e.g. \"pricing proposal\"."
+	}
+
+	results, err := s.planner.Search(ctx, query)
+	if err != nil {
+		log.Printf("Chat search for %q failed: %v", query, err)
+		return fmt.Sprintf("Search failed: %v", err)
+	}
+	if len(results) == 0 {
+		return fmt.Sprintf("Nothing found for \"%s\".", query)
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("🔍 *%s*\n", query))
+	for i, result := range results {
+		if i == chatSearchResults {
+			break
+		}
+		title := result.Title
+		if title == "" {
+			title = "(no subject)"
+		}
+		if result.Link != "" {
+			title = fmt.Sprintf("<%s|%s>", result.Link, title)
+		}
+		b.WriteString(fmt.Sprintf("• %s: %s", result.Kind, title))
+		if result.Timestamp.Unix() > 0 {
+			b.WriteString(" (" + result.Timestamp.Format("Jan 2") + ")")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// runChatAction completes, snoozes or opens a task from a card button and returns the reply
+func (s *Server) runChatAction(ctx context.Context, function, taskID string) string {
+	task, err := s.database.GetTaskByID(taskID)
+	if err != nil {
+		return "That task no longer exists."
+	}
+
+	switch function {
+	case google.ChatActionComplete:
+		if task.Status == "completed" {
+			return fmt.Sprintf("✅ Already done: %s", task.Title)
+		}
+		if err := s.planner.CompleteTask(ctx, taskID); err != nil {
+			log.Printf("Chat action failed to complete task %s: %v", taskID, err)
+			return fmt.Sprintf("Couldn't complete %s: %v", task.Title, err)
+		}
+		return fmt.Sprintf("✅ Completed: %s", task.Title)
+
+	case google.ChatActionSnooze:
+		until := time.Now().AddDate(0, 0, 1)
+		if err := s.planner.SnoozeTask(ctx, taskID, until); err != nil {
+			log.Printf("Chat action failed to snooze task %s: %v", taskID, err)
+			return fmt.Sprintf("Couldn't snooze %s: %v", task.Title, err)
+		}
+		return fmt.Sprintf("💤 Snoozed until %s: %s", until.Format("Mon 3:04 PM"), task.Title)
+
+	case google.ChatActionOpen:
+		if s.events == nil {
+			return "The live event stream isn't running, so the TUI can't be reached."
+		}
+		s.events.Publish(events.TaskOpen, map[string]interface{}{"task_id": taskID})
+		return fmt.Sprintf("🖥️ Sent to the TUI: %s", task.Title)
+	}
+
+	return fmt.Sprintf("Unknown action %q", function)
+}
@@ -0,0 +1,29 @@
+package api
+
+import "testing"
+
+// TestIsChatOwner verifies that Chat app events are only accepted from the agent's own user,
+// and from nobody while the user's email is unknown
+func TestIsChatOwner(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventEmail string
+		userEmail  string
+		want       bool
+	}{
+		{"matching", "me@example.com", "me@example.com", true},
+		{"matching case-insensitively", "Me@Example.com", "me@example.com", true},
+		{"someone else", "other@example.com", "me@example.com", false},
+		{"event without email", "", "me@example.com", false},
+		{"owner unknown", "other@example.com", "", false},
+		{"owner unknown, event without email", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isChatOwner(tt.eventEmail, tt.userEmail); got != tt.want {
+				t.Errorf("isChatOwner(%q, %q) = %v, want %v", tt.eventEmail, tt.userEmail, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// chatConversationLimit is how many Chat messages are returned for a thread by default
+const chatConversationLimit = 20
+
+// GET /api/chat/messages?thread=spaces/X/threads/Y - Messages of a synced Chat thread, with the
+// messages before it for single-message threads (DMs and unthreaded spaces), oldest first
+func (s *Server) handleChatMessages(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	thread := r.URL.Query().Get("thread")
+	if thread == "" {
+		writeError(w, http.StatusBadRequest, "thread is required")
+		return
+	}
+	limit := chatConversationLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+		limit = n
+	}
+
+	messages, err := s.database.GetChatConversation(thread, limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to get Chat messages: %v", err))
+		return
+	}
+	if messages == nil {
+		messages = []*db.ChatMessage{}
+	}
+	writeJSON(w, http.StatusOK, messages)
+}
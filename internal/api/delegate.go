@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/events"
+)
+
+// maxDelegatedTasks caps the delegated task list
+const maxDelegatedTasks = 200
+
+// delegateAuthMiddleware accepts the delegate's token as well as the API key. Like the
+// analytics token, the delegate token is only ever checked here.
+func (s *Server) delegateAuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || token == r.Header.Get("Authorization") {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if !s.isOwnerToken(token) && !tokenMatches(token, s.config.Delegate.Token) {
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// delegateAuthor names who is calling a delegate endpoint: the delegate, or the owner using the
+// API key
+func (s *Server) delegateAuthor(r *http.Request) string {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if tokenMatches(token, s.config.Delegate.Token) {
+		return s.config.Delegate.Name
+	}
+	return db.OwnerAuthor
+}
+
+// delegatedTask returns an open task carrying the delegation tag, or nil if the task doesn't
+// exist, is closed or isn't delegated
+func (s *Server) delegatedTask(taskID string) *db.Task {
+	tag, err := db.NormalizeTag(s.config.Delegate.Tag)
+	if err != nil {
+		return nil
+	}
+	task, err := s.database.GetTaskByID(taskID)
+	if err != nil || !slices.Contains(task.Tags, tag) {
+		return nil
+	}
+	if task.Status != "pending" && task.Status != "in_progress" {
+		return nil
+	}
+	return task
+}
+
+// GET /api/delegate/tasks - List open tasks carrying the delegation tag, with claims and comments
+func (s *Server) handleDelegateTasks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	tasks, err := s.database.GetTasksByTag(s.config.Delegate.Tag, maxDelegatedTasks)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := make([]TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Status == "pending" || task.Status == "in_progress" {
+			response = append(response, toTaskResponse(task))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// POST /api/delegate/tasks/:id/claim - Claim a delegated task
+// POST /api/delegate/tasks/:id/unclaim - Hand a claimed task back
+// POST /api/delegate/tasks/:id/comment - Leave a comment ({"body": "..."})
+// POST /api/delegate/tasks/:id/complete - Complete a delegated task
+func (s *Server) handleDelegateTaskAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/delegate/tasks/"), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		writeError(w, http.StatusBadRequest, "Invalid path")
+		return
+	}
+	taskID, action := parts[0], parts[1]
+
+	// The delegate only ever sees tasks carrying the tag
+	task := s.delegatedTask(taskID)
+	if task == nil {
+		writeError(w, http.StatusNotFound, "Task not found")
+		return
+	}
+	author := s.delegateAuthor(r)
+
+	switch action {
+	case "claim":
+		if err := s.database.ClaimTask(taskID, author); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+	case "unclaim":
+		if err := s.database.UnclaimTask(taskID); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+	case "comment":
+		var req struct {
+			Body string `json:"body"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if _, err := s.database.AddTaskComment(taskID, author, req.Body); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+	case "complete":
+		// Completes the task in the owner's list too, spawning the next occurrence if it recurs
+		if err := s.planner.CompleteTask(context.Background(), taskID); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+	default:
+		writeError(w, http.StatusBadRequest, "Invalid action")
+		return
+	}
+
+	s.events.Publish(events.TaskDelegated, map[string]interface{}{
+		"task_id": taskID,
+		"action":  action,
+		"by":      author,
+	})
+
+	task, err := s.database.GetTaskByID(taskID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, toTaskResponse(task))
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+// DraftRequest is the body for drafting a reply to a thread
+type DraftRequest struct {
+	Goal    string `json:"goal"`    // What the reply should achieve; defaults to answering the latest message
+	Persona string `json:"persona"` // Drafting persona; defaults to the recipient's, then the configured default
+	Review  bool   `json:"review"`  // Return the draft unsaved, to be edited and saved with /reply
+}
+
+// ReplyRequest is the body for saving or sending a reviewed reply to a thread
+type ReplyRequest struct {
+	To   string `json:"to"` // Defaults to whoever last wrote in the thread
+	Body string `json:"body"`
+	Send bool   `json:"send"` // Send it rather than save it to Gmail Drafts
+}
+
+// PersonaResponse is a drafting persona the user can pick
+type PersonaResponse struct {
+	Name         string `json:"name"`
+	Instructions string `json:"instructions"`
+	MaxWords     int    `json:"max_words"`
+	Default      bool   `json:"default"`
+}
+
+// POST /api/threads/:id/draft - Draft a reply, saving it to Gmail when possible unless it's for
+// review. A persona picked here becomes the default for the recipient.
+func (s *Server) handleDraftReply(w http.ResponseWriter, r *http.Request, threadID string) {
+	var req DraftRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	if req.Persona != "" {
+		if _, ok := s.config.Drafting.Persona(req.Persona); !ok {
+			writeError(w, http.StatusBadRequest, "Unknown persona: "+req.Persona)
+			return
+		}
+	}
+
+	draft, err := s.planner.DraftThreadReply(r.Context(), threadID, req.Goal, req.Persona, !req.Review)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, draft)
+}
+
+// POST /api/threads/:id/reply - Save a reviewed reply to Gmail Drafts, or send it
+func (s *Server) handleSaveReply(w http.ResponseWriter, r *http.Request, threadID string) {
+	var req ReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := s.planner.SaveThreadReply(r.Context(), threadID, req.To, req.Body, req.Send); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	status := "saved"
+	if req.Send {
+		status = "sent"
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": status})
+}
+
+// GET /api/personas - List drafting personas
+func (s *Server) handlePersonas(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	response := make([]PersonaResponse, 0, len(s.config.Drafting.Personas))
+	for name, persona := range s.config.Drafting.Personas {
+		response = append(response, PersonaResponse{
+			Name:         name,
+			Instructions: persona.Instructions,
+			MaxWords:     persona.MaxWords,
+			Default:      name == s.config.Drafting.DefaultPersona,
+		})
+	}
+	sort.Slice(response, func(i, j int) bool { return response[i].Name < response[j].Name })
+
+	writeJSON(w, http.StatusOK, response)
+}
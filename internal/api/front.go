@@ -0,0 +1,132 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/front"
+)
+
+// FrontThreadResponse is the Front conversation linked to a thread and its internal comments
+type FrontThreadResponse struct {
+	Metadata *db.FrontMetadata  `json:"metadata"`
+	Comments []*db.FrontComment `json:"comments"`
+}
+
+// GET /api/front/teammates - Front teammates conversations can be assigned to
+func (s *Server) handleFrontTeammates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	teammates, err := s.planner.FrontTeammates(r.Context())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if teammates == nil {
+		teammates = []front.Teammate{}
+	}
+	writeJSON(w, http.StatusOK, teammates)
+}
+
+// GET /api/front/:thread_id - The Front conversation linked to a thread and its comments
+// POST /api/front/:thread_id/archive - Archive the conversation
+// POST /api/front/:thread_id/snooze - Snooze it for {"hours"} (default front.default_snooze_hours)
+// POST /api/front/:thread_id/assign - Assign it to {"teammate"} by email, username or name, or
+// unassign it when empty
+// POST /api/front/:thread_id/draft-comment - Draft an internal comment toward an optional {"goal"}
+// POST /api/front/:thread_id/comment - Post {"body"} as an internal comment
+func (s *Server) handleFrontAction(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/front/"), "/")
+	if parts[0] == "" || len(parts) > 2 {
+		writeError(w, http.StatusBadRequest, "Invalid path")
+		return
+	}
+	threadID := parts[0]
+
+	if len(parts) == 1 {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		metadata, err := s.database.GetFrontMetadata(threadID)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		comments, err := s.database.GetFrontComments(threadID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if comments == nil {
+			comments = []*db.FrontComment{}
+		}
+		writeJSON(w, http.StatusOK, &FrontThreadResponse{Metadata: metadata, Comments: comments})
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req struct {
+		Hours    int    `json:"hours"`
+		Teammate string `json:"teammate"`
+		Goal     string `json:"goal"`
+		Body     string `json:"body"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	ctx := r.Context()
+	switch parts[1] {
+	case "archive":
+		metadata, err := s.planner.ArchiveFrontConversation(ctx, threadID)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, metadata)
+	case "snooze":
+		until, err := s.planner.SnoozeFrontConversation(ctx, threadID, req.Hours)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]time.Time{"until": until})
+	case "assign":
+		metadata, err := s.planner.AssignFrontConversation(ctx, threadID, req.Teammate)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, metadata)
+	case "draft-comment":
+		comment, err := s.planner.DraftFrontComment(ctx, threadID, req.Goal)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"comment": comment})
+	case "comment":
+		comment, err := s.planner.PostFrontComment(ctx, threadID, req.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, comment)
+	default:
+		writeError(w, http.StatusNotFound, "Unknown action")
+	}
+}
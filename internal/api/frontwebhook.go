@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/front"
+)
+
+// maxFrontWebhookBytes caps the body read from a Front webhook
+const maxFrontWebhookBytes = 1 << 20
+
+// POST /api/front/webhook - Conversation events from the Front app (front.webhook_secret), so
+// assignments, comments and status changes reach linked threads as they happen. Requests are
+// signed by Front instead of carrying the API key.
+func (s *Server) handleFrontWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxFrontWebhookBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Failed to read body")
+		return
+	}
+	err = front.VerifyWebhook(s.config.Front.WebhookSecret, r.Header.Get("X-Front-Request-Timestamp"),
+		r.Header.Get("X-Front-Signature"), body, time.Now())
+	if err != nil {
+		log.Printf("Rejected Front webhook: %v", err)
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	// Front checks a new webhook URL by asking for its challenge back
+	if challenge := r.Header.Get("X-Front-Challenge"); challenge != "" {
+		writeJSON(w, http.StatusOK, map[string]string{"challenge": challenge})
+		return
+	}
+
+	var delivery front.WebhookEvent
+	if err := json.Unmarshal(body, &delivery); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	event := &delivery.Payload
+	if event.ID == "" {
+		writeJSON(w, http.StatusOK, map[string]int{"updated": 0})
+		return
+	}
+
+	// A delivery seen before is a retry or a replay; either way it's been applied
+	fresh, err := s.database.RecordFrontEvent(event.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !fresh {
+		writeJSON(w, http.StatusOK, map[string]int{"updated": 0})
+		return
+	}
+
+	updated, err := s.planner.ApplyFrontEvent(event)
+	if err != nil {
+		log.Printf("Failed to apply Front %s event %s: %v", event.Type, event.ID, err)
+		if err := s.database.ForgetFrontEvent(event.ID); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"updated": updated})
+}
@@ -1,34 +1,93 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+	"github.com/alexrabarts/focus-agent/internal/stt"
 )
 
 // Task response structure
 type TaskResponse struct {
-	ID          string  `json:"id"`
-	Source      string  `json:"source"`
-	SourceID    string  `json:"source_id"`
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	DueTS       *string `json:"due_ts,omitempty"`
-	Project     string  `json:"project"`
-	Impact      int     `json:"impact"`
-	Urgency     int     `json:"urgency"`
-	Effort      string  `json:"effort"`
-	Stakeholder string  `json:"stakeholder"`
-	Score       float64 `json:"score"`
-	Status      string  `json:"status"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	ID          string            `json:"id"`
+	Source      string            `json:"source"`
+	SourceID    string            `json:"source_id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	DueTS       *string           `json:"due_ts,omitempty"`
+	Project     string            `json:"project"`
+	Impact      int               `json:"impact"`
+	Urgency     int               `json:"urgency"`
+	Effort      string            `json:"effort"`
+	Stakeholder string            `json:"stakeholder"`
+	Score       float64           `json:"score"`
+	Status      string            `json:"status"`
+	Recurrence  string            `json:"recurrence,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	ClaimedBy   string            `json:"claimed_by,omitempty"`
+	Comments    []*db.TaskComment `json:"comments,omitempty"`
+	CreatedAt   string            `json:"created_at"`
+	UpdatedAt   string            `json:"updated_at"`
+}
+
+// TaskRequest is the body for creating or editing a task. Fields left out keep their current
+// value when editing.
+type TaskRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	DueTS       *string `json:"due_ts"` // RFC3339; empty clears the due date
+	Impact      *int    `json:"impact"`
+	Urgency     *int    `json:"urgency"`
+	Effort      *string `json:"effort"`
+	Project     *string `json:"project"`
+	Stakeholder *string `json:"stakeholder"`
+}
+
+// apply overlays the request's fields on a task's current fields
+func (req TaskRequest) apply(in *planner.TaskInput) error {
+	if req.Title != nil {
+		in.Title = *req.Title
+	}
+	if req.Description != nil {
+		in.Description = *req.Description
+	}
+	if req.DueTS != nil {
+		in.DueTS = nil
+		if *req.DueTS != "" {
+			due, err := time.Parse(time.RFC3339, *req.DueTS)
+			if err != nil {
+				return fmt.Errorf("due_ts must be an RFC3339 time")
+			}
+			in.DueTS = &due
+		}
+	}
+	if req.Impact != nil {
+		in.Impact = *req.Impact
+	}
+	if req.Urgency != nil {
+		in.Urgency = *req.Urgency
+	}
+	if req.Effort != nil {
+		in.Effort = *req.Effort
+	}
+	if req.Project != nil {
+		in.Project = *req.Project
+	}
+	if req.Stakeholder != nil {
+		in.Stakeholder = *req.Stakeholder
+	}
+	return nil
 }
 
 // Priorities response structure
@@ -55,6 +114,19 @@ type StatsResponse struct {
 	LastDriveSync     *string `json:"last_drive_sync,omitempty"`
 	LastCalendarSync  *string `json:"last_calendar_sync,omitempty"`
 	LastTasksSync     *string `json:"last_tasks_sync,omitempty"`
+
+	Setup        *db.SetupProgress     `json:"setup,omitempty"`
+	QueryTimings []QueryTimingResponse `json:"query_timings,omitempty"`
+}
+
+// Query timing response structure (embedding and full-text queries since startup)
+type QueryTimingResponse struct {
+	Kind   string  `json:"kind"`
+	Count  int64   `json:"count"`
+	Errors int64   `json:"errors"`
+	Slow   int64   `json:"slow"`
+	AvgMs  float64 `json:"avg_ms"`
+	MaxMs  float64 `json:"max_ms"`
 }
 
 // Thread response structure
@@ -64,10 +136,99 @@ type ThreadResponse struct {
 	Summary        string  `json:"summary"`
 	SummaryHash    *string `json:"summary_hash,omitempty"`
 	TaskCount      int     `json:"task_count"`
+	PriorityScore  float64 `json:"priority_score"`
 	NextFollowupTS *string `json:"next_followup_ts,omitempty"`
 	LastSynced     string  `json:"last_synced"`
 }
 
+// Weekly digest response structure
+type DigestResponse struct {
+	Tasks    []TaskResponse   `json:"tasks"`
+	Threads  []ThreadResponse `json:"threads"`
+	TagStats []*db.TagStats   `json:"tag_stats"`
+	Since    string           `json:"since"`
+}
+
+// Voice capture response structure
+type CaptureResponse struct {
+	Transcript string         `json:"transcript"`
+	Tasks      []TaskResponse `json:"tasks"`
+}
+
+// Ask response structure: the answer and the messages and tasks it was based on
+type AskResponse struct {
+	Question string            `json:"question"`
+	Answer   string            `json:"answer"`
+	Messages []MessageResponse `json:"messages"`
+	Tasks    []TaskResponse    `json:"tasks"`
+}
+
+// Processing rule response structure
+type RuleResponse struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Pattern   string `json:"pattern"`
+	Tag       string `json:"tag,omitempty"` // Set for tag rules
+	Note      string `json:"note,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// Provider budget response structure (remaining values are omitted when there is no cap)
+type BudgetResponse struct {
+	Provider        string   `json:"provider"`
+	MaxTokens       int      `json:"max_tokens"`
+	MaxCost         float64  `json:"max_cost"`
+	UsedTokens      int      `json:"used_tokens"`
+	UsedCost        float64  `json:"used_cost"`
+	RemainingTokens *int     `json:"remaining_tokens,omitempty"`
+	RemainingCost   *float64 `json:"remaining_cost,omitempty"`
+	Exhausted       bool     `json:"exhausted"`
+	ResetsAt        string   `json:"resets_at"`
+}
+
+// Time block response structure
+type TimeBlockResponse struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Start      string   `json:"start"`
+	End        string   `json:"end"`
+	Status     string   `json:"status"`
+	Conflict   string   `json:"conflict,omitempty"`
+	TaskIDs    []string `json:"task_ids"`
+	TaskTitles []string `json:"task_titles"`
+}
+
+// Day plan commit response structure (Error lists blocks that couldn't be scheduled)
+type DayPlanResponse struct {
+	Blocks []TimeBlockResponse `json:"blocks"`
+	Error  string              `json:"error,omitempty"`
+}
+
+// Focus mode response structure
+type FocusResponse struct {
+	Active           bool    `json:"active"`
+	StartedAt        *string `json:"started_at,omitempty"`
+	EndsAt           *string `json:"ends_at,omitempty"`
+	RemainingSeconds int     `json:"remaining_seconds"`
+}
+
+// Focus summary response structure: what arrived during a focus session
+type FocusSummaryResponse struct {
+	StartedAt     string                `json:"started_at"`
+	EndedAt       string                `json:"ended_at"`
+	Threads       []FocusThreadResponse `json:"threads"`
+	Tasks         []TaskResponse        `json:"tasks"`
+	Notifications map[string]int        `json:"notifications"`
+}
+
+// Focus thread response structure
+type FocusThreadResponse struct {
+	ThreadID string `json:"thread_id"`
+	Subject  string `json:"subject"`
+	From     string `json:"from"`
+	Messages int    `json:"messages"`
+}
+
 // Message response structure
 type MessageResponse struct {
 	ID          string   `json:"id"`
@@ -91,13 +252,29 @@ type QueueItemResponse struct {
 }
 
 // GET /api/tasks - List all tasks (including completed)
+// GET /api/tasks?tag=finance - List tasks with a tag
+// POST /api/tasks - Create a task by hand
 func (s *Server) handleTasks(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		s.handleCreateTask(w, r)
+		return
+	}
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	tasks, err := s.database.GetAllTasks(100)
+	var tasks []*db.Task
+	var err error
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		if _, err := db.NormalizeTag(tag); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		tasks, err = s.database.GetTasksByTag(tag, 100)
+	} else {
+		tasks, err = s.database.GetAllTasks(100)
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -106,36 +283,45 @@ func (s *Server) handleTasks(w http.ResponseWriter, r *http.Request) {
 	// Convert to response format
 	response := make([]TaskResponse, 0, len(tasks))
 	for _, task := range tasks {
-		var dueTS *string
-		if task.DueTS != nil {
-			formatted := task.DueTS.Format(time.RFC3339)
-			dueTS = &formatted
-		}
-
-		response = append(response, TaskResponse{
-			ID:          task.ID,
-			Source:      task.Source,
-			SourceID:    task.SourceID,
-			Title:       task.Title,
-			Description: task.Description,
-			DueTS:       dueTS,
-			Project:     task.Project,
-			Impact:      task.Impact,
-			Urgency:     task.Urgency,
-			Effort:      task.Effort,
-			Stakeholder: task.Stakeholder,
-			Score:       task.Score,
-			Status:      task.Status,
-			CreatedAt:   task.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:   task.UpdatedAt.Format(time.RFC3339),
-		})
+		response = append(response, toTaskResponse(task))
 	}
 
 	writeJSON(w, http.StatusOK, response)
 }
 
+func (s *Server) handleCreateTask(w http.ResponseWriter, r *http.Request) {
+	var req TaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var in planner.TaskInput
+	if err := req.apply(&in); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, err := s.planner.CreateTask(r.Context(), in)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toTaskResponse(task))
+}
+
 // POST /api/tasks/:id/complete - Complete a task
 // POST /api/tasks/:id/uncomplete - Uncomplete a task (undo)
+// POST /api/tasks/:id/recurrence - Set or clear a task's recurrence rule
+// POST /api/tasks/:id/tags - Add and remove tags
+// POST /api/tasks/:id/snooze - Defer a task until a later time
+// POST /api/tasks/:id/due - Set or clear a task's due date (undo snooze)
+// POST /api/tasks/:id/edit - Edit a task's title, description, due date, scores or project
+// POST /api/tasks/:id/correct - Correct one extracted field (due, impact or stakeholder)
+// POST /api/tasks/:id/delete - Delete a task
+// POST /api/tasks/:id/restore - Restore a deleted task (undo delete)
+// POST /api/tasks/:id/handoff - Email a task to a colleague and track it as waiting on them
 func (s *Server) handleTaskAction(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
@@ -175,6 +361,210 @@ func (s *Server) handleTaskAction(w http.ResponseWriter, r *http.Request) {
 		}
 		writeJSON(w, http.StatusOK, map[string]string{"status": "pending"})
 
+	case "recurrence":
+		var req struct {
+			Rule string `json:"rule"` // daily, weekdays, weekly, monthly or an RRULE; empty clears it
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		rule, err := s.planner.SetTaskRecurrence(ctx, taskID, req.Rule)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"recurrence": rule})
+
+	case "snooze":
+		var req struct {
+			Until string `json:"until"` // RFC3339
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		until, err := time.Parse(time.RFC3339, req.Until)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "until must be an RFC3339 time")
+			return
+		}
+		if !until.After(time.Now()) {
+			writeError(w, http.StatusBadRequest, "until must be in the future")
+			return
+		}
+		if _, err := s.database.GetTaskByID(taskID); err != nil {
+			writeError(w, http.StatusNotFound, "Task not found")
+			return
+		}
+		if err := s.planner.SnoozeTask(ctx, taskID, until); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"due_ts": until.Format(time.RFC3339)})
+
+	case "due":
+		var req struct {
+			DueTS string `json:"due_ts"` // RFC3339; empty clears the due date
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		var due *time.Time
+		if req.DueTS != "" {
+			t, err := time.Parse(time.RFC3339, req.DueTS)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "due_ts must be an RFC3339 time")
+				return
+			}
+			due = &t
+		}
+		if _, err := s.database.GetTaskByID(taskID); err != nil {
+			writeError(w, http.StatusNotFound, "Task not found")
+			return
+		}
+		if err := s.planner.SetTaskDue(ctx, taskID, due); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"due_ts": req.DueTS})
+
+	case "edit":
+		var req TaskRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		task, err := s.database.GetTaskByID(taskID)
+		if err != nil {
+			writeError(w, http.StatusNotFound, "Task not found")
+			return
+		}
+		in := planner.TaskInputFrom(task)
+		if err := req.apply(&in); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		task, err = s.planner.UpdateTask(ctx, taskID, in)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, toTaskResponse(task))
+
+	case "correct":
+		var req struct {
+			Field string `json:"field"` // due, impact or stakeholder
+			Value string `json:"value"` // RFC3339 for due (empty clears it), 1-5 for impact
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if _, err := s.database.GetTaskByID(taskID); err != nil {
+			writeError(w, http.StatusNotFound, "Task not found")
+			return
+		}
+		task, err := s.planner.CorrectTaskField(ctx, taskID, req.Field, req.Value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, toTaskResponse(task))
+
+	case "delete":
+		if _, err := s.database.GetTaskByID(taskID); err != nil {
+			writeError(w, http.StatusNotFound, "Task not found")
+			return
+		}
+		previous, err := s.planner.DeleteTask(ctx, taskID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "cancelled", "previous_status": previous})
+
+	case "restore":
+		var req struct {
+			Status string `json:"status"` // Status before the delete (default pending)
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+		}
+		if _, err := s.database.GetTaskByID(taskID); err != nil {
+			writeError(w, http.StatusNotFound, "Task not found")
+			return
+		}
+		if err := s.planner.RestoreTask(ctx, taskID, req.Status); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		task, err := s.database.GetTaskByID(taskID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": task.Status})
+
+	case "handoff":
+		var req struct {
+			To   string `json:"to"`   // Colleague's email address
+			Note string `json:"note"` // Optional note added to the email
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if _, err := s.database.GetTaskByID(taskID); err != nil {
+			writeError(w, http.StatusNotFound, "Task not found")
+			return
+		}
+		item, err := s.planner.HandoffTask(ctx, taskID, req.To, req.Note)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, item)
+
+	case "tags":
+		var req struct {
+			Add    []string `json:"add"`
+			Remove []string `json:"remove"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if _, err := s.database.GetTaskByID(taskID); err != nil {
+			writeError(w, http.StatusNotFound, "Task not found")
+			return
+		}
+		for _, tag := range req.Add {
+			if _, err := s.database.AddTaskTag(taskID, tag); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		for _, tag := range req.Remove {
+			if err := s.database.RemoveTaskTag(taskID, tag); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+		tags, err := s.database.GetTaskTags(taskID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if tags == nil {
+			tags = []string{}
+		}
+		writeJSON(w, http.StatusOK, map[string][]string{"tags": tags})
+
 	case "feedback":
 		// Handle priority feedback submission
 		s.handleTaskFeedback(w, r, taskID)
@@ -187,9 +577,8 @@ func (s *Server) handleTaskAction(w http.ResponseWriter, r *http.Request) {
 
 // POST /api/tasks/:id/feedback - Submit priority feedback
 func (s *Server) handleTaskFeedback(w http.ResponseWriter, r *http.Request, taskID string) {
-	// Parse request body
 	var req struct {
-		Vote   int    `json:"vote"`   // -1 (thumbs down) or +1 (thumbs up)
+		Vote   int    `json:"vote"`   // -1 (ranked too high) or +1 (ranked too low)
 		Reason string `json:"reason"` // Optional reason text
 	}
 
@@ -198,22 +587,19 @@ func (s *Server) handleTaskFeedback(w http.ResponseWriter, r *http.Request, task
 		return
 	}
 
-	// Validate vote
 	if req.Vote != -1 && req.Vote != 1 {
 		writeError(w, http.StatusBadRequest, "Vote must be -1 or 1")
 		return
 	}
 
-	// Get task to retrieve current score
-	task, err := s.database.GetTaskByID(taskID)
-	if err != nil {
+	if _, err := s.database.GetTaskByID(taskID); err != nil {
 		writeError(w, http.StatusNotFound, "Task not found")
 		return
 	}
 
-	// Import scoring package for SaveFeedback
-	// Note: This requires adding the import at the top of the file
-	if err := s.saveFeedback(task.ID, req.Vote, req.Reason, task.Score, task.Score); err != nil {
+	// Learned boosts apply to every pending task, so this rescores them
+	task, err := s.planner.RecordFeedback(context.Background(), taskID, req.Vote, req.Reason)
+	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -221,9 +607,39 @@ func (s *Server) handleTaskFeedback(w http.ResponseWriter, r *http.Request, task
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"status": "success",
 		"vote":   req.Vote,
+		"score":  task.Score,
 	})
 }
 
+// GET /api/feedback - Score boosts learned from priority feedback, in points by project and sender
+func (s *Server) handleFeedback(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.planner.FeedbackBoosts())
+}
+
+// GET /api/tags - List tags in use with their open task counts
+func (s *Server) handleTags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	tags, err := s.database.GetTags()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if tags == nil {
+		tags = []*db.TagCount{}
+	}
+
+	writeJSON(w, http.StatusOK, tags)
+}
+
 // GET /api/priorities - Get all priorities
 // PUT /api/priorities - Update priorities
 func (s *Server) handlePriorities(w http.ResponseWriter, r *http.Request) {
@@ -267,8 +683,8 @@ func (s *Server) updatePriorities(w http.ResponseWriter, r *http.Request) {
 		KeyStakeholders: req.KeyStakeholders,
 	}
 
-	// Save to database
-	if err := s.database.UpdatePriorities(priorities); err != nil {
+	// Save to database, re-evaluating the top tasks against the new priorities in the background
+	if err := s.planner.SavePriorities(priorities); err != nil {
 		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update priorities: %v", err))
 		return
 	}
@@ -279,6 +695,86 @@ func (s *Server) updatePriorities(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "updated"})
 }
 
+// ScoringRequest switches the scoring profile
+type ScoringRequest struct {
+	Profile string `json:"profile"`
+}
+
+// GET /api/scoring - The scoring profile in use, its weights and the profiles to switch to
+// PUT /api/scoring - Switch the scoring profile and rescore every pending task
+func (s *Server) handleScoring(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.planner.GetScoring())
+	case http.MethodPut:
+		var req ScoringRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if _, ok := s.config.Planner.Scoring.ProfileWeights(req.Profile); !ok || strings.TrimSpace(req.Profile) == "" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Unknown scoring profile %q", req.Profile))
+			return
+		}
+		if err := s.planner.SetScoringProfile(r.Context(), req.Profile); err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to switch scoring profile: %v", err))
+			return
+		}
+		writeJSON(w, http.StatusOK, s.planner.GetScoring())
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// TimezoneRequest switches the timezone briefs are scheduled and due dates are read in
+type TimezoneRequest struct {
+	Timezone string `json:"timezone"` // IANA name, e.g. "Asia/Tokyo"
+}
+
+// GET /api/timezone - The timezone in use and the configured home timezone
+// PUT /api/timezone - Switch timezone while travelling, moving the scheduled briefs
+// DELETE /api/timezone - Switch back to the home timezone
+func (s *Server) handleTimezone(w http.ResponseWriter, r *http.Request) {
+	var name string
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.planner.Timezone())
+		return
+	case http.MethodPut:
+		var req TimezoneRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		name = strings.TrimSpace(req.Timezone)
+		if name == "" {
+			writeError(w, http.StatusBadRequest, "timezone is required")
+			return
+		}
+		if _, err := time.LoadLocation(name); err != nil || strings.EqualFold(name, "local") {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Unknown timezone %q", name))
+			return
+		}
+	case http.MethodDelete:
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	timezone, err := s.planner.SetTimezone(name)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to switch timezone: %v", err))
+		return
+	}
+	if s.scheduler != nil {
+		if err := s.scheduler.RescheduleClockJobs(); err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to reschedule briefs: %v", err))
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, timezone)
+}
+
 // POST /api/priorities/undo - Undo last priority change
 func (s *Server) handlePrioritiesUndo(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -308,7 +804,7 @@ func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
 	s.database.QueryRow("SELECT COUNT(*) FROM tasks").Scan(&stats.TaskCount)
 	s.database.QueryRow("SELECT COUNT(*) FROM tasks WHERE status = 'pending'").Scan(&stats.PendingTasks)
 	s.database.QueryRow("SELECT COUNT(*) FROM tasks WHERE status = 'pending' AND score >= 4.0").Scan(&stats.HighPriorityTasks)
-	s.database.QueryRow("SELECT COUNT(*) FROM threads WHERE summary IS NULL OR summary = ''").Scan(&stats.ThreadsNeedingAI)
+	s.database.QueryRow("SELECT COUNT(*) FROM threads WHERE (summary IS NULL OR summary = '') AND skipped_by_rule IS NULL").Scan(&stats.ThreadsNeedingAI)
 
 	// Completed today
 	today := time.Now().Format("2006-01-02")
@@ -336,64 +832,169 @@ func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
 		stats.LastTasksSync = &tasksSyncStr
 	}
 
+	// First-run checklist progress
+	if setup, err := s.database.GetSetupProgress(); err == nil {
+		setup.Authenticated = s.config.Google.UserEmail != ""
+		setup.PrioritiesAdded = setup.PrioritiesAdded || !s.config.Priorities.IsEmpty()
+		stats.Setup = setup
+	}
+
+	for _, timing := range s.database.QueryTimings() {
+		stats.QueryTimings = append(stats.QueryTimings, QueryTimingResponse{
+			Kind:   timing.Kind,
+			Count:  timing.Count,
+			Errors: timing.Errors,
+			Slow:   timing.Slow,
+			AvgMs:  float64(timing.Average().Microseconds()) / 1000,
+			MaxMs:  float64(timing.Max.Microseconds()) / 1000,
+		})
+	}
+
 	writeJSON(w, http.StatusOK, stats)
 }
 
-// GET /api/threads - List threads with summaries
-func (s *Server) handleThreads(w http.ResponseWriter, r *http.Request) {
+// GET /api/budget - Today's usage against each configured LLM provider budget
+func (s *Server) handleBudget(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	threads, err := s.database.GetThreadsWithSummaries(500)
+	statuses, err := s.database.GetProviderBudgets(s.config.LLM.Budgets)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	// Convert to response format
-	response := make([]ThreadResponse, 0, len(threads))
-	for _, thread := range threads {
-		var nextFollowupTS *string
-		if thread.NextFollowupTS != nil {
-			formatted := thread.NextFollowupTS.Format(time.RFC3339)
-			nextFollowupTS = &formatted
-		}
+	budgets := make([]BudgetResponse, 0, len(statuses))
+	for _, status := range statuses {
+		budgets = append(budgets, toBudgetResponse(status))
+	}
+	writeJSON(w, http.StatusOK, budgets)
+}
 
-		response = append(response, ThreadResponse{
-			ID:             thread.ID,
-			LastHistoryID:  thread.LastHistoryID,
-			Summary:        thread.Summary,
-			SummaryHash:    thread.SummaryHash,
-			TaskCount:      thread.TaskCount,
-			NextFollowupTS: nextFollowupTS,
-			LastSynced:     thread.LastSynced.Format(time.RFC3339),
-		})
+// GET /api/llm/health - Availability of each LLM provider, with Ollama host load state
+func (s *Server) handleLLMHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
 	}
 
-	writeJSON(w, http.StatusOK, response)
+	writeJSON(w, http.StatusOK, s.llm.ProviderHealth(r.Context()))
 }
 
-// GET /api/threads/:id - Get a single thread by ID
-// GET /api/threads/:id/messages - Get messages for a thread
-func (s *Server) handleThreadMessages(w http.ResponseWriter, r *http.Request) {
+// GET /api/issues - Operations that are currently failing, with suggested fixes
+func (s *Server) handleIssues(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
-	// Extract thread ID from path
-	path := strings.TrimPrefix(r.URL.Path, "/api/threads/")
-	parts := strings.Split(path, "/")
-
-	threadID := parts[0]
-	if threadID == "" {
-		writeError(w, http.StatusBadRequest, "Invalid thread ID")
+	issues, err := s.database.GetOpenIssues()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	if issues == nil {
+		issues = []*db.Issue{}
+	}
+	writeJSON(w, http.StatusOK, issues)
+}
 
-	// Check if requesting messages or just the thread
+// GET /api/deliveries - Briefs and reminders from the last week and whether they were delivered
+func (s *Server) handleDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -db.DeliveryHistoryDays)
+	items, err := s.database.GetDeliveryHistory(since, 100)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if items == nil {
+		items = []*db.OutboxItem{}
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// GET /api/threads - List threads with summaries
+func (s *Server) handleThreads(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	threads, err := s.database.GetThreadsWithSummaries(500)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Convert to response format
+	response := make([]ThreadResponse, 0, len(threads))
+	for _, thread := range threads {
+		response = append(response, toThreadResponse(thread))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// GET /api/threads/:id - Get a single thread by ID
+// GET /api/threads/:id/messages - Get messages for a thread
+// GET /api/threads/:id/summary-changes - Get what changed each time the thread was re-summarized
+// POST /api/threads/:id/draft - Draft a reply in a persona
+// POST /api/threads/:id/reply - Save a reviewed reply to Gmail Drafts or send it
+func (s *Server) handleThreadMessages(w http.ResponseWriter, r *http.Request) {
+	// Extract thread ID from path
+	path := strings.TrimPrefix(r.URL.Path, "/api/threads/")
+	parts := strings.Split(path, "/")
+
+	threadID := parts[0]
+	if threadID == "" {
+		writeError(w, http.StatusBadRequest, "Invalid thread ID")
+		return
+	}
+
+	if len(parts) >= 2 && parts[1] == "draft" {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		s.handleDraftReply(w, r, threadID)
+		return
+	}
+
+	if len(parts) >= 2 && parts[1] == "reply" {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		s.handleSaveReply(w, r, threadID)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if len(parts) >= 2 && parts[1] == "summary-changes" {
+		changes, err := s.database.GetSummaryChanges(threadID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if changes == nil {
+			changes = []*db.SummaryChange{}
+		}
+		writeJSON(w, http.StatusOK, changes)
+		return
+	}
+
+	// Check if requesting messages or just the thread
 	if len(parts) >= 2 && parts[1] == "messages" {
 		// Get messages for thread
 		messages, err := s.database.GetThreadMessages(threadID)
@@ -466,7 +1067,7 @@ func (s *Server) handleQueue(w http.ResponseWriter, r *http.Request) {
 		SELECT DISTINCT t.id, ANY_VALUE(m.subject) as subject, ANY_VALUE(m.from_addr) as from_addr, MAX(m.ts) as ts
 		FROM threads t
 		JOIN messages m ON t.id = m.thread_id
-		WHERE t.summary IS NULL OR t.summary = ''
+		WHERE (t.summary IS NULL OR t.summary = '') AND t.skipped_by_rule IS NULL
 		GROUP BY t.id
 		ORDER BY MAX(m.ts) DESC
 		LIMIT 500
@@ -539,7 +1140,7 @@ func (s *Server) handleTasksReprocess(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]string{"status": "reprocessing started"})
 }
 
-// POST /api/brief - Send daily brief via Google Chat
+// POST /api/brief - Send daily brief via the configured notification channels
 func (s *Server) handleBrief(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
@@ -562,8 +1163,8 @@ func (s *Server) handleBrief(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Send the daily brief via Chat API
-	if err := s.clients.Chat.SendDailyBrief(ctx, tasks, events); err != nil {
+	// Send the daily brief to the configured channels
+	if err := s.planner.DeliverDailyBrief(ctx, tasks, events); err != nil {
 		writeError(w, http.StatusInternalServerError, "Failed to send brief: "+err.Error())
 		return
 	}
@@ -574,3 +1175,1155 @@ func (s *Server) handleBrief(w http.ResponseWriter, r *http.Request) {
 		"events_count": len(events),
 	})
 }
+
+// GET /api/plans/history - List recent plans and reviews, such as end-of-day briefs
+// GET /api/plans/history?kind=end_of_day - List plans of one kind
+func (s *Server) handlePlanHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	entries, err := s.database.GetPlanHistory(r.URL.Query().Get("kind"), 30)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if entries == nil {
+		entries = []*db.PlanHistoryEntry{}
+	}
+
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// GET /api/corrections - Summarize corrections to extracted task fields over the last 30 days
+func (s *Server) handleCorrections(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	stats, err := s.database.GetCorrectionStats(time.Now().AddDate(0, 0, -30))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if stats == nil {
+		stats = []*db.CorrectionStat{}
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// GET /api/projects - Roll up open tasks by project: count, max and average score, nearest due
+func (s *Server) handleProjects(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	projects, err := s.database.GetProjectSummaries()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if projects == nil {
+		projects = []*db.ProjectSummary{}
+	}
+
+	writeJSON(w, http.StatusOK, projects)
+}
+
+// GET /api/projects/:name/tasks - List open tasks for a project (review before closing)
+// POST /api/projects/:name/close - Bulk-complete or re-home a project's open tasks
+// POST /api/projects/:name/billing - Set the client a project is invoiced to and whether it's billable
+func (s *Server) handleProjectAction(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/projects/")
+	idx := strings.LastIndex(path, "/")
+	if idx <= 0 {
+		writeError(w, http.StatusBadRequest, "Invalid path")
+		return
+	}
+
+	project := path[:idx]
+	action := path[idx+1:]
+
+	switch action {
+	case "tasks":
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		tasks, err := s.planner.GetProjectTasks(project)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		response := make([]TaskResponse, 0, len(tasks))
+		for _, task := range tasks {
+			response = append(response, toTaskResponse(task))
+		}
+		writeJSON(w, http.StatusOK, response)
+
+	case "close":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		var req struct {
+			Action        string   `json:"action"`         // "complete", "rehome" or "archive"
+			TargetProject string   `json:"target_project"` // Required for "rehome"
+			TaskIDs       []string `json:"task_ids"`       // Optional subset; empty means all open tasks
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if req.Action == "" {
+			req.Action = planner.ProjectCloseComplete
+		}
+		if req.Action != planner.ProjectCloseComplete && req.Action != planner.ProjectCloseRehome && req.Action != planner.ProjectCloseArchive {
+			writeError(w, http.StatusBadRequest, "Action must be complete, rehome or archive")
+			return
+		}
+
+		result, err := s.planner.CloseProject(context.Background(), project, req.Action, req.TargetProject, req.TaskIDs)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+
+	case "billing":
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+
+		var req struct {
+			Client   string `json:"client"`
+			Billable bool   `json:"billable"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		if err := s.database.SetProjectBilling(project, req.Client, req.Billable); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, db.ProjectBilling{Name: project, Client: req.Client, Billable: req.Billable})
+
+	default:
+		writeError(w, http.StatusBadRequest, "Invalid action")
+	}
+}
+
+// GET /api/time/export?format=csv|harvest|toggl&from=YYYY-MM-DD&to=YYYY-MM-DD - Focus and task
+// time grouped by client and project, as CSV for invoicing tools
+func (s *Server) handleTimeExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	start, end, err := planner.TimeExportRange(query.Get("from"), query.Get("to"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entries, err := s.database.GetTimeEntries(start, end)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	format := query.Get("format")
+	if format == "" {
+		format = planner.TimeExportCSV
+	}
+
+	var buf bytes.Buffer
+	if err := planner.WriteTimeExport(&buf, entries, format, s.config.Google.UserEmail); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("time-%s-%s-%s.csv", format, start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02"))
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(buf.Bytes())
+}
+
+// GET /api/digest - Low-priority tasks and FYI threads held back from the daily brief
+func (s *Server) handleDigest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	digest, err := s.planner.BuildDigest()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := DigestResponse{
+		Tasks:    make([]TaskResponse, 0, len(digest.Tasks)),
+		Threads:  make([]ThreadResponse, 0, len(digest.Threads)),
+		TagStats: digest.TagStats,
+		Since:    digest.Since.Format(time.RFC3339),
+	}
+	if response.TagStats == nil {
+		response.TagStats = []*db.TagStats{}
+	}
+	for _, task := range digest.Tasks {
+		response.Tasks = append(response.Tasks, toTaskResponse(task))
+	}
+	for _, thread := range digest.Threads {
+		response.Threads = append(response.Threads, toThreadResponse(thread))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// POST /api/digest/archive - Bulk-archive digest tasks and threads
+func (s *Server) handleDigestArchive(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req struct {
+		TaskIDs   []string `json:"task_ids"`
+		ThreadIDs []string `json:"thread_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if len(req.TaskIDs) == 0 && len(req.ThreadIDs) == 0 {
+		writeError(w, http.StatusBadRequest, "task_ids or thread_ids is required")
+		return
+	}
+
+	result, err := s.planner.ArchiveDigest(req.TaskIDs, req.ThreadIDs)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// POST /api/capture - Create tasks from a voice note
+// Accepts a JSON {"transcript": "..."} body, a multipart form with an "audio" file,
+// or a raw audio/* body (as sent by an iOS Shortcut)
+func (s *Server) handleCapture(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Transcription and extraction take longer than the server's default timeouts
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Now().Add(time.Minute))
+	rc.SetWriteDeadline(time.Now().Add(3 * time.Minute))
+
+	maxBytes := int64(s.config.STT.MaxUploadMB) << 20
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+
+	var transcript string
+	var audio []byte
+	filename := ""
+
+	contentType := r.Header.Get("Content-Type")
+	switch {
+	case strings.HasPrefix(contentType, "application/json"):
+		var req struct {
+			Transcript string `json:"transcript"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		transcript = req.Transcript
+
+	case strings.HasPrefix(contentType, "multipart/form-data"):
+		if err := r.ParseMultipartForm(maxBytes); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid form: %v", err))
+			return
+		}
+		transcript = r.FormValue("transcript")
+		if transcript == "" {
+			file, header, err := r.FormFile("audio")
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "audio file or transcript is required")
+				return
+			}
+			defer file.Close()
+			if audio, err = io.ReadAll(file); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("Failed to read audio: %v", err))
+				return
+			}
+			filename = header.Filename
+		}
+
+	case strings.HasPrefix(contentType, "audio/"):
+		var err error
+		if audio, err = io.ReadAll(r.Body); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Failed to read audio: %v", err))
+			return
+		}
+		filename = "capture." + strings.TrimPrefix(strings.SplitN(contentType, ";", 2)[0], "audio/")
+
+	default:
+		writeError(w, http.StatusUnsupportedMediaType, "Expected application/json, multipart/form-data or audio/*")
+		return
+	}
+
+	ctx := r.Context()
+
+	if transcript == "" {
+		if len(audio) == 0 {
+			writeError(w, http.StatusBadRequest, "audio file or transcript is required")
+			return
+		}
+
+		transcriber, err := stt.New(s.config.STT)
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+
+		startTime := time.Now()
+		transcript, err = transcriber.Transcribe(ctx, audio, filename)
+		s.database.LogUsage("stt", s.config.STT.Backend, 0, 0, time.Since(startTime), err)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, fmt.Sprintf("Transcription failed: %v", err))
+			return
+		}
+	}
+
+	if strings.TrimSpace(transcript) == "" {
+		writeError(w, http.StatusUnprocessableEntity, "No speech found in audio")
+		return
+	}
+
+	tasks, err := s.planner.CaptureTasks(ctx, transcript)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := CaptureResponse{
+		Transcript: transcript,
+		Tasks:      make([]TaskResponse, 0, len(tasks)),
+	}
+	for _, task := range tasks {
+		response.Tasks = append(response.Tasks, toTaskResponse(task))
+	}
+
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// GET /api/search?q=&types= - Search tasks, threads, messages, events and Drive documents in one
+// call, blending keyword, semantic and recency ranking. types is a comma-separated filter.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+
+	var kinds []string
+	if types := r.URL.Query().Get("types"); types != "" {
+		for _, kind := range strings.Split(types, ",") {
+			kind = strings.TrimSpace(strings.ToLower(kind))
+			if !slices.Contains(db.SearchKinds, kind) {
+				writeError(w, http.StatusBadRequest, "types must be from: "+strings.Join(db.SearchKinds, ", "))
+				return
+			}
+			kinds = append(kinds, kind)
+		}
+	}
+
+	results, err := s.planner.Search(r.Context(), query, kinds...)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if results == nil {
+		results = []*db.SearchResult{}
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// POST /api/ask - Answer a question about mail and tasks
+func (s *Server) handleAsk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req struct {
+		Question string `json:"question"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Question) == "" {
+		writeError(w, http.StatusBadRequest, "question is required")
+		return
+	}
+
+	// Retrieval plus an LLM call can outlast the server's write timeout
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Now().Add(2 * time.Minute))
+
+	answer, err := s.planner.Ask(r.Context(), req.Question)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := AskResponse{
+		Question: answer.Question,
+		Answer:   answer.Answer,
+		Messages: make([]MessageResponse, 0, len(answer.Messages)),
+		Tasks:    make([]TaskResponse, 0, len(answer.Tasks)),
+	}
+	for _, msg := range answer.Messages {
+		response.Messages = append(response.Messages, MessageResponse{
+			ID:          msg.ID,
+			ThreadID:    msg.ThreadID,
+			From:        msg.From,
+			To:          msg.To,
+			Subject:     msg.Subject,
+			Snippet:     msg.Snippet,
+			Timestamp:   msg.Timestamp.Format(time.RFC3339),
+			Labels:      msg.Labels,
+			Sensitivity: msg.Sensitivity,
+		})
+	}
+	for _, task := range answer.Tasks {
+		response.Tasks = append(response.Tasks, toTaskResponse(task))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// /api/focus - GET focus mode status, POST to start a session, DELETE to end it early
+func (s *Server) handleFocus(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		session, err := s.planner.GetFocusSession()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, toFocusResponse(session))
+
+	case http.MethodPost:
+		var req struct {
+			Minutes int `json:"minutes"`
+		}
+		if r.ContentLength > 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, http.StatusBadRequest, "Invalid request body")
+				return
+			}
+		}
+		if req.Minutes < 0 || req.Minutes > 24*60 {
+			writeError(w, http.StatusBadRequest, "minutes must be between 1 and 1440")
+			return
+		}
+
+		session, err := s.planner.StartFocus(req.Minutes)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, toFocusResponse(session))
+
+	case http.MethodDelete:
+		summary, err := s.planner.StopFocus(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if summary == nil {
+			writeError(w, http.StatusNotFound, "Focus mode is not active")
+			return
+		}
+
+		response := FocusSummaryResponse{
+			StartedAt:     summary.Session.StartedAt.Format(time.RFC3339),
+			EndedAt:       summary.Session.End().Format(time.RFC3339),
+			Threads:       make([]FocusThreadResponse, 0, len(summary.Threads)),
+			Tasks:         make([]TaskResponse, 0, len(summary.Tasks)),
+			Notifications: summary.Notifications,
+		}
+		for _, thread := range summary.Threads {
+			response.Threads = append(response.Threads, FocusThreadResponse{
+				ThreadID: thread.ThreadID,
+				Subject:  thread.Subject,
+				From:     thread.From,
+				Messages: thread.Messages,
+			})
+		}
+		for _, task := range summary.Tasks {
+			response.Tasks = append(response.Tasks, toTaskResponse(task))
+		}
+		writeJSON(w, http.StatusOK, response)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// Work timer response structure
+type WorkResponse struct {
+	Active  bool            `json:"active"`
+	Session *db.WorkSession `json:"session,omitempty"`
+}
+
+// GET /api/work - The running work session, if any
+// POST /api/work - Start timing work on a task ({"task_id": "..."}), turning focus mode on
+// DELETE /api/work - Stop the work timer
+func (s *Server) handleWork(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		session, err := s.planner.GetWorkSession()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, WorkResponse{Active: session != nil, Session: session})
+
+	case http.MethodPost:
+		var req struct {
+			TaskID string `json:"task_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TaskID == "" {
+			writeError(w, http.StatusBadRequest, "task_id is required")
+			return
+		}
+		if task, err := s.database.GetTaskByID(req.TaskID); err != nil || task == nil {
+			writeError(w, http.StatusNotFound, "Task not found")
+			return
+		}
+
+		session, err := s.planner.StartWork(req.TaskID)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, WorkResponse{Active: true, Session: session})
+
+	case http.MethodDelete:
+		session, err := s.planner.StopWork()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if session == nil {
+			writeError(w, http.StatusNotFound, "No task is being timed")
+			return
+		}
+		writeJSON(w, http.StatusOK, WorkResponse{Session: session})
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// POST /api/work/interrupt - Count an interruption against the running work session
+func (s *Server) handleWorkInterrupt(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	session, err := s.planner.InterruptWork()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if session == nil {
+		writeError(w, http.StatusNotFound, "No task is being timed")
+		return
+	}
+	writeJSON(w, http.StatusOK, WorkResponse{Active: true, Session: session})
+}
+
+// GET /api/work/stats - Tracked time against estimated effort, per task and for recent weeks
+func (s *Server) handleWorkStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	stats, err := s.planner.GetEffortStats()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// GET/POST /api/rules - List or add rules excluding threads from AI processing, or tagging
+// their tasks when a tag is given
+func (s *Server) handleRules(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		rules, err := s.database.GetProcessingRules()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		tagRules, err := s.database.GetTagRules()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rules = append(rules, tagRules...)
+
+		response := make([]RuleResponse, 0, len(rules))
+		for _, rule := range rules {
+			response = append(response, toRuleResponse(rule))
+		}
+		writeJSON(w, http.StatusOK, response)
+
+	case http.MethodPost:
+		var req struct {
+			Kind    string `json:"kind"`
+			Pattern string `json:"pattern"`
+			Tag     string `json:"tag"` // Tag matching tasks instead of skipping the threads
+			Note    string `json:"note"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if !db.ValidRuleKind(req.Kind) {
+			writeError(w, http.StatusBadRequest, "kind must be one of thread, sender, domain or label")
+			return
+		}
+		if strings.TrimSpace(req.Pattern) == "" {
+			writeError(w, http.StatusBadRequest, "pattern is required")
+			return
+		}
+
+		if req.Tag != "" {
+			if _, err := db.NormalizeTag(req.Tag); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			rule, err := s.database.AddTagRule(req.Kind, req.Pattern, req.Tag, req.Note)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusCreated, toRuleResponse(rule))
+			return
+		}
+
+		rule, err := s.database.AddProcessingRule(req.Kind, req.Pattern, req.Note)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// Take matching threads out of the queue right away
+		if _, err := s.database.ApplyProcessingRules(); err != nil {
+			log.Printf("Failed to apply processing rules: %v", err)
+		}
+
+		writeJSON(w, http.StatusCreated, toRuleResponse(rule))
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// DELETE /api/rules/{id} - Remove a rule, returning the threads it skipped to the queue. Tags
+// added by a tag rule stay on their tasks.
+func (s *Server) handleRuleAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/rules/")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "Invalid path")
+		return
+	}
+
+	var err error
+	if strings.HasPrefix(id, db.TagRulePrefix) {
+		err = s.database.DeleteTagRule(id)
+	} else {
+		err = s.database.DeleteProcessingRule(id)
+	}
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// GET /api/agenda - Today's and tomorrow's events, with their prep notes (?range=week for the
+// next seven days)
+func (s *Server) handleAgenda(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	days := 2
+	if r.URL.Query().Get("range") == "week" {
+		days = 7
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	events, err := s.database.GetAgenda(today, today.AddDate(0, 0, days))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if events == nil {
+		events = []*db.Event{}
+	}
+	writeJSON(w, http.StatusOK, events)
+}
+
+// POST /api/agenda/:id/respond - Accept, tentatively accept or decline an invite
+// ({"response": "declined", "note": "..."})
+// POST /api/agenda/:id/decline-note - Draft a polite note declining an invite
+func (s *Server) handleAgendaAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/agenda/"), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		writeError(w, http.StatusBadRequest, "Invalid path")
+		return
+	}
+
+	switch parts[1] {
+	case "respond":
+		var req struct {
+			Response string `json:"response"`
+			Note     string `json:"note"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		event, err := s.planner.RespondToEvent(r.Context(), parts[0], req.Response, req.Note)
+		if err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, event)
+	case "decline-note":
+		note, err := s.planner.DraftDeclineNote(r.Context(), parts[0])
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"note": note})
+	default:
+		writeError(w, http.StatusNotFound, "Unknown action")
+	}
+}
+
+// GET /api/waiting - Requests the user sent that are still waiting on a reply, soonest due first
+func (s *Server) handleWaiting(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	items, err := s.database.GetWaitingItems()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if items == nil {
+		items = []*db.WaitingItem{}
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// POST /api/waiting/{message_id}/dismiss - Stop waiting on a request
+func (s *Server) handleWaitingAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/waiting/")
+	id := strings.TrimSuffix(path, "/dismiss")
+	if id == "" || id == path {
+		writeError(w, http.StatusBadRequest, "Invalid path")
+		return
+	}
+
+	if err := s.database.DismissWaitingItem(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "dismissed"})
+}
+
+// GET /api/commitments - Promises the user made in sent mail and hasn't followed up on, soonest
+// due first
+func (s *Server) handleCommitments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	commitments, err := s.database.GetOpenCommitments(0)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if commitments == nil {
+		commitments = []*db.Commitment{}
+	}
+	writeJSON(w, http.StatusOK, commitments)
+}
+
+// POST /api/commitments/{id}/dismiss - Stop tracking a commitment
+func (s *Server) handleCommitmentAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/commitments/")
+	id := strings.TrimSuffix(path, "/dismiss")
+	if id == "" || id == path {
+		writeError(w, http.StatusBadRequest, "Invalid path")
+		return
+	}
+
+	if err := s.database.DismissCommitment(id); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "dismissed"})
+}
+
+// GET /api/timeblocks - Today's focus blocks
+// POST /api/timeblocks - Plan today's focus blocks (returns the existing ones if already planned)
+func (s *Server) handleTimeBlocks(w http.ResponseWriter, r *http.Request) {
+	var blocks []*db.TimeBlock
+	var err error
+
+	switch r.Method {
+	case http.MethodGet:
+		blocks, err = s.planner.GetTimeBlocks()
+	case http.MethodPost:
+		if !s.config.Planner.TimeBlocking.Enabled {
+			writeError(w, http.StatusBadRequest, "Time blocking is not enabled")
+			return
+		}
+		blocks, err = s.planner.ScheduleTimeBlocks(r.Context())
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := make([]TimeBlockResponse, 0, len(blocks))
+	for _, block := range blocks {
+		response = append(response, toTimeBlockResponse(block))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// POST /api/timeblocks/{id}/confirm - Write a proposed block to the calendar
+// POST /api/timeblocks/{id}/accept - Keep a proposed block in the day's plan
+// POST /api/timeblocks/{id}/decline - Dismiss a block, removing its calendar event
+// POST /api/timeblocks/{id}/adjust - Move or resize a block, or drop one of its tasks
+func (s *Server) handleTimeBlockAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/timeblocks/"), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		writeError(w, http.StatusBadRequest, "Invalid path")
+		return
+	}
+
+	var block *db.TimeBlock
+	var err error
+	switch parts[1] {
+	case "confirm":
+		block, err = s.planner.ConfirmTimeBlock(r.Context(), parts[0])
+	case "accept":
+		block, err = s.planner.AcceptTimeBlock(parts[0])
+	case "decline":
+		block, err = s.planner.DeclineTimeBlock(r.Context(), parts[0])
+	case "adjust":
+		var req struct {
+			ShiftMinutes  int    `json:"shift_minutes"`
+			ResizeMinutes int    `json:"resize_minutes"`
+			DropTaskID    string `json:"drop_task_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		block, err = s.planner.AdjustTimeBlock(r.Context(), parts[0], planner.TimeBlockChange{
+			Shift:      time.Duration(req.ShiftMinutes) * time.Minute,
+			Resize:     time.Duration(req.ResizeMinutes) * time.Minute,
+			DropTaskID: req.DropTaskID,
+		})
+	default:
+		writeError(w, http.StatusNotFound, "Unknown action")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toTimeBlockResponse(block))
+}
+
+// POST /api/timeblocks/commit - Finish planning the day, optionally writing accepted blocks to the calendar
+func (s *Server) handleCommitDayPlan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req struct {
+		Calendar bool `json:"calendar"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	blocks, err := s.planner.CommitDayPlan(r.Context(), req.Calendar)
+	if blocks == nil && err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := DayPlanResponse{Blocks: make([]TimeBlockResponse, 0, len(blocks))}
+	for _, block := range blocks {
+		response.Blocks = append(response.Blocks, toTimeBlockResponse(block))
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// GET/PUT /api/tui/session?client_id=... - Saved TUI layout for one terminal
+func (s *Server) handleTUISession(w http.ResponseWriter, r *http.Request) {
+	clientID := r.URL.Query().Get("client_id")
+	if clientID == "" {
+		writeError(w, http.StatusBadRequest, "client_id is required")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		session, err := s.database.GetTUISession(clientID)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, session)
+
+	case http.MethodPut:
+		var session db.TUISession
+		if err := json.NewDecoder(r.Body).Decode(&session); err != nil {
+			writeError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		if err := s.database.SaveTUISession(clientID, &session); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, session)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// POST /api/presence - TUI heartbeat: {"client_id": "...", "idle_seconds": 12}, the time since
+// the user last pressed a key, so urgent alerts follow them to the TUI while they're using it
+func (s *Server) handlePresence(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req struct {
+		ClientID    string `json:"client_id"`
+		IdleSeconds int    `json:"idle_seconds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.IdleSeconds < 0 {
+		writeError(w, http.StatusBadRequest, "idle_seconds must not be negative")
+		return
+	}
+
+	lastActive := time.Now().Add(-time.Duration(req.IdleSeconds) * time.Second)
+	if err := s.database.RecordTUIPresence(lastActive); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// toFocusResponse converts a focus session (nil when off) to its API representation
+func toFocusResponse(session *db.FocusSession) FocusResponse {
+	if session == nil {
+		return FocusResponse{}
+	}
+
+	startedAt := session.StartedAt.Format(time.RFC3339)
+	endsAt := session.EndsAt.Format(time.RFC3339)
+	return FocusResponse{
+		Active:           session.Active(time.Now()),
+		StartedAt:        &startedAt,
+		EndsAt:           &endsAt,
+		RemainingSeconds: int(time.Until(session.EndsAt).Seconds()),
+	}
+}
+
+// toRuleResponse converts a processing rule to its API representation
+func toRuleResponse(rule *db.ProcessingRule) RuleResponse {
+	return RuleResponse{
+		ID:        rule.ID,
+		Kind:      rule.Kind,
+		Pattern:   rule.Pattern,
+		Tag:       rule.Tag,
+		Note:      rule.Note,
+		CreatedAt: rule.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// toTimeBlockResponse converts a time block to its API representation
+func toTimeBlockResponse(block *db.TimeBlock) TimeBlockResponse {
+	return TimeBlockResponse{
+		ID:         block.ID,
+		Name:       block.Name,
+		Start:      block.StartTS.Format(time.RFC3339),
+		End:        block.EndTS.Format(time.RFC3339),
+		Status:     block.Status,
+		Conflict:   block.Conflict,
+		TaskIDs:    block.TaskIDs,
+		TaskTitles: block.TaskTitles,
+	}
+}
+
+// toBudgetResponse converts a provider budget status to its API representation
+func toBudgetResponse(status *db.ProviderBudgetStatus) BudgetResponse {
+	resp := BudgetResponse{
+		Provider:   status.Provider,
+		MaxTokens:  status.MaxTokens,
+		MaxCost:    status.MaxCost,
+		UsedTokens: status.UsedTokens,
+		UsedCost:   status.UsedCost,
+		Exhausted:  status.Exhausted(),
+		ResetsAt:   status.ResetsAt.Format(time.RFC3339),
+	}
+	if remaining := status.RemainingTokens(); remaining >= 0 {
+		resp.RemainingTokens = &remaining
+	}
+	if remaining := status.RemainingCost(); remaining >= 0 {
+		resp.RemainingCost = &remaining
+	}
+	return resp
+}
+
+// toTaskResponse converts a task to its API representation
+func toTaskResponse(task *db.Task) TaskResponse {
+	var dueTS *string
+	if task.DueTS != nil {
+		formatted := task.DueTS.Format(time.RFC3339)
+		dueTS = &formatted
+	}
+
+	return TaskResponse{
+		ID:          task.ID,
+		Source:      task.Source,
+		SourceID:    task.SourceID,
+		Title:       task.Title,
+		Description: task.Description,
+		DueTS:       dueTS,
+		Project:     task.Project,
+		Impact:      task.Impact,
+		Urgency:     task.Urgency,
+		Effort:      task.Effort,
+		Stakeholder: task.Stakeholder,
+		Score:       task.Score,
+		Status:      task.Status,
+		Recurrence:  task.Recurrence,
+		Tags:        task.Tags,
+		ClaimedBy:   task.ClaimedBy,
+		Comments:    task.Comments,
+		CreatedAt:   task.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   task.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// toThreadResponse converts a thread to its API representation
+func toThreadResponse(thread *db.Thread) ThreadResponse {
+	var nextFollowupTS *string
+	if thread.NextFollowupTS != nil {
+		formatted := thread.NextFollowupTS.Format(time.RFC3339)
+		nextFollowupTS = &formatted
+	}
+
+	return ThreadResponse{
+		ID:             thread.ID,
+		LastHistoryID:  thread.LastHistoryID,
+		Summary:        thread.Summary,
+		SummaryHash:    thread.SummaryHash,
+		TaskCount:      thread.TaskCount,
+		PriorityScore:  thread.PriorityScore,
+		NextFollowupTS: nextFollowupTS,
+		LastSynced:     thread.LastSynced.Format(time.RFC3339),
+	}
+}
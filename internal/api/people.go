@@ -0,0 +1,101 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// maxPeople caps the people listed by GET /api/people
+const maxPeople = 200
+
+// personHistoryLimit is how many recent threads a person's details include
+const personHistoryLimit = 10
+
+// WorkloadResponse is how loaded everyone the user delegates to is, with who to delegate to next
+type WorkloadResponse struct {
+	People    []*db.Workload `json:"people"`
+	Suggested []*db.Workload `json:"suggested"`
+}
+
+// PersonResponse is one person's stats with their recent threads and what's owed both ways
+type PersonResponse struct {
+	*db.Person
+	History *db.ContactHistory `json:"history,omitempty"`
+}
+
+// GET /api/people - People you have mail with, most recent interaction first (?q= filters by
+// name or address)
+func (s *Server) handlePeople(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	people, err := s.database.GetPeople(r.URL.Query().Get("q"), maxPeople)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if people == nil {
+		people = []*db.Person{}
+	}
+
+	writeJSON(w, http.StatusOK, people)
+}
+
+// GET /api/people/:email - A person's stats, recent threads, tasks you owe them and replies
+// they owe you
+func (s *Server) handlePerson(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	email, err := url.PathUnescape(strings.TrimPrefix(r.URL.Path, "/api/people/"))
+	if err != nil || email == "" || strings.Contains(email, "/") {
+		writeError(w, http.StatusBadRequest, "Invalid path")
+		return
+	}
+
+	person, err := s.database.GetPerson(email)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if person == nil {
+		writeError(w, http.StatusNotFound, "No mail with "+email)
+		return
+	}
+
+	history, err := s.database.GetContactHistory(person.Email, s.config.Google.UserEmail, personHistoryLimit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &PersonResponse{Person: person, History: history})
+}
+
+// GET /api/workload - Open requests, handoffs and promises to and from each person, how quickly
+// they answer, and who to delegate to next
+func (s *Server) handleWorkload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	workloads, err := s.database.GetWorkloads()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	suggested := db.SuggestDelegates(workloads, db.MaxDelegateSuggestions)
+	if suggested == nil {
+		suggested = []*db.Workload{}
+	}
+
+	writeJSON(w, http.StatusOK, &WorkloadResponse{People: workloads, Suggested: suggested})
+}
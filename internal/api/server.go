@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -11,16 +12,20 @@ import (
 
 	"github.com/alexrabarts/focus-agent/internal/config"
 	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/events"
 	"github.com/alexrabarts/focus-agent/internal/google"
 	"github.com/alexrabarts/focus-agent/internal/llm"
+	"github.com/alexrabarts/focus-agent/internal/metrics"
 	"github.com/alexrabarts/focus-agent/internal/planner"
-	"github.com/alexrabarts/focus-agent/internal/scoring"
 )
 
 // Scheduler interface to avoid circular dependency
 type Scheduler interface {
 	ProcessNewMessages()
 	ReprocessAITasks() error
+	GetNextRuns() map[string]time.Time
+	GuardWarnings() []string
+	RescheduleClockJobs() error
 }
 
 type Server struct {
@@ -29,6 +34,7 @@ type Server struct {
 	llm       llm.Client
 	planner   *planner.Planner
 	scheduler Scheduler
+	events    *events.Bus
 	config    *config.Config
 	server    *http.Server
 }
@@ -48,6 +54,11 @@ func (s *Server) SetScheduler(scheduler Scheduler) {
 	s.scheduler = scheduler
 }
 
+// SetEvents sets the bus streamed to clients of /api/events
+func (s *Server) SetEvents(bus *events.Bus) {
+	s.events = bus
+}
+
 func (s *Server) Start(port int) error {
 	mux := http.NewServeMux()
 
@@ -55,24 +66,93 @@ func (s *Server) Start(port int) error {
 	mux.HandleFunc("/api/tasks", s.authMiddleware(s.handleTasks))
 	mux.HandleFunc("/api/tasks/", s.authMiddleware(s.handleTaskAction))
 	mux.HandleFunc("/api/tasks/reprocess", s.authMiddleware(s.handleTasksReprocess))
+	mux.HandleFunc("/api/corrections", s.authMiddleware(s.handleCorrections))
+	mux.HandleFunc("/api/projects", s.authMiddleware(s.handleProjects))
+	mux.HandleFunc("/api/projects/", s.authMiddleware(s.handleProjectAction))
+	mux.HandleFunc("/api/tags", s.authMiddleware(s.handleTags))
+	mux.HandleFunc("/api/feedback", s.authMiddleware(s.handleFeedback))
 	mux.HandleFunc("/api/priorities", s.authMiddleware(s.handlePriorities))
+	mux.HandleFunc("/api/scoring", s.authMiddleware(s.handleScoring))
+	mux.HandleFunc("/api/timezone", s.authMiddleware(s.handleTimezone))
 	mux.HandleFunc("/api/priorities/undo", s.authMiddleware(s.handlePrioritiesUndo))
 	mux.HandleFunc("/api/stats", s.authMiddleware(s.handleStats))
+	mux.HandleFunc("/api/budget", s.authMiddleware(s.handleBudget))
+	mux.HandleFunc("/api/llm/health", s.authMiddleware(s.handleLLMHealth))
+	mux.HandleFunc("/api/issues", s.authMiddleware(s.handleIssues))
+	mux.HandleFunc("/api/deliveries", s.authMiddleware(s.handleDeliveries))
+	mux.HandleFunc("/api/status", s.authMiddleware(s.handleStatus))
 	mux.HandleFunc("/api/threads", s.authMiddleware(s.handleThreads))
 	mux.HandleFunc("/api/threads/", s.authMiddleware(s.handleThreadMessages))
+	mux.HandleFunc("/api/front/teammates", s.authMiddleware(s.handleFrontTeammates))
+	mux.HandleFunc("/api/front/", s.authMiddleware(s.handleFrontAction))
+	mux.HandleFunc("/api/personas", s.authMiddleware(s.handlePersonas))
+	mux.HandleFunc("/api/people", s.authMiddleware(s.handlePeople))
+	mux.HandleFunc("/api/people/", s.authMiddleware(s.handlePerson))
+	mux.HandleFunc("/api/workload", s.authMiddleware(s.handleWorkload))
 	mux.HandleFunc("/api/queue", s.authMiddleware(s.handleQueue))
 	mux.HandleFunc("/api/queue/process", s.authMiddleware(s.handleQueueProcess))
+	mux.HandleFunc("/api/rules", s.authMiddleware(s.handleRules))
+	mux.HandleFunc("/api/rules/", s.authMiddleware(s.handleRuleAction))
+	mux.HandleFunc("/api/agenda", s.authMiddleware(s.handleAgenda))
+	mux.HandleFunc("/api/agenda/", s.authMiddleware(s.handleAgendaAction))
+	mux.HandleFunc("/api/waiting", s.authMiddleware(s.handleWaiting))
+	mux.HandleFunc("/api/waiting/", s.authMiddleware(s.handleWaitingAction))
+	mux.HandleFunc("/api/commitments", s.authMiddleware(s.handleCommitments))
+	mux.HandleFunc("/api/commitments/", s.authMiddleware(s.handleCommitmentAction))
 	mux.HandleFunc("/api/brief", s.authMiddleware(s.handleBrief))
+	mux.HandleFunc("/api/plans/history", s.authMiddleware(s.handlePlanHistory))
+	mux.HandleFunc("/api/digest", s.authMiddleware(s.handleDigest))
+	mux.HandleFunc("/api/digest/archive", s.authMiddleware(s.handleDigestArchive))
+	mux.HandleFunc("/api/capture", s.authMiddleware(s.handleCapture))
+	mux.HandleFunc("/api/ask", s.authMiddleware(s.handleAsk))
+	mux.HandleFunc("/api/search", s.authMiddleware(s.handleSearch))
+	mux.HandleFunc("/api/focus", s.authMiddleware(s.handleFocus))
+	mux.HandleFunc("/api/work", s.authMiddleware(s.handleWork))
+	mux.HandleFunc("/api/work/interrupt", s.authMiddleware(s.handleWorkInterrupt))
+	mux.HandleFunc("/api/work/stats", s.authMiddleware(s.handleWorkStats))
+	mux.HandleFunc("/api/time/export", s.authMiddleware(s.handleTimeExport))
+	mux.HandleFunc("/api/timeblocks", s.authMiddleware(s.handleTimeBlocks))
+	mux.HandleFunc("/api/timeblocks/commit", s.authMiddleware(s.handleCommitDayPlan))
+	mux.HandleFunc("/api/timeblocks/", s.authMiddleware(s.handleTimeBlockAction))
+	mux.HandleFunc("/api/events", s.authMiddleware(s.handleEvents))
+	mux.HandleFunc("/api/chat/messages", s.authMiddleware(s.handleChatMessages))
+	mux.HandleFunc("/api/tui/session", s.authMiddleware(s.handleTUISession))
+	mux.HandleFunc("/api/presence", s.authMiddleware(s.handlePresence))
+	mux.HandleFunc("/api/delegate/tasks", s.delegateAuthMiddleware(s.handleDelegateTasks))
+	mux.HandleFunc("/api/delegate/tasks/", s.delegateAuthMiddleware(s.handleDelegateTaskAction))
+	mux.HandleFunc("/api/analytics/query", s.analyticsAuthMiddleware(s.handleAnalyticsQuery))
+	mux.HandleFunc("/api/analytics/tables", s.analyticsAuthMiddleware(s.handleAnalyticsTables))
+	if s.config.Chat.App.Enabled {
+		mux.HandleFunc("/api/chat/events", s.handleChatEvent)
+	}
+	if s.config.Front.Enabled && s.config.Front.WebhookSecret != "" {
+		mux.HandleFunc("/api/front/webhook", s.handleFrontWebhook)
+	}
+	if s.config.CalDAV.Enabled {
+		// Signs in with an API key itself, since task apps only speak Basic auth
+		mux.HandleFunc(caldavRoot, s.handleCalDAV)
+		mux.Handle("/.well-known/caldav", http.RedirectHandler(caldavRoot, http.StatusMovedPermanently))
+	}
+	if s.config.CalendarFeed.Enabled {
+		mux.HandleFunc("/api/calendar.ics", s.calendarFeedAuthMiddleware(s.handleCalendarFeed))
+	}
+	if s.config.Metrics.Enabled {
+		mux.HandleFunc("/metrics", s.metricsAuthMiddleware(s.handleMetrics))
+	}
 	mux.HandleFunc("/health", s.handleHealth)
 
 	s.server = &http.Server{
 		Addr:         fmt.Sprintf(":%d", port),
-		Handler:      s.corsMiddleware(mux),
+		Handler:      s.securityHeadersMiddleware(s.corsMiddleware(mux)),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
 
+	if s.config.API.TLSEnabled() {
+		log.Printf("API server starting on port %d (HTTPS)", port)
+		return s.server.ListenAndServeTLS(s.config.API.TLSCert, s.config.API.TLSKey)
+	}
 	log.Printf("API server starting on port %d", port)
 	return s.server.ListenAndServe()
 }
@@ -101,7 +181,7 @@ func (s *Server) authMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		}
 
 		token := parts[1]
-		if token != s.config.API.AuthKey {
+		if !s.isOwnerToken(token) {
 			http.Error(w, "Invalid token", http.StatusUnauthorized)
 			return
 		}
@@ -110,6 +190,82 @@ func (s *Server) authMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// isOwnerToken reports whether a token grants full API access: api.auth_key, or an unrevoked
+// per-client key from -api-key-create
+func (s *Server) isOwnerToken(token string) bool {
+	if token == "" {
+		return false
+	}
+	if tokenMatches(token, s.config.API.AuthKey) {
+		return true
+	}
+	apiKey, err := s.database.ValidateAPIKey(token)
+	if err != nil {
+		log.Printf("Failed to check API key: %v", err)
+		return false
+	}
+	return apiKey != nil
+}
+
+// tokenMatches compares a presented token to a configured one in constant time. An unset
+// token matches nothing.
+func tokenMatches(token, configured string) bool {
+	return configured != "" && subtle.ConstantTimeCompare([]byte(token), []byte(configured)) == 1
+}
+
+// analyticsAuthMiddleware accepts the dashboard's analytics token as well as the API key.
+// The analytics token is only ever checked here, so it can't be used on other endpoints.
+func (s *Server) analyticsAuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || token == r.Header.Get("Authorization") {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if !s.isOwnerToken(token) && !tokenMatches(token, s.config.Analytics.Token) {
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// metricsAuthMiddleware accepts the scraper's metrics token as well as the API key. Like the
+// analytics token, the metrics token is only checked here.
+func (s *Server) metricsAuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || token == r.Header.Get("Authorization") {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if !s.isOwnerToken(token) && !tokenMatches(token, s.config.Metrics.Token) {
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// contentSecurityPolicy lets nothing load or run. The API serves JSON and iCalendar, never
+// pages, so a response opened in a browser, e.g. a summary with injected HTML, stays inert.
+const contentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'"
+
+// securityHeadersMiddleware stops browsers from running, sniffing or framing API responses
+func (s *Server) securityHeadersMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", contentSecurityPolicy)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("X-Frame-Options", "DENY")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		next.ServeHTTP(w, r)
+	})
+}
+
 // CORS middleware for development
 func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -117,7 +273,8 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
-		if r.Method == "OPTIONS" {
+		// CalDAV discovery needs its own OPTIONS reply
+		if r.Method == "OPTIONS" && !strings.HasPrefix(r.URL.Path, caldavRoot) {
 			w.WriteHeader(http.StatusOK)
 			return
 		}
@@ -128,11 +285,34 @@ func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 
 // Health check endpoint (no auth required)
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{
+	health := map[string]interface{}{
 		"status": "healthy",
 		"time":   time.Now().Format(time.RFC3339),
-	})
+	}
+
+	// A Google token that can't be refreshed stops every sync; on a headless server this is
+	// often the first sign that -auth needs running again
+	if s.clients != nil {
+		token := s.clients.TokenHealth()
+		health["google_token"] = token
+		if !token.Healthy {
+			health["status"] = "degraded"
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(health)
+}
+
+// GET /metrics - Prometheus metrics for sync, LLM, cache, extraction and scheduler activity
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", metrics.ContentType)
+	metrics.WriteText(w)
 }
 
 // Helper to write JSON responses
@@ -146,8 +326,3 @@ func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 func writeError(w http.ResponseWriter, status int, message string) {
 	writeJSON(w, status, map[string]string{"error": message})
 }
-
-// saveFeedback saves priority feedback to the database
-func (s *Server) saveFeedback(taskID string, vote int, reason string, originalScore, adjustedScore float64) error {
-	return scoring.SaveFeedback(s.database, taskID, vote, reason, originalScore, adjustedScore)
-}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/google"
+	"github.com/alexrabarts/focus-agent/internal/llm"
+)
+
+// minSyncGrace is how overdue a sync may be before it's reported stale, for services that poll
+// more often than this
+const minSyncGrace = 5 * time.Minute
+
+// SyncStatus is when a service last synced successfully and whether it has fallen behind
+type SyncStatus struct {
+	Service    string    `json:"service"`
+	LastSync   time.Time `json:"last_sync"`
+	NextSync   time.Time `json:"next_sync"`
+	Stale      bool      `json:"stale"` // Missed at least a full polling interval
+	ErrorCount int       `json:"error_count,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+// StatusResponse is the state of each subsystem, for uptime monitors
+type StatusResponse struct {
+	Status      string                `json:"status"`             // ok or degraded
+	Problems    []string              `json:"problems,omitempty"` // Why the status is degraded
+	Time        time.Time             `json:"time"`
+	Sync        []*SyncStatus         `json:"sync"`
+	NextRuns    map[string]time.Time  `json:"next_runs,omitempty"` // Scheduler jobs
+	Guards      []string              `json:"guards,omitempty"`    // Resources near their limit, pausing low-priority work
+	LLM         []*llm.ProviderHealth `json:"llm"`
+	GoogleToken *google.TokenHealth   `json:"google_token,omitempty"`
+	Database    *db.DatabaseStats     `json:"database,omitempty"`
+	OpenIssues  int                   `json:"open_issues"`
+}
+
+// GET /api/status - Per-subsystem health: sync freshness, scheduler, resource guards, LLM
+// providers, Google token and database. Answers 503 when anything is wrong, so monitors can alert on the code.
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	now := time.Now()
+	status := &StatusResponse{Status: "ok", Time: now, Sync: []*SyncStatus{}}
+	problem := func(format string, args ...interface{}) {
+		status.Problems = append(status.Problems, fmt.Sprintf(format, args...))
+	}
+
+	states, err := s.database.GetSyncStates()
+	if err != nil {
+		problem("sync state unavailable: %v", err)
+	}
+	for _, state := range states {
+		service := &SyncStatus{
+			Service:    state.Service,
+			LastSync:   state.LastSync,
+			NextSync:   state.NextSync,
+			ErrorCount: state.ErrorCount,
+			LastError:  state.LastError,
+		}
+		// Overdue by a whole polling interval means at least one run failed or never happened
+		grace := max(state.NextSync.Sub(state.LastSync), minSyncGrace)
+		if state.NextSync.Unix() > 0 && now.Sub(state.NextSync) > grace {
+			service.Stale = true
+			problem("%s has not synced since %s", state.Service, state.LastSync.Format(time.RFC3339))
+		}
+		status.Sync = append(status.Sync, service)
+	}
+
+	if s.scheduler != nil {
+		status.NextRuns = s.scheduler.GetNextRuns()
+		status.Guards = s.scheduler.GuardWarnings()
+		for _, warning := range status.Guards {
+			problem("%s", warning)
+		}
+	}
+
+	status.LLM = s.llm.ProviderHealth(r.Context())
+	usable := false
+	for _, provider := range status.LLM {
+		if provider.Available && !provider.Exhausted {
+			usable = true
+		}
+	}
+	if !usable {
+		problem("no LLM provider is available")
+	}
+
+	if s.clients != nil {
+		token := s.clients.TokenHealth()
+		status.GoogleToken = token
+		if !token.Healthy {
+			problem("Google token: %s", token.Error)
+		}
+	}
+
+	if status.Database, err = s.database.GetDatabaseStats(); err != nil {
+		problem("database stats unavailable: %v", err)
+	}
+
+	if issues, err := s.database.GetOpenIssues(); err == nil {
+		status.OpenIssues = len(issues)
+	}
+
+	code := http.StatusOK
+	if len(status.Problems) > 0 {
+		status.Status = "degraded"
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, status)
+}
@@ -0,0 +1,240 @@
+package api
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// iCalendar date-time formats accepted in DUE and written out
+const (
+	icalUTC   = "20060102T150405Z"
+	icalLocal = "20060102T150405"
+	icalDate  = "20060102"
+)
+
+// vtodo is the part of a VTODO a task app can change
+type vtodo struct {
+	UID         string
+	Summary     string
+	Description string
+	Due         *time.Time
+	Status      string // NEEDS-ACTION, IN-PROCESS, COMPLETED or CANCELLED
+	Priority    int    // 1 (highest) to 9, 0 for undefined
+}
+
+// renderVTODO writes a task as an iCalendar object with a single VTODO
+func renderVTODO(task *db.Task) string {
+	var b strings.Builder
+	line := func(name, value string) {
+		b.WriteString(foldICalLine(name + ":" + value))
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//focus-agent//CalDAV//EN")
+	line("BEGIN", "VTODO")
+	line("UID", taskUID(task))
+	line("DTSTAMP", task.UpdatedAt.UTC().Format(icalUTC))
+	line("CREATED", task.CreatedAt.UTC().Format(icalUTC))
+	line("LAST-MODIFIED", task.UpdatedAt.UTC().Format(icalUTC))
+	line("SUMMARY", escapeICalText(task.Title))
+	if task.Description != "" {
+		line("DESCRIPTION", escapeICalText(task.Description))
+	}
+	if task.DueTS != nil {
+		line("DUE", task.DueTS.UTC().Format(icalUTC))
+	}
+	line("PRIORITY", strconv.Itoa(impactToPriority(task.Impact)))
+	switch task.Status {
+	case "completed":
+		line("STATUS", "COMPLETED")
+		line("PERCENT-COMPLETE", "100")
+		if task.CompletedAt != nil {
+			line("COMPLETED", task.CompletedAt.UTC().Format(icalUTC))
+		}
+	case "in_progress":
+		line("STATUS", "IN-PROCESS")
+	default:
+		line("STATUS", "NEEDS-ACTION")
+	}
+
+	var categories []string
+	if task.Project != "" {
+		categories = append(categories, escapeICalText(task.Project))
+	}
+	for _, tag := range task.Tags {
+		categories = append(categories, escapeICalText(tag))
+	}
+	if len(categories) > 0 {
+		line("CATEGORIES", strings.Join(categories, ","))
+	}
+	line("END", "VTODO")
+	line("END", "VCALENDAR")
+	return b.String()
+}
+
+// taskETag changes whenever anything rendered into a task's VTODO does
+func taskETag(task *db.Task) string {
+	sum := sha256.Sum256([]byte(renderVTODO(task)))
+	return fmt.Sprintf(`"%x"`, sum[:8])
+}
+
+// parseVTODO reads the first VTODO in an iCalendar object
+func parseVTODO(data string) (*vtodo, error) {
+	todo := &vtodo{}
+	inTodo := false
+	found := false
+	nested := 0 // Depth of components inside the VTODO, such as VALARM
+
+	for _, contentLine := range unfoldICalLines(data) {
+		name, params, value := splitICalLine(contentLine)
+		switch {
+		case name == "BEGIN" && value == "VTODO" && !found:
+			inTodo = true
+			found = true
+			continue
+		case !inTodo:
+			continue
+		case name == "BEGIN":
+			nested++
+			continue
+		case name == "END" && nested > 0:
+			nested--
+			continue
+		case name == "END" && value == "VTODO":
+			inTodo = false
+			continue
+		case nested > 0:
+			// Nested components share property names, e.g. a VALARM's DESCRIPTION
+			continue
+		}
+
+		switch name {
+		case "UID":
+			todo.UID = value
+		case "SUMMARY":
+			todo.Summary = unescapeICalText(value)
+		case "DESCRIPTION":
+			todo.Description = unescapeICalText(value)
+		case "STATUS":
+			todo.Status = strings.ToUpper(value)
+		case "PRIORITY":
+			todo.Priority, _ = strconv.Atoi(value)
+		case "DUE":
+			due, err := parseICalTime(value, params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DUE %q: %w", value, err)
+			}
+			todo.Due = &due
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no VTODO in calendar data")
+	}
+	return todo, nil
+}
+
+// unfoldICalLines splits iCalendar data into content lines, joining folded continuations
+func unfoldICalLines(data string) []string {
+	var lines []string
+	for _, raw := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(raw, " ") || strings.HasPrefix(raw, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += raw[1:]
+			continue
+		}
+		if raw != "" {
+			lines = append(lines, raw)
+		}
+	}
+	return lines
+}
+
+// splitICalLine splits a content line into its upper-cased name, parameters and value
+func splitICalLine(line string) (string, map[string]string, string) {
+	colon := strings.Index(line, ":")
+	if colon < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+	head, value := line[:colon], line[colon+1:]
+
+	parts := strings.Split(head, ";")
+	params := make(map[string]string)
+	for _, param := range parts[1:] {
+		if key, val, ok := strings.Cut(param, "="); ok {
+			params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// parseICalTime reads a DATE or DATE-TIME value, in UTC, its TZID or local time. Dates are due
+// at the end of the day, like tasks with only a due date elsewhere in the agent.
+func parseICalTime(value string, params map[string]string) (time.Time, error) {
+	loc := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		return time.Parse(icalUTC, value)
+	case params["VALUE"] == "DATE" || len(value) == len(icalDate):
+		day, err := time.ParseInLocation(icalDate, value, loc)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return day.Add(17 * time.Hour), nil
+	default:
+		return time.ParseInLocation(icalLocal, value, loc)
+	}
+}
+
+// impactToPriority maps impact 1-5 to iCalendar PRIORITY, where 1 is highest and 9 lowest
+func impactToPriority(impact int) int {
+	if impact < 1 || impact > 5 {
+		return 0
+	}
+	return 11 - 2*impact
+}
+
+// priorityToImpact maps an iCalendar PRIORITY back to impact, or 0 if it's undefined
+func priorityToImpact(priority int) int {
+	if priority < 1 || priority > 9 {
+		return 0
+	}
+	return (11 - priority) / 2
+}
+
+func escapeICalText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+func unescapeICalText(text string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(text)
+}
+
+// foldICalLine ends a content line with CRLF, folding it at 75 octets without splitting a
+// UTF-8 character
+func foldICalLine(line string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
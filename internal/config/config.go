@@ -1,31 +1,76 @@
 package config
 
 import (
+	"crypto/sha256"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+
 type Config struct {
-	Database   Database   `yaml:"database"`
-	Google     Google     `yaml:"google"`
-	Gemini     Gemini     `yaml:"gemini"`
-	Ollama     Ollama     `yaml:"ollama"`
-	Chat       Chat       `yaml:"chat"`
-	API        API        `yaml:"api"`
-	Remote     Remote     `yaml:"remote"`
-	TUI        TUI        `yaml:"tui"`
-	Schedule   Schedule   `yaml:"schedule"`
-	Planner    Planner    `yaml:"planner"`
-	Limits     Limits     `yaml:"limits"`
-	Priorities Priorities `yaml:"priorities"`
-	Front      Front      `yaml:"front"`
+	Database     Database     `yaml:"database"`
+	Google       Google       `yaml:"google"`
+	MSGraph      MSGraph      `yaml:"msgraph"`
+	Gemini       Gemini       `yaml:"gemini"`
+	Ollama       Ollama       `yaml:"ollama"`
+	LLM          LLM          `yaml:"llm"`
+	Chat         Chat         `yaml:"chat"`
+	Slack        Slack        `yaml:"slack"`
+	Notify       Notify       `yaml:"notifications"`
+	Push         Push         `yaml:"push"`
+	Notion       Notion       `yaml:"notion"`
+	API          API          `yaml:"api"`
+	Analytics    Analytics    `yaml:"analytics"`
+	Delegate     Delegate     `yaml:"delegate"`
+	CalDAV       CalDAV       `yaml:"caldav"`
+	CalendarFeed CalendarFeed `yaml:"calendar_feed"`
+	Metrics      Metrics      `yaml:"metrics"`
+	Drafting     Drafting     `yaml:"drafting"`
+	STT          STT          `yaml:"stt"`
+	Remote       Remote       `yaml:"remote"`
+	TUI          TUI          `yaml:"tui"`
+	Schedule     Schedule     `yaml:"schedule"`
+	Planner      Planner      `yaml:"planner"`
+	Dedup        Dedup        `yaml:"dedup"`
+	Search       Search       `yaml:"search"`
+	Summaries    Summaries    `yaml:"summaries"`
+	People       People       `yaml:"people"`
+	Meetings     Meetings     `yaml:"meetings"`
+	WaitingOn    WaitingOn    `yaml:"waiting_on"`
+	Commitments  Commitments  `yaml:"commitments"`
+	Escalation   Escalation   `yaml:"escalation"`
+	Focus        Focus        `yaml:"focus"`
+	Limits       Limits       `yaml:"limits"`
+	Priorities   Priorities   `yaml:"priorities"`
+	Front        Front        `yaml:"front"`
+	Guards       Guards       `yaml:"guards"`
+	Canary       Canary       `yaml:"canary"`
+
+	// Profile is the named profile the config was loaded for; empty when loaded from -config
+	Profile string `yaml:"-"`
 }
 
 type Database struct {
-	Path string `yaml:"path"`
+	Path        string `yaml:"path"`
+	SlowQueryMs int    `yaml:"slow_query_ms"` // Embedding/FTS queries slower than this are logged
+	BackupDir   string `yaml:"backup_dir"`    // Snapshots taken before migrations and destructive maintenance
+	BackupKeep  int    `yaml:"backup_keep"`   // Newest snapshots kept; older ones are removed
+
+	// Copy the database into backup_dir every night, keeping the newest nightly_backup_keep copies
+	NightlyBackup     bool `yaml:"nightly_backup"`
+	NightlyBackupKeep int  `yaml:"nightly_backup_keep"`
+
+	// Every night, move the bodies of messages older than cold_storage_days into compressed
+	// parquet files in cold_storage_dir; 0 keeps every body in the database
+	ColdStorageDays int    `yaml:"cold_storage_days"`
+	ColdStorageDir  string `yaml:"cold_storage_dir"`
 }
 
 type Google struct {
@@ -33,6 +78,7 @@ type Google struct {
 	ClientSecret   string   `yaml:"client_secret"`
 	RedirectURL    string   `yaml:"redirect_url"`
 	TokenFile      string   `yaml:"token_file"`
+	AuthFlow       string   `yaml:"auth_flow"` // browser, manual or device; how -auth gets the first token
 	Scopes         []string `yaml:"scopes"`
 	UserEmail      string   `yaml:"user_email,omitempty"` // Populated from Gmail profile
 	PollingMinutes struct {
@@ -40,11 +86,70 @@ type Google struct {
 		Drive    int `yaml:"drive"`
 		Calendar int `yaml:"calendar"`
 		Tasks    int `yaml:"tasks"`
+		Capture  int `yaml:"capture"`
+		Chat     int `yaml:"chat"`
+	} `yaml:"polling_minutes"`
+	PriorityLabel PriorityLabel `yaml:"priority_label"`
+	Attachments   Attachments   `yaml:"attachments"`
+	Capture       Capture       `yaml:"capture"`
+}
+
+// Ways of getting the first Google token
+const (
+	AuthFlowBrowser = "browser" // Open the consent page and catch the redirect on localhost:8080
+	AuthFlowManual  = "manual"  // Print the consent link and paste back the URL it redirects to
+	AuthFlowDevice  = "device"  // Enter a code at google.com/device; needs a "TVs and Limited Input" client
+)
+
+// AuthFlows lists the valid google.auth_flow values
+var AuthFlows = []string{AuthFlowBrowser, AuthFlowManual, AuthFlowDevice}
+
+// PriorityLabel labels Gmail threads the agent scores highly, so Gmail's own notifications
+// and filters can follow the agent's judgment. Requires the gmail.modify scope.
+type PriorityLabel struct {
+	Enabled   bool    `yaml:"enabled"`
+	Name      string  `yaml:"name"`      // Gmail label, nested with "/"
+	Threshold float64 `yaml:"threshold"` // Thread priority score (0-100) at or above which the label is applied
+}
+
+// Capture turns quick notes into tasks: Google Keep notes, and paragraphs of a Google Doc used as
+// an inbox. Each note is only processed once. The Keep API is only available to Workspace
+// accounts and can't label or archive notes, so processed notes can be moved to the trash instead.
+type Capture struct {
+	Keep           bool   `yaml:"keep"`            // Capture Google Keep notes; adds the keep.readonly scope
+	TrashProcessed bool   `yaml:"trash_processed"` // Move processed Keep notes to the trash; needs the keep scope
+	InboxDocID     string `yaml:"inbox_doc_id"`    // Google Doc whose paragraphs are captured as notes
+}
+
+// Attachments extracts text from PDFs, Word documents and linked Google Docs in synced mail,
+// for thread summaries and task enrichment. Linked Docs need the drive.readonly scope.
+type Attachments struct {
+	Enabled   bool   `yaml:"enabled"`
+	MaxSizeMB int    `yaml:"max_size_mb"` // Larger attachments aren't downloaded
+	PDFToText string `yaml:"pdftotext"`   // pdftotext binary (poppler-utils) used for PDFs
+}
+
+// MSGraph configures Outlook / Microsoft 365 mail and calendar sync via Microsoft Graph.
+// Synced mail and events go into the same tables as Gmail and Google Calendar.
+type MSGraph struct {
+	Enabled        bool     `yaml:"enabled"`
+	TenantID       string   `yaml:"tenant_id"` // Azure AD tenant, or "common" for any account
+	ClientID       string   `yaml:"client_id"`
+	ClientSecret   string   `yaml:"client_secret"`
+	RedirectURL    string   `yaml:"redirect_url"`
+	TokenFile      string   `yaml:"token_file"`
+	Scopes         []string `yaml:"scopes"`
+	PollingMinutes struct {
+		Mail     int `yaml:"mail"`
+		Calendar int `yaml:"calendar"`
 	} `yaml:"polling_minutes"`
 }
 
 type Gemini struct {
 	APIKey           string         `yaml:"api_key"`
+	APIKeys          []string       `yaml:"api_keys"`           // More keys, each with its own daily quota
+	KeyStrategy      string         `yaml:"key_strategy"`       // "failover" (default) or "round_robin"
+	KeyDailyRequests int            `yaml:"key_daily_requests"` // Requests per key per day (0 = until Gemini reports the quota used up)
 	Model            string         `yaml:"model"`
 	MaxTokens        int            `yaml:"max_tokens"`
 	Temperature      float32        `yaml:"temperature"`
@@ -54,6 +159,30 @@ type Gemini struct {
 	RetryOnRateLimit bool           `yaml:"retry_on_rate_limit"`
 	MaxRetries       int            `yaml:"max_retries"`
 	BaseRetryDelay   int            `yaml:"base_retry_delay_seconds"`
+	ProBudget        ProBudget      `yaml:"pro_budget"`
+}
+
+// Keys returns the configured API keys in order, api_key first, without blanks or duplicates
+func (g Gemini) Keys() []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, key := range append([]string{g.APIKey}, g.APIKeys...) {
+		key = strings.TrimSpace(key)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// ProBudget spreads the daily allocation of Pro-model calls across the working day
+type ProBudget struct {
+	DailyCalls     int    `yaml:"daily_calls"`     // Pro calls per day (0 = no budget, first come first served)
+	StartTime      string `yaml:"start_time"`      // Allocation starts accruing at "08:00"
+	EndTime        string `yaml:"end_time"`        // and is fully released by "18:00"
+	ReservePercent int    `yaml:"reserve_percent"` // Share only key-stakeholder threads may use
 }
 
 type OllamaHost struct {
@@ -64,49 +193,698 @@ type OllamaHost struct {
 }
 
 type Ollama struct {
-	Hosts          []OllamaHost `yaml:"hosts"`           // Multiple Ollama hosts with worker counts
-	Model          string       `yaml:"model"`           // e.g., "qwen2.5:7b"
-	Enabled        bool         `yaml:"enabled"`         // Enable/disable Ollama
-	TimeoutSeconds int          `yaml:"timeout_seconds"` // Request timeout per request
+	Hosts            []OllamaHost `yaml:"hosts"`              // Multiple Ollama hosts with worker counts
+	Model            string       `yaml:"model"`              // e.g., "qwen2.5:7b"
+	Enabled          bool         `yaml:"enabled"`            // Enable/disable Ollama
+	TimeoutSeconds   int          `yaml:"timeout_seconds"`    // Request timeout per request
+	KeepAlive        string       `yaml:"keep_alive"`         // How long hosts keep the model loaded after a request, e.g. "30m" ("" = Ollama's default, "-1" = forever)
+	Warmup           bool         `yaml:"warmup"`             // Load the model on every host before each processing run
+	KeepAliveMinutes int          `yaml:"keep_alive_minutes"` // Preload the model this often so it never goes cold (0 = off)
+}
+
+// LLM provider names accepted in llm.provider_order
+const (
+	ProviderOllama = "ollama"
+	ProviderClaude = "claude"
+	ProviderGemini = "gemini"
+)
+
+type LLM struct {
+	ProviderOrder []string                  `yaml:"provider_order"` // Fallback chain, first entry is tried first
+	Budgets       map[string]ProviderBudget `yaml:"budgets"`        // Daily caps keyed by provider name
+	PromptsDir    string                    `yaml:"prompts_dir"`    // Prompt templates that replace the built-in prompts, reloaded when changed
+	Pricing       map[string]ModelPrice     `yaml:"pricing"`        // Per-model prices, merged over DefaultModelPricing
+}
+
+// ModelPrice is what a model costs, in USD per million tokens
+type ModelPrice struct {
+	Input  float64 `yaml:"input"`
+	Output float64 `yaml:"output"`
+}
+
+// DefaultModelPricing holds list prices for the models focus-agent uses, keyed by model name
+// prefix. Local Ollama models aren't listed, so they cost nothing unless priced in config.
+var DefaultModelPricing = map[string]ModelPrice{
+	"gemini-2.5-pro":        {Input: 1.25, Output: 10},
+	"gemini-2.5-flash":      {Input: 0.30, Output: 2.50},
+	"gemini-2.5-flash-lite": {Input: 0.10, Output: 0.40},
+	"gemini-2.0-flash":      {Input: 0.10, Output: 0.40},
+	"gemini-1.5-pro":        {Input: 1.25, Output: 5},
+	"gemini-1.5-flash":      {Input: 0.075, Output: 0.30},
+	"gemini-embedding":      {Input: 0.15},
+	"claude-haiku":          {Input: 1, Output: 5},
+	"claude-3-5-haiku":      {Input: 0.80, Output: 4},
+	"claude-sonnet":         {Input: 3, Output: 15},
+	"claude-opus":           {Input: 15, Output: 75},
+}
+
+// Cost prices a call to a model from its input and output tokens. The longest configured name
+// prefix of the model wins, so "gemini-2.5-flash-lite" isn't priced as "gemini-2.5-flash".
+// Unknown models cost nothing.
+func (l LLM) Cost(model string, inputTokens, outputTokens int) float64 {
+	model = strings.ToLower(model)
+	var price ModelPrice
+	matched := -1
+	for name, p := range l.Pricing {
+		if strings.HasPrefix(model, name) && len(name) > matched {
+			price, matched = p, len(name)
+		}
+	}
+	return (float64(inputTokens)*price.Input + float64(outputTokens)*price.Output) / 1_000_000
+}
+
+// ProviderBudget caps how much a provider may be used per day. Once a cap is reached the
+// provider is skipped until local midnight.
+type ProviderBudget struct {
+	MaxTokens int     `yaml:"max_tokens"` // 0 means no token cap
+	MaxCost   float64 `yaml:"max_cost"`   // USD, 0 means no cost cap
 }
 
 type Chat struct {
-	WebhookURL string `yaml:"webhook_url"`
-	SpaceID    string `yaml:"space_id"`
-	ThreadKey  string `yaml:"thread_key"`
+	WebhookURL          string  `yaml:"webhook_url"`
+	SpaceID             string  `yaml:"space_id"`
+	ThreadKey           string  `yaml:"thread_key"`
+	RetryMinutes        int     `yaml:"retry_minutes"`         // How often queued messages are retried
+	MaxDeliveryAttempts int     `yaml:"max_delivery_attempts"` // Give up on a message after this many attempts
+	App                 ChatApp  `yaml:"app"`
+	Sync                ChatSync `yaml:"sync"`
+}
+
+// ChatSync reads messages from the listed Chat spaces, and optionally every DM the user is in,
+// and extracts tasks from them. Uses the chat.messages scope the agent already has.
+type ChatSync struct {
+	Enabled        bool     `yaml:"enabled"`
+	Spaces         []string `yaml:"spaces"`          // Space names (spaces/AAAA...) or IDs
+	DirectMessages bool     `yaml:"direct_messages"` // Also read every direct and group message the user is in
+	LookbackHours  int      `yaml:"lookback_hours"`  // How far back the first sync of a space reads
+}
+
+// ChatApp posts the daily brief as a card from a Chat app, with buttons to complete, snooze or
+// open each task. Button clicks are sent to /api/chat/events, so the API server must be
+// reachable from Google.
+type ChatApp struct {
+	Enabled         bool   `yaml:"enabled"`
+	CredentialsFile string `yaml:"credentials_file"` // Service account key the app posts as
+	Audience        string `yaml:"audience"`         // The app's HTTP endpoint URL, checked against each event's token
+}
+
+type Slack struct {
+	WebhookURL          string `yaml:"webhook_url"`           // Incoming webhook, posts to the webhook's channel
+	BotToken            string `yaml:"bot_token"`             // Bot token (xoxb-...), used instead of the webhook when set
+	Channel             string `yaml:"channel"`               // Channel ID, or user ID for a DM (bot token only)
+	MaxDeliveryAttempts int    `yaml:"max_delivery_attempts"` // Give up on a message after this many attempts
+}
+
+// Delivery channels accepted in notifications.channels
+const (
+	ChannelChat  = "chat"
+	ChannelSlack = "slack"
+	ChannelEmail = "email" // Sent from and to the user's own Gmail address
+	ChannelTUI   = "tui"   // A remote TUI with recent activity (notifications.follow_me.route only)
+	ChannelPush  = "push"  // Mobile push through ntfy (notifications.follow_me.route only)
+)
+
+// BriefKinds are the notification kinds notifications.briefs can route
+var BriefKinds = []string{
+	"daily_brief", "replan_brief", "end_of_day_brief", "weekly_review", "weekly_digest",
+	"follow_up", "focus_summary", "relationship_brief", "meeting_prep", "escalation", "guard_alert",
+	"canary_alert",
+}
+
+// EmailBriefKinds are the notification kinds that have an email format. Other kinds skip the
+// email channel.
+var EmailBriefKinds = []string{"daily_brief", "end_of_day_brief", "weekly_review"}
+
+type Notify struct {
+	Channels   []string            `yaml:"channels"` // Where briefs and reminders are delivered, e.g. [chat, slack]
+	Briefs     map[string][]string `yaml:"briefs"`   // Channels for one kind instead, e.g. daily_brief: [chat, email]
+	EmailTo    string              `yaml:"email_to"` // Address emailed briefs go to (default: google.user_email)
+	FollowMe   FollowMe            `yaml:"follow_me"`
+	QuietHours QuietHours          `yaml:"quiet_hours"`
+
+	// Channels whose messages are sent without emoji, for screen readers and clients that
+	// render them badly, e.g. [chat]
+	PlainFormatting []string `yaml:"plain_formatting"`
+}
+
+// FollowMe sends urgent alerts to the one place the user is likely to see them, instead of to
+// every channel
+type FollowMe struct {
+	Enabled         bool     `yaml:"enabled"`
+	Kinds           []string `yaml:"kinds"`            // Notification kinds treated as urgent
+	Route           []string `yaml:"route"`            // Tried in order until one delivers, e.g. [tui, chat, push]
+	PresenceSeconds int      `yaml:"presence_seconds"` // A TUI counts as present this long after its last keypress
+}
+
+// Routes reports whether a kind of notification is sent to one channel by presence
+func (f FollowMe) Routes(kind string) bool {
+	return f.Enabled && slices.Contains(f.Kinds, kind)
+}
+
+// QuietHours holds some kinds of Chat and Slack notification in the outbox overnight, and
+// optionally at weekends, until the next time they're allowed out
+type QuietHours struct {
+	Enabled  bool     `yaml:"enabled"`
+	Start    string   `yaml:"start"`    // HH:MM in schedule.timezone, e.g. "21:00"
+	End      string   `yaml:"end"`      // HH:MM the quiet ends, e.g. "08:00" (the next day if before start)
+	Weekends bool     `yaml:"weekends"` // Quiet all Saturday and Sunday too
+	Kinds    []string `yaml:"kinds"`    // Notification kinds held back
+}
+
+// Until returns when a notification of a kind sent at now may go out, and whether that's later
+// than now. now should be in schedule.timezone.
+func (q QuietHours) Until(kind string, now time.Time) (time.Time, bool) {
+	if !q.Enabled || !slices.Contains(q.Kinds, kind) || !q.quiet(now) {
+		return now, false
+	}
+
+	// Quiet ends at the end of a night, or at midnight after a weekend
+	end, _ := time.Parse("15:04", q.End)
+	for days := 0; days <= 8; days++ {
+		date := now.AddDate(0, 0, days)
+		midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, now.Location())
+		for _, candidate := range []time.Time{midnight, midnight.Add(time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute)} {
+			if candidate.After(now) && !q.quiet(candidate) {
+				return candidate, true
+			}
+		}
+	}
+	return now, false
+}
+
+// quiet reports whether a time falls in quiet hours
+func (q QuietHours) quiet(t time.Time) bool {
+	if q.Weekends && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
+		return true
+	}
+	start, startErr := time.Parse("15:04", q.Start)
+	end, endErr := time.Parse("15:04", q.End)
+	if startErr != nil || endErr != nil {
+		return false
+	}
+
+	clock := t.Hour()*60 + t.Minute()
+	from, to := start.Hour()*60+start.Minute(), end.Hour()*60+end.Minute()
+	if from <= to {
+		return clock >= from && clock < to
+	}
+	return clock >= from || clock < to
+}
+
+// Push sends mobile push notifications through an ntfy server (ntfy.sh or self-hosted)
+type Push struct {
+	URL   string `yaml:"url"`   // Topic URL, e.g. https://ntfy.sh/my-focus-agent
+	Token string `yaml:"token"` // Access token, for protected topics
+}
+
+// ChannelsFor returns the channels a kind of notification is delivered to
+func (n Notify) ChannelsFor(kind string) []string {
+	if channels, ok := n.Briefs[kind]; ok {
+		return channels
+	}
+	return n.Channels
+}
+
+// Plain reports whether messages to the named channel use plain formatting
+func (n Notify) Plain(channel string) bool {
+	return slices.Contains(n.PlainFormatting, channel)
+}
+
+// HasChannel reports whether any notification is delivered to the named channel
+func (n Notify) HasChannel(channel string) bool {
+	if slices.Contains(n.Channels, channel) {
+		return true
+	}
+	for _, channels := range n.Briefs {
+		if slices.Contains(channels, channel) {
+			return true
+		}
+	}
+	return n.FollowMe.Enabled && slices.Contains(n.FollowMe.Route, channel)
+}
+
+// Notion mirrors prioritized tasks and daily briefs into Notion databases, for teammates who
+// don't use the agent
+type Notion struct {
+	Enabled          bool   `yaml:"enabled"`
+	Token            string `yaml:"token"`              // Internal integration secret; share the databases with the integration
+	TasksDatabaseID  string `yaml:"tasks_database_id"`  // Database the top tasks are mirrored to
+	BriefsDatabaseID string `yaml:"briefs_database_id"` // Database each daily brief is added to as a page
+	MaxTasks         int    `yaml:"max_tasks"`          // Tasks mirrored, highest score first
+	IntervalMinutes  int    `yaml:"interval_minutes"`   // How often the tasks database is updated
 }
 
 type API struct {
 	Enabled bool   `yaml:"enabled"`
 	Port    int    `yaml:"port"`
 	AuthKey string `yaml:"auth_key"`
+	TLSCert string `yaml:"tls_cert"` // Serve HTTPS with this certificate and tls_key
+	TLSKey  string `yaml:"tls_key"`
+}
+
+// TLSEnabled reports whether the API server serves HTTPS
+func (a *API) TLSEnabled() bool {
+	return a.TLSCert != "" && a.TLSKey != ""
+}
+
+// Analytics restricts the SQL query interface used by dashboards and saved reports
+type Analytics struct {
+	Token          string   `yaml:"token"`           // Bearer token that only grants /api/analytics access
+	AllowedTables  []string `yaml:"allowed_tables"`  // Tables and views queries may read (default: analytics_* views)
+	MaxRows        int      `yaml:"max_rows"`        // Rows returned per query before truncating
+	TimeoutSeconds int      `yaml:"timeout_seconds"` // Statement timeout
+}
+
+// Delegate is a second trusted user, such as an assistant, who only sees and works tasks
+// carrying the delegation tag
+type Delegate struct {
+	Name  string `yaml:"name"`  // Shown on claims and comments, e.g. "Sam"
+	Token string `yaml:"token"` // Bearer token that only grants /api/delegate access
+	Tag   string `yaml:"tag"`   // Tag marking tasks the delegate can see
+}
+
+// CalDAV serves tasks to native task apps (iOS Reminders, Tasks.org, Thunderbird) as a VTODO
+// collection on the API server
+type CalDAV struct {
+	Enabled       bool   `yaml:"enabled"`
+	Name          string `yaml:"name"`           // List name shown in task apps
+	CompletedDays int    `yaml:"completed_days"` // How long completed tasks stay in the list
+}
+
+// CalendarFeed publishes task deadlines and focus blocks as an iCalendar feed at
+// /api/calendar.ics on the API server, for subscribing from any calendar app
+type CalendarFeed struct {
+	Enabled        bool   `yaml:"enabled"`
+	Name           string `yaml:"name"`            // Calendar name shown in calendar apps
+	Token          string `yaml:"token"`           // Only grants the feed; also accepted as ?token= for apps that can't send headers
+	PastDays       int    `yaml:"past_days"`       // How far back deadlines and focus blocks are kept
+	AheadDays      int    `yaml:"ahead_days"`      // How far ahead deadlines are included
+	RefreshMinutes int    `yaml:"refresh_minutes"` // How often calendar apps are asked to fetch the feed again
+}
+
+// Metrics exposes Prometheus metrics at /metrics on the API server
+type Metrics struct {
+	Enabled bool   `yaml:"enabled"`
+	Token   string `yaml:"token"` // Bearer token that only grants /metrics access, for the scraper
+}
+
+// Drafting configures the personas reply drafts are written in. A persona picked for a reply
+// is remembered as the default for its recipient.
+type Drafting struct {
+	DefaultPersona string             `yaml:"default_persona"` // Used when neither the request nor the recipient names one
+	Personas       map[string]Persona `yaml:"personas"`        // Keyed by name, merged over DefaultPersonas
+}
+
+// Persona is the tone and length of a reply draft
+type Persona struct {
+	Instructions string `yaml:"instructions"` // How the reply should read
+	MaxWords     int    `yaml:"max_words"`
+}
+
+// DefaultPersonas are the built-in drafting personas; config can override or add to them
+var DefaultPersonas = map[string]Persona{
+	"formal": {Instructions: "Polite and professional. Full sentences, a greeting and a sign-off, no slang.", MaxWords: 200},
+	"brief":  {Instructions: "As short as possible while still clear. No greeting or pleasantries, just the point.", MaxWords: 50},
+	"casual": {Instructions: "Friendly and relaxed, as to a colleague you know well. Contractions are fine.", MaxWords: 120},
+}
+
+// Persona returns the named drafting persona, and whether it exists. Names are case-insensitive.
+func (d Drafting) Persona(name string) (*Persona, bool) {
+	persona, ok := d.Personas[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, false
+	}
+	return &persona, true
+}
+
+// Speech-to-text backends accepted in stt.backend
+const (
+	STTWhisperCpp = "whisper_cpp"
+	STTOpenAI     = "openai"
+)
+
+// STT configures transcription for voice capture (POST /api/capture)
+type STT struct {
+	Backend       string `yaml:"backend"`        // whisper_cpp (local) or openai (cloud)
+	WhisperBinary string `yaml:"whisper_binary"` // whisper.cpp CLI, e.g. "whisper-cli"
+	WhisperModel  string `yaml:"whisper_model"`  // Path to a ggml model file
+	FFmpegBinary  string `yaml:"ffmpeg_binary"`  // Used to convert uploads to 16kHz WAV for whisper.cpp
+	APIURL        string `yaml:"api_url"`        // OpenAI-compatible transcription endpoint
+	APIKey        string `yaml:"api_key"`
+	Model         string `yaml:"model"` // Cloud model name, e.g. "whisper-1"
+	MaxUploadMB   int    `yaml:"max_upload_mb"`
 }
 
 type Remote struct {
-	URL     string `yaml:"url"`
-	AuthKey string `yaml:"auth_key"`
+	URL      string `yaml:"url"`
+	AuthKey  string `yaml:"auth_key"`
+	Delegate bool   `yaml:"delegate"` // auth_key is a delegate.token: show only delegated tasks
+	CACert   string `yaml:"ca_cert"`  // Trust this CA (e.g. a self-signed api.tls_cert) for an https url
 }
 
 type TUI struct {
-	AutoRefreshSeconds int `yaml:"auto_refresh_seconds"`
+	AutoRefreshSeconds int    `yaml:"auto_refresh_seconds"`
+	ClientID           string `yaml:"client_id"` // Key for this terminal's saved layout (default: hostname)
 }
 
 type Schedule struct {
-	DailyBriefTime  string `yaml:"daily_brief_time"` // "07:45"
-	ReplanTime      string `yaml:"replan_time"`      // "13:00"
-	FollowUpMinutes int    `yaml:"followup_minutes"` // 60
-	Timezone        string `yaml:"timezone"`         // "America/Los_Angeles"
+	DailyBriefTime   string `yaml:"daily_brief_time"`   // "07:45"
+	ReplanTime       string `yaml:"replan_time"`        // "13:00"
+	EndOfDayTime     string `yaml:"end_of_day_time"`    // "17:30" ("" = no end-of-day brief)
+	FollowUpMinutes  int    `yaml:"followup_minutes"`   // 60
+	Timezone         string `yaml:"timezone"`           // "America/Los_Angeles"
+	DigestDay        string `yaml:"digest_day"`         // "friday"
+	DigestTime       string `yaml:"digest_time"`        // "16:00"
+	WeeklyReviewDay  string `yaml:"weekly_review_day"`  // "friday"
+	WeeklyReviewTime string `yaml:"weekly_review_time"` // "17:00" ("" = no weekly review)
+
+	// override is the timezone switched to at runtime for travel. It's shared by copies of the
+	// Schedule and guarded, since the API changes it while the scheduler and LLM clients read it.
+	override *timezoneOverride
+}
+
+// timezoneOverride holds the timezone that replaces Schedule.Timezone while travelling
+type timezoneOverride struct {
+	mu   sync.RWMutex
+	name string
+}
+
+// ActiveTimezone returns the timezone in use: the one switched to for travel, if any, or
+// schedule.timezone
+func (s Schedule) ActiveTimezone() string {
+	if s.override != nil {
+		s.override.mu.RLock()
+		defer s.override.mu.RUnlock()
+		if s.override.name != "" {
+			return s.override.name
+		}
+	}
+	return s.Timezone
+}
+
+// SetActiveTimezone switches the timezone in use for travel; an empty name switches back to
+// schedule.timezone. The name isn't checked, so callers should load it first.
+func (s *Schedule) SetActiveTimezone(name string) {
+	if s.override == nil {
+		s.override = &timezoneOverride{}
+	}
+	if name == s.Timezone {
+		name = ""
+	}
+	s.override.mu.Lock()
+	s.override.name = name
+	s.override.mu.Unlock()
+}
+
+// Location returns the schedule's active time zone, or the local one if it isn't valid
+func (s Schedule) Location() *time.Location {
+	location, err := time.LoadLocation(s.ActiveTimezone())
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
+
+// Now returns the current time in the schedule's time zone
+func (s Schedule) Now() time.Time {
+	return time.Now().In(s.Location())
+}
+
+// Weekdays maps lowercase day names to their cron day-of-week number
+var Weekdays = map[string]int{
+	"sunday":    0,
+	"monday":    1,
+	"tuesday":   2,
+	"wednesday": 3,
+	"thursday":  4,
+	"friday":    5,
+	"saturday":  6,
+}
+
+// ScoringWeights weigh the factors of a task's score. Strategic alignment (0-5), urgency and
+// impact (1-5) and stakeholder (1-2) add to it and effort (0.5-1.5) takes away; the weighted sum
+// out of 4 is the score out of 100.
+type ScoringWeights struct {
+	Strategic   float64 `yaml:"strategic" json:"strategic"`
+	Urgency     float64 `yaml:"urgency" json:"urgency"`
+	Impact      float64 `yaml:"impact" json:"impact"`
+	Stakeholder float64 `yaml:"stakeholder" json:"stakeholder"`
+	Effort      float64 `yaml:"effort" json:"effort"`
+}
+
+// IsZero reports whether no weight is set
+func (w ScoringWeights) IsZero() bool {
+	return w == ScoringWeights{}
+}
+
+// String formats the weights as they appear in the scoring formula
+func (w ScoringWeights) String() string {
+	return fmt.Sprintf("%g×strategic + %g×urgency + %g×impact + %g×stakeholder - %g×effort",
+		w.Strategic, w.Urgency, w.Impact, w.Stakeholder, w.Effort)
+}
+
+// Scoring sets the weights tasks are scored with. Profile picks one of Profiles in place of
+// Weights, and can be switched at runtime from the TUI or API.
+type Scoring struct {
+	Weights  ScoringWeights            `yaml:"weights"`
+	Profile  string                    `yaml:"profile"`
+	Profiles map[string]ScoringWeights `yaml:"profiles"`
+}
+
+// DefaultScoringProfile names the base planner.scoring.weights as a profile
+const DefaultScoringProfile = "default"
+
+// ProfileWeights returns the weights of a profile, or the base weights for an empty name or
+// DefaultScoringProfile
+func (s Scoring) ProfileWeights(name string) (ScoringWeights, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == DefaultScoringProfile {
+		return s.Weights, true
+	}
+	w, ok := s.Profiles[name]
+	return w, ok
+}
+
+// validate checks the weights are usable: none negative and at least one factor that adds to
+// the score
+func (w ScoringWeights) validate() error {
+	if w.Strategic < 0 || w.Urgency < 0 || w.Impact < 0 || w.Stakeholder < 0 || w.Effort < 0 {
+		return fmt.Errorf("weights must not be negative (effort is subtracted from the score)")
+	}
+	if w.Strategic+w.Urgency+w.Impact+w.Stakeholder == 0 {
+		return fmt.Errorf("at least one of strategic, urgency, impact or stakeholder must be weighted")
+	}
+	return nil
+}
+
+// ProfileNames returns DefaultScoringProfile followed by the named scoring profiles in
+// alphabetical order
+func (s Scoring) ProfileNames() []string {
+	names := make([]string, 0, len(s.Profiles))
+	for name := range s.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return append([]string{DefaultScoringProfile}, names...)
+}
+
+// DefaultScoringWeights are the weights tasks are scored with unless planner.scoring.weights
+// says otherwise
+var DefaultScoringWeights = ScoringWeights{Strategic: 0.3, Urgency: 0.25, Impact: 0.2, Stakeholder: 0.15, Effort: 0.1}
+
+// DefaultScoringProfiles are the scoring profiles available without configuring any
+var DefaultScoringProfiles = map[string]ScoringWeights{
+	"deadline_crunch": {Strategic: 0.15, Urgency: 0.45, Impact: 0.2, Stakeholder: 0.15, Effort: 0.15},
+	"strategic":       {Strategic: 0.45, Urgency: 0.15, Impact: 0.25, Stakeholder: 0.1, Effort: 0.05},
 }
 
 type Planner struct {
-	Weights struct {
-		Impact      float64 `yaml:"impact"`
-		Urgency     float64 `yaml:"urgency"`
-		Stakeholder float64 `yaml:"stakeholder"`
-		Effort      float64 `yaml:"effort"`
-	} `yaml:"weights"`
-	MaxTasksPerBrief int `yaml:"max_tasks_per_brief"`
+	Scoring          Scoring `yaml:"scoring"`
+	MaxTasksPerBrief int     `yaml:"max_tasks_per_brief"`
 	FocusBlockHours  int `yaml:"focus_block_hours"`
+
+	// Tasks and threads scoring below this threshold (0-100) skip the daily brief
+	// and are collected into the weekly "everything else" digest instead
+	DigestScoreThreshold float64 `yaml:"digest_score_threshold"`
+
+	// Top pending tasks re-evaluated against the new priorities right after they're edited,
+	// so the task list catches up before the next scheduled rescore
+	AlignmentWarmTasks int `yaml:"alignment_warm_tasks"`
+
+	// Tasks evaluated per LLM call when rescoring strategic alignment
+	AlignmentBatchSize int `yaml:"alignment_batch_size"`
+
+	// Embedding similarity between a task and its closest priority (0-1) below which the task is
+	// scored as unaligned, and above which as aligned, without asking the LLM. Tasks in between
+	// go to the LLM. Negative turns that side of the pre-filter off.
+	AlignmentSimilarityLow  float64 `yaml:"alignment_similarity_low"`
+	AlignmentSimilarityHigh float64 `yaml:"alignment_similarity_high"`
+
+	// Projects with open tasks but no new tasks, completions or thread activity for this many
+	// weeks are suggested for closing in the month's last weekly review
+	StaleProjectWeeks int `yaml:"stale_project_weeks"`
+
+	// Most points (0-100) priority feedback can add to or take off a task's score, learned from
+	// votes on tasks from the same project and sender; negative turns the boost off
+	FeedbackMaxBoost float64 `yaml:"feedback_max_boost"`
+
+	// Days a pending task from each source (as stored in tasks.source, e.g. "calendar") may go
+	// untouched before it's archived automatically. Sources not listed are never archived.
+	AutoArchiveDays map[string]int `yaml:"auto_archive_days"`
+
+	EffortCalibration EffortCalibration `yaml:"effort_calibration"`
+	TimeBlocking      TimeBlocking      `yaml:"time_blocking"`
+	CalendarChecks    CalendarChecks    `yaml:"calendar_checks"`
+	DocUpdates        DocUpdates        `yaml:"doc_updates"`
+}
+
+// EffortCalibration learns how long tasks from each project and stakeholder really take from
+// their completion history, and scales the effort factor in scoring to match
+type EffortCalibration struct {
+	Enabled       bool    `yaml:"enabled"`
+	LookbackDays  int     `yaml:"lookback_days"`  // Only tasks completed this recently are learned from
+	MinSamples    int     `yaml:"min_samples"`    // Completed tasks a project or stakeholder needs before it's calibrated
+	MaxFactor     float64 `yaml:"max_factor"`     // Most a task's effort is scaled up, or 1/this down
+	IntervalHours int     `yaml:"interval_hours"` // Hours between recalibrations
+}
+
+// Time blocking modes accepted in planner.time_blocking.mode
+const (
+	TimeBlockingAuto    = "auto"    // Write focus blocks to the calendar as soon as they're planned
+	TimeBlockingConfirm = "confirm" // Propose focus blocks and wait for confirmation in the TUI
+)
+
+// TimeBlocking writes the daily plan's focus blocks to Google Calendar as tentative events.
+// Requires the calendar.events scope.
+type TimeBlocking struct {
+	Enabled    bool   `yaml:"enabled"`
+	Mode       string `yaml:"mode"`        // auto or confirm
+	MinMinutes int    `yaml:"min_minutes"` // Shortest block worth scheduling around existing events
+}
+
+// CalendarChecks warns in the daily brief about double-booked meetings, long runs of
+// back-to-back meetings and days with too little focus time, and suggests meetings to decline
+type CalendarChecks struct {
+	Enabled       bool    `yaml:"enabled"`
+	WorkdayStart  string  `yaml:"workday_start"`   // HH:MM; focus time is only counted within the workday
+	WorkdayEnd    string  `yaml:"workday_end"`     // HH:MM
+	MaxChainHours float64 `yaml:"max_chain_hours"` // Back-to-back meetings running longer than this are flagged
+	MinFocusHours float64 `yaml:"min_focus_hours"` // Days with less free time than this are flagged
+}
+
+// DocUpdates lists Drive documents edited since the last daily brief in the brief, when they
+// relate to the priorities: owned or last edited by a key stakeholder, named after a project or
+// focus area, or similar to a priority by embedding. The user's own edits are left out.
+type DocUpdates struct {
+	Enabled       bool    `yaml:"enabled"`
+	MaxDocs       int     `yaml:"max_docs"`       // Most documents listed in one brief
+	MinSimilarity float64 `yaml:"min_similarity"` // Embedding similarity (0-1) to a priority that counts as related; negative turns embedding matching off
+}
+
+// Dedup controls semantic duplicate detection for newly extracted tasks
+type Dedup struct {
+	Enabled             bool    `yaml:"enabled"`
+	SimilarityThreshold float64 `yaml:"similarity_threshold"` // Cosine similarity (0-1) above which tasks are merged
+	LookbackDays        int     `yaml:"lookback_days"`        // Only compare against pending tasks created this recently
+}
+
+// Search controls semantic search over thread summaries and Drive documents
+type Search struct {
+	Semantic      bool `yaml:"semantic"`       // Embed thread summaries and documents so search finds related context
+	EmbedInterval int  `yaml:"embed_interval"` // Minutes between embedding runs
+	EmbedBatch    int  `yaml:"embed_batch"`    // Most summaries and documents embedded per run
+}
+
+// Summaries adapts how deeply threads are summarized to how much they matter: a one-line gist
+// from a cheap model for low-priority threads, and a structured summary with decisions, owners,
+// risks and open questions for important ones
+type Summaries struct {
+	Adaptive  bool    `yaml:"adaptive"`   // Off gives every thread the standard summary
+	DeepScore float64 `yaml:"deep_score"` // Threads with an open task scoring at least this (0-100) get a deep summary, as do key stakeholders'
+}
+
+// People controls the per-contact relationship stats behind the People tab
+type People struct {
+	LookbackDays     int `yaml:"lookback_days"`      // Only mail from the last this many days counts
+	RefreshMinutes   int `yaml:"refresh_minutes"`    // Minutes between rebuilds of the stats
+	SenderMinThreads int `yaml:"sender_min_threads"` // Threads a sender must have started before how I treated them decides relevance
+}
+
+// Meetings controls pre-meeting relationship briefs for external contacts and meeting prep notes
+type Meetings struct {
+	RelationshipBriefs bool     `yaml:"relationship_briefs"`
+	LeadMinutes        int      `yaml:"lead_minutes"`     // How long before the meeting the brief is sent
+	InternalDomains    []string `yaml:"internal_domains"` // Attendees on these domains are not external (your own domain is always internal)
+
+	Prep            bool `yaml:"prep"`              // Generate prep notes for meetings with other attendees
+	PrepHoursAhead  int  `yaml:"prep_hours_ahead"`  // Prep notes are generated this many hours before the meeting
+	PrepSendMinutes int  `yaml:"prep_send_minutes"` // and sent this many minutes before it
+
+	RSVP bool `yaml:"rsvp"` // Accept, decline or tentatively accept invites from the TUI; adds the calendar.events scope
+}
+
+// WaitingOn tracks requests in email you've sent and nudges you when no reply arrives
+type WaitingOn struct {
+	Enabled        bool `yaml:"enabled"`
+	NudgeAfterDays int  `yaml:"nudge_after_days"` // Reply window when the email doesn't set one, and the gap between nudges
+	LookbackDays   int  `yaml:"lookback_days"`    // Sent mail older than this isn't checked for requests
+	MaxPerRun      int  `yaml:"max_per_run"`      // Sent messages checked with the LLM per run
+}
+
+// Commitments tracks what the user promises in sent mail until they follow up in the thread
+type Commitments struct {
+	Enabled      bool `yaml:"enabled"`
+	LookbackDays int  `yaml:"lookback_days"` // Sent mail older than this isn't checked for promises
+	MaxPerRun    int  `yaml:"max_per_run"`   // Sent messages checked with the LLM per run
+	MaxInBrief   int  `yaml:"max_in_brief"`  // Outstanding commitments listed in the daily brief
+}
+
+// Escalation chases high-impact tasks that go overdue: a reminder, then an urgent mobile push,
+// then a drafted apology or request for more time. Projects and stakeholders can have their own
+// policy.
+type Escalation struct {
+	Enabled          bool `yaml:"enabled"`
+	EscalationPolicy `yaml:",inline"`
+	Projects         map[string]EscalationPolicy `yaml:"projects"`     // Keyed by project name
+	Stakeholders     map[string]EscalationPolicy `yaml:"stakeholders"` // Keyed by internal, external or executive
+}
+
+// EscalationPolicy sets which overdue tasks escalate and when. Steps are minutes past the due
+// date; in project and stakeholder policies 0 keeps the default, and negative skips the step.
+type EscalationPolicy struct {
+	MinImpact       int `yaml:"min_impact"`       // Tasks with at least this impact (1-5) escalate
+	ReminderMinutes int `yaml:"reminder_minutes"` // Reminder through the usual channels
+	PushMinutes     int `yaml:"push_minutes"`     // Urgent mobile push, or a follow_me alert without push
+	DraftMinutes    int `yaml:"draft_minutes"`    // Suggested apology or renegotiation email
+}
+
+// PolicyFor returns the escalation policy for a task's project and stakeholder. A project's
+// policy wins over a stakeholder's, and both fall back to the default for fields they leave at 0.
+func (e Escalation) PolicyFor(project, stakeholder string) EscalationPolicy {
+	policy := e.EscalationPolicy
+	for _, override := range []EscalationPolicy{
+		e.Stakeholders[strings.ToLower(strings.TrimSpace(stakeholder))],
+		e.Projects[strings.ToLower(strings.TrimSpace(project))],
+	} {
+		if override.MinImpact != 0 {
+			policy.MinImpact = override.MinImpact
+		}
+		if override.ReminderMinutes != 0 {
+			policy.ReminderMinutes = override.ReminderMinutes
+		}
+		if override.PushMinutes != 0 {
+			policy.PushMinutes = override.PushMinutes
+		}
+		if override.DraftMinutes != 0 {
+			policy.DraftMinutes = override.DraftMinutes
+		}
+	}
+	return policy
+}
+
+// Focus controls focus mode, which holds back notifications for a while
+type Focus struct {
+	DefaultMinutes int `yaml:"default_minutes"` // Session length when none is given
 }
 
 type Limits struct {
@@ -119,6 +897,11 @@ type Limits struct {
 	// AI processing control
 	EnableAIProcessing    bool `yaml:"enable_ai_processing"`
 
+	// Re-enrichment of open tasks whose thread has new messages
+	ReenrichTasks      bool `yaml:"reenrich_tasks"`
+	ReenrichPerRun     int  `yaml:"reenrich_per_run"`     // Tasks re-enriched per processing run
+	ReenrichMinMinutes int  `yaml:"reenrich_min_minutes"` // Minimum time between re-enrichments of a task
+
 	// Drive limits
 	MaxDocumentsPerSync int `yaml:"max_documents_per_sync"`
 	DriveDaysOfHistory  int `yaml:"drive_days_of_history"`
@@ -144,6 +927,25 @@ type Priorities struct {
 	KeyProjects []string `yaml:"key_projects"`
 }
 
+// IsEmpty reports whether no priorities of any kind are set
+func (p *Priorities) IsEmpty() bool {
+	return len(p.OKRs)+len(p.FocusAreas)+len(p.KeyStakeholders)+len(p.KeyProjects) == 0
+}
+
+// Hash fingerprints the priorities, so results evaluated against one version of them
+// can be told apart from the next
+func (p *Priorities) Hash() string {
+	h := sha256.New()
+	for _, section := range [][]string{p.OKRs, p.FocusAreas, p.KeyStakeholders, p.KeyProjects} {
+		for _, item := range section {
+			h.Write([]byte(strings.TrimSpace(item)))
+			h.Write([]byte{0})
+		}
+		h.Write([]byte{1})
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))[:16]
+}
+
 type Front struct {
 	Enabled              bool   `yaml:"enabled"`
 	APIToken             string `yaml:"api_token"`
@@ -153,9 +955,52 @@ type Front struct {
 	MaxEnrichPerRun      int    `yaml:"max_enrich_per_run"`
 	SkipArchived         bool   `yaml:"skip_archived"`
 	DefaultSnoozeHours   int    `yaml:"default_snooze_hours"`
+	WebhookSecret        string `yaml:"webhook_secret"` // Signing secret of the Front app whose webhooks POST to /api/front/webhook
+}
+
+// Guards pause low-priority background work, such as embedding content for search and
+// re-enriching tasks, as the database, its disk or a Google API's daily quota nears its limit,
+// and alert the user before anything fails outright. A limit of 0 turns its guard off.
+type Guards struct {
+	CheckMinutes     int            `yaml:"check_minutes"`
+	MaxDBSizeMB      int            `yaml:"max_db_size_mb"`     // Size the database and its write-ahead log shouldn't outgrow
+	MinFreeDiskMB    int            `yaml:"min_free_disk_mb"`   // Free space to keep on the database's disk
+	GoogleDailyCalls map[string]int `yaml:"google_daily_calls"` // Daily request quota per Google API, e.g. tasks: 50000
+	ThrottlePercent  int            `yaml:"throttle_percent"`   // Share of max_db_size_mb or a quota at which work pauses
+}
+
+// DefaultGoogleDailyCalls are the default per-project quotas of the Google APIs that have a
+// daily request limit
+var DefaultGoogleDailyCalls = map[string]int{
+	"calendar": 1000000,
+	"tasks":    50000,
+}
+
+// Canary runs a synthetic thread through summarization, task extraction, scoring and brief
+// selection every night, and alerts if a stage fails or runs over its latency budget, so a
+// broken pipeline shows up before the morning brief comes out empty
+type Canary struct {
+	Enabled       bool           `yaml:"enabled"`
+	Time          string         `yaml:"time"`           // HH:MM (default: an hour before schedule.daily_brief_time)
+	BudgetSeconds map[string]int `yaml:"budget_seconds"` // Longest each stage may take: summarize, extract, score, brief
+}
+
+// CanaryStages are the pipeline stages the canary times, in the order it runs them
+var CanaryStages = []string{"summarize", "extract", "score", "brief"}
+
+// DefaultCanaryBudgets are how many seconds each canary stage may take by default
+var DefaultCanaryBudgets = map[string]int{
+	"summarize": 120,
+	"extract":   120,
+	"score":     60,
+	"brief":     10,
 }
 
 func Load(path string) (*Config, error) {
+	return load(path, "")
+}
+
+func load(path, profile string) (*Config, error) {
 	// Expand home directory
 	if path[:2] == "~/" {
 		home, err := os.UserHomeDir()
@@ -165,8 +1010,8 @@ func Load(path string) (*Config, error) {
 		path = filepath.Join(home, path[2:])
 	}
 
-	// Try XDG config directory as fallback if primary path doesn't exist
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	// Try XDG config directory as fallback if primary path doesn't exist (named profiles have no fallback)
+	if _, err := os.Stat(path); os.IsNotExist(err) && !isNamedProfile(profile) {
 		home, err := os.UserHomeDir()
 		if err == nil {
 			xdgPath := filepath.Join(home, ".config", "focus-agent", "config.yaml")
@@ -178,7 +1023,7 @@ func Load(path string) (*Config, error) {
 
 	// Create default config if it doesn't exist
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		if err := createDefaultConfig(path); err != nil {
+		if err := createDefaultConfig(path, profile); err != nil {
 			return nil, fmt.Errorf("failed to create default config: %w", err)
 		}
 		return nil, fmt.Errorf("config file created at %s - please update it with your settings", path)
@@ -197,6 +1042,7 @@ func Load(path string) (*Config, error) {
 	}
 
 	// Apply defaults
+	config.Profile = profile
 	applyDefaults(&config)
 
 	// Validate
@@ -208,8 +1054,28 @@ func Load(path string) (*Config, error) {
 }
 
 func applyDefaults(cfg *Config) {
+	dataDir := os.ExpandEnv("$HOME/.focus-agent")
+	if isNamedProfile(cfg.Profile) {
+		dataDir = ProfileDir(cfg.Profile)
+	}
+
 	if cfg.Database.Path == "" {
-		cfg.Database.Path = os.ExpandEnv("$HOME/.focus-agent/data.db")
+		cfg.Database.Path = filepath.Join(dataDir, "data.db")
+	}
+	if cfg.Database.SlowQueryMs == 0 {
+		cfg.Database.SlowQueryMs = 250
+	}
+	if cfg.Database.BackupDir == "" {
+		cfg.Database.BackupDir = filepath.Join(dataDir, "backups")
+	}
+	if cfg.Database.BackupKeep == 0 {
+		cfg.Database.BackupKeep = 10
+	}
+	if cfg.Database.NightlyBackupKeep == 0 {
+		cfg.Database.NightlyBackupKeep = 7
+	}
+	if cfg.Database.ColdStorageDir == "" {
+		cfg.Database.ColdStorageDir = filepath.Join(dataDir, "cold")
 	}
 
 	if cfg.Google.RedirectURL == "" {
@@ -217,7 +1083,10 @@ func applyDefaults(cfg *Config) {
 	}
 
 	if cfg.Google.TokenFile == "" {
-		cfg.Google.TokenFile = os.ExpandEnv("$HOME/.focus-agent/token.json")
+		cfg.Google.TokenFile = filepath.Join(dataDir, "token.json")
+	}
+	if cfg.Google.AuthFlow == "" {
+		cfg.Google.AuthFlow = AuthFlowBrowser
 	}
 
 	requiredScopes := []string{
@@ -230,6 +1099,25 @@ func applyDefaults(cfg *Config) {
 		"https://www.googleapis.com/auth/chat.memberships.readonly",
 	}
 
+	// Labelling threads needs write access to Gmail
+	if cfg.Google.PriorityLabel.Enabled {
+		requiredScopes = append(requiredScopes, "https://www.googleapis.com/auth/gmail.modify")
+	}
+
+	// Capturing Keep notes reads Keep, and trashing them needs write access
+	if cfg.Google.Capture.Keep {
+		if cfg.Google.Capture.TrashProcessed {
+			requiredScopes = append(requiredScopes, "https://www.googleapis.com/auth/keep")
+		} else {
+			requiredScopes = append(requiredScopes, "https://www.googleapis.com/auth/keep.readonly")
+		}
+	}
+
+	// Time blocking creates calendar events, and replying to invites edits them
+	if cfg.Planner.TimeBlocking.Enabled || cfg.Meetings.RSVP {
+		requiredScopes = append(requiredScopes, "https://www.googleapis.com/auth/calendar.events")
+	}
+
 	if len(cfg.Google.Scopes) == 0 {
 		cfg.Google.Scopes = append([]string{}, requiredScopes...)
 	} else {
@@ -257,11 +1145,56 @@ func applyDefaults(cfg *Config) {
 	if cfg.Google.PollingMinutes.Tasks == 0 {
 		cfg.Google.PollingMinutes.Tasks = 15
 	}
+	if cfg.Google.PollingMinutes.Capture == 0 {
+		cfg.Google.PollingMinutes.Capture = 15
+	}
+	if cfg.Google.PollingMinutes.Chat == 0 {
+		cfg.Google.PollingMinutes.Chat = 10
+	}
+
+	// Gmail priority label defaults
+	if cfg.Google.PriorityLabel.Name == "" {
+		cfg.Google.PriorityLabel.Name = "FocusAgent/High"
+	}
+	if cfg.Google.PriorityLabel.Threshold == 0 {
+		cfg.Google.PriorityLabel.Threshold = 75
+	}
+
+	// Attachment text extraction defaults
+	if cfg.Google.Attachments.MaxSizeMB == 0 {
+		cfg.Google.Attachments.MaxSizeMB = 10
+	}
+	if cfg.Google.Attachments.PDFToText == "" {
+		cfg.Google.Attachments.PDFToText = "pdftotext"
+	}
+
+	// Microsoft Graph defaults
+	if cfg.MSGraph.TenantID == "" {
+		cfg.MSGraph.TenantID = "common"
+	}
+	if cfg.MSGraph.RedirectURL == "" {
+		cfg.MSGraph.RedirectURL = "http://localhost:8080/callback"
+	}
+	if cfg.MSGraph.TokenFile == "" {
+		cfg.MSGraph.TokenFile = filepath.Join(dataDir, "msgraph_token.json")
+	}
+	if len(cfg.MSGraph.Scopes) == 0 {
+		cfg.MSGraph.Scopes = []string{"offline_access", "User.Read", "Mail.Read", "Calendars.Read"}
+	}
+	if cfg.MSGraph.PollingMinutes.Mail == 0 {
+		cfg.MSGraph.PollingMinutes.Mail = 5
+	}
+	if cfg.MSGraph.PollingMinutes.Calendar == 0 {
+		cfg.MSGraph.PollingMinutes.Calendar = 15
+	}
 
 	// Gemini defaults
 	if cfg.Gemini.Model == "" {
 		cfg.Gemini.Model = "gemini-2.5-flash"
 	}
+	if cfg.Gemini.KeyStrategy == "" {
+		cfg.Gemini.KeyStrategy = "failover"
+	}
 	if cfg.Gemini.MaxTokens == 0 {
 		cfg.Gemini.MaxTokens = 2000
 	}
@@ -292,6 +1225,15 @@ func applyDefaults(cfg *Config) {
 	if cfg.Gemini.BaseRetryDelay == 0 {
 		cfg.Gemini.BaseRetryDelay = 60
 	}
+	if cfg.Gemini.ProBudget.StartTime == "" {
+		cfg.Gemini.ProBudget.StartTime = "08:00"
+	}
+	if cfg.Gemini.ProBudget.EndTime == "" {
+		cfg.Gemini.ProBudget.EndTime = "18:00"
+	}
+	if cfg.Gemini.ProBudget.ReservePercent == 0 {
+		cfg.Gemini.ProBudget.ReservePercent = 25
+	}
 
 	// Ollama defaults - support for distributed processing across multiple hosts
 	if cfg.Ollama.Model == "" {
@@ -311,15 +1253,206 @@ func applyDefaults(cfg *Config) {
 		}
 	}
 
+	// LLM defaults - Ollama (free, local) first, Gemini as the final fallback
+	if cfg.LLM.PromptsDir == "" {
+		cfg.LLM.PromptsDir = filepath.Join(dataDir, "prompts")
+	} else if strings.HasPrefix(cfg.LLM.PromptsDir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			cfg.LLM.PromptsDir = filepath.Join(home, cfg.LLM.PromptsDir[2:])
+		}
+	}
+	if len(cfg.LLM.ProviderOrder) == 0 {
+		cfg.LLM.ProviderOrder = []string{ProviderOllama, ProviderClaude, ProviderGemini}
+	}
+	for i, provider := range cfg.LLM.ProviderOrder {
+		cfg.LLM.ProviderOrder[i] = strings.ToLower(strings.TrimSpace(provider))
+	}
+	if len(cfg.LLM.Budgets) > 0 {
+		budgets := make(map[string]ProviderBudget, len(cfg.LLM.Budgets))
+		for provider, budget := range cfg.LLM.Budgets {
+			budgets[strings.ToLower(strings.TrimSpace(provider))] = budget
+		}
+		cfg.LLM.Budgets = budgets
+	}
+	pricing := make(map[string]ModelPrice, len(DefaultModelPricing)+len(cfg.LLM.Pricing))
+	for model, price := range DefaultModelPricing {
+		pricing[model] = price
+	}
+	for model, price := range cfg.LLM.Pricing {
+		pricing[strings.ToLower(strings.TrimSpace(model))] = price
+	}
+	cfg.LLM.Pricing = pricing
+
+	// Chat delivery defaults
+	if cfg.Chat.RetryMinutes == 0 {
+		cfg.Chat.RetryMinutes = 5
+	}
+	if cfg.Chat.MaxDeliveryAttempts == 0 {
+		cfg.Chat.MaxDeliveryAttempts = 8
+	}
+	if cfg.Chat.Sync.LookbackHours == 0 {
+		cfg.Chat.Sync.LookbackHours = 24
+	}
+
+	// Notification channel defaults
+	if len(cfg.Notify.Channels) == 0 {
+		cfg.Notify.Channels = []string{ChannelChat}
+	}
+	for i, channel := range cfg.Notify.Channels {
+		cfg.Notify.Channels[i] = strings.ToLower(strings.TrimSpace(channel))
+	}
+	for i, channel := range cfg.Notify.PlainFormatting {
+		cfg.Notify.PlainFormatting[i] = strings.ToLower(strings.TrimSpace(channel))
+	}
+	for kind, channels := range cfg.Notify.Briefs {
+		for i, channel := range channels {
+			channels[i] = strings.ToLower(strings.TrimSpace(channel))
+		}
+		cfg.Notify.Briefs[kind] = channels
+	}
+	if len(cfg.Notify.FollowMe.Kinds) == 0 {
+		cfg.Notify.FollowMe.Kinds = []string{"follow_up", "meeting_prep"}
+	}
+	if len(cfg.Notify.FollowMe.Route) == 0 {
+		cfg.Notify.FollowMe.Route = []string{ChannelTUI, ChannelChat, ChannelPush}
+	}
+	for i, channel := range cfg.Notify.FollowMe.Route {
+		cfg.Notify.FollowMe.Route[i] = strings.ToLower(strings.TrimSpace(channel))
+	}
+	if cfg.Notify.FollowMe.PresenceSeconds == 0 {
+		cfg.Notify.FollowMe.PresenceSeconds = 300
+	}
+	if cfg.Notify.QuietHours.Start == "" {
+		cfg.Notify.QuietHours.Start = "21:00"
+	}
+	if cfg.Notify.QuietHours.End == "" {
+		cfg.Notify.QuietHours.End = "08:00"
+	}
+	if len(cfg.Notify.QuietHours.Kinds) == 0 {
+		cfg.Notify.QuietHours.Kinds = []string{"follow_up", "replan_brief"}
+	}
+	// Emailed briefs are sent through Gmail
+	if cfg.Notify.HasChannel(ChannelEmail) {
+		const sendScope = "https://www.googleapis.com/auth/gmail.send"
+		if !slices.Contains(cfg.Google.Scopes, sendScope) {
+			cfg.Google.Scopes = append(cfg.Google.Scopes, sendScope)
+		}
+	}
+	if cfg.Slack.MaxDeliveryAttempts == 0 {
+		cfg.Slack.MaxDeliveryAttempts = 8
+	}
+
+	// STT defaults
+	if cfg.STT.Backend == "" {
+		cfg.STT.Backend = STTWhisperCpp
+	}
+	if cfg.STT.WhisperBinary == "" {
+		cfg.STT.WhisperBinary = "whisper-cli"
+	}
+	if cfg.STT.FFmpegBinary == "" {
+		cfg.STT.FFmpegBinary = "ffmpeg"
+	}
+	if cfg.STT.APIURL == "" {
+		cfg.STT.APIURL = "https://api.openai.com/v1/audio/transcriptions"
+	}
+	if cfg.STT.Model == "" {
+		cfg.STT.Model = "whisper-1"
+	}
+	if cfg.STT.MaxUploadMB == 0 {
+		cfg.STT.MaxUploadMB = 25
+	}
+	if strings.HasPrefix(cfg.STT.WhisperModel, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			cfg.STT.WhisperModel = filepath.Join(home, cfg.STT.WhisperModel[2:])
+		}
+	}
+
+	if strings.HasPrefix(cfg.Chat.App.CredentialsFile, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			cfg.Chat.App.CredentialsFile = filepath.Join(home, cfg.Chat.App.CredentialsFile[2:])
+		}
+	}
+
 	// API defaults
 	if cfg.API.Port == 0 {
 		cfg.API.Port = 8081
 	}
+	for _, path := range []*string{&cfg.API.TLSCert, &cfg.API.TLSKey, &cfg.Remote.CACert} {
+		if strings.HasPrefix(*path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				*path = filepath.Join(home, (*path)[2:])
+			}
+		}
+	}
+
+	// Analytics defaults
+	if len(cfg.Analytics.AllowedTables) == 0 {
+		cfg.Analytics.AllowedTables = []string{
+			"analytics_tasks", "analytics_threads", "analytics_messages", "analytics_events", "analytics_usage",
+		}
+	}
+	if cfg.Analytics.MaxRows == 0 {
+		cfg.Analytics.MaxRows = 1000
+	}
+	if cfg.Analytics.TimeoutSeconds == 0 {
+		cfg.Analytics.TimeoutSeconds = 10
+	}
+
+	// Delegate defaults
+	if cfg.Delegate.Name == "" {
+		cfg.Delegate.Name = "Assistant"
+	}
+	if cfg.Delegate.Tag == "" {
+		cfg.Delegate.Tag = "delegable"
+	}
+
+	// CalDAV defaults
+	if cfg.CalDAV.Name == "" {
+		cfg.CalDAV.Name = "Focus Agent"
+	}
+	if cfg.CalDAV.CompletedDays == 0 {
+		cfg.CalDAV.CompletedDays = 7
+	}
+
+	// Calendar feed defaults
+	if cfg.CalendarFeed.Name == "" {
+		cfg.CalendarFeed.Name = "Focus Agent"
+	}
+	if cfg.CalendarFeed.PastDays == 0 {
+		cfg.CalendarFeed.PastDays = 14
+	}
+	if cfg.CalendarFeed.AheadDays == 0 {
+		cfg.CalendarFeed.AheadDays = 90
+	}
+	if cfg.CalendarFeed.RefreshMinutes == 0 {
+		cfg.CalendarFeed.RefreshMinutes = 30
+	}
+
+	// Drafting defaults
+	personas := make(map[string]Persona, len(DefaultPersonas)+len(cfg.Drafting.Personas))
+	for name, persona := range DefaultPersonas {
+		personas[name] = persona
+	}
+	for name, persona := range cfg.Drafting.Personas {
+		personas[strings.ToLower(strings.TrimSpace(name))] = persona
+	}
+	cfg.Drafting.Personas = personas
+	if cfg.Drafting.DefaultPersona == "" {
+		cfg.Drafting.DefaultPersona = "formal"
+	}
+	cfg.Drafting.DefaultPersona = strings.ToLower(strings.TrimSpace(cfg.Drafting.DefaultPersona))
 
 	// TUI defaults
 	if cfg.TUI.AutoRefreshSeconds == 0 {
 		cfg.TUI.AutoRefreshSeconds = 30
 	}
+	if cfg.TUI.ClientID == "" {
+		if hostname, err := os.Hostname(); err == nil && hostname != "" {
+			cfg.TUI.ClientID = hostname
+		} else {
+			cfg.TUI.ClientID = "default"
+		}
+	}
 
 	// Schedule defaults
 	if cfg.Schedule.DailyBriefTime == "" {
@@ -334,19 +1467,36 @@ func applyDefaults(cfg *Config) {
 	if cfg.Schedule.Timezone == "" {
 		cfg.Schedule.Timezone = "America/Los_Angeles"
 	}
+	if cfg.Schedule.override == nil {
+		cfg.Schedule.override = &timezoneOverride{}
+	}
+	if cfg.Schedule.DigestDay == "" {
+		cfg.Schedule.DigestDay = "friday"
+	}
+	cfg.Schedule.DigestDay = strings.ToLower(strings.TrimSpace(cfg.Schedule.DigestDay))
+	if cfg.Schedule.DigestTime == "" {
+		cfg.Schedule.DigestTime = "16:00"
+	}
+	if cfg.Schedule.WeeklyReviewDay == "" {
+		cfg.Schedule.WeeklyReviewDay = "friday"
+	}
+	cfg.Schedule.WeeklyReviewDay = strings.ToLower(strings.TrimSpace(cfg.Schedule.WeeklyReviewDay))
 
 	// Planner defaults
-	if cfg.Planner.Weights.Impact == 0 {
-		cfg.Planner.Weights.Impact = 0.4
+	if cfg.Planner.Scoring.Weights.IsZero() {
+		cfg.Planner.Scoring.Weights = DefaultScoringWeights
 	}
-	if cfg.Planner.Weights.Urgency == 0 {
-		cfg.Planner.Weights.Urgency = 0.35
+	profiles := make(map[string]ScoringWeights, len(DefaultScoringProfiles))
+	for name, weights := range DefaultScoringProfiles {
+		profiles[name] = weights
 	}
-	if cfg.Planner.Weights.Stakeholder == 0 {
-		cfg.Planner.Weights.Stakeholder = 0.15
+	for name, weights := range cfg.Planner.Scoring.Profiles {
+		profiles[strings.ToLower(name)] = weights
 	}
-	if cfg.Planner.Weights.Effort == 0 {
-		cfg.Planner.Weights.Effort = 0.1
+	cfg.Planner.Scoring.Profiles = profiles
+	cfg.Planner.Scoring.Profile = strings.ToLower(strings.TrimSpace(cfg.Planner.Scoring.Profile))
+	if cfg.Planner.Scoring.Profile == "" {
+		cfg.Planner.Scoring.Profile = DefaultScoringProfile
 	}
 	if cfg.Planner.MaxTasksPerBrief == 0 {
 		cfg.Planner.MaxTasksPerBrief = 10
@@ -354,6 +1504,166 @@ func applyDefaults(cfg *Config) {
 	if cfg.Planner.FocusBlockHours == 0 {
 		cfg.Planner.FocusBlockHours = 2
 	}
+	if cfg.Planner.DigestScoreThreshold == 0 {
+		cfg.Planner.DigestScoreThreshold = 40
+	}
+	if cfg.Planner.AlignmentWarmTasks == 0 {
+		cfg.Planner.AlignmentWarmTasks = 20
+	}
+	if cfg.Planner.AlignmentBatchSize == 0 {
+		cfg.Planner.AlignmentBatchSize = 10
+	}
+	if cfg.Planner.AlignmentSimilarityLow == 0 {
+		cfg.Planner.AlignmentSimilarityLow = 0.4
+	}
+	if cfg.Planner.AlignmentSimilarityHigh == 0 {
+		cfg.Planner.AlignmentSimilarityHigh = 0.8
+	}
+	if cfg.Planner.StaleProjectWeeks == 0 {
+		cfg.Planner.StaleProjectWeeks = 6
+	}
+	if cfg.Planner.FeedbackMaxBoost == 0 {
+		cfg.Planner.FeedbackMaxBoost = 10
+	}
+	if cfg.Planner.EffortCalibration.LookbackDays == 0 {
+		cfg.Planner.EffortCalibration.LookbackDays = 90
+	}
+	if cfg.Planner.EffortCalibration.MinSamples == 0 {
+		cfg.Planner.EffortCalibration.MinSamples = 5
+	}
+	if cfg.Planner.EffortCalibration.MaxFactor == 0 {
+		cfg.Planner.EffortCalibration.MaxFactor = 2
+	}
+	if cfg.Planner.EffortCalibration.IntervalHours == 0 {
+		cfg.Planner.EffortCalibration.IntervalHours = 24
+	}
+	if len(cfg.Planner.AutoArchiveDays) > 0 {
+		days := make(map[string]int, len(cfg.Planner.AutoArchiveDays))
+		for source, n := range cfg.Planner.AutoArchiveDays {
+			if n > 0 {
+				days[strings.ToLower(strings.TrimSpace(source))] = n
+			}
+		}
+		cfg.Planner.AutoArchiveDays = days
+	}
+	if cfg.Planner.TimeBlocking.Mode == "" {
+		cfg.Planner.TimeBlocking.Mode = TimeBlockingConfirm
+	}
+	if cfg.Planner.TimeBlocking.MinMinutes == 0 {
+		cfg.Planner.TimeBlocking.MinMinutes = 30
+	}
+	if cfg.Planner.CalendarChecks.WorkdayStart == "" {
+		cfg.Planner.CalendarChecks.WorkdayStart = "09:00"
+	}
+	if cfg.Planner.CalendarChecks.WorkdayEnd == "" {
+		cfg.Planner.CalendarChecks.WorkdayEnd = "17:00"
+	}
+	if cfg.Planner.CalendarChecks.MaxChainHours == 0 {
+		cfg.Planner.CalendarChecks.MaxChainHours = 3
+	}
+	if cfg.Planner.CalendarChecks.MinFocusHours == 0 {
+		cfg.Planner.CalendarChecks.MinFocusHours = 2
+	}
+	if cfg.Planner.DocUpdates.MaxDocs == 0 {
+		cfg.Planner.DocUpdates.MaxDocs = 5
+	}
+	if cfg.Planner.DocUpdates.MinSimilarity == 0 {
+		cfg.Planner.DocUpdates.MinSimilarity = 0.7
+	}
+
+	// Meetings defaults
+	if cfg.Meetings.LeadMinutes == 0 {
+		cfg.Meetings.LeadMinutes = 30
+	}
+	if cfg.Meetings.PrepHoursAhead == 0 {
+		cfg.Meetings.PrepHoursAhead = 12
+	}
+	if cfg.Meetings.PrepSendMinutes == 0 {
+		cfg.Meetings.PrepSendMinutes = 60
+	}
+
+	// Waiting-on defaults
+	if cfg.WaitingOn.NudgeAfterDays == 0 {
+		cfg.WaitingOn.NudgeAfterDays = 3
+	}
+	if cfg.WaitingOn.LookbackDays == 0 {
+		cfg.WaitingOn.LookbackDays = 7
+	}
+	if cfg.WaitingOn.MaxPerRun == 0 {
+		cfg.WaitingOn.MaxPerRun = 10
+	}
+
+	// Commitment defaults
+	if cfg.Commitments.LookbackDays == 0 {
+		cfg.Commitments.LookbackDays = 7
+	}
+	if cfg.Commitments.MaxPerRun == 0 {
+		cfg.Commitments.MaxPerRun = 10
+	}
+	if cfg.Commitments.MaxInBrief == 0 {
+		cfg.Commitments.MaxInBrief = 5
+	}
+
+	// Escalation defaults - remind after an hour, push after four, suggest an apology after a day
+	if cfg.Escalation.MinImpact == 0 {
+		cfg.Escalation.MinImpact = 4
+	}
+	if cfg.Escalation.ReminderMinutes == 0 {
+		cfg.Escalation.ReminderMinutes = 60
+	}
+	if cfg.Escalation.PushMinutes == 0 {
+		cfg.Escalation.PushMinutes = 4 * 60
+	}
+	if cfg.Escalation.DraftMinutes == 0 {
+		cfg.Escalation.DraftMinutes = 24 * 60
+	}
+	for _, policies := range []*map[string]EscalationPolicy{&cfg.Escalation.Projects, &cfg.Escalation.Stakeholders} {
+		if len(*policies) == 0 {
+			continue
+		}
+		keyed := make(map[string]EscalationPolicy, len(*policies))
+		for key, policy := range *policies {
+			keyed[strings.ToLower(strings.TrimSpace(key))] = policy
+		}
+		*policies = keyed
+	}
+
+	// Focus defaults
+	if cfg.Focus.DefaultMinutes == 0 {
+		cfg.Focus.DefaultMinutes = 45
+	}
+
+	// Dedup defaults
+	if cfg.Dedup.SimilarityThreshold == 0 {
+		cfg.Dedup.SimilarityThreshold = 0.9
+	}
+	if cfg.Dedup.LookbackDays == 0 {
+		cfg.Dedup.LookbackDays = 14
+	}
+
+	// Search defaults
+	if cfg.Search.EmbedInterval == 0 {
+		cfg.Search.EmbedInterval = 15
+	}
+	if cfg.Search.EmbedBatch == 0 {
+		cfg.Search.EmbedBatch = 50
+	}
+
+	// Summaries defaults
+	if cfg.Summaries.DeepScore == 0 {
+		cfg.Summaries.DeepScore = 70
+	}
+
+	// People defaults
+	if cfg.People.LookbackDays == 0 {
+		cfg.People.LookbackDays = 180
+	}
+	if cfg.People.RefreshMinutes == 0 {
+		cfg.People.RefreshMinutes = 60
+	}
+	if cfg.People.SenderMinThreads == 0 {
+		cfg.People.SenderMinThreads = 5
+	}
 
 	// Limits defaults
 	if cfg.Limits.MaxThreadsPerSync == 0 {
@@ -376,6 +1686,54 @@ func applyDefaults(cfg *Config) {
 	if cfg.Limits.MaxTaskLists == 0 {
 		cfg.Limits.MaxTaskLists = 10
 	}
+	if cfg.Limits.ReenrichPerRun == 0 {
+		cfg.Limits.ReenrichPerRun = 10
+	}
+	if cfg.Limits.ReenrichMinMinutes == 0 {
+		cfg.Limits.ReenrichMinMinutes = 60
+	}
+
+	// Notion export defaults
+	if cfg.Notion.MaxTasks == 0 {
+		cfg.Notion.MaxTasks = 25
+	}
+	if cfg.Notion.IntervalMinutes == 0 {
+		cfg.Notion.IntervalMinutes = 60
+	}
+
+	// Guard defaults
+	if cfg.Guards.CheckMinutes == 0 {
+		cfg.Guards.CheckMinutes = 5
+	}
+	if cfg.Guards.MinFreeDiskMB == 0 {
+		cfg.Guards.MinFreeDiskMB = 1024
+	}
+	if cfg.Guards.ThrottlePercent == 0 {
+		cfg.Guards.ThrottlePercent = 80
+	}
+	quotas := make(map[string]int, len(DefaultGoogleDailyCalls)+len(cfg.Guards.GoogleDailyCalls))
+	for service, calls := range DefaultGoogleDailyCalls {
+		quotas[service] = calls
+	}
+	for service, calls := range cfg.Guards.GoogleDailyCalls {
+		quotas[strings.ToLower(strings.TrimSpace(service))] = calls
+	}
+	cfg.Guards.GoogleDailyCalls = quotas
+
+	// Canary defaults
+	if cfg.Canary.Time == "" {
+		if brief, err := time.Parse("15:04", cfg.Schedule.DailyBriefTime); err == nil {
+			cfg.Canary.Time = brief.Add(-time.Hour).Format("15:04")
+		}
+	}
+	budgets := make(map[string]int, len(DefaultCanaryBudgets))
+	for stage, secs := range DefaultCanaryBudgets {
+		budgets[stage] = secs
+	}
+	for stage, secs := range cfg.Canary.BudgetSeconds {
+		budgets[strings.ToLower(strings.TrimSpace(stage))] = secs
+	}
+	cfg.Canary.BudgetSeconds = budgets
 
 	// Front defaults
 	if cfg.Front.MaxRequestsPerMinute == 0 {
@@ -404,11 +1762,348 @@ func validate(cfg *Config) error {
 	if cfg.Google.ClientSecret == "" {
 		return fmt.Errorf("google.client_secret is required")
 	}
-	if cfg.Gemini.APIKey == "" {
+	if !slices.Contains(AuthFlows, cfg.Google.AuthFlow) {
+		return fmt.Errorf("google.auth_flow must be one of %s", strings.Join(AuthFlows, ", "))
+	}
+	if len(cfg.Gemini.Keys()) == 0 {
 		return fmt.Errorf("gemini.api_key is required")
 	}
-	if cfg.Chat.WebhookURL == "" {
-		return fmt.Errorf("chat.webhook_url is required")
+	if cfg.Gemini.KeyStrategy != "failover" && cfg.Gemini.KeyStrategy != "round_robin" {
+		return fmt.Errorf("gemini.key_strategy must be failover or round_robin")
+	}
+	if cfg.Gemini.KeyDailyRequests < 0 {
+		return fmt.Errorf("gemini.key_daily_requests must not be negative")
+	}
+	if cfg.MSGraph.Enabled && cfg.MSGraph.ClientID == "" {
+		return fmt.Errorf("msgraph.client_id is required when msgraph is enabled")
+	}
+
+	// Each delivery channel must be known and configured
+	allChannels := slices.Clone(cfg.Notify.Channels)
+	for kind, channels := range cfg.Notify.Briefs {
+		if !slices.Contains(BriefKinds, kind) {
+			return fmt.Errorf("notifications.briefs: unknown kind %q (expected one of %s)", kind, strings.Join(BriefKinds, ", "))
+		}
+		if slices.Contains(channels, ChannelEmail) && !slices.Contains(EmailBriefKinds, kind) {
+			return fmt.Errorf("notifications.briefs.%s: %s can't be sent by email (only %s)", kind, kind, strings.Join(EmailBriefKinds, ", "))
+		}
+		allChannels = append(allChannels, channels...)
+	}
+	for _, channel := range allChannels {
+		switch channel {
+		case ChannelChat:
+			if cfg.Chat.WebhookURL == "" {
+				return fmt.Errorf("chat.webhook_url is required")
+			}
+		case ChannelSlack:
+			if cfg.Slack.BotToken == "" && cfg.Slack.WebhookURL == "" {
+				return fmt.Errorf("slack.webhook_url or slack.bot_token is required when Slack delivery is enabled")
+			}
+			if cfg.Slack.BotToken != "" && cfg.Slack.Channel == "" {
+				return fmt.Errorf("slack.channel is required when using slack.bot_token")
+			}
+		case ChannelEmail:
+			// Sent with the Google credentials, to notifications.email_to or the user's own address
+		default:
+			return fmt.Errorf("notifications.channels: unknown channel %q (expected chat, slack or email)", channel)
+		}
+	}
+
+	for _, channel := range cfg.Notify.PlainFormatting {
+		if channel != ChannelChat && channel != ChannelSlack && channel != ChannelEmail {
+			return fmt.Errorf("notifications.plain_formatting: unknown channel %q (expected chat, slack or email)", channel)
+		}
+	}
+
+	// Urgent alerts can also go to the TUI and mobile push
+	if cfg.Notify.FollowMe.Enabled {
+		for _, kind := range cfg.Notify.FollowMe.Kinds {
+			if !slices.Contains(BriefKinds, kind) {
+				return fmt.Errorf("notifications.follow_me.kinds: unknown kind %q (expected one of %s)", kind, strings.Join(BriefKinds, ", "))
+			}
+		}
+		for _, channel := range cfg.Notify.FollowMe.Route {
+			switch channel {
+			case ChannelTUI:
+			case ChannelChat:
+				if cfg.Chat.WebhookURL == "" {
+					return fmt.Errorf("chat.webhook_url is required when notifications.follow_me.route includes chat")
+				}
+			case ChannelSlack:
+				if cfg.Slack.BotToken == "" && cfg.Slack.WebhookURL == "" {
+					return fmt.Errorf("slack.webhook_url or slack.bot_token is required when notifications.follow_me.route includes slack")
+				}
+			case ChannelPush:
+				if cfg.Push.URL == "" {
+					return fmt.Errorf("push.url is required when notifications.follow_me.route includes push")
+				}
+			default:
+				return fmt.Errorf("notifications.follow_me.route: unknown channel %q (expected tui, chat, slack or push)", channel)
+			}
+		}
+		if cfg.Notify.FollowMe.PresenceSeconds < 0 {
+			return fmt.Errorf("notifications.follow_me.presence_seconds must not be negative")
+		}
+	}
+
+	if cfg.Notify.QuietHours.Enabled {
+		for name, clock := range map[string]string{"start": cfg.Notify.QuietHours.Start, "end": cfg.Notify.QuietHours.End} {
+			if _, err := time.Parse("15:04", clock); err != nil {
+				return fmt.Errorf("notifications.quiet_hours.%s: invalid time %q (expected HH:MM)", name, clock)
+			}
+		}
+		for _, kind := range cfg.Notify.QuietHours.Kinds {
+			if !slices.Contains(BriefKinds, kind) {
+				return fmt.Errorf("notifications.quiet_hours.kinds: unknown kind %q (expected one of %s)", kind, strings.Join(BriefKinds, ", "))
+			}
+		}
+	}
+
+	if keepAlive := cfg.Ollama.KeepAlive; keepAlive != "" && keepAlive != "-1" {
+		if _, err := time.ParseDuration(keepAlive); err != nil {
+			return fmt.Errorf("ollama.keep_alive: invalid duration %q (expected e.g. 30m, or -1 to keep the model loaded)", keepAlive)
+		}
+	}
+	if cfg.Ollama.KeepAliveMinutes < 0 {
+		return fmt.Errorf("ollama.keep_alive_minutes: must not be negative")
+	}
+
+	// LLM provider order must only name known providers, once each
+	seenProviders := make(map[string]bool)
+	for _, provider := range cfg.LLM.ProviderOrder {
+		switch provider {
+		case ProviderOllama, ProviderClaude, ProviderGemini:
+		default:
+			return fmt.Errorf("llm.provider_order: unknown provider %q (expected ollama, claude or gemini)", provider)
+		}
+		if seenProviders[provider] {
+			return fmt.Errorf("llm.provider_order: provider %q listed more than once", provider)
+		}
+		seenProviders[provider] = true
+	}
+	for provider, budget := range cfg.LLM.Budgets {
+		switch provider {
+		case ProviderOllama, ProviderClaude, ProviderGemini:
+		default:
+			return fmt.Errorf("llm.budgets: unknown provider %q (expected ollama, claude or gemini)", provider)
+		}
+		if budget.MaxTokens < 0 || budget.MaxCost < 0 {
+			return fmt.Errorf("llm.budgets.%s: caps must not be negative", provider)
+		}
+	}
+
+	switch cfg.STT.Backend {
+	case STTWhisperCpp, STTOpenAI:
+	default:
+		return fmt.Errorf("stt.backend: unknown backend %q (expected whisper_cpp or openai)", cfg.STT.Backend)
+	}
+
+	if err := cfg.Planner.Scoring.Weights.validate(); err != nil {
+		return fmt.Errorf("planner.scoring.weights: %w", err)
+	}
+	for name, weights := range cfg.Planner.Scoring.Profiles {
+		if name == DefaultScoringProfile {
+			return fmt.Errorf("planner.scoring.profiles: %q names planner.scoring.weights and can't be redefined", name)
+		}
+		if err := weights.validate(); err != nil {
+			return fmt.Errorf("planner.scoring.profiles.%s: %w", name, err)
+		}
+	}
+	if _, ok := cfg.Planner.Scoring.ProfileWeights(cfg.Planner.Scoring.Profile); !ok {
+		return fmt.Errorf("planner.scoring.profile: unknown profile %q (expected one of %s)",
+			cfg.Planner.Scoring.Profile, strings.Join(cfg.Planner.Scoring.ProfileNames(), ", "))
+	}
+	if calibration := cfg.Planner.EffortCalibration; calibration.Enabled {
+		if calibration.LookbackDays < 0 || calibration.MinSamples < 0 || calibration.IntervalHours < 0 {
+			return fmt.Errorf("planner.effort_calibration: lookback_days, min_samples and interval_hours must be positive")
+		}
+		if calibration.MaxFactor < 1 {
+			return fmt.Errorf("planner.effort_calibration.max_factor: must be at least 1, got %g", calibration.MaxFactor)
+		}
+	}
+
+	switch cfg.Planner.TimeBlocking.Mode {
+	case TimeBlockingAuto, TimeBlockingConfirm:
+	default:
+		return fmt.Errorf("planner.time_blocking.mode: unknown mode %q (expected auto or confirm)", cfg.Planner.TimeBlocking.Mode)
+	}
+
+	// Calendar checks need a workday to measure focus time in
+	if cfg.Planner.CalendarChecks.Enabled {
+		start, err := time.Parse("15:04", cfg.Planner.CalendarChecks.WorkdayStart)
+		if err != nil {
+			return fmt.Errorf("planner.calendar_checks.workday_start: invalid time %q (expected HH:MM)", cfg.Planner.CalendarChecks.WorkdayStart)
+		}
+		end, err := time.Parse("15:04", cfg.Planner.CalendarChecks.WorkdayEnd)
+		if err != nil {
+			return fmt.Errorf("planner.calendar_checks.workday_end: invalid time %q (expected HH:MM)", cfg.Planner.CalendarChecks.WorkdayEnd)
+		}
+		if !end.After(start) {
+			return fmt.Errorf("planner.calendar_checks.workday_end must be after workday_start")
+		}
+		if cfg.Planner.CalendarChecks.MaxChainHours < 0 || cfg.Planner.CalendarChecks.MinFocusHours < 0 {
+			return fmt.Errorf("planner.calendar_checks hours must not be negative")
+		}
+	}
+
+	if cfg.Planner.DocUpdates.MaxDocs < 0 {
+		return fmt.Errorf("planner.doc_updates.max_docs must not be negative")
+	}
+	if cfg.Planner.DocUpdates.MinSimilarity > 1 {
+		return fmt.Errorf("planner.doc_updates.min_similarity must be at most 1")
+	}
+
+	// Weekly digest must land on a real weekday
+	if _, ok := Weekdays[cfg.Schedule.DigestDay]; !ok {
+		return fmt.Errorf("schedule.digest_day: unknown day %q", cfg.Schedule.DigestDay)
+	}
+	if cfg.Schedule.EndOfDayTime != "" {
+		if _, err := time.Parse("15:04", cfg.Schedule.EndOfDayTime); err != nil {
+			return fmt.Errorf("schedule.end_of_day_time: invalid time %q (expected HH:MM)", cfg.Schedule.EndOfDayTime)
+		}
+	}
+	if cfg.Schedule.WeeklyReviewTime != "" {
+		if _, ok := Weekdays[cfg.Schedule.WeeklyReviewDay]; !ok {
+			return fmt.Errorf("schedule.weekly_review_day: unknown day %q", cfg.Schedule.WeeklyReviewDay)
+		}
+		if _, err := time.Parse("15:04", cfg.Schedule.WeeklyReviewTime); err != nil {
+			return fmt.Errorf("schedule.weekly_review_time: invalid time %q (expected HH:MM)", cfg.Schedule.WeeklyReviewTime)
+		}
+	}
+
+	// Pro budget window must be a valid range of the day
+	if cfg.Gemini.ProBudget.DailyCalls > 0 {
+		start, err := time.Parse("15:04", cfg.Gemini.ProBudget.StartTime)
+		if err != nil {
+			return fmt.Errorf("gemini.pro_budget.start_time: invalid time %q (expected HH:MM)", cfg.Gemini.ProBudget.StartTime)
+		}
+		end, err := time.Parse("15:04", cfg.Gemini.ProBudget.EndTime)
+		if err != nil {
+			return fmt.Errorf("gemini.pro_budget.end_time: invalid time %q (expected HH:MM)", cfg.Gemini.ProBudget.EndTime)
+		}
+		if !end.After(start) {
+			return fmt.Errorf("gemini.pro_budget.end_time must be after start_time")
+		}
+		if cfg.Gemini.ProBudget.ReservePercent < 0 || cfg.Gemini.ProBudget.ReservePercent > 100 {
+			return fmt.Errorf("gemini.pro_budget.reserve_percent must be between 0 and 100")
+		}
+	}
+
+	if cfg.CalDAV.Enabled && !cfg.API.Enabled {
+		return fmt.Errorf("caldav needs the API server (api.enabled)")
+	}
+
+	if cfg.Metrics.Enabled && !cfg.API.Enabled {
+		return fmt.Errorf("metrics needs the API server (api.enabled)")
+	}
+
+	if cfg.CalendarFeed.Enabled && !cfg.API.Enabled {
+		return fmt.Errorf("calendar_feed needs the API server (api.enabled)")
+	}
+	if cfg.CalendarFeed.PastDays < 0 || cfg.CalendarFeed.AheadDays < 0 || cfg.CalendarFeed.RefreshMinutes < 0 {
+		return fmt.Errorf("calendar_feed.past_days, ahead_days and refresh_minutes must not be negative")
+	}
+
+	if _, ok := cfg.Drafting.Persona(cfg.Drafting.DefaultPersona); !ok {
+		return fmt.Errorf("drafting.default_persona %q is not a configured persona", cfg.Drafting.DefaultPersona)
+	}
+	for name, persona := range cfg.Drafting.Personas {
+		if persona.Instructions == "" || persona.MaxWords <= 0 {
+			return fmt.Errorf("drafting.personas.%s needs instructions and a positive max_words", name)
+		}
+	}
+
+	if cfg.Chat.App.Enabled {
+		if cfg.Chat.App.CredentialsFile == "" || cfg.Chat.App.Audience == "" {
+			return fmt.Errorf("chat.app.credentials_file and chat.app.audience are required when the Chat app is enabled")
+		}
+		if !cfg.API.Enabled {
+			return fmt.Errorf("chat.app needs the API server (api.enabled) to receive button clicks")
+		}
+	}
+
+	if cfg.Chat.Sync.Enabled {
+		if len(cfg.Chat.Sync.Spaces) == 0 && !cfg.Chat.Sync.DirectMessages {
+			return fmt.Errorf("chat.sync needs spaces or direct_messages")
+		}
+		if cfg.Chat.Sync.LookbackHours < 0 {
+			return fmt.Errorf("chat.sync.lookback_hours must not be negative")
+		}
+	}
+
+	if (cfg.API.TLSCert == "") != (cfg.API.TLSKey == "") {
+		return fmt.Errorf("api.tls_cert and api.tls_key must be set together")
+	}
+
+	// A shared token would let the dashboard use every other endpoint
+	if cfg.Analytics.Token != "" && cfg.Analytics.Token == cfg.API.AuthKey {
+		return fmt.Errorf("analytics.token must differ from api.auth_key")
+	}
+	if cfg.Delegate.Token != "" && (cfg.Delegate.Token == cfg.API.AuthKey || cfg.Delegate.Token == cfg.Analytics.Token) {
+		return fmt.Errorf("delegate.token must differ from api.auth_key and analytics.token")
+	}
+	if cfg.Metrics.Token != "" && cfg.Metrics.Token == cfg.API.AuthKey {
+		return fmt.Errorf("metrics.token must differ from api.auth_key")
+	}
+	if cfg.CalendarFeed.Token != "" && cfg.CalendarFeed.Token == cfg.API.AuthKey {
+		return fmt.Errorf("calendar_feed.token must differ from api.auth_key")
+	}
+
+	if cfg.Dedup.SimilarityThreshold <= 0 || cfg.Dedup.SimilarityThreshold > 1 {
+		return fmt.Errorf("dedup.similarity_threshold must be between 0 and 1")
+	}
+
+	if cfg.Search.EmbedInterval < 0 || cfg.Search.EmbedBatch < 0 {
+		return fmt.Errorf("search.embed_interval and search.embed_batch must not be negative")
+	}
+
+	if cfg.Summaries.DeepScore < 0 || cfg.Summaries.DeepScore > 100 {
+		return fmt.Errorf("summaries.deep_score must be between 0 and 100")
+	}
+
+	if cfg.Commitments.LookbackDays < 0 || cfg.Commitments.MaxPerRun < 0 || cfg.Commitments.MaxInBrief < 0 {
+		return fmt.Errorf("commitments.lookback_days, max_per_run and max_in_brief must not be negative")
+	}
+
+	if cfg.Database.ColdStorageDays < 0 {
+		return fmt.Errorf("database.cold_storage_days must not be negative")
+	}
+
+	if cfg.People.LookbackDays < 0 || cfg.People.RefreshMinutes < 0 || cfg.People.SenderMinThreads < 0 {
+		return fmt.Errorf("people.lookback_days, people.refresh_minutes and people.sender_min_threads must not be negative")
+	}
+
+	if cfg.Notion.Enabled {
+		if cfg.Notion.Token == "" {
+			return fmt.Errorf("notion.token is required when Notion export is enabled")
+		}
+		if cfg.Notion.TasksDatabaseID == "" && cfg.Notion.BriefsDatabaseID == "" {
+			return fmt.Errorf("notion.tasks_database_id or notion.briefs_database_id is required when Notion export is enabled")
+		}
+		if cfg.Notion.MaxTasks < 0 || cfg.Notion.IntervalMinutes < 0 {
+			return fmt.Errorf("notion.max_tasks and notion.interval_minutes must not be negative")
+		}
+	}
+
+	if cfg.Guards.CheckMinutes < 0 || cfg.Guards.MaxDBSizeMB < 0 || cfg.Guards.MinFreeDiskMB < 0 {
+		return fmt.Errorf("guards.check_minutes, max_db_size_mb and min_free_disk_mb must not be negative")
+	}
+	if cfg.Guards.ThrottlePercent < 0 || cfg.Guards.ThrottlePercent > 100 {
+		return fmt.Errorf("guards.throttle_percent must be between 0 and 100")
+	}
+
+	if cfg.Canary.Enabled {
+		if _, err := time.Parse("15:04", cfg.Canary.Time); err != nil {
+			return fmt.Errorf("canary.time: invalid time %q (expected HH:MM)", cfg.Canary.Time)
+		}
+		for stage, secs := range cfg.Canary.BudgetSeconds {
+			if !slices.Contains(CanaryStages, stage) {
+				return fmt.Errorf("canary.budget_seconds: unknown stage %q (expected one of %s)", stage, strings.Join(CanaryStages, ", "))
+			}
+			if secs < 0 {
+				return fmt.Errorf("canary.budget_seconds.%s must not be negative", stage)
+			}
+		}
 	}
 
 	// Front validation (only if enabled)
@@ -424,7 +2119,7 @@ func validate(cfg *Config) error {
 	return nil
 }
 
-func createDefaultConfig(path string) error {
+func createDefaultConfig(path, profile string) error {
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -444,6 +2139,10 @@ google:
   redirect_url: http://localhost:8080/callback
   token_file: ~/.focus-agent/token.json
 
+  # How -auth gets the first token: browser (local callback), manual (paste the
+  # redirected URL back, for headless servers) or device (enter a code on another device)
+  auth_flow: browser
+
   # OAuth scopes (default read-only)
   scopes:
     - https://www.googleapis.com/auth/gmail.readonly
@@ -458,6 +2157,24 @@ google:
     calendar: 15
     tasks: 15
 
+  # Label threads scored at or above the threshold in Gmail (adds the gmail.modify scope)
+  priority_label:
+    enabled: false
+    name: FocusAgent/High
+    threshold: 75
+
+# Outlook / Microsoft 365 mail and calendar (optional)
+msgraph:
+  enabled: false
+  tenant_id: common
+  client_id: YOUR_AZURE_APP_CLIENT_ID
+  client_secret: ""
+  redirect_url: http://localhost:8080/callback
+  token_file: ~/.focus-agent/msgraph_token.json
+  polling_minutes:
+    mail: 5
+    calendar: 15
+
 gemini:
   # Get from AI Studio: https://aistudio.google.com/app/apikey
   api_key: YOUR_GEMINI_API_KEY_HERE
@@ -466,30 +2183,189 @@ gemini:
   temperature: 0.3
   cache_hours: 24
 
+  # Extra keys, used when api_key's daily quota runs out (failover) or in turn (round_robin)
+  api_keys: []
+  key_strategy: failover
+  key_daily_requests: 0    # Free tier: 250 (0 = until Gemini reports the quota used up)
+
+  # Spread Pro-model calls across the day instead of first come first served
+  pro_budget:
+    daily_calls: 0         # 0 disables the budget
+    start_time: "08:00"
+    end_time: "18:00"
+    reserve_percent: 25    # Held back for key-stakeholder threads
+
+llm:
+  # Order in which LLM providers are tried (unavailable ones are skipped)
+  provider_order: [ollama, claude, gemini]
+
+  # Daily caps per provider; a provider is skipped once either cap is reached (0 = no cap)
+  budgets:
+    gemini:
+      max_tokens: 0
+      max_cost: 0
+
 chat:
   # Google Chat webhook URL
   webhook_url: YOUR_WEBHOOK_URL_HERE
   space_id: YOUR_SPACE_ID
   thread_key: focus-agent
+  # Interactive brief cards (Complete, Snooze 1d, Open in TUI buttons)
+  app:
+    enabled: false
+    credentials_file: ~/.focus-agent/chat-app.json
+    audience: https://focus-agent.example.com/api/chat/events
+  # Extract tasks from messages in these spaces and your DMs
+  sync:
+    enabled: false
+    spaces: []
+    direct_messages: true
+
+# Slack delivery (add slack to notifications.channels to enable)
+slack:
+  webhook_url: ""
+  bot_token: ""
+  channel: ""
+
+notifications:
+  # Where briefs and reminders are delivered: chat, slack and/or email
+  channels: [chat]
+  briefs: {}
+  email_to: ""
+  # Send urgent alerts only to where you are: the TUI if you've used it recently,
+  # otherwise Chat, otherwise mobile push
+  follow_me:
+    enabled: false
+    kinds: [follow_up, meeting_prep]
+    route: [tui, chat, push]
+    presence_seconds: 300
+  # Hold these kinds in the outbox overnight (and at weekends) instead of sending them
+  quiet_hours:
+    enabled: false
+    start: "21:00"
+    end: "08:00"
+    weekends: false
+    kinds: [follow_up, replan_brief]
+
+# Mobile push through ntfy (https://ntfy.sh or a self-hosted server)
+push:
+  url: ""
+  token: ""
+
+# Mirror top tasks and daily briefs into Notion databases for teammates
+notion:
+  enabled: false
+  token: ""
+  tasks_database_id: ""
+  briefs_database_id: ""
+  max_tasks: 25
+  interval_minutes: 60
+
+# Speech-to-text for voice capture: whisper_cpp (local) or openai (cloud)
+stt:
+  backend: whisper_cpp
+  whisper_model: ""
+  api_key: ""
 
 schedule:
   daily_brief_time: "07:45"
   replan_time: "13:00"
+  end_of_day_time: ""
   followup_minutes: 60
   timezone: America/Los_Angeles
+  digest_day: friday
+  digest_time: "16:00"
+  weekly_review_day: friday
+  weekly_review_time: ""
 
 planner:
-  # Task scoring weights
-  weights:
-    impact: 0.4
-    urgency: 0.35
-    stakeholder: 0.15
-    effort: 0.1
+  # Task scoring weights; profile switches to a named set (default, deadline_crunch, strategic)
+  scoring:
+    weights:
+      strategic: 0.3
+      urgency: 0.25
+      impact: 0.2
+      stakeholder: 0.15
+      effort: 0.1
+    profile: default
 
   max_tasks_per_brief: 10
   focus_block_hours: 2
+  digest_score_threshold: 40
+  alignment_warm_tasks: 20  # Top tasks re-scored right after priorities are edited
+
+  # Scale each task's effort in scoring by how long tasks from its project or
+  # stakeholder have really taken, relearned every interval_hours
+  effort_calibration:
+    enabled: false
+    lookback_days: 90
+    min_samples: 5
+    max_factor: 2
+    interval_hours: 24
+
+  # Write the daily plan's focus blocks to Google Calendar as tentative events
+  # (adds the calendar.events scope)
+  time_blocking:
+    enabled: false
+    mode: confirm     # auto, or confirm in the TUI (B on the tasks view)
+    min_minutes: 30   # Shortest block worth fitting around existing events
+
+# Relationship brief before meetings with external contacts, and prep notes for meetings
+meetings:
+  relationship_briefs: false
+  lead_minutes: 30
+  internal_domains: []
+  prep: false
+  prep_hours_ahead: 12
+  prep_send_minutes: 60
+
+# Track requests in email you send and nudge you when no reply arrives
+waiting_on:
+  enabled: false
+  nudge_after_days: 3
+  lookback_days: 7
+  max_per_run: 10
+
+# Track what you promise in email you send until you follow up in the thread
+commitments:
+  enabled: false
+  lookback_days: 7
+  max_per_run: 10
+  max_in_brief: 5
+
+# Merge newly extracted tasks that duplicate a recent pending task (requires Ollama embeddings)
+dedup:
+  enabled: false
+  similarity_threshold: 0.9
+  lookback_days: 14
+
+# Semantic search over thread summaries and Drive documents
+search:
+  semantic: false
+  embed_interval: 15
+  embed_batch: 50
+
+# Gist low-priority threads and give important ones a structured summary
+summaries:
+  adaptive: false
+  deep_score: 70
+
+# Per-contact stats for the People tab, rebuilt from your mail
+people:
+  lookback_days: 180
+  refresh_minutes: 60
+  sender_min_threads: 5
+
+# Focus mode: notifications are held and summarized when the session ends
+focus:
+  default_minutes: 45
 `
 
+	// A named profile keeps its database and credentials in its own directory
+	if isNamedProfile(profile) {
+		exampleConfig = strings.ReplaceAll(exampleConfig, "~/.focus-agent/", "~/.focus-agent/profiles/"+profile+"/")
+	}
+
 	if err := os.WriteFile(path, []byte(exampleConfig), 0644); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
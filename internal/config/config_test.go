@@ -0,0 +1,38 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestDefaultConfigKeys verifies that every key in the generated default config is one the
+// Config struct knows, so a misplaced section isn't silently dropped when it's parsed
+func TestDefaultConfigKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := createDefaultConfig(path, ""); err != nil {
+		t.Fatalf("createDefaultConfig: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil {
+		t.Fatalf("default config has keys Config doesn't know: %v", err)
+	}
+
+	// Parsed before defaults are applied, so these come from the file itself
+	if len(cfg.LLM.ProviderOrder) == 0 {
+		t.Error("Expected llm.provider_order in the default config")
+	}
+	if _, ok := cfg.LLM.Budgets[ProviderGemini]; !ok {
+		t.Error("Expected llm.budgets.gemini in the default config")
+	}
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// DefaultProfile is the profile whose config, database and credentials live directly in ~/.focus-agent
+const DefaultProfile = "default"
+
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// isNamedProfile reports whether profile is set and isn't the default profile
+func isNamedProfile(profile string) bool {
+	return profile != "" && profile != DefaultProfile
+}
+
+// BaseDir returns the focus-agent directory, ~/.focus-agent
+func BaseDir() string {
+	return os.ExpandEnv("$HOME/.focus-agent")
+}
+
+// ProfileDir returns the directory holding a profile's config, database and credentials
+func ProfileDir(name string) string {
+	if !isNamedProfile(name) {
+		return BaseDir()
+	}
+	return filepath.Join(BaseDir(), "profiles", name)
+}
+
+// ProfileConfigPath returns the path of a profile's config file
+func ProfileConfigPath(name string) string {
+	return filepath.Join(ProfileDir(name), "config.yaml")
+}
+
+// ValidateProfileName checks that a profile name is safe to use as a directory name
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// LoadProfile loads a profile's config. A new profile gets a template config in its own directory,
+// and any database or token path the config leaves unset defaults to that directory.
+func LoadProfile(name string) (*Config, error) {
+	if err := ValidateProfileName(name); err != nil {
+		return nil, err
+	}
+	return load(ProfileConfigPath(name), name)
+}
+
+// ListProfiles returns the configured profiles: the default profile (if it has a config) followed by
+// the named profiles in alphabetical order
+func ListProfiles() ([]string, error) {
+	var profiles []string
+	if _, err := os.Stat(ProfileConfigPath(DefaultProfile)); err == nil {
+		profiles = append(profiles, DefaultProfile)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(BaseDir(), "profiles"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	var named []string
+	for _, entry := range entries {
+		if !entry.IsDir() || ValidateProfileName(entry.Name()) != nil || entry.Name() == DefaultProfile {
+			continue
+		}
+		if _, err := os.Stat(ProfileConfigPath(entry.Name())); err == nil {
+			named = append(named, entry.Name())
+		}
+	}
+	sort.Strings(named)
+
+	return append(profiles, named...), nil
+}
+
+// CheckProfilesIsolated returns an error if profiles run in one process would share a database,
+// credentials or an API port
+func CheckProfilesIsolated(cfgs []*Config) error {
+	seen := make(map[string]string)
+	claim := func(cfg *Config, kind, value string) error {
+		if value == "" {
+			return nil
+		}
+		key := kind + ":" + expandHome(value)
+		if other, ok := seen[key]; ok {
+			return fmt.Errorf("profiles %s and %s share the same %s (%s)", other, cfg.Profile, kind, value)
+		}
+		seen[key] = cfg.Profile
+		return nil
+	}
+
+	for _, cfg := range cfgs {
+		if err := claim(cfg, "database", cfg.Database.Path); err != nil {
+			return err
+		}
+		if err := claim(cfg, "Google token file", cfg.Google.TokenFile); err != nil {
+			return err
+		}
+		if cfg.MSGraph.Enabled {
+			if err := claim(cfg, "Microsoft Graph token file", cfg.MSGraph.TokenFile); err != nil {
+				return err
+			}
+		}
+		if cfg.API.Enabled {
+			if err := claim(cfg, "API port", fmt.Sprint(cfg.API.Port)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// expandHome expands a leading ~/ so equivalent paths compare equal
+func expandHome(path string) string {
+	if len(path) >= 2 && path[:2] == "~/" {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,258 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// deniedFunctionPrefixes and deniedFunctions block functions that read files, settings or the catalog
+var (
+	deniedFunctionPrefixes = []string{"read_", "duckdb_", "pragma_", "parquet_", "sniff_"}
+	deniedFunctions        = map[string]bool{
+		"getenv": true, "current_setting": true, "query": true, "query_table": true,
+		"glob": true, "getvariable": true,
+	}
+)
+
+// AnalyticsLimits restricts what an analytics query may read and how much work it may do
+type AnalyticsLimits struct {
+	AllowedTables []string      // Tables and views that may appear in FROM clauses
+	MaxRows       int           // Rows returned before the result is truncated
+	Timeout       time.Duration // Statement timeout
+}
+
+// AnalyticsResult is the outcome of an analytics query
+type AnalyticsResult struct {
+	Columns   []string        `json:"columns"`
+	Rows      [][]interface{} `json:"rows"`
+	Truncated bool            `json:"truncated"` // More rows matched than MaxRows
+	Duration  time.Duration   `json:"-"`
+}
+
+// AnalyticsQueryError is returned when a query is rejected by the analytics restrictions
+type AnalyticsQueryError struct {
+	Reason string
+}
+
+func (e *AnalyticsQueryError) Error() string {
+	return fmt.Sprintf("query not allowed: %s", e.Reason)
+}
+
+// RunAnalyticsQuery runs a single read-only SELECT against allowlisted tables, capped at
+// limits.MaxRows rows and limits.Timeout. Queries are parsed by DuckDB and rejected unless every
+// table they reference is allowlisted and they call no table functions.
+func (db *DB) RunAnalyticsQuery(ctx context.Context, query string, limits AnalyticsLimits) (*AnalyticsResult, error) {
+	query = strings.TrimSpace(query)
+	query = strings.TrimSpace(strings.TrimSuffix(query, ";"))
+	if query == "" {
+		return nil, &AnalyticsQueryError{Reason: "query is empty"}
+	}
+
+	// Fetch one extra row to tell whether the result was truncated. The wrapped query is what gets
+	// checked, so nothing in the query can change how the wrapper parses.
+	wrapped := fmt.Sprintf("SELECT * FROM (\n%s\n) AS analytics_query LIMIT %d", query, limits.MaxRows+1)
+	if err := db.checkAnalyticsQuery(ctx, wrapped, limits.AllowedTables); err != nil {
+		return nil, err
+	}
+
+	if limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.Timeout)
+		defer cancel()
+	}
+
+	// The query runs in a transaction that is always rolled back, as a second line of defence
+	// behind the SELECT-only check (DuckDB has no read-only transactions)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin analytics transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	start := time.Now()
+	rows, err := tx.QueryContext(ctx, wrapped)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, &AnalyticsQueryError{Reason: fmt.Sprintf("statement timeout of %s exceeded", limits.Timeout)}
+		}
+		return nil, fmt.Errorf("analytics query failed: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AnalyticsResult{Columns: columns, Rows: [][]interface{}{}}
+	for rows.Next() {
+		if len(result.Rows) == limits.MaxRows {
+			result.Truncated = true
+			break
+		}
+
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	if err := rows.Err(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, &AnalyticsQueryError{Reason: fmt.Sprintf("statement timeout of %s exceeded", limits.Timeout)}
+		}
+		return nil, err
+	}
+
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// checkAnalyticsQuery parses the query with DuckDB and checks it against the restrictions.
+// json_serialize_sql only accepts SELECT statements, so anything else is rejected here.
+func (db *DB) checkAnalyticsQuery(ctx context.Context, query string, allowedTables []string) error {
+	var serialized string
+	if err := db.QueryRowContext(ctx, `SELECT CAST(json_serialize_sql(?::VARCHAR) AS VARCHAR)`, query).Scan(&serialized); err != nil {
+		return fmt.Errorf("failed to parse query: %w", err)
+	}
+
+	var parsed struct {
+		Error        bool          `json:"error"`
+		ErrorMessage string        `json:"error_message"`
+		Statements   []interface{} `json:"statements"`
+	}
+	if err := json.Unmarshal([]byte(serialized), &parsed); err != nil {
+		return fmt.Errorf("failed to decode parsed query: %w", err)
+	}
+	if parsed.Error {
+		return &AnalyticsQueryError{Reason: parsed.ErrorMessage}
+	}
+	if len(parsed.Statements) != 1 {
+		return &AnalyticsQueryError{Reason: "exactly one SELECT statement is allowed"}
+	}
+
+	allowed := make(map[string]bool, len(allowedTables))
+	for _, table := range allowedTables {
+		allowed[strings.ToLower(table)] = true
+	}
+
+	walk := &analyticsWalk{ctes: make(map[string]bool)}
+	walk.visit(parsed.Statements[0])
+	if walk.err != nil {
+		return walk.err
+	}
+
+	// A CTE named after a real table would hide references to that table from the allowlist check
+	existing, err := db.tableNames(ctx)
+	if err != nil {
+		return err
+	}
+	for cte := range walk.ctes {
+		if existing[cte] && !allowed[cte] {
+			return &AnalyticsQueryError{Reason: fmt.Sprintf("CTE %q shadows a table", cte)}
+		}
+	}
+
+	for _, table := range walk.tables {
+		if !allowed[table] && !walk.ctes[table] {
+			return &AnalyticsQueryError{Reason: fmt.Sprintf("table %q is not available to analytics queries", table)}
+		}
+	}
+
+	return nil
+}
+
+// tableNames returns the lowercased names of all tables and views in the database
+func (db *DB) tableNames(ctx context.Context) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, `SELECT lower(table_name) FROM information_schema.tables`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	names := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names[name] = true
+	}
+	return names, rows.Err()
+}
+
+// analyticsWalk collects table references, CTE names and disallowed constructs from a parsed query
+type analyticsWalk struct {
+	tables []string
+	ctes   map[string]bool
+	err    error
+}
+
+func (w *analyticsWalk) visit(node interface{}) {
+	if w.err != nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case []interface{}:
+		for _, child := range n {
+			w.visit(child)
+		}
+
+	case map[string]interface{}:
+		nodeType, _ := n["type"].(string)
+		switch nodeType {
+		case "BASE_TABLE":
+			schema, _ := n["schema_name"].(string)
+			catalog, _ := n["catalog_name"].(string)
+			table, _ := n["table_name"].(string)
+			if catalog != "" || (schema != "" && !strings.EqualFold(schema, "main")) {
+				w.err = &AnalyticsQueryError{Reason: fmt.Sprintf("table %s.%s is not available to analytics queries", schema, table)}
+				return
+			}
+			w.tables = append(w.tables, strings.ToLower(table))
+
+		case "TABLE_FUNCTION":
+			w.err = &AnalyticsQueryError{Reason: "table functions are not allowed"}
+			return
+		}
+
+		if class, _ := n["class"].(string); class == "FUNCTION" {
+			name, _ := n["function_name"].(string)
+			name = strings.ToLower(name)
+			if deniedFunctions[name] {
+				w.err = &AnalyticsQueryError{Reason: fmt.Sprintf("function %s is not allowed", name)}
+				return
+			}
+			for _, prefix := range deniedFunctionPrefixes {
+				if strings.HasPrefix(name, prefix) {
+					w.err = &AnalyticsQueryError{Reason: fmt.Sprintf("function %s is not allowed", name)}
+					return
+				}
+			}
+		}
+
+		if cteMap, ok := n["cte_map"].(map[string]interface{}); ok {
+			if entries, ok := cteMap["map"].([]interface{}); ok {
+				for _, entry := range entries {
+					if e, ok := entry.(map[string]interface{}); ok {
+						if key, ok := e["key"].(string); ok {
+							w.ctes[strings.ToLower(key)] = true
+						}
+					}
+				}
+			}
+		}
+
+		for _, child := range n {
+			w.visit(child)
+		}
+	}
+}
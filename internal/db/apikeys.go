@@ -0,0 +1,151 @@
+package db
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// apiKeyPrefix marks focus-agent API keys, so a leaked one is recognisable
+const apiKeyPrefix = "fa_"
+
+// apiKeyTouchInterval is how stale last_used_at may get before a request updates it, so busy
+// clients don't write on every call
+const apiKeyTouchInterval = time.Minute
+
+// APIKey is a per-client key for the API server. The key itself is only shown when created.
+type APIKey struct {
+	ID         string
+	Name       string // Who or what the key is for, e.g. "laptop"
+	Prefix     string // First characters of the key, to tell keys apart
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// CreateAPIKey generates a key for a client, returning the key, which isn't stored
+func (db *DB) CreateAPIKey(name string) (string, *APIKey, error) {
+	if name == "" {
+		return "", nil, fmt.Errorf("API key name is required")
+	}
+	var existing int
+	err := db.QueryRow(`SELECT CAST(COUNT(*) AS INTEGER) FROM api_keys WHERE name = ? AND revoked_at IS NULL`, name).Scan(&existing)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to check API keys: %w", err)
+	}
+	if existing > 0 {
+		return "", nil, fmt.Errorf("an API key named %q already exists; revoke it first", name)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key := apiKeyPrefix + hex.EncodeToString(secret)
+
+	now := time.Now()
+	apiKey := &APIKey{
+		ID:        fmt.Sprintf("key_%d", now.UnixNano()),
+		Name:      name,
+		Prefix:    key[:len(apiKeyPrefix)+8],
+		CreatedAt: now,
+	}
+	_, err = db.Exec(`
+		INSERT INTO api_keys (id, name, key_hash, prefix, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, apiKey.ID, apiKey.Name, hashAPIKey(key), apiKey.Prefix, now.Unix())
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to save API key: %w", err)
+	}
+	return key, apiKey, nil
+}
+
+// ValidateAPIKey returns the unrevoked key matching a presented key, or nil if there's none
+func (db *DB) ValidateAPIKey(key string) (*APIKey, error) {
+	if len(key) <= len(apiKeyPrefix) || key[:len(apiKeyPrefix)] != apiKeyPrefix {
+		return nil, nil
+	}
+
+	// Looked up by hash, so comparing doesn't leak the key through timing
+	apiKey := &APIKey{}
+	var createdAt int64
+	var lastUsedAt sql.NullInt64
+	err := db.QueryRow(`
+		SELECT id, name, prefix, created_at, last_used_at
+		FROM api_keys
+		WHERE key_hash = ? AND revoked_at IS NULL
+	`, hashAPIKey(key)).Scan(&apiKey.ID, &apiKey.Name, &apiKey.Prefix, &createdAt, &lastUsedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check API key: %w", err)
+	}
+	apiKey.CreatedAt = time.Unix(createdAt, 0)
+
+	now := time.Now()
+	if !lastUsedAt.Valid || now.Sub(time.Unix(lastUsedAt.Int64, 0)) > apiKeyTouchInterval {
+		db.Exec(`UPDATE api_keys SET last_used_at = ? WHERE id = ?`, now.Unix(), apiKey.ID)
+		lastUsedAt = sql.NullInt64{Int64: now.Unix(), Valid: true}
+	}
+	usedAt := time.Unix(lastUsedAt.Int64, 0)
+	apiKey.LastUsedAt = &usedAt
+	return apiKey, nil
+}
+
+// ListAPIKeys returns every key, revoked ones included, newest first
+func (db *DB) ListAPIKeys() ([]*APIKey, error) {
+	rows, err := db.Query(`
+		SELECT id, name, prefix, created_at, last_used_at, revoked_at
+		FROM api_keys
+		ORDER BY created_at DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*APIKey
+	for rows.Next() {
+		apiKey := &APIKey{}
+		var createdAt int64
+		var lastUsedAt, revokedAt sql.NullInt64
+		if err := rows.Scan(&apiKey.ID, &apiKey.Name, &apiKey.Prefix, &createdAt, &lastUsedAt, &revokedAt); err != nil {
+			return nil, err
+		}
+		apiKey.CreatedAt = time.Unix(createdAt, 0)
+		if lastUsedAt.Valid {
+			t := time.Unix(lastUsedAt.Int64, 0)
+			apiKey.LastUsedAt = &t
+		}
+		if revokedAt.Valid {
+			t := time.Unix(revokedAt.Int64, 0)
+			apiKey.RevokedAt = &t
+		}
+		keys = append(keys, apiKey)
+	}
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey stops a client's key from working, by name or prefix
+func (db *DB) RevokeAPIKey(nameOrPrefix string) error {
+	result, err := db.Exec(`
+		UPDATE api_keys SET revoked_at = ?
+		WHERE (name = ? OR prefix = ?) AND revoked_at IS NULL
+	`, time.Now().Unix(), nameOrPrefix, nameOrPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	if revoked, _ := result.RowsAffected(); revoked == 0 {
+		return fmt.Errorf("no active API key named %q", nameOrPrefix)
+	}
+	return nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Attachment is text extracted from a file attached to, or a Google Doc linked from, an email
+type Attachment struct {
+	ID        string    `json:"id"`
+	MessageID string    `json:"message_id"`
+	ThreadID  string    `json:"thread_id"`
+	Filename  string    `json:"filename"`
+	MimeType  string    `json:"mime_type"`
+	SizeBytes int64     `json:"size_bytes"`
+	Text      string    `json:"text"`
+	Error     string    `json:"error,omitempty"` // Why no text could be extracted
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SaveAttachment stores an attachment's extracted text
+func (db *DB) SaveAttachment(attachment *Attachment) error {
+	query := `
+		INSERT INTO attachments (id, message_id, thread_id, filename, mime_type, size_bytes, text, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			text = excluded.text,
+			error = excluded.error
+	`
+
+	_, err := db.Exec(query,
+		attachment.ID, attachment.MessageID, attachment.ThreadID, attachment.Filename,
+		attachment.MimeType, attachment.SizeBytes, attachment.Text, attachment.Error, time.Now().Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save attachment: %w", err)
+	}
+	return nil
+}
+
+// HasAttachment reports whether an attachment has already been processed
+func (db *DB) HasAttachment(id string) bool {
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM attachments WHERE id = ?`, id).Scan(&count); err != nil {
+		return false
+	}
+	return count > 0
+}
+
+// LoadAttachments fills in the attachments that have text on the given messages
+func (db *DB) LoadAttachments(messages []*Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Message, len(messages))
+	placeholders := make([]string, 0, len(messages))
+	args := make([]interface{}, 0, len(messages))
+	for _, msg := range messages {
+		msg.Attachments = nil
+		byID[msg.ID] = msg
+		placeholders = append(placeholders, "?")
+		args = append(args, msg.ID)
+	}
+
+	query := `
+		SELECT id, message_id, thread_id, filename, mime_type, size_bytes, text, error, created_at
+		FROM attachments
+		WHERE message_id IN (` + strings.Join(placeholders, ", ") + `) AND text != ''
+		ORDER BY created_at ASC
+	`
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to load attachments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attachment := &Attachment{}
+		var createdAt int64
+		if err := rows.Scan(&attachment.ID, &attachment.MessageID, &attachment.ThreadID, &attachment.Filename,
+			&attachment.MimeType, &attachment.SizeBytes, &attachment.Text, &attachment.Error, &createdAt); err != nil {
+			return err
+		}
+		attachment.CreatedAt = time.Unix(createdAt, 0)
+		if msg := byID[attachment.MessageID]; msg != nil {
+			msg.Attachments = append(msg.Attachments, attachment)
+		}
+	}
+	return rows.Err()
+}
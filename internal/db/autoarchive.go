@@ -0,0 +1,66 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SourceCount is how many tasks from one source something happened to
+type SourceCount struct {
+	Source string `json:"source"`
+	Count  int    `json:"count"`
+}
+
+// AutoArchiveTasks archives pending tasks from a source that nobody has touched since a time,
+// returning how many were archived. Tasks due in the future, e.g. snoozed ones, are kept until
+// their due date passes. Like ArchiveTasks, archived tasks are stored as cancelled.
+func (db *DB) AutoArchiveTasks(source string, untouchedSince time.Time) (int, error) {
+	now := time.Now().Unix()
+	result, err := db.Exec(`
+		UPDATE tasks SET status = 'cancelled', auto_archived_at = ?, updated_at = ?
+		WHERE status = 'pending' AND LOWER(source) = ? AND updated_at < ?
+		  AND (due_ts IS NULL OR due_ts < ?)
+	`, now, now, source, untouchedSince.Unix(), now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive %s tasks: %w", source, err)
+	}
+
+	archived, _ := result.RowsAffected()
+	return int(archived), nil
+}
+
+// GetAutoArchivedCounts returns how many tasks were archived automatically in a period, by
+// source, most first. Tasks restored since aren't counted.
+func (db *DB) GetAutoArchivedCounts(start, end time.Time) ([]*SourceCount, error) {
+	rows, err := db.Query(`
+		SELECT LOWER(source), CAST(COUNT(*) AS INTEGER)
+		FROM tasks
+		WHERE auto_archived_at >= ? AND auto_archived_at < ? AND status = 'cancelled'
+		GROUP BY LOWER(source)
+		ORDER BY COUNT(*) DESC, LOWER(source)
+	`, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count auto-archived tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []*SourceCount
+	for rows.Next() {
+		count := &SourceCount{}
+		if err := rows.Scan(&count.Source, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// FormatSourceCounts lists counts by source, e.g. "4 calendar, 2 ai"
+func FormatSourceCounts(counts []*SourceCount) string {
+	parts := make([]string, 0, len(counts))
+	for _, count := range counts {
+		parts = append(parts, fmt.Sprintf("%d %s", count.Count, count.Source))
+	}
+	return strings.Join(parts, ", ")
+}
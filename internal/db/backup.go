@@ -0,0 +1,410 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupPrefix and backupSuffix name the database copies Backup writes into a directory
+const (
+	backupPrefix = "focus-agent-"
+	backupSuffix = ".duckdb"
+)
+
+// Snapshot exports the whole database to a new timestamped directory under dir, named after
+// the operation about to run, and returns its path. Restore one with IMPORT DATABASE into an
+// empty database. Only the newest keep snapshots are kept; keep <= 0 keeps them all.
+func (db *DB) Snapshot(dir, label string, keep int) (string, error) {
+	dir, err := expandHome(dir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	path := filepath.Join(dir, time.Now().Format("20060102-150405")+"-"+label)
+	query := fmt.Sprintf(`EXPORT DATABASE '%s' (FORMAT PARQUET)`, strings.ReplaceAll(path, "'", "''"))
+	if _, err := db.Exec(query); err != nil {
+		os.RemoveAll(path)
+		return "", fmt.Errorf("failed to snapshot database to %s: %w", path, err)
+	}
+
+	if keep > 0 {
+		pruneSnapshots(dir, keep)
+	}
+	return path, nil
+}
+
+// pruneSnapshots removes all but the newest keep snapshots in dir. Snapshot names start with
+// their timestamp, so they sort oldest first.
+func pruneSnapshots(dir string, keep int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		log.Printf("Failed to list snapshots in %s: %v", dir, err)
+		return
+	}
+
+	var snapshots []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			snapshots = append(snapshots, entry.Name())
+		}
+	}
+	sort.Strings(snapshots)
+
+	for len(snapshots) > keep {
+		if err := os.RemoveAll(filepath.Join(dir, snapshots[0])); err != nil {
+			log.Printf("Failed to remove old snapshot %s: %v", snapshots[0], err)
+		}
+		snapshots = snapshots[1:]
+	}
+}
+
+// PendingMigrations returns the structured migrations not yet applied, in order
+func PendingMigrations(db *DB) ([]Migration, error) {
+	applied, err := GetAppliedMigrations(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	var pending []Migration
+	for _, migration := range GetMigrations() {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// SnapshotBeforeMigrations snapshots the database if migrations are about to change it,
+// returning the snapshot's path or "" if none was needed. A new database, with no migrations
+// applied yet, has nothing to lose and isn't snapshotted.
+func SnapshotBeforeMigrations(db *DB, dir string, keep int) (string, error) {
+	applied, err := GetAppliedMigrations(db)
+	if err != nil {
+		return "", fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	if len(applied) == 0 {
+		return "", nil
+	}
+
+	pending, err := PendingMigrations(db)
+	if err != nil || len(pending) == 0 {
+		return "", err
+	}
+	return db.Snapshot(dir, fmt.Sprintf("migration-%d", pending[0].Version), keep)
+}
+
+// BackupResult describes a verified database backup
+type BackupResult struct {
+	Path   string
+	Bytes  int64
+	Tables int
+	Rows   int64
+}
+
+// Backup copies the database file to path, or into it with a timestamped name if path is a
+// directory. The database is checkpointed first so the file holds every committed change, and
+// automatic checkpoints are held off while it's copied so the file can't change underneath the
+// copy. The copy is then opened read-only and every table scanned before it's kept.
+func (db *DB) Backup(path string) (*BackupResult, error) {
+	db.backupMu.Lock()
+	defer db.backupMu.Unlock()
+
+	source, err := db.filePath()
+	if err != nil {
+		return nil, err
+	}
+	if path, err = expandHome(path); err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, backupName())
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	var threshold string
+	if err := db.QueryRow(`SELECT current_setting('checkpoint_threshold')`).Scan(&threshold); err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint threshold: %w", err)
+	}
+	if _, err := db.Exec(`SET checkpoint_threshold = '1TB'`); err != nil {
+		return nil, fmt.Errorf("failed to hold off checkpoints: %w", err)
+	}
+	defer func() {
+		if _, err := db.Exec(fmt.Sprintf(`SET checkpoint_threshold = '%s'`, strings.ReplaceAll(threshold, "'", ""))); err != nil {
+			log.Printf("Failed to restore checkpoint threshold: %v", err)
+		}
+	}()
+	if _, err := db.Exec(`FORCE CHECKPOINT`); err != nil {
+		return nil, fmt.Errorf("failed to checkpoint database: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := copyFile(source, tmp); err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+
+	result, err := db.verifyCopy(tmp)
+	if err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("backup failed verification: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to move backup into place: %w", err)
+	}
+	result.Path = path
+	return result, nil
+}
+
+// BackupInto backs the database up into a directory, creating it if needed
+func (db *DB) BackupInto(dir string) (*BackupResult, error) {
+	dir, err := expandHome(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	return db.Backup(filepath.Join(dir, backupName()))
+}
+
+// backupName is the file name of a backup made now. Names sort oldest first.
+func backupName() string {
+	return backupPrefix + time.Now().Format("20060102-150405") + backupSuffix
+}
+
+// filePath returns the file the database is stored in
+func (db *DB) filePath() (string, error) {
+	var path sql.NullString
+	err := db.QueryRow(`SELECT path FROM duckdb_databases() WHERE database_name = current_database()`).Scan(&path)
+	if err != nil {
+		return "", fmt.Errorf("failed to find database file: %w", err)
+	}
+	if !path.Valid || path.String == "" {
+		return "", fmt.Errorf("database is in memory; nothing to back up")
+	}
+	return path.String, nil
+}
+
+// verifyCopy attaches a copy of the database read-only and scans every table in it, so a
+// truncated or corrupt copy fails here rather than on restore
+func (db *DB) verifyCopy(path string) (*BackupResult, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	result := &BackupResult{Bytes: info.Size()}
+
+	// Attaching and detaching must happen on one connection
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	attach := fmt.Sprintf(`ATTACH '%s' AS backup_verify (READ_ONLY)`, strings.ReplaceAll(path, "'", "''"))
+	if _, err := conn.ExecContext(ctx, attach); err != nil {
+		return nil, fmt.Errorf("failed to open copy: %w", err)
+	}
+	defer conn.ExecContext(ctx, `DETACH backup_verify`)
+
+	rows, err := conn.QueryContext(ctx, `
+		SELECT schema_name, table_name FROM duckdb_tables() WHERE database_name = 'backup_verify'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables in copy: %w", err)
+	}
+	var tables [][2]string
+	for rows.Next() {
+		var schema, table string
+		if err := rows.Scan(&schema, &table); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tables = append(tables, [2]string{schema, table})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("copy has no tables")
+	}
+
+	for _, table := range tables {
+		var count int64
+		query := fmt.Sprintf(`SELECT COUNT(*) FROM backup_verify."%s"."%s"`, table[0], table[1])
+		if err := conn.QueryRowContext(ctx, query).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to read %s in copy: %w", table[1], err)
+		}
+		result.Tables++
+		result.Rows += count
+	}
+	return result, nil
+}
+
+// PruneBackups removes all but the newest keep database copies Backup wrote into dir
+func PruneBackups(dir string, keep int) error {
+	dir, err := expandHome(dir)
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to list backups in %s: %w", dir, err)
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, backupPrefix) && strings.HasSuffix(name, backupSuffix) {
+			backups = append(backups, name)
+		}
+	}
+	sort.Strings(backups)
+
+	for len(backups) > keep {
+		if err := os.Remove(filepath.Join(dir, backups[0])); err != nil {
+			return fmt.Errorf("failed to remove old backup %s: %w", backups[0], err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// Restore replaces the database file at dbPath with a backup. The database must not be open.
+// The backup is checked by opening it before anything is touched, and the database it replaces
+// is moved aside rather than deleted; its new path is returned.
+func Restore(backupPath, dbPath string) (string, error) {
+	var err error
+	if backupPath, err = expandHome(backupPath); err != nil {
+		return "", err
+	}
+	if dbPath, err = expandHome(dbPath); err != nil {
+		return "", err
+	}
+
+	if err := checkBackup(backupPath); err != nil {
+		return "", fmt.Errorf("backup %s can't be read: %w", backupPath, err)
+	}
+
+	// Copy next to the database first, so a failed copy leaves it untouched
+	tmp := dbPath + ".restore.tmp"
+	if err := copyFile(backupPath, tmp); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+
+	previous := ""
+	if _, err := os.Stat(dbPath); err == nil {
+		if err := checkNotInUse(dbPath); err != nil {
+			os.Remove(tmp)
+			return "", err
+		}
+		previous = dbPath + ".pre-restore-" + time.Now().Format("20060102-150405")
+		if err := os.Rename(dbPath, previous); err != nil {
+			os.Remove(tmp)
+			return "", fmt.Errorf("failed to move current database aside: %w", err)
+		}
+		// Changes in the write-ahead log belong to the database being replaced
+		if _, err := os.Stat(dbPath + ".wal"); err == nil {
+			if err := os.Rename(dbPath+".wal", previous+".wal"); err != nil {
+				return "", fmt.Errorf("failed to move write-ahead log aside: %w", err)
+			}
+		}
+	}
+
+	if err := os.Rename(tmp, dbPath); err != nil {
+		return previous, fmt.Errorf("failed to move backup into place: %w", err)
+	}
+	return previous, nil
+}
+
+// checkBackup opens a backup read-only and lists its migrations, to tell a usable backup from
+// a file that isn't one
+func checkBackup(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	sqlDB, err := sql.Open("duckdb", path+"?access_mode=read_only")
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	// The embeddings index needs the VSS extension; without it (e.g. offline) the tables
+	// themselves still open
+	sqlDB.Exec(`LOAD vss`)
+
+	var version sql.NullInt64
+	if err := sqlDB.QueryRow(`SELECT MAX(version) FROM migration_versions`).Scan(&version); err != nil {
+		return err
+	}
+	if !version.Valid {
+		return fmt.Errorf("no migrations recorded")
+	}
+	return nil
+}
+
+// checkNotInUse fails if another process has the database open. DuckDB locks the file, so
+// opening it fails while the agent is running.
+func checkNotInUse(path string) error {
+	sqlDB, err := sql.Open("duckdb", path)
+	if err == nil {
+		err = sqlDB.Ping()
+		sqlDB.Close()
+	}
+	if err != nil {
+		return fmt.Errorf("database %s is in use; stop focus-agent before restoring: %w", path, err)
+	}
+	return nil
+}
+
+// copyFile copies a file and syncs the copy to disk
+func copyFile(from, to string) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", from, err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", to, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy to %s: %w", to, err)
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to sync %s: %w", to, err)
+	}
+	return out.Close()
+}
+
+// expandHome expands a leading ~/ in a path
+func expandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, path[2:]), nil
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+)
+
+// ProviderBudgetStatus is an LLM provider's usage today against its configured daily caps
+type ProviderBudgetStatus struct {
+	Provider   string
+	MaxTokens  int     // 0 means no token cap
+	MaxCost    float64 // 0 means no cost cap
+	UsedTokens int
+	UsedCost   float64
+	ResetsAt   time.Time // Next local midnight
+}
+
+// Exhausted reports whether either cap has been reached
+func (s *ProviderBudgetStatus) Exhausted() bool {
+	return (s.MaxTokens > 0 && s.UsedTokens >= s.MaxTokens) ||
+		(s.MaxCost > 0 && s.UsedCost >= s.MaxCost)
+}
+
+// RemainingTokens returns the tokens left today, or -1 when there is no token cap
+func (s *ProviderBudgetStatus) RemainingTokens() int {
+	if s.MaxTokens <= 0 {
+		return -1
+	}
+	return max(s.MaxTokens-s.UsedTokens, 0)
+}
+
+// RemainingCost returns the spend left today, or -1 when there is no cost cap
+func (s *ProviderBudgetStatus) RemainingCost() float64 {
+	if s.MaxCost <= 0 {
+		return -1
+	}
+	return max(s.MaxCost-s.UsedCost, 0)
+}
+
+// ActionQuotaExhausted is logged against an API key when the provider reports its daily quota used up
+const ActionQuotaExhausted = "quota_exhausted"
+
+// KeyUsage is one API key's usage since the start of its quota day
+type KeyUsage struct {
+	Key       string // Label, never the key itself
+	Requests  int
+	Tokens    int
+	Exhausted bool // The provider reported the key's quota used up
+}
+
+// GetKeyUsageSince returns a service's usage per API key since the given time
+func (db *DB) GetKeyUsageSince(service string, since time.Time) (map[string]*KeyUsage, error) {
+	query := `
+		SELECT api_key,
+		       COUNT(*) FILTER (WHERE action != ?),
+		       COALESCE(SUM(tokens), 0),
+		       COUNT(*) FILTER (WHERE action = ?)
+		FROM usage
+		WHERE service = ? AND ts >= ? AND api_key IS NOT NULL
+		GROUP BY api_key
+	`
+	rows, err := db.Query(query, ActionQuotaExhausted, ActionQuotaExhausted, service, since.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query key usage for %s: %w", service, err)
+	}
+	defer rows.Close()
+
+	usage := make(map[string]*KeyUsage)
+	for rows.Next() {
+		key := &KeyUsage{}
+		var exhausted int
+		if err := rows.Scan(&key.Key, &key.Requests, &key.Tokens, &exhausted); err != nil {
+			return nil, err
+		}
+		key.Exhausted = exhausted > 0
+		usage[key.Key] = key
+	}
+	return usage, rows.Err()
+}
+
+// CountModelCallsSince returns how many successful calls were made to one of a service's models
+// since the given time
+func (db *DB) CountModelCallsSince(service, model string, since time.Time) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM usage WHERE service = ? AND model = ? AND COALESCE(error, '') = '' AND ts >= ?`
+	if err := db.QueryRow(query, service, model, since.Unix()).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count %s calls to %s: %w", service, model, err)
+	}
+	return count, nil
+}
+
+// GetProviderBudget returns a provider's usage since local midnight against its budget
+func (db *DB) GetProviderBudget(provider string, budget config.ProviderBudget) (*ProviderBudgetStatus, error) {
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	tokens, cost, err := db.GetServiceUsageSince(provider, midnight)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProviderBudgetStatus{
+		Provider:   provider,
+		MaxTokens:  budget.MaxTokens,
+		MaxCost:    budget.MaxCost,
+		UsedTokens: tokens,
+		UsedCost:   cost,
+		ResetsAt:   midnight.AddDate(0, 0, 1),
+	}, nil
+}
+
+// GetProviderBudgets returns the status of every configured provider budget, sorted by provider
+func (db *DB) GetProviderBudgets(budgets map[string]config.ProviderBudget) ([]*ProviderBudgetStatus, error) {
+	providers := make([]string, 0, len(budgets))
+	for provider := range budgets {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	statuses := make([]*ProviderBudgetStatus, 0, len(providers))
+	for _, provider := range providers {
+		status, err := db.GetProviderBudget(provider, budgets[provider])
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
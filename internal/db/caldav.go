@@ -0,0 +1,54 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// GetCalDAVTasks returns the tasks shared with CalDAV task apps: the user's open tasks, and
+// tasks completed since a time so completions show on other devices before dropping off
+func (db *DB) GetCalDAVTasks(completedSince time.Time) ([]*Task, error) {
+	rows, err := db.Query(`
+		SELECT id, source, source_id, title, description, due_ts, project,
+		       impact, urgency, effort, stakeholder, score, status, metadata,
+		       matched_priorities, created_at, updated_at, completed_at,
+		       COALESCE(recurrence, ''), COALESCE(recurs_from, '')
+		FROM tasks
+		WHERE (status IN ('pending', 'in_progress') OR (status = 'completed' AND completed_at >= ?))
+		  AND (
+		    stakeholder IS NULL
+		    OR stakeholder = ''
+		    OR LOWER(stakeholder) IN ('me', 'you', 'i', 'myself')
+		    OR stakeholder LIKE '%@%'
+		  )
+		ORDER BY score DESC, created_at DESC
+	`, completedSince.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CalDAV tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.loadTaskTags(tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetTaskIDBySource returns the ID of the task created from an item in another system, or ""
+// if there's none
+func (db *DB) GetTaskIDBySource(source, sourceID string) (string, error) {
+	var id string
+	err := db.QueryRow(`SELECT id FROM tasks WHERE source = ? AND source_id = ? LIMIT 1`, source, sourceID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up %s task: %w", source, err)
+	}
+	return id, nil
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Calendar warning kinds
+const (
+	CalendarConflict = "conflict"  // Meetings that overlap
+	CalendarChain    = "chain"     // A long run of back-to-back meetings
+	CalendarLowFocus = "low_focus" // Too little free time left for focus work
+	CalendarDecline  = "decline"   // A meeting worth declining to free up time
+)
+
+// CalendarWarning is an over-scheduling problem with the day's calendar, flagged in the daily
+// brief
+type CalendarWarning struct {
+	Kind    string   `json:"kind"`
+	Events  []*Event `json:"events,omitempty"` // The meetings involved
+	Minutes int      `json:"minutes"`          // Length of a chain, or focus time left
+	Limit   int      `json:"limit,omitempty"`  // Longest chain, or least focus time, allowed in minutes
+	Reason  string   `json:"reason,omitempty"` // Why a meeting could be declined
+}
+
+// Describe summarizes a warning in one line for briefs, e.g.
+// "Double-booked: Standup (9:30 AM) and 1:1 with Bob (9:45 AM)"
+func (w *CalendarWarning) Describe() string {
+	switch w.Kind {
+	case CalendarConflict:
+		return "Double-booked: " + joinEvents(w.Events)
+	case CalendarChain:
+		start := w.Events[0].StartTS
+		return fmt.Sprintf("%s of back-to-back meetings from %s to %s (%d meetings)",
+			formatMinutes(w.Minutes), start.Format("3:04 PM"),
+			start.Add(time.Duration(w.Minutes)*time.Minute).Format("3:04 PM"), len(w.Events))
+	case CalendarLowFocus:
+		return fmt.Sprintf("Only %s of focus time left today (aim for %s)",
+			formatMinutes(w.Minutes), formatMinutes(w.Limit))
+	case CalendarDecline:
+		return fmt.Sprintf("Consider declining %s: %s", joinEvents(w.Events), w.Reason)
+	}
+	return w.Kind
+}
+
+// joinEvents names meetings with their start times
+func joinEvents(events []*Event) string {
+	names := make([]string, 0, len(events))
+	for _, event := range events {
+		names = append(names, fmt.Sprintf("%s (%s)", event.Title, event.StartTS.Format("3:04 PM")))
+	}
+	return strings.Join(names, " and ")
+}
+
+// formatMinutes shows a duration in minutes as e.g. "3h", "1h30m" or "45m"
+func formatMinutes(minutes int) string {
+	d := time.Duration(minutes) * time.Minute
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	default:
+		return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+	}
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"fmt"
+)
+
+// CanaryPrefix starts the IDs of the synthetic threads, messages and tasks the pipeline canary
+// creates, so they can be told apart from real data and cleaned up
+const CanaryPrefix = "canary-"
+
+// DeleteCanaryData removes everything the pipeline canary created: its threads and messages, the
+// tasks extracted from them and the rows derived from both. Tables are cleared children first,
+// outside a transaction, since DuckDB checks foreign keys against rows deleted earlier in the
+// same transaction. It returns how many threads were removed.
+func (db *DB) DeleteCanaryData() (int, error) {
+	like := CanaryPrefix + "%"
+	taskIDs := `SELECT id FROM tasks WHERE id LIKE ? OR source = 'canary'`
+
+	statements := []struct {
+		query string
+		arg   string
+	}{
+		{`DELETE FROM task_tags WHERE task_id IN (` + taskIDs + `)`, like},
+		{`DELETE FROM task_enrichments WHERE task_id IN (` + taskIDs + `)`, like},
+		{`DELETE FROM task_embeddings WHERE task_id IN (` + taskIDs + `)`, like},
+		{`DELETE FROM priority_feedback WHERE task_id IN (` + taskIDs + `)`, like},
+		{`DELETE FROM work_sessions WHERE task_id IN (` + taskIDs + `)`, like},
+		{`DELETE FROM tasks WHERE id LIKE ? OR source = 'canary'`, like},
+		{`DELETE FROM content_embeddings WHERE source_id LIKE ?`, like},
+		{`DELETE FROM thread_insights WHERE thread_id LIKE ?`, like},
+		{`DELETE FROM summary_changes WHERE thread_id LIKE ?`, like},
+		{`DELETE FROM attachments WHERE thread_id LIKE ?`, like},
+		{`DELETE FROM commitments WHERE thread_id LIKE ?`, like},
+		{`DELETE FROM waiting_items WHERE thread_id LIKE ?`, like},
+		{`DELETE FROM messages WHERE thread_id LIKE ?`, like},
+	}
+	for _, s := range statements {
+		if _, err := db.Exec(s.query, s.arg); err != nil {
+			return 0, fmt.Errorf("failed to delete canary data: %w", err)
+		}
+	}
+
+	result, err := db.Exec(`DELETE FROM threads WHERE id LIKE ?`, like)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete canary threads: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// Quick note sources, also used as the source of the tasks captured from them
+const (
+	NoteSourceKeep     = "keep"      // A Google Keep note
+	NoteSourceInboxDoc = "inbox_doc" // A paragraph of the Google Doc used as an inbox
+)
+
+// CapturedNote is a quick note to turn into tasks
+type CapturedNote struct {
+	ID     string `json:"id"` // Keep resource name, or inbox doc ID and a hash of the text
+	Source string `json:"source"`
+	Title  string `json:"title"`
+	Text   string `json:"text"`
+}
+
+// GetCapturedNoteIDs returns the IDs of the notes from a source that have been processed already
+func (db *DB) GetCapturedNoteIDs(source string) (map[string]bool, error) {
+	rows, err := db.Query(`SELECT id FROM captured_notes WHERE source = ?`, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query captured notes: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+	return ids, rows.Err()
+}
+
+// SaveCapturedNote records that a note has been processed and how many tasks it became
+func (db *DB) SaveCapturedNote(note *CapturedNote, tasks int) error {
+	_, err := db.Exec(`
+		INSERT INTO captured_notes (id, source, title, tasks, captured_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO NOTHING
+	`, note.ID, note.Source, note.Title, tasks, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to save captured note: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TaskSourceGoogleChat is the source of tasks extracted from Chat messages. Their source ID is
+// the Chat thread they were extracted from (spaces/{space}/threads/{thread}).
+const TaskSourceGoogleChat = "google_chat"
+
+// ChatMessage is a message read from a Google Chat space or DM under chat.sync
+type ChatMessage struct {
+	ID         string    `json:"id"`          // Resource name, spaces/{space}/messages/{message}
+	Space      string    `json:"space"`       // spaces/{space}
+	SpaceName  string    `json:"space_name"`  // Display name; empty for direct messages
+	Thread     string    `json:"thread"`      // spaces/{space}/threads/{thread}
+	Sender     string    `json:"sender"`      // users/{user}
+	SenderName string    `json:"sender_name"` // Display name, when Chat provides one
+	FromUser   bool      `json:"from_user"`   // Sent by the user
+	Text       string    `json:"text"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// Describe returns the message as a transcript line, e.g. "[Tue 3:04 PM] Alice Chen: Can you..."
+func (m *ChatMessage) Describe() string {
+	sender := m.SenderName
+	switch {
+	case m.FromUser:
+		sender = "Me"
+	case sender == "":
+		sender = "Someone"
+	}
+	return fmt.Sprintf("[%s] %s: %s", m.Timestamp.Format("Mon 3:04 PM"), sender, m.Text)
+}
+
+// ChatThreadLink returns the link that opens a Chat thread, given its resource name, or the space
+// for a space's resource name
+func ChatThreadLink(name string) string {
+	parts := strings.Split(name, "/")
+	if len(parts) < 2 || parts[0] != "spaces" {
+		return ""
+	}
+	if len(parts) == 4 && parts[2] == "threads" {
+		return fmt.Sprintf("https://chat.google.com/room/%s/%s", parts[1], parts[3])
+	}
+	return "https://chat.google.com/room/" + parts[1]
+}
+
+// SaveChatMessage saves a Chat message, updating its text if it was edited since it was read
+func (db *DB) SaveChatMessage(msg *ChatMessage) error {
+	// Note: space and thread are indexed, so they can't be updated in ON CONFLICT DO UPDATE; a
+	// message never moves anyway
+	_, err := db.Exec(`
+		INSERT INTO chat_messages (id, space, space_name, thread, sender, sender_name, from_user, text, ts)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			space_name = excluded.space_name,
+			sender_name = excluded.sender_name,
+			text = excluded.text
+	`, msg.ID, msg.Space, msg.SpaceName, msg.Thread, msg.Sender, msg.SenderName, msg.FromUser,
+		msg.Text, msg.Timestamp.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to save chat message: %w", err)
+	}
+	return nil
+}
+
+// GetLatestChatMessageTime returns when the newest stored message in a space was sent, or the
+// zero time if none is stored
+func (db *DB) GetLatestChatMessageTime(space string) (time.Time, error) {
+	var ts int64
+	err := db.QueryRow(`SELECT COALESCE(MAX(ts), 0) FROM chat_messages WHERE space = ?`, space).Scan(&ts)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get latest chat message: %w", err)
+	}
+	if ts == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(ts, 0), nil
+}
+
+// GetUnprocessedChatMessages returns messages that haven't been through task extraction yet,
+// oldest first across all spaces, so a space whose messages keep failing extraction can't fill
+// every batch
+func (db *DB) GetUnprocessedChatMessages(limit int) ([]*ChatMessage, error) {
+	return db.queryChatMessages(`
+		WHERE processed_at IS NULL
+		ORDER BY ts, id
+		LIMIT ?
+	`, limit)
+}
+
+// GetChatMessagesBefore returns the last messages in a space sent before a time, oldest first
+func (db *DB) GetChatMessagesBefore(space string, before time.Time, limit int) ([]*ChatMessage, error) {
+	return db.queryLatestChatMessages(`WHERE space = ? AND ts < ?`, space, before.Unix(), limit)
+}
+
+// GetChatConversation returns the last messages of a Chat thread, oldest first. A thread of a
+// single message, as in DMs and spaces without threads, comes with the messages before it in its
+// space, up to limit in all.
+func (db *DB) GetChatConversation(thread string, limit int) ([]*ChatMessage, error) {
+	messages, err := db.queryLatestChatMessages(`WHERE thread = ?`, thread, limit)
+	if err != nil || len(messages) != 1 {
+		return messages, err
+	}
+
+	earlier, err := db.GetChatMessagesBefore(messages[0].Space, messages[0].Timestamp, limit-1)
+	if err != nil {
+		return nil, err
+	}
+	return append(earlier, messages...), nil
+}
+
+// MarkChatMessagesProcessed records that messages have been through task extraction
+func (db *DB) MarkChatMessagesProcessed(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	args := []interface{}{time.Now().Unix()}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	_, err := db.Exec(`UPDATE chat_messages SET processed_at = ? WHERE id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to mark chat messages processed: %w", err)
+	}
+	return nil
+}
+
+// queryLatestChatMessages returns the last messages matching conditions, oldest first. The
+// limit is the last argument.
+func (db *DB) queryLatestChatMessages(conditions string, args ...interface{}) ([]*ChatMessage, error) {
+	messages, err := db.queryChatMessages(conditions+` ORDER BY ts DESC, id DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+func (db *DB) queryChatMessages(conditions string, args ...interface{}) ([]*ChatMessage, error) {
+	rows, err := db.Query(`
+		SELECT id, space, COALESCE(space_name, ''), COALESCE(thread, ''), COALESCE(sender, ''),
+		       COALESCE(sender_name, ''), from_user, COALESCE(text, ''), ts
+		FROM chat_messages
+	`+conditions, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chat messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*ChatMessage
+	for rows.Next() {
+		msg := &ChatMessage{}
+		var ts int64
+		if err := rows.Scan(&msg.ID, &msg.Space, &msg.SpaceName, &msg.Thread, &msg.Sender,
+			&msg.SenderName, &msg.FromUser, &msg.Text, &ts); err != nil {
+			return nil, err
+		}
+		msg.Timestamp = time.Unix(ts, 0)
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
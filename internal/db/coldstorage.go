@@ -0,0 +1,145 @@
+package db
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// coldFilePrefix names the parquet files MoveToColdStorage writes message bodies into
+const coldFilePrefix = "messages-"
+
+// MoveToColdStorage moves the bodies of messages sent before a time out of the database into a
+// new ZSTD-compressed parquet file under dir, returning the file and how many bodies it holds.
+// Subjects, snippets and thread summaries stay in the database; the bodies are read back from
+// the file when a thread's messages are loaded. Nothing is written when no body is old enough.
+func (db *DB) MoveToColdStorage(dir string, olderThan time.Time) (string, int, error) {
+	dir, err := expandHome(dir)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", 0, fmt.Errorf("failed to create cold storage directory: %w", err)
+	}
+
+	path, err := filepath.Abs(filepath.Join(dir, coldFilePrefix+time.Now().Format("20060102-150405")+".parquet"))
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return "", 0, fmt.Errorf("%s already exists", path)
+	}
+	file := quoteLiteral(path)
+
+	_, err = db.Exec(fmt.Sprintf(`
+		COPY (
+			SELECT id, thread_id, body FROM messages
+			WHERE body IS NOT NULL AND body != '' AND ts < %d
+			ORDER BY thread_id, id
+		) TO %s (FORMAT PARQUET, COMPRESSION ZSTD)
+	`, olderThan.Unix(), file))
+	if err != nil {
+		os.Remove(path)
+		return "", 0, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	// Only bodies that made it into the file are cleared
+	var moved int
+	if err := db.QueryRow(`SELECT CAST(COUNT(*) AS INTEGER) FROM read_parquet(` + file + `)`).Scan(&moved); err != nil {
+		os.Remove(path)
+		return "", 0, fmt.Errorf("failed to read back %s: %w", path, err)
+	}
+	if moved == 0 {
+		os.Remove(path)
+		return "", 0, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return "", 0, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO cold_messages (message_id, file, archived_at)
+		SELECT id, ?, ? FROM read_parquet(`+file+`)
+		ON CONFLICT (message_id) DO UPDATE SET file = excluded.file, archived_at = excluded.archived_at
+	`, path, time.Now().Unix())
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to record cold messages: %w", err)
+	}
+	_, err = tx.Exec(`UPDATE messages SET body = '' WHERE id IN (SELECT id FROM read_parquet(` + file + `))`)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to clear message bodies: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		os.Remove(path)
+		return "", 0, err
+	}
+
+	// Let DuckDB reuse the space the bodies took
+	if _, err := db.Exec(`CHECKPOINT`); err != nil {
+		log.Printf("Failed to checkpoint after moving bodies to cold storage: %v", err)
+	}
+	return path, moved, nil
+}
+
+// restoreColdBodies reads the bodies of messages moved to cold storage back from their files.
+// A missing or unreadable file is logged and its messages are left with only their snippet.
+func (db *DB) restoreColdBodies(messages []*Message) error {
+	byID := make(map[string]*Message)
+	var ids []interface{}
+	for _, msg := range messages {
+		if msg.Body == "" {
+			byID[msg.ID] = msg
+			ids = append(ids, msg.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	rows, err := db.Query(`SELECT message_id, file FROM cold_messages WHERE message_id IN (`+placeholders+`)`, ids...)
+	if err != nil {
+		return fmt.Errorf("failed to query cold messages: %w", err)
+	}
+	files := make(map[string][]interface{})
+	for rows.Next() {
+		var id, file string
+		if err := rows.Scan(&id, &file); err != nil {
+			rows.Close()
+			return err
+		}
+		files[file] = append(files[file], id)
+	}
+	rows.Close()
+
+	for file, fileIDs := range files {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(fileIDs)), ", ")
+		rows, err := db.Query(`SELECT id, body FROM read_parquet(`+quoteLiteral(file)+`) WHERE id IN (`+placeholders+`)`, fileIDs...)
+		if err != nil {
+			log.Printf("Failed to read message bodies from cold storage %s: %v", file, err)
+			continue
+		}
+		for rows.Next() {
+			var id, body string
+			if err := rows.Scan(&id, &body); err != nil {
+				rows.Close()
+				return err
+			}
+			byID[id].Body = body
+		}
+		rows.Close()
+	}
+	return nil
+}
+
+// quoteLiteral quotes a string as a SQL literal, for the file paths COPY and read_parquet
+// can't take as parameters
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
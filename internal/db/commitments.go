@@ -0,0 +1,216 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Commitment statuses
+const (
+	CommitmentOpen      = "open"      // Not delivered yet
+	CommitmentDelivered = "delivered" // The user followed up in the thread
+	CommitmentDismissed = "dismissed" // The user stopped tracking it
+	CommitmentNone      = "none"      // The sent message promised nothing
+)
+
+// Commitment kinds, which decide what closes a commitment
+const (
+	CommitmentReply      = "reply"      // Any later message from the user in the thread
+	CommitmentAttachment = "attachment" // A later message from the user with a file or linked doc
+)
+
+// Commitment is something the user promised in an email they sent
+type Commitment struct {
+	ID        string     `json:"id"`
+	MessageID string     `json:"message_id"` // The sent message
+	ThreadID  string     `json:"thread_id"`
+	Subject   string     `json:"subject"`
+	Recipient string     `json:"recipient"`
+	Promise   string     `json:"promise"` // What was promised
+	Kind      string     `json:"kind"`
+	SentAt    time.Time  `json:"sent_at"`
+	DueTS     *time.Time `json:"due_ts,omitempty"` // When it was promised by, if the email said
+	Status    string     `json:"status"`
+}
+
+// Overdue reports whether the promised date has passed without delivery
+func (c *Commitment) Overdue(now time.Time) bool {
+	return c.Status == CommitmentOpen && c.DueTS != nil && !now.Before(*c.DueTS)
+}
+
+// Describe summarizes a commitment in one line for briefs, e.g.
+// "Send the Q3 deck to bob@example.com (due Fri Oct 17, overdue)"
+func (c *Commitment) Describe(now time.Time) string {
+	text := c.Promise
+	if c.Recipient != "" {
+		text += " to " + c.Recipient
+	}
+	switch {
+	case c.Overdue(now):
+		text += fmt.Sprintf(" (due %s, overdue)", c.DueTS.Format("Mon Jan 2"))
+	case c.DueTS != nil:
+		text += fmt.Sprintf(" (due %s)", c.DueTS.Format("Mon Jan 2"))
+	default:
+		text += fmt.Sprintf(" (promised %s)", c.SentAt.Format("Mon Jan 2"))
+	}
+	return text
+}
+
+const commitmentColumns = `id, message_id, thread_id, COALESCE(subject, ''), COALESCE(recipient, ''),
+	COALESCE(promise, ''), COALESCE(kind, ''), sent_ts, due_ts, status`
+
+// GetSentMessagesForCommitments returns messages the user sent since a time that haven't been
+// checked for commitments yet, newest first
+func (db *DB) GetSentMessagesForCommitments(userEmail string, since time.Time, limit int) ([]*Message, error) {
+	userEmail = strings.ToLower(strings.TrimSpace(userEmail))
+	if userEmail == "" {
+		return nil, fmt.Errorf("user email is required to find sent mail")
+	}
+
+	query := `
+		SELECT m.id, m.thread_id, COALESCE(m.from_addr, ''), COALESCE(m.to_addr, ''),
+		       COALESCE(m.subject, ''), COALESCE(m.snippet, ''), COALESCE(m.body, ''), m.ts
+		FROM messages m
+		WHERE m.ts >= ?
+		  AND lower(COALESCE(m.from_addr, '')) LIKE ?
+		  AND m.id NOT IN (SELECT message_id FROM commitments)
+		ORDER BY m.ts DESC
+		LIMIT ?
+	`
+	rows, err := db.Query(query, since.Unix(), "%"+userEmail+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sent messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*Message
+	for rows.Next() {
+		msg := &Message{}
+		var ts int64
+		if err := rows.Scan(&msg.ID, &msg.ThreadID, &msg.From, &msg.To, &msg.Subject, &msg.Snippet,
+			&msg.Body, &ts); err != nil {
+			return nil, err
+		}
+		msg.Timestamp = time.Unix(ts, 0)
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
+
+// SaveCommitments records the commitments found in a checked sent message. A message that
+// promised nothing is saved as one row with status none so it isn't checked again.
+func (db *DB) SaveCommitments(msg *Message, commitments []*Commitment) error {
+	if len(commitments) == 0 {
+		commitments = []*Commitment{{ID: msg.ID, Status: CommitmentNone}}
+	}
+
+	query := `
+		INSERT INTO commitments (id, message_id, thread_id, subject, recipient, promise, kind, sent_ts,
+		                         due_ts, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO NOTHING
+	`
+	now := time.Now().Unix()
+	for i, commitment := range commitments {
+		if commitment.ID == "" {
+			commitment.ID = fmt.Sprintf("%s:%d", msg.ID, i)
+		}
+		commitment.MessageID = msg.ID
+		commitment.ThreadID = msg.ThreadID
+		commitment.Subject = msg.Subject
+		commitment.SentAt = msg.Timestamp
+
+		var dueTS interface{}
+		if commitment.DueTS != nil {
+			dueTS = commitment.DueTS.Unix()
+		}
+		_, err := db.Exec(query, commitment.ID, msg.ID, msg.ThreadID, msg.Subject, commitment.Recipient,
+			commitment.Promise, commitment.Kind, msg.Timestamp.Unix(), dueTS, commitment.Status, now)
+		if err != nil {
+			return fmt.Errorf("failed to save commitment: %w", err)
+		}
+	}
+	return nil
+}
+
+// ResolveDeliveredCommitments marks open commitments delivered once the user has written in
+// their thread since making them, with a file or linked doc for those that promised one
+func (db *DB) ResolveDeliveredCommitments(userEmail string) (int, error) {
+	userEmail = strings.ToLower(strings.TrimSpace(userEmail))
+	if userEmail == "" {
+		return 0, fmt.Errorf("user email is required to find sent mail")
+	}
+
+	query := `
+		UPDATE commitments SET status = ?, closed_at = ?
+		WHERE status = ?
+		  AND EXISTS (
+			SELECT 1 FROM messages r
+			WHERE r.thread_id = commitments.thread_id AND r.ts > commitments.sent_ts
+			  AND lower(COALESCE(r.from_addr, '')) LIKE ?
+			  AND (commitments.kind != ? OR EXISTS (SELECT 1 FROM attachments a WHERE a.message_id = r.id))
+		  )
+	`
+	result, err := db.Exec(query, CommitmentDelivered, time.Now().Unix(), CommitmentOpen, "%"+userEmail+"%",
+		CommitmentAttachment)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve commitments: %w", err)
+	}
+	resolved, _ := result.RowsAffected()
+	return int(resolved), nil
+}
+
+// GetOpenCommitments returns commitments not delivered yet, those with the soonest promised
+// date first and undated ones oldest first after them. limit <= 0 returns them all.
+func (db *DB) GetOpenCommitments(limit int) ([]*Commitment, error) {
+	query := `
+		SELECT ` + commitmentColumns + `
+		FROM commitments
+		WHERE status = ?
+		ORDER BY due_ts ASC NULLS LAST, sent_ts ASC
+	`
+	args := []interface{}{CommitmentOpen}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query commitments: %w", err)
+	}
+	defer rows.Close()
+
+	var commitments []*Commitment
+	for rows.Next() {
+		commitment := &Commitment{}
+		var sentTS int64
+		var dueTS sql.NullInt64
+		if err := rows.Scan(&commitment.ID, &commitment.MessageID, &commitment.ThreadID, &commitment.Subject,
+			&commitment.Recipient, &commitment.Promise, &commitment.Kind, &sentTS, &dueTS, &commitment.Status); err != nil {
+			return nil, err
+		}
+		commitment.SentAt = time.Unix(sentTS, 0)
+		if dueTS.Valid {
+			t := time.Unix(dueTS.Int64, 0)
+			commitment.DueTS = &t
+		}
+		commitments = append(commitments, commitment)
+	}
+	return commitments, rows.Err()
+}
+
+// DismissCommitment stops tracking a commitment
+func (db *DB) DismissCommitment(id string) error {
+	result, err := db.Exec(`UPDATE commitments SET status = ?, closed_at = ? WHERE id = ? AND status = ?`,
+		CommitmentDismissed, time.Now().Unix(), id, CommitmentOpen)
+	if err != nil {
+		return fmt.Errorf("failed to dismiss commitment: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("commitment not found: %s", id)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ContactInteraction is an email thread exchanged with a contact, described by its latest message
+type ContactInteraction struct {
+	ThreadID      string
+	Subject       string
+	Summary       string
+	Snippet       string
+	LastFrom      string
+	LastTS        time.Time
+	AwaitingReply bool // The user sent the latest message and is waiting on the contact
+
+	Insights *ThreadInsights // Deep summary fields, nil unless the thread has one (meeting prep only)
+}
+
+// ContactHistory is everything known about a contact, assembled for a relationship brief
+type ContactHistory struct {
+	Email         string
+	Interactions  []*ContactInteraction // Most recent first
+	YouOwe        []*Task               // Open tasks linked to the contact
+	TheyOwe       []*ContactInteraction // Threads where the contact owes a reply
+	FrontComments []*FrontComment       // Internal Front comments on the contact's threads
+	FrontTags     []string
+}
+
+// GetContactHistory collects recent threads, open commitments and Front data for a contact.
+// userEmail identifies messages sent by the user; limit caps the interactions returned.
+func (db *DB) GetContactHistory(email, userEmail string, limit int) (*ContactHistory, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return nil, fmt.Errorf("contact email is required")
+	}
+	pattern := "%" + email + "%"
+
+	// Latest message of every thread the contact took part in
+	query := `
+		WITH contact_threads AS (
+			SELECT DISTINCT thread_id FROM messages
+			WHERE lower(from_addr) LIKE ? OR lower(to_addr) LIKE ?
+		),
+		latest AS (
+			SELECT m.thread_id, m.subject, m.snippet, m.from_addr, m.ts,
+			       ROW_NUMBER() OVER (PARTITION BY m.thread_id ORDER BY m.ts DESC) AS rn
+			FROM messages m
+			INNER JOIN contact_threads ct ON ct.thread_id = m.thread_id
+		)
+		SELECT l.thread_id, COALESCE(l.subject, ''), COALESCE(l.snippet, ''),
+		       COALESCE(l.from_addr, ''), l.ts, COALESCE(t.summary, '')
+		FROM latest l
+		LEFT JOIN threads t ON t.id = l.thread_id
+		WHERE l.rn = 1
+		ORDER BY l.ts DESC
+		LIMIT 50
+	`
+
+	rows, err := db.Query(query, pattern, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contact threads: %w", err)
+	}
+	defer rows.Close()
+
+	history := &ContactHistory{Email: email}
+	var threadIDs []string
+	userEmail = strings.ToLower(userEmail)
+
+	for rows.Next() {
+		interaction := &ContactInteraction{}
+		var ts int64
+		if err := rows.Scan(&interaction.ThreadID, &interaction.Subject, &interaction.Snippet,
+			&interaction.LastFrom, &ts, &interaction.Summary); err != nil {
+			return nil, err
+		}
+		interaction.LastTS = time.Unix(ts, 0)
+		interaction.AwaitingReply = userEmail != "" && strings.Contains(strings.ToLower(interaction.LastFrom), userEmail)
+
+		threadIDs = append(threadIDs, interaction.ThreadID)
+		if len(history.Interactions) < limit {
+			history.Interactions = append(history.Interactions, interaction)
+		}
+		if interaction.AwaitingReply {
+			history.TheyOwe = append(history.TheyOwe, interaction)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Open tasks extracted from the contact's threads or naming them as stakeholder
+	taskQuery := `
+		SELECT id, source, source_id, title, description, due_ts, project,
+		       impact, urgency, effort, stakeholder, score, status, metadata,
+		       matched_priorities, created_at, updated_at, completed_at,
+		       COALESCE(recurrence, ''), COALESCE(recurs_from, '')
+		FROM tasks
+		WHERE status IN ('pending', 'in_progress')
+		  AND (lower(stakeholder) LIKE ?
+		       OR (source = 'gmail' AND source_id IN (
+		           SELECT DISTINCT thread_id FROM messages
+		           WHERE lower(from_addr) LIKE ? OR lower(to_addr) LIKE ?)))
+		ORDER BY score DESC
+		LIMIT 20
+	`
+	taskRows, err := db.Query(taskQuery, pattern, pattern, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query contact tasks: %w", err)
+	}
+	defer taskRows.Close()
+
+	if history.YouOwe, err = scanTasks(taskRows); err != nil {
+		return nil, err
+	}
+
+	// Front comments and tags give the team's view of the relationship
+	seenTags := make(map[string]bool)
+	for _, threadID := range threadIDs {
+		if len(history.FrontComments) >= 10 {
+			break
+		}
+		if comments, err := db.GetFrontComments(threadID); err == nil {
+			history.FrontComments = append(history.FrontComments, comments...)
+		}
+		if metadata, err := db.GetFrontMetadata(threadID); err == nil && metadata != nil {
+			for _, tag := range metadata.Tags {
+				if !seenTags[tag] {
+					seenTags[tag] = true
+					history.FrontTags = append(history.FrontTags, tag)
+				}
+			}
+		}
+	}
+
+	return history, nil
+}
+
+// HasRelationshipBrief reports whether a brief was already sent for this event and contact
+func (db *DB) HasRelationshipBrief(eventID, contact string) (bool, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM relationship_briefs WHERE event_id = ? AND contact = ?`
+	if err := db.QueryRow(query, eventID, strings.ToLower(contact)).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// SaveRelationshipBrief records a sent relationship brief
+func (db *DB) SaveRelationshipBrief(eventID, contact, brief string) error {
+	query := `
+		INSERT INTO relationship_briefs (event_id, contact, brief, sent_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (event_id, contact) DO UPDATE SET brief = excluded.brief, sent_at = excluded.sent_at
+	`
+	if _, err := db.Exec(query, eventID, strings.ToLower(contact), brief, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to save relationship brief: %w", err)
+	}
+	return nil
+}
+
+// GetContactPersona returns the drafting persona last used when replying to a contact, or ""
+// if none has been picked for them
+func (db *DB) GetContactPersona(email string) (string, error) {
+	var persona sql.NullString
+	query := `SELECT draft_persona FROM contacts WHERE email = ?`
+	err := db.QueryRow(query, strings.ToLower(strings.TrimSpace(email))).Scan(&persona)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get contact persona: %w", err)
+	}
+	return persona.String, nil
+}
+
+// SetContactPersona remembers the drafting persona to use by default when replying to a contact
+func (db *DB) SetContactPersona(email, persona string) error {
+	query := `
+		INSERT INTO contacts (email, draft_persona, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (email) DO UPDATE SET draft_persona = excluded.draft_persona, updated_at = excluded.updated_at
+	`
+	if _, err := db.Exec(query, strings.ToLower(strings.TrimSpace(email)), persona, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to save contact persona: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// Task fields that can be corrected after extraction
+const (
+	CorrectionDue         = "due"
+	CorrectionImpact      = "impact"
+	CorrectionStakeholder = "stakeholder"
+)
+
+// TaskCorrection is a fix to a field the LLM guessed when extracting a task
+type TaskCorrection struct {
+	ID          string    `json:"id"`
+	TaskID      string    `json:"task_id"`
+	Source      string    `json:"source"` // The task's source, e.g. gmail
+	Field       string    `json:"field"`
+	OldValue    string    `json:"old_value"`
+	NewValue    string    `json:"new_value"`
+	CorrectedAt time.Time `json:"corrected_at"`
+}
+
+// CorrectionStat summarizes the corrections to one field of tasks from one source
+type CorrectionStat struct {
+	Field    string  `json:"field"`
+	Source   string  `json:"source"`
+	Count    int     `json:"count"`
+	AvgDelta float64 `json:"avg_delta"` // Mean new minus old value, for numeric fields
+}
+
+// SaveTaskCorrection records a corrected task field
+func (db *DB) SaveTaskCorrection(c *TaskCorrection) error {
+	if c.CorrectedAt.IsZero() {
+		c.CorrectedAt = time.Now()
+	}
+	if c.ID == "" {
+		c.ID = fmt.Sprintf("corr_%s_%s_%d", c.TaskID, c.Field, c.CorrectedAt.UnixNano())
+	}
+
+	query := `
+		INSERT INTO task_corrections (id, task_id, source, field, old_value, new_value, corrected_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	if _, err := db.Exec(query, c.ID, c.TaskID, c.Source, c.Field, c.OldValue, c.NewValue, c.CorrectedAt.Unix()); err != nil {
+		return fmt.Errorf("failed to save task correction: %w", err)
+	}
+	return nil
+}
+
+// GetCorrectionStats summarizes corrections since the given time by field and task source
+func (db *DB) GetCorrectionStats(since time.Time) ([]*CorrectionStat, error) {
+	query := `
+		SELECT field, COALESCE(source, ''), COUNT(*),
+		       COALESCE(AVG(TRY_CAST(new_value AS DOUBLE) - TRY_CAST(old_value AS DOUBLE)), 0)
+		FROM task_corrections
+		WHERE corrected_at >= ?
+		GROUP BY field, source
+		ORDER BY COUNT(*) DESC, field
+	`
+
+	rows, err := db.Query(query, since.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query correction stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*CorrectionStat
+	for rows.Next() {
+		stat := &CorrectionStat{}
+		if err := rows.Scan(&stat.Field, &stat.Source, &stat.Count, &stat.AvgDelta); err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
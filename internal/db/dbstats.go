@@ -0,0 +1,76 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// statsTables are the tables whose row counts are reported in database stats
+var statsTables = []string{"threads", "messages", "cold_messages", "tasks", "events", "docs", "usage", "llm_cache", "errors"}
+
+// DatabaseStats describes the size and contents of the database
+type DatabaseStats struct {
+	Size          string         `json:"size"`     // On-disk size as DuckDB reports it, e.g. "48.2 MiB"
+	WALSize       string         `json:"wal_size"` // Write-ahead log not yet checkpointed
+	SchemaVersion int            `json:"schema_version"`
+	Rows          map[string]int `json:"rows"`
+}
+
+// GetDatabaseStats returns the database's size, schema version and row counts of its main tables
+func (db *DB) GetDatabaseStats() (*DatabaseStats, error) {
+	stats := &DatabaseStats{Rows: make(map[string]int)}
+
+	err := db.QueryRow(`SELECT database_size, wal_size FROM pragma_database_size()`).Scan(&stats.Size, &stats.WALSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database size: %w", err)
+	}
+
+	err = db.QueryRow(`SELECT CAST(COALESCE(MAX(version), 0) AS INTEGER) FROM migration_versions`).Scan(&stats.SchemaVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	for _, table := range statsTables {
+		var count int
+		if err := db.QueryRow(fmt.Sprintf(`SELECT CAST(COUNT(*) AS INTEGER) FROM %s`, table)).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", table, err)
+		}
+		stats.Rows[table] = count
+	}
+	return stats, nil
+}
+
+// DiskUsage is how much space the database takes up and how much is left on its disk
+type DiskUsage struct {
+	DBBytes   int64  `json:"db_bytes"`   // Database file and its write-ahead log
+	FreeBytes uint64 `json:"free_bytes"` // Available to the agent on the database's file system
+}
+
+// GetDiskUsage measures the database file and the free space on the disk it's stored on
+func (db *DB) GetDiskUsage() (*DiskUsage, error) {
+	path, err := db.filePath()
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &DiskUsage{}
+	for _, file := range []string{path, path + ".wal"} {
+		info, err := os.Stat(file)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to stat %s: %w", file, err)
+		}
+		usage.DBBytes += info.Size()
+	}
+
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(filepath.Dir(path), &fs); err != nil {
+		return nil, fmt.Errorf("failed to check free disk space: %w", err)
+	}
+	usage.FreeBytes = uint64(fs.Bavail) * uint64(fs.Bsize)
+	return usage, nil
+}
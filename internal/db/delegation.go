@@ -0,0 +1,122 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OwnerAuthor is the author recorded on comments left by the agent's owner rather than the delegate
+const OwnerAuthor = "owner"
+
+// TaskComment is a note left on a delegated task
+type TaskComment struct {
+	ID        string    `json:"id"`
+	TaskID    string    `json:"task_id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ClaimTask marks a task as being worked by the delegate
+func (db *DB) ClaimTask(taskID, claimedBy string) error {
+	query := `INSERT OR REPLACE INTO task_claims (task_id, claimed_by, claimed_at) VALUES (?, ?, ?)`
+	if _, err := db.Exec(query, taskID, claimedBy, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to claim task: %w", err)
+	}
+	return nil
+}
+
+// UnclaimTask hands a claimed task back
+func (db *DB) UnclaimTask(taskID string) error {
+	if _, err := db.Exec(`DELETE FROM task_claims WHERE task_id = ?`, taskID); err != nil {
+		return fmt.Errorf("failed to unclaim task: %w", err)
+	}
+	return nil
+}
+
+// AddTaskComment leaves a note on a task
+func (db *DB) AddTaskComment(taskID, author, body string) (*TaskComment, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, fmt.Errorf("comment is empty")
+	}
+
+	now := time.Now()
+	comment := &TaskComment{
+		ID:        fmt.Sprintf("comment_%s_%d", taskID, now.UnixNano()),
+		TaskID:    taskID,
+		Author:    author,
+		Body:      body,
+		CreatedAt: now,
+	}
+
+	query := `INSERT INTO task_comments (id, task_id, author, body, created_at) VALUES (?, ?, ?, ?, ?)`
+	if _, err := db.Exec(query, comment.ID, comment.TaskID, comment.Author, comment.Body, now.Unix()); err != nil {
+		return nil, fmt.Errorf("failed to save comment: %w", err)
+	}
+	return comment, nil
+}
+
+// loadTaskDelegation fills in the claims and comments of the given tasks
+func (db *DB) loadTaskDelegation(tasks []*Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Task, len(tasks))
+	placeholders := make([]string, 0, len(tasks))
+	args := make([]interface{}, 0, len(tasks))
+	for _, task := range tasks {
+		task.ClaimedBy = ""
+		task.Comments = nil
+		byID[task.ID] = task
+		placeholders = append(placeholders, "?")
+		args = append(args, task.ID)
+	}
+	in := strings.Join(placeholders, ", ")
+
+	rows, err := db.Query(`SELECT task_id, claimed_by FROM task_claims WHERE task_id IN (`+in+`)`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to load task claims: %w", err)
+	}
+	for rows.Next() {
+		var taskID, claimedBy string
+		if err := rows.Scan(&taskID, &claimedBy); err != nil {
+			rows.Close()
+			return err
+		}
+		if task := byID[taskID]; task != nil {
+			task.ClaimedBy = claimedBy
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	query := `
+		SELECT id, task_id, author, body, created_at
+		FROM task_comments
+		WHERE task_id IN (` + in + `)
+		ORDER BY created_at ASC
+	`
+	rows, err = db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to load task comments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		comment := &TaskComment{}
+		var createdAt int64
+		if err := rows.Scan(&comment.ID, &comment.TaskID, &comment.Author, &comment.Body, &createdAt); err != nil {
+			return err
+		}
+		comment.CreatedAt = time.Unix(createdAt, 0)
+		if task := byID[comment.TaskID]; task != nil {
+			task.Comments = append(task.Comments, comment)
+		}
+	}
+	return rows.Err()
+}
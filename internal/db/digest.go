@@ -0,0 +1,121 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// GetLowPriorityTasks returns pending tasks scoring below maxScore, highest score first
+func (db *DB) GetLowPriorityTasks(maxScore float64, limit int) ([]*Task, error) {
+	query := `
+		SELECT id, source, source_id, title, description, due_ts, project,
+		       impact, urgency, effort, stakeholder, score, status, metadata,
+		       matched_priorities, created_at, updated_at, completed_at,
+		       COALESCE(recurrence, ''), COALESCE(recurs_from, '')
+		FROM tasks
+		WHERE status = 'pending'
+		  AND score < ?
+		ORDER BY score DESC, created_at ASC
+		LIMIT ?
+	`
+
+	rows, err := db.Query(query, maxScore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTasks(rows)
+}
+
+// GetFYIThreads returns summarized, unarchived threads synced since the given time
+// that produced no tasks or only low-scoring ones
+func (db *DB) GetFYIThreads(maxScore float64, since time.Time, limit int) ([]*Thread, error) {
+	query := `
+		SELECT t.id, t.last_history_id, t.summary, t.task_count, t.priority_score,
+		       t.last_synced
+		FROM threads t
+		WHERE t.summary IS NOT NULL AND t.summary != ''
+		  AND (t.task_count = 0 OR t.priority_score < ?)
+		  AND t.last_synced >= ?
+		  AND t.id NOT IN (SELECT thread_id FROM archived_threads)
+		ORDER BY t.last_synced DESC
+		LIMIT ?
+	`
+
+	rows, err := db.Query(query, maxScore, since.Unix(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var threads []*Thread
+	for rows.Next() {
+		thread := &Thread{}
+		var priorityScore sql.NullFloat64
+		var lastSyncedTS sql.NullInt64
+
+		err := rows.Scan(
+			&thread.ID, &thread.LastHistoryID, &thread.Summary, &thread.TaskCount,
+			&priorityScore, &lastSyncedTS,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if priorityScore.Valid {
+			thread.PriorityScore = priorityScore.Float64
+		}
+		if lastSyncedTS.Valid {
+			thread.LastSynced = time.Unix(lastSyncedTS.Int64, 0)
+		}
+
+		threads = append(threads, thread)
+	}
+
+	return threads, rows.Err()
+}
+
+// ArchiveTasks drops pending tasks out of briefs and digests.
+// The tasks status CHECK constraint has no "archived" value, so archived tasks are stored as cancelled.
+func (db *DB) ArchiveTasks(taskIDs []string) (int, error) {
+	query := `UPDATE tasks SET status = 'cancelled', updated_at = ? WHERE id = ? AND status = 'pending'`
+	now := time.Now().Unix()
+
+	archived := 0
+	for _, id := range taskIDs {
+		result, err := db.Exec(query, now, id)
+		if err != nil {
+			return archived, fmt.Errorf("failed to archive task %s: %w", id, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			archived++
+		}
+	}
+
+	return archived, nil
+}
+
+// ArchiveThreads hides threads from the thread list and future digests
+func (db *DB) ArchiveThreads(threadIDs []string) (int, error) {
+	query := `
+		INSERT INTO archived_threads (thread_id, archived_at)
+		VALUES (?, ?)
+		ON CONFLICT (thread_id) DO NOTHING
+	`
+	now := time.Now().Unix()
+
+	archived := 0
+	for _, id := range threadIDs {
+		result, err := db.Exec(query, id, now)
+		if err != nil {
+			return archived, fmt.Errorf("failed to archive thread %s: %w", id, err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			archived++
+		}
+	}
+
+	return archived, nil
+}
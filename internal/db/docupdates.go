@@ -0,0 +1,54 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// DocumentUpdate is a Drive document edited since the last daily brief, with why it's related
+// to the user's priorities
+type DocumentUpdate struct {
+	*Document
+	Reason string `json:"reason"` // e.g. "edited by alice@example.com" or "project: Atlas"
+}
+
+// Describe returns a one-line summary for briefs, e.g. "Atlas launch plan (edited by
+// alice@example.com, 3:04 PM Tue)"
+func (u *DocumentUpdate) Describe() string {
+	title := u.Title
+	if title == "" {
+		title = "(untitled)"
+	}
+	return fmt.Sprintf("%s (%s, %s)", title, u.Reason, u.UpdatedTS.Format("3:04 PM Mon"))
+}
+
+// GetDocumentsUpdatedSince returns Drive documents modified after since, most recently modified
+// first
+func (db *DB) GetDocumentsUpdatedSince(since time.Time, limit int) ([]*Document, error) {
+	query := `
+		SELECT id, title, COALESCE(link, ''), COALESCE(mime_type, ''), COALESCE(summary, ''),
+		       COALESCE(owner, ''), COALESCE(modified_by, ''), updated_ts
+		FROM docs
+		WHERE updated_ts > ?
+		ORDER BY updated_ts DESC
+		LIMIT ?
+	`
+	rows, err := db.Query(query, since.Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query updated documents: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []*Document
+	for rows.Next() {
+		doc := &Document{}
+		var updatedTS int64
+		if err := rows.Scan(&doc.ID, &doc.Title, &doc.Link, &doc.MimeType, &doc.Summary,
+			&doc.Owner, &doc.ModifiedBy, &updatedTS); err != nil {
+			return nil, err
+		}
+		doc.UpdatedTS = time.Unix(updatedTS, 0)
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
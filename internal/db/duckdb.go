@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	_ "github.com/marcboeker/go-duckdb/v2"
 )
 
 type DB struct {
 	*sql.DB
+	stats    *queryStats // Timings for embedding and full-text queries
+	backupMu sync.Mutex  // Held while Backup copies the database file
 }
 
 // Init creates and initializes the DuckDB database
@@ -63,7 +66,7 @@ func Init(dbPath string) (*DB, error) {
 		}
 	}
 
-	return &DB{sqlDB}, nil
+	return &DB{DB: sqlDB, stats: newQueryStats()}, nil
 }
 
 // RunMigrations executes all SQL migration files
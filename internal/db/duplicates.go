@@ -0,0 +1,40 @@
+package db
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// MergeDuplicateTask records dup as a duplicate of an existing task instead of inserting it.
+// The existing task keeps its identity but adopts the duplicate's due date if it is sooner.
+func (db *DB) MergeDuplicateTask(existingID string, dup *Task, similarity float64) error {
+	now := time.Now()
+	hash := sha1.Sum([]byte(fmt.Sprintf("%s|%s|%s", existingID, dup.SourceID, dup.Title)))
+	id := fmt.Sprintf("dup_%s", hex.EncodeToString(hash[:])[:12])
+
+	return db.WithTx(func(tx *sql.Tx) error {
+		insertQuery := `
+			INSERT INTO task_duplicates (id, task_id, source, source_id, title, similarity, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET similarity = excluded.similarity
+		`
+		if _, err := tx.Exec(insertQuery, id, existingID, dup.Source, dup.SourceID, dup.Title, similarity, now.Unix()); err != nil {
+			return fmt.Errorf("failed to record duplicate task: %w", err)
+		}
+
+		if dup.DueTS != nil {
+			dueQuery := `
+				UPDATE tasks SET due_ts = ?, updated_at = ?
+				WHERE id = ? AND (due_ts IS NULL OR due_ts > ?)
+			`
+			if _, err := tx.Exec(dueQuery, dup.DueTS.Unix(), now.Unix(), existingID, dup.DueTS.Unix()); err != nil {
+				return fmt.Errorf("failed to merge due date: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
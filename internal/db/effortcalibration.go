@@ -0,0 +1,191 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// effortCalibrationKey is the prefs key holding the last effort calibration
+const effortCalibrationKey = "effort_calibration"
+
+// EffortFactor is how much longer (above 1) or shorter (below 1) than their effort size suggests
+// a group of tasks took, and how many completed tasks that was learned from
+type EffortFactor struct {
+	Factor  float64 `json:"factor"`
+	Samples int     `json:"samples"`
+}
+
+// EffortCalibration holds the effort factors learned per project and per stakeholder from
+// completed tasks
+type EffortCalibration struct {
+	ComputedAt   time.Time                `json:"computed_at"`
+	Projects     map[string]*EffortFactor `json:"projects"`     // By lowercased project
+	Stakeholders map[string]*EffortFactor `json:"stakeholders"` // internal, external or executive
+}
+
+// For returns the factor a task's effort is scaled by and what it was learned from: its
+// project's if calibrated, otherwise its stakeholder's, otherwise 1
+func (c *EffortCalibration) For(project, stakeholder string) (float64, string) {
+	if c == nil {
+		return 1, ""
+	}
+	if project = strings.ToLower(strings.TrimSpace(project)); project != "" {
+		if f, ok := c.Projects[project]; ok {
+			return f.Factor, "project " + project
+		}
+	}
+	if stakeholder == "" {
+		stakeholder = "internal"
+	}
+	if f, ok := c.Stakeholders[stakeholder]; ok {
+		return f.Factor, stakeholder + " stakeholder"
+	}
+	return 1, ""
+}
+
+// Version identifies the factors, so tasks are only rescored when they change
+func (c *EffortCalibration) Version() string {
+	if c == nil {
+		return ""
+	}
+	var parts []string
+	for project, f := range c.Projects {
+		parts = append(parts, fmt.Sprintf("p:%s=%.2f", project, f.Factor))
+	}
+	for stakeholder, f := range c.Stakeholders {
+		parts = append(parts, fmt.Sprintf("s:%s=%.2f", stakeholder, f.Factor))
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, ";")
+}
+
+// CalibrateEffort learns effort factors from the tasks completed since a time. A task timed with
+// the work timer took its tracked time over its effort estimate; any other took its time from
+// creation to completion over the median for completed tasks of the same size. Each project and
+// stakeholder with at least minSamples tasks gets the median of their ratios, kept between
+// 1/maxFactor and maxFactor.
+func (db *DB) CalibrateEffort(since time.Time, minSamples int, maxFactor float64) (*EffortCalibration, error) {
+	now := time.Now()
+	tasks, err := db.GetTasksCompletedBetween(since, now.Add(time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get completed tasks: %w", err)
+	}
+	efforts, err := db.GetTaskEfforts()
+	if err != nil {
+		return nil, err
+	}
+
+	leadTime := func(task *Task) time.Duration {
+		if task.CompletedAt == nil {
+			return 0
+		}
+		return max(task.CompletedAt.Sub(task.CreatedAt), time.Minute)
+	}
+
+	// Typical time to completion for each effort size
+	leadTimes := make(map[string][]float64)
+	for _, task := range tasks {
+		if lead := leadTime(task); lead > 0 {
+			leadTimes[effortSize(task.Effort)] = append(leadTimes[effortSize(task.Effort)], lead.Hours())
+		}
+	}
+	medianLead := make(map[string]float64, len(leadTimes))
+	for size, hours := range leadTimes {
+		medianLead[size] = median(hours)
+	}
+
+	projects := make(map[string][]float64)
+	stakeholders := make(map[string][]float64)
+	for _, task := range tasks {
+		var ratio float64
+		if effort, ok := efforts[task.ID]; ok && effort.Actual > 0 {
+			ratio = effort.Actual.Hours() / EffortEstimate(task.Effort).Hours()
+		} else if lead, typical := leadTime(task), medianLead[effortSize(task.Effort)]; lead > 0 && typical > 0 {
+			ratio = lead.Hours() / typical
+		} else {
+			continue
+		}
+
+		if project := strings.ToLower(strings.TrimSpace(task.Project)); project != "" {
+			projects[project] = append(projects[project], ratio)
+		}
+		stakeholder := task.Stakeholder
+		if stakeholder == "" {
+			stakeholder = "internal"
+		}
+		stakeholders[stakeholder] = append(stakeholders[stakeholder], ratio)
+	}
+
+	factors := func(groups map[string][]float64) map[string]*EffortFactor {
+		learned := make(map[string]*EffortFactor)
+		for key, ratios := range groups {
+			if len(ratios) < max(minSamples, 1) {
+				continue
+			}
+			factor := min(max(median(ratios), 1/maxFactor), maxFactor)
+			learned[key] = &EffortFactor{Factor: math.Round(factor*100) / 100, Samples: len(ratios)}
+		}
+		return learned
+	}
+	return &EffortCalibration{
+		ComputedAt:   now,
+		Projects:     factors(projects),
+		Stakeholders: factors(stakeholders),
+	}, nil
+}
+
+// GetEffortCalibration returns the last effort calibration, or nil if effort hasn't been
+// calibrated yet
+func (db *DB) GetEffortCalibration() (*EffortCalibration, error) {
+	val, err := db.GetPreference(effortCalibrationKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get effort calibration: %w", err)
+	}
+	if val == "" {
+		return nil, nil
+	}
+
+	calibration := &EffortCalibration{}
+	if err := json.Unmarshal([]byte(val), calibration); err != nil {
+		return nil, fmt.Errorf("failed to decode effort calibration: %w", err)
+	}
+	return calibration, nil
+}
+
+// SaveEffortCalibration stores an effort calibration for scoring to use
+func (db *DB) SaveEffortCalibration(calibration *EffortCalibration) error {
+	val, err := json.Marshal(calibration)
+	if err != nil {
+		return fmt.Errorf("failed to encode effort calibration: %w", err)
+	}
+	if err := db.SetPreference(effortCalibrationKey, string(val)); err != nil {
+		return fmt.Errorf("failed to save effort calibration: %w", err)
+	}
+	return nil
+}
+
+// effortSize returns a task's effort size, with unset counting as M
+func effortSize(effort string) string {
+	if effort == "S" || effort == "L" {
+		return effort
+	}
+	return "M"
+}
+
+// median returns the middle value, or the mean of the two middle values
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
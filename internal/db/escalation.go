@@ -0,0 +1,93 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// Escalation steps for overdue tasks, in the order they're sent
+const (
+	EscalationNone     = 0
+	EscalationReminder = 1 // A reminder through the usual channels
+	EscalationPush     = 2 // An urgent mobile push
+	EscalationDraft    = 3 // A suggested apology or renegotiation email
+)
+
+// TaskEscalation is an overdue task and the escalation step reached for its due date
+type TaskEscalation struct {
+	Task       *Task
+	Level      int    // Last step sent, EscalationNone if none yet
+	Draft      string // Suggested email, at EscalationDraft
+	DraftSaved bool   // The suggested email was saved as a Gmail draft
+}
+
+// GetOverdueTasks returns open tasks that went overdue since a time, most overdue first, with
+// the escalation step reached for their current due date
+func (db *DB) GetOverdueTasks(since time.Time) ([]*TaskEscalation, error) {
+	now := time.Now().Unix()
+
+	rows, err := db.Query(`
+		SELECT id, CASE WHEN escalated_due_ts = due_ts THEN COALESCE(escalation_level, 0) ELSE 0 END
+		FROM tasks
+		WHERE status IN ('pending', 'in_progress') AND due_ts >= ? AND due_ts < ?
+	`, since.Unix(), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query overdue tasks: %w", err)
+	}
+	levels := make(map[string]int)
+	for rows.Next() {
+		var id string
+		var level int
+		if err := rows.Scan(&id, &level); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		levels[id] = level
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(levels) == 0 {
+		return nil, nil
+	}
+
+	rows, err = db.Query(`
+		SELECT id, source, source_id, title, description, due_ts, project,
+		       impact, urgency, effort, stakeholder, score, status, metadata,
+		       matched_priorities, created_at, updated_at, completed_at,
+		       COALESCE(recurrence, ''), COALESCE(recurs_from, '')
+		FROM tasks
+		WHERE status IN ('pending', 'in_progress') AND due_ts >= ? AND due_ts < ?
+		ORDER BY due_ts
+	`, since.Unix(), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query overdue tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var overdue []*TaskEscalation
+	for _, task := range tasks {
+		level, ok := levels[task.ID]
+		if !ok {
+			// Went overdue between the queries; picked up next time
+			continue
+		}
+		overdue = append(overdue, &TaskEscalation{Task: task, Level: level})
+	}
+	return overdue, nil
+}
+
+// SetTaskEscalation records the escalation step sent for a task's due date
+func (db *DB) SetTaskEscalation(taskID string, level int, due time.Time) error {
+	query := `UPDATE tasks SET escalation_level = ?, escalated_due_ts = ? WHERE id = ?`
+	if _, err := db.Exec(query, level, due.Unix(), taskID); err != nil {
+		return fmt.Errorf("failed to record escalation of task %s: %w", taskID, err)
+	}
+	return nil
+}
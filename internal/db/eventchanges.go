@@ -0,0 +1,318 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Calendar change kinds
+const (
+	EventMoved     = "moved"
+	EventCancelled = "cancelled"
+)
+
+// significantEventMove is how far an event's start or end has to move to count as rescheduled
+const significantEventMove = 15 * time.Minute
+
+// EventChange is a significant change to a calendar event found during sync
+type EventChange struct {
+	ID         string
+	EventID    string
+	Title      string
+	Kind       string
+	OldStart   time.Time
+	OldEnd     time.Time
+	NewStart   time.Time // Zero for cancellations
+	NewEnd     time.Time
+	DetectedAt time.Time
+}
+
+// Describe returns a one-line summary for briefs, e.g. "Board meeting moved to 4:00 PM (was 2:00 PM)"
+func (c *EventChange) Describe() string {
+	now := time.Now()
+	format := func(t time.Time) string {
+		if sameDay(t, now) {
+			return t.Format("3:04 PM")
+		}
+		return t.Format("Mon Jan 2 3:04 PM")
+	}
+
+	if c.Kind == EventCancelled {
+		return fmt.Sprintf("%s (%s) was cancelled", c.Title, format(c.OldStart))
+	}
+
+	if sameDay(c.OldStart, c.NewStart) && !sameDay(c.NewStart, now) {
+		return fmt.Sprintf("%s on %s moved to %s (was %s)", c.Title,
+			c.NewStart.Format("Mon Jan 2"), c.NewStart.Format("3:04 PM"), c.OldStart.Format("3:04 PM"))
+	}
+	return fmt.Sprintf("%s moved to %s (was %s)", c.Title, format(c.NewStart), format(c.OldStart))
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// detectEventChange compares a stored event with its synced copy and returns the change if it
+// was moved or cancelled. Changes to events that are already over are ignored.
+func detectEventChange(old, updated *Event) *EventChange {
+	change := &EventChange{
+		ID:         fmt.Sprintf("evchg_%d", time.Now().UnixNano()),
+		EventID:    old.ID,
+		Title:      updated.Title,
+		OldStart:   old.StartTS,
+		OldEnd:     old.EndTS,
+		DetectedAt: time.Now(),
+	}
+
+	switch {
+	case updated.Status == "cancelled" && old.Status != "cancelled":
+		if !old.EndTS.After(time.Now()) {
+			return nil
+		}
+		change.Kind = EventCancelled
+	case old.Status != "cancelled" && updated.Status != "cancelled":
+		startMoved := updated.StartTS.Sub(old.StartTS).Abs() >= significantEventMove
+		endMoved := updated.EndTS.Sub(old.EndTS).Abs() >= significantEventMove
+		if !startMoved && !endMoved {
+			return nil
+		}
+		if !old.EndTS.After(time.Now()) && !updated.EndTS.After(time.Now()) {
+			return nil
+		}
+		change.Kind = EventMoved
+		change.NewStart = updated.StartTS
+		change.NewEnd = updated.EndTS
+	default:
+		return nil
+	}
+
+	if change.Title == "" {
+		change.Title = old.Title
+	}
+	return change
+}
+
+// getEvent returns a stored event, or nil if it hasn't been synced
+func (db *DB) getEvent(id string) (*Event, error) {
+	query := `
+		SELECT id, title, start_ts, end_ts, location, description,
+		       attendees, meeting_link, status
+		FROM events
+		WHERE id = ?
+	`
+
+	event := &Event{}
+	var startTS, endTS int64
+	var title, location, description, attendeesJSON, meetingLink, status sql.NullString
+	err := db.QueryRow(query, id).Scan(
+		&event.ID, &title, &startTS, &endTS, &location, &description,
+		&attendeesJSON, &meetingLink, &status,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+
+	event.Title = title.String
+	event.StartTS = time.Unix(startTS, 0)
+	event.EndTS = time.Unix(endTS, 0)
+	event.Location = location.String
+	event.Description = description.String
+	event.MeetingLink = meetingLink.String
+	event.Status = status.String
+	if attendeesJSON.String != "" {
+		json.Unmarshal([]byte(attendeesJSON.String), &event.Attendees)
+	}
+	return event, nil
+}
+
+func (db *DB) recordEventChange(change *EventChange) error {
+	var newStart, newEnd *int64
+	if !change.NewStart.IsZero() {
+		start, end := change.NewStart.Unix(), change.NewEnd.Unix()
+		newStart, newEnd = &start, &end
+	}
+
+	query := `
+		INSERT INTO event_changes (id, event_id, title, kind, old_start_ts, old_end_ts,
+		                           new_start_ts, new_end_ts, detected_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := db.Exec(query, change.ID, change.EventID, change.Title, change.Kind,
+		change.OldStart.Unix(), change.OldEnd.Unix(), newStart, newEnd, change.DetectedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to record event change: %w", err)
+	}
+	return nil
+}
+
+// CancelEvent marks a stored event as cancelled, for syncs that report deletions by ID only
+func (db *DB) CancelEvent(id string) error {
+	existing, err := db.getEvent(id)
+	if err != nil || existing == nil {
+		return err
+	}
+	if existing.Status == "cancelled" {
+		return nil
+	}
+
+	if _, err := db.Exec(`UPDATE events SET status = 'cancelled' WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to cancel event: %w", err)
+	}
+
+	cancelled := *existing
+	cancelled.Status = "cancelled"
+	if change := detectEventChange(existing, &cancelled); change != nil {
+		return db.recordEventChange(change)
+	}
+	return nil
+}
+
+// GetUnprocessedEventChanges returns changes whose linked tasks and time blocks haven't been
+// updated yet, oldest first
+func (db *DB) GetUnprocessedEventChanges() ([]*EventChange, error) {
+	return db.queryEventChanges(`WHERE processed_at IS NULL ORDER BY detected_at, id`)
+}
+
+// MarkEventChangeProcessed records that a change's linked tasks and time blocks were updated
+func (db *DB) MarkEventChangeProcessed(id string) error {
+	_, err := db.Exec(`UPDATE event_changes SET processed_at = ? WHERE id = ?`, time.Now().Unix(), id)
+	return err
+}
+
+// GetUnreportedEventChanges returns changes not yet called out in a brief, one per event, for
+// events that are still to come. Successive moves of the same event are combined, and an event
+// moved back to its original time drops out. Changes to time block events are left out.
+func (db *DB) GetUnreportedEventChanges() ([]*EventChange, error) {
+	changes, err := db.queryEventChanges(`
+		WHERE reported_at IS NULL
+		  AND event_id NOT IN (SELECT calendar_event_id FROM time_blocks WHERE calendar_event_id IS NOT NULL)
+		ORDER BY detected_at, id
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]*EventChange)
+	var order []string
+	for _, change := range changes {
+		first, ok := merged[change.EventID]
+		if !ok {
+			merged[change.EventID] = change
+			order = append(order, change.EventID)
+			continue
+		}
+		// Keep the original time and the latest outcome
+		combined := *change
+		combined.OldStart, combined.OldEnd = first.OldStart, first.OldEnd
+		merged[change.EventID] = &combined
+	}
+
+	now := time.Now()
+	var result []*EventChange
+	for _, eventID := range order {
+		change := merged[eventID]
+		switch change.Kind {
+		case EventCancelled:
+			if !change.OldEnd.After(now) {
+				continue
+			}
+		case EventMoved:
+			if !change.NewEnd.After(now) ||
+				(change.NewStart.Sub(change.OldStart).Abs() < significantEventMove &&
+					change.NewEnd.Sub(change.OldEnd).Abs() < significantEventMove) {
+				continue
+			}
+		}
+		result = append(result, change)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].OldStart.Before(result[j].OldStart)
+	})
+	return result, nil
+}
+
+// MarkEventChangesReported records that the changes to the given events were called out in a brief
+func (db *DB) MarkEventChangesReported(eventIDs []string) error {
+	now := time.Now().Unix()
+	for _, id := range eventIDs {
+		_, err := db.Exec(`UPDATE event_changes SET reported_at = ? WHERE event_id = ? AND reported_at IS NULL`, now, id)
+		if err != nil {
+			return fmt.Errorf("failed to mark event changes reported: %w", err)
+		}
+	}
+	return nil
+}
+
+func (db *DB) queryEventChanges(where string) ([]*EventChange, error) {
+	query := `
+		SELECT id, event_id, title, kind, old_start_ts, old_end_ts, new_start_ts, new_end_ts, detected_at
+		FROM event_changes ` + where
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*EventChange
+	for rows.Next() {
+		change := &EventChange{}
+		var title sql.NullString
+		var oldStart, oldEnd, detectedAt int64
+		var newStart, newEnd sql.NullInt64
+		err := rows.Scan(&change.ID, &change.EventID, &title, &change.Kind,
+			&oldStart, &oldEnd, &newStart, &newEnd, &detectedAt)
+		if err != nil {
+			return nil, err
+		}
+		change.Title = title.String
+		change.OldStart = time.Unix(oldStart, 0)
+		change.OldEnd = time.Unix(oldEnd, 0)
+		if newStart.Valid {
+			change.NewStart = time.Unix(newStart.Int64, 0)
+			change.NewEnd = time.Unix(newEnd.Int64, 0)
+		}
+		change.DetectedAt = time.Unix(detectedAt, 0)
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+// ShiftEventTasks moves the due dates of pending tasks linked to an event (by source_id) along
+// with the event
+func (db *DB) ShiftEventTasks(eventID string, delta time.Duration) (int, error) {
+	query := `
+		UPDATE tasks SET due_ts = due_ts + ?, updated_at = ?
+		WHERE source_id = ? AND status = 'pending' AND due_ts IS NOT NULL
+	`
+	result, err := db.Exec(query, int64(delta.Seconds()), time.Now().Unix(), eventID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to shift event tasks: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+// CancelEventTasks cancels pending tasks linked to an event, such as meeting prep, when the
+// event is cancelled
+func (db *DB) CancelEventTasks(eventID string) (int, error) {
+	query := `
+		UPDATE tasks SET status = 'cancelled', updated_at = ?
+		WHERE source_id = ? AND status = 'pending'
+	`
+	result, err := db.Exec(query, time.Now().Unix(), eventID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cancel event tasks: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
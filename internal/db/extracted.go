@@ -0,0 +1,110 @@
+package db
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// normalizedTitleSQL matches NormalizeTaskTitle for titles stored in the tasks table
+const normalizedTitleSQL = `lower(trim(regexp_replace(title, '\s+', ' ', 'g')))`
+
+// NormalizeTaskTitle lowercases a title and collapses its whitespace, so the same task
+// extracted twice with cosmetic differences is recognised
+func NormalizeTaskTitle(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+// ExtractedTaskID derives a task's ID from the item it was extracted from (e.g. a Gmail
+// thread) and its normalized title, so extracting the same task again gives the same ID
+func ExtractedTaskID(source, sourceID, title string) string {
+	hash := sha1.Sum([]byte(sourceID + "|" + NormalizeTaskTitle(title)))
+	return fmt.Sprintf("%s_%s", source, hex.EncodeToString(hash[:8]))
+}
+
+// AssignExtractedTaskID gives an extracted task its ID. A task extracted from the same item
+// with the same title before IDs were derived from content keeps the ID it already has.
+func (db *DB) AssignExtractedTaskID(task *Task) error {
+	task.ID = ExtractedTaskID(task.Source, task.SourceID, task.Title)
+
+	query := `
+		SELECT id FROM tasks
+		WHERE id = ? OR (source = ? AND source_id = ? AND ` + normalizedTitleSQL + ` = ?)
+		ORDER BY id = ? DESC, created_at
+		LIMIT 1
+	`
+	var existingID string
+	err := db.QueryRow(query, task.ID, task.Source, task.SourceID, NormalizeTaskTitle(task.Title), task.ID).Scan(&existingID)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to look up extracted task: %w", err)
+	}
+	task.ID = existingID
+	return nil
+}
+
+// SaveExtractedTask saves a task extracted by the LLM after AssignExtractedTaskID. When the
+// task was extracted before, the existing task is updated with the new extraction but keeps
+// its status, completion, creation time and any fields the user edited or corrected; its tags,
+// comments and feedback stay attached because the ID doesn't change. Other pending copies of
+// the task from the same item are removed, though not upcoming occurrences of a recurring task.
+// Reports whether the task is new.
+func (db *DB) SaveExtractedTask(task *Task) (bool, error) {
+	extractedTitle := task.Title
+	existing, err := db.GetTaskByID(task.ID)
+	created := err != nil
+	if !created {
+		task.Status = existing.Status
+		task.CompletedAt = existing.CompletedAt
+		task.CreatedAt = existing.CreatedAt
+		task.Recurrence = existing.Recurrence
+		task.RecursFrom = existing.RecursFrom
+
+		edited, err := db.editedFields(task.ID)
+		if err != nil {
+			return false, err
+		}
+		if edited[EditTitle] {
+			task.Title = existing.Title
+		}
+		if edited[EditDescription] {
+			task.Description = existing.Description
+		}
+		if edited[EditProject] {
+			task.Project = existing.Project
+		}
+		if edited[EditUrgency] {
+			task.Urgency = existing.Urgency
+		}
+		if edited[EditEffort] {
+			task.Effort = existing.Effort
+		}
+		if edited[CorrectionImpact] {
+			task.Impact = existing.Impact
+		}
+		if edited[CorrectionStakeholder] {
+			task.Stakeholder = existing.Stakeholder
+		}
+	}
+
+	err = db.WithTx(func(tx *sql.Tx) error {
+		purgeQuery := `
+			DELETE FROM tasks
+			WHERE source = ? AND source_id = ? AND status = 'pending' AND id != ?
+			  AND (recurs_from IS NULL OR recurs_from = '')
+			  AND ` + normalizedTitleSQL + ` = ?
+		`
+		if _, err := tx.Exec(purgeQuery, task.Source, task.SourceID, task.ID, NormalizeTaskTitle(extractedTitle)); err != nil {
+			return fmt.Errorf("failed to purge duplicate tasks: %w", err)
+		}
+		return saveTask(tx, task)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to save extracted task: %w", err)
+	}
+	return created, nil
+}
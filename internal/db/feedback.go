@@ -0,0 +1,134 @@
+package db
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// feedbackPrior is how many neutral votes each project's and sender's feedback is smoothed
+// toward, so a single vote nudges scores and a consistent run of votes moves them fully
+const feedbackPrior = 2
+
+// threadSendersSQL is a CTE of the lowercased address of whoever started each thread
+const threadSendersSQL = `thread_senders AS (
+		SELECT thread_id, lower(regexp_extract(arg_min(from_addr, ts), '[^<\s]+@[^>\s]+')) AS sender
+		FROM messages
+		GROUP BY thread_id
+	)`
+
+// FeedbackBoosts are learned from priority feedback: the smoothed net vote on tasks from each
+// project and sender, from -1 (always ranked too high) to 1 (always ranked too low)
+type FeedbackBoosts struct {
+	Projects map[string]float64 `json:"projects"` // Keyed by lowercased project
+	Senders  map[string]float64 `json:"senders"`  // Keyed by lowercased email address
+	Threads  map[string]string  `json:"-"`        // Who started each open task's thread, when senders have boosts
+}
+
+// For returns the combined boost for a task's project and sender, from -1 to 1
+func (b *FeedbackBoosts) For(project, sender string) float64 {
+	boost := b.Projects[strings.ToLower(strings.TrimSpace(project))] + b.Senders[sender]
+	return min(max(boost, -1), 1)
+}
+
+// Version identifies the learned boosts, so scores can be recomputed when they change
+func (b *FeedbackBoosts) Version() string {
+	data, _ := json.Marshal(b)
+	return fmt.Sprintf("%x", sha256.Sum256(data))[:16]
+}
+
+// GetFeedbackBoosts learns project and sender boosts from priority feedback given since a time
+func (db *DB) GetFeedbackBoosts(since time.Time) (*FeedbackBoosts, error) {
+	boosts := &FeedbackBoosts{
+		Projects: make(map[string]float64),
+		Senders:  make(map[string]float64),
+		Threads:  make(map[string]string),
+	}
+
+	projectQuery := `
+		SELECT LOWER(TRIM(t.project)), CAST(SUM(f.user_vote) AS INTEGER), COUNT(*)
+		FROM priority_feedback f
+		JOIN tasks t ON t.id = f.task_id
+		WHERE f.feedback_at >= ? AND COALESCE(TRIM(t.project), '') <> ''
+		GROUP BY 1
+	`
+	if err := db.scanFeedbackBoosts(boosts.Projects, projectQuery, since.Unix()); err != nil {
+		return nil, fmt.Errorf("failed to learn project boosts: %w", err)
+	}
+
+	senderQuery := `
+		WITH ` + threadSendersSQL + `
+		SELECT s.sender, CAST(SUM(f.user_vote) AS INTEGER), COUNT(*)
+		FROM priority_feedback f
+		JOIN tasks t ON t.id = f.task_id
+		JOIN thread_senders s ON s.thread_id = t.source_id
+		WHERE f.feedback_at >= ? AND t.source = 'gmail' AND COALESCE(s.sender, '') <> ''
+		GROUP BY 1
+	`
+	if err := db.scanFeedbackBoosts(boosts.Senders, senderQuery, since.Unix()); err != nil {
+		return nil, fmt.Errorf("failed to learn sender boosts: %w", err)
+	}
+	if len(boosts.Senders) == 0 {
+		return boosts, nil
+	}
+
+	threadQuery := `
+		WITH ` + threadSendersSQL + `
+		SELECT DISTINCT t.source_id, s.sender
+		FROM tasks t
+		JOIN thread_senders s ON s.thread_id = t.source_id
+		WHERE t.source = 'gmail' AND t.status IN ('pending', 'in_progress') AND COALESCE(s.sender, '') <> ''
+	`
+	rows, err := db.Query(threadQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task senders: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var threadID, sender string
+		if err := rows.Scan(&threadID, &sender); err != nil {
+			return nil, err
+		}
+		boosts.Threads[threadID] = sender
+	}
+	return boosts, rows.Err()
+}
+
+// scanFeedbackBoosts reads key, vote sum and vote count rows into smoothed boosts
+func (db *DB) scanFeedbackBoosts(boosts map[string]float64, query string, args ...interface{}) error {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var sum, count int
+		if err := rows.Scan(&key, &sum, &count); err != nil {
+			return err
+		}
+		if sum != 0 {
+			boosts[key] = float64(sum) / float64(count+feedbackPrior)
+		}
+	}
+	return rows.Err()
+}
+
+// GetThreadSender returns the lowercased address of whoever started a thread, or "" if the
+// thread isn't synced
+func (db *DB) GetThreadSender(threadID string) (string, error) {
+	var sender sql.NullString
+	err := db.QueryRow(`
+		SELECT lower(regexp_extract(arg_min(from_addr, ts), '[^<\s]+@[^>\s]+'))
+		FROM messages
+		WHERE thread_id = ?
+	`, threadID).Scan(&sender)
+	if err != nil {
+		return "", fmt.Errorf("failed to get thread sender: %w", err)
+	}
+	return sender.String, nil
+}
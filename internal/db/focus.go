@@ -0,0 +1,263 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FocusSession is a period during which notifications are held back
+type FocusSession struct {
+	ID            string
+	StartedAt     time.Time
+	EndsAt        time.Time
+	EndedAt       *time.Time // Set when stopped early
+	SummarySentAt *time.Time
+}
+
+// Active reports whether the session is still silencing notifications
+func (f *FocusSession) Active(now time.Time) bool {
+	return f.EndedAt == nil && now.Before(f.EndsAt)
+}
+
+// End returns when the session finished (or will finish)
+func (f *FocusSession) End() time.Time {
+	if f.EndedAt != nil {
+		return *f.EndedAt
+	}
+	return f.EndsAt
+}
+
+// FocusThread is an email thread that received messages during a focus session
+type FocusThread struct {
+	ThreadID string
+	Subject  string
+	From     string // Sender of the latest message
+	Messages int
+}
+
+// FocusSummary lists what arrived while a focus session was active
+type FocusSummary struct {
+	Session       *FocusSession
+	Threads       []*FocusThread
+	Tasks         []*Task
+	Notifications map[string]int // Held notifications by kind
+}
+
+// StartFocusSession starts a focus session, replacing any active one
+func (db *DB) StartFocusSession(duration time.Duration) (*FocusSession, error) {
+	now := time.Now()
+
+	if _, err := db.Exec(`UPDATE focus_sessions SET ended_at = ? WHERE ended_at IS NULL AND ends_at > ?`, now.Unix(), now.Unix()); err != nil {
+		return nil, fmt.Errorf("failed to end previous focus session: %w", err)
+	}
+
+	session := &FocusSession{
+		ID:        fmt.Sprintf("focus_%d", now.UnixNano()),
+		StartedAt: now,
+		EndsAt:    now.Add(duration),
+	}
+
+	query := `INSERT INTO focus_sessions (id, started_at, ends_at) VALUES (?, ?, ?)`
+	if _, err := db.Exec(query, session.ID, session.StartedAt.Unix(), session.EndsAt.Unix()); err != nil {
+		return nil, fmt.Errorf("failed to start focus session: %w", err)
+	}
+
+	return session, nil
+}
+
+// GetActiveFocusSession returns the running focus session, or nil if there is none
+func (db *DB) GetActiveFocusSession() (*FocusSession, error) {
+	query := `
+		SELECT id, started_at, ends_at, ended_at, summary_sent_at
+		FROM focus_sessions
+		WHERE ended_at IS NULL AND ends_at > ?
+		ORDER BY started_at DESC
+		LIMIT 1
+	`
+	session, err := scanFocusSession(db.QueryRow(query, time.Now().Unix()))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return session, err
+}
+
+// InFocusMode reports whether notifications are currently being held back
+func (db *DB) InFocusMode() bool {
+	session, err := db.GetActiveFocusSession()
+	return err == nil && session != nil
+}
+
+// EndFocusSession stops the running focus session early and returns it, or nil if none was running
+func (db *DB) EndFocusSession() (*FocusSession, error) {
+	session, err := db.GetActiveFocusSession()
+	if err != nil || session == nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err := db.Exec(`UPDATE focus_sessions SET ended_at = ? WHERE id = ?`, now.Unix(), session.ID); err != nil {
+		return nil, fmt.Errorf("failed to end focus session: %w", err)
+	}
+	session.EndedAt = &now
+
+	return session, nil
+}
+
+// GetFinishedFocusSessions returns sessions that are over but whose exit summary hasn't been sent
+func (db *DB) GetFinishedFocusSessions() ([]*FocusSession, error) {
+	query := `
+		SELECT id, started_at, ends_at, ended_at, summary_sent_at
+		FROM focus_sessions
+		WHERE summary_sent_at IS NULL AND (ended_at IS NOT NULL OR ends_at <= ?)
+		ORDER BY started_at ASC
+	`
+	rows, err := db.Query(query, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*FocusSession
+	for rows.Next() {
+		session, err := scanFocusSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// GetFocusSessionsBetween returns the sessions that started in a period, oldest first
+func (db *DB) GetFocusSessionsBetween(start, end time.Time) ([]*FocusSession, error) {
+	query := `
+		SELECT id, started_at, ends_at, ended_at, summary_sent_at
+		FROM focus_sessions
+		WHERE started_at >= ? AND started_at < ?
+		ORDER BY started_at ASC
+	`
+	rows, err := db.Query(query, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query focus sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*FocusSession
+	for rows.Next() {
+		session, err := scanFocusSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// MarkFocusSummarySent records that a session's exit summary was delivered
+func (db *DB) MarkFocusSummarySent(id string) error {
+	_, err := db.Exec(`UPDATE focus_sessions SET summary_sent_at = ? WHERE id = ?`, time.Now().Unix(), id)
+	return err
+}
+
+// GetFocusSummary collects the email, tasks and held notifications that arrived during a session.
+// Messages sent by userEmail are left out.
+func (db *DB) GetFocusSummary(session *FocusSession, userEmail string) (*FocusSummary, error) {
+	start, end := session.StartedAt.Unix(), session.End().Unix()
+	summary := &FocusSummary{Session: session, Notifications: make(map[string]int)}
+
+	threadQuery := `
+		SELECT thread_id, arg_max(COALESCE(subject, ''), ts), arg_max(COALESCE(from_addr, ''), ts), COUNT(*)
+		FROM messages
+		WHERE ts >= ? AND ts < ? AND (? = '' OR lower(from_addr) NOT LIKE ?)
+		GROUP BY thread_id
+		ORDER BY MAX(ts) DESC
+	`
+	userEmail = strings.ToLower(userEmail)
+	rows, err := db.Query(threadQuery, start, end, userEmail, "%"+userEmail+"%")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query focus messages: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		thread := &FocusThread{}
+		if err := rows.Scan(&thread.ThreadID, &thread.Subject, &thread.From, &thread.Messages); err != nil {
+			return nil, err
+		}
+		summary.Threads = append(summary.Threads, thread)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	taskQuery := `
+		SELECT id, source, source_id, title, description, due_ts, project,
+		       impact, urgency, effort, stakeholder, score, status, metadata,
+		       matched_priorities, created_at, updated_at, completed_at,
+		       COALESCE(recurrence, ''), COALESCE(recurs_from, '')
+		FROM tasks
+		WHERE created_at >= ? AND created_at < ? AND status IN ('pending', 'in_progress')
+		ORDER BY score DESC
+	`
+	taskRows, err := db.Query(taskQuery, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query focus tasks: %w", err)
+	}
+	defer taskRows.Close()
+
+	if summary.Tasks, err = scanTasks(taskRows); err != nil {
+		return nil, err
+	}
+
+	// Each notification is queued once per channel, so count the busiest channel. Emails are only
+	// recorded in the outbox after they're sent, so they were never held.
+	noteRows, err := db.Query(`
+		SELECT kind, MAX(n) FROM (
+			SELECT kind, channel, COUNT(*) AS n FROM notifications_outbox
+			WHERE created_at >= ? AND created_at < ? AND channel <> 'email'
+			GROUP BY kind, channel
+		)
+		GROUP BY kind
+	`, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query held notifications: %w", err)
+	}
+	defer noteRows.Close()
+
+	for noteRows.Next() {
+		var kind string
+		var count int
+		if err := noteRows.Scan(&kind, &count); err != nil {
+			return nil, err
+		}
+		summary.Notifications[kind] = count
+	}
+
+	return summary, noteRows.Err()
+}
+
+// scanFocusSession scans a focus session row
+func scanFocusSession(row interface{ Scan(...interface{}) error }) (*FocusSession, error) {
+	session := &FocusSession{}
+	var startedAt, endsAt int64
+	var endedAt, summarySentAt sql.NullInt64
+
+	if err := row.Scan(&session.ID, &startedAt, &endsAt, &endedAt, &summarySentAt); err != nil {
+		return nil, err
+	}
+
+	session.StartedAt = time.Unix(startedAt, 0)
+	session.EndsAt = time.Unix(endsAt, 0)
+	if endedAt.Valid {
+		t := time.Unix(endedAt.Int64, 0)
+		session.EndedAt = &t
+	}
+	if summarySentAt.Valid {
+		t := time.Unix(summarySentAt.Int64, 0)
+		session.SummarySentAt = &t
+	}
+
+	return session, nil
+}
@@ -87,6 +87,55 @@ func (db *DB) GetFrontMetadata(threadID string) (*FrontMetadata, error) {
 	return &metadata, nil
 }
 
+// GetFrontThreadIDs returns the threads linked to a Front conversation
+func (db *DB) GetFrontThreadIDs(conversationID string) ([]string, error) {
+	rows, err := db.Query(`SELECT thread_id FROM front_metadata WHERE conversation_id = ?`, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var threadIDs []string
+	for rows.Next() {
+		var threadID string
+		if err := rows.Scan(&threadID); err != nil {
+			return nil, err
+		}
+		threadIDs = append(threadIDs, threadID)
+	}
+	return threadIDs, rows.Err()
+}
+
+// frontEventRetention is how long applied webhook event IDs are kept to spot replays; older
+// deliveries are already rejected by their timestamp
+const frontEventRetention = 24 * time.Hour
+
+// RecordFrontEvent records a Front webhook event as applied, reporting false if it already was
+func (db *DB) RecordFrontEvent(eventID string) (bool, error) {
+	now := time.Now()
+	if _, err := db.Exec(`DELETE FROM front_webhook_events WHERE received_at < ?`, now.Add(-frontEventRetention).Unix()); err != nil {
+		return false, fmt.Errorf("failed to prune Front events: %w", err)
+	}
+
+	result, err := db.Exec(`
+		INSERT INTO front_webhook_events (id, received_at) VALUES (?, ?)
+		ON CONFLICT (id) DO NOTHING
+	`, eventID, now.Unix())
+	if err != nil {
+		return false, fmt.Errorf("failed to record Front event: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// ForgetFrontEvent drops a webhook event recorded as applied, so Front's retry is applied instead
+func (db *DB) ForgetFrontEvent(eventID string) error {
+	if _, err := db.Exec(`DELETE FROM front_webhook_events WHERE id = ?`, eventID); err != nil {
+		return fmt.Errorf("failed to forget Front event: %w", err)
+	}
+	return nil
+}
+
 // SaveFrontComment saves an internal Front comment
 func (db *DB) SaveFrontComment(comment *FrontComment) error {
 	query := `
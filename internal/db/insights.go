@@ -0,0 +1,111 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ThreadInsights is how deeply a thread was last summarized and, for a deep summary, what it
+// found beyond the summary text
+type ThreadInsights struct {
+	ThreadID      string    `json:"thread_id"`
+	Depth         string    `json:"depth"` // gist, standard or deep
+	Decisions     []string  `json:"decisions,omitempty"`
+	Owners        []string  `json:"owners,omitempty"` // "Name: action"
+	Risks         []string  `json:"risks,omitempty"`
+	OpenQuestions []string  `json:"open_questions,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Empty reports whether a deep summary found nothing beyond its text
+func (i *ThreadInsights) Empty() bool {
+	return len(i.Decisions)+len(i.Owners)+len(i.Risks)+len(i.OpenQuestions) == 0
+}
+
+// SaveThreadInsights records the depth and structured fields of a thread's latest summary,
+// replacing those of the previous one
+func (db *DB) SaveThreadInsights(insights *ThreadInsights) error {
+	encode := func(items []string) string {
+		data, _ := json.Marshal(items)
+		return string(data)
+	}
+	query := `
+		INSERT INTO thread_insights (thread_id, depth, decisions, owners, risks, open_questions, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (thread_id) DO UPDATE SET
+			depth = excluded.depth, decisions = excluded.decisions, owners = excluded.owners,
+			risks = excluded.risks, open_questions = excluded.open_questions, updated_at = excluded.updated_at
+	`
+	_, err := db.Exec(query, insights.ThreadID, insights.Depth, encode(insights.Decisions), encode(insights.Owners),
+		encode(insights.Risks), encode(insights.OpenQuestions), time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to save thread insights: %w", err)
+	}
+	return nil
+}
+
+// GetThreadInsights returns the insights of the given threads that have a deep summary, keyed
+// by thread ID
+func (db *DB) GetThreadInsights(threadIDs []string) (map[string]*ThreadInsights, error) {
+	insights := make(map[string]*ThreadInsights)
+	if len(threadIDs) == 0 {
+		return insights, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(threadIDs)), ", ")
+	args := make([]interface{}, len(threadIDs))
+	for i, id := range threadIDs {
+		args[i] = id
+	}
+	query := `
+		SELECT thread_id, depth, decisions, owners, risks, open_questions, updated_at
+		FROM thread_insights
+		WHERE depth = 'deep' AND thread_id IN (` + placeholders + `)
+	`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query thread insights: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		insight := &ThreadInsights{}
+		var decisions, owners, risks, questions sql.NullString
+		var updatedAt int64
+		if err := rows.Scan(&insight.ThreadID, &insight.Depth, &decisions, &owners, &risks, &questions, &updatedAt); err != nil {
+			return nil, err
+		}
+		json.Unmarshal([]byte(decisions.String), &insight.Decisions)
+		json.Unmarshal([]byte(owners.String), &insight.Owners)
+		json.Unmarshal([]byte(risks.String), &insight.Risks)
+		json.Unmarshal([]byte(questions.String), &insight.OpenQuestions)
+		insight.UpdatedAt = time.Unix(updatedAt, 0)
+		insights[insight.ThreadID] = insight
+	}
+	return insights, rows.Err()
+}
+
+// GetThreadTaskScore returns the highest score of a thread's open tasks, and whether the thread
+// has been summarized before. A thread summarized without open tasks scores 0.
+func (db *DB) GetThreadTaskScore(threadID string) (score float64, summarized bool, err error) {
+	query := `
+		SELECT COALESCE(t.summary, '') != '',
+		       COALESCE((SELECT MAX(score) FROM tasks
+		                 WHERE source = 'gmail' AND source_id = t.id
+		                   AND status IN ('pending', 'in_progress')), 0)
+		FROM threads t
+		WHERE t.id = ?
+	`
+	err = db.QueryRow(query, threadID).Scan(&summarized, &score)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get thread task score: %w", err)
+	}
+	return score, summarized, nil
+}
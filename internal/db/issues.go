@@ -0,0 +1,207 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/metrics"
+)
+
+// Error categories recorded in the errors table
+const (
+	IssueAuth    = "auth"
+	IssueQuota   = "quota"
+	IssueNetwork = "network"
+	IssueParse   = "parse"
+	IssueOther   = "other"
+)
+
+// issueStaleAfter is how long an issue can go without failing again before it counts as resolved.
+// Most operations clear their issue on the next success; this covers the ones that don't log successes.
+const issueStaleAfter = 24 * time.Hour
+
+// googleServices are the services authorized by the Google OAuth token
+var googleServices = map[string]bool{
+	"gmail": true, "drive": true, "calendar": true, "tasks": true, "chat": true,
+}
+
+// Issue is an operation that is currently failing, from the errors table
+type Issue struct {
+	Service   string    `json:"service"`
+	Action    string    `json:"action"`
+	Category  string    `json:"category"`
+	Message   string    `json:"message"` // Latest error
+	Count     int       `json:"count"`   // Failures since the issue started
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+	Fix       string    `json:"fix"` // Suggested fix
+}
+
+// ClassifyError sorts an error into auth, quota, network, parse or other
+func ClassifyError(err error) string {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return IssueParse
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	// Quota first: Google reports rate limits as 403s
+	case containsAny(msg, "quota", "rate limit", "ratelimitexceeded", "resource_exhausted", "resource exhausted",
+		"too many requests", "error 429", "status 429", "budget"):
+		return IssueQuota
+	case containsAny(msg, "invalid_grant", "invalid_client", "unauthorized", "unauthenticated", "error 401",
+		"status 401", "error 403", "status 403", "permission denied", "insufficient authentication scopes",
+		"api key not valid", "token expired", "oauth2"):
+		return IssueAuth
+	case containsAny(msg, "connection refused", "no such host", "connection reset", "network is unreachable",
+		"i/o timeout", "deadline exceeded", "tls handshake", "dial tcp", "unexpected eof", ": eof"):
+		return IssueNetwork
+	case containsAny(msg, "invalid character", "unexpected end of json", "cannot unmarshal", "failed to parse",
+		"failed to decode"):
+		return IssueParse
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return IssueNetwork
+	}
+	return IssueOther
+}
+
+func containsAny(s string, substrings ...string) bool {
+	for _, sub := range substrings {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// SuggestFix returns what the user can do about a category of failure in a service
+func SuggestFix(service, category string) string {
+	switch category {
+	case IssueAuth:
+		switch {
+		case googleServices[service]:
+			return "Run focus-agent -auth to sign in to Google again (new features may need new scopes)"
+		case strings.HasPrefix(service, "outlook"):
+			return "Run focus-agent -auth to sign in to Microsoft 365 again"
+		case service == "gemini":
+			return "Check gemini.api_key and gemini.api_keys in config.yaml"
+		case service == "slack":
+			return "Check slack.bot_token or slack.webhook_url in config.yaml"
+		case service == "claude":
+			return "Log in to the Claude CLI again"
+		case service == "notion":
+			return "Check notion.token in config.yaml and that both databases are shared with the integration"
+		}
+		return "Check the credentials for " + service + " in config.yaml"
+	case IssueQuota:
+		switch {
+		case service == "gemini":
+			return "Wait for the daily quota to reset, or add a key to gemini.api_keys"
+		case googleServices[service]:
+			return "Google is rate limiting requests; lower google.max_requests_per_minute"
+		}
+		return "Wait for the quota to reset, or raise the provider's cap in llm.budgets"
+	case IssueNetwork:
+		if service == "ollama" {
+			return "Check that the hosts in ollama.hosts are running and reachable"
+		}
+		return "Check the network connection; the operation is retried on its next run"
+	case IssueParse:
+		return "A response couldn't be parsed, which is usually transient; if it persists, check the logs for the raw output"
+	}
+	return "Check the logs for details"
+}
+
+// recordIssue counts a failure against its operation's issue, starting a new issue if the last
+// one was resolved or has gone stale
+func (db *DB) recordIssue(service, action string, err error) error {
+	now := time.Now().Unix()
+	staleBefore := time.Now().Add(-issueStaleAfter).Unix()
+
+	query := `
+		INSERT INTO errors (service, action, category, message, count, first_seen, last_seen, resolved_at)
+		VALUES (?, ?, ?, ?, 1, ?, ?, NULL)
+		ON CONFLICT (service, action) DO UPDATE SET
+			category = excluded.category,
+			message = excluded.message,
+			count = CASE WHEN errors.resolved_at IS NULL AND errors.last_seen >= ? THEN errors.count + 1 ELSE 1 END,
+			first_seen = CASE WHEN errors.resolved_at IS NULL AND errors.last_seen >= ? THEN errors.first_seen ELSE excluded.first_seen END,
+			last_seen = excluded.last_seen,
+			resolved_at = NULL
+	`
+	_, execErr := db.Exec(query, service, action, ClassifyError(err), err.Error(), now, now, staleBefore, staleBefore)
+	if execErr != nil {
+		return fmt.Errorf("failed to record issue: %w", execErr)
+	}
+	return nil
+}
+
+// resolveIssues clears an operation's issue after it succeeds. Auth and quota problems belong to
+// the whole service, so any success clears them.
+func (db *DB) resolveIssues(service, action string) error {
+	query := `
+		UPDATE errors SET resolved_at = ?
+		WHERE resolved_at IS NULL AND service = ? AND (action = ? OR category IN (?, ?))
+	`
+	if _, err := db.Exec(query, time.Now().Unix(), service, action, IssueAuth, IssueQuota); err != nil {
+		return fmt.Errorf("failed to resolve issues: %w", err)
+	}
+	return nil
+}
+
+// TrackResult records a failed operation as an issue, or clears its issue when it succeeds.
+// LogUsage and LogKeyUsage do this already; use it for operations that don't log usage.
+func (db *DB) TrackResult(service, action string, err error) {
+	var trackErr error
+	if err != nil {
+		if ClassifyError(err) == IssueQuota {
+			metrics.QuotaErrors.Inc(service)
+		}
+		trackErr = db.recordIssue(service, action, err)
+	} else {
+		trackErr = db.resolveIssues(service, action)
+	}
+	if trackErr != nil {
+		log.Printf("Warning: %v", trackErr)
+	}
+}
+
+// GetOpenIssues returns the issues that are unresolved and have failed recently, most recent first
+func (db *DB) GetOpenIssues() ([]*Issue, error) {
+	query := `
+		SELECT service, action, category, message, count, first_seen, last_seen
+		FROM errors
+		WHERE resolved_at IS NULL AND last_seen >= ?
+		ORDER BY last_seen DESC
+	`
+	rows, err := db.Query(query, time.Now().Add(-issueStaleAfter).Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issues: %w", err)
+	}
+	defer rows.Close()
+
+	var issues []*Issue
+	for rows.Next() {
+		issue := &Issue{}
+		var firstSeen, lastSeen int64
+		if err := rows.Scan(&issue.Service, &issue.Action, &issue.Category, &issue.Message, &issue.Count,
+			&firstSeen, &lastSeen); err != nil {
+			return nil, err
+		}
+		issue.FirstSeen = time.Unix(firstSeen, 0)
+		issue.LastSeen = time.Unix(lastSeen, 0)
+		issue.Fix = SuggestFix(issue.Service, issue.Category)
+		issues = append(issues, issue)
+	}
+	return issues, rows.Err()
+}
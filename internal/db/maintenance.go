@@ -0,0 +1,63 @@
+package db
+
+import (
+	"fmt"
+)
+
+// Filters on tasks for the rows maintenance commands delete, shared with their dry-run previews
+const (
+	// ReprocessTasksFilter matches the AI-extracted tasks -reprocess-tasks replaces
+	ReprocessTasksFilter = `source = 'ai'`
+
+	// OtherPeoplesTasksFilter matches tasks whose stakeholder is someone other than the user.
+	// Its one parameter is a LIKE pattern for the user's email.
+	OtherPeoplesTasksFilter = `stakeholder IS NOT NULL
+		  AND stakeholder != ''
+		  AND LOWER(stakeholder) != 'me'
+		  AND LOWER(stakeholder) != 'you'
+		  AND LOWER(stakeholder) NOT LIKE ?`
+)
+
+// previewSampleSize is how many rows a preview lists
+const previewSampleSize = 10
+
+// TaskPreview is what deleting tasks matching a filter would remove
+type TaskPreview struct {
+	Count   int
+	Samples []*Task // The most recently updated few, with ID, title and stakeholder set
+}
+
+// PreviewTaskDelete counts the tasks matching a filter and returns a few of them, without
+// changing anything
+func (db *DB) PreviewTaskDelete(filter string, args ...interface{}) (*TaskPreview, error) {
+	preview := &TaskPreview{}
+	query := `SELECT CAST(COUNT(*) AS INTEGER) FROM tasks WHERE ` + filter
+	if err := db.QueryRow(query, args...).Scan(&preview.Count); err != nil {
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	if preview.Count == 0 {
+		return preview, nil
+	}
+
+	query = fmt.Sprintf(`
+		SELECT id, title, COALESCE(stakeholder, '')
+		FROM tasks
+		WHERE %s
+		ORDER BY updated_at DESC
+		LIMIT %d
+	`, filter, previewSampleSize)
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		task := &Task{}
+		if err := rows.Scan(&task.ID, &task.Title, &task.Stakeholder); err != nil {
+			return nil, err
+		}
+		preview.Samples = append(preview.Samples, task)
+	}
+	return preview, rows.Err()
+}
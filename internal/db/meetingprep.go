@@ -0,0 +1,266 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// meetingContextDays is how far back email is searched for threads related to a meeting
+const meetingContextDays = 30
+
+// meetingTitleStopWords are title words too generic to find related documents and threads
+var meetingTitleStopWords = map[string]bool{
+	"meeting": true, "sync": true, "call": true, "chat": true, "weekly": true, "daily": true,
+	"monthly": true, "with": true, "catch": true, "catchup": true, "check": true, "intro": true,
+	"update": true, "standup": true,
+}
+
+// MeetingContext is the documents and email threads related to a meeting
+type MeetingContext struct {
+	Documents []*Document
+	Threads   []*ContactInteraction // Most relevant first
+}
+
+// MeetingPrep is a meeting whose prep notes are waiting to be sent
+type MeetingPrep struct {
+	Event *Event
+	Notes string
+}
+
+// GetEventsNeedingPrep returns meetings starting within the window that have no prep notes yet
+func (db *DB) GetEventsNeedingPrep(within time.Duration) ([]*Event, error) {
+	now := time.Now()
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE start_ts >= ? AND start_ts <= ?
+		  AND COALESCE(status, '') != 'cancelled'
+		  AND prep_generated_at IS NULL
+		ORDER BY start_ts ASC
+	`
+	events, err := db.queryEvents(query, now.Unix(), now.Add(within).Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events needing prep: %w", err)
+	}
+	return events, nil
+}
+
+// SaveMeetingPrep stores prep notes on an event. Empty notes mark a meeting that needs no prep.
+func (db *DB) SaveMeetingPrep(eventID, notes string) error {
+	_, err := db.Exec(`UPDATE events SET prep_notes = ?, prep_generated_at = ? WHERE id = ?`,
+		notes, time.Now().Unix(), eventID)
+	if err != nil {
+		return fmt.Errorf("failed to save meeting prep: %w", err)
+	}
+	return nil
+}
+
+// GetMeetingPrepsToSend returns unsent prep notes for meetings starting within the window
+func (db *DB) GetMeetingPrepsToSend(within time.Duration) ([]*MeetingPrep, error) {
+	now := time.Now()
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE start_ts >= ? AND start_ts <= ?
+		  AND COALESCE(status, '') != 'cancelled'
+		  AND COALESCE(prep_notes, '') != ''
+		  AND prep_sent_at IS NULL
+		ORDER BY start_ts ASC
+	`
+	events, err := db.queryEvents(query, now.Unix(), now.Add(within).Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meeting preps to send: %w", err)
+	}
+
+	preps := make([]*MeetingPrep, 0, len(events))
+	for _, event := range events {
+		preps = append(preps, &MeetingPrep{Event: event, Notes: event.PrepNotes})
+	}
+	return preps, nil
+}
+
+// MarkMeetingPrepSent records that a meeting's prep notes were sent
+func (db *DB) MarkMeetingPrepSent(eventID string) error {
+	_, err := db.Exec(`UPDATE events SET prep_sent_at = ? WHERE id = ?`, time.Now().Unix(), eventID)
+	if err != nil {
+		return fmt.Errorf("failed to mark meeting prep sent: %w", err)
+	}
+	return nil
+}
+
+// GetMeetingContext finds documents and recent email threads related to a meeting: documents
+// linked to the event, owned by an attendee or sharing a title word, and threads with an attendee
+// or a subject sharing a title word. Threads matching both rank first. userEmail is left out of
+// the attendees, since the user is on everything.
+func (db *DB) GetMeetingContext(event *Event, userEmail string, limit int) (*MeetingContext, error) {
+	var attendees []string
+	userEmail = strings.ToLower(strings.TrimSpace(userEmail))
+	for _, attendee := range event.Attendees {
+		attendee = strings.ToLower(strings.TrimSpace(attendee))
+		if attendee == "" || attendee == userEmail || strings.HasSuffix(attendee, "calendar.google.com") {
+			continue
+		}
+		attendees = append(attendees, attendee)
+	}
+	keywords := meetingTitleKeywords(event.Title)
+
+	docs, err := db.getMeetingDocuments(event.ID, attendees, keywords, limit)
+	if err != nil {
+		return nil, err
+	}
+	threads, err := db.getMeetingThreads(attendees, keywords, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &MeetingContext{Documents: docs, Threads: threads}, nil
+}
+
+func (db *DB) getMeetingDocuments(eventID string, attendees, keywords []string, limit int) ([]*Document, error) {
+	conditions := []string{"meeting_id = ?"}
+	args := []interface{}{eventID}
+	for _, attendee := range attendees {
+		conditions = append(conditions, "lower(owner) = ?")
+		args = append(args, attendee)
+	}
+	for _, keyword := range keywords {
+		conditions = append(conditions, "lower(title) LIKE ?")
+		args = append(args, "%"+keyword+"%")
+	}
+	args = append(args, limit)
+
+	query := `
+		SELECT id, title, link, COALESCE(mime_type, ''), COALESCE(summary, ''), COALESCE(owner, ''),
+		       COALESCE(updated_ts, 0)
+		FROM docs
+		WHERE ` + strings.Join(conditions, " OR ") + `
+		ORDER BY updated_ts DESC NULLS LAST
+		LIMIT ?
+	`
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query meeting documents: %w", err)
+	}
+	defer rows.Close()
+
+	var docs []*Document
+	for rows.Next() {
+		doc := &Document{}
+		var updatedTS int64
+		if err := rows.Scan(&doc.ID, &doc.Title, &doc.Link, &doc.MimeType, &doc.Summary, &doc.Owner, &updatedTS); err != nil {
+			return nil, err
+		}
+		doc.UpdatedTS = time.Unix(updatedTS, 0)
+		docs = append(docs, doc)
+	}
+	return docs, rows.Err()
+}
+
+func (db *DB) getMeetingThreads(attendees, keywords []string, limit int) ([]*ContactInteraction, error) {
+	if len(attendees) == 0 && len(keywords) == 0 {
+		return nil, nil
+	}
+
+	attendeeMatch, attendeeArgs := "FALSE", []interface{}{}
+	if len(attendees) > 0 {
+		var conditions []string
+		for _, attendee := range attendees {
+			conditions = append(conditions, "lower(from_addr) LIKE ? OR lower(to_addr) LIKE ?")
+			attendeeArgs = append(attendeeArgs, "%"+attendee+"%", "%"+attendee+"%")
+		}
+		attendeeMatch = "(" + strings.Join(conditions, " OR ") + ")"
+	}
+
+	titleMatch, titleArgs := "FALSE", []interface{}{}
+	if len(keywords) > 0 {
+		var conditions []string
+		for _, keyword := range keywords {
+			conditions = append(conditions, "lower(subject) LIKE ?")
+			titleArgs = append(titleArgs, "%"+keyword+"%")
+		}
+		titleMatch = "(" + strings.Join(conditions, " OR ") + ")"
+	}
+
+	query := `
+		WITH matched AS (
+			SELECT thread_id,
+			       MAX(CASE WHEN ` + attendeeMatch + ` THEN 1 ELSE 0 END) +
+			       MAX(CASE WHEN ` + titleMatch + ` THEN 1 ELSE 0 END) AS relevance
+			FROM messages
+			WHERE ts >= ? AND (` + attendeeMatch + ` OR ` + titleMatch + `)
+			GROUP BY thread_id
+		),
+		latest AS (
+			SELECT m.thread_id, m.subject, m.snippet, m.from_addr, m.ts, mt.relevance,
+			       ROW_NUMBER() OVER (PARTITION BY m.thread_id ORDER BY m.ts DESC) AS rn
+			FROM messages m
+			INNER JOIN matched mt ON mt.thread_id = m.thread_id
+		)
+		SELECT l.thread_id, COALESCE(l.subject, ''), COALESCE(l.snippet, ''),
+		       COALESCE(l.from_addr, ''), l.ts, COALESCE(t.summary, '')
+		FROM latest l
+		LEFT JOIN threads t ON t.id = l.thread_id
+		WHERE l.rn = 1
+		ORDER BY l.relevance DESC, l.ts DESC
+		LIMIT ?
+	`
+
+	since := time.Now().AddDate(0, 0, -meetingContextDays).Unix()
+	var args []interface{}
+	args = append(args, attendeeArgs...)
+	args = append(args, titleArgs...)
+	args = append(args, since)
+	args = append(args, attendeeArgs...)
+	args = append(args, titleArgs...)
+	args = append(args, limit)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query meeting threads: %w", err)
+	}
+	defer rows.Close()
+
+	var threads []*ContactInteraction
+	for rows.Next() {
+		thread := &ContactInteraction{}
+		var ts int64
+		if err := rows.Scan(&thread.ThreadID, &thread.Subject, &thread.Snippet,
+			&thread.LastFrom, &ts, &thread.Summary); err != nil {
+			return nil, err
+		}
+		thread.LastTS = time.Unix(ts, 0)
+		threads = append(threads, thread)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	threadIDs := make([]string, len(threads))
+	for i, thread := range threads {
+		threadIDs[i] = thread.ThreadID
+	}
+	insights, err := db.GetThreadInsights(threadIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, thread := range threads {
+		thread.Insights = insights[thread.ThreadID]
+	}
+	return threads, nil
+}
+
+// meetingTitleKeywords returns the distinctive words of a meeting title
+func meetingTitleKeywords(title string) []string {
+	var keywords []string
+	seen := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127)
+	}) {
+		if len(word) < 4 || meetingTitleStopWords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		keywords = append(keywords, word)
+	}
+	return keywords
+}
@@ -542,6 +542,1459 @@ func GetMigrations() []Migration {
 				return err
 			},
 		},
+		{
+			Version: 8,
+			Name:    "create_notifications_outbox_table",
+			Up: func(tx *sql.Tx) error {
+				// Check if notifications_outbox table exists
+				var count int
+				err := tx.QueryRow(`
+					SELECT COUNT(*)
+					FROM information_schema.tables
+					WHERE table_name='notifications_outbox'
+				`).Scan(&count)
+				if err != nil {
+					return fmt.Errorf("failed to check notifications_outbox table: %w", err)
+				}
+
+				// Create notifications_outbox table if it doesn't exist
+				if count == 0 {
+					_, err = tx.Exec(`
+						CREATE TABLE notifications_outbox (
+							id VARCHAR PRIMARY KEY,
+							channel VARCHAR NOT NULL,
+							kind VARCHAR NOT NULL,
+							payload VARCHAR NOT NULL,
+							thread_key VARCHAR DEFAULT NULL,
+							status VARCHAR NOT NULL DEFAULT 'pending',
+							attempts INTEGER NOT NULL DEFAULT 0,
+							last_error VARCHAR DEFAULT NULL,
+							remote_id VARCHAR DEFAULT NULL,
+							created_at BIGINT NOT NULL,
+							next_attempt_at BIGINT NOT NULL,
+							sent_at BIGINT DEFAULT NULL
+						);
+					`)
+					if err != nil {
+						return fmt.Errorf("failed to create notifications_outbox table: %w", err)
+					}
+
+					// Create index for the delivery worker's pending lookup
+					_, err = tx.Exec(`
+						CREATE INDEX IF NOT EXISTS idx_notifications_outbox_status ON notifications_outbox(status, next_attempt_at);
+					`)
+					if err != nil {
+						return fmt.Errorf("failed to create notifications_outbox index: %w", err)
+					}
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP INDEX IF EXISTS idx_notifications_outbox_status`)
+				if err != nil {
+					return err
+				}
+				_, err = tx.Exec(`DROP TABLE IF EXISTS notifications_outbox`)
+				return err
+			},
+		},
+		{
+			Version: 9,
+			Name:    "create_archived_threads_table",
+			Up: func(tx *sql.Tx) error {
+				// Check if archived_threads table exists
+				var count int
+				err := tx.QueryRow(`
+					SELECT COUNT(*)
+					FROM information_schema.tables
+					WHERE table_name='archived_threads'
+				`).Scan(&count)
+				if err != nil {
+					return fmt.Errorf("failed to check archived_threads table: %w", err)
+				}
+
+				// Create archived_threads table if it doesn't exist
+				if count == 0 {
+					_, err = tx.Exec(`
+						CREATE TABLE archived_threads (
+							thread_id VARCHAR PRIMARY KEY,
+							archived_at BIGINT NOT NULL
+						);
+					`)
+					if err != nil {
+						return fmt.Errorf("failed to create archived_threads table: %w", err)
+					}
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS archived_threads`)
+				return err
+			},
+		},
+		{
+			Version: 10,
+			Name:    "create_task_duplicates_table",
+			Up: func(tx *sql.Tx) error {
+				// Check if task_duplicates table exists
+				var count int
+				err := tx.QueryRow(`
+					SELECT COUNT(*)
+					FROM information_schema.tables
+					WHERE table_name='task_duplicates'
+				`).Scan(&count)
+				if err != nil {
+					return fmt.Errorf("failed to check task_duplicates table: %w", err)
+				}
+
+				// Create task_duplicates table if it doesn't exist
+				if count == 0 {
+					_, err = tx.Exec(`
+						CREATE TABLE task_duplicates (
+							id VARCHAR PRIMARY KEY,
+							task_id VARCHAR NOT NULL,
+							source VARCHAR NOT NULL,
+							source_id VARCHAR,
+							title VARCHAR NOT NULL,
+							similarity DOUBLE NOT NULL,
+							created_at BIGINT NOT NULL
+						);
+					`)
+					if err != nil {
+						return fmt.Errorf("failed to create task_duplicates table: %w", err)
+					}
+
+					// Create index for looking up the duplicates merged into a task
+					_, err = tx.Exec(`
+						CREATE INDEX IF NOT EXISTS idx_task_duplicates_task ON task_duplicates(task_id);
+					`)
+					if err != nil {
+						return fmt.Errorf("failed to create task_duplicates index: %w", err)
+					}
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP INDEX IF EXISTS idx_task_duplicates_task`)
+				if err != nil {
+					return err
+				}
+				_, err = tx.Exec(`DROP TABLE IF EXISTS task_duplicates`)
+				return err
+			},
+		},
+		{
+			Version: 11,
+			Name:    "create_relationship_briefs_table",
+			Up: func(tx *sql.Tx) error {
+				// Check if relationship_briefs table exists
+				var count int
+				err := tx.QueryRow(`
+					SELECT COUNT(*)
+					FROM information_schema.tables
+					WHERE table_name='relationship_briefs'
+				`).Scan(&count)
+				if err != nil {
+					return fmt.Errorf("failed to check relationship_briefs table: %w", err)
+				}
+
+				// Create relationship_briefs table if it doesn't exist
+				// One row per (event, contact) so each brief is only sent once
+				if count == 0 {
+					_, err = tx.Exec(`
+						CREATE TABLE relationship_briefs (
+							event_id VARCHAR NOT NULL,
+							contact VARCHAR NOT NULL,
+							brief VARCHAR NOT NULL,
+							sent_at BIGINT NOT NULL,
+							PRIMARY KEY (event_id, contact)
+						);
+					`)
+					if err != nil {
+						return fmt.Errorf("failed to create relationship_briefs table: %w", err)
+					}
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS relationship_briefs`)
+				return err
+			},
+		},
+		{
+			Version: 12,
+			Name:    "create_focus_sessions_table",
+			Up: func(tx *sql.Tx) error {
+				// Check if focus_sessions table exists
+				var count int
+				err := tx.QueryRow(`
+					SELECT COUNT(*)
+					FROM information_schema.tables
+					WHERE table_name='focus_sessions'
+				`).Scan(&count)
+				if err != nil {
+					return fmt.Errorf("failed to check focus_sessions table: %w", err)
+				}
+
+				// Create focus_sessions table if it doesn't exist
+				// ended_at is set when a session is stopped early; summary_sent_at once the exit summary went out
+				if count == 0 {
+					_, err = tx.Exec(`
+						CREATE TABLE focus_sessions (
+							id VARCHAR PRIMARY KEY,
+							started_at BIGINT NOT NULL,
+							ends_at BIGINT NOT NULL,
+							ended_at BIGINT DEFAULT NULL,
+							summary_sent_at BIGINT DEFAULT NULL
+						);
+					`)
+					if err != nil {
+						return fmt.Errorf("failed to create focus_sessions table: %w", err)
+					}
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS focus_sessions`)
+				return err
+			},
+		},
+		{
+			Version: 13,
+			Name:    "create_processing_rules_table",
+			Up: func(tx *sql.Tx) error {
+				// Check if processing_rules table exists
+				var count int
+				err := tx.QueryRow(`
+					SELECT COUNT(*)
+					FROM information_schema.tables
+					WHERE table_name='processing_rules'
+				`).Scan(&count)
+				if err != nil {
+					return fmt.Errorf("failed to check processing_rules table: %w", err)
+				}
+
+				// Create processing_rules table if it doesn't exist
+				// Threads matching a rule (by thread, sender, domain or label) are never sent to the LLM
+				if count == 0 {
+					_, err = tx.Exec(`
+						CREATE TABLE processing_rules (
+							id VARCHAR PRIMARY KEY,
+							kind VARCHAR NOT NULL,
+							pattern VARCHAR NOT NULL,
+							note VARCHAR,
+							created_at BIGINT NOT NULL,
+							UNIQUE (kind, pattern)
+						);
+					`)
+					if err != nil {
+						return fmt.Errorf("failed to create processing_rules table: %w", err)
+					}
+				}
+
+				// Check if skipped_by_rule column exists
+				err = tx.QueryRow(`
+					SELECT COUNT(*)
+					FROM information_schema.columns
+					WHERE table_name='threads' AND column_name='skipped_by_rule'
+				`).Scan(&count)
+				if err != nil {
+					return fmt.Errorf("failed to check skipped_by_rule column: %w", err)
+				}
+
+				// Add skipped_by_rule column if it doesn't exist
+				// Set to the matching rule ID so skipped threads leave the AI processing queue
+				if count == 0 {
+					_, err = tx.Exec(`
+						ALTER TABLE threads ADD COLUMN skipped_by_rule VARCHAR DEFAULT NULL;
+					`)
+					if err != nil {
+						return fmt.Errorf("failed to add skipped_by_rule column: %w", err)
+					}
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				// Note: DuckDB supports DROP COLUMN
+				// For compatibility, we'll leave the skipped_by_rule column
+				_, err := tx.Exec(`DROP TABLE IF EXISTS processing_rules`)
+				return err
+			},
+		},
+		{
+			Version: 14,
+			Name:    "create_analytics_views",
+			Up: func(tx *sql.Tx) error {
+				// Views the restricted analytics query interface reads from.
+				// They leave out message bodies, subjects, summaries and descriptions
+				// so a leaked dashboard token can't be used to read email content.
+				views := []string{
+					`CREATE OR REPLACE VIEW analytics_tasks AS
+						SELECT id, source, project, impact, urgency, effort, stakeholder, score, status,
+						       due_ts, created_at, updated_at, completed_at
+						FROM tasks`,
+					`CREATE OR REPLACE VIEW analytics_threads AS
+						SELECT id, task_count, priority_score, relevant_to_user, next_followup_ts,
+						       last_synced, created_at
+						FROM threads`,
+					`CREATE OR REPLACE VIEW analytics_messages AS
+						SELECT id, thread_id, from_addr, ts, labels, sensitivity
+						FROM messages`,
+					`CREATE OR REPLACE VIEW analytics_events AS
+						SELECT id, start_ts, end_ts, status
+						FROM events`,
+					`CREATE OR REPLACE VIEW analytics_usage AS
+						SELECT id, ts, service, action, tokens, cost, duration_ms, error
+						FROM usage`,
+				}
+				for _, view := range views {
+					if _, err := tx.Exec(view); err != nil {
+						return fmt.Errorf("failed to create analytics view: %w", err)
+					}
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				for _, view := range []string{"analytics_tasks", "analytics_threads", "analytics_messages", "analytics_events", "analytics_usage"} {
+					if _, err := tx.Exec(`DROP VIEW IF EXISTS ` + view); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version: 15,
+			Name:    "add_priority_label_applied_to_threads",
+			Up: func(tx *sql.Tx) error {
+				// Check if priority_label_applied column exists
+				var count int
+				err := tx.QueryRow(`
+					SELECT COUNT(*)
+					FROM information_schema.columns
+					WHERE table_name='threads' AND column_name='priority_label_applied'
+				`).Scan(&count)
+				if err != nil {
+					return fmt.Errorf("failed to check priority_label_applied column: %w", err)
+				}
+
+				// Add priority_label_applied column if it doesn't exist
+				// Records whether the Gmail priority label is on the thread, so it can be removed again
+				if count == 0 {
+					_, err = tx.Exec(`
+						ALTER TABLE threads ADD COLUMN priority_label_applied BOOLEAN DEFAULT FALSE;
+					`)
+					if err != nil {
+						return fmt.Errorf("failed to add priority_label_applied column: %w", err)
+					}
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				// Note: DuckDB supports DROP COLUMN
+				// For compatibility, we'll leave the column
+				return nil
+			},
+		},
+		{
+			Version: 16,
+			Name:    "add_recurrence_to_tasks",
+			Up: func(tx *sql.Tx) error {
+				// Recurrence rule (daily, weekdays, weekly, monthly or an RRULE subset) and,
+				// for spawned occurrences, the task that was completed to create them
+				columns := []struct{ name, ddl string }{
+					{"recurrence", `ALTER TABLE tasks ADD COLUMN recurrence VARCHAR DEFAULT NULL;`},
+					{"recurs_from", `ALTER TABLE tasks ADD COLUMN recurs_from VARCHAR DEFAULT NULL;`},
+				}
+				for _, column := range columns {
+					var count int
+					err := tx.QueryRow(`
+						SELECT COUNT(*)
+						FROM information_schema.columns
+						WHERE table_name='tasks' AND column_name=?
+					`, column.name).Scan(&count)
+					if err != nil {
+						return fmt.Errorf("failed to check %s column: %w", column.name, err)
+					}
+					if count == 0 {
+						if _, err := tx.Exec(column.ddl); err != nil {
+							return fmt.Errorf("failed to add %s column: %w", column.name, err)
+						}
+					}
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				// Note: DuckDB supports DROP COLUMN
+				// For compatibility, we'll leave the columns
+				return nil
+			},
+		},
+		{
+			Version: 17,
+			Name:    "create_time_blocks_table",
+			Up: func(tx *sql.Tx) error {
+				// Focus blocks proposed from the daily plan, and the tentative calendar
+				// events they were written to
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS time_blocks (
+						id VARCHAR PRIMARY KEY,
+						day VARCHAR NOT NULL,
+						name VARCHAR NOT NULL,
+						start_ts BIGINT NOT NULL,
+						end_ts BIGINT NOT NULL,
+						task_ids VARCHAR,
+						status VARCHAR NOT NULL,
+						conflict VARCHAR,
+						calendar_event_id VARCHAR,
+						created_at BIGINT NOT NULL,
+						updated_at BIGINT NOT NULL
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create time_blocks table: %w", err)
+				}
+
+				_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_time_blocks_day ON time_blocks(day);`)
+				if err != nil {
+					return fmt.Errorf("failed to create time_blocks index: %w", err)
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS time_blocks`)
+				return err
+			},
+		},
+		{
+			Version: 18,
+			Name:    "create_event_changes_table",
+			Up: func(tx *sql.Tx) error {
+				// Significant calendar changes (moves and cancellations) detected during sync.
+				// processed_at is set once linked tasks and time blocks are updated, and
+				// reported_at once the change has been called out in a brief.
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS event_changes (
+						id VARCHAR PRIMARY KEY,
+						event_id VARCHAR NOT NULL,
+						title VARCHAR,
+						kind VARCHAR NOT NULL,
+						old_start_ts BIGINT NOT NULL,
+						old_end_ts BIGINT NOT NULL,
+						new_start_ts BIGINT,
+						new_end_ts BIGINT,
+						detected_at BIGINT NOT NULL,
+						processed_at BIGINT,
+						reported_at BIGINT
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create event_changes table: %w", err)
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS event_changes`)
+				return err
+			},
+		},
+		{
+			Version: 19,
+			Name:    "create_tags_tables",
+			Up: func(tx *sql.Tx) error {
+				// Free-form tags on tasks, alongside the single project field
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS task_tags (
+						task_id VARCHAR NOT NULL,
+						tag VARCHAR NOT NULL,
+						created_at BIGINT NOT NULL,
+						PRIMARY KEY (task_id, tag)
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create task_tags table: %w", err)
+				}
+
+				if _, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_task_tags_tag ON task_tags(tag)`); err != nil {
+					return fmt.Errorf("failed to create task_tags index: %w", err)
+				}
+
+				// Rules that tag tasks extracted from matching threads, using the same matching
+				// as the "do not process" rules
+				_, err = tx.Exec(`
+					CREATE TABLE IF NOT EXISTS tag_rules (
+						id VARCHAR PRIMARY KEY,
+						kind VARCHAR NOT NULL,
+						pattern VARCHAR NOT NULL,
+						tag VARCHAR NOT NULL,
+						note VARCHAR,
+						created_at BIGINT NOT NULL,
+						UNIQUE (kind, pattern, tag)
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create tag_rules table: %w", err)
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				if _, err := tx.Exec(`DROP TABLE IF EXISTS tag_rules`); err != nil {
+					return err
+				}
+				_, err := tx.Exec(`DROP TABLE IF EXISTS task_tags`)
+				return err
+			},
+		},
+		{
+			Version: 20,
+			Name:    "add_api_key_to_usage",
+			Up: func(tx *sql.Tx) error {
+				// Which API key a request used (a label, never the key itself), so each key's
+				// daily quota can be tracked
+				var count int
+				err := tx.QueryRow(`
+					SELECT COUNT(*)
+					FROM information_schema.columns
+					WHERE table_name='usage' AND column_name='api_key'
+				`).Scan(&count)
+				if err != nil {
+					return fmt.Errorf("failed to check api_key column: %w", err)
+				}
+
+				if count == 0 {
+					_, err = tx.Exec(`
+						ALTER TABLE usage ADD COLUMN api_key VARCHAR DEFAULT NULL;
+					`)
+					if err != nil {
+						return fmt.Errorf("failed to add api_key column: %w", err)
+					}
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				// Note: DuckDB supports DROP COLUMN
+				// For compatibility, we'll leave the column
+				return nil
+			},
+		},
+		{
+			Version: 21,
+			Name:    "create_plan_history_table",
+			Up: func(tx *sql.Tx) error {
+				// Plans and reviews kept for looking back, one per kind per day (e.g. the
+				// end-of-day brief). content is the plan as JSON.
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS plan_history (
+						id VARCHAR PRIMARY KEY,
+						kind VARCHAR NOT NULL,
+						plan_date VARCHAR NOT NULL,
+						summary VARCHAR,
+						content VARCHAR,
+						created_at BIGINT NOT NULL
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create plan_history table: %w", err)
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS plan_history`)
+				return err
+			},
+		},
+		{
+			Version: 22,
+			Name:    "create_task_corrections_table",
+			Up: func(tx *sql.Tx) error {
+				// Fixes to fields the LLM guessed when extracting a task, kept as a signal for
+				// how far off extraction tends to be
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS task_corrections (
+						id VARCHAR PRIMARY KEY,
+						task_id VARCHAR NOT NULL,
+						source VARCHAR,
+						field VARCHAR NOT NULL,
+						old_value VARCHAR,
+						new_value VARCHAR,
+						corrected_at BIGINT NOT NULL
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create task_corrections table: %w", err)
+				}
+
+				_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_task_corrections_field ON task_corrections(field)`)
+				if err != nil {
+					return fmt.Errorf("failed to create task_corrections index: %w", err)
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS task_corrections`)
+				return err
+			},
+		},
+		{
+			Version: 23,
+			Name:    "create_delegation_tables",
+			Up: func(tx *sql.Tx) error {
+				// Delegated tasks claimed by the assistant, and comments left on them
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS task_claims (
+						task_id VARCHAR PRIMARY KEY,
+						claimed_by VARCHAR NOT NULL,
+						claimed_at BIGINT NOT NULL
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create task_claims table: %w", err)
+				}
+
+				_, err = tx.Exec(`
+					CREATE TABLE IF NOT EXISTS task_comments (
+						id VARCHAR PRIMARY KEY,
+						task_id VARCHAR NOT NULL,
+						author VARCHAR NOT NULL,
+						body VARCHAR NOT NULL,
+						created_at BIGINT NOT NULL
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create task_comments table: %w", err)
+				}
+
+				_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_task_comments_task ON task_comments(task_id)`)
+				if err != nil {
+					return fmt.Errorf("failed to create task_comments index: %w", err)
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				if _, err := tx.Exec(`DROP TABLE IF EXISTS task_comments`); err != nil {
+					return err
+				}
+				_, err := tx.Exec(`DROP TABLE IF EXISTS task_claims`)
+				return err
+			},
+		},
+		{
+			Version: 24,
+			Name:    "create_attachments_table",
+			Up: func(tx *sql.Tx) error {
+				// Text extracted from email attachments and linked Google Docs
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS attachments (
+						id VARCHAR PRIMARY KEY,
+						message_id VARCHAR NOT NULL,
+						thread_id VARCHAR NOT NULL,
+						filename VARCHAR NOT NULL,
+						mime_type VARCHAR NOT NULL,
+						size_bytes BIGINT NOT NULL DEFAULT 0,
+						text VARCHAR NOT NULL DEFAULT '',
+						error VARCHAR NOT NULL DEFAULT '',
+						created_at BIGINT NOT NULL
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create attachments table: %w", err)
+				}
+
+				_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_attachments_thread ON attachments(thread_id)`)
+				if err != nil {
+					return fmt.Errorf("failed to create attachments index: %w", err)
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS attachments`)
+				return err
+			},
+		},
+		{
+			Version: 25,
+			Name:    "create_summary_changes_table",
+			Up: func(tx *sql.Tx) error {
+				// What changed each time a thread was re-summarized, until reported in a brief
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS summary_changes (
+						id VARCHAR PRIMARY KEY,
+						thread_id VARCHAR NOT NULL,
+						subject VARCHAR NOT NULL DEFAULT '',
+						previous_summary VARCHAR NOT NULL,
+						summary VARCHAR NOT NULL,
+						added VARCHAR,
+						removed VARCHAR,
+						detected_at BIGINT NOT NULL,
+						reported_at BIGINT
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create summary_changes table: %w", err)
+				}
+
+				_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_summary_changes_thread ON summary_changes(thread_id)`)
+				if err != nil {
+					return fmt.Errorf("failed to create summary_changes index: %w", err)
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS summary_changes`)
+				return err
+			},
+		},
+		{
+			Version: 26,
+			Name:    "create_content_embeddings_table",
+			Up: func(tx *sql.Tx) error {
+				// Embeddings of thread summaries and Drive documents for semantic search.
+				// content_version tells when the source has changed and needs embedding again.
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS content_embeddings (
+						kind VARCHAR NOT NULL,
+						source_id VARCHAR NOT NULL,
+						embedding FLOAT[768] NOT NULL,
+						content_version VARCHAR NOT NULL,
+						model VARCHAR NOT NULL,
+						generated_at BIGINT NOT NULL,
+						PRIMARY KEY (kind, source_id)
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create content_embeddings table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS content_embeddings`)
+				return err
+			},
+		},
+		{
+			Version: 27,
+			Name:    "add_meeting_prep_to_events",
+			Up: func(tx *sql.Tx) error {
+				// Prep notes generated ahead of a meeting, when they were generated (set even when
+				// the meeting needs none) and when they were sent
+				columns := []struct{ name, ddl string }{
+					{"prep_notes", `ALTER TABLE events ADD COLUMN prep_notes VARCHAR DEFAULT NULL;`},
+					{"prep_generated_at", `ALTER TABLE events ADD COLUMN prep_generated_at BIGINT DEFAULT NULL;`},
+					{"prep_sent_at", `ALTER TABLE events ADD COLUMN prep_sent_at BIGINT DEFAULT NULL;`},
+				}
+				for _, column := range columns {
+					var count int
+					err := tx.QueryRow(`
+						SELECT COUNT(*)
+						FROM information_schema.columns
+						WHERE table_name='events' AND column_name=?
+					`, column.name).Scan(&count)
+					if err != nil {
+						return fmt.Errorf("failed to check %s column: %w", column.name, err)
+					}
+					if count == 0 {
+						if _, err := tx.Exec(column.ddl); err != nil {
+							return fmt.Errorf("failed to add %s column: %w", column.name, err)
+						}
+					}
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				// Note: DuckDB supports DROP COLUMN
+				// For compatibility, we'll leave the columns
+				return nil
+			},
+		},
+		{
+			Version: 28,
+			Name:    "create_errors_table",
+			Up: func(tx *sql.Tx) error {
+				// One row per failing operation: its category (auth, quota, network, parse, other),
+				// latest message, how often it has failed since it started, and when it was resolved
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS errors (
+						service VARCHAR NOT NULL,
+						action VARCHAR NOT NULL,
+						category VARCHAR NOT NULL,
+						message VARCHAR NOT NULL,
+						count INTEGER NOT NULL DEFAULT 1,
+						first_seen BIGINT NOT NULL,
+						last_seen BIGINT NOT NULL,
+						resolved_at BIGINT,
+						PRIMARY KEY (service, action)
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create errors table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS errors`)
+				return err
+			},
+		},
+		{
+			Version: 29,
+			Name:    "create_waiting_items_table",
+			Up: func(tx *sql.Tx) error {
+				// One row per sent message checked for a request: status is waiting, replied or
+				// dismissed, or none when the message asked for nothing
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS waiting_items (
+						message_id VARCHAR PRIMARY KEY,
+						thread_id VARCHAR NOT NULL,
+						subject VARCHAR,
+						recipient VARCHAR,
+						request VARCHAR,
+						sent_ts BIGINT NOT NULL,
+						expected_by BIGINT,
+						status VARCHAR NOT NULL,
+						nudged_at BIGINT,
+						resolved_at BIGINT,
+						created_at BIGINT NOT NULL
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create waiting_items table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS waiting_items`)
+				return err
+			},
+		},
+		{
+			Version: 30,
+			Name:    "create_projects_table",
+			Up: func(tx *sql.Tx) error {
+				// Billing settings for projects, which otherwise exist only as a name on tasks.
+				// key is the lowercased name, since projects are matched case-insensitively.
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS projects (
+						key VARCHAR PRIMARY KEY,
+						name VARCHAR NOT NULL,
+						client VARCHAR,
+						billable BOOLEAN NOT NULL DEFAULT false,
+						updated_at BIGINT NOT NULL
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create projects table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS projects`)
+				return err
+			},
+		},
+		{
+			Version: 31,
+			Name:    "add_alignment_hash_to_tasks",
+			Up: func(tx *sql.Tx) error {
+				// The strategic alignment score from the last evaluation, and a hash of the task
+				// fields and priorities it was evaluated on, so unchanged tasks aren't sent to
+				// the LLM again
+				columns := []struct{ name, ddl string }{
+					{"strategic_score", `ALTER TABLE tasks ADD COLUMN strategic_score DOUBLE DEFAULT NULL;`},
+					{"alignment_hash", `ALTER TABLE tasks ADD COLUMN alignment_hash VARCHAR DEFAULT NULL;`},
+				}
+				for _, column := range columns {
+					var count int
+					err := tx.QueryRow(`
+						SELECT COUNT(*)
+						FROM information_schema.columns
+						WHERE table_name='tasks' AND column_name=?
+					`, column.name).Scan(&count)
+					if err != nil {
+						return fmt.Errorf("failed to check %s column: %w", column.name, err)
+					}
+					if count == 0 {
+						if _, err := tx.Exec(column.ddl); err != nil {
+							return fmt.Errorf("failed to add %s column: %w", column.name, err)
+						}
+					}
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				// Note: DuckDB supports DROP COLUMN
+				// For compatibility, we'll leave the columns
+				return nil
+			},
+		},
+		{
+			Version: 32,
+			Name:    "add_last_scored_at_to_tasks",
+			Up: func(tx *sql.Tx) error {
+				// When the task was last scored; tasks updated since then are rescored
+				var count int
+				err := tx.QueryRow(`
+					SELECT COUNT(*)
+					FROM information_schema.columns
+					WHERE table_name='tasks' AND column_name='last_scored_at'
+				`).Scan(&count)
+				if err != nil {
+					return fmt.Errorf("failed to check last_scored_at column: %w", err)
+				}
+				if count == 0 {
+					if _, err := tx.Exec(`ALTER TABLE tasks ADD COLUMN last_scored_at BIGINT DEFAULT NULL;`); err != nil {
+						return fmt.Errorf("failed to add last_scored_at column: %w", err)
+					}
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				// Note: DuckDB supports DROP COLUMN
+				// For compatibility, we'll leave the column
+				return nil
+			},
+		},
+		{
+			Version: 33,
+			Name:    "add_token_split_to_usage",
+			Up: func(tx *sql.Tx) error {
+				// The model behind an LLM call and its input and output tokens, so calls can be
+				// priced per model. tokens stays the total.
+				columns := []struct{ name, ddl string }{
+					{"model", `ALTER TABLE usage ADD COLUMN model VARCHAR DEFAULT NULL;`},
+					{"input_tokens", `ALTER TABLE usage ADD COLUMN input_tokens INTEGER DEFAULT NULL;`},
+					{"output_tokens", `ALTER TABLE usage ADD COLUMN output_tokens INTEGER DEFAULT NULL;`},
+				}
+				for _, column := range columns {
+					var count int
+					err := tx.QueryRow(`
+						SELECT COUNT(*)
+						FROM information_schema.columns
+						WHERE table_name='usage' AND column_name=?
+					`, column.name).Scan(&count)
+					if err != nil {
+						return fmt.Errorf("failed to check %s column: %w", column.name, err)
+					}
+					if count == 0 {
+						if _, err := tx.Exec(column.ddl); err != nil {
+							return fmt.Errorf("failed to add %s column: %w", column.name, err)
+						}
+					}
+				}
+
+				_, err := tx.Exec(`
+					CREATE OR REPLACE VIEW analytics_usage AS
+						SELECT id, ts, service, action, model, tokens, input_tokens, output_tokens, cost, duration_ms, error
+						FROM usage
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to update analytics_usage view: %w", err)
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				// Note: DuckDB supports DROP COLUMN
+				// For compatibility, we'll leave the columns
+				return nil
+			},
+		},
+		{
+			Version: 34,
+			Name:    "add_escalation_to_tasks",
+			Up: func(tx *sql.Tx) error {
+				// The last escalation step sent for an overdue task, and the due date it was
+				// sent for, so moving the due date starts escalation again
+				columns := []struct{ name, ddl string }{
+					{"escalation_level", `ALTER TABLE tasks ADD COLUMN escalation_level INTEGER DEFAULT 0;`},
+					{"escalated_due_ts", `ALTER TABLE tasks ADD COLUMN escalated_due_ts BIGINT DEFAULT NULL;`},
+				}
+				for _, column := range columns {
+					var count int
+					err := tx.QueryRow(`
+						SELECT COUNT(*)
+						FROM information_schema.columns
+						WHERE table_name='tasks' AND column_name=?
+					`, column.name).Scan(&count)
+					if err != nil {
+						return fmt.Errorf("failed to check %s column: %w", column.name, err)
+					}
+					if count == 0 {
+						if _, err := tx.Exec(column.ddl); err != nil {
+							return fmt.Errorf("failed to add %s column: %w", column.name, err)
+						}
+					}
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				// Note: DuckDB supports DROP COLUMN
+				// For compatibility, we'll leave the columns
+				return nil
+			},
+		},
+		{
+			Version: 35,
+			Name:    "add_auto_archived_at_to_tasks",
+			Up: func(tx *sql.Tx) error {
+				// When a task was archived for going untouched past its source's
+				// planner.auto_archive_days, so the weekly review can count them
+				var count int
+				err := tx.QueryRow(`
+					SELECT COUNT(*)
+					FROM information_schema.columns
+					WHERE table_name='tasks' AND column_name='auto_archived_at'
+				`).Scan(&count)
+				if err != nil {
+					return fmt.Errorf("failed to check auto_archived_at column: %w", err)
+				}
+
+				if count == 0 {
+					_, err = tx.Exec(`
+						ALTER TABLE tasks ADD COLUMN auto_archived_at BIGINT DEFAULT NULL;
+					`)
+					if err != nil {
+						return fmt.Errorf("failed to add auto_archived_at column: %w", err)
+					}
+				}
+
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				// Note: DuckDB supports DROP COLUMN
+				// For compatibility, we'll leave the column
+				return nil
+			},
+		},
+		{
+			Version: 36,
+			Name:    "create_api_keys_table",
+			Up: func(tx *sql.Tx) error {
+				// Per-client keys for the API server, alongside api.auth_key. Only a hash of
+				// each key is kept; prefix is its first few characters, to tell keys apart.
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS api_keys (
+						id VARCHAR PRIMARY KEY,
+						name VARCHAR NOT NULL,
+						key_hash VARCHAR NOT NULL,
+						prefix VARCHAR NOT NULL,
+						created_at BIGINT NOT NULL,
+						last_used_at BIGINT,
+						revoked_at BIGINT
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create api_keys table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS api_keys`)
+				return err
+			},
+		},
+		{
+			Version: 37,
+			Name:    "create_task_enrichments_table",
+			Up: func(tx *sql.Tx) error {
+				// What each task's description was last enriched from: the size and latest
+				// message of its thread, and a hash of the description written, so a later
+				// edit by the user isn't overwritten
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS task_enrichments (
+						task_id VARCHAR PRIMARY KEY,
+						message_count INTEGER NOT NULL,
+						last_message_ts BIGINT NOT NULL,
+						description_hash VARCHAR NOT NULL,
+						enriched_at BIGINT NOT NULL
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create task_enrichments table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS task_enrichments`)
+				return err
+			},
+		},
+		{
+			Version: 38,
+			Name:    "create_contacts_table",
+			Up: func(tx *sql.Tx) error {
+				// Per-recipient preferences, keyed by lowercase email address. For now only the
+				// drafting persona last used when replying to them.
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS contacts (
+						email VARCHAR PRIMARY KEY,
+						draft_persona VARCHAR,
+						updated_at BIGINT NOT NULL
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create contacts table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS contacts`)
+				return err
+			},
+		},
+		{
+			Version: 39,
+			Name:    "create_thread_insights_table",
+			Up: func(tx *sql.Tx) error {
+				// Depth of each thread's latest summary, and the structured fields of a deep
+				// summary (JSON string arrays, empty below deep)
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS thread_insights (
+						thread_id VARCHAR PRIMARY KEY,
+						depth VARCHAR NOT NULL,
+						decisions VARCHAR,
+						owners VARCHAR,
+						risks VARCHAR,
+						open_questions VARCHAR,
+						updated_at BIGINT NOT NULL
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create thread_insights table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS thread_insights`)
+				return err
+			},
+		},
+		{
+			Version: 40,
+			Name:    "add_contact_stats",
+			Up: func(tx *sql.Tx) error {
+				// Relationship stats per contact, rebuilt from the messages exchanged with them.
+				// Response times are average seconds, NULL until there's a reply to measure.
+				_, err := tx.Exec(`
+					ALTER TABLE contacts ADD COLUMN name VARCHAR;
+					ALTER TABLE contacts ADD COLUMN thread_count INTEGER DEFAULT 0;
+					ALTER TABLE contacts ADD COLUMN message_count INTEGER DEFAULT 0;
+					ALTER TABLE contacts ADD COLUMN tasks_owed INTEGER DEFAULT 0;
+					ALTER TABLE contacts ADD COLUMN waiting_on INTEGER DEFAULT 0;
+					ALTER TABLE contacts ADD COLUMN last_interaction_ts BIGINT;
+					ALTER TABLE contacts ADD COLUMN my_response_secs BIGINT;
+					ALTER TABLE contacts ADD COLUMN their_response_secs BIGINT;
+					ALTER TABLE contacts ADD COLUMN stats_updated_at BIGINT;
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to add contact stats columns: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				for _, column := range []string{"name", "thread_count", "message_count", "tasks_owed", "waiting_on",
+					"last_interaction_ts", "my_response_secs", "their_response_secs", "stats_updated_at"} {
+					if _, err := tx.Exec(`ALTER TABLE contacts DROP COLUMN IF EXISTS ` + column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version: 41,
+			Name:    "add_cold_messages",
+			Up: func(tx *sql.Tx) error {
+				// Messages whose body was moved out to a compressed parquet file. The message row
+				// keeps its metadata with an empty body; file is where the body can be read back.
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS cold_messages (
+						message_id VARCHAR PRIMARY KEY,
+						file VARCHAR NOT NULL,
+						archived_at BIGINT NOT NULL
+					);
+					CREATE INDEX IF NOT EXISTS idx_cold_messages_file ON cold_messages(file);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create cold_messages table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS cold_messages`)
+				return err
+			},
+		},
+		{
+			Version: 42,
+			Name:    "create_commitments_table",
+			Up: func(tx *sql.Tx) error {
+				// Promises the user made in sent mail, one row each: status is open, delivered or
+				// dismissed, or none (with id = message_id) when the message promised nothing.
+				// kind is what closes it: any later reply in the thread, or one with an attachment.
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS commitments (
+						id VARCHAR PRIMARY KEY,
+						message_id VARCHAR NOT NULL,
+						thread_id VARCHAR NOT NULL,
+						subject VARCHAR,
+						recipient VARCHAR,
+						promise VARCHAR,
+						kind VARCHAR,
+						sent_ts BIGINT NOT NULL,
+						due_ts BIGINT,
+						status VARCHAR NOT NULL,
+						closed_at BIGINT,
+						created_at BIGINT NOT NULL
+					);
+					CREATE INDEX IF NOT EXISTS idx_commitments_message ON commitments(message_id);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create commitments table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS commitments`)
+				return err
+			},
+		},
+		{
+			Version: 43,
+			Name:    "add_organizer_to_events",
+			Up: func(tx *sql.Tx) error {
+				// Who organized an event and the user's own RSVP (accepted, tentative, declined or
+				// needsAction), used to suggest meetings to decline
+				columns := []struct{ name, ddl string }{
+					{"organizer", `ALTER TABLE events ADD COLUMN organizer VARCHAR DEFAULT NULL;`},
+					{"response", `ALTER TABLE events ADD COLUMN response VARCHAR DEFAULT NULL;`},
+				}
+				for _, column := range columns {
+					var count int
+					err := tx.QueryRow(`
+						SELECT COUNT(*)
+						FROM information_schema.columns
+						WHERE table_name='events' AND column_name=?
+					`, column.name).Scan(&count)
+					if err != nil {
+						return fmt.Errorf("failed to check %s column: %w", column.name, err)
+					}
+					if count == 0 {
+						if _, err := tx.Exec(column.ddl); err != nil {
+							return fmt.Errorf("failed to add %s column: %w", column.name, err)
+						}
+					}
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				// Note: DuckDB supports DROP COLUMN
+				// For compatibility, we'll leave the columns
+				return nil
+			},
+		},
+		{
+			Version: 44,
+			Name:    "create_captured_notes_table",
+			Up: func(tx *sql.Tx) error {
+				// Quick notes already turned into tasks, so each is only processed once: Keep notes
+				// by their resource name, inbox doc paragraphs by the doc and a hash of their text
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS captured_notes (
+						id VARCHAR PRIMARY KEY,
+						source VARCHAR NOT NULL,
+						title VARCHAR,
+						tasks INTEGER NOT NULL DEFAULT 0,
+						captured_at BIGINT NOT NULL
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create captured_notes table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS captured_notes`)
+				return err
+			},
+		},
+		{
+			Version: 45,
+			Name:    "create_front_webhook_events_table",
+			Up: func(tx *sql.Tx) error {
+				// IDs of Front webhook events already applied, so a replayed delivery is ignored
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS front_webhook_events (
+						id VARCHAR PRIMARY KEY,
+						received_at BIGINT NOT NULL
+					);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create front_webhook_events table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS front_webhook_events`)
+				return err
+			},
+		},
+		{
+			Version: 46,
+			Name:    "create_work_sessions_table",
+			Up: func(tx *sql.Tx) error {
+				// Time spent working on a task, started and stopped from the TUI or API, with how
+				// often the work was interrupted
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS work_sessions (
+						id VARCHAR PRIMARY KEY,
+						task_id VARCHAR NOT NULL,
+						started_at BIGINT NOT NULL,
+						ended_at BIGINT,
+						interruptions INTEGER NOT NULL DEFAULT 0
+					);
+					CREATE INDEX IF NOT EXISTS idx_work_sessions_task ON work_sessions(task_id);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create work_sessions table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS work_sessions`)
+				return err
+			},
+		},
+		{
+			Version: 47,
+			Name:    "create_sender_stats_table",
+			Up: func(tx *sql.Tx) error {
+				// How the user has treated each sender's mail, rebuilt with the people stats, so
+				// relevance and model selection can learn from it
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS sender_stats (
+						sender VARCHAR PRIMARY KEY,
+						threads INTEGER NOT NULL DEFAULT 0,
+						replied INTEGER NOT NULL DEFAULT 0,
+						extracted INTEGER NOT NULL DEFAULT 0,
+						accepted INTEGER NOT NULL DEFAULT 0,
+						discarded INTEGER NOT NULL DEFAULT 0,
+						updated_at BIGINT NOT NULL
+					)
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create sender_stats table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS sender_stats`)
+				return err
+			},
+		},
+		{
+			Version: 48,
+			Name:    "add_modified_by_to_docs",
+			Up: func(tx *sql.Tx) error {
+				// Who last edited a Drive document, so briefs can tell the user's own edits from
+				// their stakeholders'
+				var count int
+				err := tx.QueryRow(`
+					SELECT COUNT(*)
+					FROM information_schema.columns
+					WHERE table_name='docs' AND column_name='modified_by'
+				`).Scan(&count)
+				if err != nil {
+					return fmt.Errorf("failed to check modified_by column: %w", err)
+				}
+				if count == 0 {
+					if _, err := tx.Exec(`ALTER TABLE docs ADD COLUMN modified_by VARCHAR DEFAULT NULL;`); err != nil {
+						return fmt.Errorf("failed to add modified_by column: %w", err)
+					}
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				// Note: DuckDB supports DROP COLUMN
+				// For compatibility, we'll leave the column
+				return nil
+			},
+		},
+		{
+			Version: 49,
+			Name:    "create_chat_messages_table",
+			Up: func(tx *sql.Tx) error {
+				// Messages read from the Google Chat spaces and DMs under chat.sync, kept for task
+				// extraction and as context for the tasks extracted from them
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS chat_messages (
+						id VARCHAR PRIMARY KEY,
+						space VARCHAR NOT NULL,
+						space_name VARCHAR,
+						thread VARCHAR,
+						sender VARCHAR,
+						sender_name VARCHAR,
+						from_user BOOLEAN NOT NULL DEFAULT false,
+						text VARCHAR,
+						ts BIGINT NOT NULL,
+						processed_at BIGINT
+					);
+					CREATE INDEX IF NOT EXISTS idx_chat_messages_space ON chat_messages(space);
+					CREATE INDEX IF NOT EXISTS idx_chat_messages_thread ON chat_messages(thread);
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create chat_messages table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS chat_messages`)
+				return err
+			},
+		},
+		{
+			Version: 50,
+			Name:    "create_task_edits_table",
+			Up: func(tx *sql.Tx) error {
+				// Fields of a task the user has edited by hand, kept when the task is extracted
+				// again
+				_, err := tx.Exec(`
+					CREATE TABLE IF NOT EXISTS task_edits (
+						task_id VARCHAR NOT NULL,
+						field VARCHAR NOT NULL,
+						edited_at BIGINT NOT NULL,
+						PRIMARY KEY (task_id, field)
+					)
+				`)
+				if err != nil {
+					return fmt.Errorf("failed to create task_edits table: %w", err)
+				}
+				return nil
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`DROP TABLE IF EXISTS task_edits`)
+				return err
+			},
+		},
 		// Add future migrations here
 	}
 }
@@ -7,23 +7,25 @@ import (
 	"time"
 
 	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/metrics"
 )
 
 // Message represents an email message
 type Message struct {
-	ID          string    `json:"id"`
-	ThreadID    string    `json:"thread_id"`
-	From        string    `json:"from"`
-	To          string    `json:"to"`
-	Subject     string    `json:"subject"`
-	Snippet     string    `json:"snippet"`
-	Body        string    `json:"body"`
-	Timestamp   time.Time `json:"timestamp"`
-	LastMsgID   string    `json:"last_msg_id"`
-	Labels      []string  `json:"labels"`
-	Sensitivity string    `json:"sensitivity"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          string        `json:"id"`
+	ThreadID    string        `json:"thread_id"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Subject     string        `json:"subject"`
+	Snippet     string        `json:"snippet"`
+	Body        string        `json:"body"`
+	Timestamp   time.Time     `json:"timestamp"`
+	LastMsgID   string        `json:"last_msg_id"`
+	Labels      []string      `json:"labels"`
+	Sensitivity string        `json:"sensitivity"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	Attachments []*Attachment `json:"attachments,omitempty"` // Only filled in where prompts need them
 }
 
 // Thread represents an email conversation
@@ -43,24 +45,29 @@ type Thread struct {
 
 // Task represents a work item
 type Task struct {
-	ID                 string     `json:"id"`
-	Source             string     `json:"source"`
-	SourceID           string     `json:"source_id"`
-	Title              string     `json:"title"`
-	Description        string     `json:"description"`
-	DueTS              *time.Time `json:"due_ts"`
-	Project            string     `json:"project"`
-	Impact             int        `json:"impact"`
-	Urgency            int        `json:"urgency"`
-	Effort             string     `json:"effort"`
-	Stakeholder        string     `json:"stakeholder"`
-	Score              float64    `json:"score"`
-	Status             string     `json:"status"`
-	Metadata           string     `json:"metadata"`
-	MatchedPriorities  string     `json:"matched_priorities"` // JSON string storing which priorities matched
-	CreatedAt          time.Time  `json:"created_at"`
-	UpdatedAt          time.Time  `json:"updated_at"`
-	CompletedAt        *time.Time `json:"completed_at"`
+	ID                string         `json:"id"`
+	Source            string         `json:"source"`
+	SourceID          string         `json:"source_id"`
+	Title             string         `json:"title"`
+	Description       string         `json:"description"`
+	DueTS             *time.Time     `json:"due_ts"`
+	Project           string         `json:"project"`
+	Impact            int            `json:"impact"`
+	Urgency           int            `json:"urgency"`
+	Effort            string         `json:"effort"`
+	Stakeholder       string         `json:"stakeholder"`
+	Score             float64        `json:"score"`
+	Status            string         `json:"status"`
+	Metadata          string         `json:"metadata"`
+	MatchedPriorities string         `json:"matched_priorities"`    // JSON string storing which priorities matched
+	Recurrence        string         `json:"recurrence,omitempty"`  // Recurrence rule, empty for one-off tasks
+	RecursFrom        string         `json:"recurs_from,omitempty"` // Completed occurrence this task was spawned from
+	Tags              []string       `json:"tags,omitempty"`        // Free-form tags, filled in by GetAllTasks, GetTasksByTag and GetTaskByID
+	ClaimedBy         string         `json:"claimed_by,omitempty"`  // Delegate working the task, filled in with the tags
+	Comments          []*TaskComment `json:"comments,omitempty"`    // Delegation comments, oldest first, filled in with the tags
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	CompletedAt       *time.Time     `json:"completed_at"`
 }
 
 // PriorityMatches represents which priority areas matched for a task
@@ -80,6 +87,7 @@ type Document struct {
 	MeetingID  string    `json:"meeting_id"`
 	Summary    string    `json:"summary"`
 	Owner      string    `json:"owner"`
+	ModifiedBy string    `json:"modified_by"` // Email of whoever last edited it
 	UpdatedTS  time.Time `json:"updated_ts"`
 	LastSynced time.Time `json:"last_synced"`
 	CreatedAt  time.Time `json:"created_at"`
@@ -96,6 +104,10 @@ type Event struct {
 	Attendees   []string  `json:"attendees"`
 	MeetingLink string    `json:"meeting_link"`
 	Status      string    `json:"status"`
+	Organizer   string    `json:"organizer,omitempty"`    // Organizer's address
+	Response    string    `json:"response,omitempty"`     // The user's RSVP: accepted, tentative, declined or needsAction
+	PrepNotes   string    `json:"prep_notes,omitempty"`   // Generated meeting prep, if any
+	PrepChecked bool      `json:"prep_checked,omitempty"` // Prep has been generated, or the meeting was found to need none
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -189,8 +201,13 @@ func (db *DB) SaveMessage(msg *Message) error {
 	return err
 }
 
-// SaveThread inserts or updates a thread
+// SaveThread inserts or updates a thread. Replacing a summary records what changed in it.
 func (db *DB) SaveThread(thread *Thread) error {
+	var previousSummary string
+	if thread.Summary != "" {
+		previousSummary = db.getThreadSummary(thread.ID)
+	}
+
 	var nextFollowup *int64
 	if thread.NextFollowupTS != nil {
 		ts := thread.NextFollowupTS.Unix()
@@ -215,11 +232,27 @@ func (db *DB) SaveThread(thread *Thread) error {
 		thread.ID, thread.LastHistoryID, thread.Summary, thread.SummaryHash,
 		thread.TaskCount, thread.PriorityScore, thread.RelevantToUser, nextFollowup, thread.LastSynced.Unix(),
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if previousSummary != "" && previousSummary != thread.Summary {
+		return db.recordSummaryChange(thread.ID, previousSummary, thread.Summary)
+	}
+	return nil
+}
+
+// execer runs a statement on the database or inside a transaction
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
 }
 
 // SaveTask inserts or updates a task
 func (db *DB) SaveTask(task *Task) error {
+	return saveTask(db, task)
+}
+
+func saveTask(ex execer, task *Task) error {
 	var dueTS *int64
 	if task.DueTS != nil {
 		ts := task.DueTS.Unix()
@@ -246,8 +279,8 @@ func (db *DB) SaveTask(task *Task) error {
 	// Note: DuckDB doesn't allow updating indexed columns in ON CONFLICT DO UPDATE
 	// Indexed columns: status, due_ts, score, source, source_id
 	query := `
-		INSERT INTO tasks (id, source, source_id, title, description, due_ts, project, impact, urgency, effort, stakeholder, score, status, metadata, completed_at, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO tasks (id, source, source_id, title, description, due_ts, project, impact, urgency, effort, stakeholder, score, status, metadata, recurrence, recurs_from, completed_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			title = excluded.title,
 			description = excluded.description,
@@ -257,14 +290,16 @@ func (db *DB) SaveTask(task *Task) error {
 			effort = excluded.effort,
 			stakeholder = excluded.stakeholder,
 			metadata = excluded.metadata,
+			recurrence = excluded.recurrence,
 			completed_at = excluded.completed_at,
 			updated_at = excluded.updated_at
 	`
 
-	_, err := db.Exec(query,
+	_, err := ex.Exec(query,
 		task.ID, task.Source, task.SourceID, task.Title, task.Description, dueTS,
 		task.Project, task.Impact, task.Urgency, task.Effort, task.Stakeholder,
-		task.Score, task.Status, task.Metadata, completedTS, createdTS, updatedTS,
+		task.Score, task.Status, task.Metadata, task.Recurrence, task.RecursFrom,
+		completedTS, createdTS, updatedTS,
 	)
 	return err
 }
@@ -275,7 +310,8 @@ func (db *DB) GetPendingTasks(limit int) ([]*Task, error) {
 	query := `
 		SELECT id, source, source_id, title, description, due_ts, project,
 		       impact, urgency, effort, stakeholder, score, status, metadata,
-		       matched_priorities, created_at, updated_at, completed_at
+		       matched_priorities, created_at, updated_at, completed_at,
+		       COALESCE(recurrence, ''), COALESCE(recurs_from, '')
 		FROM tasks
 		WHERE status = 'pending'
 		  AND (
@@ -305,6 +341,7 @@ func (db *DB) GetPendingTasks(limit int) ([]*Task, error) {
 			&dueTS, &task.Project, &task.Impact, &task.Urgency, &task.Effort,
 			&task.Stakeholder, &task.Score, &task.Status, &task.Metadata,
 			&matchedPriorities, &createdTS, &updatedTS, &completedTS,
+			&task.Recurrence, &task.RecursFrom,
 		)
 		if err != nil {
 			return nil, err
@@ -339,7 +376,8 @@ func (db *DB) GetTaskByID(taskID string) (*Task, error) {
 	query := `
 		SELECT id, source, source_id, title, description, due_ts, project,
 		       impact, urgency, effort, stakeholder, score, status, metadata,
-		       matched_priorities, created_at, updated_at, completed_at
+		       matched_priorities, created_at, updated_at, completed_at,
+		       COALESCE(recurrence, ''), COALESCE(recurs_from, '')
 		FROM tasks
 		WHERE id = ?
 	`
@@ -353,6 +391,7 @@ func (db *DB) GetTaskByID(taskID string) (*Task, error) {
 		&dueTS, &task.Project, &task.Impact, &task.Urgency, &task.Effort,
 		&task.Stakeholder, &task.Score, &task.Status, &task.Metadata,
 		&matchedPriorities, &createdTS, &updatedTS, &completedTS,
+		&task.Recurrence, &task.RecursFrom,
 	)
 	if err != nil {
 		return nil, err
@@ -376,6 +415,13 @@ func (db *DB) GetTaskByID(taskID string) (*Task, error) {
 		task.CompletedAt = &t
 	}
 
+	if err := db.loadTaskTags([]*Task{task}); err != nil {
+		return nil, err
+	}
+	if err := db.loadTaskDelegation([]*Task{task}); err != nil {
+		return nil, err
+	}
+
 	return task, nil
 }
 
@@ -385,7 +431,8 @@ func (db *DB) GetAllTasks(limit int) ([]*Task, error) {
 	query := `
 		SELECT id, source, source_id, title, description, due_ts, project,
 		       impact, urgency, effort, stakeholder, score, status, metadata,
-		       matched_priorities, created_at, updated_at, completed_at
+		       matched_priorities, created_at, updated_at, completed_at,
+		       COALESCE(recurrence, ''), COALESCE(recurs_from, '')
 		FROM tasks
 		WHERE (
 		    stakeholder IS NULL
@@ -414,6 +461,7 @@ func (db *DB) GetAllTasks(limit int) ([]*Task, error) {
 			&dueTS, &task.Project, &task.Impact, &task.Urgency, &task.Effort,
 			&task.Stakeholder, &task.Score, &task.Status, &task.Metadata,
 			&matchedPriorities, &createdTS, &updatedTS, &completedTS,
+			&task.Recurrence, &task.RecursFrom,
 		)
 		if err != nil {
 			return nil, err
@@ -440,6 +488,13 @@ func (db *DB) GetAllTasks(limit int) ([]*Task, error) {
 		tasks = append(tasks, task)
 	}
 
+	if err := db.loadTaskTags(tasks); err != nil {
+		return nil, err
+	}
+	if err := db.loadTaskDelegation(tasks); err != nil {
+		return nil, err
+	}
+
 	return tasks, nil
 }
 
@@ -449,14 +504,59 @@ func (db *DB) GetUpcomingEvents(hours int) ([]*Event, error) {
 	endTime := now.Add(time.Duration(hours) * time.Hour)
 
 	query := `
-		SELECT id, title, start_ts, end_ts, location, description,
-		       attendees, meeting_link, status
+		SELECT ` + eventColumns + `
 		FROM events
-		WHERE start_ts >= ? AND start_ts <= ?
+		WHERE start_ts >= ? AND start_ts <= ? AND COALESCE(status, '') != 'cancelled'
 		ORDER BY start_ts ASC
 	`
 
-	rows, err := db.Query(query, now.Unix(), endTime.Unix())
+	return db.queryEvents(query, now.Unix(), endTime.Unix())
+}
+
+// GetAgenda returns the events starting in a period, cancelled ones included, earliest first
+func (db *DB) GetAgenda(start, end time.Time) ([]*Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE start_ts >= ? AND start_ts < ?
+		ORDER BY start_ts ASC, title ASC
+	`
+
+	events, err := db.queryEvents(query, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agenda: %w", err)
+	}
+	return events, nil
+}
+
+// GetEvent returns an event by ID, or nil if there's no such event
+func (db *DB) GetEvent(id string) (*Event, error) {
+	events, err := db.queryEvents(`SELECT `+eventColumns+` FROM events WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+	return events[0], nil
+}
+
+// SetEventResponse records the user's RSVP to an event until the next calendar sync confirms it
+func (db *DB) SetEventResponse(id, response string) error {
+	if _, err := db.Exec(`UPDATE events SET response = ? WHERE id = ?`, response, id); err != nil {
+		return fmt.Errorf("failed to update event response: %w", err)
+	}
+	return nil
+}
+
+// eventColumns are the columns queryEvents scans
+const eventColumns = `id, title, start_ts, end_ts, COALESCE(location, ''), COALESCE(description, ''),
+		       COALESCE(attendees, ''), COALESCE(meeting_link, ''), COALESCE(status, ''), COALESCE(organizer, ''),
+		       COALESCE(response, ''), COALESCE(prep_notes, ''), prep_generated_at IS NOT NULL`
+
+// queryEvents runs a query selecting eventColumns and scans the events it returns
+func (db *DB) queryEvents(query string, args ...interface{}) ([]*Event, error) {
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -471,7 +571,8 @@ func (db *DB) GetUpcomingEvents(hours int) ([]*Event, error) {
 		err := rows.Scan(
 			&event.ID, &event.Title, &startTS, &endTS,
 			&event.Location, &event.Description, &attendeesJSON,
-			&event.MeetingLink, &event.Status,
+			&event.MeetingLink, &event.Status, &event.Organizer, &event.Response,
+			&event.PrepNotes, &event.PrepChecked,
 		)
 		if err != nil {
 			return nil, err
@@ -515,6 +616,33 @@ func (db *DB) GetSyncState(service string) (*SyncState, error) {
 	return state, nil
 }
 
+// GetSyncStates returns when each service last synced and is next due, without its sync
+// cursor, ordered by service
+func (db *DB) GetSyncStates() ([]*SyncState, error) {
+	rows, err := db.Query(`
+		SELECT service, COALESCE(last_sync, 0), COALESCE(next_sync, 0), COALESCE(error_count, 0), COALESCE(last_error, '')
+		FROM sync_state
+		ORDER BY service
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sync state: %w", err)
+	}
+	defer rows.Close()
+
+	var states []*SyncState
+	for rows.Next() {
+		state := &SyncState{}
+		var lastSync, nextSync int64
+		if err := rows.Scan(&state.Service, &lastSync, &nextSync, &state.ErrorCount, &state.LastError); err != nil {
+			return nil, err
+		}
+		state.LastSync = time.Unix(lastSync, 0)
+		state.NextSync = time.Unix(nextSync, 0)
+		states = append(states, state)
+	}
+	return states, rows.Err()
+}
+
 // SaveSyncState updates sync state for a service
 func (db *DB) SaveSyncState(state *SyncState) error {
 	query := `
@@ -544,9 +672,84 @@ func (db *DB) LogUsage(service, action string, tokens int, cost float64, duratio
 
 	query := `INSERT INTO usage (service, action, tokens, cost, duration_ms, error) VALUES (?, ?, ?, ?, ?, ?)`
 	_, dbErr := db.Exec(query, service, action, tokens, cost, duration.Milliseconds(), errStr)
+
+	// Failures show up as issues until the operation succeeds again
+	db.TrackResult(service, action, err)
 	return dbErr
 }
 
+// LogKeyUsage records API usage against one of a service's API keys, identified by a label
+func (db *DB) LogKeyUsage(service, apiKey, action string, tokens int, cost float64, duration time.Duration, err error) error {
+	var errStr string
+	if err != nil {
+		errStr = err.Error()
+	}
+
+	var key any // NULL when no key was used, e.g. every key was out of quota
+	if apiKey != "" {
+		key = apiKey
+	}
+
+	query := `INSERT INTO usage (service, api_key, action, tokens, cost, duration_ms, error) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, dbErr := db.Exec(query, service, key, action, tokens, cost, duration.Milliseconds(), errStr)
+
+	// Keyed services are LLM providers, which log successful calls through LogLLMUsage
+	if err != nil {
+		metrics.LLMCalls.Inc(service, "error")
+	}
+
+	db.TrackResult(service, action, err)
+	return dbErr
+}
+
+// LLMUsage is one successful LLM call, with its tokens split into input and output so it can
+// be priced per model
+type LLMUsage struct {
+	Service      string
+	APIKey       string // Label of the API key used, if the service has several
+	Action       string
+	Model        string
+	InputTokens  int
+	OutputTokens int
+	Cost         float64 // USD
+	Duration     time.Duration
+}
+
+// LogLLMUsage records a successful LLM call
+func (db *DB) LogLLMUsage(u *LLMUsage) error {
+	var key any
+	if u.APIKey != "" {
+		key = u.APIKey
+	}
+
+	query := `
+		INSERT INTO usage (service, api_key, action, model, tokens, input_tokens, output_tokens, cost, duration_ms, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, '')
+	`
+	_, err := db.Exec(query, u.Service, key, u.Action, u.Model, u.InputTokens+u.OutputTokens,
+		u.InputTokens, u.OutputTokens, u.Cost, u.Duration.Milliseconds())
+
+	metrics.LLMCalls.Inc(u.Service, "ok")
+	metrics.LLMTokens.Add(float64(u.InputTokens), u.Service, "input")
+	metrics.LLMTokens.Add(float64(u.OutputTokens), u.Service, "output")
+	metrics.LLMCost.Add(u.Cost, u.Service)
+	metrics.LLMDuration.Observe(u.Duration.Seconds(), u.Service)
+
+	db.TrackResult(u.Service, u.Action, nil)
+	return err
+}
+
+// GetServiceUsageSince sums the tokens and cost a service has logged since the given time
+func (db *DB) GetServiceUsageSince(service string, since time.Time) (int, float64, error) {
+	var tokens int
+	var cost float64
+	query := `SELECT COALESCE(SUM(tokens), 0), COALESCE(SUM(cost), 0) FROM usage WHERE service = ? AND ts >= ?`
+	if err := db.QueryRow(query, service, since.Unix()).Scan(&tokens, &cost); err != nil {
+		return 0, 0, fmt.Errorf("failed to sum usage for %s: %w", service, err)
+	}
+	return tokens, cost, nil
+}
+
 // GetCachedResponse retrieves a cached LLM response
 func (db *DB) GetCachedResponse(hash string) (*LLMCache, error) {
 	cache := &LLMCache{}
@@ -561,10 +764,12 @@ func (db *DB) GetCachedResponse(hash string) (*LLMCache, error) {
 	)
 
 	if err == sql.ErrNoRows {
+		metrics.CacheLookups.Inc("miss")
 		return nil, nil
 	} else if err != nil {
 		return nil, err
 	}
+	metrics.CacheLookups.Inc("hit")
 
 	cache.Hash = hash
 	cache.CreatedAt = time.Unix(createdTS, 0)
@@ -629,7 +834,9 @@ func (db *DB) SearchMessages(query string, limit int) ([]*Message, error) {
 		LIMIT ?
 	`
 
+	start := time.Now()
 	rows, err := db.Query(searchQuery, query, limit)
+	db.ObserveQuery(QueryKindFTS, start, err)
 	if err != nil {
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
@@ -660,30 +867,67 @@ func (db *DB) SearchMessages(query string, limit int) ([]*Message, error) {
 	return messages, nil
 }
 
-// SaveEvent saves an event to the database
+// GetEventsBetween returns events that overlap the given period, skipping cancelled ones
+func (db *DB) GetEventsBetween(start, end time.Time) ([]*Event, error) {
+	query := `
+		SELECT ` + eventColumns + `
+		FROM events
+		WHERE start_ts < ? AND end_ts > ? AND COALESCE(status, '') != 'cancelled'
+		ORDER BY start_ts ASC
+	`
+
+	return db.queryEvents(query, end.Unix(), start.Unix())
+}
+
+// SaveEvent saves an event to the database, recording a change if a synced event was moved
+// or cancelled
 func (db *DB) SaveEvent(event *Event) error {
+	existing, err := db.getEvent(event.ID)
+	if err != nil {
+		return err
+	}
+
 	attendeesJSON, _ := json.Marshal(event.Attendees)
 
 	// Note: DuckDB doesn't allow updating indexed columns in ON CONFLICT DO UPDATE
 	// Indexed columns: start_ts, end_ts
 	query := `
-		INSERT INTO events (id, title, start_ts, end_ts, location, description, attendees, meeting_link, status)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO events (id, title, start_ts, end_ts, location, description, attendees, meeting_link, status,
+		                    organizer, response)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			title = excluded.title,
 			location = excluded.location,
 			description = excluded.description,
 			attendees = excluded.attendees,
 			meeting_link = excluded.meeting_link,
-			status = excluded.status
+			status = excluded.status,
+			organizer = excluded.organizer,
+			response = excluded.response
 	`
 
-	_, err := db.Exec(query,
+	_, err = db.Exec(query,
 		event.ID, event.Title, event.StartTS.Unix(), event.EndTS.Unix(),
 		event.Location, event.Description, string(attendeesJSON),
-		event.MeetingLink, event.Status,
+		event.MeetingLink, event.Status, event.Organizer, event.Response,
 	)
-	return err
+	if err != nil || existing == nil {
+		return err
+	}
+
+	// The upsert can't touch the indexed times, so a moved event is updated separately
+	if existing.StartTS.Unix() != event.StartTS.Unix() || existing.EndTS.Unix() != event.EndTS.Unix() {
+		_, err = db.Exec(`UPDATE events SET start_ts = ?, end_ts = ? WHERE id = ?`,
+			event.StartTS.Unix(), event.EndTS.Unix(), event.ID)
+		if err != nil {
+			return fmt.Errorf("failed to update event times: %w", err)
+		}
+	}
+
+	if change := detectEventChange(existing, event); change != nil {
+		return db.recordEventChange(change)
+	}
+	return nil
 }
 
 // SaveDocument saves a document to the database
@@ -691,22 +935,33 @@ func (db *DB) SaveDocument(doc *Document) error {
 	// Note: DuckDB doesn't allow updating indexed columns in ON CONFLICT DO UPDATE
 	// Indexed columns: meeting_id, updated_ts
 	query := `
-		INSERT INTO docs (id, title, link, mime_type, meeting_id, summary, owner, updated_ts, last_synced)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO docs (id, title, link, mime_type, meeting_id, summary, owner, modified_by, updated_ts, last_synced)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			title = excluded.title,
 			link = excluded.link,
 			mime_type = excluded.mime_type,
 			summary = excluded.summary,
 			owner = excluded.owner,
+			modified_by = excluded.modified_by,
 			last_synced = excluded.last_synced
 	`
 
 	_, err := db.Exec(query,
 		doc.ID, doc.Title, doc.Link, doc.MimeType, doc.MeetingID,
-		doc.Summary, doc.Owner, doc.UpdatedTS.Unix(), doc.LastSynced.Unix(),
+		doc.Summary, doc.Owner, doc.ModifiedBy, doc.UpdatedTS.Unix(), doc.LastSynced.Unix(),
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	// Keep the modified time current for documents edited since they were first synced
+	_, err = db.Exec(`UPDATE docs SET updated_ts = ? WHERE id = ? AND COALESCE(updated_ts, 0) <> ?`,
+		doc.UpdatedTS.Unix(), doc.ID, doc.UpdatedTS.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to update document modified time: %w", err)
+	}
+	return nil
 }
 
 // GetThreadsWithSummaries returns threads that have AI-generated summaries
@@ -716,6 +971,7 @@ func (db *DB) GetThreadsWithSummaries(limit int) ([]*Thread, error) {
 		       t.priority_score, t.relevant_to_user, t.next_followup_ts, t.last_synced, t.created_at, t.updated_at
 		FROM threads t
 		WHERE t.summary IS NOT NULL AND t.summary != ''
+		  AND t.id NOT IN (SELECT thread_id FROM archived_threads)
 		ORDER BY t.priority_score DESC, t.last_synced DESC
 		LIMIT ?
 	`
@@ -848,7 +1104,13 @@ func (db *DB) GetThreadMessages(threadID string) ([]*Message, error) {
 
 		messages = append(messages, msg)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
+	if err := db.restoreColdBodies(messages); err != nil {
+		return nil, err
+	}
 	return messages, nil
 }
 // RecalculateThreadPriorities updates priority scores for all threads based on their tasks
@@ -893,6 +1155,58 @@ func (db *DB) RecalculateThreadPriorities() error {
 	return nil
 }
 
+// IsThreadPriorityLabeled reports whether the Gmail priority label was applied to a thread
+func (db *DB) IsThreadPriorityLabeled(threadID string) (bool, error) {
+	var labeled sql.NullBool
+	query := `SELECT priority_label_applied FROM threads WHERE id = ?`
+	if err := db.QueryRow(query, threadID).Scan(&labeled); err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+	return labeled.Valid && labeled.Bool, nil
+}
+
+// SetThreadPriorityLabeled records whether the Gmail priority label is on a thread
+func (db *DB) SetThreadPriorityLabeled(threadID string, labeled bool) error {
+	query := `UPDATE threads SET priority_label_applied = ? WHERE id = ?`
+	if _, err := db.Exec(query, labeled, threadID); err != nil {
+		return fmt.Errorf("failed to update priority label state: %w", err)
+	}
+	return nil
+}
+
+// GetPriorityLabelChanges returns the Gmail threads whose priority label no longer matches their
+// task scores, with the highest score among each thread's pending tasks: labelled threads with no
+// pending task at the threshold any more, whether scores dropped or the tasks were done, and
+// unlabelled threads with a pending task at or above it. Only Gmail thread IDs (as in
+// google.IsGmailThreadID) are considered.
+func (db *DB) GetPriorityLabelChanges(threshold float64, limit int) (map[string]float64, error) {
+	query := `
+		SELECT th.id, COALESCE(MAX(t.score) FILTER (WHERE t.status = 'pending'), 0) AS score
+		FROM threads th
+		LEFT JOIN tasks t ON t.source = 'gmail' AND t.source_id = th.id
+		WHERE regexp_full_match(th.id, '[0-9a-f]{1,20}')
+		GROUP BY th.id, th.priority_label_applied
+		HAVING COALESCE(th.priority_label_applied, false) <> (score >= ?)
+		LIMIT ?
+	`
+	rows, err := db.Query(query, threshold, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query priority label changes: %w", err)
+	}
+	defer rows.Close()
+
+	scores := make(map[string]float64)
+	for rows.Next() {
+		var threadID string
+		var score float64
+		if err := rows.Scan(&threadID, &score); err != nil {
+			return nil, err
+		}
+		scores[threadID] = score
+	}
+	return scores, rows.Err()
+}
+
 // AddPriority adds a new priority to the database
 func (db *DB) AddPriority(priorityType, value, notes string) (*Priority, error) {
 	// Use nanoseconds for unique IDs even when adding multiple in same second
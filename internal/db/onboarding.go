@@ -0,0 +1,40 @@
+package db
+
+import (
+	"fmt"
+)
+
+// SetupProgress tracks how far a new user has got through setting up the agent
+type SetupProgress struct {
+	Authenticated   bool `json:"authenticated"`    // Google account connected and identified
+	Synced          bool `json:"synced"`           // At least one source has synced
+	BriefSent       bool `json:"brief_sent"`       // A daily brief has been delivered
+	PrioritiesAdded bool `json:"priorities_added"` // OKRs, focus areas, stakeholders or projects set
+}
+
+// Done reports whether every setup step is complete
+func (p *SetupProgress) Done() bool {
+	return p.Authenticated && p.Synced && p.BriefSent && p.PrioritiesAdded
+}
+
+// GetSetupProgress checks the setup steps the database knows about. Authentication and
+// priorities kept in config are up to the caller.
+func (db *DB) GetSetupProgress() (*SetupProgress, error) {
+	var synced, briefs, priorities int
+	err := db.QueryRow(`
+		SELECT
+			(SELECT CAST(COUNT(*) AS INTEGER) FROM sync_state WHERE last_sync > 0),
+			(SELECT CAST(COUNT(*) AS INTEGER) FROM usage
+			 WHERE service = 'planner' AND action = 'daily_brief' AND (error IS NULL OR error = '')),
+			(SELECT CAST(COUNT(*) AS INTEGER) FROM priorities WHERE active)
+	`).Scan(&synced, &briefs, &priorities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check setup progress: %w", err)
+	}
+
+	return &SetupProgress{
+		Synced:          synced > 0,
+		BriefSent:       briefs > 0,
+		PrioritiesAdded: priorities > 0,
+	}, nil
+}
@@ -0,0 +1,214 @@
+package db
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Outbox statuses
+const (
+	OutboxPending = "pending"
+	OutboxSent    = "sent"
+	OutboxFailed  = "failed" // Gave up after max attempts
+)
+
+// DeliveryHistoryDays is how far back the delivery history goes
+const DeliveryHistoryDays = 7
+
+// OutboxItem is a notification persisted before delivery so it survives channel outages
+type OutboxItem struct {
+	ID            string     `json:"id"`
+	Channel       string     `json:"channel"` // e.g. "chat"
+	Kind          string     `json:"kind"`    // e.g. "daily_brief", "replan_brief", "follow_up"
+	Payload       string     `json:"payload"` // Channel-specific JSON message
+	ThreadKey     string     `json:"thread_key"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	RemoteID      string     `json:"remote_id"` // Message name/ID returned by the channel
+	CreatedAt     time.Time  `json:"created_at"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+// EnqueueNotification persists a notification for delivery and returns its ID
+func (db *DB) EnqueueNotification(channel, kind, payload, threadKey string) (*OutboxItem, error) {
+	now := time.Now()
+	hash := sha1.Sum([]byte(fmt.Sprintf("%s|%s|%s|%d", channel, kind, payload, now.UnixNano())))
+
+	item := &OutboxItem{
+		ID:            fmt.Sprintf("ntf_%s", hex.EncodeToString(hash[:])[:12]),
+		Channel:       channel,
+		Kind:          kind,
+		Payload:       payload,
+		ThreadKey:     threadKey,
+		Status:        OutboxPending,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}
+
+	query := `
+		INSERT INTO notifications_outbox (id, channel, kind, payload, thread_key, status, attempts, created_at, next_attempt_at)
+		VALUES (?, ?, ?, ?, ?, ?, 0, ?, ?)
+	`
+	if _, err := db.Exec(query, item.ID, channel, kind, payload, threadKey, item.Status, now.Unix(), now.Unix()); err != nil {
+		return nil, fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+
+	return item, nil
+}
+
+// GetDueNotifications returns pending notifications for a channel whose next attempt is due, oldest first
+func (db *DB) GetDueNotifications(channel string, limit int) ([]*OutboxItem, error) {
+	query := `
+		SELECT id, channel, kind, payload, thread_key, status, attempts, last_error,
+		       remote_id, created_at, next_attempt_at, sent_at
+		FROM notifications_outbox
+		WHERE channel = ? AND status = 'pending' AND next_attempt_at <= ?
+		ORDER BY created_at ASC
+		LIMIT ?
+	`
+
+	rows, err := db.Query(query, channel, time.Now().Unix(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOutboxItems(rows)
+}
+
+// MarkNotificationSent records a successful delivery
+func (db *DB) MarkNotificationSent(id, remoteID string) error {
+	query := `
+		UPDATE notifications_outbox
+		SET status = 'sent', attempts = attempts + 1, remote_id = ?, sent_at = ?, last_error = NULL
+		WHERE id = ?
+	`
+	_, err := db.Exec(query, remoteID, time.Now().Unix(), id)
+	return err
+}
+
+// DeferNotification holds a pending notification back until a time, without counting an attempt
+func (db *DB) DeferNotification(id string, until time.Time) error {
+	query := `UPDATE notifications_outbox SET next_attempt_at = ? WHERE id = ? AND status = 'pending'`
+	if _, err := db.Exec(query, until.Unix(), id); err != nil {
+		return fmt.Errorf("failed to defer notification: %w", err)
+	}
+	return nil
+}
+
+// MarkNotificationFailed records a failed attempt and schedules the next one.
+// Once maxAttempts is reached the notification is marked failed and no longer retried.
+func (db *DB) MarkNotificationFailed(id string, deliveryErr error, nextAttempt time.Time, maxAttempts int) error {
+	query := `
+		UPDATE notifications_outbox
+		SET attempts = attempts + 1,
+		    last_error = ?,
+		    next_attempt_at = ?,
+		    status = CASE WHEN attempts + 1 >= ? THEN 'failed' ELSE 'pending' END
+		WHERE id = ?
+	`
+	_, err := db.Exec(query, deliveryErr.Error(), nextAttempt.Unix(), maxAttempts, id)
+	return err
+}
+
+// RecordDelivery adds a notification sent outside the outbox, such as an emailed brief, to the
+// delivery history. It isn't retried.
+func (db *DB) RecordDelivery(channel, kind string, deliveryErr error) error {
+	now := time.Now()
+	hash := sha1.Sum([]byte(fmt.Sprintf("%s|%s|%d", channel, kind, now.UnixNano())))
+	id := fmt.Sprintf("ntf_%s", hex.EncodeToString(hash[:])[:12])
+
+	status, lastError, sentAt := OutboxSent, sql.NullString{}, sql.NullInt64{Int64: now.Unix(), Valid: true}
+	if deliveryErr != nil {
+		status, lastError, sentAt = OutboxFailed, sql.NullString{String: deliveryErr.Error(), Valid: true}, sql.NullInt64{}
+	}
+
+	query := `
+		INSERT INTO notifications_outbox (id, channel, kind, payload, status, attempts, last_error, created_at, next_attempt_at, sent_at)
+		VALUES (?, ?, ?, '', ?, 1, ?, ?, ?, ?)
+	`
+	if _, err := db.Exec(query, id, channel, kind, status, lastError, now.Unix(), now.Unix(), sentAt); err != nil {
+		return fmt.Errorf("failed to record %s delivery: %w", kind, err)
+	}
+	return nil
+}
+
+// GetDeliveryHistory returns the notifications created since a time on every channel, newest
+// first, without their payloads
+func (db *DB) GetDeliveryHistory(since time.Time, limit int) ([]*OutboxItem, error) {
+	query := `
+		SELECT id, channel, kind, '', thread_key, status, attempts, last_error,
+		       remote_id, created_at, next_attempt_at, sent_at
+		FROM notifications_outbox
+		WHERE created_at >= ?
+		ORDER BY created_at DESC, id
+		LIMIT ?
+	`
+
+	rows, err := db.Query(query, since.Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query delivery history: %w", err)
+	}
+	defer rows.Close()
+
+	return scanOutboxItems(rows)
+}
+
+// GetThreadRootRemoteID returns the remote ID of the first delivered message in a thread,
+// for channels that thread replies by parent message ID rather than by key
+func (db *DB) GetThreadRootRemoteID(channel, threadKey string) (string, error) {
+	query := `
+		SELECT remote_id FROM notifications_outbox
+		WHERE channel = ? AND thread_key = ? AND status = 'sent' AND remote_id IS NOT NULL
+		ORDER BY sent_at ASC
+		LIMIT 1
+	`
+
+	var remoteID string
+	err := db.QueryRow(query, channel, threadKey).Scan(&remoteID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return remoteID, err
+}
+
+// scanOutboxItems reads notification rows selected with the standard outbox column list
+func scanOutboxItems(rows *sql.Rows) ([]*OutboxItem, error) {
+	var items []*OutboxItem
+	for rows.Next() {
+		item := &OutboxItem{}
+		var threadKey, lastError, remoteID sql.NullString
+		var createdTS, nextTS, sentTS sql.NullInt64
+
+		err := rows.Scan(
+			&item.ID, &item.Channel, &item.Kind, &item.Payload, &threadKey, &item.Status,
+			&item.Attempts, &lastError, &remoteID, &createdTS, &nextTS, &sentTS,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		item.ThreadKey = threadKey.String
+		item.LastError = lastError.String
+		item.RemoteID = remoteID.String
+		if createdTS.Valid {
+			item.CreatedAt = time.Unix(createdTS.Int64, 0)
+		}
+		if nextTS.Valid {
+			item.NextAttemptAt = time.Unix(nextTS.Int64, 0)
+		}
+		if sentTS.Valid {
+			t := time.Unix(sentTS.Int64, 0)
+			item.SentAt = &t
+		}
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
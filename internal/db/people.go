@@ -0,0 +1,343 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// maxResponseGap is the longest wait between a message and the reply to it that still counts
+// as a response time; anything longer is a new conversation rather than a slow reply
+const maxResponseGap = 14 * 24 * time.Hour
+
+// automatedAddresses are address fragments of mail sent by systems rather than people
+var automatedAddresses = []string{
+	"noreply", "no-reply", "donotreply", "do-not-reply", "notifications@", "notification@",
+	"newsletter", "mailer-daemon", "bounce",
+}
+
+// IsAutomatedAddress reports whether an address looks like a system sender rather than a person
+func IsAutomatedAddress(address string) bool {
+	address = strings.ToLower(address)
+	for _, fragment := range automatedAddresses {
+		if strings.Contains(address, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// Person is a contact and how the relationship stands, built from the mail exchanged with them
+type Person struct {
+	Email             string    `json:"email"`
+	Name              string    `json:"name,omitempty"`
+	Threads           int       `json:"threads"`
+	Messages          int       `json:"messages"`
+	TasksOwed         int       `json:"tasks_owed"` // Open tasks from threads they wrote in: what I owe them
+	WaitingOn         int       `json:"waiting_on"` // Requests I sent them still without a reply: what they owe me
+	LastInteraction   time.Time `json:"last_interaction"`
+	MyResponseSecs    int64     `json:"my_response_secs,omitempty"`    // How long I take to reply to them, on average
+	TheirResponseSecs int64     `json:"their_response_secs,omitempty"` // How long they take to reply to me
+	DraftPersona      string    `json:"draft_persona,omitempty"`
+}
+
+// personStats accumulates a person's stats while messages are scanned
+type personStats struct {
+	*Person
+	threads       map[string]bool
+	myGaps        []int64
+	theirGaps     []int64
+	senderThreads map[string]bool // Threads they wrote in, which their tasks come from
+}
+
+// RefreshPeople rebuilds the relationship stats of everyone the user exchanged mail with since
+// the given time, from the From and To of each message. Automated senders are left out, and
+// contacts with no mail in that window have their stats cleared. It returns how many people
+// were updated.
+func (db *DB) RefreshPeople(userEmail string, since time.Time) (int, error) {
+	userEmail = strings.ToLower(strings.TrimSpace(userEmail))
+	if userEmail == "" {
+		return 0, fmt.Errorf("user email is required to tell sent from received mail")
+	}
+	started := time.Now().Unix()
+
+	rows, err := db.Query(`
+		SELECT thread_id, COALESCE(from_addr, ''), COALESCE(to_addr, ''), ts
+		FROM messages
+		WHERE ts >= ?
+		ORDER BY thread_id, ts
+	`, since.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to query messages: %w", err)
+	}
+	defer rows.Close()
+
+	people := make(map[string]*personStats)
+	person := func(address *mail.Address) *personStats {
+		email := strings.ToLower(address.Address)
+		stats, ok := people[email]
+		if !ok {
+			stats = &personStats{
+				Person:        &Person{Email: email},
+				threads:       make(map[string]bool),
+				senderThreads: make(map[string]bool),
+			}
+			people[email] = stats
+		}
+		if stats.Name == "" {
+			stats.Name = address.Name
+		}
+		return stats
+	}
+
+	// The previous message in the thread, to measure how long each reply took
+	var prevThread, prevSender string
+	var prevRecipients map[string]bool
+	var prevTS int64
+
+	for rows.Next() {
+		var threadID, from, to string
+		var ts int64
+		if err := rows.Scan(&threadID, &from, &to, &ts); err != nil {
+			return 0, err
+		}
+		if threadID != prevThread {
+			prevSender, prevRecipients = "", nil
+		}
+		gap := ts - prevTS
+		replied := gap >= 0 && time.Duration(gap)*time.Second <= maxResponseGap
+
+		sender := parseAddresses(from)
+		if len(sender) == 0 {
+			continue
+		}
+		senderEmail := strings.ToLower(sender[0].Address)
+		seen := time.Unix(ts, 0)
+
+		if senderEmail == userEmail {
+			// Sent: each recipient is a counterpart, and a reply to one of them is my response
+			recipients := make(map[string]bool)
+			for _, address := range parseAddresses(to) {
+				email := strings.ToLower(address.Address)
+				if email == userEmail || IsAutomatedAddress(email) {
+					continue
+				}
+				recipients[email] = true
+				stats := person(address)
+				stats.Messages++
+				stats.threads[threadID] = true
+				stats.LastInteraction = later(stats.LastInteraction, seen)
+				if prevSender == email && replied {
+					stats.myGaps = append(stats.myGaps, gap)
+				}
+			}
+			prevSender, prevRecipients = userEmail, recipients
+		} else {
+			// Received: the sender is the counterpart, and if I wrote to them last this is their response
+			if !IsAutomatedAddress(senderEmail) {
+				stats := person(sender[0])
+				stats.Messages++
+				stats.threads[threadID] = true
+				stats.senderThreads[threadID] = true
+				stats.LastInteraction = later(stats.LastInteraction, seen)
+				if prevSender == userEmail && prevRecipients[senderEmail] && replied {
+					stats.theirGaps = append(stats.theirGaps, gap)
+				}
+			}
+			prevSender, prevRecipients = senderEmail, nil
+		}
+		prevThread, prevTS = threadID, ts
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	rows.Close()
+
+	// Open tasks from a thread are owed to the people who wrote in it
+	taskThreads := make(map[string]int)
+	taskRows, err := db.Query(`
+		SELECT source_id FROM tasks
+		WHERE source = 'gmail' AND status IN ('pending', 'in_progress')
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query open tasks: %w", err)
+	}
+	for taskRows.Next() {
+		var threadID sql.NullString
+		if err := taskRows.Scan(&threadID); err != nil {
+			taskRows.Close()
+			return 0, err
+		}
+		taskThreads[threadID.String]++
+	}
+	taskRows.Close()
+
+	waiting, err := db.GetWaitingItems()
+	if err != nil {
+		return 0, err
+	}
+	waitingOn := make(map[string]int)
+	for _, item := range waiting {
+		if addresses := parseAddresses(item.Recipient); len(addresses) > 0 {
+			waitingOn[strings.ToLower(addresses[0].Address)]++
+		}
+	}
+
+	query := `
+		INSERT INTO contacts (email, name, thread_count, message_count, tasks_owed, waiting_on,
+		                      last_interaction_ts, my_response_secs, their_response_secs, stats_updated_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (email) DO UPDATE SET
+			name = excluded.name, thread_count = excluded.thread_count, message_count = excluded.message_count,
+			tasks_owed = excluded.tasks_owed, waiting_on = excluded.waiting_on,
+			last_interaction_ts = excluded.last_interaction_ts, my_response_secs = excluded.my_response_secs,
+			their_response_secs = excluded.their_response_secs, stats_updated_at = excluded.stats_updated_at
+	`
+	for email, stats := range people {
+		stats.Threads = len(stats.threads)
+		for threadID := range stats.senderThreads {
+			stats.TasksOwed += taskThreads[threadID]
+		}
+		_, err := db.Exec(query, email, stats.Name, stats.Threads, stats.Messages, stats.TasksOwed, waitingOn[email],
+			stats.LastInteraction.Unix(), averageSecs(stats.myGaps), averageSecs(stats.theirGaps), started, started)
+		if err != nil {
+			return 0, fmt.Errorf("failed to save stats for %s: %w", email, err)
+		}
+	}
+
+	// People with no mail in the window drop out of the list; their drafting persona is kept
+	staleRows, err := db.Query(`SELECT email FROM contacts WHERE thread_count > 0`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query contacts: %w", err)
+	}
+	var stale []string
+	for staleRows.Next() {
+		var email string
+		if err := staleRows.Scan(&email); err != nil {
+			staleRows.Close()
+			return 0, err
+		}
+		if people[email] == nil {
+			stale = append(stale, email)
+		}
+	}
+	staleRows.Close()
+
+	for _, email := range stale {
+		_, err := db.Exec(`
+			UPDATE contacts
+			SET thread_count = 0, message_count = 0, tasks_owed = 0, waiting_on = 0,
+			    my_response_secs = NULL, their_response_secs = NULL, stats_updated_at = ?
+			WHERE email = ?
+		`, started, email)
+		if err != nil {
+			return 0, fmt.Errorf("failed to clear stats for %s: %w", email, err)
+		}
+	}
+
+	return len(people), nil
+}
+
+const personColumns = `email, COALESCE(name, ''), COALESCE(thread_count, 0), COALESCE(message_count, 0),
+	COALESCE(tasks_owed, 0), COALESCE(waiting_on, 0), COALESCE(last_interaction_ts, 0),
+	my_response_secs, their_response_secs, COALESCE(draft_persona, '')`
+
+// GetPeople returns the people the user has mail with, most recent interaction first. A non-empty
+// query keeps those whose name or address contains it.
+func (db *DB) GetPeople(query string, limit int) ([]*Person, error) {
+	where := `WHERE thread_count > 0`
+	var args []interface{}
+	if query = strings.ToLower(strings.TrimSpace(query)); query != "" {
+		where += ` AND (email LIKE ? OR lower(name) LIKE ?)`
+		args = append(args, "%"+query+"%", "%"+query+"%")
+	}
+	args = append(args, limit)
+
+	rows, err := db.Query(`
+		SELECT `+personColumns+`
+		FROM contacts
+		`+where+`
+		ORDER BY last_interaction_ts DESC, email
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query people: %w", err)
+	}
+	defer rows.Close()
+
+	var people []*Person
+	for rows.Next() {
+		person, err := scanPerson(rows)
+		if err != nil {
+			return nil, err
+		}
+		people = append(people, person)
+	}
+	return people, rows.Err()
+}
+
+// GetPerson returns one person's stats, or nil if there's no mail with them
+func (db *DB) GetPerson(email string) (*Person, error) {
+	row := db.QueryRow(`SELECT `+personColumns+` FROM contacts WHERE email = ? AND thread_count > 0`,
+		strings.ToLower(strings.TrimSpace(email)))
+	person, err := scanPerson(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get person: %w", err)
+	}
+	return person, nil
+}
+
+func scanPerson(row interface{ Scan(...interface{}) error }) (*Person, error) {
+	person := &Person{}
+	var lastTS int64
+	var mine, theirs sql.NullInt64
+	err := row.Scan(&person.Email, &person.Name, &person.Threads, &person.Messages, &person.TasksOwed,
+		&person.WaitingOn, &lastTS, &mine, &theirs, &person.DraftPersona)
+	if err != nil {
+		return nil, err
+	}
+	person.LastInteraction = time.Unix(lastTS, 0)
+	person.MyResponseSecs = mine.Int64
+	person.TheirResponseSecs = theirs.Int64
+	return person, nil
+}
+
+// parseAddresses reads an address header, falling back to comma-separated bare addresses when
+// it isn't well-formed
+func parseAddresses(header string) []*mail.Address {
+	if addresses, err := mail.ParseAddressList(header); err == nil {
+		return addresses
+	}
+	var addresses []*mail.Address
+	for _, part := range strings.Split(header, ",") {
+		if address := senderAddress(part); strings.Contains(address, "@") {
+			addresses = append(addresses, &mail.Address{Address: address})
+		}
+	}
+	return addresses
+}
+
+// averageSecs returns the mean of the gaps, or nil when there are none
+func averageSecs(gaps []int64) interface{} {
+	if len(gaps) == 0 {
+		return nil
+	}
+	var total int64
+	for _, gap := range gaps {
+		total += gap
+	}
+	return total / int64(len(gaps))
+}
+
+// later returns whichever time is later
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
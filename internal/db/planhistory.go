@@ -0,0 +1,149 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PlanKindEndOfDay is the plan history kind of the end-of-day brief
+const PlanKindEndOfDay = "end_of_day"
+
+// EndOfDayBrief is the shutdown review sent at the end of the working day
+type EndOfDayBrief struct {
+	Date            time.Time     `json:"date"`
+	Completed       []*Task       `json:"completed"`        // Completed today
+	Slipping        []*Task       `json:"slipping"`         // Open tasks due today or overdue, moving to tomorrow
+	DueTomorrowAM   []*Task       `json:"due_tomorrow_am"`  // Open tasks due tomorrow morning
+	TomorrowMorning []*Event      `json:"tomorrow_morning"` // Meetings tomorrow morning
+	Plan            []*PlanActual `json:"plan,omitempty"`   // Today's committed focus blocks against what happened
+	PlanSeed        string        `json:"plan_seed"`        // One line to start tomorrow's plan from
+}
+
+// PlanActual compares a focus block committed in the morning plan with what was done in it
+type PlanActual struct {
+	Name         string    `json:"name"`
+	StartTS      time.Time `json:"start_ts"`
+	EndTS        time.Time `json:"end_ts"`
+	TasksPlanned int       `json:"tasks_planned"`
+	TasksDone    int       `json:"tasks_done"`    // Planned tasks completed today
+	FocusMinutes int       `json:"focus_minutes"` // Focus session time inside the block
+}
+
+// Describe summarises the block's plan against its actuals in one line
+func (a *PlanActual) Describe() string {
+	planned := int(a.EndTS.Sub(a.StartTS).Minutes())
+	return fmt.Sprintf("%s %s-%s: %d/%d tasks done, %d of %d min in focus", a.Name,
+		a.StartTS.Format("15:04"), a.EndTS.Format("15:04"), a.TasksDone, a.TasksPlanned, a.FocusMinutes, planned)
+}
+
+// PlanHistoryEntry is a plan or review kept in plan history
+type PlanHistoryEntry struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	Date      string          `json:"date"` // YYYY-MM-DD
+	Summary   string          `json:"summary"`
+	Content   json.RawMessage `json:"content"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// GetTasksCompletedBetween returns tasks completed in a time range, most recent first
+func (db *DB) GetTasksCompletedBetween(start, end time.Time) ([]*Task, error) {
+	query := `
+		SELECT id, source, source_id, title, description, due_ts, project,
+		       impact, urgency, effort, stakeholder, score, status, metadata,
+		       matched_priorities, created_at, updated_at, completed_at,
+		       COALESCE(recurrence, ''), COALESCE(recurs_from, '')
+		FROM tasks
+		WHERE status = 'completed' AND completed_at >= ? AND completed_at < ?
+		ORDER BY completed_at DESC
+	`
+
+	rows, err := db.Query(query, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query completed tasks: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTasks(rows)
+}
+
+// GetOpenTasksDueBetween returns pending and in-progress tasks due in a time range, highest
+// score first. A zero start includes everything overdue.
+func (db *DB) GetOpenTasksDueBetween(start, end time.Time) ([]*Task, error) {
+	var startTS int64
+	if !start.IsZero() {
+		startTS = start.Unix()
+	}
+
+	query := `
+		SELECT id, source, source_id, title, description, due_ts, project,
+		       impact, urgency, effort, stakeholder, score, status, metadata,
+		       matched_priorities, created_at, updated_at, completed_at,
+		       COALESCE(recurrence, ''), COALESCE(recurs_from, '')
+		FROM tasks
+		WHERE status IN ('pending', 'in_progress')
+		  AND due_ts IS NOT NULL AND due_ts >= ? AND due_ts < ?
+		ORDER BY score DESC, due_ts ASC
+	`
+
+	rows, err := db.Query(query, startTS, end.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks due: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTasks(rows)
+}
+
+// SavePlanHistory stores a plan or review, replacing any earlier one of the same kind that day
+func (db *DB) SavePlanHistory(kind string, date time.Time, summary string, content any) error {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+
+	day := date.Format("2006-01-02")
+	query := `
+		INSERT OR REPLACE INTO plan_history (id, kind, plan_date, summary, content, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	if _, err := db.Exec(query, kind+"_"+day, kind, day, summary, string(data), time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to save plan history: %w", err)
+	}
+	return nil
+}
+
+// GetPlanHistory returns the most recent plans of a kind ("" = all kinds), newest first
+func (db *DB) GetPlanHistory(kind string, limit int) ([]*PlanHistoryEntry, error) {
+	query := `
+		SELECT id, kind, plan_date, COALESCE(summary, ''), COALESCE(content, ''), created_at
+		FROM plan_history
+		WHERE ? = '' OR kind = ?
+		ORDER BY plan_date DESC, created_at DESC
+		LIMIT ?
+	`
+
+	rows, err := db.Query(query, kind, kind, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query plan history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*PlanHistoryEntry
+	for rows.Next() {
+		entry := &PlanHistoryEntry{}
+		var content string
+		var createdAt int64
+		if err := rows.Scan(&entry.ID, &entry.Kind, &entry.Date, &entry.Summary, &content, &createdAt); err != nil {
+			return nil, err
+		}
+		if content != "" {
+			entry.Content = json.RawMessage(content)
+		}
+		entry.CreatedAt = time.Unix(createdAt, 0)
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
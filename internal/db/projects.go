@@ -0,0 +1,229 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ProjectSummary rolls up a project's open tasks
+type ProjectSummary struct {
+	Name      string     `json:"name"`
+	TaskCount int        `json:"task_count"`
+	MaxScore  float64    `json:"max_score"`
+	AvgScore  float64    `json:"avg_score"`
+	NextDue   *time.Time `json:"next_due,omitempty"` // Nearest due date (nil = none due)
+	Client    string     `json:"client,omitempty"`   // From the projects table
+	Billable  bool       `json:"billable"`
+}
+
+// ProjectBilling is how a project's time is invoiced, from the projects table
+type ProjectBilling struct {
+	Name     string `json:"name"`
+	Client   string `json:"client"`
+	Billable bool   `json:"billable"`
+}
+
+// GetProjectSummaries groups pending and in-progress tasks by project, highest scoring project
+// first. Projects are matched case-insensitively, and tasks without a project are left out.
+func (db *DB) GetProjectSummaries() ([]*ProjectSummary, error) {
+	query := `
+		SELECT MIN(t.project), COUNT(*), MAX(t.score), AVG(t.score), MIN(t.due_ts),
+		       COALESCE(MAX(p.client), ''), COALESCE(BOOL_OR(p.billable), false)
+		FROM tasks t
+		LEFT JOIN projects p ON p.key = LOWER(TRIM(t.project))
+		WHERE t.project IS NOT NULL AND TRIM(t.project) != ''
+		  AND t.status IN ('pending', 'in_progress')
+		GROUP BY LOWER(t.project)
+		ORDER BY MAX(t.score) DESC, COUNT(*) DESC
+	`
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query projects: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []*ProjectSummary
+	for rows.Next() {
+		summary := &ProjectSummary{}
+		var nextDue sql.NullInt64
+		if err := rows.Scan(&summary.Name, &summary.TaskCount, &summary.MaxScore, &summary.AvgScore, &nextDue,
+			&summary.Client, &summary.Billable); err != nil {
+			return nil, err
+		}
+		if nextDue.Valid {
+			t := time.Unix(nextDue.Int64, 0)
+			summary.NextDue = &t
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, rows.Err()
+}
+
+// GetOpenTasksByProject returns pending and in-progress tasks for a project (case-insensitive)
+func (db *DB) GetOpenTasksByProject(project string) ([]*Task, error) {
+	query := `
+		SELECT id, source, source_id, title, description, due_ts, project,
+		       impact, urgency, effort, stakeholder, score, status, metadata,
+		       matched_priorities, created_at, updated_at, completed_at,
+		       COALESCE(recurrence, ''), COALESCE(recurs_from, '')
+		FROM tasks
+		WHERE LOWER(project) = LOWER(?)
+		  AND status IN ('pending', 'in_progress')
+		ORDER BY score DESC
+	`
+
+	rows, err := db.Query(query, project)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTasks(rows)
+}
+
+// StaleProject is a project with open tasks but no recent activity
+type StaleProject struct {
+	Name         string    `json:"name"`
+	OpenTasks    int       `json:"open_tasks"`
+	LastActivity time.Time `json:"last_activity"`
+}
+
+// GetStaleProjects returns projects with open tasks where nothing has happened since a time:
+// no task was added or completed, and no message arrived on a thread a task came from.
+// Projects quiet the longest come first.
+func (db *DB) GetStaleProjects(since time.Time) ([]*StaleProject, error) {
+	query := `
+		WITH activity AS (
+			SELECT LOWER(TRIM(t.project)) AS key, t.project, t.status,
+			       GREATEST(COALESCE(t.created_at, 0), COALESCE(t.completed_at, 0),
+			                COALESCE((SELECT MAX(m.ts) FROM messages m WHERE m.thread_id = t.source_id), 0)) AS last_ts
+			FROM tasks t
+			WHERE t.project IS NOT NULL AND TRIM(t.project) != ''
+		)
+		SELECT MIN(project), COUNT(*) FILTER (WHERE status IN ('pending', 'in_progress')), MAX(last_ts)
+		FROM activity
+		GROUP BY key
+		HAVING COUNT(*) FILTER (WHERE status IN ('pending', 'in_progress')) > 0
+		   AND MAX(last_ts) < ?
+		ORDER BY MAX(last_ts) ASC
+	`
+
+	rows, err := db.Query(query, since.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stale projects: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []*StaleProject
+	for rows.Next() {
+		project := &StaleProject{}
+		var lastTS int64
+		if err := rows.Scan(&project.Name, &project.OpenTasks, &lastTS); err != nil {
+			return nil, err
+		}
+		project.LastActivity = time.Unix(lastTS, 0)
+		projects = append(projects, project)
+	}
+
+	return projects, rows.Err()
+}
+
+// SetTaskProject moves a task to a different project
+func (db *DB) SetTaskProject(taskID, project string) error {
+	query := `UPDATE tasks SET project = ?, updated_at = ? WHERE id = ?`
+	if _, err := db.Exec(query, project, time.Now().Unix(), taskID); err != nil {
+		return fmt.Errorf("failed to update project for task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// scanTasks reads task rows selected with the standard task column list
+func scanTasks(rows *sql.Rows) ([]*Task, error) {
+	var tasks []*Task
+	for rows.Next() {
+		task := &Task{}
+		var dueTS, createdTS, updatedTS, completedTS sql.NullInt64
+		var matchedPriorities sql.NullString
+
+		err := rows.Scan(
+			&task.ID, &task.Source, &task.SourceID, &task.Title, &task.Description,
+			&dueTS, &task.Project, &task.Impact, &task.Urgency, &task.Effort,
+			&task.Stakeholder, &task.Score, &task.Status, &task.Metadata,
+			&matchedPriorities, &createdTS, &updatedTS, &completedTS,
+			&task.Recurrence, &task.RecursFrom,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if dueTS.Valid {
+			t := time.Unix(dueTS.Int64, 0)
+			task.DueTS = &t
+		}
+		if matchedPriorities.Valid {
+			task.MatchedPriorities = matchedPriorities.String
+		}
+		if createdTS.Valid {
+			task.CreatedAt = time.Unix(createdTS.Int64, 0)
+		}
+		if updatedTS.Valid {
+			task.UpdatedAt = time.Unix(updatedTS.Int64, 0)
+		}
+		if completedTS.Valid {
+			t := time.Unix(completedTS.Int64, 0)
+			task.CompletedAt = &t
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// SetProjectBilling sets the client a project is invoiced to and whether its time is billable
+func (db *DB) SetProjectBilling(name, client string, billable bool) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("project name is required")
+	}
+
+	query := `
+		INSERT INTO projects (key, name, client, billable, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			name = excluded.name,
+			client = excluded.client,
+			billable = excluded.billable,
+			updated_at = excluded.updated_at
+	`
+	_, err := db.Exec(query, strings.ToLower(name), name, strings.TrimSpace(client), billable, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to set billing for project %s: %w", name, err)
+	}
+	return nil
+}
+
+// GetProjectBilling returns the billing settings of every configured project, keyed by
+// lowercased name
+func (db *DB) GetProjectBilling() (map[string]*ProjectBilling, error) {
+	rows, err := db.Query(`SELECT key, name, COALESCE(client, ''), billable FROM projects`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query project billing: %w", err)
+	}
+	defer rows.Close()
+
+	billing := make(map[string]*ProjectBilling)
+	for rows.Next() {
+		var key string
+		project := &ProjectBilling{}
+		if err := rows.Scan(&key, &project.Name, &project.Client, &project.Billable); err != nil {
+			return nil, err
+		}
+		billing[key] = project
+	}
+	return billing, rows.Err()
+}
@@ -0,0 +1,156 @@
+package db
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Query kinds tracked by ObserveQuery
+const (
+	QueryKindSimilarity = "vss_similarity" // Embedding similarity search over task_embeddings
+	QueryKindKNN        = "vss_knn"        // Feedback neighbour scan for KNN scoring
+	QueryKindFTS        = "fts_messages"   // Full-text message search
+)
+
+// defaultSlowQueryThreshold applies until SetSlowQueryThreshold is called
+const defaultSlowQueryThreshold = 250 * time.Millisecond
+
+// QueryTiming aggregates timings for one kind of query since startup
+type QueryTiming struct {
+	Kind      string
+	Count     int64
+	Errors    int64
+	Slow      int64
+	Total     time.Duration
+	Max       time.Duration
+	LastError string
+}
+
+// Average returns the mean duration of the observed queries
+func (t QueryTiming) Average() time.Duration {
+	if t.Count == 0 {
+		return 0
+	}
+	return t.Total / time.Duration(t.Count)
+}
+
+// SlowQuerySummary summarizes slow queries recorded in the usage log
+type SlowQuerySummary struct {
+	Kind    string
+	Count   int
+	AvgMs   float64
+	MaxMs   int64
+	LastRun time.Time
+}
+
+type queryStats struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	timings   map[string]*QueryTiming
+}
+
+func newQueryStats() *queryStats {
+	return &queryStats{
+		threshold: defaultSlowQueryThreshold,
+		timings:   make(map[string]*QueryTiming),
+	}
+}
+
+// SetSlowQueryThreshold sets the duration above which tracked queries are logged as slow
+func (db *DB) SetSlowQueryThreshold(threshold time.Duration) {
+	if db.stats == nil || threshold <= 0 {
+		return
+	}
+	db.stats.mu.Lock()
+	db.stats.threshold = threshold
+	db.stats.mu.Unlock()
+}
+
+// ObserveQuery records the duration of a tracked query started at start.
+// Queries slower than the threshold are logged and written to the usage table
+// so they can be reviewed later with -doctor.
+func (db *DB) ObserveQuery(kind string, start time.Time, err error) {
+	if db.stats == nil {
+		return
+	}
+	elapsed := time.Since(start)
+
+	db.stats.mu.Lock()
+	timing, ok := db.stats.timings[kind]
+	if !ok {
+		timing = &QueryTiming{Kind: kind}
+		db.stats.timings[kind] = timing
+	}
+	timing.Count++
+	timing.Total += elapsed
+	if elapsed > timing.Max {
+		timing.Max = elapsed
+	}
+	if err != nil {
+		timing.Errors++
+		timing.LastError = err.Error()
+	}
+	slow := elapsed >= db.stats.threshold
+	if slow {
+		timing.Slow++
+	}
+	db.stats.mu.Unlock()
+
+	if slow {
+		log.Printf("Slow query [%s]: %v", kind, elapsed.Round(time.Millisecond))
+		if logErr := db.LogUsage("db", "slow_query:"+kind, 0, 0, elapsed, err); logErr != nil {
+			log.Printf("Warning: failed to record slow query: %v", logErr)
+		}
+	}
+}
+
+// QueryTimings returns a snapshot of tracked query timings, sorted by kind
+func (db *DB) QueryTimings() []QueryTiming {
+	if db.stats == nil {
+		return nil
+	}
+
+	db.stats.mu.Lock()
+	defer db.stats.mu.Unlock()
+
+	timings := make([]QueryTiming, 0, len(db.stats.timings))
+	for _, timing := range db.stats.timings {
+		timings = append(timings, *timing)
+	}
+	sort.Slice(timings, func(i, j int) bool { return timings[i].Kind < timings[j].Kind })
+	return timings
+}
+
+// GetSlowQueries summarizes slow queries recorded since the given time
+func (db *DB) GetSlowQueries(since time.Time) ([]SlowQuerySummary, error) {
+	query := `
+		SELECT replace(action, 'slow_query:', '') AS kind,
+		       COUNT(*), AVG(duration_ms), MAX(duration_ms), MAX(ts)
+		FROM usage
+		WHERE service = 'db' AND action LIKE 'slow_query:%' AND ts >= ?
+		GROUP BY action
+		ORDER BY COUNT(*) DESC
+	`
+
+	rows, err := db.Query(query, since.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query slow queries: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []SlowQuerySummary
+	for rows.Next() {
+		var summary SlowQuerySummary
+		var lastTS int64
+		if err := rows.Scan(&summary.Kind, &summary.Count, &summary.AvgMs, &summary.MaxMs, &lastTS); err != nil {
+			return nil, err
+		}
+		summary.LastRun = time.Unix(lastTS, 0)
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, rows.Err()
+}
@@ -0,0 +1,237 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+// rruleDays maps RRULE BYDAY codes to weekdays
+var rruleDays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Recurrence is a parsed task recurrence rule
+type Recurrence struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday // Weekly only; empty repeats on the due date's weekday
+	ByMonthDay int            // Monthly only; 0 repeats on the due date's day of the month
+}
+
+// ParseRecurrence parses a recurrence rule. It accepts the shorthands daily, weekdays, weekly
+// and monthly, or an RRULE subset: FREQ=DAILY|WEEKLY|MONTHLY with optional INTERVAL, BYDAY for
+// weekly rules and BYMONTHDAY for monthly ones (e.g. "FREQ=WEEKLY;INTERVAL=2",
+// "RRULE:FREQ=WEEKLY;BYDAY=MO,TH" or "FREQ=MONTHLY;BYMONTHDAY=31").
+func ParseRecurrence(rule string) (*Recurrence, error) {
+	rule = strings.ToUpper(strings.TrimSpace(rule))
+	switch rule {
+	case "":
+		return nil, fmt.Errorf("recurrence rule is empty")
+	case "DAILY", "WEEKLY", "MONTHLY":
+		return &Recurrence{Freq: rule, Interval: 1}, nil
+	case "WEEKDAYS":
+		return &Recurrence{Freq: FreqWeekly, Interval: 1, ByDay: []time.Weekday{
+			time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday,
+		}}, nil
+	}
+
+	r := &Recurrence{Interval: 1}
+	for _, part := range strings.Split(strings.TrimPrefix(rule, "RRULE:"), ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+		switch key {
+		case "FREQ":
+			switch value {
+			case FreqDaily, FreqWeekly, FreqMonthly:
+				r.Freq = value
+			default:
+				return nil, fmt.Errorf("unsupported recurrence frequency %q (expected DAILY, WEEKLY or MONTHLY)", value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid recurrence interval %q", value)
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, ok := rruleDays[code]
+				if !ok {
+					return nil, fmt.Errorf("invalid recurrence day %q", code)
+				}
+				r.ByDay = append(r.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 31 {
+				return nil, fmt.Errorf("invalid recurrence month day %q", value)
+			}
+			r.ByMonthDay = n
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part %q", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("recurrence rule is missing FREQ")
+	}
+	if len(r.ByDay) > 0 && (r.Freq != FreqWeekly || r.Interval != 1) {
+		return nil, fmt.Errorf("BYDAY is only supported for weekly rules with an interval of 1")
+	}
+	if r.ByMonthDay > 0 && r.Freq != FreqMonthly {
+		return nil, fmt.Errorf("BYMONTHDAY is only supported for monthly rules")
+	}
+	return r, nil
+}
+
+// String returns the rule in the form it is stored in
+func (r *Recurrence) String() string {
+	rule := "FREQ=" + r.Freq
+	if r.Interval > 1 {
+		rule += fmt.Sprintf(";INTERVAL=%d", r.Interval)
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			for code, d := range rruleDays {
+				if d == day {
+					codes = append(codes, code)
+				}
+			}
+		}
+		rule += ";BYDAY=" + strings.Join(codes, ",")
+	}
+	if r.ByMonthDay > 0 {
+		rule += fmt.Sprintf(";BYMONTHDAY=%d", r.ByMonthDay)
+	}
+	return rule
+}
+
+// Describe returns a short human-readable form of the rule, for briefs
+func (r *Recurrence) Describe() string {
+	if len(r.ByDay) == 5 && r.String() == "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR" {
+		return "weekdays"
+	}
+	unit := map[string]string{FreqDaily: "day", FreqWeekly: "week", FreqMonthly: "month"}[r.Freq]
+	if len(r.ByDay) > 0 {
+		names := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			names = append(names, day.String()[:3])
+		}
+		return "every " + strings.Join(names, ", ")
+	}
+	if r.Interval == 1 {
+		return "every " + unit
+	}
+	return fmt.Sprintf("every %d %ss", r.Interval, unit)
+}
+
+// Next returns the first occurrence after t, keeping t's time of day
+func (r *Recurrence) Next(t time.Time) time.Time {
+	switch r.Freq {
+	case FreqDaily:
+		return t.AddDate(0, 0, r.Interval)
+	case FreqWeekly:
+		if len(r.ByDay) == 0 {
+			return t.AddDate(0, 0, 7*r.Interval)
+		}
+		for i := 1; i <= 7; i++ {
+			next := t.AddDate(0, 0, i)
+			for _, day := range r.ByDay {
+				if next.Weekday() == day {
+					return next
+				}
+			}
+		}
+		return t.AddDate(0, 0, 7)
+	default:
+		// Monthly: stay on the same day, clamped to the end of shorter months
+		year, month, day := t.Date()
+		if r.ByMonthDay > 0 {
+			day = r.ByMonthDay
+		}
+		first := time.Date(year, month+time.Month(r.Interval), 1, t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+		lastDay := first.AddDate(0, 1, -1).Day()
+		return first.AddDate(0, 0, min(day, lastDay)-1)
+	}
+}
+
+// DescribeRecurrence returns a human-readable form of a stored rule, or the rule itself if it
+// can't be parsed
+func DescribeRecurrence(rule string) string {
+	recurrence, err := ParseRecurrence(rule)
+	if err != nil {
+		return rule
+	}
+	return recurrence.Describe()
+}
+
+// SetTaskRecurrence sets a task's recurrence rule. An empty rule makes the task one-off.
+func (db *DB) SetTaskRecurrence(taskID, rule string) error {
+	query := `UPDATE tasks SET recurrence = NULLIF(?, ''), updated_at = ? WHERE id = ?`
+	result, err := db.Exec(query, rule, time.Now().Unix(), taskID)
+	if err != nil {
+		return fmt.Errorf("failed to set task recurrence: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("task not found: %s", taskID)
+	}
+	return nil
+}
+
+// HasSpawnedOccurrence reports whether the next occurrence of a recurring task was already created
+func (db *DB) HasSpawnedOccurrence(taskID string) (bool, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM tasks WHERE recurs_from = ?`
+	if err := db.QueryRow(query, taskID).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DeleteSpawnedOccurrences removes pending occurrences spawned by completing a task, used when
+// the completion is undone. Occurrences that were already worked on are kept.
+func (db *DB) DeleteSpawnedOccurrences(taskID string) (int, error) {
+	result, err := db.Exec(`DELETE FROM tasks WHERE recurs_from = ? AND status = 'pending'`, taskID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete spawned occurrences: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+// GetUpcomingRecurringTasks returns pending recurring tasks due before the given time, soonest first
+func (db *DB) GetUpcomingRecurringTasks(until time.Time, limit int) ([]*Task, error) {
+	query := `
+		SELECT id, source, source_id, title, description, due_ts, project,
+		       impact, urgency, effort, stakeholder, score, status, metadata,
+		       matched_priorities, created_at, updated_at, completed_at,
+		       COALESCE(recurrence, ''), COALESCE(recurs_from, '')
+		FROM tasks
+		WHERE status = 'pending'
+		  AND recurrence IS NOT NULL
+		  AND due_ts IS NOT NULL AND due_ts < ?
+		ORDER BY due_ts
+		LIMIT ?
+	`
+
+	rows, err := db.Query(query, until.Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recurring tasks: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTasks(rows)
+}
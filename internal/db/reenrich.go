@@ -0,0 +1,90 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReenrichCandidate is an open task whose thread has changed since its description was written
+type ReenrichCandidate struct {
+	TaskID        string
+	ThreadID      string
+	MessageCount  int
+	LastMessageTS time.Time
+}
+
+// GetTasksToReenrich returns open email tasks whose thread has gained messages since the task
+// was last enriched, newest activity first. Tasks enriched within the throttle interval wait,
+// and tasks whose description the user has edited by hand are left alone; a description
+// rewritten by re-extraction doesn't stop re-enrichment.
+func (db *DB) GetTasksToReenrich(throttle time.Duration, limit int) ([]*ReenrichCandidate, error) {
+	rows, err := db.Query(`
+		SELECT t.id, t.source_id, m.message_count, m.last_ts
+		FROM tasks t
+		JOIN (
+			SELECT thread_id, CAST(COUNT(*) AS INTEGER) AS message_count, MAX(ts) AS last_ts
+			FROM messages
+			GROUP BY thread_id
+		) m ON m.thread_id = t.source_id
+		LEFT JOIN task_enrichments e ON e.task_id = t.id
+		WHERE t.source = 'gmail'
+		  AND t.status IN ('pending', 'in_progress')
+		  AND (
+		    (e.task_id IS NULL AND m.last_ts > t.created_at)
+		    OR m.last_ts > e.last_message_ts
+		    OR m.message_count <> e.message_count
+		  )
+		  AND (e.task_id IS NULL OR e.enriched_at <= ?)
+		  AND NOT EXISTS (
+		    SELECT 1 FROM task_edits d
+		    WHERE d.task_id = t.id AND d.field = ?
+		  )
+		ORDER BY m.last_ts DESC
+		LIMIT ?
+	`, time.Now().Add(-throttle).Unix(), EditDescription, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks to re-enrich: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []*ReenrichCandidate
+	for rows.Next() {
+		c := &ReenrichCandidate{}
+		var lastTS int64
+		if err := rows.Scan(&c.TaskID, &c.ThreadID, &c.MessageCount, &lastTS); err != nil {
+			return nil, err
+		}
+		c.LastMessageTS = time.Unix(lastTS, 0)
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// MarkTaskEnriched records that a task's current description reflects its thread as of the
+// given message count and latest message
+func (db *DB) MarkTaskEnriched(taskID string, messageCount int, lastMessageTS time.Time) error {
+	_, err := db.Exec(`
+		INSERT INTO task_enrichments (task_id, message_count, last_message_ts, description_hash, enriched_at)
+		SELECT id, ?, ?, md5(COALESCE(description, '')), ?
+		FROM tasks
+		WHERE id = ?
+		ON CONFLICT (task_id) DO UPDATE SET
+			message_count = excluded.message_count,
+			last_message_ts = excluded.last_message_ts,
+			description_hash = excluded.description_hash,
+			enriched_at = excluded.enriched_at
+	`, messageCount, lastMessageTS.Unix(), time.Now().Unix(), taskID)
+	if err != nil {
+		return fmt.Errorf("failed to record enrichment of task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// SetTaskDescription replaces a task's description
+func (db *DB) SetTaskDescription(taskID, description string) error {
+	query := `UPDATE tasks SET description = ?, updated_at = ? WHERE id = ?`
+	if _, err := db.Exec(query, description, time.Now().Unix(), taskID); err != nil {
+		return fmt.Errorf("failed to update description for task %s: %w", taskID, err)
+	}
+	return nil
+}
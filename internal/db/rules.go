@@ -0,0 +1,233 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Processing rule kinds
+const (
+	RuleThread = "thread" // A single thread, by ID
+	RuleSender = "sender" // Messages from an email address
+	RuleDomain = "domain" // Messages from a domain or any of its subdomains
+	RuleLabel  = "label"  // Messages carrying a label (Gmail label or Outlook category)
+)
+
+// ProcessingRule excludes matching threads from AI processing. A rule with a tag is a tag rule
+// instead: tasks from matching threads get the tag and the threads are processed as usual.
+type ProcessingRule struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Pattern   string    `json:"pattern"`
+	Tag       string    `json:"tag,omitempty"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ValidRuleKind reports whether kind is a known processing rule kind
+func ValidRuleKind(kind string) bool {
+	switch kind {
+	case RuleThread, RuleSender, RuleDomain, RuleLabel:
+		return true
+	}
+	return false
+}
+
+// normalizeRulePattern lowercases everything except thread IDs, which are case-sensitive
+func normalizeRulePattern(kind, pattern string) string {
+	pattern = strings.TrimSpace(pattern)
+	switch kind {
+	case RuleSender:
+		return strings.ToLower(senderAddress(pattern))
+	case RuleDomain:
+		return strings.ToLower(strings.TrimPrefix(pattern, "@"))
+	case RuleLabel:
+		return strings.ToLower(pattern)
+	}
+	return pattern
+}
+
+// senderAddress extracts the bare address from a From header like "Name <addr>"
+func senderAddress(from string) string {
+	if addr, err := mail.ParseAddress(from); err == nil {
+		return addr.Address
+	}
+	return strings.Trim(strings.TrimSpace(from), "<>")
+}
+
+// Matches reports whether a message in the given thread is covered by the rule
+func (r *ProcessingRule) Matches(threadID, from string, labels []string) bool {
+	switch r.Kind {
+	case RuleThread:
+		return threadID == r.Pattern
+	case RuleSender:
+		return strings.ToLower(senderAddress(from)) == r.Pattern
+	case RuleDomain:
+		address := strings.ToLower(senderAddress(from))
+		at := strings.LastIndex(address, "@")
+		if at < 0 {
+			return false
+		}
+		domain := address[at+1:]
+		return domain == r.Pattern || strings.HasSuffix(domain, "."+r.Pattern)
+	case RuleLabel:
+		for _, label := range labels {
+			if strings.EqualFold(label, r.Pattern) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AddProcessingRule creates a rule; adding a rule that already exists returns the existing one
+func (db *DB) AddProcessingRule(kind, pattern, note string) (*ProcessingRule, error) {
+	if !ValidRuleKind(kind) {
+		return nil, fmt.Errorf("unknown rule kind %q (expected thread, sender, domain or label)", kind)
+	}
+	pattern = normalizeRulePattern(kind, pattern)
+	if pattern == "" {
+		return nil, fmt.Errorf("rule pattern is required")
+	}
+
+	rule := &ProcessingRule{
+		ID:        fmt.Sprintf("rule_%d", time.Now().UnixNano()),
+		Kind:      kind,
+		Pattern:   pattern,
+		Note:      note,
+		CreatedAt: time.Now(),
+	}
+
+	query := `
+		INSERT INTO processing_rules (id, kind, pattern, note, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (kind, pattern) DO NOTHING
+	`
+	if _, err := db.Exec(query, rule.ID, rule.Kind, rule.Pattern, rule.Note, rule.CreatedAt.Unix()); err != nil {
+		return nil, fmt.Errorf("failed to save processing rule: %w", err)
+	}
+
+	var createdAt int64
+	var savedNote *string
+	err := db.QueryRow(`SELECT id, note, created_at FROM processing_rules WHERE kind = ? AND pattern = ?`,
+		kind, pattern).Scan(&rule.ID, &savedNote, &createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load processing rule: %w", err)
+	}
+	rule.Note = ""
+	if savedNote != nil {
+		rule.Note = *savedNote
+	}
+	rule.CreatedAt = time.Unix(createdAt, 0)
+
+	return rule, nil
+}
+
+// GetProcessingRules returns all processing rules, oldest first
+func (db *DB) GetProcessingRules() ([]*ProcessingRule, error) {
+	rows, err := db.Query(`SELECT id, kind, pattern, note, created_at FROM processing_rules ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query processing rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*ProcessingRule
+	for rows.Next() {
+		rule := &ProcessingRule{}
+		var note *string
+		var createdAt int64
+		if err := rows.Scan(&rule.ID, &rule.Kind, &rule.Pattern, &note, &createdAt); err != nil {
+			return nil, err
+		}
+		if note != nil {
+			rule.Note = *note
+		}
+		rule.CreatedAt = time.Unix(createdAt, 0)
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// DeleteProcessingRule removes a rule and returns the threads it skipped to the processing queue
+func (db *DB) DeleteProcessingRule(id string) error {
+	return db.WithTx(func(tx *sql.Tx) error {
+		result, err := tx.Exec(`DELETE FROM processing_rules WHERE id = ?`, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete processing rule: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("processing rule not found: %s", id)
+		}
+		if _, err := tx.Exec(`UPDATE threads SET skipped_by_rule = NULL WHERE skipped_by_rule = ?`, id); err != nil {
+			return fmt.Errorf("failed to release skipped threads: %w", err)
+		}
+		return nil
+	})
+}
+
+// ApplyProcessingRules marks unprocessed threads that match a rule as skipped, taking them out of
+// the AI processing queue. It returns how many threads were skipped.
+func (db *DB) ApplyProcessingRules() (int, error) {
+	rules, err := db.GetProcessingRules()
+	if err != nil {
+		return 0, err
+	}
+	if len(rules) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		SELECT m.thread_id, m.from_addr, m.labels
+		FROM messages m
+		JOIN threads t ON t.id = m.thread_id
+		WHERE (t.summary IS NULL OR t.summary = '') AND t.skipped_by_rule IS NULL
+	`
+	rows, err := db.Query(query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query unprocessed messages: %w", err)
+	}
+
+	matched := make(map[string]string) // thread ID -> rule ID
+	for rows.Next() {
+		var threadID string
+		var from, labelsJSON *string
+		if err := rows.Scan(&threadID, &from, &labelsJSON); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if _, done := matched[threadID]; done {
+			continue
+		}
+
+		var labels []string
+		if labelsJSON != nil {
+			json.Unmarshal([]byte(*labelsJSON), &labels)
+		}
+		var sender string
+		if from != nil {
+			sender = *from
+		}
+
+		for _, rule := range rules {
+			if rule.Matches(threadID, sender, labels) {
+				matched[threadID] = rule.ID
+				break
+			}
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for threadID, ruleID := range matched {
+		if _, err := db.Exec(`UPDATE threads SET skipped_by_rule = ? WHERE id = ?`, ruleID, threadID); err != nil {
+			return 0, fmt.Errorf("failed to mark thread skipped: %w", err)
+		}
+	}
+	return len(matched), nil
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SearchMessagesByKeywords finds messages containing any of the given terms in the sender,
+// subject, snippet or body, ranked by how many terms match and then by recency.
+// It is the fallback when the full-text index is unavailable.
+func (db *DB) SearchMessagesByKeywords(terms []string, limit int) ([]*Message, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var matches []string
+	var args []interface{}
+	for _, term := range terms {
+		matches = append(matches, `CASE WHEN lower(concat_ws(' ', from_addr, subject, snippet, body)) LIKE ? THEN 1 ELSE 0 END`)
+		args = append(args, "%"+strings.ToLower(term)+"%")
+	}
+	args = append(args, limit)
+
+	query := fmt.Sprintf(`
+		SELECT id, thread_id, COALESCE(from_addr, ''), COALESCE(to_addr, ''), COALESCE(subject, ''),
+		       COALESCE(snippet, ''), COALESCE(body, ''), ts, labels
+		FROM (
+			SELECT *, (%s) AS hits FROM messages
+		)
+		WHERE hits > 0
+		ORDER BY hits DESC, ts DESC
+		LIMIT ?
+	`, strings.Join(matches, " + "))
+
+	start := time.Now()
+	rows, err := db.Query(query, args...)
+	db.ObserveQuery(QueryKindFTS, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("keyword search failed: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*Message
+	for rows.Next() {
+		msg := &Message{}
+		var ts int64
+		var labelsJSON *string
+
+		if err := rows.Scan(&msg.ID, &msg.ThreadID, &msg.From, &msg.To, &msg.Subject,
+			&msg.Snippet, &msg.Body, &ts, &labelsJSON); err != nil {
+			return nil, err
+		}
+
+		msg.Timestamp = time.Unix(ts, 0)
+		if labelsJSON != nil && *labelsJSON != "" {
+			json.Unmarshal([]byte(*labelsJSON), &msg.Labels)
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, rows.Err()
+}
@@ -0,0 +1,433 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of content embedded for semantic search
+const (
+	ContentKindThread   = "thread"
+	ContentKindDocument = "document"
+)
+
+// Kinds of search results besides embedded content. Tasks are embedded separately, in
+// task_embeddings; messages and events are found by keyword only.
+const (
+	ContentKindTask    = "task"
+	ContentKindMessage = "message"
+	ContentKindEvent   = "event"
+)
+
+// SearchKinds are the kinds of result a search can return, for filtering by type
+var SearchKinds = []string{ContentKindTask, ContentKindThread, ContentKindMessage, ContentKindEvent, ContentKindDocument}
+
+// minSemanticSimilarity is the cosine similarity below which embedded content isn't considered
+// related to a search
+const minSemanticSimilarity = 0.5
+
+// searchRankOffset damps the weight of top ranks when blending result lists (reciprocal rank
+// fusion), so one list's first result doesn't swamp agreement between lists
+const searchRankOffset = 60
+
+// searchRecencyHalfLife is how long it takes a result's recency boost to halve. A result from
+// today gets as much as ranking first in one list.
+const searchRecencyHalfLife = 30 * 24 * time.Hour
+
+// SearchResult is a task, thread, message, calendar event or Drive document found by search
+type SearchResult struct {
+	Kind         string    `json:"kind"` // One of SearchKinds
+	ID           string    `json:"id"`
+	ThreadID     string    `json:"thread_id,omitempty"` // Email thread of a message, or of a task extracted from one
+	Title        string    `json:"title"`
+	Snippet      string    `json:"snippet"`
+	Link         string    `json:"link"`
+	Timestamp    time.Time `json:"timestamp"`     // Last activity, or an event's start
+	KeywordMatch bool      `json:"keyword_match"` // Found by keyword search
+	Similarity   float64   `json:"similarity"`    // Cosine similarity to the query, if found by semantic search
+	Score        float64   `json:"score"`         // Blended rank score, higher is better
+}
+
+// searchesKind reports whether a search filtered to kinds includes a kind; no kinds means all
+func searchesKind(kinds []string, kind string) bool {
+	return len(kinds) == 0 || slices.Contains(kinds, kind)
+}
+
+// ContentToEmbed is a thread summary or Drive document whose embedding is missing or stale
+type ContentToEmbed struct {
+	Kind     string
+	ID       string
+	Title    string
+	Text     string
+	MimeType string
+	Version  string // Stored with the embedding to tell when the content changes
+}
+
+// threadLink returns the Gmail link to a thread
+func threadLink(threadID string) string {
+	return "https://mail.google.com/mail/u/0/#inbox/" + threadID
+}
+
+// GetContentNeedingEmbedding returns thread summaries, then Drive documents, that have no
+// embedding or have changed since they were embedded, most recent first
+func (db *DB) GetContentNeedingEmbedding(limit int) ([]*ContentToEmbed, error) {
+	threadQuery := `
+		SELECT t.id,
+		       COALESCE((SELECT subject FROM messages m WHERE m.thread_id = t.id ORDER BY m.ts DESC LIMIT 1), ''),
+		       t.summary, '', md5(t.summary)
+		FROM threads t
+		LEFT JOIN content_embeddings ce ON ce.kind = 'thread' AND ce.source_id = t.id
+		WHERE COALESCE(t.summary, '') <> ''
+		  AND (ce.content_version IS NULL OR ce.content_version <> md5(t.summary))
+		ORDER BY t.last_synced DESC
+		LIMIT ?
+	`
+	items, err := db.queryContentToEmbed(ContentKindThread, threadQuery, limit)
+	if err != nil || len(items) >= limit {
+		return items, err
+	}
+
+	docQuery := `
+		SELECT d.id, d.title, COALESCE(d.summary, ''), COALESCE(d.mime_type, ''),
+		       CAST(COALESCE(d.updated_ts, 0) AS VARCHAR)
+		FROM docs d
+		LEFT JOIN content_embeddings ce ON ce.kind = 'document' AND ce.source_id = d.id
+		WHERE ce.content_version IS NULL
+		   OR ce.content_version <> CAST(COALESCE(d.updated_ts, 0) AS VARCHAR)
+		ORDER BY d.updated_ts DESC
+		LIMIT ?
+	`
+	docs, err := db.queryContentToEmbed(ContentKindDocument, docQuery, limit-len(items))
+	if err != nil {
+		return nil, err
+	}
+	return append(items, docs...), nil
+}
+
+func (db *DB) queryContentToEmbed(kind, query string, limit int) ([]*ContentToEmbed, error) {
+	rows, err := db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %ss to embed: %w", kind, err)
+	}
+	defer rows.Close()
+
+	var items []*ContentToEmbed
+	for rows.Next() {
+		item := &ContentToEmbed{Kind: kind}
+		if err := rows.Scan(&item.ID, &item.Title, &item.Text, &item.MimeType, &item.Version); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// SaveContentEmbedding stores the embedding of a thread summary or Drive document
+func (db *DB) SaveContentEmbedding(item *ContentToEmbed, embedding []float64, model string) error {
+	embeddingJSON, err := json.Marshal(embedding)
+	if err != nil {
+		return fmt.Errorf("failed to marshal embedding: %w", err)
+	}
+
+	query := `
+		INSERT OR REPLACE INTO content_embeddings
+		(kind, source_id, embedding, content_version, model, generated_at)
+		VALUES (?, ?, ?::FLOAT[768], ?, ?, ?)
+	`
+	_, err = db.Exec(query, item.Kind, item.ID, string(embeddingJSON), item.Version, model, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to save content embedding: %w", err)
+	}
+	return nil
+}
+
+// SemanticSearch returns the tasks, thread summaries and Drive documents of the given kinds (all
+// when none are given) whose embeddings are closest to the given one, most similar first, leaving
+// out those too dissimilar to be related
+func (db *DB) SemanticSearch(embedding []float64, kinds []string, limit int) ([]*SearchResult, error) {
+	embeddingJSON, err := json.Marshal(embedding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embedding: %w", err)
+	}
+
+	var parts []string
+	var args []interface{}
+	if searchesKind(kinds, ContentKindThread) || searchesKind(kinds, ContentKindDocument) {
+		parts = append(parts, `
+			SELECT ce.kind, ce.source_id,
+			       array_cosine_similarity(ce.embedding, ?::FLOAT[768]) AS similarity,
+			       COALESCE(d.title, (SELECT subject FROM messages m WHERE m.thread_id = ce.source_id ORDER BY m.ts DESC LIMIT 1), '') AS title,
+			       COALESCE(t.summary, d.summary, '') AS snippet,
+			       COALESCE(d.link, '') AS link,
+			       COALESCE(d.updated_ts, (SELECT MAX(m.ts) FROM messages m WHERE m.thread_id = ce.source_id), 0) AS ts
+			FROM content_embeddings ce
+			LEFT JOIN threads t ON ce.kind = 'thread' AND t.id = ce.source_id
+			LEFT JOIN docs d ON ce.kind = 'document' AND d.id = ce.source_id
+			WHERE t.id IS NOT NULL OR d.id IS NOT NULL`)
+		args = append(args, string(embeddingJSON))
+	}
+	if searchesKind(kinds, ContentKindTask) {
+		parts = append(parts, `
+			SELECT 'task', t.id,
+			       array_cosine_similarity(te.embedding, ?::FLOAT[768]),
+			       t.title, COALESCE(t.description, ''), '', COALESCE(t.updated_at, 0)
+			FROM task_embeddings te
+			JOIN tasks t ON t.id = te.task_id
+			WHERE t.status <> 'cancelled'`)
+		args = append(args, string(embeddingJSON))
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT kind, source_id, similarity, title, snippet, link, ts
+		FROM (%s)
+		WHERE similarity >= ?
+		ORDER BY similarity DESC
+		LIMIT ?
+	`, strings.Join(parts, "\n\t\t\tUNION ALL"))
+	args = append(args, minSemanticSimilarity, limit)
+
+	start := time.Now()
+	rows, err := db.Query(query, args...)
+	db.ObserveQuery(QueryKindSimilarity, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("semantic search failed: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*SearchResult
+	for rows.Next() {
+		result := &SearchResult{}
+		var ts int64
+		if err := rows.Scan(&result.Kind, &result.ID, &result.Similarity, &result.Title, &result.Snippet, &result.Link, &ts); err != nil {
+			return nil, err
+		}
+		if !searchesKind(kinds, result.Kind) {
+			continue // A thread when only documents were asked for, or the reverse
+		}
+		result.Timestamp = time.Unix(ts, 0)
+		if result.Kind == ContentKindThread {
+			result.ThreadID = result.ID
+			result.Link = threadLink(result.ID)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return db.linkTaskThreads(results)
+}
+
+// KeywordSearch returns the results of the given kinds (all when none are given) matching any of
+// the terms: threads and messages by full-text search, falling back to keyword matching, and
+// tasks, calendar events and Drive documents by their titles and descriptions
+func (db *DB) KeywordSearch(terms []string, kinds []string, limit int) ([]*SearchResult, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	var results []*SearchResult
+	if searchesKind(kinds, ContentKindThread) || searchesKind(kinds, ContentKindMessage) {
+		messages, err := db.SearchMessages(strings.Join(terms, " OR "), limit*3)
+		if err != nil || len(messages) == 0 {
+			if messages, err = db.SearchMessagesByKeywords(terms, limit*3); err != nil {
+				return nil, err
+			}
+		}
+		if searchesKind(kinds, ContentKindThread) {
+			results = append(results, messageThreadResults(messages, limit)...)
+		}
+		if searchesKind(kinds, ContentKindMessage) {
+			for i, msg := range messages {
+				if i >= limit {
+					break
+				}
+				results = append(results, &SearchResult{
+					Kind:         ContentKindMessage,
+					ID:           msg.ID,
+					ThreadID:     msg.ThreadID,
+					Title:        msg.Subject,
+					Snippet:      msg.Snippet,
+					Link:         threadLink(msg.ThreadID),
+					Timestamp:    msg.Timestamp,
+					KeywordMatch: true,
+				})
+			}
+		}
+	}
+
+	// Tasks, events and documents rank by how many terms they contain, then by recency
+	searches := []struct {
+		kind, query string
+	}{
+		{ContentKindTask, `
+			SELECT id, title, COALESCE(description, ''), '', COALESCE(updated_at, 0)
+			FROM (SELECT *, (%s) AS hits FROM tasks WHERE status <> 'cancelled')
+			WHERE hits > 0
+			ORDER BY hits DESC, updated_at DESC
+			LIMIT ?`},
+		{ContentKindEvent, `
+			SELECT id, title, COALESCE(description, ''), COALESCE(meeting_link, ''), start_ts
+			FROM (SELECT *, (%s) AS hits FROM events WHERE COALESCE(status, '') <> 'cancelled')
+			WHERE hits > 0
+			ORDER BY hits DESC, start_ts DESC
+			LIMIT ?`},
+		{ContentKindDocument, `
+			SELECT id, title, COALESCE(summary, ''), link, COALESCE(updated_ts, 0)
+			FROM (SELECT *, (%s) AS hits FROM docs)
+			WHERE hits > 0
+			ORDER BY hits DESC, updated_ts DESC
+			LIMIT ?`},
+	}
+	columns := map[string]string{
+		ContentKindTask:     `concat_ws(' ', title, description)`,
+		ContentKindEvent:    `concat_ws(' ', title, description, location)`,
+		ContentKindDocument: `title`,
+	}
+	for _, search := range searches {
+		if !searchesKind(kinds, search.kind) {
+			continue
+		}
+
+		var matches []string
+		var args []interface{}
+		for _, term := range terms {
+			matches = append(matches, fmt.Sprintf(`CASE WHEN lower(%s) LIKE ? THEN 1 ELSE 0 END`, columns[search.kind]))
+			args = append(args, "%"+strings.ToLower(term)+"%")
+		}
+		args = append(args, limit)
+
+		found, err := db.querySearchResults(search.kind, fmt.Sprintf(search.query, strings.Join(matches, " + ")), args...)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, found...)
+	}
+	return db.linkTaskThreads(results)
+}
+
+// messageThreadResults turns matching messages into one result per thread, in order of each
+// thread's best match
+func messageThreadResults(messages []*Message, limit int) []*SearchResult {
+	var results []*SearchResult
+	seen := make(map[string]bool)
+	for _, msg := range messages {
+		if seen[msg.ThreadID] || len(results) >= limit {
+			continue
+		}
+		seen[msg.ThreadID] = true
+		results = append(results, &SearchResult{
+			Kind:         ContentKindThread,
+			ID:           msg.ThreadID,
+			ThreadID:     msg.ThreadID,
+			Title:        msg.Subject,
+			Snippet:      msg.Snippet,
+			Link:         threadLink(msg.ThreadID),
+			Timestamp:    msg.Timestamp,
+			KeywordMatch: true,
+		})
+	}
+	return results
+}
+
+// querySearchResults runs a keyword query selecting id, title, snippet, link and a unix timestamp
+func (db *DB) querySearchResults(kind, query string, args ...interface{}) ([]*SearchResult, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s search failed: %w", kind, err)
+	}
+	defer rows.Close()
+
+	var results []*SearchResult
+	for rows.Next() {
+		result := &SearchResult{Kind: kind, KeywordMatch: true}
+		var ts int64
+		if err := rows.Scan(&result.ID, &result.Title, &result.Snippet, &result.Link, &ts); err != nil {
+			return nil, err
+		}
+		result.Timestamp = time.Unix(ts, 0)
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// linkTaskThreads sets the thread and Gmail link of task results extracted from email
+func (db *DB) linkTaskThreads(results []*SearchResult) ([]*SearchResult, error) {
+	for _, result := range results {
+		if result.Kind != ContentKindTask {
+			continue
+		}
+		var source, sourceID sql.NullString
+		err := db.QueryRow(`SELECT source, source_id FROM tasks WHERE id = ?`, result.ID).Scan(&source, &sourceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up task %s: %w", result.ID, err)
+		}
+		if source.String == "gmail" && sourceID.String != "" {
+			result.ThreadID = sourceID.String
+			result.Link = threadLink(sourceID.String)
+		}
+	}
+	return results, nil
+}
+
+// BlendSearchResults merges ranked result lists by reciprocal rank fusion: each result scores
+// by its rank among results of its kind in every list it appears in, so results found by both
+// keyword and semantic search rise to the top and no kind crowds out the others. Recent results
+// get a boost that fades with age.
+func BlendSearchResults(limit int, lists ...[]*SearchResult) []*SearchResult {
+	merged := make(map[string]*SearchResult)
+	var order []string
+	for _, list := range lists {
+		ranks := make(map[string]int)
+		for _, result := range list {
+			rank := ranks[result.Kind]
+			ranks[result.Kind]++
+
+			key := result.Kind + ":" + result.ID
+			existing, ok := merged[key]
+			if !ok {
+				copied := *result
+				existing = &copied
+				merged[key] = existing
+				order = append(order, key)
+			} else {
+				existing.KeywordMatch = existing.KeywordMatch || result.KeywordMatch
+				existing.Similarity = max(existing.Similarity, result.Similarity)
+				if existing.Title == "" {
+					existing.Title = result.Title
+				}
+				if existing.Timestamp.Unix() <= 0 {
+					existing.Timestamp = result.Timestamp
+				}
+			}
+			existing.Score += 1 / float64(searchRankOffset+rank+1)
+		}
+	}
+
+	now := time.Now()
+	results := make([]*SearchResult, 0, len(order))
+	for _, key := range order {
+		result := merged[key]
+		if result.Timestamp.Unix() > 0 {
+			age := max(now.Sub(result.Timestamp), 0)
+			halfLives := float64(age) / float64(searchRecencyHalfLife)
+			result.Score += math.Pow(0.5, halfLives) / float64(searchRankOffset+1)
+		}
+		results = append(results, result)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
@@ -0,0 +1,179 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SenderStats is how the user has treated the threads a sender started: how many got a reply,
+// and what became of the tasks extracted from them
+type SenderStats struct {
+	Sender    string    `json:"sender"`
+	Threads   int       `json:"threads"`
+	Replied   int       `json:"replied"` // Threads the user wrote in after the sender
+	Extracted int       `json:"extracted"`
+	Accepted  int       `json:"accepted"`  // Extracted tasks completed or being worked on
+	Discarded int       `json:"discarded"` // Extracted tasks cancelled
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Reputation returns how much of the sender's mail the user engages with, from 0 to 1: the share
+// of their threads replied to, averaged with the share of their resolved tasks accepted when any
+// have been resolved
+func (s *SenderStats) Reputation() float64 {
+	if s == nil || s.Threads == 0 {
+		return 0
+	}
+	reputation := float64(s.Replied) / float64(s.Threads)
+	if resolved := s.Accepted + s.Discarded; resolved > 0 {
+		reputation = (reputation + float64(s.Accepted)/float64(resolved)) / 2
+	}
+	return reputation
+}
+
+// senderCounts accumulates a sender's stats while messages are scanned
+type senderCounts struct {
+	*SenderStats
+	threads map[string]bool
+}
+
+// RefreshSenderStats rebuilds the per-sender stats from the threads started since the given time.
+// A thread belongs to whoever sent its first message, so threads the user started are left out.
+// It returns how many senders have stats.
+func (db *DB) RefreshSenderStats(userEmail string, since time.Time) (int, error) {
+	userEmail = strings.ToLower(strings.TrimSpace(userEmail))
+	if userEmail == "" {
+		return 0, fmt.Errorf("user email is required to tell sent from received mail")
+	}
+	now := time.Now()
+
+	rows, err := db.Query(`
+		SELECT thread_id, COALESCE(from_addr, ''), ts
+		FROM messages
+		WHERE thread_id IN (
+			SELECT thread_id FROM messages GROUP BY thread_id HAVING MIN(ts) >= ?
+		)
+		ORDER BY thread_id, ts
+	`, since.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to query messages: %w", err)
+	}
+	defer rows.Close()
+
+	senders := make(map[string]*senderCounts)
+	starters := make(map[string]*senderCounts) // By thread ID
+	replied := make(map[string]bool)
+
+	var prevThread string
+	for rows.Next() {
+		var threadID, from string
+		var ts int64
+		if err := rows.Scan(&threadID, &from, &ts); err != nil {
+			return 0, err
+		}
+		sender := strings.ToLower(senderAddress(from))
+		first := threadID != prevThread
+		prevThread = threadID
+
+		if !first {
+			if starters[threadID] != nil && sender == userEmail {
+				replied[threadID] = true
+			}
+			continue
+		}
+		if sender == "" || sender == userEmail {
+			continue
+		}
+		counts, ok := senders[sender]
+		if !ok {
+			counts = &senderCounts{
+				SenderStats: &SenderStats{Sender: sender, UpdatedAt: now},
+				threads:     make(map[string]bool),
+			}
+			senders[sender] = counts
+		}
+		counts.threads[threadID] = true
+		starters[threadID] = counts
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	rows.Close()
+
+	for threadID := range replied {
+		starters[threadID].Replied++
+	}
+
+	taskRows, err := db.Query(`SELECT source_id, status FROM tasks WHERE source = 'gmail'`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query extracted tasks: %w", err)
+	}
+	for taskRows.Next() {
+		var threadID sql.NullString
+		var status string
+		if err := taskRows.Scan(&threadID, &status); err != nil {
+			taskRows.Close()
+			return 0, err
+		}
+		counts := starters[threadID.String]
+		if counts == nil {
+			continue
+		}
+		counts.Extracted++
+		switch status {
+		case "completed", "in_progress":
+			counts.Accepted++
+		case "cancelled":
+			counts.Discarded++
+		}
+	}
+	taskRows.Close()
+
+	if _, err := db.Exec(`DELETE FROM sender_stats`); err != nil {
+		return 0, fmt.Errorf("failed to clear sender stats: %w", err)
+	}
+	query := `
+		INSERT INTO sender_stats (sender, threads, replied, extracted, accepted, discarded, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	for sender, counts := range senders {
+		counts.Threads = len(counts.threads)
+		_, err := db.Exec(query, sender, counts.Threads, counts.Replied, counts.Extracted,
+			counts.Accepted, counts.Discarded, now.Unix())
+		if err != nil {
+			return 0, fmt.Errorf("failed to save stats for %s: %w", sender, err)
+		}
+	}
+	return len(senders), nil
+}
+
+// GetSenderStats returns the stats of the sender in a From header, or nil if they haven't started
+// any threads
+func (db *DB) GetSenderStats(from string) (*SenderStats, error) {
+	stats := &SenderStats{}
+	var updatedAt int64
+	err := db.QueryRow(`
+		SELECT sender, threads, replied, extracted, accepted, discarded, updated_at
+		FROM sender_stats
+		WHERE sender = ?
+	`, strings.ToLower(senderAddress(from))).Scan(&stats.Sender, &stats.Threads, &stats.Replied,
+		&stats.Extracted, &stats.Accepted, &stats.Discarded, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sender stats: %w", err)
+	}
+	stats.UpdatedAt = time.Unix(updatedAt, 0)
+	return stats, nil
+}
+
+// SetThreadRelevance records whether a thread is for the user to act on
+func (db *DB) SetThreadRelevance(threadID string, relevant bool) error {
+	if _, err := db.Exec(`UPDATE threads SET relevant_to_user = ? WHERE id = ?`, relevant, threadID); err != nil {
+		return fmt.Errorf("failed to update thread relevance: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TUISession holds the layout a TUI client restores on startup
+type TUISession struct {
+	Tab        string `json:"tab"`         // Last open tab, e.g. "tasks"
+	TaskSource string `json:"task_source"` // Tasks list source filter ("" = all)
+}
+
+// tuiSessionKey is the prefs key for a TUI client's session
+func tuiSessionKey(clientID string) string {
+	return "tui_session:" + strings.ToLower(strings.TrimSpace(clientID))
+}
+
+// GetTUISession returns the saved session for a TUI client, or an empty session if none is saved
+func (db *DB) GetTUISession(clientID string) (*TUISession, error) {
+	val, err := db.GetPreference(tuiSessionKey(clientID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TUI session: %w", err)
+	}
+
+	session := &TUISession{}
+	if val == "" {
+		return session, nil
+	}
+	if err := json.Unmarshal([]byte(val), session); err != nil {
+		return nil, fmt.Errorf("failed to decode TUI session: %w", err)
+	}
+	return session, nil
+}
+
+// SaveTUISession stores the session for a TUI client
+func (db *DB) SaveTUISession(clientID string, session *TUISession) error {
+	val, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to encode TUI session: %w", err)
+	}
+	if err := db.SetPreference(tuiSessionKey(clientID), string(val)); err != nil {
+		return fmt.Errorf("failed to save TUI session: %w", err)
+	}
+	return nil
+}
+
+// tuiLastActiveKey is the prefs key holding when a user last used any TUI client
+const tuiLastActiveKey = "tui_last_active"
+
+// RecordTUIPresence records a TUI heartbeat: when the user last pressed a key in it
+func (db *DB) RecordTUIPresence(lastActive time.Time) error {
+	current, err := db.GetTUILastActive()
+	if err != nil {
+		return err
+	}
+	// Another client may have seen the user more recently
+	if !lastActive.After(current) {
+		return nil
+	}
+	if err := db.SetPreference(tuiLastActiveKey, strconv.FormatInt(lastActive.Unix(), 10)); err != nil {
+		return fmt.Errorf("failed to record TUI presence: %w", err)
+	}
+	return nil
+}
+
+// GetTUILastActive returns when the user last used any TUI client (zero if never)
+func (db *DB) GetTUILastActive() (time.Time, error) {
+	val, err := db.GetPreference(tuiLastActiveKey)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get TUI presence: %w", err)
+	}
+	if val == "" {
+		return time.Time{}, nil
+	}
+	ts, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return time.Time{}, nil
+	}
+	return time.Unix(ts, 0), nil
+}
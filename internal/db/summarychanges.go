@@ -0,0 +1,264 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxSummaryChangePoints caps the points listed as added or removed in one change
+const maxSummaryChangePoints = 5
+
+// samePointThreshold is the word overlap at which two summary points count as the same point
+// reworded
+const samePointThreshold = 0.5
+
+// SummaryChange is what changed when a thread was re-summarized after new messages arrived
+type SummaryChange struct {
+	ID              string    `json:"id"`
+	ThreadID        string    `json:"thread_id"`
+	Subject         string    `json:"subject"`
+	PreviousSummary string    `json:"previous_summary"`
+	Summary         string    `json:"summary"`
+	Added           []string  `json:"added"`   // Points new to the summary
+	Removed         []string  `json:"removed"` // Points dropped from it
+	DetectedAt      time.Time `json:"detected_at"`
+
+	// Insights of the thread's deep summary, nil unless it has one (set for briefs only)
+	Insights *ThreadInsights `json:"insights,omitempty"`
+}
+
+// Describe returns a one-line changelog for briefs, e.g.
+// "new: Decision made to ship Friday; removed: Open question about pricing". A thread with a
+// deep summary also lists its open questions and risks.
+func (c *SummaryChange) Describe() string {
+	var parts []string
+	if len(c.Added) > 0 {
+		parts = append(parts, "new: "+strings.Join(c.Added, "; "))
+	}
+	if len(c.Removed) > 0 {
+		parts = append(parts, "removed: "+strings.Join(c.Removed, "; "))
+	}
+	if c.Insights != nil {
+		if len(c.Insights.OpenQuestions) > 0 {
+			parts = append(parts, "open: "+strings.Join(c.Insights.OpenQuestions, "; "))
+		}
+		if len(c.Insights.Risks) > 0 {
+			parts = append(parts, "risks: "+strings.Join(c.Insights.Risks, "; "))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+var (
+	summaryBulletPattern   = regexp.MustCompile(`^(?:[-*•]|\d+[.)])\s*`)
+	summarySentencePattern = regexp.MustCompile(`([.!?])\s+`)
+	summaryWordPattern     = regexp.MustCompile(`[a-z0-9]+`)
+)
+
+// summaryPoints splits a summary into its points: bullets, lines and sentences, leaving out
+// headings such as "Action items:"
+func summaryPoints(summary string) []string {
+	var points []string
+	for _, line := range strings.Split(summary, "\n") {
+		line = summaryBulletPattern.ReplaceAllString(strings.TrimSpace(line), "")
+		line = strings.ReplaceAll(line, "**", "")
+		for _, sentence := range strings.Split(summarySentencePattern.ReplaceAllString(line, "$1\n"), "\n") {
+			sentence = strings.TrimSpace(sentence)
+			if sentence == "" || strings.HasSuffix(sentence, ":") {
+				continue
+			}
+			points = append(points, strings.TrimRight(sentence, "."))
+		}
+	}
+	return points
+}
+
+// pointWords returns the distinct words of a summary point that carry meaning
+func pointWords(point string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range summaryWordPattern.FindAllString(strings.ToLower(point), -1) {
+		if len(word) > 2 {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+// samePoint reports whether two summary points say the same thing, allowing for rewording
+func samePoint(a, b map[string]bool) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared)/float64(len(a)+len(b)-shared) >= samePointThreshold
+}
+
+// DiffSummaries compares two summaries of a thread point by point, returning the points only
+// in the current summary and those only in the previous one
+func DiffSummaries(previous, current string) (added, removed []string) {
+	before, after := summaryPoints(previous), summaryPoints(current)
+	beforeWords := make([]map[string]bool, len(before))
+	for i, point := range before {
+		beforeWords[i] = pointWords(point)
+	}
+	afterWords := make([]map[string]bool, len(after))
+	for i, point := range after {
+		afterWords[i] = pointWords(point)
+	}
+
+	unmatched := func(points []string, words, others []map[string]bool) []string {
+		var result []string
+		for i, point := range points {
+			matched := false
+			for _, other := range others {
+				if samePoint(words[i], other) {
+					matched = true
+					break
+				}
+			}
+			if !matched && len(result) < maxSummaryChangePoints {
+				result = append(result, point)
+			}
+		}
+		return result
+	}
+
+	return unmatched(after, afterWords, beforeWords), unmatched(before, beforeWords, afterWords)
+}
+
+// getThreadSummary returns a thread's stored summary, or "" if it has none yet
+func (db *DB) getThreadSummary(threadID string) string {
+	var summary sql.NullString
+	db.QueryRow(`SELECT summary FROM threads WHERE id = ?`, threadID).Scan(&summary)
+	return summary.String
+}
+
+// recordSummaryChange stores what changed between a thread's previous and new summary, if
+// anything did beyond rewording
+func (db *DB) recordSummaryChange(threadID, previous, summary string) error {
+	added, removed := DiffSummaries(previous, summary)
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	var subject sql.NullString
+	db.QueryRow(`SELECT subject FROM messages WHERE thread_id = ? ORDER BY ts DESC LIMIT 1`, threadID).Scan(&subject)
+
+	addedJSON, _ := json.Marshal(added)
+	removedJSON, _ := json.Marshal(removed)
+	query := `
+		INSERT INTO summary_changes (id, thread_id, subject, previous_summary, summary, added, removed, detected_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := db.Exec(query, fmt.Sprintf("sumchg_%d", time.Now().UnixNano()), threadID, subject.String,
+		previous, summary, string(addedJSON), string(removedJSON), time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to record summary change: %w", err)
+	}
+	return nil
+}
+
+// GetSummaryChanges returns the changes to a thread's summary, newest first
+func (db *DB) GetSummaryChanges(threadID string) ([]*SummaryChange, error) {
+	return db.querySummaryChanges(`WHERE thread_id = ? ORDER BY detected_at DESC, id DESC`, threadID)
+}
+
+// GetUnreportedSummaryChanges returns summary changes not yet called out in a brief, one per
+// thread, most recent first. Successive changes to the same thread are combined into one
+// against the summary last reported, and threads that have since been archived are left out.
+func (db *DB) GetUnreportedSummaryChanges(limit int) ([]*SummaryChange, error) {
+	changes, err := db.querySummaryChanges(`
+		WHERE reported_at IS NULL
+		  AND thread_id NOT IN (SELECT thread_id FROM archived_threads)
+		ORDER BY detected_at, id
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]*SummaryChange)
+	var order []string
+	for _, change := range changes {
+		first, ok := merged[change.ThreadID]
+		if !ok {
+			merged[change.ThreadID] = change
+			order = append(order, change.ThreadID)
+			continue
+		}
+		combined := *change
+		combined.PreviousSummary = first.PreviousSummary
+		combined.Added, combined.Removed = DiffSummaries(combined.PreviousSummary, combined.Summary)
+		merged[change.ThreadID] = &combined
+	}
+
+	var result []*SummaryChange
+	var threadIDs []string
+	for i := len(order) - 1; i >= 0 && len(result) < limit; i-- {
+		change := merged[order[i]]
+		if len(change.Added) == 0 && len(change.Removed) == 0 {
+			continue
+		}
+		result = append(result, change)
+		threadIDs = append(threadIDs, change.ThreadID)
+	}
+
+	insights, err := db.GetThreadInsights(threadIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range result {
+		change.Insights = insights[change.ThreadID]
+	}
+	return result, nil
+}
+
+// MarkSummaryChangesReported records that the summary changes of the given threads were called
+// out in a brief
+func (db *DB) MarkSummaryChangesReported(threadIDs []string) error {
+	now := time.Now().Unix()
+	for _, id := range threadIDs {
+		_, err := db.Exec(`UPDATE summary_changes SET reported_at = ? WHERE thread_id = ? AND reported_at IS NULL`, now, id)
+		if err != nil {
+			return fmt.Errorf("failed to mark summary changes reported: %w", err)
+		}
+	}
+	return nil
+}
+
+func (db *DB) querySummaryChanges(where string, args ...interface{}) ([]*SummaryChange, error) {
+	query := `
+		SELECT id, thread_id, subject, previous_summary, summary, added, removed, detected_at
+		FROM summary_changes ` + where
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query summary changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*SummaryChange
+	for rows.Next() {
+		change := &SummaryChange{}
+		var added, removed sql.NullString
+		var detectedAt int64
+		err := rows.Scan(&change.ID, &change.ThreadID, &change.Subject, &change.PreviousSummary,
+			&change.Summary, &added, &removed, &detectedAt)
+		if err != nil {
+			return nil, err
+		}
+		json.Unmarshal([]byte(added.String), &change.Added)
+		json.Unmarshal([]byte(removed.String), &change.Removed)
+		change.DetectedAt = time.Unix(detectedAt, 0)
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
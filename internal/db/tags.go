@@ -0,0 +1,374 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxTagLength keeps tags short enough to show next to task titles
+const maxTagLength = 32
+
+// TagRulePrefix starts the IDs of tag rules, telling them apart from "do not process" rules
+const TagRulePrefix = "tagrule_"
+
+var validTag = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// TagCount is a tag with the number of open tasks carrying it
+type TagCount struct {
+	Tag  string `json:"tag"`
+	Open int    `json:"open"`
+}
+
+// TagStats summarizes a tag's activity over a period, for the weekly review
+type TagStats struct {
+	Tag       string `json:"tag"`
+	Added     int    `json:"added"`     // Tagged tasks created in the period
+	Completed int    `json:"completed"` // Tagged tasks completed in the period
+	Open      int    `json:"open"`      // Tagged tasks still open
+}
+
+// NormalizeTag lowercases a tag, drops a leading '#' and joins words with dashes, so
+// "#Deep Work" becomes "deep-work"
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	tag = strings.Join(strings.Fields(tag), "-")
+	if tag == "" {
+		return "", fmt.Errorf("tag is required")
+	}
+	if len(tag) > maxTagLength || !validTag.MatchString(tag) {
+		return "", fmt.Errorf("invalid tag %q (letters, digits, - and _ only, up to %d characters)", tag, maxTagLength)
+	}
+	return tag, nil
+}
+
+// AddTaskTag tags a task and returns the normalized tag. Adding a tag twice is a no-op.
+func (db *DB) AddTaskTag(taskID, tag string) (string, error) {
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return "", err
+	}
+
+	query := `
+		INSERT INTO task_tags (task_id, tag, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (task_id, tag) DO NOTHING
+	`
+	if _, err := db.Exec(query, taskID, tag, time.Now().Unix()); err != nil {
+		return "", fmt.Errorf("failed to tag task: %w", err)
+	}
+	return tag, nil
+}
+
+// RemoveTaskTag removes a tag from a task
+func (db *DB) RemoveTaskTag(taskID, tag string) error {
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec(`DELETE FROM task_tags WHERE task_id = ? AND tag = ?`, taskID, tag); err != nil {
+		return fmt.Errorf("failed to remove tag: %w", err)
+	}
+	return nil
+}
+
+// GetTaskTags returns a task's tags in alphabetical order
+func (db *DB) GetTaskTags(taskID string) ([]string, error) {
+	task := &Task{ID: taskID}
+	if err := db.loadTaskTags([]*Task{task}); err != nil {
+		return nil, err
+	}
+	return task.Tags, nil
+}
+
+// loadTaskTags fills in the tags of the given tasks
+func (db *DB) loadTaskTags(tasks []*Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*Task, len(tasks))
+	placeholders := make([]string, 0, len(tasks))
+	args := make([]interface{}, 0, len(tasks))
+	for _, task := range tasks {
+		task.Tags = nil
+		byID[task.ID] = task
+		placeholders = append(placeholders, "?")
+		args = append(args, task.ID)
+	}
+
+	query := `SELECT task_id, tag FROM task_tags WHERE task_id IN (` + strings.Join(placeholders, ", ") + `) ORDER BY tag`
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to load task tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, tag string
+		if err := rows.Scan(&taskID, &tag); err != nil {
+			return err
+		}
+		if task := byID[taskID]; task != nil {
+			task.Tags = append(task.Tags, tag)
+		}
+	}
+	return rows.Err()
+}
+
+// GetTags returns every tag in use with its number of open tasks, busiest first
+func (db *DB) GetTags() ([]*TagCount, error) {
+	query := `
+		SELECT tt.tag, COUNT(*) FILTER (WHERE t.status IN ('pending', 'in_progress')) AS open
+		FROM task_tags tt
+		JOIN tasks t ON t.id = tt.task_id
+		GROUP BY tt.tag
+		ORDER BY open DESC, tt.tag
+	`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []*TagCount
+	for rows.Next() {
+		tag := &TagCount{}
+		if err := rows.Scan(&tag.Tag, &tag.Open); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// GetTasksByTag returns tasks carrying a tag, with the same filtering and order as GetAllTasks
+func (db *DB) GetTasksByTag(tag string, limit int) ([]*Task, error) {
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT id, source, source_id, title, description, due_ts, project,
+		       impact, urgency, effort, stakeholder, score, status, metadata,
+		       matched_priorities, created_at, updated_at, completed_at,
+		       COALESCE(recurrence, ''), COALESCE(recurs_from, '')
+		FROM tasks
+		WHERE id IN (SELECT task_id FROM task_tags WHERE tag = ?)
+		  AND (
+		    stakeholder IS NULL
+		    OR stakeholder = ''
+		    OR LOWER(stakeholder) IN ('me', 'you', 'i', 'myself')
+		    OR stakeholder LIKE '%@%'
+		  )
+		ORDER BY score DESC, created_at DESC
+		LIMIT ?
+	`
+	rows, err := db.Query(query, tag, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.loadTaskTags(tasks); err != nil {
+		return nil, err
+	}
+	if err := db.loadTaskDelegation(tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetTagStats returns per-tag activity since the given time, most completed first. Tags with
+// nothing added, completed or open are left out.
+func (db *DB) GetTagStats(since time.Time) ([]*TagStats, error) {
+	query := `
+		SELECT tag, added, completed, open FROM (
+			SELECT tt.tag,
+			       COUNT(*) FILTER (WHERE t.created_at >= ?) AS added,
+			       COUNT(*) FILTER (WHERE t.status = 'completed' AND t.completed_at >= ?) AS completed,
+			       COUNT(*) FILTER (WHERE t.status IN ('pending', 'in_progress')) AS open
+			FROM task_tags tt
+			JOIN tasks t ON t.id = tt.task_id
+			GROUP BY tt.tag
+		)
+		WHERE added > 0 OR completed > 0 OR open > 0
+		ORDER BY completed DESC, open DESC, tag
+	`
+	rows, err := db.Query(query, since.Unix(), since.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tag stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*TagStats
+	for rows.Next() {
+		stat := &TagStats{}
+		if err := rows.Scan(&stat.Tag, &stat.Added, &stat.Completed, &stat.Open); err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
+
+// AddTagRule creates a rule tagging tasks from matching threads and applies it to open tasks.
+// Adding a rule that already exists returns the existing one.
+func (db *DB) AddTagRule(kind, pattern, tag, note string) (*ProcessingRule, error) {
+	if !ValidRuleKind(kind) {
+		return nil, fmt.Errorf("unknown rule kind %q (expected thread, sender, domain or label)", kind)
+	}
+	pattern = normalizeRulePattern(kind, pattern)
+	if pattern == "" {
+		return nil, fmt.Errorf("rule pattern is required")
+	}
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO tag_rules (id, kind, pattern, tag, note, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (kind, pattern, tag) DO NOTHING
+	`
+	now := time.Now()
+	_, err = db.Exec(query, fmt.Sprintf("%s%d", TagRulePrefix, now.UnixNano()), kind, pattern, tag, note, now.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to save tag rule: %w", err)
+	}
+
+	rules, err := db.queryTagRules(`WHERE kind = ? AND pattern = ? AND tag = ?`, kind, pattern, tag)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("failed to load tag rule")
+	}
+
+	if _, err := db.applyTagRules(rules, time.Time{}); err != nil {
+		return nil, err
+	}
+	return rules[0], nil
+}
+
+// GetTagRules returns all tag rules, oldest first
+func (db *DB) GetTagRules() ([]*ProcessingRule, error) {
+	return db.queryTagRules(`ORDER BY created_at, id`)
+}
+
+// DeleteTagRule removes a tag rule. Tags it already added stay on their tasks.
+func (db *DB) DeleteTagRule(id string) error {
+	result, err := db.Exec(`DELETE FROM tag_rules WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag rule: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("tag rule not found: %s", id)
+	}
+	return nil
+}
+
+// ApplyTagRules tags open tasks created since the given time whose source thread matches a tag
+// rule. Only new tasks are tagged, so tags removed by hand stay removed. It returns how many
+// tags were added.
+func (db *DB) ApplyTagRules(since time.Time) (int, error) {
+	rules, err := db.GetTagRules()
+	if err != nil {
+		return 0, err
+	}
+	return db.applyTagRules(rules, since)
+}
+
+func (db *DB) applyTagRules(rules []*ProcessingRule, since time.Time) (int, error) {
+	if len(rules) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		SELECT t.id, m.thread_id, m.from_addr, m.labels
+		FROM tasks t
+		JOIN messages m ON m.thread_id = t.source_id
+		WHERE t.status IN ('pending', 'in_progress') AND t.created_at >= ?
+	`
+	rows, err := db.Query(query, since.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("failed to query tasks for tag rules: %w", err)
+	}
+
+	type taskTag struct{ taskID, tag string }
+	matched := make(map[taskTag]bool)
+	for rows.Next() {
+		var taskID, threadID string
+		var from, labelsJSON *string
+		if err := rows.Scan(&taskID, &threadID, &from, &labelsJSON); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		var labels []string
+		if labelsJSON != nil {
+			json.Unmarshal([]byte(*labelsJSON), &labels)
+		}
+		var sender string
+		if from != nil {
+			sender = *from
+		}
+
+		for _, rule := range rules {
+			if rule.Matches(threadID, sender, labels) {
+				matched[taskTag{taskID, rule.Tag}] = true
+			}
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for tt := range matched {
+		result, err := db.Exec(`
+			INSERT INTO task_tags (task_id, tag, created_at)
+			VALUES (?, ?, ?)
+			ON CONFLICT (task_id, tag) DO NOTHING
+		`, tt.taskID, tt.tag, time.Now().Unix())
+		if err != nil {
+			return added, fmt.Errorf("failed to tag task: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			added++
+		}
+	}
+	return added, nil
+}
+
+func (db *DB) queryTagRules(where string, args ...interface{}) ([]*ProcessingRule, error) {
+	rows, err := db.Query(`SELECT id, kind, pattern, tag, note, created_at FROM tag_rules `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tag rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*ProcessingRule
+	for rows.Next() {
+		rule := &ProcessingRule{}
+		var note sql.NullString
+		var createdAt int64
+		if err := rows.Scan(&rule.ID, &rule.Kind, &rule.Pattern, &rule.Tag, &note, &createdAt); err != nil {
+			return nil, err
+		}
+		rule.Note = note.String
+		rule.CreatedAt = time.Unix(createdAt, 0)
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
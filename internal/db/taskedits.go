@@ -0,0 +1,55 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// Task fields that can be edited by hand, besides the correctable due, impact and stakeholder
+const (
+	EditTitle       = "title"
+	EditDescription = "description"
+	EditProject     = "project"
+	EditUrgency     = "urgency"
+	EditEffort      = "effort"
+)
+
+// SaveTaskEdits records that the user edited fields of a task, so extracting the task again
+// doesn't overwrite them
+func (db *DB) SaveTaskEdits(taskID string, fields []string) error {
+	now := time.Now().Unix()
+	for _, field := range fields {
+		_, err := db.Exec(`
+			INSERT INTO task_edits (task_id, field, edited_at)
+			VALUES (?, ?, ?)
+			ON CONFLICT (task_id, field) DO UPDATE SET edited_at = excluded.edited_at
+		`, taskID, field, now)
+		if err != nil {
+			return fmt.Errorf("failed to save task edit: %w", err)
+		}
+	}
+	return nil
+}
+
+// editedFields returns the fields of a task the user has edited or corrected
+func (db *DB) editedFields(taskID string) (map[string]bool, error) {
+	rows, err := db.Query(`
+		SELECT field FROM task_edits WHERE task_id = ?
+		UNION
+		SELECT field FROM task_corrections WHERE task_id = ?
+	`, taskID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task edits: %w", err)
+	}
+	defer rows.Close()
+
+	fields := make(map[string]bool)
+	for rows.Next() {
+		var field string
+		if err := rows.Scan(&field); err != nil {
+			return nil, err
+		}
+		fields[field] = true
+	}
+	return fields, rows.Err()
+}
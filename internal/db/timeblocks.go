@@ -0,0 +1,174 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Time block statuses
+const (
+	TimeBlockProposed  = "proposed"  // Waiting to be confirmed in the TUI
+	TimeBlockAccepted  = "accepted"  // Kept in the day's plan without a calendar event
+	TimeBlockScheduled = "scheduled" // Written to the calendar as a tentative event
+	TimeBlockConflict  = "conflict"  // No free time left in the block's window
+	TimeBlockDeclined  = "declined"  // Dismissed without scheduling
+)
+
+// TimeBlock is a focus block from the daily plan, with the tasks it was planned for
+type TimeBlock struct {
+	ID              string    `json:"id"`
+	Day             string    `json:"day"` // YYYY-MM-DD, local time
+	Name            string    `json:"name"`
+	StartTS         time.Time `json:"start_ts"`
+	EndTS           time.Time `json:"end_ts"`
+	TaskIDs         []string  `json:"task_ids"`
+	TaskTitles      []string  `json:"task_titles,omitempty"` // Filled in by the planner for display
+	Status          string    `json:"status"`
+	Conflict        string    `json:"conflict,omitempty"` // Title of the event the block couldn't fit around
+	CalendarEventID string    `json:"calendar_event_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TimeBlockDay returns the key time blocks are stored under for the day containing t
+func TimeBlockDay(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// SaveTimeBlock inserts a new time block
+func (db *DB) SaveTimeBlock(block *TimeBlock) error {
+	now := time.Now()
+	if block.ID == "" {
+		block.ID = fmt.Sprintf("block_%d", now.UnixNano())
+	}
+	block.CreatedAt = now
+	block.UpdatedAt = now
+
+	query := `
+		INSERT INTO time_blocks (id, day, name, start_ts, end_ts, task_ids, status, conflict,
+		                         calendar_event_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)
+	`
+	_, err := db.Exec(query,
+		block.ID, block.Day, block.Name, block.StartTS.Unix(), block.EndTS.Unix(),
+		strings.Join(block.TaskIDs, ","), block.Status, block.Conflict, block.CalendarEventID,
+		now.Unix(), now.Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save time block: %w", err)
+	}
+	return nil
+}
+
+// UpdateTimeBlock saves a block's times, tasks, status, conflict and calendar event
+func (db *DB) UpdateTimeBlock(block *TimeBlock) error {
+	block.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE time_blocks
+		SET start_ts = ?, end_ts = ?, task_ids = ?, status = ?, conflict = NULLIF(?, ''),
+		    calendar_event_id = NULLIF(?, ''), updated_at = ?
+		WHERE id = ?
+	`
+	result, err := db.Exec(query,
+		block.StartTS.Unix(), block.EndTS.Unix(), strings.Join(block.TaskIDs, ","), block.Status,
+		block.Conflict, block.CalendarEventID, block.UpdatedAt.Unix(), block.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update time block: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("time block not found: %s", block.ID)
+	}
+	return nil
+}
+
+// GetTimeBlocks returns a day's time blocks in start order
+func (db *DB) GetTimeBlocks(day string) ([]*TimeBlock, error) {
+	return db.queryTimeBlocks(`WHERE day = ? ORDER BY start_ts`, day)
+}
+
+// GetTimeBlock returns a single time block
+func (db *DB) GetTimeBlock(id string) (*TimeBlock, error) {
+	blocks, err := db.queryTimeBlocks(`WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("time block not found: %s", id)
+	}
+	return blocks[0], nil
+}
+
+// GetTimeBlockByEvent returns the time block written to the given calendar event, or nil
+func (db *DB) GetTimeBlockByEvent(eventID string) (*TimeBlock, error) {
+	blocks, err := db.queryTimeBlocks(`WHERE calendar_event_id = ?`, eventID)
+	if err != nil || len(blocks) == 0 {
+		return nil, err
+	}
+	return blocks[0], nil
+}
+
+// GetTimeBlocksBetween returns blocks that overlap the given period, leaving out declined ones
+func (db *DB) GetTimeBlocksBetween(start, end time.Time) ([]*TimeBlock, error) {
+	return db.queryTimeBlocks(`WHERE start_ts < ? AND end_ts > ? AND status != ? ORDER BY start_ts`,
+		end.Unix(), start.Unix(), TimeBlockDeclined)
+}
+
+// GetTimeBlockEventIDs returns the calendar events created for time blocks, so they aren't
+// mistaken for meetings
+func (db *DB) GetTimeBlockEventIDs() (map[string]bool, error) {
+	rows, err := db.Query(`SELECT calendar_event_id FROM time_blocks WHERE calendar_event_id IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query time block events: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+	return ids, rows.Err()
+}
+
+func (db *DB) queryTimeBlocks(where string, args ...interface{}) ([]*TimeBlock, error) {
+	query := `
+		SELECT id, day, name, start_ts, end_ts, task_ids, status, conflict,
+		       calendar_event_id, created_at, updated_at
+		FROM time_blocks ` + where
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query time blocks: %w", err)
+	}
+	defer rows.Close()
+
+	var blocks []*TimeBlock
+	for rows.Next() {
+		block := &TimeBlock{}
+		var startTS, endTS, createdAt, updatedAt int64
+		var taskIDs, conflict, eventID sql.NullString
+		err := rows.Scan(&block.ID, &block.Day, &block.Name, &startTS, &endTS, &taskIDs,
+			&block.Status, &conflict, &eventID, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+		block.StartTS = time.Unix(startTS, 0)
+		block.EndTS = time.Unix(endTS, 0)
+		if taskIDs.String != "" {
+			block.TaskIDs = strings.Split(taskIDs.String, ",")
+		}
+		block.Conflict = conflict.String
+		block.CalendarEventID = eventID.String
+		block.CreatedAt = time.Unix(createdAt, 0)
+		block.UpdatedAt = time.Unix(updatedAt, 0)
+		blocks = append(blocks, block)
+	}
+	return blocks, rows.Err()
+}
@@ -0,0 +1,179 @@
+package db
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sources of tracked time
+const (
+	TimeSourceFocus = "focus" // A focus session
+	TimeSourceBlock = "block" // A committed focus block from the daily plan
+	TimeSourceWork  = "work"  // A work session timed against a task
+)
+
+// TimeEntry is time spent on a task, with its project's billing settings
+type TimeEntry struct {
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Duration time.Duration `json:"duration"` // A share of End - Start when the time was split between tasks
+	Source   string        `json:"source"`
+	TaskID   string        `json:"task_id,omitempty"`
+	Task     string        `json:"task"`
+	Project  string        `json:"project,omitempty"`
+	Client   string        `json:"client,omitempty"`
+	Billable bool          `json:"billable"`
+}
+
+// GetTimeEntries returns the time tracked in a period, grouped by client and project. Work
+// sessions count against the task they timed. Focus sessions are split between the tasks
+// completed during them, or failing that the tasks planned for an overlapping focus block.
+// Scheduled focus blocks that have passed count too, unless a focus session already covers them.
+// Focus sessions and blocks that overlap a work session are left out, since it's more precise.
+func (db *DB) GetTimeEntries(start, end time.Time) ([]*TimeEntry, error) {
+	billing, err := db.GetProjectBilling()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	sessions, err := db.GetFocusSessionsBetween(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	work, err := db.GetWorkSessionsBetween(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*TimeEntry
+	for _, session := range work {
+		sessionEnd := session.StartedAt.Add(session.Duration(now))
+		if !sessionEnd.After(session.StartedAt) {
+			continue
+		}
+		task, err := db.GetTaskByID(session.TaskID)
+		if err != nil || task == nil {
+			task = &Task{ID: session.TaskID, Title: session.Task}
+		}
+		entries = append(entries, splitTime(session.StartedAt, sessionEnd, TimeSourceWork, []*Task{task}, billing)...)
+	}
+
+	for _, session := range sessions {
+		sessionEnd := session.End()
+		if sessionEnd.After(now) {
+			sessionEnd = now
+		}
+		if !sessionEnd.After(session.StartedAt) || coveredByWork(session.StartedAt, sessionEnd, work) {
+			continue
+		}
+
+		tasks, err := db.GetTasksCompletedBetween(session.StartedAt, sessionEnd)
+		if err != nil {
+			return nil, err
+		}
+		if len(tasks) == 0 {
+			blocks, err := db.GetTimeBlocksBetween(session.StartedAt, sessionEnd)
+			if err != nil {
+				return nil, err
+			}
+			for _, block := range blocks {
+				tasks = append(tasks, db.blockTasks(block)...)
+			}
+		}
+		entries = append(entries, splitTime(session.StartedAt, sessionEnd, TimeSourceFocus, tasks, billing)...)
+	}
+
+	blocks, err := db.GetTimeBlocksBetween(start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range blocks {
+		planned := block.Status == TimeBlockScheduled || block.Status == TimeBlockAccepted
+		if !planned || block.EndTS.After(now) || block.StartTS.Before(start) {
+			continue
+		}
+		if coveredByFocus(block, sessions) || coveredByWork(block.StartTS, block.EndTS, work) {
+			continue
+		}
+		if tasks := db.blockTasks(block); len(tasks) > 0 {
+			entries = append(entries, splitTime(block.StartTS, block.EndTS, TimeSourceBlock, tasks, billing)...)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if !strings.EqualFold(a.Client, b.Client) {
+			return strings.ToLower(a.Client) < strings.ToLower(b.Client)
+		}
+		if !strings.EqualFold(a.Project, b.Project) {
+			return strings.ToLower(a.Project) < strings.ToLower(b.Project)
+		}
+		return a.Start.Before(b.Start)
+	})
+	return entries, nil
+}
+
+// blockTasks returns the tasks a focus block was planned for that still exist
+func (db *DB) blockTasks(block *TimeBlock) []*Task {
+	var tasks []*Task
+	for _, id := range block.TaskIDs {
+		if task, err := db.GetTaskByID(id); err == nil && task != nil {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// splitTime divides a period evenly between tasks, or records it against no task when there
+// are none
+func splitTime(start, end time.Time, source string, tasks []*Task, billing map[string]*ProjectBilling) []*TimeEntry {
+	if len(tasks) == 0 {
+		return []*TimeEntry{{Start: start, End: end, Duration: end.Sub(start), Source: source, Task: "Focus session"}}
+	}
+
+	share := end.Sub(start) / time.Duration(len(tasks))
+	entries := make([]*TimeEntry, 0, len(tasks))
+	for _, task := range tasks {
+		entry := &TimeEntry{
+			Start:    start,
+			End:      end,
+			Duration: share,
+			Source:   source,
+			TaskID:   task.ID,
+			Task:     task.Title,
+			Project:  strings.TrimSpace(task.Project),
+		}
+		if project, ok := billing[strings.ToLower(entry.Project)]; ok {
+			entry.Client = project.Client
+			entry.Billable = project.Billable
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// coveredByFocus reports whether a focus session overlaps a block, in which case the session
+// already accounts for its time
+func coveredByFocus(block *TimeBlock, sessions []*FocusSession) bool {
+	for _, session := range sessions {
+		if session.StartedAt.Before(block.EndTS) && session.End().After(block.StartTS) {
+			return true
+		}
+	}
+	return false
+}
+
+// coveredByWork reports whether a work session overlaps a period, in which case the session
+// already accounts for its time
+func coveredByWork(start, end time.Time, work []*WorkSession) bool {
+	now := time.Now()
+	for _, session := range work {
+		if session.StartedAt.Before(end) && session.StartedAt.Add(session.Duration(now)).After(start) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Waiting item statuses
+const (
+	WaitingOpen      = "waiting"   // No reply yet
+	WaitingReplied   = "replied"   // Someone replied in the thread
+	WaitingDismissed = "dismissed" // The user stopped waiting
+	WaitingNone      = "none"      // The sent message asked for nothing
+)
+
+// WaitingItem is a request the user sent by email that is waiting on a reply
+type WaitingItem struct {
+	MessageID  string     `json:"message_id"` // The sent message
+	ThreadID   string     `json:"thread_id"`
+	Subject    string     `json:"subject"`
+	Recipient  string     `json:"recipient"`
+	Request    string     `json:"request"` // What was asked for
+	SentAt     time.Time  `json:"sent_at"`
+	ExpectedBy time.Time  `json:"expected_by"` // When a reply was due
+	Status     string     `json:"status"`
+	NudgedAt   *time.Time `json:"nudged_at,omitempty"`
+}
+
+// Overdue reports whether the reply is late
+func (w *WaitingItem) Overdue(now time.Time) bool {
+	return w.Status == WaitingOpen && !now.Before(w.ExpectedBy)
+}
+
+const waitingItemColumns = `message_id, thread_id, COALESCE(subject, ''), COALESCE(recipient, ''),
+	COALESCE(request, ''), sent_ts, COALESCE(expected_by, sent_ts), status, nudged_at`
+
+// GetSentMessagesToCheck returns messages the user sent since a time that haven't been checked
+// for requests yet and haven't had a reply, newest first
+func (db *DB) GetSentMessagesToCheck(userEmail string, since time.Time, limit int) ([]*Message, error) {
+	userEmail = strings.ToLower(strings.TrimSpace(userEmail))
+	if userEmail == "" {
+		return nil, fmt.Errorf("user email is required to find sent mail")
+	}
+
+	query := `
+		SELECT m.id, m.thread_id, COALESCE(m.from_addr, ''), COALESCE(m.to_addr, ''),
+		       COALESCE(m.subject, ''), COALESCE(m.snippet, ''), COALESCE(m.body, ''), m.ts
+		FROM messages m
+		WHERE m.ts >= ?
+		  AND lower(COALESCE(m.from_addr, '')) LIKE ?
+		  AND m.id NOT IN (SELECT message_id FROM waiting_items)
+		  AND NOT EXISTS (
+			SELECT 1 FROM messages r
+			WHERE r.thread_id = m.thread_id AND r.ts > m.ts
+			  AND lower(COALESCE(r.from_addr, '')) NOT LIKE ?
+		  )
+		ORDER BY m.ts DESC
+		LIMIT ?
+	`
+	pattern := "%" + userEmail + "%"
+	rows, err := db.Query(query, since.Unix(), pattern, pattern, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sent messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*Message
+	for rows.Next() {
+		msg := &Message{}
+		var ts int64
+		if err := rows.Scan(&msg.ID, &msg.ThreadID, &msg.From, &msg.To, &msg.Subject, &msg.Snippet,
+			&msg.Body, &ts); err != nil {
+			return nil, err
+		}
+		msg.Timestamp = time.Unix(ts, 0)
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
+
+// SaveWaitingItem records a checked sent message. Messages that asked for nothing are saved
+// with status none so they aren't checked again.
+func (db *DB) SaveWaitingItem(item *WaitingItem) error {
+	query := `
+		INSERT INTO waiting_items (message_id, thread_id, subject, recipient, request, sent_ts,
+		                           expected_by, status, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (message_id) DO NOTHING
+	`
+	_, err := db.Exec(query, item.MessageID, item.ThreadID, item.Subject, item.Recipient, item.Request,
+		item.SentAt.Unix(), item.ExpectedBy.Unix(), item.Status, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to save waiting item: %w", err)
+	}
+	return nil
+}
+
+// ResolveRepliedWaitingItems marks waiting items replied once anyone but the user has written in
+// their thread since the request was sent
+func (db *DB) ResolveRepliedWaitingItems(userEmail string) (int, error) {
+	userEmail = strings.ToLower(strings.TrimSpace(userEmail))
+	if userEmail == "" {
+		return 0, fmt.Errorf("user email is required to tell replies apart")
+	}
+
+	query := `
+		UPDATE waiting_items SET status = ?, resolved_at = ?
+		WHERE status = ?
+		  AND EXISTS (
+			SELECT 1 FROM messages r
+			WHERE r.thread_id = waiting_items.thread_id AND r.ts > waiting_items.sent_ts
+			  AND lower(COALESCE(r.from_addr, '')) NOT LIKE ?
+		  )
+	`
+	result, err := db.Exec(query, WaitingReplied, time.Now().Unix(), WaitingOpen, "%"+userEmail+"%")
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve waiting items: %w", err)
+	}
+	resolved, _ := result.RowsAffected()
+	return int(resolved), nil
+}
+
+// GetWaitingItems returns the requests still waiting on a reply, soonest due first
+func (db *DB) GetWaitingItems() ([]*WaitingItem, error) {
+	query := `
+		SELECT ` + waitingItemColumns + `
+		FROM waiting_items
+		WHERE status = ?
+		ORDER BY COALESCE(expected_by, sent_ts) ASC
+	`
+	return db.queryWaitingItems(query, WaitingOpen)
+}
+
+// GetWaitingItemsToNudge returns overdue requests that haven't been nudged within the interval
+func (db *DB) GetWaitingItemsToNudge(interval time.Duration) ([]*WaitingItem, error) {
+	now := time.Now()
+	query := `
+		SELECT ` + waitingItemColumns + `
+		FROM waiting_items
+		WHERE status = ?
+		  AND COALESCE(expected_by, sent_ts) <= ?
+		  AND (nudged_at IS NULL OR nudged_at <= ?)
+		ORDER BY COALESCE(expected_by, sent_ts) ASC
+	`
+	return db.queryWaitingItems(query, WaitingOpen, now.Unix(), now.Add(-interval).Unix())
+}
+
+// MarkWaitingItemNudged records that the user was reminded about a waiting item
+func (db *DB) MarkWaitingItemNudged(messageID string) error {
+	_, err := db.Exec(`UPDATE waiting_items SET nudged_at = ? WHERE message_id = ?`, time.Now().Unix(), messageID)
+	if err != nil {
+		return fmt.Errorf("failed to mark waiting item nudged: %w", err)
+	}
+	return nil
+}
+
+// DismissWaitingItem stops waiting on a request
+func (db *DB) DismissWaitingItem(messageID string) error {
+	result, err := db.Exec(`UPDATE waiting_items SET status = ?, resolved_at = ? WHERE message_id = ? AND status = ?`,
+		WaitingDismissed, time.Now().Unix(), messageID, WaitingOpen)
+	if err != nil {
+		return fmt.Errorf("failed to dismiss waiting item: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("waiting item not found: %s", messageID)
+	}
+	return nil
+}
+
+func (db *DB) queryWaitingItems(query string, args ...interface{}) ([]*WaitingItem, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query waiting items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*WaitingItem
+	for rows.Next() {
+		item := &WaitingItem{}
+		var sentTS, expectedBy int64
+		var nudgedAt sql.NullInt64
+		if err := rows.Scan(&item.MessageID, &item.ThreadID, &item.Subject, &item.Recipient, &item.Request,
+			&sentTS, &expectedBy, &item.Status, &nudgedAt); err != nil {
+			return nil, err
+		}
+		item.SentAt = time.Unix(sentTS, 0)
+		item.ExpectedBy = time.Unix(expectedBy, 0)
+		if nudgedAt.Valid {
+			t := time.Unix(nudgedAt.Int64, 0)
+			item.NudgedAt = &t
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
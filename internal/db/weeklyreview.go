@@ -0,0 +1,84 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PlanKindWeeklyReview is the plan history kind of the weekly review
+const PlanKindWeeklyReview = "weekly_review"
+
+// WeeklyReview looks back over the past week's work and misses
+type WeeklyReview struct {
+	Since         time.Time         `json:"since"`
+	Until         time.Time         `json:"until"`
+	Completed     []*Task           `json:"completed"`      // Completed during the week
+	Slipped       []*Task           `json:"slipped"`        // Still open, due during the week
+	AwaitingReply []*AwaitingThread `json:"awaiting_reply"` // Inbox threads whose latest message isn't from the user
+	MeetingCount  int               `json:"meeting_count"`
+	MeetingHours  float64           `json:"meeting_hours"`
+	Narrative     string            `json:"narrative"` // LLM-written summary of the week
+
+	// Projects with no activity for planner.stale_project_weeks, in the last review of the month
+	StaleProjects []*StaleProject `json:"stale_projects,omitempty"`
+
+	// Tasks archived during the week for going untouched past planner.auto_archive_days
+	AutoArchived []*SourceCount `json:"auto_archived,omitempty"`
+}
+
+// AwaitingThread is an email thread waiting on the user's reply, described by its latest message
+type AwaitingThread struct {
+	ThreadID   string    `json:"thread_id"`
+	Subject    string    `json:"subject"`
+	From       string    `json:"from"`
+	Summary    string    `json:"summary"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// GetThreadsAwaitingReply returns unarchived inbox threads whose latest message arrived in a time
+// range from someone other than the user, oldest first
+func (db *DB) GetThreadsAwaitingReply(userEmail string, start, end time.Time, limit int) ([]*AwaitingThread, error) {
+	userEmail = strings.ToLower(strings.TrimSpace(userEmail))
+	if userEmail == "" {
+		return nil, fmt.Errorf("user email is required to tell replies apart")
+	}
+
+	query := `
+		WITH latest AS (
+			SELECT thread_id, subject, snippet, from_addr, ts,
+			       ROW_NUMBER() OVER (PARTITION BY thread_id ORDER BY ts DESC) AS rn
+			FROM messages
+		)
+		SELECT l.thread_id, COALESCE(l.subject, ''), COALESCE(l.from_addr, ''), l.ts,
+		       COALESCE(NULLIF(t.summary, ''), l.snippet, '')
+		FROM latest l
+		INNER JOIN threads t ON t.id = l.thread_id
+		WHERE l.rn = 1
+		  AND l.ts >= ? AND l.ts < ?
+		  AND t.relevant_to_user = true
+		  AND lower(COALESCE(l.from_addr, '')) NOT LIKE ?
+		  AND l.thread_id NOT IN (SELECT thread_id FROM archived_threads)
+		ORDER BY l.ts ASC
+		LIMIT ?
+	`
+
+	rows, err := db.Query(query, start.Unix(), end.Unix(), "%"+userEmail+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query threads awaiting reply: %w", err)
+	}
+	defer rows.Close()
+
+	var threads []*AwaitingThread
+	for rows.Next() {
+		thread := &AwaitingThread{}
+		var ts int64
+		if err := rows.Scan(&thread.ThreadID, &thread.Subject, &thread.From, &ts, &thread.Summary); err != nil {
+			return nil, err
+		}
+		thread.ReceivedAt = time.Unix(ts, 0)
+		threads = append(threads, thread)
+	}
+
+	return threads, rows.Err()
+}
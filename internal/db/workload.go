@@ -0,0 +1,156 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// workloadWindow is how far back answered requests count toward a person's turnaround
+const workloadWindow = 90 * 24 * time.Hour
+
+// Workload is how loaded a person already is with things the user is waiting on them for, and
+// how quickly they've turned requests around, to help pick who to delegate to
+type Workload struct {
+	Email          string `json:"email"`
+	Name           string `json:"name,omitempty"`
+	Delegated      int    `json:"delegated"`                 // Open requests and handoffs waiting on them
+	Overdue        int    `json:"overdue"`                   // Of those, the ones past when a reply was due
+	Promised       int    `json:"promised"`                  // Open commitments the user made to them
+	TasksOwed      int    `json:"tasks_owed"`                // Open tasks from threads they wrote in
+	Completed      int    `json:"completed"`                 // Requests they answered in the window
+	TurnaroundSecs int64  `json:"turnaround_secs,omitempty"` // How long they took to answer, on average
+}
+
+// Open is everything open between the user and the person, both ways
+func (w *Workload) Open() int {
+	return w.Delegated + w.Promised + w.TasksOwed
+}
+
+// GetWorkloads returns the open items to and from everyone the user is waiting on or has made
+// promises to, with how long each took to answer requests over the last 90 days, busiest first
+func (db *DB) GetWorkloads() ([]*Workload, error) {
+	now := time.Now().Unix()
+	workloads := make(map[string]*Workload)
+	workload := func(recipient string) *Workload {
+		addresses := parseAddresses(recipient)
+		if len(addresses) == 0 {
+			return nil
+		}
+		email := strings.ToLower(addresses[0].Address)
+		w, ok := workloads[email]
+		if !ok {
+			w = &Workload{Email: email, Name: addresses[0].Name}
+			workloads[email] = w
+		}
+		return w
+	}
+
+	// Requests: open ones are their load, answered ones their turnaround
+	rows, err := db.Query(`
+		SELECT COALESCE(recipient, ''), status, sent_ts, COALESCE(expected_by, sent_ts), resolved_at
+		FROM waiting_items
+		WHERE status = ? OR (status = ? AND sent_ts >= ?)
+	`, WaitingOpen, WaitingReplied, time.Now().Add(-workloadWindow).Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query waiting items: %w", err)
+	}
+	turnarounds := make(map[string][]int64)
+	for rows.Next() {
+		var recipient, status string
+		var sentTS, expectedBy int64
+		var resolvedAt sql.NullInt64
+		if err := rows.Scan(&recipient, &status, &sentTS, &expectedBy, &resolvedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		w := workload(recipient)
+		if w == nil {
+			continue
+		}
+		switch status {
+		case WaitingOpen:
+			w.Delegated++
+			if now >= expectedBy {
+				w.Overdue++
+			}
+		case WaitingReplied:
+			w.Completed++
+			if resolvedAt.Valid && resolvedAt.Int64 >= sentTS {
+				turnarounds[w.Email] = append(turnarounds[w.Email], resolvedAt.Int64-sentTS)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	commitments, err := db.GetOpenCommitments(0)
+	if err != nil {
+		return nil, err
+	}
+	for _, commitment := range commitments {
+		if w := workload(commitment.Recipient); w != nil {
+			w.Promised++
+		}
+	}
+
+	result := make([]*Workload, 0, len(workloads))
+	for email, w := range workloads {
+		if secs, ok := averageSecs(turnarounds[email]).(int64); ok {
+			w.TurnaroundSecs = secs
+		}
+		if person, err := db.GetPerson(email); err == nil && person != nil {
+			w.TasksOwed = person.TasksOwed
+			if person.Name != "" {
+				w.Name = person.Name
+			}
+		}
+		result = append(result, w)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Open() != result[j].Open() {
+			return result[i].Open() > result[j].Open()
+		}
+		return result[i].Email < result[j].Email
+	})
+	return result, nil
+}
+
+// MaxDelegateSuggestions caps the people suggested to delegate to in the TUI and the API
+const MaxDelegateSuggestions = 3
+
+// SuggestDelegates picks who to delegate to next: people who have answered requests before,
+// least loaded first, then those with nothing overdue, then the quickest. Nobody is suggested
+// from a list with no answered requests.
+func SuggestDelegates(workloads []*Workload, limit int) []*Workload {
+	var candidates []*Workload
+	for _, w := range workloads {
+		if w.Completed > 0 {
+			candidates = append(candidates, w)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Delegated != b.Delegated {
+			return a.Delegated < b.Delegated
+		}
+		if a.Overdue != b.Overdue {
+			return a.Overdue < b.Overdue
+		}
+		if (a.TurnaroundSecs == 0) != (b.TurnaroundSecs == 0) {
+			return a.TurnaroundSecs != 0
+		}
+		return a.TurnaroundSecs < b.TurnaroundSecs
+	})
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
@@ -0,0 +1,270 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// EffortWeeks is how many weeks of tracked time the effort stats cover
+const EffortWeeks = 4
+
+// WorkSession is a stretch of time spent working on a task, timed from the TUI or API
+type WorkSession struct {
+	ID            string     `json:"id"`
+	TaskID        string     `json:"task_id"`
+	Task          string     `json:"task"` // The task's title
+	StartedAt     time.Time  `json:"started_at"`
+	EndedAt       *time.Time `json:"ended_at,omitempty"` // Nil while the timer is running
+	Interruptions int        `json:"interruptions"`
+}
+
+// Duration returns how long the session ran, up to now if it's still running
+func (s *WorkSession) Duration(now time.Time) time.Duration {
+	end := now
+	if s.EndedAt != nil {
+		end = *s.EndedAt
+	}
+	return max(end.Sub(s.StartedAt), 0)
+}
+
+// TaskEffort compares the time tracked on a task with what its effort size estimates
+type TaskEffort struct {
+	TaskID        string        `json:"task_id"`
+	Sessions      int           `json:"sessions"`
+	Interruptions int           `json:"interruptions"`
+	Actual        time.Duration `json:"actual"`
+	Estimate      time.Duration `json:"estimate"`
+}
+
+// WeekEffort is the time tracked in a week, and for the tasks completed that week with tracked
+// time, how the time they took compares with their estimates
+type WeekEffort struct {
+	Start         time.Time     `json:"start"` // Monday at midnight
+	Tracked       time.Duration `json:"tracked"`
+	Sessions      int           `json:"sessions"`
+	Interruptions int           `json:"interruptions"`
+	Completed     int           `json:"completed"`
+	Estimate      time.Duration `json:"estimate"`
+	Actual        time.Duration `json:"actual"`
+}
+
+// EffortEstimate returns the time an effort size (S, M or L) stands for: the middle of its
+// range, with S under 2 hours, M 2-4 hours and L more than 4
+func EffortEstimate(effort string) time.Duration {
+	switch effort {
+	case "S":
+		return time.Hour
+	case "L":
+		return 6 * time.Hour
+	default:
+		return 3 * time.Hour
+	}
+}
+
+// StartWorkSession starts timing work on a task, stopping the session running on any other
+func (db *DB) StartWorkSession(taskID string) (*WorkSession, error) {
+	if _, err := db.StopWorkSession(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &WorkSession{
+		ID:        fmt.Sprintf("work_%d", now.UnixNano()),
+		TaskID:    taskID,
+		StartedAt: now,
+	}
+	query := `INSERT INTO work_sessions (id, task_id, started_at) VALUES (?, ?, ?)`
+	if _, err := db.Exec(query, session.ID, session.TaskID, session.StartedAt.Unix()); err != nil {
+		return nil, fmt.Errorf("failed to start work session: %w", err)
+	}
+
+	if task, err := db.GetTaskByID(taskID); err == nil && task != nil {
+		session.Task = task.Title
+	}
+	return session, nil
+}
+
+// GetActiveWorkSession returns the running work session, or nil if no task is being timed
+func (db *DB) GetActiveWorkSession() (*WorkSession, error) {
+	query := `
+		SELECT s.id, s.task_id, COALESCE(t.title, ''), s.started_at, s.ended_at, s.interruptions
+		FROM work_sessions s
+		LEFT JOIN tasks t ON t.id = s.task_id
+		WHERE s.ended_at IS NULL
+		ORDER BY s.started_at DESC
+		LIMIT 1
+	`
+	session, err := scanWorkSession(db.QueryRow(query))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work session: %w", err)
+	}
+	return session, nil
+}
+
+// StopWorkSession stops the running work session and returns it, or nil if none was running
+func (db *DB) StopWorkSession() (*WorkSession, error) {
+	session, err := db.GetActiveWorkSession()
+	if err != nil || session == nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err := db.Exec(`UPDATE work_sessions SET ended_at = ? WHERE id = ?`, now.Unix(), session.ID); err != nil {
+		return nil, fmt.Errorf("failed to stop work session: %w", err)
+	}
+	session.EndedAt = &now
+	return session, nil
+}
+
+// AddWorkInterruption counts an interruption against the running work session and returns it,
+// or nil if none is running
+func (db *DB) AddWorkInterruption() (*WorkSession, error) {
+	session, err := db.GetActiveWorkSession()
+	if err != nil || session == nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(`UPDATE work_sessions SET interruptions = interruptions + 1 WHERE id = ?`, session.ID); err != nil {
+		return nil, fmt.Errorf("failed to record interruption: %w", err)
+	}
+	session.Interruptions++
+	return session, nil
+}
+
+// GetWorkSessionsBetween returns the work sessions that started in a period, oldest first
+func (db *DB) GetWorkSessionsBetween(start, end time.Time) ([]*WorkSession, error) {
+	query := `
+		SELECT s.id, s.task_id, COALESCE(t.title, ''), s.started_at, s.ended_at, s.interruptions
+		FROM work_sessions s
+		LEFT JOIN tasks t ON t.id = s.task_id
+		WHERE s.started_at >= ? AND s.started_at < ?
+		ORDER BY s.started_at ASC
+	`
+	rows, err := db.Query(query, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query work sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*WorkSession
+	for rows.Next() {
+		session, err := scanWorkSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// GetTaskEfforts returns the time tracked on each task that has any, by task ID
+func (db *DB) GetTaskEfforts() (map[string]*TaskEffort, error) {
+	query := `
+		SELECT s.task_id, COALESCE(t.effort, ''), COUNT(*), SUM(s.interruptions),
+		       SUM(COALESCE(s.ended_at, ?) - s.started_at)
+		FROM work_sessions s
+		LEFT JOIN tasks t ON t.id = s.task_id
+		GROUP BY s.task_id, t.effort
+	`
+	rows, err := db.Query(query, time.Now().Unix())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task efforts: %w", err)
+	}
+	defer rows.Close()
+
+	efforts := make(map[string]*TaskEffort)
+	for rows.Next() {
+		effort := &TaskEffort{}
+		var size string
+		var seconds int64
+		if err := rows.Scan(&effort.TaskID, &size, &effort.Sessions, &effort.Interruptions, &seconds); err != nil {
+			return nil, err
+		}
+		effort.Actual = time.Duration(max(seconds, 0)) * time.Second
+		effort.Estimate = EffortEstimate(size)
+		efforts[effort.TaskID] = effort
+	}
+	return efforts, rows.Err()
+}
+
+// GetWeeklyEfforts returns the time tracked in each of the last weeks, oldest first, ending with
+// the current week
+func (db *DB) GetWeeklyEfforts(weeks int) ([]*WeekEffort, error) {
+	now := time.Now()
+	thisWeek := StartOfWeek(now)
+	start := thisWeek.AddDate(0, 0, -7*(weeks-1))
+
+	result := make([]*WeekEffort, weeks)
+	for i := range result {
+		result[i] = &WeekEffort{Start: start.AddDate(0, 0, 7*i)}
+	}
+	week := func(t time.Time) *WeekEffort {
+		for i := len(result) - 1; i >= 0; i-- {
+			if !t.Before(result[i].Start) {
+				return result[i]
+			}
+		}
+		return nil
+	}
+
+	sessions, err := db.GetWorkSessionsBetween(start, now.Add(time.Second))
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		if w := week(session.StartedAt); w != nil {
+			w.Tracked += session.Duration(now)
+			w.Sessions++
+			w.Interruptions += session.Interruptions
+		}
+	}
+
+	completed, err := db.GetTasksCompletedBetween(start, now.Add(time.Second))
+	if err != nil {
+		return nil, err
+	}
+	efforts, err := db.GetTaskEfforts()
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range completed {
+		effort, ok := efforts[task.ID]
+		if !ok || task.CompletedAt == nil {
+			continue
+		}
+		if w := week(*task.CompletedAt); w != nil {
+			w.Completed++
+			w.Estimate += effort.Estimate
+			w.Actual += effort.Actual
+		}
+	}
+	return result, nil
+}
+
+// StartOfWeek returns midnight on the Monday of t's week, in t's location
+func StartOfWeek(t time.Time) time.Time {
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, t.Location())
+}
+
+// scanWorkSession scans a work session row
+func scanWorkSession(row interface{ Scan(...interface{}) error }) (*WorkSession, error) {
+	session := &WorkSession{}
+	var startedAt int64
+	var endedAt sql.NullInt64
+
+	if err := row.Scan(&session.ID, &session.TaskID, &session.Task, &startedAt, &endedAt, &session.Interruptions); err != nil {
+		return nil, err
+	}
+
+	session.StartedAt = time.Unix(startedAt, 0)
+	if endedAt.Valid {
+		t := time.Unix(endedAt.Int64, 0)
+		session.EndedAt = &t
+	}
+	return session, nil
+}
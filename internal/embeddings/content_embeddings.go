@@ -0,0 +1,44 @@
+package embeddings
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// maxContentEmbeddingChars keeps embedded text within the embedding model's context window
+const maxContentEmbeddingChars = 6000
+
+// BuildContentEmbeddingText creates the text to embed for a thread summary or Drive document
+func BuildContentEmbeddingText(item *db.ContentToEmbed) string {
+	var parts []string
+	switch item.Kind {
+	case db.ContentKindThread:
+		if item.Title != "" {
+			parts = append(parts, fmt.Sprintf("Email: %s", item.Title))
+		}
+		parts = append(parts, fmt.Sprintf("Summary: %s", item.Text))
+	case db.ContentKindDocument:
+		parts = append(parts, fmt.Sprintf("Document: %s", item.Title))
+		if item.Text != "" {
+			parts = append(parts, item.Text)
+		}
+	}
+
+	text := []rune(strings.Join(parts, "\n"))
+	if len(text) > maxContentEmbeddingChars {
+		text = text[:maxContentEmbeddingChars]
+	}
+	return string(text)
+}
+
+// EmbedContent generates and stores the embedding of a thread summary or Drive document
+func EmbedContent(ctx context.Context, client *Client, database *db.DB, item *db.ContentToEmbed) error {
+	embedding, err := client.GenerateWithRetry(ctx, BuildContentEmbeddingText(item), 3)
+	if err != nil {
+		return fmt.Errorf("failed to generate embedding: %w", err)
+	}
+	return database.SaveContentEmbedding(item, embedding, client.model)
+}
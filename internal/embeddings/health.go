@@ -0,0 +1,99 @@
+package embeddings
+
+import (
+	"fmt"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// IndexHealth describes how well the task embedding index covers the tasks table.
+// Semantic features (dedup, KNN scoring) quietly get worse as these numbers drift.
+type IndexHealth struct {
+	PendingTasks      int  // Pending tasks that should have embeddings
+	EmbeddedTasks     int  // Rows in task_embeddings
+	MissingEmbeddings int  // Pending tasks with no embedding
+	StaleEmbeddings   int  // Pending tasks whose content changed since they were embedded
+	OrphanEmbeddings  int  // Embeddings whose task no longer exists
+	HNSWIndex         bool // Whether the HNSW index on task_embeddings exists
+	FTSIndex          bool // Whether the messages full-text index exists
+}
+
+// CheckIndexHealth inspects task embeddings and search indexes for gaps
+func CheckIndexHealth(database *db.DB) (*IndexHealth, error) {
+	health := &IndexHealth{}
+
+	counts := []struct {
+		target *int
+		query  string
+	}{
+		{&health.PendingTasks, `SELECT COUNT(*) FROM tasks WHERE status = 'pending'`},
+		{&health.EmbeddedTasks, `SELECT COUNT(*) FROM task_embeddings`},
+		{&health.MissingEmbeddings, `
+			SELECT COUNT(*) FROM tasks t
+			LEFT JOIN task_embeddings te ON te.task_id = t.id
+			WHERE t.status = 'pending' AND te.task_id IS NULL`},
+		{&health.OrphanEmbeddings, `
+			SELECT COUNT(*) FROM task_embeddings te
+			LEFT JOIN tasks t ON t.id = te.task_id
+			WHERE t.id IS NULL`},
+	}
+	for _, c := range counts {
+		if err := database.QueryRow(c.query).Scan(c.target); err != nil {
+			return nil, fmt.Errorf("failed to count index rows: %w", err)
+		}
+	}
+
+	// Stale embeddings: rebuild the embedding content and compare with what was embedded
+	rows, err := database.Query(`
+		SELECT t.title, t.description, t.source, t.project, t.matched_priorities, te.embedding_content
+		FROM tasks t
+		INNER JOIN task_embeddings te ON te.task_id = t.id
+		WHERE t.status = 'pending'
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedded tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		task := &db.Task{}
+		var description, project, matched, embedded *string
+		if err := rows.Scan(&task.Title, &description, &task.Source, &project, &matched, &embedded); err != nil {
+			return nil, err
+		}
+		if description != nil {
+			task.Description = *description
+		}
+		if project != nil {
+			task.Project = *project
+		}
+		if matched != nil {
+			task.MatchedPriorities = *matched
+		}
+		if embedded == nil || BuildEmbeddingContent(task) != *embedded {
+			health.StaleEmbeddings++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var hnswCount int
+	if err := database.QueryRow(`SELECT COUNT(*) FROM duckdb_indexes() WHERE index_name = 'idx_task_embeddings_hnsw'`).Scan(&hnswCount); err != nil {
+		return nil, fmt.Errorf("failed to check HNSW index: %w", err)
+	}
+	health.HNSWIndex = hnswCount > 0
+
+	var ftsCount int
+	if err := database.QueryRow(`SELECT COUNT(*) FROM information_schema.tables WHERE table_name = 'messages_fts'`).Scan(&ftsCount); err != nil {
+		return nil, fmt.Errorf("failed to check FTS index: %w", err)
+	}
+	health.FTSIndex = ftsCount > 0
+
+	return health, nil
+}
+
+// Healthy reports whether every pending task has an up-to-date embedding and the HNSW index exists
+func (h *IndexHealth) Healthy() bool {
+	return h.HNSWIndex && h.MissingEmbeddings == 0 && h.StaleEmbeddings == 0
+}
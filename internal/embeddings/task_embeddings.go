@@ -3,6 +3,7 @@ package embeddings
 import (
 	"context"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -210,3 +211,73 @@ func HasTaskEmbedding(database *db.DB, taskID string) (bool, error) {
 	}
 	return count > 0, nil
 }
+
+// FindMostSimilarPendingTask returns the pending task (other than excludeID) created since the
+// given time whose embedding is closest to the given one, with its cosine similarity.
+// Returns an empty task ID if there are no candidates.
+func FindMostSimilarPendingTask(database *db.DB, embedding []float64, excludeID string, since time.Time) (string, float64, error) {
+	embeddingJSON, err := json.Marshal(embedding)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to marshal embedding: %w", err)
+	}
+
+	query := `
+		SELECT te.task_id, array_cosine_similarity(te.embedding, ?::FLOAT[768]) AS similarity
+		FROM task_embeddings te
+		INNER JOIN tasks t ON t.id = te.task_id
+		WHERE t.status = 'pending'
+		  AND t.id != ?
+		  AND t.created_at >= ?
+		ORDER BY similarity DESC
+		LIMIT 1
+	`
+
+	var taskID string
+	var similarity float64
+	start := time.Now()
+	err = database.QueryRow(query, string(embeddingJSON), excludeID, since.Unix()).Scan(&taskID, &similarity)
+	if err == sql.ErrNoRows {
+		database.ObserveQuery(db.QueryKindSimilarity, start, nil)
+		return "", 0, nil
+	}
+	database.ObserveQuery(db.QueryKindSimilarity, start, err)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to search similar tasks: %w", err)
+	}
+
+	return taskID, similarity, nil
+}
+
+// FindSimilarTasks returns the IDs of the tasks (any status) whose embeddings are closest to
+// the given one, most similar first
+func FindSimilarTasks(database *db.DB, embedding []float64, limit int) ([]string, error) {
+	embeddingJSON, err := json.Marshal(embedding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embedding: %w", err)
+	}
+
+	query := `
+		SELECT task_id
+		FROM task_embeddings
+		ORDER BY array_cosine_similarity(embedding, ?::FLOAT[768]) DESC
+		LIMIT ?
+	`
+
+	start := time.Now()
+	rows, err := database.Query(query, string(embeddingJSON), limit)
+	database.ObserveQuery(db.QueryKindSimilarity, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search similar tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var taskIDs []string
+	for rows.Next() {
+		var taskID string
+		if err := rows.Scan(&taskID); err != nil {
+			return nil, err
+		}
+		taskIDs = append(taskIDs, taskID)
+	}
+	return taskIDs, rows.Err()
+}
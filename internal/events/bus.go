@@ -0,0 +1,103 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types pushed to /api/events subscribers
+const (
+	SyncStarted     = "sync.started"
+	SyncCompleted   = "sync.completed"
+	SyncFailed      = "sync.failed"
+	ThreadProcessed = "thread.processed"
+	TaskCreated     = "task.created"
+	BriefSent       = "brief.sent"
+	QuotaExhausted  = "quota.exhausted"
+	TaskOpen        = "task.open"      // Asks a connected TUI to show a task, e.g. from a Chat button
+	TaskDelegated   = "task.delegated" // The delegate claimed, commented on or completed a task
+	Alert           = "alert"          // An urgent alert routed to the TUI because the user is there
+)
+
+// replaySize is how many recent events are kept for clients reconnecting with Last-Event-ID
+const replaySize = 100
+
+// Event is a single notification about something the agent did
+type Event struct {
+	ID   int64                  `json:"id"`
+	Type string                 `json:"type"`
+	Time time.Time              `json:"time"`
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// Bus fans events out to subscribers. A nil *Bus is valid and drops everything,
+// so components can publish without checking whether anyone is listening.
+type Bus struct {
+	mu          sync.Mutex
+	nextID      int64
+	recent      []Event
+	subscribers map[chan Event]struct{}
+}
+
+// NewBus creates an event bus
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// Publish sends an event to all subscribers. Subscribers that have fallen behind miss the event
+// rather than blocking the publisher.
+func (b *Bus) Publish(eventType string, data map[string]interface{}) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	event := Event{ID: b.nextID, Type: eventType, Time: time.Now(), Data: data}
+
+	b.recent = append(b.recent, event)
+	if len(b.recent) > replaySize {
+		b.recent = b.recent[len(b.recent)-replaySize:]
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe registers a new subscriber. Events after lastID that are still buffered are returned
+// as a backlog to send first. The returned function unsubscribes and closes the channel.
+func (b *Bus) Subscribe(lastID int64) (<-chan Event, []Event, func()) {
+	ch := make(chan Event, 64)
+
+	b.mu.Lock()
+	var backlog []Event
+	if lastID > 0 {
+		for _, event := range b.recent {
+			if event.ID > lastID {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, backlog, unsubscribe
+}
@@ -98,6 +98,119 @@ func (c *Client) Snooze(ctx context.Context, convID string, until time.Time) err
 	return c.updateConversation(ctx, url, payload)
 }
 
+// Assign assigns a conversation to a teammate, or unassigns it when teammateID is empty
+func (c *Client) Assign(ctx context.Context, convID string, teammateID string) error {
+	url := fmt.Sprintf("%s/conversations/%s/assignee", baseURL, convID)
+
+	payload := map[string]interface{}{
+		"assignee_id": nil,
+	}
+	if teammateID != "" {
+		payload["assignee_id"] = teammateID
+	}
+
+	bodyBytes, _ := json.Marshal(payload)
+
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiToken))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Front API error: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// AddComment posts an internal comment on a conversation as the API token's owner
+func (c *Client) AddComment(ctx context.Context, convID string, body string) (*Comment, error) {
+	url := fmt.Sprintf("%s/conversations/%s/comments", baseURL, convID)
+
+	payload := map[string]interface{}{
+		"body": body,
+	}
+
+	bodyBytes, _ := json.Marshal(payload)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiToken))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Front API error: %d", resp.StatusCode)
+	}
+
+	// posted_at comes back with fractional seconds
+	var result struct {
+		ID       string  `json:"id"`
+		Author   Author  `json:"author"`
+		Body     string  `json:"body"`
+		PostedAt float64 `json:"posted_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return &Comment{
+		ID:        result.ID,
+		Author:    result.Author,
+		Body:      result.Body,
+		CreatedAt: int64(result.PostedAt),
+	}, nil
+}
+
+// GetTeammates retrieves the teammates conversations can be assigned to
+func (c *Client) GetTeammates(ctx context.Context) ([]Teammate, error) {
+	url := fmt.Sprintf("%s/teammates", baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiToken))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Front API error: %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Results []Teammate `json:"_results"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result.Results, nil
+}
+
 // GetTags retrieves all available tags
 func (c *Client) GetTags(ctx context.Context) ([]Tag, error) {
 	url := fmt.Sprintf("%s/tags", baseURL)
@@ -58,6 +58,23 @@ type Comment struct {
 	CreatedAt int64     `json:"posted_at"`
 }
 
+// Teammate represents a Front user conversations can be assigned to
+type Teammate struct {
+	ID        string `json:"id"`
+	Email     string `json:"email"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// GetFullName returns the full name of a teammate
+func (t *Teammate) GetFullName() string {
+	if t.LastName != "" {
+		return fmt.Sprintf("%s %s", t.FirstName, t.LastName)
+	}
+	return t.FirstName
+}
+
 // Author represents a comment author
 type Author struct {
 	ID        string `json:"id"`
@@ -0,0 +1,96 @@
+package front
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// WebhookMaxAge is how far a webhook's timestamp may be from now before it's rejected as a replay
+const WebhookMaxAge = 5 * time.Minute
+
+// WebhookEvent is a Front app webhook delivery: an event on a conversation
+type WebhookEvent struct {
+	Type    string `json:"type"`
+	Payload Event  `json:"payload"`
+}
+
+// Event is something that happened to a conversation, such as an assignment or a new comment
+type Event struct {
+	ID           string             `json:"id"`
+	Type         string             `json:"type"` // assign, unassign, comment, archive, reopen, tag, untag, ...
+	EmittedAt    float64            `json:"emitted_at"`
+	Conversation *EventConversation `json:"conversation"`
+	Target       *struct {
+		Meta struct {
+			Type string `json:"type"` // teammate, comment, tag, ...
+		} `json:"_meta"`
+		Data json.RawMessage `json:"data"`
+	} `json:"target"`
+}
+
+// EventConversation is the state of the conversation after an event, with just the fields kept
+// in front_metadata
+type EventConversation struct {
+	ID       string    `json:"id"`
+	Status   string    `json:"status"`
+	Assignee *Assignee `json:"assignee"`
+	Tags     []Tag     `json:"tags"`
+}
+
+// Comment returns the comment an event added, or nil if it isn't a comment event
+func (e *Event) Comment() *Comment {
+	if e.Target == nil || e.Target.Meta.Type != "comment" {
+		return nil
+	}
+
+	var result struct {
+		ID       string  `json:"id"`
+		Author   Author  `json:"author"`
+		Body     string  `json:"body"`
+		PostedAt float64 `json:"posted_at"`
+	}
+	if err := json.Unmarshal(e.Target.Data, &result); err != nil || result.ID == "" {
+		return nil
+	}
+	return &Comment{
+		ID:        result.ID,
+		Author:    result.Author,
+		Body:      result.Body,
+		CreatedAt: int64(result.PostedAt),
+	}
+}
+
+// VerifyWebhook checks a webhook's X-Front-Signature, an HMAC-SHA256 of its
+// X-Front-Request-Timestamp and body keyed with the app's signing secret, and that the timestamp
+// (in milliseconds) is within WebhookMaxAge of now
+func VerifyWebhook(secret, timestamp, signature string, body []byte, now time.Time) error {
+	if secret == "" {
+		return fmt.Errorf("no webhook secret configured")
+	}
+	if timestamp == "" || signature == "" {
+		return fmt.Errorf("missing signature headers")
+	}
+
+	ms, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	age := now.Sub(time.UnixMilli(ms))
+	if age > WebhookMaxAge || age < -WebhookMaxAge {
+		return fmt.Errorf("timestamp is %s off, outside the %s window", age.Round(time.Second), WebhookMaxAge)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + ":"))
+	mac.Write(body)
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
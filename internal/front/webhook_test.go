@@ -0,0 +1,47 @@
+package front
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestVerifyWebhook verifies signed, fresh deliveries pass and tampered or stale ones don't
+func TestVerifyWebhook(t *testing.T) {
+	now := time.Unix(1760000000, 0)
+	body := []byte(`{"type":"assign","payload":{"id":"evt_1"}}`)
+	sign := func(secret, timestamp string, body []byte) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(timestamp + ":"))
+		mac.Write(body)
+		return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+	fresh := strconv.FormatInt(now.UnixMilli(), 10)
+	stale := strconv.FormatInt(now.Add(-10*time.Minute).UnixMilli(), 10)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		wantErr   bool
+	}{
+		{"valid", "s3cret", fresh, sign("s3cret", fresh, body), body, false},
+		{"wrong secret", "s3cret", fresh, sign("other", fresh, body), body, true},
+		{"tampered body", "s3cret", fresh, sign("s3cret", fresh, body), []byte(`{"type":"archive"}`), true},
+		{"stale timestamp", "s3cret", stale, sign("s3cret", stale, body), body, true},
+		{"missing signature", "s3cret", fresh, "", body, true},
+		{"no secret", "", fresh, sign("", fresh, body), body, true},
+	}
+
+	for _, tt := range tests {
+		err := VerifyWebhook(tt.secret, tt.timestamp, tt.signature, tt.body, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: VerifyWebhook() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package google
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/api/gmail/v1"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// maxAttachmentText caps the text kept for each attachment
+const maxAttachmentText = 20000
+
+// Attachment MIME types text is extracted from
+const (
+	mimePDF  = "application/pdf"
+	mimeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// googleDocLinkPattern matches links to Google Docs, Sheets and Slides
+var googleDocLinkPattern = regexp.MustCompile(`https://docs\.google\.com/(?:document|spreadsheets|presentation)/d/([A-Za-z0-9_-]+)`)
+
+// attachmentMimeType returns the MIME type text can be extracted as, going by the file extension
+// when Gmail only knows it as a generic binary, or "" for unsupported files
+func attachmentMimeType(part *gmail.MessagePart) string {
+	mimeType := part.MimeType
+	if mimeType == "application/octet-stream" {
+		switch strings.ToLower(filepath.Ext(part.Filename)) {
+		case ".pdf":
+			mimeType = mimePDF
+		case ".docx":
+			mimeType = mimeDOCX
+		case ".txt", ".md", ".csv":
+			mimeType = "text/plain"
+		}
+	}
+
+	switch {
+	case mimeType == mimePDF, mimeType == mimeDOCX:
+		return mimeType
+	case strings.HasPrefix(mimeType, "text/") && mimeType != "text/html":
+		return mimeType
+	}
+	return ""
+}
+
+// attachmentParts returns the parts of a message that are attached files, at any depth
+func attachmentParts(payload *gmail.MessagePart) []*gmail.MessagePart {
+	var parts []*gmail.MessagePart
+	for _, part := range payload.Parts {
+		if part.Filename != "" && part.Body != nil {
+			parts = append(parts, part)
+		}
+		parts = append(parts, attachmentParts(part)...)
+	}
+	return parts
+}
+
+// ingestAttachments extracts and stores the text of a message's PDF, Word and text attachments
+// and of the Google Docs its body links to. Files already processed are skipped; files that
+// can't be read are stored with the reason so they aren't retried on every sync.
+func (g *GmailClient) ingestAttachments(ctx context.Context, database *db.DB, msg *gmail.Message, threadID, body string) {
+	maxBytes := int64(g.Config.Google.Attachments.MaxSizeMB) << 20
+
+	for _, part := range attachmentParts(msg.Payload) {
+		mimeType := attachmentMimeType(part)
+		id := msg.Id + ":" + part.PartId
+		if mimeType == "" || database.HasAttachment(id) {
+			continue
+		}
+
+		attachment := &db.Attachment{
+			ID:        id,
+			MessageID: msg.Id,
+			ThreadID:  threadID,
+			Filename:  part.Filename,
+			MimeType:  mimeType,
+			SizeBytes: part.Body.Size,
+		}
+		if attachment.SizeBytes > maxBytes {
+			attachment.Error = fmt.Sprintf("larger than %d MB", g.Config.Google.Attachments.MaxSizeMB)
+		} else if data, err := g.downloadAttachment(ctx, msg.Id, part.Body); err != nil {
+			attachment.Error = err.Error()
+		} else if text, err := g.extractAttachmentText(ctx, mimeType, data); err != nil {
+			attachment.Error = err.Error()
+		} else {
+			attachment.Text = text
+		}
+
+		if attachment.Error != "" {
+			log.Printf("No text from attachment %q on message %s: %s", part.Filename, msg.Id, attachment.Error)
+		}
+		if err := database.SaveAttachment(attachment); err != nil {
+			log.Printf("Failed to save attachment %q: %v", part.Filename, err)
+		}
+	}
+
+	if g.Drive == nil {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, match := range googleDocLinkPattern.FindAllStringSubmatch(body, -1) {
+		fileID := match[1]
+		id := msg.Id + ":drive:" + fileID
+		if seen[fileID] || database.HasAttachment(id) {
+			continue
+		}
+		seen[fileID] = true
+
+		attachment := &db.Attachment{
+			ID:        id,
+			MessageID: msg.Id,
+			ThreadID:  threadID,
+			Filename:  match[0],
+		}
+		file, text, err := g.Drive.ExportText(ctx, fileID, maxBytes)
+		if err != nil {
+			attachment.Error = err.Error()
+			log.Printf("No text from linked doc %s on message %s: %v", fileID, msg.Id, err)
+		} else {
+			attachment.Filename = file.Name
+			attachment.MimeType = file.MimeType
+			attachment.SizeBytes = int64(len(text))
+			attachment.Text = truncateText(text, maxAttachmentText)
+		}
+		if err := database.SaveAttachment(attachment); err != nil {
+			log.Printf("Failed to save linked doc %s: %v", fileID, err)
+		}
+	}
+}
+
+// downloadAttachment returns an attachment's content, fetching it unless it came inline
+func (g *GmailClient) downloadAttachment(ctx context.Context, messageID string, body *gmail.MessagePartBody) ([]byte, error) {
+	data := body.Data
+	if body.AttachmentId != "" {
+		attachment, err := g.Service.Users.Messages.Attachments.Get("me", messageID, body.AttachmentId).Context(ctx).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to download attachment: %w", err)
+		}
+		data = attachment.Data
+	}
+
+	decoded, err := base64.URLEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode attachment: %w", err)
+	}
+	return decoded, nil
+}
+
+// extractAttachmentText extracts the text of a PDF, Word document or text file
+func (g *GmailClient) extractAttachmentText(ctx context.Context, mimeType string, data []byte) (string, error) {
+	var text string
+	switch mimeType {
+	case mimePDF:
+		cmd := exec.CommandContext(ctx, g.Config.Google.Attachments.PDFToText, "-q", "-", "-")
+		cmd.Stdin = bytes.NewReader(data)
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("pdftotext failed: %w", err)
+		}
+		text = string(out)
+	case mimeDOCX:
+		var err error
+		if text, err = extractDOCXText(data); err != nil {
+			return "", err
+		}
+	default:
+		text = string(data)
+	}
+
+	text = strings.TrimSpace(strings.ToValidUTF8(text, ""))
+	if text == "" {
+		return "", fmt.Errorf("no text found")
+	}
+	return truncateText(text, maxAttachmentText), nil
+}
+
+// extractDOCXText reads the paragraphs of a Word document's main body
+func extractDOCXText(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("not a Word document: %w", err)
+	}
+
+	for _, file := range archive.File {
+		if file.Name != "word/document.xml" {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return "", fmt.Errorf("failed to open document body: %w", err)
+		}
+		defer reader.Close()
+
+		var text strings.Builder
+		decoder := xml.NewDecoder(reader)
+		inText := false
+		for {
+			token, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", fmt.Errorf("failed to read document body: %w", err)
+			}
+			switch t := token.(type) {
+			case xml.StartElement:
+				switch t.Name.Local {
+				case "t":
+					inText = true
+				case "tab":
+					text.WriteString("\t")
+				case "br":
+					text.WriteString("\n")
+				}
+			case xml.EndElement:
+				switch t.Name.Local {
+				case "t":
+					inText = false
+				case "p":
+					text.WriteString("\n")
+				}
+			case xml.CharData:
+				if inText {
+					text.Write(t)
+				}
+			}
+		}
+		return text.String(), nil
+	}
+
+	return "", fmt.Errorf("document body not found")
+}
+
+// truncateText cuts text to at most max bytes without splitting a character
+func truncateText(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	text = text[:max]
+	for !utf8.ValidString(text) {
+		text = text[:len(text)-1]
+	}
+	return text
+}
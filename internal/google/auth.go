@@ -1,14 +1,18 @@
 package google
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/oauth2"
@@ -17,6 +21,7 @@ import (
 	"google.golang.org/api/chat/v1"
 	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/gmail/v1"
+	"google.golang.org/api/keep/v1"
 	"google.golang.org/api/option"
 	"google.golang.org/api/tasks/v1"
 
@@ -30,10 +35,18 @@ type Clients struct {
 	Drive    *DriveClient
 	Calendar *CalendarClient
 	Tasks    *TasksClient
+	Keep     *KeepClient
 	Chat     *ChatClient
+
+	tokens *savingTokenSource
+	calls  *callCounter
+	config *config.Config
 }
 
-// NewClients creates all Google API clients
+// NewClients creates all Google API clients. Nothing goes over the network here: the saved
+// token is refreshed on the first API call that needs it, so commands that never call Google
+// start instantly and work offline. Only the first run, with no saved token, goes through the
+// OAuth flow.
 func NewClients(ctx context.Context, cfg *config.Config) (*Clients, error) {
 	// Get OAuth2 config
 	oauth2Config := &oauth2.Config{
@@ -44,14 +57,28 @@ func NewClients(ctx context.Context, cfg *config.Config) (*Clients, error) {
 		Endpoint:     google.Endpoint,
 	}
 
+	tokenFile, err := expandHome(cfg.Google.TokenFile)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get token
-	token, err := getToken(ctx, oauth2Config, cfg.Google.TokenFile)
+	token, err := getToken(oauth2Config, tokenFile, cfg.Google.AuthFlow)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get token: %w", err)
 	}
 
-	// Create HTTP client
-	httpClient := oauth2Config.Client(ctx, token)
+	// Create HTTP client, refreshing the token when a request needs it
+	tokens := &savingTokenSource{
+		base: oauth2Config.TokenSource(ctx, token),
+		file: tokenFile,
+		last: token.AccessToken,
+	}
+	httpClient := oauth2.NewClient(ctx, tokens)
+
+	// Count requests per API for the quota guard
+	calls := &callCounter{base: httpClient.Transport}
+	httpClient.Transport = calls
 
 	// Create Gmail service
 	gmailService, err := gmail.NewService(ctx, option.WithHTTPClient(httpClient))
@@ -59,17 +86,6 @@ func NewClients(ctx context.Context, cfg *config.Config) (*Clients, error) {
 		return nil, fmt.Errorf("failed to create Gmail service: %w", err)
 	}
 
-	// Capture the authenticated user's email address for downstream services
-	if cfg.Google.UserEmail == "" {
-		profile, err := gmailService.Users.GetProfile("me").Context(ctx).Do()
-		if err != nil {
-			log.Printf("WARNING: failed to retrieve Gmail profile for user identification: %v", err)
-		} else if profile != nil && profile.EmailAddress != "" {
-			cfg.Google.UserEmail = strings.ToLower(profile.EmailAddress)
-			log.Printf("Detected authenticated Google Workspace user: %s", cfg.Google.UserEmail)
-		}
-	}
-
 	// Create Drive service
 	driveService, err := drive.NewService(ctx, option.WithHTTPClient(httpClient))
 	if err != nil {
@@ -88,49 +104,183 @@ func NewClients(ctx context.Context, cfg *config.Config) (*Clients, error) {
 		return nil, fmt.Errorf("failed to create Tasks service: %w", err)
 	}
 
+	// Create Keep service
+	keepService, err := keep.NewService(ctx, option.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Keep service: %w", err)
+	}
+
 	// Create Chat service
 	chatService, err := chat.NewService(ctx, option.WithHTTPClient(httpClient))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Chat service: %w", err)
 	}
 
-	return &Clients{
-		Gmail:    &GmailClient{Service: gmailService, Config: cfg},
-		Drive:    &DriveClient{Service: driveService, Config: cfg},
+	chatClient := &ChatClient{Service: chatService, Config: cfg, httpClient: httpClient}
+
+	// Interactive cards can only be posted by the Chat app itself, not on the user's behalf
+	if cfg.Chat.App.Enabled {
+		appService, err := chat.NewService(ctx,
+			option.WithCredentialsFile(cfg.Chat.App.CredentialsFile),
+			option.WithScopes("https://www.googleapis.com/auth/chat.bot"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Chat app service: %w", err)
+		}
+		chatClient.appService = appService
+	}
+
+	driveClient := &DriveClient{Service: driveService, Config: cfg}
+
+	clients := &Clients{
+		Gmail:    &GmailClient{Service: gmailService, Config: cfg, Drive: driveClient},
+		Drive:    driveClient,
 		Calendar: &CalendarClient{Service: calendarService, Config: cfg},
 		Tasks:    &TasksClient{Service: tasksService, Config: cfg},
-		Chat:     &ChatClient{Service: chatService, Config: cfg, httpClient: httpClient},
-	}, nil
+		Keep:     &KeepClient{Service: keepService, Config: cfg},
+		Chat:     chatClient,
+		tokens:   tokens,
+		calls:    calls,
+		config:   cfg,
+	}
+
+	// Capture the authenticated user's email address for downstream services. It's kept next to
+	// the token, so only the first run has to ask Gmail.
+	if cfg.Google.UserEmail == "" {
+		if email, err := os.ReadFile(userEmailFile(tokenFile)); err == nil {
+			cfg.Google.UserEmail = strings.TrimSpace(string(email))
+		}
+	}
+	if cfg.Google.UserEmail == "" {
+		if err := clients.detectUserEmail(ctx); err != nil {
+			log.Printf("WARNING: failed to retrieve Gmail profile for user identification: %v", err)
+		}
+	}
+
+	return clients, nil
 }
 
-// getToken retrieves a token from a local file or runs OAuth flow
-func getToken(ctx context.Context, config *oauth2.Config, tokenFile string) (*oauth2.Token, error) {
-	// Expand home directory
-	if tokenFile[:2] == "~/" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, err
+// Authenticate checks that the saved token still works, refreshing it if it has expired, and
+// looks up the user's email address if it isn't known yet
+func (c *Clients) Authenticate(ctx context.Context) error {
+	if _, err := c.tokens.Token(); err != nil {
+		return fmt.Errorf("failed to refresh token: %w", err)
+	}
+	if c.config.Google.UserEmail == "" {
+		return c.detectUserEmail(ctx)
+	}
+	return nil
+}
+
+// detectUserEmail asks Gmail who the token belongs to and remembers the answer
+func (c *Clients) detectUserEmail(ctx context.Context) error {
+	profile, err := c.Gmail.Service.Users.GetProfile("me").Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	if profile == nil || profile.EmailAddress == "" {
+		return fmt.Errorf("Gmail profile has no email address")
+	}
+
+	c.config.Google.UserEmail = strings.ToLower(profile.EmailAddress)
+	log.Printf("Detected authenticated Google Workspace user: %s", c.config.Google.UserEmail)
+	if err := os.WriteFile(userEmailFile(c.tokens.file), []byte(c.config.Google.UserEmail+"\n"), 0600); err != nil {
+		log.Printf("Failed to save user email: %v", err)
+	}
+	return nil
+}
+
+// userEmailFile is where the user's email address is kept, next to the token it belongs to
+func userEmailFile(tokenFile string) string {
+	return strings.TrimSuffix(tokenFile, filepath.Ext(tokenFile)) + ".email"
+}
+
+// tokenCheckInterval is how often TokenHealth asks for a token, refreshing it if it has expired
+const tokenCheckInterval = 5 * time.Minute
+
+// TokenHealth is whether the saved Google token can still be refreshed
+type TokenHealth struct {
+	Healthy   bool      `json:"healthy"`
+	Expiry    time.Time `json:"expiry,omitempty"`     // When the current access token expires
+	CheckedAt time.Time `json:"checked_at,omitempty"` // When a token was last asked for
+	Error     string    `json:"error,omitempty"`      // Why the last refresh failed
+}
+
+// TokenHealth reports whether the Google token works, refreshing it if it's expired and hasn't
+// been checked for a few minutes. A failure here usually means the token was revoked and
+// -auth has to be run again.
+func (c *Clients) TokenHealth() *TokenHealth {
+	c.tokens.mu.Lock()
+	stale := time.Since(c.tokens.checkedAt) > tokenCheckInterval
+	c.tokens.mu.Unlock()
+	if stale {
+		c.tokens.Token()
+	}
+
+	c.tokens.mu.Lock()
+	defer c.tokens.mu.Unlock()
+	health := &TokenHealth{
+		Healthy:   c.tokens.err == nil,
+		Expiry:    c.tokens.expiry,
+		CheckedAt: c.tokens.checkedAt,
+	}
+	if c.tokens.err != nil {
+		health.Error = c.tokens.err.Error()
+	}
+	return health
+}
+
+// savingTokenSource refreshes the OAuth token when it has expired and saves each new one
+type savingTokenSource struct {
+	mu   sync.Mutex
+	base oauth2.TokenSource
+	file string
+	last string // Access token last saved
+
+	// Result of the last request for a token, for TokenHealth
+	checkedAt time.Time
+	expiry    time.Time
+	err       error
+}
+
+func (s *savingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, err := s.base.Token()
+	s.checkedAt = time.Now()
+	s.err = err
+	if err != nil {
+		return nil, err
+	}
+	s.expiry = token.Expiry
+	if token.AccessToken != s.last {
+		// Token was refreshed, save it
+		if err := saveToken(s.file, token); err != nil {
+			log.Printf("Failed to save refreshed token: %v", err)
 		}
-		tokenFile = filepath.Join(home, tokenFile[2:])
+		s.last = token.AccessToken
 	}
+	return token, nil
+}
 
+// getToken retrieves a token from a local file or runs OAuth flow
+func getToken(oauth2Config *oauth2.Config, tokenFile, flow string) (*oauth2.Token, error) {
 	// Try to read token from file
 	token, err := tokenFromFile(tokenFile)
 	if err == nil {
-		// Check if token is expired and refresh if needed
-		tokenSource := config.TokenSource(ctx, token)
-		newToken, err := tokenSource.Token()
-		if err == nil && newToken.AccessToken != token.AccessToken {
-			// Token was refreshed, save it
-			saveToken(tokenFile, newToken)
-			return newToken, nil
-		}
 		return token, nil
 	}
 
 	// Need to run OAuth flow
-	log.Printf("Starting OAuth flow...")
-	token, err = getTokenFromWeb(config)
+	log.Printf("Starting OAuth flow (%s)...", flow)
+	switch flow {
+	case config.AuthFlowManual:
+		token, err = getTokenFromPaste(oauth2Config, os.Stdin)
+	case config.AuthFlowDevice:
+		token, err = getTokenFromDevice(oauth2Config)
+	default:
+		token, err = getTokenFromWeb(oauth2Config)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get token from web: %w", err)
 	}
@@ -146,6 +296,18 @@ func getToken(ctx context.Context, config *oauth2.Config, tokenFile string) (*oa
 	return token, nil
 }
 
+// expandHome expands a leading ~/ in a path
+func expandHome(path string) (string, error) {
+	if !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[2:]), nil
+}
+
 // tokenFromFile retrieves a token from a local file
 func tokenFromFile(file string) (*oauth2.Token, error) {
 	f, err := os.Open(file)
@@ -161,7 +323,8 @@ func tokenFromFile(file string) (*oauth2.Token, error) {
 
 // saveToken saves a token to a file path
 func saveToken(path string, token *oauth2.Token) error {
-	fmt.Printf("Saving credential file to: %s\n", path)
+	// Logged rather than printed, since a refresh can happen while the TUI owns the terminal
+	log.Printf("Saving credential file to: %s", path)
 
 	// Create directory if needed
 	dir := filepath.Dir(path)
@@ -253,6 +416,61 @@ func getTokenFromWeb(config *oauth2.Config) (*oauth2.Token, error) {
 	return token, nil
 }
 
+// getTokenFromPaste runs the OAuth flow without a local browser or callback server: the consent
+// link is opened on any machine, and the URL Google redirects to is pasted back. It doesn't
+// matter whether the redirect page loads, only that its URL carries the code.
+func getTokenFromPaste(config *oauth2.Config, input io.Reader) (*oauth2.Token, error) {
+	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
+	fmt.Printf("Open the following link in a browser on any machine:\n%v\n\n", authURL)
+	fmt.Printf("After approving, the browser goes to %s, which may not load.\n", config.RedirectURL)
+	fmt.Printf("Paste the full URL from its address bar (or just the code) here: ")
+
+	line, err := bufio.NewReader(input).ReadString('\n')
+	if err != nil && line == "" {
+		return nil, fmt.Errorf("failed to read authorization code: %w", err)
+	}
+	code := authCodeFromInput(line)
+	if code == "" {
+		return nil, fmt.Errorf("no authorization code entered")
+	}
+
+	log.Printf("Exchanging authorization code for access token...")
+	token, err := config.Exchange(context.Background(), code)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve token from pasted code: %w", err)
+	}
+	return token, nil
+}
+
+// authCodeFromInput takes the code out of a pasted redirect URL, or returns a bare code as is
+func authCodeFromInput(input string) string {
+	input = strings.TrimSpace(input)
+	if u, err := url.Parse(input); err == nil && u.Query().Get("code") != "" {
+		return u.Query().Get("code")
+	}
+	return input
+}
+
+// getTokenFromDevice runs the OAuth device flow: a code is entered at Google's device page from
+// any phone or computer while this waits. It needs an OAuth client of type "TVs and Limited Input
+// devices", and Google only allows some scopes with it.
+func getTokenFromDevice(config *oauth2.Config) (*oauth2.Token, error) {
+	ctx := context.Background()
+	response, err := config.DeviceAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start device authorization (Google doesn't allow every scope here; try google.auth_flow: manual): %w", err)
+	}
+
+	fmt.Printf("On any device, go to %s and enter the code %s\n", response.VerificationURI, response.UserCode)
+	fmt.Printf("Waiting for approval (the code expires at %s)...\n", response.Expiry.Format("15:04"))
+
+	token, err := config.DeviceAccessToken(ctx, response)
+	if err != nil {
+		return nil, fmt.Errorf("device authorization failed: %w", err)
+	}
+	return token, nil
+}
+
 // RefreshToken refreshes an OAuth token
 func RefreshToken(ctx context.Context, cfg *config.Config, token *oauth2.Token) (*oauth2.Token, error) {
 	oauth2Config := &oauth2.Config{
@@ -0,0 +1,24 @@
+package google
+
+import (
+	"testing"
+)
+
+// TestAuthCodeFromInput verifies the code is found in a pasted redirect URL or taken as is
+func TestAuthCodeFromInput(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"http://localhost:8080/callback?state=state-token&code=4/0Abc-def&scope=x\n", "4/0Abc-def"},
+		{"https://agent.example.com/oauth?code=4%2F0Abc", "4/0Abc"},
+		{"  4/0Abc-def  \n", "4/0Abc-def"},
+		{"\n", ""},
+	}
+
+	for _, tt := range tests {
+		if got := authCodeFromInput(tt.input); got != tt.want {
+			t.Errorf("authCodeFromInput(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/plaintext"
+)
+
+// emailBrief builds the HTML body of an emailed brief
+type emailBrief struct {
+	body  strings.Builder
+	plain bool // notifications.plain_formatting includes email
+}
+
+func (g *GmailClient) newEmailBrief(title, subtitle string) *emailBrief {
+	b := &emailBrief{plain: g.Config.Notify.Plain(config.ChannelEmail)}
+	b.body.WriteString(`<html><body style="font-family: -apple-system, Helvetica, Arial, sans-serif; color: #202124; max-width: 640px;">`)
+	b.body.WriteString(fmt.Sprintf(`<h2 style="margin-bottom: 0;">%s</h2>`, html.EscapeString(b.text(title))))
+	if subtitle != "" {
+		b.body.WriteString(fmt.Sprintf(`<p style="color: #5f6368; margin-top: 4px;">%s</p>`, html.EscapeString(b.text(subtitle))))
+	}
+	return b
+}
+
+// text applies plain formatting when the email channel uses it
+func (b *emailBrief) text(text string) string {
+	if b.plain {
+		return plaintext.Text(text)
+	}
+	return text
+}
+
+// paragraph adds a block of prose
+func (b *emailBrief) paragraph(text string) {
+	b.body.WriteString(fmt.Sprintf(`<p>%s</p>`, html.EscapeString(b.text(text))))
+}
+
+// list adds a headed list, leaving it out if there are no items. Items are HTML.
+func (b *emailBrief) list(header string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	b.body.WriteString(fmt.Sprintf(`<h3 style="margin-bottom: 4px;">%s</h3><ul style="margin-top: 0;">`, html.EscapeString(b.text(header))))
+	for _, item := range items {
+		b.body.WriteString("<li>" + b.text(item) + "</li>")
+	}
+	b.body.WriteString("</ul>")
+}
+
+func (b *emailBrief) String() string {
+	return b.body.String() + `<p style="color: #9aa0a6; font-size: 12px;">Sent by Focus Agent</p></body></html>`
+}
+
+// emailTask describes a task as an HTML list item, linking to its source where there is one
+func emailTask(task *db.Task, detail string) string {
+	item := html.EscapeString(task.Title)
+	switch {
+	case task.Source == "gmail" && task.SourceID != "":
+		item = fmt.Sprintf(`<a href="https://mail.google.com/mail/u/0/#inbox/%s">%s</a>`, html.EscapeString(task.SourceID), item)
+	case task.Source == db.TaskSourceGoogleChat && task.SourceID != "":
+		item = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(db.ChatThreadLink(task.SourceID)), item)
+	}
+	if detail != "" {
+		item += fmt.Sprintf(` <span style="color: #5f6368;">(%s)</span>`, html.EscapeString(detail))
+	}
+	return item
+}
+
+// SendDailyBriefEmail emails the daily brief: meetings moved or cancelled, warnings about
+// today's calendar, top tasks, today's meetings, recurring tasks coming up, notable changes to
+// thread summaries, related documents edited since the last brief and outstanding commitments
+func (g *GmailClient) SendDailyBriefEmail(ctx context.Context, to string, tasks []*db.Task, events []*db.Event, upcoming []*db.Task, changes []*db.EventChange, threadChanges []*db.SummaryChange, docs []*db.DocumentUpdate, commitments []*db.Commitment, warnings []*db.CalendarWarning) error {
+	now := time.Now()
+	brief := g.newEmailBrief("Daily Brief - "+now.Format("Monday, January 2"), "Your focus plan for today")
+
+	var items []string
+	for _, change := range changes {
+		items = append(items, html.EscapeString(change.Describe()))
+	}
+	brief.list("📅 Calendar Changes", items)
+
+	items = nil
+	for _, warning := range warnings {
+		items = append(items, html.EscapeString(warning.Describe()))
+	}
+	brief.list("⚠️ Calendar Warnings", items)
+
+	items = nil
+	for i, task := range tasks {
+		if i >= 5 {
+			break
+		}
+		detail := humanizeSource(task.Source)
+		if task.DueTS != nil {
+			detail += " • Due: " + task.DueTS.Format("3:04 PM")
+		}
+		items = append(items, emailTask(task, detail))
+	}
+	brief.list("📋 Top Priority Tasks", items)
+
+	items = nil
+	for _, event := range events {
+		items = append(items, fmt.Sprintf("<b>%s</b> %s", event.StartTS.Format("3:04 PM"), html.EscapeString(event.Title)))
+	}
+	brief.list("🗓️ Today's Meetings", items)
+
+	items = nil
+	for _, task := range upcoming {
+		items = append(items, emailTask(task, task.DueTS.Format("Mon Jan 2")+", "+db.DescribeRecurrence(task.Recurrence)))
+	}
+	brief.list("🔁 Coming Up (Recurring)", items)
+
+	items = nil
+	for _, change := range threadChanges {
+		items = append(items, fmt.Sprintf("<b>%s</b>: %s", html.EscapeString(threadChangeSubject(change)), html.EscapeString(change.Describe())))
+	}
+	brief.list("📝 Thread Updates", items)
+
+	items = nil
+	for _, doc := range docs {
+		item := html.EscapeString(doc.Describe())
+		if doc.Link != "" {
+			item = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(doc.Link), item)
+		}
+		items = append(items, item)
+	}
+	brief.list("📄 Docs Updated", items)
+
+	items = nil
+	for _, commitment := range commitments {
+		items = append(items, fmt.Sprintf(`<a href="https://mail.google.com/mail/u/0/#inbox/%s">%s</a>`,
+			html.EscapeString(commitment.ThreadID), html.EscapeString(commitment.Describe(now))))
+	}
+	brief.list("🤝 Outstanding Commitments", items)
+
+	return g.SendHTMLMessage(ctx, to, "Daily Brief - "+now.Format("Monday, January 2"), brief.String())
+}
+
+// SendEndOfDayBriefEmail emails the end-of-day shutdown brief
+func (g *GmailClient) SendEndOfDayBriefEmail(ctx context.Context, to string, brief *db.EndOfDayBrief) error {
+	title := "End of Day - " + brief.Date.Format("Monday, January 2")
+	email := g.newEmailBrief(title, "")
+
+	var items []string
+	for _, task := range brief.Completed {
+		items = append(items, emailTask(task, ""))
+	}
+	email.list(fmt.Sprintf("✅ Completed Today (%d)", len(brief.Completed)), items)
+
+	items = nil
+	for _, actual := range brief.Plan {
+		items = append(items, html.EscapeString(actual.Describe()))
+	}
+	email.list("📋 Plan vs Actual", items)
+
+	items = nil
+	for _, task := range brief.Slipping {
+		detail := ""
+		if task.DueTS != nil && task.DueTS.Before(brief.Date) {
+			detail = "overdue since " + task.DueTS.Format("Mon Jan 2")
+		}
+		items = append(items, emailTask(task, detail))
+	}
+	email.list("➡️ Slipping to Tomorrow", items)
+
+	items = nil
+	for _, event := range brief.TomorrowMorning {
+		items = append(items, fmt.Sprintf("<b>%s</b> %s", event.StartTS.Format("3:04 PM"), html.EscapeString(event.Title)))
+	}
+	for _, task := range brief.DueTomorrowAM {
+		items = append(items, emailTask(task, "due "+task.DueTS.Format("3:04 PM")))
+	}
+	email.list("🌅 Tomorrow Morning", items)
+
+	email.list("🌱 Tomorrow's Plan", []string{html.EscapeString(brief.PlanSeed)})
+
+	return g.SendHTMLMessage(ctx, to, title, email.String())
+}
+
+// SendWeeklyReviewEmail emails the weekly review of completed work and misses
+func (g *GmailClient) SendWeeklyReviewEmail(ctx context.Context, to string, review *db.WeeklyReview) error {
+	email := g.newEmailBrief("Weekly Review",
+		fmt.Sprintf("%s – %s", review.Since.Format("Jan 2"), review.Until.Format("Jan 2")))
+	if review.Narrative != "" {
+		email.paragraph(review.Narrative)
+	}
+
+	var items []string
+	for _, task := range review.Completed {
+		items = append(items, emailTask(task, ""))
+	}
+	email.list(fmt.Sprintf("✅ Completed (%d)", len(review.Completed)), items)
+
+	items = nil
+	for _, task := range review.Slipped {
+		items = append(items, emailTask(task, "due "+task.DueTS.Format("Mon Jan 2")))
+	}
+	email.list(fmt.Sprintf("⚠️ Slipped Past Due (%d)", len(review.Slipped)), items)
+
+	items = nil
+	for _, thread := range review.AwaitingReply {
+		items = append(items, fmt.Sprintf(`<a href="https://mail.google.com/mail/u/0/#inbox/%s">%s</a> <span style="color: #5f6368;">(from %s, %s)</span>`,
+			html.EscapeString(thread.ThreadID), html.EscapeString(thread.Subject),
+			html.EscapeString(thread.From), thread.ReceivedAt.Format("Mon Jan 2")))
+	}
+	email.list(fmt.Sprintf("📬 Awaiting Your Reply (%d)", len(review.AwaitingReply)), items)
+
+	email.list("📅 Meetings", []string{fmt.Sprintf("%d meetings, %.1f hours", review.MeetingCount, review.MeetingHours)})
+	if len(review.AutoArchived) > 0 {
+		email.list("🗄️ Auto-Archived", []string{html.EscapeString(db.FormatSourceCounts(review.AutoArchived))})
+	}
+
+	if len(review.StaleProjects) > 0 {
+		items = nil
+		for _, project := range review.StaleProjects {
+			items = append(items, fmt.Sprintf(`%s <span style="color: #5f6368;">(%d open, quiet since %s)</span>`,
+				html.EscapeString(project.Name), project.OpenTasks, project.LastActivity.Format("Jan 2")))
+		}
+		email.list(fmt.Sprintf("🌇 Consider Closing (%d)", len(review.StaleProjects)), items)
+		email.paragraph("Archive a project and its remaining tasks with A on the Projects tab.")
+	}
+
+	return g.SendHTMLMessage(ctx, to, "Weekly Review - "+review.Until.Format("Jan 2"), email.String())
+}
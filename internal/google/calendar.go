@@ -138,9 +138,13 @@ func (c *CalendarClient) incrementalSync(ctx context.Context, database *db.DB, s
 	changeCount := 0
 	for _, event := range events.Items {
 		if event.Status == "cancelled" {
-			// Handle deleted events
+			// Deleted events only carry their ID, so mark the stored copy cancelled
 			log.Printf("Event cancelled: %s", event.Id)
-			// Could delete from DB or mark as cancelled
+			if err := database.CancelEvent(event.Id); err != nil {
+				log.Printf("Failed to cancel event %s: %v", event.Id, err)
+				continue
+			}
+			changeCount++
 		} else {
 			if err := c.processEvent(ctx, database, event); err != nil {
 				log.Printf("Failed to process event %s: %v", event.Id, err)
@@ -172,10 +176,19 @@ func (c *CalendarClient) processEvent(ctx context.Context, database *db.DB, even
 		return fmt.Errorf("failed to parse end time: %w", err)
 	}
 
-	// Extract attendees
+	// Extract attendees, and the user's own RSVP
 	var attendees []string
+	var response string
 	for _, attendee := range event.Attendees {
 		attendees = append(attendees, attendee.Email)
+		if attendee.Self {
+			response = attendee.ResponseStatus
+		}
+	}
+
+	var organizer string
+	if event.Organizer != nil {
+		organizer = event.Organizer.Email
 	}
 
 	// Extract meeting link
@@ -192,6 +205,8 @@ func (c *CalendarClient) processEvent(ctx context.Context, database *db.DB, even
 		Attendees:   attendees,
 		MeetingLink: meetingLink,
 		Status:      event.Status,
+		Organizer:   organizer,
+		Response:    response,
 	}
 
 	// Save to database
@@ -235,11 +250,11 @@ func (c *CalendarClient) CreateEvent(ctx context.Context, title, description str
 		Description: description,
 		Start: &calendar.EventDateTime{
 			DateTime: startTime.Format(time.RFC3339),
-			TimeZone: c.Config.Schedule.Timezone,
+			TimeZone: c.Config.Schedule.ActiveTimezone(),
 		},
 		End: &calendar.EventDateTime{
 			DateTime: endTime.Format(time.RFC3339),
-			TimeZone: c.Config.Schedule.Timezone,
+			TimeZone: c.Config.Schedule.ActiveTimezone(),
 		},
 	}
 
@@ -261,6 +276,96 @@ func (c *CalendarClient) CreateEvent(ctx context.Context, title, description str
 	return createdEvent, nil
 }
 
+// CreateFocusBlock creates a tentative, busy event for a planned focus block. The block and
+// its tasks are recorded in the event's private properties.
+func (c *CalendarClient) CreateFocusBlock(ctx context.Context, title, description string, startTime, endTime time.Time, blockID string, taskIDs []string) (*calendar.Event, error) {
+	event := &calendar.Event{
+		Summary:      title,
+		Description:  description,
+		Status:       "tentative",
+		Transparency: "opaque",
+		Start: &calendar.EventDateTime{
+			DateTime: startTime.Format(time.RFC3339),
+			TimeZone: c.Config.Schedule.ActiveTimezone(),
+		},
+		End: &calendar.EventDateTime{
+			DateTime: endTime.Format(time.RFC3339),
+			TimeZone: c.Config.Schedule.ActiveTimezone(),
+		},
+		ExtendedProperties: &calendar.EventExtendedProperties{
+			Private: map[string]string{
+				"focusAgentBlock": blockID,
+				"focusAgentTasks": strings.Join(taskIDs, ","),
+			},
+		},
+	}
+
+	createdEvent, err := c.Service.Events.Insert("primary", event).
+		Context(ctx).
+		Do()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create focus block: %w", err)
+	}
+
+	return createdEvent, nil
+}
+
+// DeleteEvent removes an event from the primary calendar
+func (c *CalendarClient) DeleteEvent(ctx context.Context, eventID string) error {
+	if err := c.Service.Events.Delete("primary", eventID).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+	return nil
+}
+
+// MoveEvent changes the start and end of an event on the primary calendar
+func (c *CalendarClient) MoveEvent(ctx context.Context, eventID string, startTime, endTime time.Time) error {
+	patch := &calendar.Event{
+		Start: &calendar.EventDateTime{
+			DateTime: startTime.Format(time.RFC3339),
+			TimeZone: c.Config.Schedule.ActiveTimezone(),
+		},
+		End: &calendar.EventDateTime{
+			DateTime: endTime.Format(time.RFC3339),
+			TimeZone: c.Config.Schedule.ActiveTimezone(),
+		},
+	}
+
+	if _, err := c.Service.Events.Patch("primary", eventID, patch).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to move event: %w", err)
+	}
+	return nil
+}
+
+// RespondToEvent sets the user's RSVP (accepted, tentative or declined) to an event on the
+// primary calendar. A note is added as the response comment, which the organizer sees.
+func (c *CalendarClient) RespondToEvent(ctx context.Context, eventID, response, note string) error {
+	event, err := c.Service.Events.Get("primary", eventID).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get event: %w", err)
+	}
+
+	invited := false
+	for _, attendee := range event.Attendees {
+		if attendee.Self {
+			attendee.ResponseStatus = response
+			attendee.Comment = note
+			invited = true
+		}
+	}
+	if !invited {
+		return fmt.Errorf("%s has no invitation to respond to", event.Summary)
+	}
+
+	// Attendees are replaced as a whole, so the full list goes back with only the user's entry changed
+	patch := &calendar.Event{Attendees: event.Attendees}
+	if _, err := c.Service.Events.Patch("primary", eventID, patch).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to respond to event: %w", err)
+	}
+	return nil
+}
+
 // parseEventTime parses event time from calendar API
 func parseEventTime(eventTime *calendar.EventDateTime) (time.Time, error) {
 	if eventTime.DateTime != "" {
@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 
@@ -21,7 +22,9 @@ type ChatClient struct {
 	Service    *chat.Service
 	Config     *config.Config
 	httpClient *http.Client
-	dmSpace    string // Cached DM space name
+	appService *chat.Service // Posts as the Chat app, for messages with cards (chat.app)
+	dmSpace    string        // Cached DM space name
+	chatUserID string        // Cached Chat ID of the user (users/{id}), for telling their own messages apart
 }
 
 // ChatMessage represents a Google Chat message
@@ -33,7 +36,8 @@ type ChatMessage struct {
 
 // ChatThread represents a thread in Google Chat
 type ChatThread struct {
-	Name string `json:"name,omitempty"`
+	Name      string `json:"name,omitempty"`
+	ThreadKey string `json:"threadKey,omitempty"` // Client-assigned key; messages sharing it are threaded together
 }
 
 // ChatCard represents a card in Google Chat
@@ -92,6 +96,13 @@ type TextButton struct {
 // OnClick represents the action when a button is clicked
 type OnClick struct {
 	OpenLink *OpenLink `json:"openLink,omitempty"`
+	Action   *Action   `json:"action,omitempty"`
+}
+
+// Action is a function the Chat app runs when a button is clicked
+type Action struct {
+	Function   string            `json:"function"`
+	Parameters map[string]string `json:"parameters,omitempty"`
 }
 
 // OpenLink represents a link to open
@@ -182,10 +193,16 @@ func (c *ChatClient) getDMSpace(ctx context.Context) (string, error) {
 
 // SendMessage sends a message to Google Chat via the API
 func (c *ChatClient) SendMessage(ctx context.Context, message *ChatMessage) error {
+	_, err := c.sendMessage(ctx, message)
+	return err
+}
+
+// sendMessage sends a message and returns the created message's resource name
+func (c *ChatClient) sendMessage(ctx context.Context, message *ChatMessage) (string, error) {
 	// Get the DM space
 	spaceName, err := c.getDMSpace(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get DM space: %w", err)
+		return "", fmt.Errorf("failed to get DM space: %w", err)
 	}
 
 	// Convert our message format to Chat API message format
@@ -204,14 +221,30 @@ func (c *ChatClient) SendMessage(ctx context.Context, message *ChatMessage) erro
 		chatMessage.CardsV2 = cardsV2
 	}
 
+	// Cards can't be posted with user credentials, so they go out as the Chat app
+	service := c.Service
+	if len(message.Cards) > 0 && c.appService != nil {
+		service = c.appService
+	}
+
 	// Create the message
-	createCall := c.Service.Spaces.Messages.Create(spaceName, chatMessage)
-	_, err = createCall.Do()
+	createCall := service.Spaces.Messages.Create(spaceName, chatMessage).Context(ctx)
+
+	// Thread under an existing conversation when a thread is set, starting a new one if needed
+	if message.Thread != nil && (message.Thread.Name != "" || message.Thread.ThreadKey != "") {
+		chatMessage.Thread = &chat.Thread{
+			Name:      message.Thread.Name,
+			ThreadKey: message.Thread.ThreadKey,
+		}
+		createCall = createCall.MessageReplyOption("REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD")
+	}
+
+	created, err := createCall.Do()
 	if err != nil {
-		return fmt.Errorf("failed to send message: %w", err)
+		return "", fmt.Errorf("failed to send message: %w", err)
 	}
 
-	return nil
+	return created.Name, nil
 }
 
 // convertToAPICard converts our internal card format to Chat API CardWithId format
@@ -251,7 +284,19 @@ func (c *ChatClient) convertToAPICard(card *ChatCard) *chat.CardWithId {
 						TopLabel:    widget.KeyValue.TopLabel,
 						Text:        widget.KeyValue.Content,
 						BottomLabel: widget.KeyValue.BottomLabel,
+						WrapText:    widget.KeyValue.ContentMultiline,
+					}
+					if widget.KeyValue.Button != nil {
+						apiWidget.DecoratedText.Button = convertToAPIButton(widget.KeyValue.Button)
+					}
+				}
+
+				if len(widget.Buttons) > 0 {
+					buttonList := &chat.GoogleAppsCardV1ButtonList{}
+					for k := range widget.Buttons {
+						buttonList.Buttons = append(buttonList.Buttons, convertToAPIButton(&widget.Buttons[k]))
 					}
+					apiWidget.ButtonList = buttonList
 				}
 
 				widgets[j] = apiWidget
@@ -267,18 +312,56 @@ func (c *ChatClient) convertToAPICard(card *ChatCard) *chat.CardWithId {
 	}
 }
 
-// SendDailyBrief sends the daily brief as text (cards not supported with user credentials)
-func (c *ChatClient) SendDailyBrief(ctx context.Context, tasks []*db.Task, events []*db.Event) error {
-	text := c.createDailyBriefText(tasks, events)
+// convertToAPIButton converts a text button to the Chat API format
+func convertToAPIButton(button *Button) *chat.GoogleAppsCardV1Button {
+	apiButton := &chat.GoogleAppsCardV1Button{OnClick: &chat.GoogleAppsCardV1OnClick{}}
+	if button.TextButton == nil {
+		return apiButton
+	}
+
+	apiButton.Text = button.TextButton.Text
+	if onClick := button.TextButton.OnClick; onClick != nil {
+		if onClick.OpenLink != nil {
+			apiButton.OnClick.OpenLink = &chat.GoogleAppsCardV1OpenLink{Url: onClick.OpenLink.URL}
+		}
+		if onClick.Action != nil {
+			action := &chat.GoogleAppsCardV1Action{Function: onClick.Action.Function}
+			keys := make([]string, 0, len(onClick.Action.Parameters))
+			for key := range onClick.Action.Parameters {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				action.Parameters = append(action.Parameters, &chat.GoogleAppsCardV1ActionParameter{
+					Key:   key,
+					Value: onClick.Action.Parameters[key],
+				})
+			}
+			apiButton.OnClick.Action = action
+		}
+	}
+	return apiButton
+}
+
+// SendDailyBrief sends the daily brief as text (cards not supported with user credentials).
+// It starts the day's Chat thread that later replan and follow-up messages reply to.
+// Recurring tasks due in the coming week are listed after the top tasks, and meetings moved
+// or cancelled since the last brief before them, followed by warnings about today's calendar,
+// with notable changes to thread summaries, related documents edited since the last brief and outstanding commitments at the end. With the Chat app configured, the top tasks also get a card with buttons to act on them.
+func (c *ChatClient) SendDailyBrief(ctx context.Context, database *db.DB, tasks []*db.Task, events []*db.Event, upcoming []*db.Task, changes []*db.EventChange, threadChanges []*db.SummaryChange, docs []*db.DocumentUpdate, commitments []*db.Commitment, warnings []*db.CalendarWarning) error {
+	text := c.createDailyBriefText(tasks, events, upcoming, changes, threadChanges, docs, commitments, warnings)
 	message := &ChatMessage{
 		Text: text,
 	}
+	if c.appService != nil && len(tasks) > 0 {
+		message.Cards = []ChatCard{c.createTaskActionsCard(tasks)}
+	}
 
-	return c.SendMessage(ctx, message)
+	return c.Deliver(ctx, database, "daily_brief", message)
 }
 
 // createDailyBriefText creates a plain text daily brief
-func (c *ChatClient) createDailyBriefText(tasks []*db.Task, events []*db.Event) string {
+func (c *ChatClient) createDailyBriefText(tasks []*db.Task, events []*db.Event, upcoming []*db.Task, changes []*db.EventChange, threadChanges []*db.SummaryChange, docs []*db.DocumentUpdate, commitments []*db.Commitment, warnings []*db.CalendarWarning) string {
 	now := time.Now()
 	var brief strings.Builder
 
@@ -286,6 +369,24 @@ func (c *ChatClient) createDailyBriefText(tasks []*db.Task, events []*db.Event)
 	brief.WriteString(fmt.Sprintf("*Daily Brief - %s*\n", now.Format("Monday, January 2")))
 	brief.WriteString("_Your focus plan for today_\n\n")
 
+	// Calendar changes section
+	if len(changes) > 0 {
+		brief.WriteString("📅 *Calendar Changes*\n")
+		for _, change := range changes {
+			brief.WriteString(fmt.Sprintf("• %s\n", change.Describe()))
+		}
+		brief.WriteString("\n")
+	}
+
+	// Calendar warnings section
+	if len(warnings) > 0 {
+		brief.WriteString("⚠️ *Calendar Warnings*\n")
+		for _, warning := range warnings {
+			brief.WriteString(fmt.Sprintf("• %s\n", warning.Describe()))
+		}
+		brief.WriteString("\n")
+	}
+
 	// Top Tasks section
 	if len(tasks) > 0 {
 		brief.WriteString("📋 *Top Priority Tasks*\n")
@@ -309,9 +410,54 @@ func (c *ChatClient) createDailyBriefText(tasks []*db.Task, events []*db.Event)
 		}
 	}
 
+	// Upcoming recurrences section
+	if len(upcoming) > 0 {
+		brief.WriteString("\n🔁 *Coming Up (Recurring)*\n")
+		for _, task := range upcoming {
+			brief.WriteString(fmt.Sprintf("• %s %s (%s)\n",
+				task.DueTS.Format("Mon Jan 2"), task.Title, db.DescribeRecurrence(task.Recurrence)))
+		}
+	}
+
+	// Thread summary changes section
+	if len(threadChanges) > 0 {
+		brief.WriteString("\n📝 *Thread Updates*\n")
+		for _, change := range threadChanges {
+			brief.WriteString(fmt.Sprintf("• *%s*: %s\n", threadChangeSubject(change), change.Describe()))
+		}
+	}
+
+	// Updated documents section
+	if len(docs) > 0 {
+		brief.WriteString("\n📄 *Docs Updated*\n")
+		for _, doc := range docs {
+			if doc.Link != "" {
+				brief.WriteString(fmt.Sprintf("• <%s|%s>\n", doc.Link, doc.Describe()))
+			} else {
+				brief.WriteString(fmt.Sprintf("• %s\n", doc.Describe()))
+			}
+		}
+	}
+
+	// Outstanding commitments section
+	if len(commitments) > 0 {
+		brief.WriteString("\n🤝 *Outstanding Commitments*\n")
+		for _, commitment := range commitments {
+			brief.WriteString(fmt.Sprintf("• %s\n", commitment.Describe(now)))
+		}
+	}
+
 	return brief.String()
 }
 
+// threadChangeSubject names the thread a summary change belongs to in briefs
+func threadChangeSubject(change *db.SummaryChange) string {
+	if change.Subject != "" {
+		return change.Subject
+	}
+	return "(no subject)"
+}
+
 // createDailyBriefCard creates a formatted daily brief card
 func (c *ChatClient) createDailyBriefCard(tasks []*db.Task, events []*db.Event) ChatCard {
 	now := time.Now()
@@ -370,14 +516,14 @@ func (c *ChatClient) createDailyBriefCard(tasks []*db.Task, events []*db.Event)
 	return card
 }
 
-// SendReplanBrief sends the midday replan brief
-func (c *ChatClient) SendReplanBrief(ctx context.Context, completedTasks int, remainingTasks []*db.Task, afternoonEvents []*db.Event) error {
+// SendReplanBrief sends the midday replan brief as a reply in the day's brief thread
+func (c *ChatClient) SendReplanBrief(ctx context.Context, database *db.DB, completedTasks int, remainingTasks []*db.Task, afternoonEvents []*db.Event) error {
 	card := c.createReplanCard(completedTasks, remainingTasks, afternoonEvents)
 	message := &ChatMessage{
 		Cards: []ChatCard{card},
 	}
 
-	return c.SendMessage(ctx, message)
+	return c.Deliver(ctx, database, "replan_brief", message)
 }
 
 // createReplanCard creates a midday replan card
@@ -432,8 +578,193 @@ func (c *ChatClient) createReplanCard(completedTasks int, remainingTasks []*db.T
 	return card
 }
 
-// SendFollowUpReminder sends a follow-up reminder
-func (c *ChatClient) SendFollowUpReminder(ctx context.Context, threads []*db.Thread) error {
+// SendEndOfDayBrief sends the end-of-day shutdown brief as a reply in the day's brief thread
+func (c *ChatClient) SendEndOfDayBrief(ctx context.Context, database *db.DB, brief *db.EndOfDayBrief) error {
+	message := &ChatMessage{
+		Cards: []ChatCard{c.createEndOfDayCard(brief)},
+	}
+
+	return c.Deliver(ctx, database, "end_of_day_brief", message)
+}
+
+// createEndOfDayCard creates the end-of-day shutdown card
+func (c *ChatClient) createEndOfDayCard(brief *db.EndOfDayBrief) ChatCard {
+	card := ChatCard{
+		Header: &CardHeader{
+			Title:    "End of Day",
+			Subtitle: fmt.Sprintf("Shutdown for %s", brief.Date.Format("Monday, January 2")),
+		},
+		Sections: []CardSection{},
+	}
+
+	taskSection := func(header string, tasks []*db.Task, describe func(*db.Task) string) {
+		if len(tasks) == 0 {
+			return
+		}
+		widgets := []CardWidget{}
+		for i, task := range tasks {
+			if i >= 8 {
+				widgets = append(widgets, CardWidget{
+					TextParagraph: &TextParagraph{Text: fmt.Sprintf("... and %d more", len(tasks)-8)},
+				})
+				break
+			}
+			widgets = append(widgets, CardWidget{
+				TextParagraph: &TextParagraph{Text: describe(task)},
+			})
+		}
+		card.Sections = append(card.Sections, CardSection{Header: header, Widgets: widgets})
+	}
+
+	taskSection(fmt.Sprintf("✅ Completed Today (%d)", len(brief.Completed)), brief.Completed, func(task *db.Task) string {
+		return "• " + task.Title
+	})
+	if len(brief.Plan) > 0 {
+		widgets := []CardWidget{}
+		for _, actual := range brief.Plan {
+			widgets = append(widgets, CardWidget{
+				TextParagraph: &TextParagraph{Text: "• " + actual.Describe()},
+			})
+		}
+		card.Sections = append(card.Sections, CardSection{Header: "📋 Plan vs Actual", Widgets: widgets})
+	}
+	taskSection("➡️ Slipping to Tomorrow", brief.Slipping, func(task *db.Task) string {
+		if task.DueTS != nil && task.DueTS.Before(brief.Date) {
+			return fmt.Sprintf("• %s (overdue since %s)", task.Title, task.DueTS.Format("Mon Jan 2"))
+		}
+		return "• " + task.Title
+	})
+
+	morning := []CardWidget{}
+	for _, event := range brief.TomorrowMorning {
+		morning = append(morning, CardWidget{
+			KeyValue: &KeyValue{
+				TopLabel: event.StartTS.Format("3:04 PM"),
+				Content:  event.Title,
+				Icon:     "CLOCK",
+			},
+		})
+	}
+	for _, task := range brief.DueTomorrowAM {
+		morning = append(morning, CardWidget{
+			TextParagraph: &TextParagraph{
+				Text: fmt.Sprintf("• %s (due %s)", task.Title, task.DueTS.Format("3:04 PM")),
+			},
+		})
+	}
+	if len(morning) > 0 {
+		card.Sections = append(card.Sections, CardSection{
+			Header:  "🌅 Tomorrow Morning",
+			Widgets: morning,
+		})
+	}
+
+	card.Sections = append(card.Sections, CardSection{
+		Header: "🌱 Tomorrow's Plan",
+		Widgets: []CardWidget{{
+			TextParagraph: &TextParagraph{Text: brief.PlanSeed},
+		}},
+	})
+
+	return card
+}
+
+// SendWeeklyReview sends the weekly review of completed work and misses
+func (c *ChatClient) SendWeeklyReview(ctx context.Context, database *db.DB, review *db.WeeklyReview) error {
+	message := &ChatMessage{
+		Cards: []ChatCard{c.createWeeklyReviewCard(review)},
+	}
+
+	return c.Deliver(ctx, database, "weekly_review", message)
+}
+
+// createWeeklyReviewCard creates the weekly review card
+func (c *ChatClient) createWeeklyReviewCard(review *db.WeeklyReview) ChatCard {
+	card := ChatCard{
+		Header: &CardHeader{
+			Title:    "Weekly Review",
+			Subtitle: fmt.Sprintf("%s – %s", review.Since.Format("Jan 2"), review.Until.Format("Jan 2")),
+		},
+		Sections: []CardSection{},
+	}
+
+	if review.Narrative != "" {
+		card.Sections = append(card.Sections, CardSection{
+			Widgets: []CardWidget{{
+				TextParagraph: &TextParagraph{Text: review.Narrative},
+			}},
+		})
+	}
+
+	list := func(header string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		widgets := []CardWidget{}
+		for i, line := range lines {
+			if i >= 8 {
+				widgets = append(widgets, CardWidget{
+					TextParagraph: &TextParagraph{Text: fmt.Sprintf("... and %d more", len(lines)-8)},
+				})
+				break
+			}
+			widgets = append(widgets, CardWidget{
+				TextParagraph: &TextParagraph{Text: line},
+			})
+		}
+		card.Sections = append(card.Sections, CardSection{Header: header, Widgets: widgets})
+	}
+
+	var completed, slipped, awaiting []string
+	for _, task := range review.Completed {
+		completed = append(completed, "• "+task.Title)
+	}
+	for _, task := range review.Slipped {
+		slipped = append(slipped, fmt.Sprintf("• %s (due %s)", task.Title, task.DueTS.Format("Mon Jan 2")))
+	}
+	for _, thread := range review.AwaitingReply {
+		awaiting = append(awaiting, fmt.Sprintf("• %s (from %s, %s)", thread.Subject, thread.From, thread.ReceivedAt.Format("Mon Jan 2")))
+	}
+	list(fmt.Sprintf("✅ Completed (%d)", len(review.Completed)), completed)
+	list(fmt.Sprintf("⚠️ Slipped Past Due (%d)", len(review.Slipped)), slipped)
+	list(fmt.Sprintf("📬 Awaiting Your Reply (%d)", len(review.AwaitingReply)), awaiting)
+
+	card.Sections = append(card.Sections, CardSection{
+		Header: "📅 Meetings",
+		Widgets: []CardWidget{{
+			TextParagraph: &TextParagraph{
+				Text: fmt.Sprintf("%d meetings, %.1f hours", review.MeetingCount, review.MeetingHours),
+			},
+		}},
+	})
+
+	if len(review.AutoArchived) > 0 {
+		card.Sections = append(card.Sections, CardSection{
+			Header: "🗄️ Auto-Archived",
+			Widgets: []CardWidget{{
+				TextParagraph: &TextParagraph{Text: db.FormatSourceCounts(review.AutoArchived)},
+			}},
+		})
+	}
+
+	if len(review.StaleProjects) > 0 {
+		var stale []string
+		for _, project := range review.StaleProjects {
+			stale = append(stale, fmt.Sprintf("• %s (%d open, quiet since %s)", project.Name, project.OpenTasks, project.LastActivity.Format("Jan 2")))
+		}
+		list(fmt.Sprintf("🌇 Consider Closing (%d)", len(review.StaleProjects)), stale)
+		card.Sections = append(card.Sections, CardSection{
+			Widgets: []CardWidget{{
+				TextParagraph: &TextParagraph{Text: "Archive a project and its remaining tasks with A on the Projects tab."},
+			}},
+		})
+	}
+
+	return card
+}
+
+// SendFollowUpReminder sends a follow-up reminder as a reply in the day's brief thread
+func (c *ChatClient) SendFollowUpReminder(ctx context.Context, database *db.DB, threads []*db.Thread) error {
 	if len(threads) == 0 {
 		return nil
 	}
@@ -453,7 +784,225 @@ func (c *ChatClient) SendFollowUpReminder(ctx context.Context, threads []*db.Thr
 		Text: text.String(),
 	}
 
-	return c.SendMessage(ctx, message)
+	return c.Deliver(ctx, database, "follow_up", message)
+}
+
+// SendWaitingReminder nudges the user about requests they sent that haven't had a reply
+func (c *ChatClient) SendWaitingReminder(ctx context.Context, database *db.DB, items []*db.WaitingItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	var text strings.Builder
+	text.WriteString("⏳ *Still Waiting On*\n\n")
+
+	for i, item := range items {
+		if i >= 10 {
+			text.WriteString(fmt.Sprintf("... and %d more\n", len(items)-10))
+			break
+		}
+		text.WriteString(fmt.Sprintf("• %s - %s, asked %s (%s)\n", item.Recipient, item.Request,
+			item.SentAt.Format("Mon Jan 2"), item.Subject))
+	}
+
+	return c.Deliver(ctx, database, "follow_up", &ChatMessage{Text: text.String()})
+}
+
+// SendGuardAlert warns that the database, its disk or a Google API quota is nearing its limit and
+// low-priority work has paused
+func (c *ChatClient) SendGuardAlert(ctx context.Context, database *db.DB, warnings []string) error {
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	var text strings.Builder
+	text.WriteString("⚠️ *Running Short on Resources*\n\n")
+	for _, warning := range warnings {
+		text.WriteString(fmt.Sprintf("• %s\n", warning))
+	}
+	text.WriteString("\nSearch embedding and task re-enrichment are paused until usage drops.\n")
+
+	return c.Deliver(ctx, database, "guard_alert", &ChatMessage{Text: text.String()})
+}
+
+// SendCanaryAlert reports the pipeline stages the nightly canary thread failed or that ran over
+// their latency budget
+func (c *ChatClient) SendCanaryAlert(ctx context.Context, database *db.DB, problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	var text strings.Builder
+	text.WriteString("🐤 *Pipeline Canary Failed*\n\n")
+	for _, problem := range problems {
+		text.WriteString(fmt.Sprintf("• %s\n", problem))
+	}
+	text.WriteString("\nNew email may not be turning into tasks; check the agent's logs.\n")
+
+	return c.Deliver(ctx, database, "canary_alert", &ChatMessage{Text: text.String()})
+}
+
+// SendEscalation chases an overdue high-impact task, with a suggested email to send once it's
+// reached the draft step
+func (c *ChatClient) SendEscalation(ctx context.Context, database *db.DB, escalation *db.TaskEscalation) error {
+	task := escalation.Task
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("🚨 *Overdue*: %s\n", task.Title))
+	text.WriteString(fmt.Sprintf("Was due %s", task.DueTS.Format("Mon Jan 2 at 3:04 PM")))
+	if task.Project != "" {
+		text.WriteString(fmt.Sprintf(" (%s)", task.Project))
+	}
+	text.WriteString("\n")
+
+	if escalation.Draft != "" {
+		text.WriteString(fmt.Sprintf("\n✉️ *Suggested note*:\n%s\n", escalation.Draft))
+		if escalation.DraftSaved {
+			text.WriteString("\nSaved as a Gmail draft on the thread.\n")
+		}
+	}
+
+	return c.Deliver(ctx, database, "escalation", &ChatMessage{Text: text.String()})
+}
+
+// SendWeeklyDigest sends the weekly "everything else" digest of low-priority tasks and FYI threads.
+// It gets its own thread so it never buries the daily brief.
+func (c *ChatClient) SendWeeklyDigest(ctx context.Context, database *db.DB, tasks []*db.Task, threads []*db.Thread, tagStats []*db.TagStats) error {
+	if len(tasks) == 0 && len(threads) == 0 && len(tagStats) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	year, week := now.ISOWeek()
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("📥 *Everything Else* - week %d\n", week))
+	text.WriteString(fmt.Sprintf("_%d low-priority tasks, %d FYI threads_\n\n", len(tasks), len(threads)))
+
+	if len(tasks) > 0 {
+		text.WriteString("*Low-priority tasks*\n")
+		for i, task := range tasks {
+			if i >= 10 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(tasks)-10))
+				break
+			}
+			text.WriteString(fmt.Sprintf("• %s (%.0f%%)\n", task.Title, task.Score))
+		}
+		text.WriteString("\n")
+	}
+
+	if len(threads) > 0 {
+		text.WriteString("*FYI threads*\n")
+		for i, thread := range threads {
+			if i >= 10 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(threads)-10))
+				break
+			}
+			summary := thread.Summary
+			if len(summary) > 120 {
+				summary = summary[:117] + "..."
+			}
+			text.WriteString(fmt.Sprintf("• %s\n", summary))
+		}
+		text.WriteString("\n")
+	}
+
+	if len(tagStats) > 0 {
+		text.WriteString("*By tag*\n")
+		for i, stat := range tagStats {
+			if i >= 10 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(tagStats)-10))
+				break
+			}
+			text.WriteString(fmt.Sprintf("• #%s: %d done, %d added, %d open\n", stat.Tag, stat.Completed, stat.Added, stat.Open))
+		}
+		text.WriteString("\n")
+	}
+
+	text.WriteString("Press *D* in the Tasks view to review and archive all of it in one go.")
+
+	message := &ChatMessage{
+		Text:   text.String(),
+		Thread: &ChatThread{ThreadKey: fmt.Sprintf("%s-digest-%d-W%02d", c.threadKeyPrefix(), year, week)},
+	}
+
+	return c.Deliver(ctx, database, "weekly_digest", message)
+}
+
+// SendRelationshipBrief sends a pre-meeting brief about an external contact.
+// Briefs for the same meeting share a thread.
+func (c *ChatClient) SendRelationshipBrief(ctx context.Context, database *db.DB, event *db.Event, history *db.ContactHistory, analysis string) error {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("🤝 *Before %s* - %s\n", event.Title, event.StartTS.Format("3:04 PM")))
+	text.WriteString(fmt.Sprintf("_Relationship brief: %s_\n\n", history.Email))
+
+	if analysis != "" {
+		text.WriteString(analysis + "\n\n")
+	}
+
+	text.WriteString("*Last interactions*\n")
+	if len(history.Interactions) == 0 {
+		text.WriteString("No email history yet\n")
+	}
+	for _, interaction := range history.Interactions {
+		text.WriteString(fmt.Sprintf("• %s - %s\n", interaction.LastTS.Format("Jan 2"), interaction.Subject))
+	}
+
+	if len(history.YouOwe) > 0 {
+		text.WriteString("\n*You owe them*\n")
+		for i, task := range history.YouOwe {
+			if i >= 5 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(history.YouOwe)-5))
+				break
+			}
+			text.WriteString(fmt.Sprintf("• %s\n", task.Title))
+		}
+	}
+
+	if len(history.TheyOwe) > 0 {
+		text.WriteString("\n*Waiting on them*\n")
+		for i, interaction := range history.TheyOwe {
+			if i >= 5 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(history.TheyOwe)-5))
+				break
+			}
+			text.WriteString(fmt.Sprintf("• %s (since %s)\n", interaction.Subject, interaction.LastTS.Format("Jan 2")))
+		}
+	}
+
+	message := &ChatMessage{
+		Text:   text.String(),
+		Thread: &ChatThread{ThreadKey: fmt.Sprintf("%s-meeting-%s", c.threadKeyPrefix(), event.ID)},
+	}
+
+	return c.Deliver(ctx, database, "relationship_brief", message)
+}
+
+// SendMeetingPrep sends a meeting's prep notes with links to its related documents, in the
+// same thread as the meeting's relationship briefs
+func (c *ChatClient) SendMeetingPrep(ctx context.Context, database *db.DB, prep *db.MeetingPrep, docs []*db.Document) error {
+	event := prep.Event
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("📋 *Prep: %s* - %s\n", event.Title, event.StartTS.Format("3:04 PM")))
+	if event.MeetingLink != "" {
+		text.WriteString(fmt.Sprintf("<%s|Join meeting>\n", event.MeetingLink))
+	}
+	text.WriteString("\n" + prep.Notes + "\n")
+
+	if len(docs) > 0 {
+		text.WriteString("\n*Related documents*\n")
+		for _, doc := range docs {
+			text.WriteString(fmt.Sprintf("• <%s|%s>\n", doc.Link, doc.Title))
+		}
+	}
+
+	message := &ChatMessage{
+		Text:   text.String(),
+		Thread: &ChatThread{ThreadKey: fmt.Sprintf("%s-meeting-%s", c.threadKeyPrefix(), event.ID)},
+	}
+
+	return c.Deliver(ctx, database, "meeting_prep", message)
 }
 
 // getPriorityIndicator returns an emoji indicator based on score
@@ -481,6 +1030,8 @@ func (c *ChatClient) getTaskSourceInfo(task *db.Task) (string, string, string) {
 		return "Gmail", "", ""
 	case "gtasks", "google_tasks":
 		return "Google Tasks", "https://tasks.google.com", "Open in Tasks"
+	case db.TaskSourceGoogleChat:
+		return "Google Chat", db.ChatThreadLink(task.SourceID), "Open in Chat"
 	default:
 		return humanizeSource(task.Source), "", ""
 	}
@@ -565,3 +1116,48 @@ func (c *ChatClient) generateStats(tasks []*db.Task, events []*db.Event) string
 
 	return stats.String()
 }
+
+// SendFocusSummary sends the exit summary of a focus session: what arrived while notifications were held
+func (c *ChatClient) SendFocusSummary(ctx context.Context, database *db.DB, summary *db.FocusSummary) error {
+	session := summary.Session
+	minutes := int(session.End().Sub(session.StartedAt).Minutes())
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("🎧 *Focus session over* - %d min\n", minutes))
+	text.WriteString(fmt.Sprintf("_%d email threads, %d new tasks while you were heads-down_\n\n", len(summary.Threads), len(summary.Tasks)))
+
+	if len(summary.Threads) > 0 {
+		text.WriteString("*Email*\n")
+		for i, thread := range summary.Threads {
+			if i >= 10 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(summary.Threads)-10))
+				break
+			}
+			text.WriteString(fmt.Sprintf("• %s - %s (%d)\n", thread.Subject, thread.From, thread.Messages))
+		}
+		text.WriteString("\n")
+	}
+
+	if len(summary.Tasks) > 0 {
+		text.WriteString("*New tasks*\n")
+		for i, task := range summary.Tasks {
+			if i >= 10 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(summary.Tasks)-10))
+				break
+			}
+			text.WriteString(fmt.Sprintf("• %s (%.0f%%)\n", task.Title, task.Score))
+		}
+		text.WriteString("\n")
+	}
+
+	if len(summary.Notifications) > 0 {
+		var held []string
+		for kind, count := range summary.Notifications {
+			held = append(held, fmt.Sprintf("%d %s", count, strings.ReplaceAll(kind, "_", " ")))
+		}
+		sort.Strings(held)
+		text.WriteString(fmt.Sprintf("Held notifications follow: %s\n", strings.Join(held, ", ")))
+	}
+
+	return c.Deliver(ctx, database, "focus_summary", &ChatMessage{Text: text.String()})
+}
@@ -0,0 +1,101 @@
+package google
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/idtoken"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// Functions behind the task buttons on interactive brief cards
+const (
+	ChatActionComplete = "complete_task"
+	ChatActionSnooze   = "snooze_task"
+	ChatActionOpen     = "open_task"
+)
+
+// chatIssuerEmail is the account Google Chat signs the tokens on app events with
+const chatIssuerEmail = "chat@system.gserviceaccount.com"
+
+// ChatEvent is an interaction event sent to the Chat app's HTTP endpoint
+type ChatEvent struct {
+	Type string `json:"type"` // CARD_CLICKED, MESSAGE, ADDED_TO_SPACE, ...
+	User struct {
+		Email       string `json:"email"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	Common struct {
+		InvokedFunction string            `json:"invokedFunction"`
+		Parameters      map[string]string `json:"parameters"`
+	} `json:"common"`
+	Message struct {
+		Text         string `json:"text"`
+		ArgumentText string `json:"argumentText"` // Text without the @mention of the app
+	} `json:"message"`
+}
+
+// ChatEventResponse is the synchronous reply to an interaction event
+type ChatEventResponse struct {
+	Text           string              `json:"text,omitempty"`
+	ActionResponse *ChatActionResponse `json:"actionResponse,omitempty"`
+}
+
+// ChatActionResponse says how a reply to a card click is posted
+type ChatActionResponse struct {
+	Type string `json:"type"` // NEW_MESSAGE or UPDATE_MESSAGE
+}
+
+// VerifyChatEventToken checks the bearer token on a Chat app event was issued by Google Chat for
+// the app's endpoint
+func VerifyChatEventToken(ctx context.Context, token, audience string) error {
+	payload, err := idtoken.Validate(ctx, token, audience)
+	if err != nil {
+		return fmt.Errorf("invalid token: %w", err)
+	}
+	if email, _ := payload.Claims["email"].(string); email != chatIssuerEmail {
+		return fmt.Errorf("token was not issued by Google Chat")
+	}
+	return nil
+}
+
+// createTaskActionsCard creates a card listing the top tasks with buttons to complete, snooze
+// or open each one
+func (c *ChatClient) createTaskActionsCard(tasks []*db.Task) ChatCard {
+	action := func(text, function, taskID string) Button {
+		return Button{TextButton: &TextButton{
+			Text: text,
+			OnClick: &OnClick{Action: &Action{
+				Function:   function,
+				Parameters: map[string]string{"task_id": taskID},
+			}},
+		}}
+	}
+
+	var widgets []CardWidget
+	for i, task := range tasks {
+		if i >= 5 {
+			break
+		}
+		widgets = append(widgets,
+			CardWidget{KeyValue: &KeyValue{
+				TopLabel:         c.getPriorityIndicator(task.Score),
+				Content:          task.Title,
+				ContentMultiline: true,
+			}},
+			CardWidget{Buttons: []Button{
+				action("Complete", ChatActionComplete, task.ID),
+				action("Snooze 1d", ChatActionSnooze, task.ID),
+				action("Open in TUI", ChatActionOpen, task.ID),
+			}},
+		)
+	}
+
+	return ChatCard{
+		Sections: []CardSection{{
+			Header:  "📋 Top Priority Tasks",
+			Widgets: widgets,
+		}},
+	}
+}
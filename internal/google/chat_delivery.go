@@ -0,0 +1,175 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/plaintext"
+)
+
+// chatChannel is the outbox channel name used for Google Chat deliveries
+const chatChannel = "chat"
+
+// threadKeyPrefix returns the configured Chat thread key prefix
+func (c *ChatClient) threadKeyPrefix() string {
+	if c.Config.Chat.ThreadKey == "" {
+		return "focus-agent"
+	}
+	return c.Config.Chat.ThreadKey
+}
+
+// dailyThreadKey returns the Chat thread key shared by all of today's brief messages
+func (c *ChatClient) dailyThreadKey(now time.Time) string {
+	return fmt.Sprintf("%s-%s", c.threadKeyPrefix(), now.Format("2006-01-02"))
+}
+
+// Deliver persists a message to the outbox, threads it under today's brief and attempts delivery.
+// A failed attempt leaves the message queued; RetryPending will deliver it later.
+func (c *ChatClient) Deliver(ctx context.Context, database *db.DB, kind string, message *ChatMessage) error {
+	if message.Thread == nil {
+		message.Thread = &ChatThread{ThreadKey: c.dailyThreadKey(time.Now())}
+	}
+	if c.Config.Notify.Plain(config.ChannelChat) {
+		plainMessage(message)
+	}
+
+	// Without a database there is nowhere to persist the message, so send directly
+	if database == nil {
+		return c.SendMessage(ctx, message)
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode chat message: %w", err)
+	}
+
+	item, err := database.EnqueueNotification(chatChannel, kind, string(payload), message.Thread.ThreadKey)
+	if err != nil {
+		// Persisting failed - still try to get the message out
+		log.Printf("Warning: failed to persist %s to outbox, sending directly: %v", kind, err)
+		return c.SendMessage(ctx, message)
+	}
+
+	// Quiet hours hold some kinds in the outbox until the quiet ends
+	if until, quiet := c.Config.Notify.QuietHours.Until(kind, time.Now().In(c.Config.Schedule.Location())); quiet {
+		if err := database.DeferNotification(item.ID, until); err != nil {
+			return err
+		}
+		log.Printf("Quiet hours, holding %s until %s", kind, until.Format("Mon 15:04"))
+		return nil
+	}
+
+	// Focus mode holds messages in the outbox until it ends
+	if database.InFocusMode() {
+		log.Printf("Focus mode active, holding %s until it ends", kind)
+		return nil
+	}
+
+	if err := c.deliverItem(ctx, database, item, message); err != nil {
+		return fmt.Errorf("failed to deliver %s (queued for retry): %w", kind, err)
+	}
+
+	return nil
+}
+
+// plainMessage rewrites a message's text and card labels with plain formatting
+func plainMessage(message *ChatMessage) {
+	message.Text = plaintext.Text(message.Text)
+	for i := range message.Cards {
+		card := &message.Cards[i]
+		if card.Header != nil {
+			card.Header.Title = plaintext.Text(card.Header.Title)
+			card.Header.Subtitle = plaintext.Text(card.Header.Subtitle)
+		}
+		for j := range card.Sections {
+			section := &card.Sections[j]
+			section.Header = plaintext.Text(section.Header)
+			for _, widget := range section.Widgets {
+				if widget.TextParagraph != nil {
+					widget.TextParagraph.Text = plaintext.Text(widget.TextParagraph.Text)
+				}
+				if widget.KeyValue != nil {
+					widget.KeyValue.TopLabel = plaintext.Text(widget.KeyValue.TopLabel)
+					widget.KeyValue.Content = plaintext.Text(widget.KeyValue.Content)
+					widget.KeyValue.BottomLabel = plaintext.Text(widget.KeyValue.BottomLabel)
+					plainButton(widget.KeyValue.Button)
+				}
+				for k := range widget.Buttons {
+					plainButton(&widget.Buttons[k])
+				}
+			}
+		}
+	}
+}
+
+func plainButton(button *Button) {
+	if button != nil && button.TextButton != nil {
+		button.TextButton.Text = plaintext.Text(button.TextButton.Text)
+	}
+}
+
+// RetryPending re-attempts delivery of queued Chat messages whose backoff has elapsed
+func (c *ChatClient) RetryPending(ctx context.Context, database *db.DB) (int, error) {
+	if database.InFocusMode() {
+		return 0, nil
+	}
+
+	items, err := database.GetDueNotifications(chatChannel, 20)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load pending notifications: %w", err)
+	}
+
+	sent := 0
+	for _, item := range items {
+		var message ChatMessage
+		if err := json.Unmarshal([]byte(item.Payload), &message); err != nil {
+			log.Printf("Dropping undecodable %s notification %s: %v", item.Kind, item.ID, err)
+			database.MarkNotificationFailed(item.ID, err, time.Now(), 0)
+			continue
+		}
+
+		if err := c.deliverItem(ctx, database, item, &message); err != nil {
+			log.Printf("Retry of %s notification %s failed (attempt %d): %v", item.Kind, item.ID, item.Attempts+1, err)
+			continue
+		}
+
+		log.Printf("Delivered queued %s notification %s after %d attempt(s)", item.Kind, item.ID, item.Attempts+1)
+		sent++
+	}
+
+	return sent, nil
+}
+
+// deliverItem sends one outbox message and records the outcome
+func (c *ChatClient) deliverItem(ctx context.Context, database *db.DB, item *db.OutboxItem, message *ChatMessage) error {
+	name, err := c.sendMessage(ctx, message)
+	if err != nil {
+		nextAttempt := time.Now().Add(retryBackoff(item.Attempts + 1))
+		if markErr := database.MarkNotificationFailed(item.ID, err, nextAttempt, c.Config.Chat.MaxDeliveryAttempts); markErr != nil {
+			log.Printf("Warning: failed to record delivery failure for %s: %v", item.ID, markErr)
+		}
+		return err
+	}
+
+	if err := database.MarkNotificationSent(item.ID, name); err != nil {
+		log.Printf("Warning: failed to record delivery of %s: %v", item.ID, err)
+	}
+	return nil
+}
+
+// retryBackoff returns an exponential delay (1m, 2m, 4m, ...) capped at one hour
+func retryBackoff(attempts int) time.Duration {
+	if attempts > 6 {
+		return time.Hour
+	}
+	delay := time.Minute << uint(attempts-1)
+	if delay > time.Hour {
+		delay = time.Hour
+	}
+	return delay
+}
@@ -0,0 +1,199 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"google.golang.org/api/chat/v1"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// SyncMessages reads new messages from the spaces under chat.sync, and every DM and group DM the
+// user is in with chat.sync.direct_messages, into chat_messages. The first sync of a space reads
+// back chat.sync.lookback_hours. The agent's own DM, where briefs are posted, is never read.
+func (c *ChatClient) SyncMessages(ctx context.Context, database *db.DB) error {
+	spaces, err := c.syncSpaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	userID, err := c.userID(ctx, spaces)
+	if err != nil {
+		// Messages can still be read, only not told apart from the user's own
+		log.Printf("Warning: %v", err)
+	}
+
+	total := 0
+	var errs []string
+	for _, space := range spaces {
+		count, err := c.syncSpace(ctx, database, space, userID)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", space.Name, err))
+			continue
+		}
+		total += count
+	}
+
+	log.Printf("Chat sync completed: %d new messages from %d spaces", total, len(spaces))
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to read Chat messages: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// syncSpaces returns the spaces to read: those listed in chat.sync.spaces, then the user's DMs
+func (c *ChatClient) syncSpaces(ctx context.Context) ([]*chat.Space, error) {
+	skip := map[string]bool{}
+	if configured := strings.TrimSpace(c.Config.Chat.SpaceID); configured != "" {
+		skip[chatSpaceName(configured)] = true
+	}
+	if c.dmSpace != "" {
+		skip[c.dmSpace] = true
+	}
+
+	var spaces []*chat.Space
+	seen := map[string]bool{}
+	for _, name := range c.Config.Chat.Sync.Spaces {
+		name = chatSpaceName(name)
+		if name == "" || skip[name] || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		space, err := c.Service.Spaces.Get(name).Context(ctx).Do()
+		if err != nil {
+			log.Printf("Failed to get Chat space %s, reading it without its name: %v", name, err)
+			space = &chat.Space{Name: name}
+		}
+		spaces = append(spaces, space)
+	}
+
+	if !c.Config.Chat.Sync.DirectMessages {
+		return spaces, nil
+	}
+
+	pageToken := ""
+	for {
+		call := c.Service.Spaces.List().
+			Filter(`spaceType = "DIRECT_MESSAGE" OR spaceType = "GROUP_CHAT"`).
+			PageSize(100)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Context(ctx).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list direct messages: %w", err)
+		}
+
+		for _, space := range resp.Spaces {
+			// DMs with apps, this one included, only carry notifications
+			if space.SingleUserBotDm || skip[space.Name] || seen[space.Name] {
+				continue
+			}
+			seen[space.Name] = true
+			spaces = append(spaces, space)
+		}
+
+		if resp.NextPageToken == "" {
+			return spaces, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// userID returns the user's Chat ID (users/{id}), which is how their own messages are marked,
+// from their membership of the first space that has it
+func (c *ChatClient) userID(ctx context.Context, spaces []*chat.Space) (string, error) {
+	if c.chatUserID != "" {
+		return c.chatUserID, nil
+	}
+
+	userEmail := strings.ToLower(c.Config.Google.UserEmail)
+	if userEmail == "" {
+		return "", fmt.Errorf("google user email is not configured, so your own Chat messages can't be recognised")
+	}
+	for _, space := range spaces {
+		membership, err := c.Service.Spaces.Members.Get(fmt.Sprintf("%s/members/users/%s", space.Name, url.PathEscape(userEmail))).Context(ctx).Do()
+		if err != nil || membership.Member == nil {
+			continue
+		}
+		c.chatUserID = membership.Member.Name
+		return c.chatUserID, nil
+	}
+	return "", fmt.Errorf("failed to find your Chat membership, so your own Chat messages can't be recognised")
+}
+
+// syncSpace stores the messages sent in a space since the newest one stored, and returns how
+// many there were. Messages from apps and ones without text are skipped.
+func (c *ChatClient) syncSpace(ctx context.Context, database *db.DB, space *chat.Space, userID string) (int, error) {
+	since, err := database.GetLatestChatMessageTime(space.Name)
+	if err != nil {
+		return 0, err
+	}
+	if since.IsZero() {
+		since = time.Now().Add(-time.Duration(c.Config.Chat.Sync.LookbackHours) * time.Hour)
+	}
+
+	count := 0
+	pageToken := ""
+	for {
+		call := c.Service.Spaces.Messages.List(space.Name).
+			Filter(fmt.Sprintf(`create_time > "%s"`, since.UTC().Format(time.RFC3339))).
+			PageSize(100)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Context(ctx).Do()
+		if err != nil {
+			return count, fmt.Errorf("failed to list messages: %w", err)
+		}
+
+		for _, message := range resp.Messages {
+			text := strings.TrimSpace(message.Text)
+			if text == "" || message.Sender == nil || message.Sender.Type == "BOT" {
+				continue
+			}
+			created, err := time.Parse(time.RFC3339, message.CreateTime)
+			if err != nil {
+				continue
+			}
+
+			msg := &db.ChatMessage{
+				ID:         message.Name,
+				Space:      space.Name,
+				SpaceName:  space.DisplayName,
+				Sender:     message.Sender.Name,
+				SenderName: message.Sender.DisplayName,
+				FromUser:   userID != "" && message.Sender.Name == userID,
+				Text:       text,
+				Timestamp:  created,
+			}
+			if message.Thread != nil {
+				msg.Thread = message.Thread.Name
+			}
+			if err := database.SaveChatMessage(msg); err != nil {
+				return count, err
+			}
+			count++
+		}
+
+		if resp.NextPageToken == "" {
+			return count, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// chatSpaceName turns a space ID into its resource name, leaving resource names as they are
+func chatSpaceName(space string) string {
+	space = strings.TrimSpace(space)
+	if space == "" || strings.HasPrefix(space, "spaces/") {
+		return space
+	}
+	return "spaces/" + space
+}
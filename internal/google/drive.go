@@ -2,8 +2,11 @@ package google
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"strings"
 	"time"
@@ -106,7 +109,7 @@ func (d *DriveClient) fullSync(ctx context.Context, database *db.DB, state *Driv
 		call := d.Service.Files.List().
 			Context(ctx).
 			Q(query).
-			Fields("nextPageToken, files(id, name, mimeType, webViewLink, owners, modifiedTime)").
+			Fields("nextPageToken, files(id, name, mimeType, webViewLink, owners, lastModifyingUser, modifiedTime)").
 			PageSize(100)
 
 		if pageToken != "" {
@@ -168,7 +171,7 @@ func (d *DriveClient) incrementalSync(ctx context.Context, database *db.DB, stat
 	for {
 		call := d.Service.Changes.List(pageToken).
 			Context(ctx).
-			Fields("nextPageToken, newStartPageToken, changes(file(id, name, mimeType, webViewLink, owners, modifiedTime))").
+			Fields("nextPageToken, newStartPageToken, changes(file(id, name, mimeType, webViewLink, owners, lastModifyingUser, modifiedTime))").
 			PageSize(100)
 
 		resp, err := call.Do()
@@ -211,6 +214,10 @@ func (d *DriveClient) processFile(ctx context.Context, database *db.DB, file *dr
 	if len(file.Owners) > 0 {
 		owner = file.Owners[0].EmailAddress
 	}
+	modifiedBy := ""
+	if file.LastModifyingUser != nil {
+		modifiedBy = file.LastModifyingUser.EmailAddress
+	}
 
 	// Parse modified time
 	modifiedTime, err := time.Parse(time.RFC3339, file.ModifiedTime)
@@ -225,6 +232,7 @@ func (d *DriveClient) processFile(ctx context.Context, database *db.DB, file *dr
 		Link:       file.WebViewLink,
 		MimeType:   file.MimeType,
 		Owner:      owner,
+		ModifiedBy: modifiedBy,
 		UpdatedTS:  modifiedTime,
 		LastSynced: time.Now(),
 	}
@@ -251,6 +259,62 @@ func (d *DriveClient) GetDocument(ctx context.Context, fileID string) (*drive.Fi
 	return file, nil
 }
 
+// ExportText exports a Google Doc, Sheet or Slides file as text (Sheets as CSV), reading at most
+// limit bytes
+func (d *DriveClient) ExportText(ctx context.Context, fileID string, limit int64) (*drive.File, string, error) {
+	file, err := d.GetDocument(ctx, fileID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	exportType := "text/plain"
+	if file.MimeType == "application/vnd.google-apps.spreadsheet" {
+		exportType = "text/csv"
+	}
+
+	resp, err := d.Service.Files.Export(fileID, exportType).Context(ctx).Download()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to export document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read exported document: %w", err)
+	}
+	return file, strings.TrimSpace(strings.ToValidUTF8(string(data), "")), nil
+}
+
+// maxInboxDocBytes caps how much of the inbox doc is read
+const maxInboxDocBytes = 1 << 20
+
+// InboxNotes reads the Google Doc used as a capture inbox, returning each paragraph (notes are
+// separated by a blank line) as a note identified by the doc and a hash of its text, so a note
+// edited later is captured again
+func (d *DriveClient) InboxNotes(ctx context.Context, docID string) ([]*db.CapturedNote, error) {
+	_, text, err := d.ExportText(ctx, docID, maxInboxDocBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []*db.CapturedNote
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		hash := sha256.Sum256([]byte(paragraph))
+		title, _, _ := strings.Cut(paragraph, "\n")
+		notes = append(notes, &db.CapturedNote{
+			ID:     docID + ":" + hex.EncodeToString(hash[:8]),
+			Source: db.NoteSourceInboxDoc,
+			Title:  title,
+			Text:   paragraph,
+		})
+	}
+	return notes, nil
+}
+
 // SearchDocuments searches for documents by query
 func (d *DriveClient) SearchDocuments(ctx context.Context, query string, maxResults int64) ([]*drive.File, error) {
 	resp, err := d.Service.Files.List().
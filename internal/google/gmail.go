@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"mime"
 	"strings"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 type GmailClient struct {
 	Service *gmail.Service
 	Config  *config.Config
+	Drive   *DriveClient // Exports Google Docs linked from mail (google.attachments)
 }
 
 // GmailSyncState stores Gmail-specific sync state
@@ -320,6 +322,10 @@ func (g *GmailClient) processMessage(ctx context.Context, database *db.DB, msg *
 		return fmt.Errorf("failed to save message: %w", err)
 	}
 
+	if g.Config.Google.Attachments.Enabled {
+		g.ingestAttachments(ctx, database, msg, threadID, body)
+	}
+
 	return nil
 }
 
@@ -385,14 +391,14 @@ func (g *GmailClient) GetThread(ctx context.Context, threadID string) (*gmail.Th
 	return thread, nil
 }
 
-// SendMessage sends an email message
-func (g *GmailClient) SendMessage(ctx context.Context, to, subject, body string, threadID string) error {
+// SendMessage sends an email message, returning the sent message's ID and thread ID
+func (g *GmailClient) SendMessage(ctx context.Context, to, subject, body string, threadID string) (*gmail.Message, error) {
 	var message gmail.Message
 
 	// Create RFC 2822 formatted message
 	var msgStr strings.Builder
 	msgStr.WriteString(fmt.Sprintf("To: %s\r\n", to))
-	msgStr.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
+	msgStr.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject)))
 	if threadID != "" {
 		msgStr.WriteString(fmt.Sprintf("In-Reply-To: %s\r\n", threadID))
 		msgStr.WriteString(fmt.Sprintf("References: %s\r\n", threadID))
@@ -410,8 +416,28 @@ func (g *GmailClient) SendMessage(ctx context.Context, to, subject, body string,
 	}
 
 	// Send message
-	_, err := g.Service.Users.Messages.Send("me", &message).Context(ctx).Do()
+	sent, err := g.Service.Users.Messages.Send("me", &message).Context(ctx).Do()
 	if err != nil {
+		return nil, fmt.Errorf("failed to send message: %w", err)
+	}
+
+	return sent, nil
+}
+
+// SendHTMLMessage sends an HTML email message
+func (g *GmailClient) SendHTMLMessage(ctx context.Context, to, subject, htmlBody string) error {
+	var msgStr strings.Builder
+	msgStr.WriteString(fmt.Sprintf("To: %s\r\n", to))
+	msgStr.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject)))
+	msgStr.WriteString("MIME-Version: 1.0\r\n")
+	msgStr.WriteString("Content-Type: text/html; charset=\"UTF-8\"\r\n")
+	msgStr.WriteString("\r\n")
+	msgStr.WriteString(htmlBody)
+
+	message := &gmail.Message{
+		Raw: base64.URLEncoding.EncodeToString([]byte(msgStr.String())),
+	}
+	if _, err := g.Service.Users.Messages.Send("me", message).Context(ctx).Do(); err != nil {
 		return fmt.Errorf("failed to send message: %w", err)
 	}
 
@@ -455,6 +481,59 @@ func (g *GmailClient) CreateDraft(ctx context.Context, to, subject, body string,
 	return createdDraft, nil
 }
 
+// EnsureLabel returns the ID of the user label with the given name, creating it if needed
+func (g *GmailClient) EnsureLabel(ctx context.Context, name string) (string, error) {
+	labels, err := g.Service.Users.Labels.List("me").Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to list labels: %w", err)
+	}
+	for _, label := range labels.Labels {
+		if strings.EqualFold(label.Name, name) {
+			return label.Id, nil
+		}
+	}
+
+	label, err := g.Service.Users.Labels.Create("me", &gmail.Label{
+		Name:                  name,
+		LabelListVisibility:   "labelShow",
+		MessageListVisibility: "show",
+	}).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to create label %q: %w", name, err)
+	}
+	log.Printf("Created Gmail label %q", name)
+	return label.Id, nil
+}
+
+// SetThreadLabel adds or removes a label on every message in a thread
+func (g *GmailClient) SetThreadLabel(ctx context.Context, threadID, labelID string, apply bool) error {
+	req := &gmail.ModifyThreadRequest{}
+	if apply {
+		req.AddLabelIds = []string{labelID}
+	} else {
+		req.RemoveLabelIds = []string{labelID}
+	}
+
+	if _, err := g.Service.Users.Threads.Modify("me", threadID, req).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to modify labels on thread %s: %w", threadID, err)
+	}
+	return nil
+}
+
+// IsGmailThreadID reports whether an ID looks like a Gmail thread ID. Threads synced from
+// other mailboxes (Outlook conversation IDs) share the threads table but can't be labelled.
+func IsGmailThreadID(id string) bool {
+	if id == "" || len(id) > 20 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
 // Helper function to parse uint64
 func parseUint64(s string) (uint64, error) {
 	var result uint64
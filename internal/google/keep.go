@@ -0,0 +1,146 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"google.golang.org/api/keep/v1"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// KeepClient handles Google Keep API operations
+type KeepClient struct {
+	Service *keep.Service
+	Config  *config.Config
+}
+
+// noteSettleTime is how long a note must go unedited before it's captured, so a note still being
+// written on the phone isn't captured half-finished
+const noteSettleTime = 5 * time.Minute
+
+// KeepSyncState stores Keep-specific sync state
+type KeepSyncState struct {
+	Since string `json:"since"` // RFC 3339; notes created before capture was turned on are left alone
+}
+
+// NewNotes returns the notes created since capture was turned on that aren't in the trash, with
+// their text and unchecked list items. The first call only records when capture started.
+func (k *KeepClient) NewNotes(ctx context.Context, database *db.DB) ([]*db.CapturedNote, error) {
+	syncState, err := database.GetSyncState("keep")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync state: %w", err)
+	}
+
+	var state KeepSyncState
+	if syncState.State != "" && syncState.State != "{}" {
+		if err := json.Unmarshal([]byte(syncState.State), &state); err != nil {
+			log.Printf("Warning: invalid Keep sync state, starting over: %v", err)
+		}
+	}
+	since, err := time.Parse(time.RFC3339, state.Since)
+	first := err != nil
+
+	var notes []*db.CapturedNote
+	if first {
+		since = time.Now().UTC()
+		state.Since = since.Format(time.RFC3339)
+		log.Printf("Capturing Keep notes created from now on")
+	} else {
+		notes, err = k.listNotes(ctx, since)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stateJSON, _ := json.Marshal(state)
+	syncState.State = string(stateJSON)
+	syncState.LastSync = time.Now()
+	syncState.NextSync = time.Now().Add(time.Duration(k.Config.Google.PollingMinutes.Capture) * time.Minute)
+	if err := database.SaveSyncState(syncState); err != nil {
+		return nil, fmt.Errorf("failed to save sync state: %w", err)
+	}
+	return notes, nil
+}
+
+// listNotes returns the notes created since a time that aren't in the trash or still being edited
+func (k *KeepClient) listNotes(ctx context.Context, since time.Time) ([]*db.CapturedNote, error) {
+	var notes []*db.CapturedNote
+	pageToken := ""
+	for {
+		call := k.Service.Notes.List().Context(ctx).Filter("trashed = false").PageSize(100)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Keep notes: %w", err)
+		}
+
+		for _, note := range resp.Notes {
+			created, err := time.Parse(time.RFC3339, note.CreateTime)
+			if note.Trashed || err != nil || created.Before(since) {
+				continue
+			}
+			if updated, err := time.Parse(time.RFC3339, note.UpdateTime); err == nil && time.Since(updated) < noteSettleTime {
+				continue
+			}
+			text := noteText(note)
+			if text == "" {
+				continue
+			}
+			notes = append(notes, &db.CapturedNote{
+				ID:     note.Name,
+				Source: db.NoteSourceKeep,
+				Title:  note.Title,
+				Text:   text,
+			})
+		}
+
+		if resp.NextPageToken == "" {
+			return notes, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// TrashNote moves a Keep note to the trash, where Keep keeps it for a week
+func (k *KeepClient) TrashNote(ctx context.Context, name string) error {
+	if _, err := k.Service.Notes.Delete(name).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to trash Keep note: %w", err)
+	}
+	return nil
+}
+
+// noteText flattens a note into text: its title, then its body or unchecked list items
+func noteText(note *keep.Note) string {
+	var text strings.Builder
+	if note.Title != "" {
+		text.WriteString(note.Title + "\n")
+	}
+	if note.Body != nil {
+		if note.Body.Text != nil {
+			text.WriteString(note.Body.Text.Text)
+		}
+		if note.Body.List != nil {
+			writeListItems(&text, note.Body.List.ListItems, "")
+		}
+	}
+	return strings.TrimSpace(text.String())
+}
+
+// writeListItems writes unchecked list items as bullets, nesting child items
+func writeListItems(text *strings.Builder, items []*keep.ListItem, indent string) {
+	for _, item := range items {
+		if item.Checked || item.Text == nil {
+			continue
+		}
+		text.WriteString(indent + "- " + item.Text.Text + "\n")
+		writeListItems(text, item.ChildListItems, indent+"  ")
+	}
+}
@@ -0,0 +1,90 @@
+package google
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/metrics"
+)
+
+// quotaZone is where Google's daily API quotas reset at midnight
+const quotaZone = "America/Los_Angeles"
+
+// quotaDay returns the start of the quota day containing t
+func quotaDay(t time.Time) time.Time {
+	if loc, err := time.LoadLocation(quotaZone); err == nil {
+		t = t.In(loc)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// callCounter counts the requests made to each Google API since its quota last reset, so the
+// resource guards can pause low-priority work before a quota runs out. Counts are kept in
+// memory and start again from zero when the agent restarts.
+type callCounter struct {
+	base http.RoundTripper
+
+	mu    sync.Mutex
+	day   time.Time
+	calls map[string]int
+}
+
+func (c *callCounter) RoundTrip(req *http.Request) (*http.Response, error) {
+	service := apiService(req.URL)
+
+	c.mu.Lock()
+	if day := quotaDay(time.Now()); !day.Equal(c.day) {
+		c.day, c.calls = day, make(map[string]int)
+	}
+	c.calls[service]++
+	c.mu.Unlock()
+
+	metrics.GoogleAPICalls.Inc(service)
+	return c.base.RoundTrip(req)
+}
+
+// today returns the requests made to each API in the current quota day
+func (c *callCounter) today() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	calls := make(map[string]int, len(c.calls))
+	if quotaDay(time.Now()).Equal(c.day) {
+		for service, n := range c.calls {
+			calls[service] = n
+		}
+	}
+	return calls
+}
+
+// apiService names the API a request goes to: gmail.googleapis.com is gmail, and
+// www.googleapis.com/calendar/v3/... is calendar
+func apiService(u *url.URL) string {
+	host := u.Hostname()
+	name, ok := strings.CutSuffix(host, ".googleapis.com")
+	if !ok {
+		return host
+	}
+	if name != "www" {
+		return name
+	}
+
+	for _, segment := range strings.Split(strings.Trim(u.Path, "/"), "/") {
+		if segment != "upload" && segment != "batch" && segment != "" {
+			return segment
+		}
+	}
+	return host
+}
+
+// APICallsToday returns how many requests each Google API has been sent since its daily quota
+// last reset, keyed by service, e.g. gmail or tasks
+func (c *Clients) APICallsToday() map[string]int {
+	if c.calls == nil {
+		return map[string]int{}
+	}
+	return c.calls.today()
+}
@@ -0,0 +1,241 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// alignmentCacheTTL is how long strategic alignment results are cached. They're keyed by the
+// priorities, so they only go stale when the task itself is edited.
+const alignmentCacheTTL = 7 * 24 * time.Hour
+
+// alignmentBatchSchema is the structured output schema for batched strategic alignment with Gemini
+func alignmentBatchSchema() *genai.Schema {
+	return &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"results": {
+				Type: genai.TypeArray,
+				Items: &genai.Schema{
+					Type: genai.TypeObject,
+					Properties: map[string]*genai.Schema{
+						"task":            {Type: genai.TypeInteger, Description: "Task number, from 1"},
+						"score":           {Type: genai.TypeNumber, Description: "Strategic alignment score from 0.0 to 5.0"},
+						"okrs":            {Type: genai.TypeArray, Items: &genai.Schema{Type: genai.TypeString}},
+						"focus_areas":     {Type: genai.TypeArray, Items: &genai.Schema{Type: genai.TypeString}},
+						"projects":        {Type: genai.TypeArray, Items: &genai.Schema{Type: genai.TypeString}},
+						"key_stakeholder": {Type: genai.TypeBoolean},
+						"reasoning":       {Type: genai.TypeString},
+					},
+					Required: []string{"task", "score", "okrs", "focus_areas", "projects", "reasoning"},
+				},
+			},
+		},
+		Required: []string{"results"},
+	}
+}
+
+// alignmentBatchGenerator sends a batched strategic alignment prompt to an LLM and returns the
+// raw response
+type alignmentBatchGenerator func(ctx context.Context, prompt string) (string, error)
+
+// alignmentEvaluator evaluates a single task, the fallback for tasks a batch response left out
+type alignmentEvaluator func(ctx context.Context, task *db.Task, priorities *config.Priorities) (*StrategicAlignmentResult, error)
+
+// evaluateAlignmentBatch evaluates the strategic alignment of several tasks. Results are cached
+// per task under the same key as EvaluateStrategicAlignment, so single and batched evaluations
+// share the cache and only uncached tasks go into the batch prompt. Tasks missing from the batch
+// response are evaluated one at a time. A nil result means the task couldn't be evaluated; the
+// error is the last failure.
+func (g *GeminiClient) evaluateAlignmentBatch(ctx context.Context, tasks []*db.Task, priorities *config.Priorities,
+	generate alignmentBatchGenerator, single alignmentEvaluator) ([]*StrategicAlignmentResult, error) {
+	results := make([]*StrategicAlignmentResult, len(tasks))
+	keys := make([]string, len(tasks))
+	prompts := make([]string, len(tasks))
+
+	var pending []int
+	for i, task := range tasks {
+		prompts[i] = g.prompts.BuildStrategicAlignment(task, priorities)
+		keys[i] = g.alignmentCacheKey(prompts[i], priorities)
+		if cached, err := g.db.GetCachedResponse(keys[i]); err == nil && cached != nil {
+			results[i] = g.parseStrategicAlignmentResponse(cached.Response)
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	if len(pending) > 1 {
+		batch := make([]*db.Task, len(pending))
+		for j, i := range pending {
+			batch[j] = tasks[i]
+		}
+
+		response, err := generate(ctx, g.prompts.BuildStrategicAlignmentBatch(batch, priorities))
+		if err != nil {
+			log.Printf("Batched strategic alignment failed for %d tasks, evaluating them one at a time: %v", len(batch), err)
+		} else {
+			for j, result := range parseStrategicAlignmentBatch(response, len(batch)) {
+				if result == nil {
+					continue
+				}
+				i := pending[j]
+				results[i] = result
+
+				resultJSON, _ := json.Marshal(result)
+				g.db.SaveCachedResponse(&db.LLMCache{
+					Hash:      keys[i],
+					Prompt:    prompts[i],
+					Response:  string(resultJSON),
+					Model:     "batch",
+					Tokens:    g.estimateTokens(prompts[i] + string(resultJSON)),
+					ExpiresAt: time.Now().Add(alignmentCacheTTL),
+				})
+			}
+		}
+	}
+
+	var lastErr error
+	for _, i := range pending {
+		if results[i] != nil {
+			continue
+		}
+		result, err := single(ctx, tasks[i], priorities)
+		if err != nil {
+			log.Printf("Failed to evaluate strategic alignment for task %s: %v", tasks[i].ID, err)
+			lastErr = err
+			continue
+		}
+		results[i] = result
+	}
+
+	return results, lastErr
+}
+
+// parseStrategicAlignmentBatch parses a batched strategic alignment response into one result per
+// task, in task order. Tasks the response doesn't cover are left nil.
+func parseStrategicAlignmentBatch(response string, count int) []*StrategicAlignmentResult {
+	results := make([]*StrategicAlignmentResult, count)
+
+	type batchResult struct {
+		Task int `json:"task"`
+		StrategicAlignmentResult
+	}
+
+	// Find the JSON in the response, tolerating markdown fences and prose around it
+	var entries []batchResult
+	for i := 0; i < len(response) && entries == nil; i++ {
+		switch response[i] {
+		case '{':
+			var decoded struct {
+				Results []batchResult `json:"results"`
+			}
+			if json.NewDecoder(strings.NewReader(response[i:])).Decode(&decoded) == nil && decoded.Results != nil {
+				entries = decoded.Results
+			}
+		case '[':
+			var decoded []batchResult
+			if json.NewDecoder(strings.NewReader(response[i:])).Decode(&decoded) == nil {
+				entries = decoded
+			}
+		}
+	}
+	if entries == nil {
+		log.Printf("Failed to parse batched strategic alignment response: %s", excerpt(response, 200))
+		return results
+	}
+
+	for _, entry := range entries {
+		if entry.Task < 1 || entry.Task > count || results[entry.Task-1] != nil {
+			continue
+		}
+		result := entry.StrategicAlignmentResult
+		if result.OKRs == nil {
+			result.OKRs = []string{}
+		}
+		if result.FocusAreas == nil {
+			result.FocusAreas = []string{}
+		}
+		if result.Projects == nil {
+			result.Projects = []string{}
+		}
+		if result.Score < 0 {
+			result.Score = 0
+		} else if result.Score > 5 {
+			result.Score = 5
+		}
+		results[entry.Task-1] = &result
+	}
+
+	return results
+}
+
+// EvaluateStrategicAlignmentBatch evaluates several tasks against the priorities in one call
+func (g *GeminiClient) EvaluateStrategicAlignmentBatch(ctx context.Context, tasks []*db.Task, priorities *config.Priorities) ([]*StrategicAlignmentResult, error) {
+	return g.evaluateAlignmentBatch(ctx, tasks, priorities, g.generateAlignmentBatch, g.EvaluateStrategicAlignment)
+}
+
+// generateAlignmentBatch sends a batched strategic alignment prompt to Gemini
+func (g *GeminiClient) generateAlignmentBatch(ctx context.Context, prompt string) (string, error) {
+	if err := g.rateLimiter.Wait(ctx); err != nil {
+		return "", fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	startTime := time.Now()
+	resp, key, err := g.generateWithRetryForModel(ctx, genai.Text(prompt), g.jsonModel(alignmentBatchSchema()))
+	if err != nil {
+		g.db.LogKeyUsage("gemini", key, "strategic_alignment_batch", 0, 0, time.Since(startTime), err)
+		return "", fmt.Errorf("failed to evaluate strategic alignment: %w", err)
+	}
+
+	text := g.extractText(resp)
+	g.logUsage(key, "strategic_alignment_batch", g.modelName, resp, prompt, text, startTime)
+	return text, nil
+}
+
+// EvaluateStrategicAlignmentBatch evaluates several tasks against the priorities in one call,
+// using the configured provider order
+func (h *HybridClient) EvaluateStrategicAlignmentBatch(ctx context.Context, tasks []*db.Task, priorities *config.Priorities) ([]*StrategicAlignmentResult, error) {
+	return h.gemini.evaluateAlignmentBatch(ctx, tasks, priorities, h.generateAlignmentBatch, h.EvaluateStrategicAlignment)
+}
+
+// generateAlignmentBatch sends a batched strategic alignment prompt to the first provider that
+// answers
+func (h *HybridClient) generateAlignmentBatch(ctx context.Context, prompt string) (string, error) {
+	var response string
+	err := h.tryProviders("EvaluateStrategicAlignmentBatch", map[string]func() error{
+		config.ProviderOllama: func() error {
+			// EnrichTaskDescription is a plain JSON-mode generation on the Ollama side
+			startTime := time.Now()
+			result, err := h.ollama.EnrichTaskDescription(ctx, prompt)
+			if err != nil {
+				return err
+			}
+			h.logOllamaUsage("strategic_alignment_batch", prompt, result, startTime)
+			response = result
+			return nil
+		},
+		config.ProviderClaude: func() error {
+			result, err := h.callClaude(ctx, "strategic_alignment_batch", prompt+"\n\nIMPORTANT: Respond with ONLY the JSON object, no markdown formatting or explanation.")
+			if err != nil {
+				return err
+			}
+			response = result
+			return nil
+		},
+		config.ProviderGemini: func() error {
+			result, err := h.gemini.generateAlignmentBatch(ctx, prompt)
+			response = result
+			return err
+		},
+	})
+	return response, err
+}
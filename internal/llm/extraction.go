@@ -0,0 +1,199 @@
+package llm
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// taskOutputFormat is the output section shared by every task extraction prompt. Gemini and
+// Ollama are also held to taskResponseSchema; the Claude CLI relies on the prompt alone.
+const taskOutputFormat = `Respond with ONLY a JSON object in this shape, with no markdown or explanation:
+{"tasks": [{"title": "Review Q4 budget variance report", "due": "Friday", "impact": 4, "urgency": 3, "effort": "M", "stakeholder": "Sarah Chen", "project": "Q4 planning"}]}
+
+- impact and urgency are numbers from 1 to 5; effort is "S", "M" or "L"
+- Use "" for due, stakeholder or project when there is none
+- If there are no actionable tasks, respond with {"tasks": []}`
+
+// taskResponseSchema is the structured output schema for task extraction with Gemini
+func taskResponseSchema() *genai.Schema {
+	return &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"tasks": {
+				Type: genai.TypeArray,
+				Items: &genai.Schema{
+					Type: genai.TypeObject,
+					Properties: map[string]*genai.Schema{
+						"title":       {Type: genai.TypeString, Description: "Action verb + object, 20-60 characters"},
+						"due":         {Type: genai.TypeString, Description: "Deadline in natural language, or empty"},
+						"impact":      {Type: genai.TypeInteger, Description: "Business impact from 1 to 5"},
+						"urgency":     {Type: genai.TypeInteger, Description: "Time sensitivity from 1 to 5"},
+						"effort":      {Type: genai.TypeString, Enum: []string{"S", "M", "L"}},
+						"stakeholder": {Type: genai.TypeString, Description: "Full name of the person who needs this done, or empty"},
+						"project":     {Type: genai.TypeString, Description: "Related project or initiative, or empty"},
+					},
+					Required: []string{"title", "due", "impact", "urgency", "effort", "stakeholder", "project"},
+				},
+			},
+		},
+		Required: []string{"tasks"},
+	}
+}
+
+// taskJSONSchema is taskResponseSchema as JSON Schema, for Ollama's structured outputs
+var taskJSONSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"tasks": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"title":       map[string]string{"type": "string"},
+					"due":         map[string]string{"type": "string"},
+					"impact":      map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 5},
+					"urgency":     map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 5},
+					"effort":      map[string]interface{}{"type": "string", "enum": []string{"S", "M", "L"}},
+					"stakeholder": map[string]string{"type": "string"},
+					"project":     map[string]string{"type": "string"},
+				},
+				"required": []string{"title", "due", "impact", "urgency", "effort", "stakeholder", "project"},
+			},
+		},
+	},
+	"required": []string{"tasks"},
+}
+
+// extractedTask is one task in a structured extraction response. Decoding is lenient about the
+// variations models produce: quoted numbers, due_date for due, and the older priority field.
+type extractedTask struct {
+	Title       string  `json:"title"`
+	Due         string  `json:"due"`
+	DueDate     string  `json:"due_date"`
+	Impact      flexInt `json:"impact"`
+	Urgency     flexInt `json:"urgency"`
+	Effort      string  `json:"effort"`
+	Stakeholder string  `json:"stakeholder"`
+	Project     string  `json:"project"`
+	Priority    string  `json:"priority"`
+}
+
+// flexInt decodes a number that may have been written as a string
+type flexInt int
+
+func (f *flexInt) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(strings.TrimSpace(string(data)), `"`)
+	if n, err := strconv.ParseFloat(text, 64); err == nil {
+		*f = flexInt(n)
+	}
+	return nil
+}
+
+// parseTaskResponse parses a task extraction response. The structured JSON format is tried
+// first; responses that aren't JSON (cached from before structured output, or from a model
+// that ignored the format) fall back to the numbered pipe-delimited parser. Due dates are
+// relative to now, which should be in the schedule's time zone.
+func parseTaskResponse(response string, now time.Time) []*db.Task {
+	var tasks []*db.Task
+	if extracted, ok := decodeTaskJSON(response); ok {
+		for _, t := range extracted {
+			tasks = append(tasks, t.toTask(now))
+		}
+	} else {
+		tasks = parsePipeTasks(response, now)
+	}
+
+	// Drop empty titles, meeting invitations and duplicates, clear N/A placeholders and give
+	// each task a content ID
+	var result []*db.Task
+	seenTitles := make(map[string]bool)
+	for _, task := range tasks {
+		normalized := db.NormalizeTaskTitle(task.Title)
+		if normalized == "" || seenTitles[normalized] || isMeetingInvitationGemini(task.Title) {
+			continue
+		}
+		seenTitles[normalized] = true
+		if strings.EqualFold(task.Stakeholder, "N/A") {
+			task.Stakeholder = ""
+		}
+		if strings.EqualFold(task.Project, "N/A") {
+			task.Project = ""
+		}
+		task.ID = db.ExtractedTaskID(task.Source, task.SourceID, task.Title)
+		result = append(result, task)
+	}
+	return result
+}
+
+// decodeTaskJSON finds the JSON in a response and decodes its tasks. It tolerates markdown
+// fences and prose around the JSON, and a bare array of tasks instead of the object.
+func decodeTaskJSON(response string) ([]extractedTask, bool) {
+	for i := 0; i < len(response); i++ {
+		switch response[i] {
+		case '{':
+			var result struct {
+				Tasks *[]extractedTask `json:"tasks"`
+			}
+			if json.NewDecoder(strings.NewReader(response[i:])).Decode(&result) == nil && result.Tasks != nil {
+				return *result.Tasks, true
+			}
+		case '[':
+			var tasks []extractedTask
+			if json.NewDecoder(strings.NewReader(response[i:])).Decode(&tasks) == nil {
+				return tasks, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// toTask converts an extracted task, falling back to medium impact, urgency and effort
+// when the model gave no valid value. Due dates are relative to now.
+func (t extractedTask) toTask(now time.Time) *db.Task {
+	task := &db.Task{
+		Title:   strings.TrimSpace(t.Title),
+		Source:  "ai",
+		Status:  "pending",
+		Impact:  3,
+		Urgency: 3,
+		Effort:  "M",
+		Project: strings.TrimSpace(t.Project),
+	}
+
+	switch strings.ToLower(strings.TrimSpace(t.Priority)) {
+	case "high", "critical", "urgent":
+		task.Impact, task.Urgency = 4, 4
+	case "low":
+		task.Impact, task.Urgency = 2, 2
+	}
+	if t.Impact >= 1 && t.Impact <= 5 {
+		task.Impact = int(t.Impact)
+	}
+	if t.Urgency >= 1 && t.Urgency <= 5 {
+		task.Urgency = int(t.Urgency)
+	}
+	if effort := strings.ToUpper(strings.TrimSpace(t.Effort)); effort == "S" || effort == "M" || effort == "L" {
+		task.Effort = effort
+	}
+
+	if stakeholder := strings.TrimSpace(t.Stakeholder); isValidStakeholder(stakeholder) {
+		task.Stakeholder = stakeholder
+	}
+
+	due := t.Due
+	if due == "" {
+		due = t.DueDate
+	}
+	task.DueTS = parseDueDate(due, now)
+	if task.DueTS != nil {
+		task.Urgency = maxInt(task.Urgency, calculateUrgencyFromDue(*task.DueTS, now))
+	}
+
+	return task
+}
@@ -0,0 +1,136 @@
+package llm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestParseTaskResponse parses task extraction responses recorded from Gemini (schema
+// enforced), Ollama and the Claude CLI, including older formats still found in the cache
+func TestParseTaskResponse(t *testing.T) {
+	type want struct {
+		title       string
+		impact      int
+		urgency     int
+		effort      string
+		stakeholder string
+		project     string
+		hasDue      bool
+	}
+
+	tests := []struct {
+		file  string
+		tasks []want
+	}{
+		{"gemini_schema.txt", []want{
+			{"Review Q4 budget variance report", 4, 3, "M", "Sarah Chen", "Q4 planning", true},
+			{"Send signed NDA to Acme legal", 3, 2, "S", "Tim Davis", "", false},
+		}},
+		// Quoted numbers, lowercase effort, N/A placeholders, a team as stakeholder and a
+		// meeting invitation
+		{"ollama_quoted_numbers.txt", []want{
+			{"Confirm venue booking for offsite", 3, 4, "S", "", "", false},
+		}},
+		// Prose and a markdown fence around the JSON, out of range values and a duplicate title
+		{"claude_fenced.txt", []want{
+			{"Prepare NDA for client review", 4, 3, "M", "Dana Moore", "Acme deal", false},
+		}},
+		// A bare array with due_date and priority, as the Claude prompt once asked for
+		{"claude_legacy_json.txt", []want{
+			{"Reply to Dana about the hiring plan", 4, 4, "M", "", "", true},
+		}},
+		{"pipe_single_line.txt", []want{
+			{"Approve marketing budget request", 4, 2, "S", "Priya Patel", "Launch", false},
+			{"Schedule design review with agency", 2, 2, "M", "", "", false},
+		}},
+		{"pipe_multi_line.txt", []want{
+			{"Draft board update for March", 5, 3, "L", "Maria Lopez", "", false},
+		}},
+		{"empty.txt", nil},
+		// JSON cut off mid-response falls back to the numbered list before it
+		{"truncated.txt", []want{
+			{"Update onboarding checklist", 2, 1, "S", "", "", false},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			response, err := os.ReadFile(filepath.Join("testdata", "extraction", tt.file))
+			if err != nil {
+				t.Fatalf("Failed to read recorded response: %v", err)
+			}
+
+			tasks := parseTaskResponse(string(response), time.Now())
+			if len(tasks) != len(tt.tasks) {
+				for _, task := range tasks {
+					t.Logf("Parsed task: %q", task.Title)
+				}
+				t.Fatalf("Expected %d tasks, got %d", len(tt.tasks), len(tasks))
+			}
+
+			for i, w := range tt.tasks {
+				task := tasks[i]
+				if task.Title != w.title {
+					t.Errorf("Task %d: expected title %q, got %q", i, w.title, task.Title)
+				}
+				if task.Impact != w.impact || task.Urgency != w.urgency || task.Effort != w.effort {
+					t.Errorf("Task %d: expected impact %d, urgency %d, effort %s, got %d, %d, %s",
+						i, w.impact, w.urgency, w.effort, task.Impact, task.Urgency, task.Effort)
+				}
+				if task.Stakeholder != w.stakeholder {
+					t.Errorf("Task %d: expected stakeholder %q, got %q", i, w.stakeholder, task.Stakeholder)
+				}
+				if task.Project != w.project {
+					t.Errorf("Task %d: expected project %q, got %q", i, w.project, task.Project)
+				}
+				if (task.DueTS != nil) != w.hasDue {
+					t.Errorf("Task %d: expected due date %v, got %v", i, w.hasDue, task.DueTS)
+				}
+				if task.Source != "ai" || task.Status != "pending" {
+					t.Errorf("Task %d: expected an ai pending task, got %s %s", i, task.Source, task.Status)
+				}
+				if task.ID == "" {
+					t.Errorf("Task %d: expected an ID", i)
+				}
+			}
+		})
+	}
+}
+
+// TestParseTaskResponseStableIDs verifies a task gets the same ID however the model formatted it
+func TestParseTaskResponseStableIDs(t *testing.T) {
+	fromJSON := parseTaskResponse(`{"tasks": [{"title": "Approve marketing budget request", "impact": 4}]}`, time.Now())
+	fromPipe := parseTaskResponse("1. Title: Approve  marketing budget request | Impact: 4", time.Now())
+	if len(fromJSON) != 1 || len(fromPipe) != 1 {
+		t.Fatalf("Expected one task from each response, got %d and %d", len(fromJSON), len(fromPipe))
+	}
+	if fromJSON[0].ID != fromPipe[0].ID {
+		t.Errorf("Expected the same ID, got %s and %s", fromJSON[0].ID, fromPipe[0].ID)
+	}
+}
+
+// TestParseDeepSummary parses deep summaries wrapped in a code block and with blank items
+func TestParseDeepSummary(t *testing.T) {
+	response := "```json\n{\"summary\": \" Pricing for Q3 is agreed. \", \"decisions\": [\"Ship Friday\", \" \"], " +
+		"\"owners\": [\"Sam: send the contract\"], \"risks\": [], \"open_questions\": [\"Who signs?\"]}\n```"
+
+	summary, err := parseDeepSummary(response)
+	if err != nil {
+		t.Fatalf("parseDeepSummary() error = %v", err)
+	}
+	if summary.Summary != "Pricing for Q3 is agreed." {
+		t.Errorf("Summary = %q", summary.Summary)
+	}
+	if len(summary.Decisions) != 1 || summary.Decisions[0] != "Ship Friday" {
+		t.Errorf("Decisions = %q, want [Ship Friday]", summary.Decisions)
+	}
+	if len(summary.Risks) != 0 || len(summary.OpenQuestions) != 1 || len(summary.Owners) != 1 {
+		t.Errorf("Risks = %q, OpenQuestions = %q, Owners = %q", summary.Risks, summary.OpenQuestions, summary.Owners)
+	}
+
+	if _, err := parseDeepSummary("The thread is about pricing."); err == nil {
+		t.Error("parseDeepSummary() of prose: expected an error")
+	}
+}
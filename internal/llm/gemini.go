@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
@@ -14,7 +15,6 @@ import (
 	"github.com/google/generative-ai-go/genai"
 	"golang.org/x/time/rate"
 	"google.golang.org/api/googleapi"
-	"google.golang.org/api/option"
 
 	"github.com/alexrabarts/focus-agent/internal/config"
 	"github.com/alexrabarts/focus-agent/internal/db"
@@ -25,17 +25,29 @@ type Client interface {
 	Close() error
 	SummarizeThread(ctx context.Context, messages []*db.Message) (string, error)
 	SummarizeThreadWithModelSelection(ctx context.Context, messages []*db.Message, metadata ThreadMetadata) (string, error)
+	SummarizeThreadGist(ctx context.Context, messages []*db.Message) (string, error)
+	SummarizeThreadDeep(ctx context.Context, messages []*db.Message, metadata ThreadMetadata) (*DeepSummary, error)
 	ExtractTasks(ctx context.Context, content string) ([]*db.Task, error)
 	ExtractTasksFromMessages(ctx context.Context, content string, messages []*db.Message, frontComments []*db.FrontComment, frontMetadata *db.FrontMetadata) ([]*db.Task, error)
 	EnrichTaskDescription(ctx context.Context, task *db.Task, messages []*db.Message) (string, error)
 	EvaluateStrategicAlignment(ctx context.Context, task *db.Task, priorities *config.Priorities) (*StrategicAlignmentResult, error)
-	DraftReply(ctx context.Context, thread []*db.Message, goal string) (string, error)
-	GenerateMeetingPrep(ctx context.Context, event *db.Event, relatedDocs []*db.Document) (string, error)
+	EvaluateStrategicAlignmentBatch(ctx context.Context, tasks []*db.Task, priorities *config.Priorities) ([]*StrategicAlignmentResult, error)
+	DraftReply(ctx context.Context, thread []*db.Message, goal string, persona *config.Persona) (string, error)
+	GenerateMeetingPrep(ctx context.Context, event *db.Event, relatedDocs []*db.Document, relatedThreads []*db.ContactInteraction) (string, error)
+	GenerateRelationshipBrief(ctx context.Context, event *db.Event, history *db.ContactHistory) (string, error)
+	AnswerQuestion(ctx context.Context, question string, messages []*db.Message, tasks []*db.Task) (string, error)
+	WriteWeeklyReview(ctx context.Context, review *db.WeeklyReview) (string, error)
+	ExtractWaitingRequest(ctx context.Context, msg *db.Message) (*WaitingRequest, error)
+	ExtractCommitments(ctx context.Context, msg *db.Message) ([]*Commitment, error)
+	Warmup(ctx context.Context) error
+	ProviderHealth(ctx context.Context) []*ProviderHealth
 }
 
 // GeminiClient handles Gemini API operations
 type GeminiClient struct {
-	client          *genai.Client
+	client          *genai.Client  // First key's client, which the models below are built on
+	keys            *geminiKeyPool // API keys with their daily quotas
+	modelName       string
 	model           *genai.GenerativeModel
 	proModel        *genai.GenerativeModel
 	db              *db.DB
@@ -43,25 +55,45 @@ type GeminiClient struct {
 	prompts         *PromptBuilder
 	rateLimiter     *rate.Limiter
 	proRateLimiter  *rate.Limiter
+	proBudget       *proBudget // Daily Pro allocation (nil = first come first served)
 	cacheTTL        time.Duration
 }
 
+// proModelName is the model used for threads that warrant deeper analysis
+const proModelName = "gemini-2.5-pro"
+
 // ThreadMetadata contains metadata for smart model selection
 type ThreadMetadata struct {
-	QueueSize      int       // Number of threads waiting to be processed
-	SenderEmail    string    // Email address of sender
-	Timestamp      time.Time // When the thread was received
-	MessageCount   int       // Number of messages in thread
+	QueueSize        int       // Number of threads waiting to be processed
+	SenderEmail      string    // Email address of sender
+	Timestamp        time.Time // When the thread was received
+	MessageCount     int       // Number of messages in thread
+	SenderReputation float64   // 0-1: how much of the thread starter's mail I reply to or act on
+	SenderKnown      bool      // Whether the starter has enough history for SenderReputation to count
 }
 
-// NewGeminiClient creates a new Gemini client
-func NewGeminiClient(apiKey string, database *db.DB, cfg *config.Config, prompts *PromptBuilder) (*GeminiClient, error) {
+// Sender reputations at or above engagedSender earn a thread a point towards Pro; those below
+// ignoredSender lose one
+const (
+	engagedSender = 0.6
+	ignoredSender = 0.2
+)
+
+// NewGeminiClient creates a new Gemini client. Requests fail over (or rotate) between the given
+// API keys as their daily quotas run out.
+func NewGeminiClient(apiKeys []string, database *db.DB, cfg *config.Config, prompts *PromptBuilder) (*GeminiClient, error) {
 	ctx := context.Background()
 
-	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	keys, err := newGeminiKeyPool(ctx, apiKeys, cfg.Gemini.KeyStrategy, cfg.Gemini.KeyDailyRequests)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
 	}
+	if database != nil {
+		if err := keys.loadUsage(database); err != nil {
+			log.Printf("Warning: failed to load Gemini key usage: %v", err)
+		}
+	}
+	client := keys.keys[0].client
 
 	// Use configured model (defaults to gemini-2.5-flash)
 	modelName := cfg.Gemini.Model
@@ -101,7 +133,7 @@ func NewGeminiClient(apiKey string, database *db.DB, cfg *config.Config, prompts
 	limiter := rate.NewLimiter(rate.Every(time.Minute/time.Duration(rateLimit)), 1)
 
 	// Create Pro model and rate limiter for strategic use
-	proModel := client.GenerativeModel("gemini-2.5-pro")
+	proModel := client.GenerativeModel(proModelName)
 	if cfg.Gemini.Temperature > 0 {
 		proModel.SetTemperature(cfg.Gemini.Temperature)
 	}
@@ -125,6 +157,8 @@ func NewGeminiClient(apiKey string, database *db.DB, cfg *config.Config, prompts
 
 	return &GeminiClient{
 		client:         client,
+		keys:           keys,
+		modelName:      modelName,
 		model:          model,
 		proModel:       proModel,
 		db:             database,
@@ -132,6 +166,7 @@ func NewGeminiClient(apiKey string, database *db.DB, cfg *config.Config, prompts
 		prompts:        prompts,
 		rateLimiter:    limiter,
 		proRateLimiter: proLimiter,
+		proBudget:      newProBudget(cfg, database),
 		cacheTTL:       cacheTTL,
 	}, nil
 }
@@ -146,17 +181,29 @@ func getRateLimit(cfg *config.Config, model string) int {
 
 // Close closes the Gemini client
 func (g *GeminiClient) Close() error {
-	if g.client != nil {
-		return g.client.Close()
+	if g.keys != nil {
+		return g.keys.close()
 	}
 	return nil
 }
 
-// generateWithRetry wraps GenerateContent with exponential backoff retry logic
-func (g *GeminiClient) generateWithRetry(ctx context.Context, prompt genai.Text) (*genai.GenerateContentResponse, error) {
+// generateWithRetry wraps GenerateContent with exponential backoff retry logic. It returns the
+// label of the API key used, for usage logging.
+func (g *GeminiClient) generateWithRetry(ctx context.Context, prompt genai.Text) (*genai.GenerateContentResponse, string, error) {
 	return g.generateWithRetryForModel(ctx, prompt, g.model)
 }
 
+// jsonModel returns a model with the default model's settings that responds with JSON
+// matching the schema
+func (g *GeminiClient) jsonModel(schema *genai.Schema) *genai.GenerativeModel {
+	model := g.client.GenerativeModel(g.modelName)
+	model.GenerationConfig = g.model.GenerationConfig
+	model.SafetySettings = g.model.SafetySettings
+	model.ResponseMIMEType = "application/json"
+	model.ResponseSchema = schema
+	return model
+}
+
 // DailyQuotaExceededError is returned when the daily API quota is exhausted
 type DailyQuotaExceededError struct {
 	Message string
@@ -199,8 +246,51 @@ func isDailyQuotaError(apiErr *googleapi.Error) bool {
 	return false
 }
 
-// generateWithRetryForModel wraps GenerateContent with exponential backoff retry logic for a specific model
-func (g *GeminiClient) generateWithRetryForModel(ctx context.Context, prompt genai.Text, model *genai.GenerativeModel) (*genai.GenerateContentResponse, error) {
+// generateWithRetryForModel wraps GenerateContent with exponential backoff retry logic for a
+// specific model. When a key's daily quota runs out the request moves to the next key; it returns
+// the label of the key that answered.
+func (g *GeminiClient) generateWithRetryForModel(ctx context.Context, prompt genai.Text, model *genai.GenerativeModel) (*genai.GenerateContentResponse, string, error) {
+	for {
+		key := g.keys.acquire()
+		if key == nil {
+			return nil, "", g.keys.quotaExhaustedError()
+		}
+
+		resp, err := g.generateOnKey(ctx, prompt, g.modelOnKey(model, key))
+		var quotaErr *DailyQuotaExceededError
+		if errors.As(err, &quotaErr) {
+			g.keys.markExhausted(key)
+			g.db.LogKeyUsage("gemini", key.label, db.ActionQuotaExhausted, 0, 0, 0, err)
+			if remaining := g.keys.available(); remaining > 0 {
+				log.Printf("🔑 Gemini key %s is out of quota, moving to the next key (%d left)", key.label, remaining)
+			}
+			continue
+		}
+		return resp, key.label, err
+	}
+}
+
+// modelOnKey returns the model with the same settings on the given key's client
+func (g *GeminiClient) modelOnKey(model *genai.GenerativeModel, key *geminiKey) *genai.GenerativeModel {
+	if key.client == g.client {
+		return model
+	}
+
+	name := g.modelName
+	if model == g.proModel {
+		name = proModelName
+	}
+	keyModel := key.client.GenerativeModel(name)
+	keyModel.GenerationConfig = model.GenerationConfig
+	keyModel.SafetySettings = model.SafetySettings
+	keyModel.Tools = model.Tools
+	keyModel.ToolConfig = model.ToolConfig
+	keyModel.SystemInstruction = model.SystemInstruction
+	return keyModel
+}
+
+// generateOnKey runs a request on one key, retrying per-minute rate limits with backoff
+func (g *GeminiClient) generateOnKey(ctx context.Context, prompt genai.Text, model *genai.GenerativeModel) (*genai.GenerateContentResponse, error) {
 	var lastErr error
 
 	for attempt := 0; attempt <= g.config.Gemini.MaxRetries; attempt++ {
@@ -213,9 +303,8 @@ func (g *GeminiClient) generateWithRetryForModel(ctx context.Context, prompt gen
 		if apiErr, ok := err.(*googleapi.Error); ok && apiErr.Code == 429 {
 			// Check if it's a daily quota error (not just per-minute rate limit)
 			if isDailyQuotaError(apiErr) {
-				// Daily quota exhausted - don't retry
+				// Daily quota exhausted - don't retry on this key
 				log.Printf("🚫 Daily quota exhausted (250 requests/day free tier limit)")
-				log.Printf("   Processing stopped. Quota resets in ~24 hours.")
 				return nil, &DailyQuotaExceededError{
 					Message: "Daily API quota exhausted (250 requests/day free tier limit). Quota resets in ~24 hours.",
 				}
@@ -252,9 +341,16 @@ func (g *GeminiClient) generateWithRetryForModel(ctx context.Context, prompt gen
 
 // SummarizeThread summarizes an email thread
 func (g *GeminiClient) SummarizeThread(ctx context.Context, messages []*db.Message) (string, error) {
-	// Build prompt
-	prompt := g.prompts.BuildThreadSummary(messages)
+	return g.summarizeWithFlash(ctx, g.prompts.BuildThreadSummary(messages), "summarize_thread")
+}
 
+// SummarizeThreadGist sums up a low-priority thread in one line, always on Flash
+func (g *GeminiClient) SummarizeThreadGist(ctx context.Context, messages []*db.Message) (string, error) {
+	return g.summarizeWithFlash(ctx, g.prompts.BuildThreadGist(messages), "summarize_thread_gist")
+}
+
+// summarizeWithFlash runs a summary prompt on the Flash model, with caching
+func (g *GeminiClient) summarizeWithFlash(ctx context.Context, prompt, action string) (string, error) {
 	// Check cache
 	hash := g.hashPrompt(prompt)
 	cached, err := g.db.GetCachedResponse(hash)
@@ -270,21 +366,17 @@ func (g *GeminiClient) SummarizeThread(ctx context.Context, messages []*db.Messa
 
 	// Generate summary with retry
 	startTime := time.Now()
-	resp, err := g.generateWithRetry(ctx, genai.Text(prompt))
+	resp, key, err := g.generateWithRetry(ctx, genai.Text(prompt))
 	if err != nil {
-		g.db.LogUsage("gemini", "summarize_thread", 0, 0, time.Since(startTime), err)
+		g.db.LogKeyUsage("gemini", key, action, 0, 0, time.Since(startTime), err)
 		return "", fmt.Errorf("failed to generate summary: %w", err)
 	}
 
 	// Extract text from response
 	summary := g.extractText(resp)
 
-	// Calculate token usage (approximate)
-	tokens := g.estimateTokens(prompt + summary)
-	cost := g.calculateCost(tokens)
-
 	// Log usage
-	g.db.LogUsage("gemini", "summarize_thread", tokens, cost, time.Since(startTime), nil)
+	tokens := g.logUsage(key, action, g.modelName, resp, prompt, summary, startTime)
 
 	// Cache response
 	cache := &db.LLMCache{
@@ -302,6 +394,22 @@ func (g *GeminiClient) SummarizeThread(ctx context.Context, messages []*db.Messa
 
 // SummarizeThreadWithModelSelection summarizes a thread using smart model selection
 func (g *GeminiClient) SummarizeThreadWithModelSelection(ctx context.Context, messages []*db.Message, metadata ThreadMetadata) (string, error) {
+	return g.summarizeWithModelSelection(ctx, g.prompts.BuildThreadSummary(messages), metadata, "summarize_thread")
+}
+
+// SummarizeThreadDeep writes a structured summary of an important thread, on Pro when the
+// thread's metadata and the Pro allocation allow
+func (g *GeminiClient) SummarizeThreadDeep(ctx context.Context, messages []*db.Message, metadata ThreadMetadata) (*DeepSummary, error) {
+	response, err := g.summarizeWithModelSelection(ctx, g.prompts.BuildThreadDeepSummary(messages), metadata, "summarize_thread_deep")
+	if err != nil {
+		return nil, err
+	}
+	return parseDeepSummary(response)
+}
+
+// summarizeWithModelSelection runs a summary prompt on Pro or Flash, picked from the thread's
+// metadata, with caching
+func (g *GeminiClient) summarizeWithModelSelection(ctx context.Context, prompt string, metadata ThreadMetadata, action string) (string, error) {
 	// Calculate priority score for model selection
 	score := 0
 	reasoning := []string{}
@@ -325,6 +433,17 @@ func (g *GeminiClient) SummarizeThreadWithModelSelection(ctx context.Context, me
 		reasoning = append(reasoning, "key stakeholder")
 	}
 
+	// Sender whose mail I usually act on: +1 point; one I usually ignore: -1 point
+	if metadata.SenderKnown {
+		if metadata.SenderReputation >= engagedSender {
+			score += 1
+			reasoning = append(reasoning, fmt.Sprintf("engaged sender (%.0f%%)", metadata.SenderReputation*100))
+		} else if metadata.SenderReputation < ignoredSender {
+			score -= 1
+			reasoning = append(reasoning, fmt.Sprintf("ignored sender (%.0f%%)", metadata.SenderReputation*100))
+		}
+	}
+
 	// Recent (< 24h): +2 points
 	if time.Since(metadata.Timestamp) < 24*time.Hour {
 		score += 2
@@ -339,33 +458,36 @@ func (g *GeminiClient) SummarizeThreadWithModelSelection(ctx context.Context, me
 
 	// Decide which model to use (score ≥ 3 = Pro, else Flash)
 	usePro := score >= 3
-	selectedModel := "gemini-2.5-flash"
+	selectedModel := g.modelName
 	selectedRateLimiter := g.rateLimiter
 	actualModel := g.model
 
 	if usePro {
-		// Check if Pro rate limiter has capacity
-		if g.proRateLimiter.Allow() {
-			selectedModel = "gemini-2.5-pro"
+		// Check the daily allocation first, then whether the Pro rate limiter has capacity
+		if !g.proBudget.take(isKeyStakeholder) {
+			log.Printf("Pro allocation used up for now, falling back to Flash (score: %d, reasons: %s)", score, strings.Join(reasoning, ", "))
+		} else if g.proRateLimiter.Allow() {
+			selectedModel = proModelName
 			selectedRateLimiter = g.proRateLimiter
 			actualModel = g.proModel
 			log.Printf("Using Pro model (score: %d, reasons: %s)", score, strings.Join(reasoning, ", "))
 		} else {
 			// Fallback to Flash if Pro exhausted
+			g.proBudget.release()
 			log.Printf("Pro model exhausted, falling back to Flash (score: %d, reasons: %s)", score, strings.Join(reasoning, ", "))
 		}
 	} else {
 		log.Printf("Using Flash model (score: %d)", score)
 	}
 
-	// Build prompt
-	prompt := g.prompts.BuildThreadSummary(messages)
-
 	// Check cache
 	hash := g.hashPrompt(prompt + selectedModel)
 	cached, err := g.db.GetCachedResponse(hash)
 	if err == nil && cached != nil {
 		log.Printf("Using cached summary for thread")
+		if actualModel == g.proModel {
+			g.proBudget.release()
+		}
 		return cached.Response, nil
 	}
 
@@ -376,21 +498,17 @@ func (g *GeminiClient) SummarizeThreadWithModelSelection(ctx context.Context, me
 
 	// Generate summary with retry using selected model
 	startTime := time.Now()
-	resp, err := g.generateWithRetryForModel(ctx, genai.Text(prompt), actualModel)
+	resp, key, err := g.generateWithRetryForModel(ctx, genai.Text(prompt), actualModel)
 	if err != nil {
-		g.db.LogUsage("gemini", "summarize_thread", 0, 0, time.Since(startTime), err)
+		g.db.LogKeyUsage("gemini", key, action, 0, 0, time.Since(startTime), err)
 		return "", fmt.Errorf("failed to generate summary: %w", err)
 	}
 
 	// Extract text from response
 	summary := g.extractText(resp)
 
-	// Calculate token usage (approximate)
-	tokens := g.estimateTokens(prompt + summary)
-	cost := g.calculateCost(tokens)
-
 	// Log usage
-	g.db.LogUsage("gemini", "summarize_thread", tokens, cost, time.Since(startTime), nil)
+	tokens := g.logUsage(key, action, selectedModel, resp, prompt, summary, startTime)
 
 	// Cache response
 	cache := &db.LLMCache{
@@ -446,7 +564,7 @@ func (g *GeminiClient) ExtractTasksFromMessages(ctx context.Context, content str
 	cached, err := g.db.GetCachedResponse(hash)
 	if err == nil && cached != nil {
 		log.Printf("Using cached task extraction")
-		return g.filterTasksForUser(g.parseTasksFromResponse(cached.Response)), nil
+		return g.filterTasksForUser(parseTaskResponse(cached.Response, g.config.Schedule.Now())), nil
 	}
 
 	// Wait for rate limit
@@ -456,19 +574,17 @@ func (g *GeminiClient) ExtractTasksFromMessages(ctx context.Context, content str
 
 	// Generate response with retry
 	startTime := time.Now()
-	resp, err := g.generateWithRetry(ctx, genai.Text(prompt))
+	resp, key, err := g.generateWithRetryForModel(ctx, genai.Text(prompt), g.jsonModel(taskResponseSchema()))
 	if err != nil {
-		g.db.LogUsage("gemini", "extract_tasks", 0, 0, time.Since(startTime), err)
+		g.db.LogKeyUsage("gemini", key, "extract_tasks", 0, 0, time.Since(startTime), err)
 		return nil, fmt.Errorf("failed to extract tasks: %w", err)
 	}
 
 	// Extract text
 	text := g.extractText(resp)
 
-	// Calculate usage
-	tokens := g.estimateTokens(prompt + text)
-	cost := g.calculateCost(tokens)
-	g.db.LogUsage("gemini", "extract_tasks", tokens, cost, time.Since(startTime), nil)
+	// Log usage
+	tokens := g.logUsage(key, "extract_tasks", g.modelName, resp, prompt, text, startTime)
 
 	// Cache response
 	cache := &db.LLMCache{
@@ -481,7 +597,7 @@ func (g *GeminiClient) ExtractTasksFromMessages(ctx context.Context, content str
 	}
 	g.db.SaveCachedResponse(cache)
 
-	return g.filterTasksForUser(g.parseTasksFromResponse(text)), nil
+	return g.filterTasksForUser(parseTaskResponse(text, g.config.Schedule.Now())), nil
 }
 
 // StrategicAlignmentResult contains the result of strategic alignment evaluation
@@ -499,7 +615,7 @@ func (g *GeminiClient) EvaluateStrategicAlignment(ctx context.Context, task *db.
 	prompt := g.prompts.BuildStrategicAlignment(task, priorities)
 
 	// Check cache
-	hash := g.hashPrompt(prompt)
+	hash := g.alignmentCacheKey(prompt, priorities)
 	cached, err := g.db.GetCachedResponse(hash)
 	if err == nil && cached != nil {
 		return g.parseStrategicAlignmentResponse(cached.Response), nil
@@ -511,18 +627,7 @@ func (g *GeminiClient) EvaluateStrategicAlignment(ctx context.Context, task *db.
 	}
 
 	// Create a temporary model with JSON response mode
-	jsonModel := g.client.GenerativeModel(g.config.Gemini.Model)
-	jsonModel.SetTemperature(g.config.Gemini.Temperature)
-	jsonModel.SetTopK(40)
-	jsonModel.SetTopP(0.95)
-	if g.config.Gemini.MaxTokens > 0 {
-		jsonModel.SetMaxOutputTokens(int32(g.config.Gemini.MaxTokens))
-	}
-	jsonModel.SafetySettings = g.model.SafetySettings
-
-	// Configure JSON response
-	jsonModel.ResponseMIMEType = "application/json"
-	jsonModel.ResponseSchema = &genai.Schema{
+	jsonModel := g.jsonModel(&genai.Schema{
 		Type: genai.TypeObject,
 		Properties: map[string]*genai.Schema{
 			"score": {
@@ -550,13 +655,13 @@ func (g *GeminiClient) EvaluateStrategicAlignment(ctx context.Context, task *db.
 			},
 		},
 		Required: []string{"score", "okrs", "focus_areas", "projects", "reasoning"},
-	}
+	})
 
 	// Generate response with retry
 	startTime := time.Now()
-	resp, err := g.generateWithRetryForModel(ctx, genai.Text(prompt), jsonModel)
+	resp, key, err := g.generateWithRetryForModel(ctx, genai.Text(prompt), jsonModel)
 	if err != nil {
-		g.db.LogUsage("gemini", "strategic_alignment", 0, 0, time.Since(startTime), err)
+		g.db.LogKeyUsage("gemini", key, "strategic_alignment", 0, 0, time.Since(startTime), err)
 		return nil, fmt.Errorf("failed to evaluate strategic alignment: %w", err)
 	}
 
@@ -577,10 +682,8 @@ func (g *GeminiClient) EvaluateStrategicAlignment(ctx context.Context, task *db.
 		}
 	}
 
-	// Calculate usage
-	tokens := g.estimateTokens(prompt + text)
-	cost := g.calculateCost(tokens)
-	g.db.LogUsage("gemini", "strategic_alignment", tokens, cost, time.Since(startTime), nil)
+	// Log usage
+	tokens := g.logUsage(key, "strategic_alignment", g.modelName, resp, prompt, text, startTime)
 
 	// Cache response (longer TTL since priorities don't change often)
 	cache := &db.LLMCache{
@@ -606,9 +709,9 @@ func (g *GeminiClient) filterTasksForUser(tasks []*db.Task) []*db.Task {
 	return tasks
 }
 
-// DraftReply drafts a reply to an email
-func (g *GeminiClient) DraftReply(ctx context.Context, thread []*db.Message, goal string) (string, error) {
-	prompt := g.prompts.BuildReply(thread, goal)
+// DraftReply drafts a reply to an email in the given persona, or the default tone when nil
+func (g *GeminiClient) DraftReply(ctx context.Context, thread []*db.Message, goal string, persona *config.Persona) (string, error) {
+	prompt := g.prompts.BuildReply(thread, goal, persona)
 
 	// Check cache
 	hash := g.hashPrompt(prompt)
@@ -625,19 +728,17 @@ func (g *GeminiClient) DraftReply(ctx context.Context, thread []*db.Message, goa
 
 	// Generate reply with retry
 	startTime := time.Now()
-	resp, err := g.generateWithRetry(ctx, genai.Text(prompt))
+	resp, key, err := g.generateWithRetry(ctx, genai.Text(prompt))
 	if err != nil {
-		g.db.LogUsage("gemini", "draft_reply", 0, 0, time.Since(startTime), err)
+		g.db.LogKeyUsage("gemini", key, "draft_reply", 0, 0, time.Since(startTime), err)
 		return "", fmt.Errorf("failed to draft reply: %w", err)
 	}
 
 	// Extract text
 	reply := g.extractText(resp)
 
-	// Calculate usage
-	tokens := g.estimateTokens(prompt + reply)
-	cost := g.calculateCost(tokens)
-	g.db.LogUsage("gemini", "draft_reply", tokens, cost, time.Since(startTime), nil)
+	// Log usage
+	tokens := g.logUsage(key, "draft_reply", g.modelName, resp, prompt, reply, startTime)
 
 	// Cache response
 	cache := &db.LLMCache{
@@ -654,8 +755,8 @@ func (g *GeminiClient) DraftReply(ctx context.Context, thread []*db.Message, goa
 }
 
 // GenerateMeetingPrep generates meeting preparation notes
-func (g *GeminiClient) GenerateMeetingPrep(ctx context.Context, event *db.Event, relatedDocs []*db.Document) (string, error) {
-	prompt := g.prompts.BuildMeetingPrep(event, relatedDocs)
+func (g *GeminiClient) GenerateMeetingPrep(ctx context.Context, event *db.Event, relatedDocs []*db.Document, relatedThreads []*db.ContactInteraction) (string, error) {
+	prompt := g.prompts.BuildMeetingPrep(event, relatedDocs, relatedThreads)
 
 	// Check cache
 	hash := g.hashPrompt(prompt)
@@ -672,19 +773,17 @@ func (g *GeminiClient) GenerateMeetingPrep(ctx context.Context, event *db.Event,
 
 	// Generate prep with retry
 	startTime := time.Now()
-	resp, err := g.generateWithRetry(ctx, genai.Text(prompt))
+	resp, key, err := g.generateWithRetry(ctx, genai.Text(prompt))
 	if err != nil {
-		g.db.LogUsage("gemini", "meeting_prep", 0, 0, time.Since(startTime), err)
+		g.db.LogKeyUsage("gemini", key, "meeting_prep", 0, 0, time.Since(startTime), err)
 		return "", fmt.Errorf("failed to generate meeting prep: %w", err)
 	}
 
 	// Extract text
 	prep := g.extractText(resp)
 
-	// Calculate usage
-	tokens := g.estimateTokens(prompt + prep)
-	cost := g.calculateCost(tokens)
-	g.db.LogUsage("gemini", "meeting_prep", tokens, cost, time.Since(startTime), nil)
+	// Log usage
+	tokens := g.logUsage(key, "meeting_prep", g.modelName, resp, prompt, prep, startTime)
 
 	// Cache response
 	cache := &db.LLMCache{
@@ -700,6 +799,299 @@ func (g *GeminiClient) GenerateMeetingPrep(ctx context.Context, event *db.Event,
 	return prep, nil
 }
 
+// GenerateRelationshipBrief summarizes sentiment and topics from a contact's history
+func (g *GeminiClient) GenerateRelationshipBrief(ctx context.Context, event *db.Event, history *db.ContactHistory) (string, error) {
+	prompt := g.prompts.BuildRelationshipBrief(event, history)
+
+	// Check cache
+	hash := g.hashPrompt(prompt)
+	cached, err := g.db.GetCachedResponse(hash)
+	if err == nil && cached != nil {
+		log.Printf("Using cached relationship brief")
+		return cached.Response, nil
+	}
+
+	// Wait for rate limit
+	if err := g.rateLimiter.Wait(ctx); err != nil {
+		return "", fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	// Generate brief with retry
+	startTime := time.Now()
+	resp, key, err := g.generateWithRetry(ctx, genai.Text(prompt))
+	if err != nil {
+		g.db.LogKeyUsage("gemini", key, "relationship_brief", 0, 0, time.Since(startTime), err)
+		return "", fmt.Errorf("failed to generate relationship brief: %w", err)
+	}
+
+	// Extract text
+	brief := g.extractText(resp)
+
+	// Log usage
+	tokens := g.logUsage(key, "relationship_brief", g.modelName, resp, prompt, brief, startTime)
+
+	// Cache response
+	cache := &db.LLMCache{
+		Hash:      hash,
+		Prompt:    prompt,
+		Response:  brief,
+		Model:     "gemini-1.5-flash",
+		Tokens:    tokens,
+		ExpiresAt: time.Now().Add(g.cacheTTL),
+	}
+	g.db.SaveCachedResponse(cache)
+
+	return brief, nil
+}
+
+// AnswerQuestion answers a question from retrieved messages and tasks
+func (g *GeminiClient) AnswerQuestion(ctx context.Context, question string, messages []*db.Message, tasks []*db.Task) (string, error) {
+	prompt := g.prompts.BuildQuestionAnswer(question, messages, tasks)
+
+	// Check cache
+	hash := g.hashPrompt(prompt)
+	cached, err := g.db.GetCachedResponse(hash)
+	if err == nil && cached != nil {
+		log.Printf("Using cached answer")
+		return cached.Response, nil
+	}
+
+	// Wait for rate limit
+	if err := g.rateLimiter.Wait(ctx); err != nil {
+		return "", fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	// Generate answer with retry
+	startTime := time.Now()
+	resp, key, err := g.generateWithRetry(ctx, genai.Text(prompt))
+	if err != nil {
+		g.db.LogKeyUsage("gemini", key, "ask", 0, 0, time.Since(startTime), err)
+		return "", fmt.Errorf("failed to answer question: %w", err)
+	}
+
+	// Extract text
+	answer := g.extractText(resp)
+
+	// Log usage
+	tokens := g.logUsage(key, "ask", g.modelName, resp, prompt, answer, startTime)
+
+	// Cache response
+	cache := &db.LLMCache{
+		Hash:      hash,
+		Prompt:    prompt,
+		Response:  answer,
+		Model:     "gemini-1.5-flash",
+		Tokens:    tokens,
+		ExpiresAt: time.Now().Add(g.cacheTTL),
+	}
+	g.db.SaveCachedResponse(cache)
+
+	return answer, nil
+}
+
+// WriteWeeklyReview writes a short narrative of the week's work and misses
+func (g *GeminiClient) WriteWeeklyReview(ctx context.Context, review *db.WeeklyReview) (string, error) {
+	prompt := g.prompts.BuildWeeklyReview(review)
+
+	// Wait for rate limit
+	if err := g.rateLimiter.Wait(ctx); err != nil {
+		return "", fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	// Generate narrative with retry
+	startTime := time.Now()
+	resp, key, err := g.generateWithRetry(ctx, genai.Text(prompt))
+	if err != nil {
+		g.db.LogKeyUsage("gemini", key, "weekly_review", 0, 0, time.Since(startTime), err)
+		return "", fmt.Errorf("failed to write weekly review: %w", err)
+	}
+
+	// Extract text
+	narrative := g.extractText(resp)
+
+	// Log usage
+	g.logUsage(key, "weekly_review", g.modelName, resp, prompt, narrative, startTime)
+
+	return narrative, nil
+}
+
+// WaitingRequest is what an email the user sent asks the recipient for
+type WaitingRequest struct {
+	IsRequest bool   `json:"is_request"`
+	Request   string `json:"request"`   // What was asked for
+	Recipient string `json:"recipient"` // Who is expected to reply
+	ReplyBy   string `json:"reply_by"`  // Date a reply was asked for (YYYY-MM-DD), if any
+}
+
+// ExtractWaitingRequest finds what a sent email asks the recipient for, if anything
+func (g *GeminiClient) ExtractWaitingRequest(ctx context.Context, msg *db.Message) (*WaitingRequest, error) {
+	prompt := g.prompts.BuildWaitingRequest(msg)
+
+	// Wait for rate limit
+	if err := g.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	// Generate response with retry
+	startTime := time.Now()
+	resp, key, err := g.generateWithRetry(ctx, genai.Text(prompt))
+	if err != nil {
+		g.db.LogKeyUsage("gemini", key, "waiting_request", 0, 0, time.Since(startTime), err)
+		return nil, fmt.Errorf("failed to extract waiting request: %w", err)
+	}
+
+	// Extract text
+	text := g.extractText(resp)
+
+	// Log usage
+	g.logUsage(key, "waiting_request", g.modelName, resp, prompt, text, startTime)
+
+	return parseWaitingRequest(text)
+}
+
+// parseWaitingRequest reads a waiting request from an LLM response, which may wrap the JSON in
+// a markdown code block
+func parseWaitingRequest(response string) (*WaitingRequest, error) {
+	response = strings.TrimSpace(response)
+	jsonStart := strings.Index(response, "{")
+	jsonEnd := strings.LastIndex(response, "}")
+	if jsonStart < 0 || jsonEnd < jsonStart {
+		return nil, fmt.Errorf("failed to parse waiting request: no JSON object in response")
+	}
+
+	request := &WaitingRequest{}
+	if err := json.Unmarshal([]byte(response[jsonStart:jsonEnd+1]), request); err != nil {
+		return nil, fmt.Errorf("failed to parse waiting request: %w", err)
+	}
+	request.Request = strings.TrimSpace(request.Request)
+	request.Recipient = strings.TrimSpace(request.Recipient)
+	request.ReplyBy = strings.TrimSpace(request.ReplyBy)
+	return request, nil
+}
+
+// maxCommitments caps the commitments taken from one sent email
+const maxCommitments = 5
+
+// Commitment is something the user promised in an email they sent
+type Commitment struct {
+	Promise     string `json:"promise"`     // What was promised
+	Recipient   string `json:"recipient"`   // Who it was promised to
+	DueBy       string `json:"due_by"`      // Date it was promised by (YYYY-MM-DD), if any
+	Deliverable bool   `json:"deliverable"` // A file or document is to be sent, not just a reply
+}
+
+// ExtractCommitments finds what the user promised to do in an email they sent
+func (g *GeminiClient) ExtractCommitments(ctx context.Context, msg *db.Message) ([]*Commitment, error) {
+	prompt := g.prompts.BuildCommitments(msg)
+
+	// Wait for rate limit
+	if err := g.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	// Generate response with retry
+	startTime := time.Now()
+	resp, key, err := g.generateWithRetry(ctx, genai.Text(prompt))
+	if err != nil {
+		g.db.LogKeyUsage("gemini", key, "commitments", 0, 0, time.Since(startTime), err)
+		return nil, fmt.Errorf("failed to extract commitments: %w", err)
+	}
+
+	// Extract text
+	text := g.extractText(resp)
+
+	// Log usage
+	g.logUsage(key, "commitments", g.modelName, resp, prompt, text, startTime)
+
+	return parseCommitments(text)
+}
+
+// parseCommitments reads commitments from an LLM response, a JSON array that may be wrapped in
+// a markdown code block. Entries without a promise are dropped.
+func parseCommitments(response string) ([]*Commitment, error) {
+	response = strings.TrimSpace(response)
+	jsonStart := strings.Index(response, "[")
+	jsonEnd := strings.LastIndex(response, "]")
+	if jsonStart < 0 || jsonEnd < jsonStart {
+		return nil, fmt.Errorf("failed to parse commitments: no JSON array in response")
+	}
+
+	var parsed []*Commitment
+	if err := json.Unmarshal([]byte(response[jsonStart:jsonEnd+1]), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse commitments: %w", err)
+	}
+
+	var commitments []*Commitment
+	for _, commitment := range parsed {
+		if commitment == nil {
+			continue
+		}
+		commitment.Promise = strings.TrimSpace(commitment.Promise)
+		commitment.Recipient = strings.TrimSpace(commitment.Recipient)
+		commitment.DueBy = strings.TrimSpace(commitment.DueBy)
+		if commitment.Promise == "" {
+			continue
+		}
+		commitments = append(commitments, commitment)
+		if len(commitments) == maxCommitments {
+			break
+		}
+	}
+	return commitments, nil
+}
+
+// Summary depths, from a one-line gist to a structured deep summary
+const (
+	SummaryGist     = "gist"
+	SummaryStandard = "standard"
+	SummaryDeep     = "deep"
+)
+
+// DeepSummary is a structured summary of an important thread
+type DeepSummary struct {
+	Summary       string   `json:"summary"`
+	Decisions     []string `json:"decisions"`
+	Owners        []string `json:"owners"` // "Name: action"
+	Risks         []string `json:"risks"`
+	OpenQuestions []string `json:"open_questions"`
+}
+
+// parseDeepSummary reads a deep summary from an LLM response, which may wrap the JSON in a
+// markdown code block
+func parseDeepSummary(response string) (*DeepSummary, error) {
+	response = strings.TrimSpace(response)
+	jsonStart := strings.Index(response, "{")
+	jsonEnd := strings.LastIndex(response, "}")
+	if jsonStart < 0 || jsonEnd < jsonStart {
+		return nil, fmt.Errorf("failed to parse deep summary: no JSON object in response")
+	}
+
+	summary := &DeepSummary{}
+	if err := json.Unmarshal([]byte(response[jsonStart:jsonEnd+1]), summary); err != nil {
+		return nil, fmt.Errorf("failed to parse deep summary: %w", err)
+	}
+	summary.Summary = strings.TrimSpace(summary.Summary)
+	if summary.Summary == "" {
+		return nil, fmt.Errorf("failed to parse deep summary: empty summary")
+	}
+	summary.Decisions = trimItems(summary.Decisions)
+	summary.Owners = trimItems(summary.Owners)
+	summary.Risks = trimItems(summary.Risks)
+	summary.OpenQuestions = trimItems(summary.OpenQuestions)
+	return summary, nil
+}
+
+// trimItems trims each item of a list and drops the empty ones
+func trimItems(items []string) []string {
+	var trimmed []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			trimmed = append(trimmed, item)
+		}
+	}
+	return trimmed
+}
+
 // EnrichTaskDescription generates a rich, contextual description for a task based on email thread
 func (g *GeminiClient) EnrichTaskDescription(ctx context.Context, task *db.Task, messages []*db.Message) (string, error) {
 	prompt := g.prompts.BuildTaskEnrichment(task, messages)
@@ -719,19 +1111,17 @@ func (g *GeminiClient) EnrichTaskDescription(ctx context.Context, task *db.Task,
 
 	// Generate enriched description with retry
 	startTime := time.Now()
-	resp, err := g.generateWithRetry(ctx, genai.Text(prompt))
+	resp, key, err := g.generateWithRetry(ctx, genai.Text(prompt))
 	if err != nil {
-		g.db.LogUsage("gemini", "enrich_task", 0, 0, time.Since(startTime), err)
+		g.db.LogKeyUsage("gemini", key, "enrich_task", 0, 0, time.Since(startTime), err)
 		return "", fmt.Errorf("failed to enrich task description: %w", err)
 	}
 
 	// Extract text
 	enrichedDesc := g.extractText(resp)
 
-	// Calculate usage
-	tokens := g.estimateTokens(prompt + enrichedDesc)
-	cost := g.calculateCost(tokens)
-	g.db.LogUsage("gemini", "enrich_task", tokens, cost, time.Since(startTime), nil)
+	// Log usage
+	tokens := g.logUsage(key, "enrich_task", g.modelName, resp, prompt, enrichedDesc, startTime)
 
 	// Cache response
 	cache := &db.LLMCache{
@@ -807,12 +1197,6 @@ func (g *GeminiClient) parseStrategicAlignmentResponse(response string) *Strateg
 	return result
 }
 
-// parseTasksFromResponse parses tasks from LLM response
-// Handles both single-line format: "1. Title: X | Owner: Y | Due: Z | Priority: W"
-// And multi-line format:
-//   1. **Title:** X
-//      * **Owner:** Y
-//      * **Due:** Z
 // isMeetingInvitationGemini checks if a task title is a meeting invitation (should be filtered)
 func isMeetingInvitationGemini(title string) bool {
 	titleLower := strings.ToLower(title)
@@ -867,7 +1251,14 @@ func isValidStakeholder(stakeholder string) bool {
 	return true
 }
 
-func (g *GeminiClient) parseTasksFromResponse(response string) []*db.Task {
+// parsePipeTasks parses tasks from the numbered list format extraction prompts used before
+// structured output, which models still fall back to occasionally.
+// Handles both single-line format: "1. Title: X | Owner: Y | Due: Z | Priority: W"
+// And multi-line format:
+//   1. **Title:** X
+//      * **Owner:** Y
+//      * **Due:** Z
+func parsePipeTasks(response string, now time.Time) []*db.Task {
 	var tasks []*db.Task
 	seenTitles := make(map[string]bool) // Track duplicate titles
 
@@ -906,7 +1297,6 @@ func (g *GeminiClient) parseTasksFromResponse(response string) []*db.Task {
 
 			// Start new task
 			currentTask = &db.Task{
-				ID:      fmt.Sprintf("ai_%d", time.Now().UnixNano()),
 				Source:  "ai",
 				Status:  "pending",
 				Impact:  3, // Default medium impact
@@ -925,13 +1315,13 @@ func (g *GeminiClient) parseTasksFromResponse(response string) []*db.Task {
 		if strings.Contains(line, "|") {
 			parts := strings.Split(line, "|")
 			for _, part := range parts {
-				g.parseTaskField(currentTask, part)
+				parseTaskField(currentTask, part, now)
 			}
 			continue
 		}
 
 		// Parse field: value format (multi-line tasks)
-		g.parseTaskField(currentTask, line)
+		parseTaskField(currentTask, line, now)
 	}
 
 	// Add last task (skip meeting invitations and duplicates)
@@ -946,8 +1336,8 @@ func (g *GeminiClient) parseTasksFromResponse(response string) []*db.Task {
 	return tasks
 }
 
-// parseTaskField parses a single field from a task line
-func (g *GeminiClient) parseTaskField(task *db.Task, field string) {
+// parseTaskField parses a single field from a task line. Due dates are relative to now.
+func parseTaskField(task *db.Task, field string, now time.Time) {
 	if task == nil {
 		return
 	}
@@ -996,9 +1386,9 @@ func (g *GeminiClient) parseTaskField(task *db.Task, field string) {
 	} else if strings.HasPrefix(field, "Due:") || strings.HasPrefix(fieldLower, "due:") {
 		dueStr := strings.TrimSpace(strings.TrimPrefix(field, "Due:"))
 		dueStr = strings.TrimSpace(strings.TrimPrefix(dueStr, "due:"))
-		task.DueTS = parseDueDate(dueStr)
+		task.DueTS = parseDueDate(dueStr, now)
 		if task.DueTS != nil {
-			task.Urgency = maxInt(task.Urgency, calculateUrgencyFromDue(*task.DueTS))
+			task.Urgency = maxInt(task.Urgency, calculateUrgencyFromDue(*task.DueTS, now))
 		}
 	} else if strings.HasPrefix(field, "Priority:") {
 		// Legacy support for old format
@@ -1021,16 +1411,15 @@ func (g *GeminiClient) parseTaskField(task *db.Task, field string) {
 	}
 }
 
-// parseDueDate parses due date strings with enhanced pattern matching (15+ patterns)
-func parseDueDate(dueStr string) *time.Time {
+// parseDueDate parses due date strings with enhanced pattern matching (15+ patterns).
+// Relative dates count from now, and dates without a time of day end in now's time zone.
+func parseDueDate(dueStr string, now time.Time) *time.Time {
 	dueStr = strings.ToLower(strings.TrimSpace(dueStr))
 
 	if dueStr == "" || dueStr == "n/a" || dueStr == "none" {
 		return nil
 	}
 
-	now := time.Now()
-
 	// Exact keyword matches
 	switch dueStr {
 	case "today":
@@ -1165,13 +1554,14 @@ func parseDueDate(dueStr string) *time.Time {
 	for _, format := range formats {
 		if parsed, err := time.Parse(format, dueStr); err == nil {
 			// For formats without year, use current year or next year
+			year := parsed.Year()
 			if !strings.Contains(format, "2006") {
-				year := now.Year()
+				year = now.Year()
 				if parsed.Month() < now.Month() || (parsed.Month() == now.Month() && parsed.Day() < now.Day()) {
 					year++
 				}
-				parsed = time.Date(year, parsed.Month(), parsed.Day(), 23, 59, 59, 0, now.Location())
 			}
+			parsed = time.Date(year, parsed.Month(), parsed.Day(), 23, 59, 59, 0, now.Location())
 			return &parsed
 		}
 	}
@@ -1181,8 +1571,8 @@ func parseDueDate(dueStr string) *time.Time {
 }
 
 // calculateUrgencyFromDue calculates urgency score based on due date
-func calculateUrgencyFromDue(dueDate time.Time) int {
-	hoursUntil := time.Until(dueDate).Hours()
+func calculateUrgencyFromDue(dueDate, now time.Time) int {
+	hoursUntil := dueDate.Sub(now).Hours()
 
 	switch {
 	case hoursUntil <= 0:
@@ -1226,16 +1616,14 @@ func (g *GeminiClient) hashPrompt(prompt string) string {
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
+// alignmentCacheKey versions a strategic alignment cache entry by the priorities it was
+// evaluated against, so editing priorities never serves matches against the old ones
+func (g *GeminiClient) alignmentCacheKey(prompt string, priorities *config.Priorities) string {
+	return g.hashPrompt("priorities:" + priorities.Hash() + "\n" + prompt)
+}
+
 // estimateTokens estimates token count (rough approximation)
 func (g *GeminiClient) estimateTokens(text string) int {
 	// Rough estimate: 1 token ≈ 4 characters
 	return len(text) / 4
-}
-
-// calculateCost calculates API cost (Gemini 1.5 Flash pricing)
-func (g *GeminiClient) calculateCost(tokens int) float64 {
-	// Gemini 1.5 Flash: $0.075 per 1M input tokens, $0.30 per 1M output tokens
-	// Using average for simplicity
-	costPerToken := 0.0000002 // $0.20 per 1M tokens average
-	return float64(tokens) * costPerToken
 }
\ No newline at end of file
@@ -0,0 +1,175 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// geminiQuotaZone is where Gemini's daily quotas reset at midnight
+const geminiQuotaZone = "America/Los_Angeles"
+
+// geminiKey is one API key with its own client and daily quota
+type geminiKey struct {
+	label     string // Identifies the key in logs and the usage table without revealing it
+	client    *genai.Client
+	requests  int  // Requests made today
+	exhausted bool // Gemini reported today's quota used up
+}
+
+// geminiKeyPool picks the API key for each request, failing over or rotating between keys and
+// skipping those whose daily quota is used up
+type geminiKeyPool struct {
+	mu            sync.Mutex
+	keys          []*geminiKey
+	roundRobin    bool
+	dailyRequests int // Requests per key per day (0 = no limit of our own)
+	next          int // Next key to try in round robin
+	day           time.Time
+}
+
+// keyLabel identifies an API key by its last four characters
+func keyLabel(apiKey string) string {
+	if len(apiKey) <= 4 {
+		return "…" + apiKey
+	}
+	return "…" + apiKey[len(apiKey)-4:]
+}
+
+// geminiQuotaDay returns the start of the quota day containing t
+func geminiQuotaDay(t time.Time) time.Time {
+	if loc, err := time.LoadLocation(geminiQuotaZone); err == nil {
+		t = t.In(loc)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// loadUsage restores today's per-key request counts and exhausted quotas from the usage table,
+// so a restart doesn't retry keys that already ran out
+func (p *geminiKeyPool) loadUsage(database *db.DB) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.day = geminiQuotaDay(time.Now())
+	usage, err := database.GetKeyUsageSince("gemini", p.day)
+	if err != nil {
+		return err
+	}
+	for _, key := range p.keys {
+		if u := usage[key.label]; u != nil {
+			key.requests = u.Requests
+			key.exhausted = u.Exhausted
+		}
+	}
+	return nil
+}
+
+// acquire returns the key to use for the next request and counts the request against it, or
+// nil if every key's quota is used up
+func (p *geminiKeyPool) acquire() *geminiKey {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Quotas reset at midnight Pacific
+	if day := geminiQuotaDay(time.Now()); !day.Equal(p.day) {
+		p.day = day
+		for _, key := range p.keys {
+			key.requests = 0
+			key.exhausted = false
+		}
+	}
+
+	start := 0
+	if p.roundRobin {
+		start = p.next
+	}
+	for i := range p.keys {
+		idx := (start + i) % len(p.keys)
+		key := p.keys[idx]
+		if key.exhausted || (p.dailyRequests > 0 && key.requests >= p.dailyRequests) {
+			continue
+		}
+		key.requests++
+		p.next = (idx + 1) % len(p.keys)
+		return key
+	}
+	return nil
+}
+
+// markExhausted takes a key out of rotation until its quota resets
+func (p *geminiKeyPool) markExhausted(key *geminiKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key.exhausted = true
+}
+
+// available reports how many keys still have quota today
+func (p *geminiKeyPool) available() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	count := 0
+	for _, key := range p.keys {
+		if !key.exhausted && (p.dailyRequests == 0 || key.requests < p.dailyRequests) {
+			count++
+		}
+	}
+	return count
+}
+
+// close closes every key's client
+func (p *geminiKeyPool) close() error {
+	var firstErr error
+	for _, key := range p.keys {
+		if err := key.client.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// newGeminiKeyPool creates a client for each API key
+func newGeminiKeyPool(ctx context.Context, apiKeys []string, strategy string, dailyRequests int) (*geminiKeyPool, error) {
+	if len(apiKeys) == 0 {
+		return nil, fmt.Errorf("no Gemini API key configured")
+	}
+
+	pool := &geminiKeyPool{
+		roundRobin:    strategy == "round_robin",
+		dailyRequests: dailyRequests,
+		day:           geminiQuotaDay(time.Now()),
+	}
+	for _, apiKey := range apiKeys {
+		client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+		if err != nil {
+			pool.close()
+			return nil, fmt.Errorf("failed to create Gemini client for key %s: %w", keyLabel(apiKey), err)
+		}
+		pool.keys = append(pool.keys, &geminiKey{label: keyLabel(apiKey), client: client})
+	}
+	if len(pool.keys) > 1 {
+		log.Printf("Gemini key pool: %d keys (%s)", len(pool.keys), strategy)
+	}
+	return pool, nil
+}
+
+// quotaExhaustedError describes a request that found every key's quota used up
+func (p *geminiKeyPool) quotaExhaustedError() *DailyQuotaExceededError {
+	if len(p.keys) == 1 {
+		return &DailyQuotaExceededError{
+			Message: "Daily API quota exhausted (250 requests/day free tier limit). Quota resets in ~24 hours.",
+		}
+	}
+	reset := geminiQuotaDay(time.Now()).AddDate(0, 0, 1)
+	return &DailyQuotaExceededError{
+		Message: fmt.Sprintf("Daily API quota exhausted on all %d Gemini API keys. Quotas reset at %s.",
+			len(p.keys), reset.Local().Format("Mon 3:04 PM")),
+	}
+}
@@ -0,0 +1,145 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+)
+
+// healthCheckTimeout bounds each host's reachability and load-state checks
+const healthCheckTimeout = 5 * time.Second
+
+// ProviderHealth describes whether an LLM provider can be used right now
+type ProviderHealth struct {
+	Provider  string              `json:"provider"`
+	Available bool                `json:"available"`           // Initialized and tried in the fallback chain
+	Exhausted bool                `json:"exhausted,omitempty"` // Out of quota or over its llm.budgets cap for today
+	Detail    string              `json:"detail,omitempty"`    // Claude CLI path, or Gemini keys with quota left
+	Hosts     []*OllamaHostHealth `json:"hosts,omitempty"`     // Ollama only
+}
+
+// OllamaHostHealth is the reachability and model load state of one Ollama host
+type OllamaHostHealth struct {
+	Name        string     `json:"name"`
+	URL         string     `json:"url"`
+	Reachable   bool       `json:"reachable"`
+	ModelLoaded bool       `json:"model_loaded"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // When the host unloads the model if left idle
+	Error       string     `json:"error,omitempty"`
+}
+
+// ollamaHostClients returns a client for each configured Ollama host, in configured order
+func ollamaHostClients(cfg *config.Config, prompts *PromptBuilder) []*OllamaClient {
+	clients := make([]*OllamaClient, 0, len(cfg.Ollama.Hosts))
+	for _, host := range cfg.Ollama.Hosts {
+		client := NewOllamaClient(host.URL, cfg.Ollama.Model, prompts)
+		client.keepAlive = cfg.Ollama.KeepAlive
+		client.schedule = &cfg.Schedule
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// Warmup loads the Ollama model on every configured host, in parallel, so the first request of
+// a processing run doesn't wait for it to load. Hosts that fail are logged and skipped.
+func (h *HybridClient) Warmup(ctx context.Context) error {
+	if h.ollama == nil {
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for _, client := range ollamaHostClients(h.config, h.prompts) {
+		wg.Add(1)
+		go func(client *OllamaClient) {
+			defer wg.Done()
+			start := time.Now()
+			if err := client.Preload(ctx); err != nil {
+				log.Printf("Failed to warm up Ollama model on %s: %v", client.baseURL, err)
+				return
+			}
+			log.Printf("Ollama model %s warm on %s (%s)", client.model, client.baseURL, time.Since(start).Round(time.Millisecond))
+		}(client)
+	}
+	wg.Wait()
+	return nil
+}
+
+// ProviderHealth reports each provider in fallback order, with the reachability and model load
+// state of every Ollama host
+func (h *HybridClient) ProviderHealth(ctx context.Context) []*ProviderHealth {
+	health := make([]*ProviderHealth, 0, len(h.providers))
+	for _, provider := range h.providers {
+		status := &ProviderHealth{
+			Provider:  provider,
+			Available: h.providerAvailable(provider),
+			Exhausted: h.budgetExhausted(provider),
+		}
+		switch provider {
+		case config.ProviderOllama:
+			if h.config.Ollama.Enabled {
+				status.Hosts = h.ollamaHostHealth(ctx)
+			}
+		case config.ProviderClaude:
+			status.Detail = "claude CLI not found on PATH"
+			if h.claudePath != "" {
+				status.Detail = h.claudePath
+			}
+		case config.ProviderGemini:
+			if h.gemini != nil {
+				gemini := h.gemini.ProviderHealth(ctx)[0]
+				status.Exhausted = status.Exhausted || gemini.Exhausted
+				status.Detail = gemini.Detail
+			}
+		}
+		health = append(health, status)
+	}
+	return health
+}
+
+// ollamaHostHealth checks every configured Ollama host, in configured order
+func (h *HybridClient) ollamaHostHealth(ctx context.Context) []*OllamaHostHealth {
+	clients := ollamaHostClients(h.config, h.prompts)
+	hosts := make([]*OllamaHostHealth, len(h.config.Ollama.Hosts))
+
+	var wg sync.WaitGroup
+	for i, host := range h.config.Ollama.Hosts {
+		hosts[i] = &OllamaHostHealth{Name: host.Name, URL: host.URL}
+		wg.Add(1)
+		go func(status *OllamaHostHealth, client *OllamaClient) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			loaded, expiresAt, err := client.ModelLoaded(ctx)
+			if err != nil {
+				status.Error = err.Error()
+				return
+			}
+			status.Reachable = true
+			status.ModelLoaded = loaded
+			status.ExpiresAt = expiresAt
+		}(hosts[i], clients[i])
+	}
+	wg.Wait()
+	return hosts
+}
+
+// Warmup is a no-op: Gemini has no model to load
+func (g *GeminiClient) Warmup(ctx context.Context) error {
+	return nil
+}
+
+// ProviderHealth reports Gemini as the only provider, with how many API keys have quota left
+func (g *GeminiClient) ProviderHealth(ctx context.Context) []*ProviderHealth {
+	available := g.keys.available()
+	return []*ProviderHealth{{
+		Provider:  config.ProviderGemini,
+		Available: true,
+		Exhausted: available == 0,
+		Detail:    fmt.Sprintf("%d of %d API keys have quota left today", available, len(g.keys.keys)),
+	}}
+}
@@ -18,11 +18,13 @@ type OllamaInterface interface {
 	SummarizeThread(ctx context.Context, messages []*db.Message) (string, error)
 	ExtractTasks(ctx context.Context, content, userEmail string) ([]*db.Task, error)
 	EnrichTaskDescription(ctx context.Context, prompt string) (string, error)
+	Complete(ctx context.Context, prompt, format string) (string, error)
 	EvaluateStrategicAlignment(ctx context.Context, task *db.Task, priorities *config.Priorities) (*StrategicAlignmentResult, error)
 }
 
-// HybridClient uses Ollama as primary, Claude CLI as secondary, and Gemini as final fallback
+// HybridClient tries Ollama, Claude CLI and Gemini in the order configured by llm.provider_order
 type HybridClient struct {
+	providers         []string                 // Provider fallback order from config
 	ollama            OllamaInterface          // Can be *OllamaClient or *DistributedOllamaClient
 	distributedOllama *DistributedOllamaClient // Keep reference for shutdown
 	claudePath        string
@@ -32,13 +34,13 @@ type HybridClient struct {
 	prompts           *PromptBuilder
 }
 
-// NewHybridClient creates a hybrid LLM client with fallback chain: Ollama -> Claude CLI -> Gemini
-func NewHybridClient(geminiAPIKey string, database *db.DB, cfg *config.Config) (*HybridClient, error) {
+// NewHybridClient creates a hybrid LLM client with a configurable fallback chain (default: Ollama -> Claude CLI -> Gemini)
+func NewHybridClient(geminiAPIKeys []string, database *db.DB, cfg *config.Config) (*HybridClient, error) {
 	// Create centralized prompt builder
-	prompts := NewPromptBuilder(cfg.Google.UserEmail)
+	prompts := NewPromptBuilder(cfg.Google.UserEmail, cfg.LLM.PromptsDir)
 
 	// Initialize Gemini client as final fallback
-	geminiClient, err := NewGeminiClient(geminiAPIKey, database, cfg, prompts)
+	geminiClient, err := NewGeminiClient(geminiAPIKeys, database, cfg, prompts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Gemini fallback client: %w", err)
 	}
@@ -58,6 +60,8 @@ func NewHybridClient(geminiAPIKey string, database *db.DB, cfg *config.Config) (
 			// Single host - use simple client
 			host := cfg.Ollama.Hosts[0]
 			simpleClient := NewOllamaClient(host.URL, cfg.Ollama.Model, prompts)
+			simpleClient.keepAlive = cfg.Ollama.KeepAlive
+			simpleClient.schedule = &cfg.Schedule
 
 			// Test connectivity
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -89,7 +93,10 @@ func NewHybridClient(geminiAPIKey string, database *db.DB, cfg *config.Config) (
 		log.Printf("Claude CLI found at: %s", claudePath)
 	}
 
+	log.Printf("LLM provider order: %s", strings.Join(cfg.LLM.ProviderOrder, " -> "))
+
 	return &HybridClient{
+		providers:         cfg.LLM.ProviderOrder,
 		ollama:            ollamaInterface,
 		distributedOllama: distributedOllama,
 		claudePath:        claudePath,
@@ -113,30 +120,82 @@ func (h *HybridClient) Close() error {
 	return nil
 }
 
-// callClaude executes the claude CLI with the given prompt
-func (h *HybridClient) callClaude(ctx context.Context, prompt string) (string, error) {
-	if h.claudePath == "" {
-		return "", fmt.Errorf("claude CLI not available")
-	}
+// providerAvailable reports whether a provider was initialized and can be tried
+func (h *HybridClient) providerAvailable(provider string) bool {
+	switch provider {
+	case config.ProviderOllama:
+		return h.ollama != nil
+	case config.ProviderClaude:
+		return h.claudePath != ""
+	case config.ProviderGemini:
+		return h.gemini != nil
+	}
+	return false
+}
 
-	cmd := exec.CommandContext(ctx,
-		h.claudePath,
-		"-p",
-		"--model", "haiku",
-		"--dangerously-skip-permissions",
-		"--output-format", "text",
-		prompt,
-	)
+// budgetExhausted reports whether a provider has reached its llm.budgets cap for today.
+// Providers without a budget are never exhausted.
+func (h *HybridClient) budgetExhausted(provider string) bool {
+	budget, ok := h.config.LLM.Budgets[provider]
+	if !ok {
+		return false
+	}
 
-	output, err := cmd.Output()
+	status, err := h.db.GetProviderBudget(provider, budget)
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return "", fmt.Errorf("claude CLI failed: %w (stderr: %s)", err, string(exitErr.Stderr))
+		// Failing open keeps processing going; the next call checks again
+		log.Printf("Warning: failed to check %s budget: %v", provider, err)
+		return false
+	}
+	return status.Exhausted()
+}
+
+// tryProviders runs the attempts in configured provider order until one succeeds.
+// Providers without an attempt for this operation, that are unavailable, or that have used
+// up their daily budget are skipped.
+func (h *HybridClient) tryProviders(operation string, attempts map[string]func() error) error {
+	var lastErr error
+	tried := 0
+
+	for _, provider := range h.providers {
+		attempt, ok := attempts[provider]
+		if !ok || !h.providerAvailable(provider) {
+			continue
 		}
-		return "", fmt.Errorf("claude CLI execution failed: %w", err)
+		if h.budgetExhausted(provider) {
+			log.Printf("Skipping %s for %s: daily budget exhausted", provider, operation)
+			continue
+		}
+
+		tried++
+		startTime := time.Now()
+		if err := attempt(); err != nil {
+			log.Printf("⚠ %s failed for %s: %v", provider, operation, err)
+			lastErr = err
+			continue
+		}
+
+		log.Printf("✓ %s succeeded for %s (%.2fs)", provider, operation, time.Since(startTime).Seconds())
+		return nil
 	}
 
-	return strings.TrimSpace(string(output)), nil
+	if tried == 0 {
+		return fmt.Errorf("no LLM provider available for %s", operation)
+	}
+	return fmt.Errorf("all LLM providers failed for %s: %w", operation, lastErr)
+}
+
+// cacheResponse stores a provider response in the LLM cache
+func (h *HybridClient) cacheResponse(hash, prompt, response, model string, ttl time.Duration) {
+	cache := &db.LLMCache{
+		Hash:      hash,
+		Prompt:    prompt,
+		Response:  response,
+		Model:     model,
+		Tokens:    h.gemini.estimateTokens(prompt + response),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	h.db.SaveCachedResponse(cache)
 }
 
 // extractTasksWithClaude uses Claude CLI to extract tasks from full message thread
@@ -153,129 +212,25 @@ func (h *HybridClient) extractTasksWithClaude(ctx context.Context, messages []*d
 	cached, err := h.db.GetCachedResponse(hash)
 	if err == nil && cached != nil {
 		log.Printf("Using cached Claude task extraction")
-		return h.gemini.parseTasksFromResponse(cached.Response), nil
+		return parseTaskResponse(cached.Response, h.config.Schedule.Now()), nil
 	}
 
-	// Call Claude CLI with JSON output
-	cmd := exec.CommandContext(ctx,
-		h.claudePath,
-		"-p",
-		"--model", "haiku",
-		"--dangerously-skip-permissions",
-		"--output-format", "text",
-		prompt,
-	)
-
+	// Call Claude CLI
 	startTime := time.Now()
-	output, err := cmd.Output()
+	response, err := h.callClaude(ctx, "extract_tasks", prompt)
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("claude CLI failed: %w (stderr: %s)", err, string(exitErr.Stderr))
-		}
-		return nil, fmt.Errorf("claude CLI execution failed: %w", err)
+		return nil, err
 	}
-
-	response := strings.TrimSpace(string(output))
 	log.Printf("✓ Claude CLI succeeded for task extraction (%.2fs)", time.Since(startTime).Seconds())
 
 	// Cache the response
-	tokens := h.gemini.estimateTokens(prompt + response)
-	cache := &db.LLMCache{
-		Hash:      hash,
-		Prompt:    prompt,
-		Response:  response,
-		Model:     "claude-haiku",
-		Tokens:    tokens,
-		ExpiresAt: time.Now().Add(24 * time.Hour),
-	}
-	h.db.SaveCachedResponse(cache)
-	h.db.LogUsage("claude", "extract_tasks", tokens, 0, time.Since(startTime), nil)
-
-	// Parse tasks from pipe-delimited response (same format as Gemini/Ollama)
-	return h.gemini.parseTasksFromResponse(response), nil
-}
+	h.cacheResponse(hash, prompt, response, "claude-haiku", 24*time.Hour)
 
-// parseTasksFromJSON parses Claude's JSON response into tasks
-func (h *HybridClient) parseTasksFromJSON(response string, userEmail string) ([]*db.Task, error) {
-	// Extract JSON from response (might have markdown code blocks)
-	jsonStr := response
-	if strings.Contains(response, "```json") {
-		start := strings.Index(response, "```json") + 7
-		end := strings.LastIndex(response, "```")
-		if start > 7 && end > start {
-			jsonStr = strings.TrimSpace(response[start:end])
-		}
-	} else if strings.Contains(response, "```") {
-		start := strings.Index(response, "```") + 3
-		end := strings.LastIndex(response, "```")
-		if start > 3 && end > start {
-			jsonStr = strings.TrimSpace(response[start:end])
-		}
-	}
-
-	// Parse JSON
-	var result struct {
-		Tasks []struct {
-			Title     string `json:"title"`
-			Priority  string `json:"priority"`
-			DueDate   string `json:"due_date"`
-			Reasoning string `json:"reasoning"`
-		} `json:"tasks"`
-	}
-
-	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON response: %w (response: %s)", err, jsonStr)
-	}
-
-	// Convert to db.Task objects
-	var tasks []*db.Task
-	for _, t := range result.Tasks {
-		if t.Title == "" {
-			continue
-		}
-
-		// Map priority string to urgency/impact scores
-		urgency := 3 // Default medium
-		impact := 3  // Default medium
-		switch strings.ToLower(t.Priority) {
-		case "high":
-			urgency = 4
-			impact = 4
-		case "low":
-			urgency = 2
-			impact = 2
-		}
-
-		task := &db.Task{
-			Title:   t.Title,
-			Urgency: urgency,
-			Impact:  impact,
-			Effort:  "M", // Default effort
-			Status:  "pending",
-			ID:      fmt.Sprintf("ai_%d", time.Now().UnixNano()),
-			Source:  "ai",
-		}
-
-		if userEmail != "" {
-			task.Stakeholder = userEmail
-		} else {
-			task.Stakeholder = "me"
-		}
-
-		// Parse due date if provided
-		if t.DueDate != "" && t.DueDate != "0000-00-00" {
-			if dueTime, err := time.Parse("2006-01-02", t.DueDate); err == nil {
-				task.DueTS = &dueTime
-			}
-		}
-
-		tasks = append(tasks, task)
-	}
-
-	return tasks, nil
+	// The CLI can't enforce a schema, so this relies on the tolerant parser
+	return parseTaskResponse(response, h.config.Schedule.Now()), nil
 }
 
-// SummarizeThread summarizes an email thread (Claude primary, Gemini fallback)
+// SummarizeThread summarizes an email thread (Claude CLI and Gemini, in configured order)
 func (h *HybridClient) SummarizeThread(ctx context.Context, messages []*db.Message) (string, error) {
 	// Build prompt
 	prompt := h.prompts.BuildThreadSummary(messages)
@@ -288,69 +243,129 @@ func (h *HybridClient) SummarizeThread(ctx context.Context, messages []*db.Messa
 		return cached.Response, nil
 	}
 
-	// Try Claude CLI first
-	startTime := time.Now()
-	summary, err := h.callClaude(ctx, prompt)
-	if err == nil {
-		log.Printf("✓ Claude CLI succeeded for SummarizeThread (%.2fs)", time.Since(startTime).Seconds())
-
-		// Cache the response
-		tokens := h.gemini.estimateTokens(prompt + summary)
-		cache := &db.LLMCache{
-			Hash:      hash,
-			Prompt:    prompt,
-			Response:  summary,
-			Model:     "claude-haiku",
-			Tokens:    tokens,
-			ExpiresAt: time.Now().Add(h.gemini.cacheTTL),
-		}
-		h.db.SaveCachedResponse(cache)
-
-		// Log usage
-		h.db.LogUsage("claude", "summarize_thread", tokens, 0, time.Since(startTime), nil)
-
-		return summary, nil
-	}
-
-	// Fallback to Gemini
-	log.Printf("⚠ Claude CLI failed, falling back to Gemini: %v", err)
-	return h.gemini.SummarizeThread(ctx, messages)
+	var summary string
+	err = h.tryProviders("SummarizeThread", map[string]func() error{
+		config.ProviderClaude: func() error {
+			result, err := h.callClaude(ctx, "summarize_thread", prompt)
+			if err != nil {
+				return err
+			}
+			h.cacheResponse(hash, prompt, result, "claude-haiku", h.gemini.cacheTTL)
+			summary = result
+			return nil
+		},
+		config.ProviderGemini: func() error {
+			result, err := h.gemini.SummarizeThread(ctx, messages)
+			summary = result
+			return err
+		},
+	})
+	return summary, err
 }
 
-// SummarizeThreadWithModelSelection summarizes with 3-tier fallback (Ollama → Claude → Gemini)
+// SummarizeThreadWithModelSelection summarizes using the configured provider order
+// (Gemini picks Pro/Flash based on thread metadata)
 func (h *HybridClient) SummarizeThreadWithModelSelection(ctx context.Context, messages []*db.Message, metadata ThreadMetadata) (string, error) {
-	// Try Ollama first (free, unlimited local processing)
-	if h.ollama != nil {
-		summary, err := h.ollama.SummarizeThread(ctx, messages)
-		if err == nil && summary != "" {
-			log.Printf("Thread summary generated using Ollama (qwen2.5)")
-			return summary, nil
-		}
-		log.Printf("Ollama summarization failed, falling back to Claude: %v", err)
-	}
+	var summary string
+	err := h.tryProviders("SummarizeThreadWithModelSelection", map[string]func() error{
+		config.ProviderOllama: func() error {
+			startTime := time.Now()
+			result, err := h.ollama.SummarizeThread(ctx, messages)
+			if err == nil && result == "" {
+				err = fmt.Errorf("empty summary")
+			}
+			if err == nil {
+				h.logOllamaUsage("summarize_thread", h.prompts.BuildThreadSummary(messages), result, startTime)
+			}
+			summary = result
+			return err
+		},
+		config.ProviderClaude: func() error {
+			prompt := h.prompts.BuildThreadSummary(messages)
+			result, err := h.callClaude(ctx, "summarize_thread", prompt)
+			if err == nil && result == "" {
+				err = fmt.Errorf("empty summary")
+			}
+			summary = result
+			return err
+		},
+		config.ProviderGemini: func() error {
+			result, err := h.gemini.SummarizeThreadWithModelSelection(ctx, messages, metadata)
+			summary = result
+			return err
+		},
+	})
+	return summary, err
+}
 
-	// Try Claude CLI second (free, remote API)
-	if h.claudePath != "" {
-		prompt := h.prompts.BuildThreadSummary(messages)
-		summary, err := h.callClaude(ctx, prompt)
-		if err == nil && summary != "" {
-			log.Printf("Thread summary generated using Claude CLI")
-			return summary, nil
-		}
-		log.Printf("Claude summarization failed, falling back to Gemini: %v", err)
-	}
+// SummarizeThreadGist sums up a low-priority thread in one line, on the cheap providers only
+// (Ollama and Gemini Flash, in configured order)
+func (h *HybridClient) SummarizeThreadGist(ctx context.Context, messages []*db.Message) (string, error) {
+	prompt := h.prompts.BuildThreadGist(messages)
+
+	var gist string
+	err := h.tryProviders("SummarizeThreadGist", map[string]func() error{
+		config.ProviderOllama: func() error {
+			startTime := time.Now()
+			result, err := h.ollama.Complete(ctx, prompt, "")
+			if err == nil && strings.TrimSpace(result) == "" {
+				err = fmt.Errorf("empty gist")
+			}
+			if err == nil {
+				h.logOllamaUsage("summarize_thread_gist", prompt, result, startTime)
+			}
+			gist = strings.TrimSpace(result)
+			return err
+		},
+		config.ProviderGemini: func() error {
+			result, err := h.gemini.SummarizeThreadGist(ctx, messages)
+			gist = result
+			return err
+		},
+	})
+	return gist, err
+}
 
-	// Final fallback to Gemini (with Pro/Flash model selection)
-	log.Printf("Using Gemini for thread summarization (fallback)")
-	return h.gemini.SummarizeThreadWithModelSelection(ctx, messages, metadata)
+// SummarizeThreadDeep writes a structured summary of an important thread using the configured
+// provider order (Gemini picks Pro/Flash based on thread metadata)
+func (h *HybridClient) SummarizeThreadDeep(ctx context.Context, messages []*db.Message, metadata ThreadMetadata) (*DeepSummary, error) {
+	prompt := h.prompts.BuildThreadDeepSummary(messages)
+
+	var summary *DeepSummary
+	err := h.tryProviders("SummarizeThreadDeep", map[string]func() error{
+		config.ProviderOllama: func() error {
+			startTime := time.Now()
+			response, err := h.ollama.Complete(ctx, prompt, "json")
+			if err != nil {
+				return err
+			}
+			h.logOllamaUsage("summarize_thread_deep", prompt, response, startTime)
+			summary, err = parseDeepSummary(response)
+			return err
+		},
+		config.ProviderClaude: func() error {
+			response, err := h.callClaude(ctx, "summarize_thread_deep", prompt)
+			if err != nil {
+				return err
+			}
+			summary, err = parseDeepSummary(response)
+			return err
+		},
+		config.ProviderGemini: func() error {
+			result, err := h.gemini.SummarizeThreadDeep(ctx, messages, metadata)
+			summary = result
+			return err
+		},
+	})
+	return summary, err
 }
 
-// ExtractTasks extracts action items with 3-tier fallback
+// ExtractTasks extracts action items using the configured provider order
 func (h *HybridClient) ExtractTasks(ctx context.Context, content string) ([]*db.Task, error) {
 	return h.ExtractTasksFromMessages(ctx, content, nil, nil, nil)
 }
 
-// ExtractTasksFromMessages extracts tasks with 3-tier fallback (Ollama → Claude → Gemini)
+// ExtractTasksFromMessages extracts tasks using the configured provider order
 // Now accepts Front data for enhanced context
 func (h *HybridClient) ExtractTasksFromMessages(ctx context.Context, content string, messages []*db.Message, frontComments []*db.FrontComment, frontMetadata *db.FrontMetadata) ([]*db.Task, error) {
 	userEmail := ""
@@ -358,41 +373,48 @@ func (h *HybridClient) ExtractTasksFromMessages(ctx context.Context, content str
 		userEmail = h.gemini.config.Google.UserEmail
 	}
 
-	// Try Ollama first (free, unlimited local processing)
-	if h.ollama != nil {
-		tasks, err := h.ollama.ExtractTasks(ctx, content, userEmail)
-		if err == nil {
-			// Ollama succeeded - return results even if empty (no tasks found)
-			if len(tasks) > 0 {
-				log.Printf("Extracted %d tasks using Ollama (qwen2.5)", len(tasks))
-			} else {
-				log.Printf("Ollama processed thread successfully - no tasks found")
+	var tasks []*db.Task
+	attempts := map[string]func() error{
+		config.ProviderOllama: func() error {
+			// Ollama success counts even if empty (no tasks found)
+			startTime := time.Now()
+			result, err := h.ollama.ExtractTasks(ctx, content, userEmail)
+			if err == nil {
+				h.logOllamaUsage("extract_tasks", content, "", startTime)
 			}
-			return tasks, nil
+			tasks = result
+			return err
+		},
+		config.ProviderGemini: func() error {
+			// Gemini handles sent email detection and filtering
+			result, err := h.gemini.ExtractTasksFromMessages(ctx, content, messages, frontComments, frontMetadata)
+			tasks = result
+			return err
+		},
+	}
+
+	// Claude works from the full message context + Front data, so it needs messages
+	if len(messages) > 0 {
+		attempts[config.ProviderClaude] = func() error {
+			result, err := h.extractTasksWithClaude(ctx, messages, frontComments, frontMetadata, userEmail)
+			tasks = result
+			return err
 		}
-		log.Printf("Ollama task extraction failed, falling back to Claude: %v", err)
 	}
 
-	// Try Claude CLI second (with full message context + Front data for better intelligence)
-	if h.claudePath != "" && len(messages) > 0 {
-		tasks, err := h.extractTasksWithClaude(ctx, messages, frontComments, frontMetadata, userEmail)
-		if err == nil {
-			if len(tasks) > 0 {
-				log.Printf("Extracted %d tasks using Claude CLI", len(tasks))
-			} else {
-				log.Printf("Claude CLI processed thread successfully - no tasks found")
-			}
-			return tasks, nil
-		}
-		log.Printf("Claude task extraction failed, falling back to Gemini: %v", err)
+	if err := h.tryProviders("ExtractTasks", attempts); err != nil {
+		return nil, err
 	}
 
-	// Final fallback to Gemini (handles sent email detection and filtering)
-	log.Printf("Using Gemini for task extraction (fallback)")
-	return h.gemini.ExtractTasksFromMessages(ctx, content, messages, frontComments, frontMetadata)
+	if len(tasks) > 0 {
+		log.Printf("Extracted %d tasks", len(tasks))
+	} else {
+		log.Printf("Thread processed successfully - no tasks found")
+	}
+	return tasks, nil
 }
 
-// EnrichTaskDescription generates rich contextual descriptions (Ollama -> Claude CLI -> Gemini fallback)
+// EnrichTaskDescription generates rich contextual descriptions using the configured provider order
 func (h *HybridClient) EnrichTaskDescription(ctx context.Context, task *db.Task, messages []*db.Message) (string, error) {
 	// Build prompt
 	prompt := h.prompts.BuildTaskEnrichment(task, messages)
@@ -405,219 +427,280 @@ func (h *HybridClient) EnrichTaskDescription(ctx context.Context, task *db.Task,
 		return cached.Response, nil
 	}
 
-	// Try Ollama first (distributed or single client)
-	if h.ollama != nil {
-		startTime := time.Now()
-		enrichedDesc, err := h.ollama.EnrichTaskDescription(ctx, prompt)
-		if err == nil {
-			log.Printf("✓ Ollama succeeded for EnrichTaskDescription (%.2fs)", time.Since(startTime).Seconds())
-
-			// Cache the response
-			tokens := h.gemini.estimateTokens(prompt + enrichedDesc)
-			cache := &db.LLMCache{
-				Hash:      hash,
-				Prompt:    prompt,
-				Response:  enrichedDesc,
-				Model:     "ollama-" + h.config.Ollama.Model,
-				Tokens:    tokens,
-				ExpiresAt: time.Now().Add(h.gemini.cacheTTL),
+	var enrichedDesc string
+	err = h.tryProviders("EnrichTaskDescription", map[string]func() error{
+		config.ProviderOllama: func() error {
+			startTime := time.Now()
+			result, err := h.ollama.EnrichTaskDescription(ctx, prompt)
+			if err != nil {
+				return err
 			}
-			h.db.SaveCachedResponse(cache)
-
-			// Log usage (free, so cost = 0)
-			h.db.LogUsage("ollama", "enrich_task", tokens, 0, time.Since(startTime), nil)
-
-			return enrichedDesc, nil
-		}
-		log.Printf("⚠ Ollama failed for EnrichTaskDescription: %v", err)
-	}
-
-	// Try Claude CLI second
-	startTime := time.Now()
-	enrichedDesc, err := h.callClaude(ctx, prompt)
-	if err == nil {
-		log.Printf("✓ Claude CLI succeeded for EnrichTaskDescription (%.2fs)", time.Since(startTime).Seconds())
-
-		// Cache the response
-		tokens := h.gemini.estimateTokens(prompt + enrichedDesc)
-		cache := &db.LLMCache{
-			Hash:      hash,
-			Prompt:    prompt,
-			Response:  enrichedDesc,
-			Model:     "claude-haiku",
-			Tokens:    tokens,
-			ExpiresAt: time.Now().Add(h.gemini.cacheTTL),
-		}
-		h.db.SaveCachedResponse(cache)
-
-		// Log usage
-		h.db.LogUsage("claude", "enrich_task", tokens, 0, time.Since(startTime), nil)
-
-		return enrichedDesc, nil
-	}
-
-	// Final fallback to Gemini
-	log.Printf("⚠ Claude CLI failed, falling back to Gemini: %v", err)
-	return h.gemini.EnrichTaskDescription(ctx, task, messages)
+			h.cacheResponse(hash, prompt, result, "ollama-"+h.config.Ollama.Model, h.gemini.cacheTTL)
+			h.logOllamaUsage("enrich_task", prompt, result, startTime)
+			enrichedDesc = result
+			return nil
+		},
+		config.ProviderClaude: func() error {
+			result, err := h.callClaude(ctx, "enrich_task", prompt)
+			if err != nil {
+				return err
+			}
+			h.cacheResponse(hash, prompt, result, "claude-haiku", h.gemini.cacheTTL)
+			enrichedDesc = result
+			return nil
+		},
+		config.ProviderGemini: func() error {
+			result, err := h.gemini.EnrichTaskDescription(ctx, task, messages)
+			enrichedDesc = result
+			return err
+		},
+	})
+	return enrichedDesc, err
 }
 
-// EvaluateStrategicAlignment evaluates strategic alignment (Ollama -> Claude -> Gemini fallback)
+// EvaluateStrategicAlignment evaluates strategic alignment using the configured provider order
 func (h *HybridClient) EvaluateStrategicAlignment(ctx context.Context, task *db.Task, priorities *config.Priorities) (*StrategicAlignmentResult, error) {
 	// Build prompt
 	prompt := h.prompts.BuildStrategicAlignment(task, priorities)
 
 	// Check cache
-	hash := h.gemini.hashPrompt(prompt)
+	hash := h.gemini.alignmentCacheKey(prompt, priorities)
 	cached, err := h.db.GetCachedResponse(hash)
 	if err == nil && cached != nil {
 		log.Printf("Using cached strategic alignment")
 		return h.gemini.parseStrategicAlignmentResponse(cached.Response), nil
 	}
 
-	// Try Ollama first (qwen2.5:7b with JSON format)
-	if h.ollama != nil {
-		startTime := time.Now()
-		result, err := h.ollama.EvaluateStrategicAlignment(ctx, task, priorities)
-		if err == nil {
-			log.Printf("✓ Ollama succeeded for EvaluateStrategicAlignment (%.2fs)", time.Since(startTime).Seconds())
+	var alignment *StrategicAlignmentResult
+	err = h.tryProviders("EvaluateStrategicAlignment", map[string]func() error{
+		config.ProviderOllama: func() error {
+			// qwen2.5:7b with JSON format
+			startTime := time.Now()
+			result, err := h.ollama.EvaluateStrategicAlignment(ctx, task, priorities)
+			if err != nil {
+				return err
+			}
 
 			// Convert result back to JSON for caching
 			resultJSON, _ := json.Marshal(result)
-			response := string(resultJSON)
-
-			// Cache the response
-			tokens := h.gemini.estimateTokens(prompt + response)
-			cache := &db.LLMCache{
-				Hash:      hash,
-				Prompt:    prompt,
-				Response:  response,
-				Model:     "ollama-" + h.config.Ollama.Model,
-				Tokens:    tokens,
-				ExpiresAt: time.Now().Add(7 * 24 * time.Hour), // 7 days like Gemini
+			h.cacheResponse(hash, prompt, string(resultJSON), "ollama-"+h.config.Ollama.Model, 7*24*time.Hour) // 7 days like Gemini
+			h.logOllamaUsage("strategic_alignment", prompt, string(resultJSON), startTime)
+			alignment = result
+			return nil
+		},
+		config.ProviderClaude: func() error {
+			// Add JSON formatting instruction for Claude
+			claudePrompt := prompt + "\n\nIMPORTANT: Respond with ONLY a valid JSON object, no markdown formatting or explanation. The JSON must have these exact fields: score (number), okrs (array of strings), focus_areas (array of strings), projects (array of strings), reasoning (string)."
+
+			response, err := h.callClaude(ctx, "strategic_alignment", claudePrompt)
+			if err != nil {
+				return err
 			}
-			h.db.SaveCachedResponse(cache)
-
-			// Log usage (free, so cost = 0)
-			h.db.LogUsage("ollama", "strategic_alignment", tokens, 0, time.Since(startTime), nil)
-
-			return result, nil
-		}
-		log.Printf("⚠ Ollama failed for EvaluateStrategicAlignment: %v", err)
-	}
-
-	// Add JSON formatting instruction for Claude
-	claudePrompt := prompt + "\n\nIMPORTANT: Respond with ONLY a valid JSON object, no markdown formatting or explanation. The JSON must have these exact fields: score (number), okrs (array of strings), focus_areas (array of strings), projects (array of strings), reasoning (string)."
-
-	// Try Claude CLI second
-	startTime := time.Now()
-	response, err := h.callClaude(ctx, claudePrompt)
-	if err == nil {
-		log.Printf("✓ Claude CLI succeeded for EvaluateStrategicAlignment (%.2fs)", time.Since(startTime).Seconds())
-
-		// Cache the response
-		tokens := h.gemini.estimateTokens(prompt + response)
-		cache := &db.LLMCache{
-			Hash:      hash,
-			Prompt:    prompt,
-			Response:  response,
-			Model:     "claude-haiku",
-			Tokens:    tokens,
-			ExpiresAt: time.Now().Add(7 * 24 * time.Hour), // 7 days like Gemini
-		}
-		h.db.SaveCachedResponse(cache)
+			h.cacheResponse(hash, prompt, response, "claude-haiku", 7*24*time.Hour) // 7 days like Gemini
+			alignment = h.gemini.parseStrategicAlignmentResponse(response)
+			return nil
+		},
+		config.ProviderGemini: func() error {
+			result, err := h.gemini.EvaluateStrategicAlignment(ctx, task, priorities)
+			alignment = result
+			return err
+		},
+	})
+	return alignment, err
+}
 
-		// Log usage
-		h.db.LogUsage("claude", "strategic_alignment", tokens, 0, time.Since(startTime), nil)
+// DraftReply drafts an email reply (Claude CLI and Gemini, in configured order)
+func (h *HybridClient) DraftReply(ctx context.Context, thread []*db.Message, goal string, persona *config.Persona) (string, error) {
+	// Build prompt
+	prompt := h.prompts.BuildReply(thread, goal, persona)
 
-		// Parse and return
-		return h.gemini.parseStrategicAlignmentResponse(response), nil
+	// Check cache
+	hash := h.gemini.hashPrompt(prompt)
+	cached, err := h.db.GetCachedResponse(hash)
+	if err == nil && cached != nil {
+		log.Printf("Using cached draft reply")
+		return cached.Response, nil
 	}
 
-	// Final fallback to Gemini
-	log.Printf("⚠ Claude CLI failed, falling back to Gemini: %v", err)
-	return h.gemini.EvaluateStrategicAlignment(ctx, task, priorities)
+	var reply string
+	err = h.tryProviders("DraftReply", map[string]func() error{
+		config.ProviderClaude: func() error {
+			result, err := h.callClaude(ctx, "draft_reply", prompt)
+			if err != nil {
+				return err
+			}
+			h.cacheResponse(hash, prompt, result, "claude-haiku", h.gemini.cacheTTL)
+			reply = result
+			return nil
+		},
+		config.ProviderGemini: func() error {
+			result, err := h.gemini.DraftReply(ctx, thread, goal, persona)
+			reply = result
+			return err
+		},
+	})
+	return reply, err
 }
 
-// DraftReply drafts an email reply (Claude primary, Gemini fallback)
-func (h *HybridClient) DraftReply(ctx context.Context, thread []*db.Message, goal string) (string, error) {
+// GenerateMeetingPrep generates meeting preparation notes (Claude CLI and Gemini, in configured order)
+func (h *HybridClient) GenerateMeetingPrep(ctx context.Context, event *db.Event, relatedDocs []*db.Document, relatedThreads []*db.ContactInteraction) (string, error) {
 	// Build prompt
-	prompt := h.prompts.BuildReply(thread, goal)
+	prompt := h.prompts.BuildMeetingPrep(event, relatedDocs, relatedThreads)
 
 	// Check cache
 	hash := h.gemini.hashPrompt(prompt)
 	cached, err := h.db.GetCachedResponse(hash)
 	if err == nil && cached != nil {
-		log.Printf("Using cached draft reply")
+		log.Printf("Using cached meeting prep")
 		return cached.Response, nil
 	}
 
-	// Try Claude CLI first
-	startTime := time.Now()
-	reply, err := h.callClaude(ctx, prompt)
-	if err == nil {
-		log.Printf("✓ Claude CLI succeeded for DraftReply (%.2fs)", time.Since(startTime).Seconds())
-
-		// Cache the response
-		tokens := h.gemini.estimateTokens(prompt + reply)
-		cache := &db.LLMCache{
-			Hash:      hash,
-			Prompt:    prompt,
-			Response:  reply,
-			Model:     "claude-haiku",
-			Tokens:    tokens,
-			ExpiresAt: time.Now().Add(h.gemini.cacheTTL),
-		}
-		h.db.SaveCachedResponse(cache)
+	var prep string
+	err = h.tryProviders("GenerateMeetingPrep", map[string]func() error{
+		config.ProviderClaude: func() error {
+			result, err := h.callClaude(ctx, "meeting_prep", prompt)
+			if err != nil {
+				return err
+			}
+			h.cacheResponse(hash, prompt, result, "claude-haiku", h.gemini.cacheTTL)
+			prep = result
+			return nil
+		},
+		config.ProviderGemini: func() error {
+			result, err := h.gemini.GenerateMeetingPrep(ctx, event, relatedDocs, relatedThreads)
+			prep = result
+			return err
+		},
+	})
+	return prep, err
+}
 
-		// Log usage
-		h.db.LogUsage("claude", "draft_reply", tokens, 0, time.Since(startTime), nil)
+// GenerateRelationshipBrief summarizes sentiment and topics for a contact (Claude CLI and Gemini, in configured order)
+func (h *HybridClient) GenerateRelationshipBrief(ctx context.Context, event *db.Event, history *db.ContactHistory) (string, error) {
+	prompt := h.prompts.BuildRelationshipBrief(event, history)
 
-		return reply, nil
+	// Check cache
+	hash := h.gemini.hashPrompt(prompt)
+	cached, err := h.db.GetCachedResponse(hash)
+	if err == nil && cached != nil {
+		log.Printf("Using cached relationship brief")
+		return cached.Response, nil
 	}
 
-	// Fallback to Gemini
-	log.Printf("⚠ Claude CLI failed, falling back to Gemini: %v", err)
-	return h.gemini.DraftReply(ctx, thread, goal)
+	var brief string
+	err = h.tryProviders("GenerateRelationshipBrief", map[string]func() error{
+		config.ProviderClaude: func() error {
+			result, err := h.callClaude(ctx, "relationship_brief", prompt)
+			if err != nil {
+				return err
+			}
+			h.cacheResponse(hash, prompt, result, "claude-haiku", h.gemini.cacheTTL)
+			brief = result
+			return nil
+		},
+		config.ProviderGemini: func() error {
+			result, err := h.gemini.GenerateRelationshipBrief(ctx, event, history)
+			brief = result
+			return err
+		},
+	})
+	return brief, err
 }
 
-// GenerateMeetingPrep generates meeting preparation notes (Claude primary, Gemini fallback)
-func (h *HybridClient) GenerateMeetingPrep(ctx context.Context, event *db.Event, relatedDocs []*db.Document) (string, error) {
-	// Build prompt
-	prompt := h.prompts.BuildMeetingPrep(event, relatedDocs)
+// AnswerQuestion answers a question from retrieved messages and tasks (Claude CLI and Gemini, in configured order)
+func (h *HybridClient) AnswerQuestion(ctx context.Context, question string, messages []*db.Message, tasks []*db.Task) (string, error) {
+	prompt := h.prompts.BuildQuestionAnswer(question, messages, tasks)
 
 	// Check cache
 	hash := h.gemini.hashPrompt(prompt)
 	cached, err := h.db.GetCachedResponse(hash)
 	if err == nil && cached != nil {
-		log.Printf("Using cached meeting prep")
+		log.Printf("Using cached answer")
 		return cached.Response, nil
 	}
 
-	// Try Claude CLI first
-	startTime := time.Now()
-	prep, err := h.callClaude(ctx, prompt)
-	if err == nil {
-		log.Printf("✓ Claude CLI succeeded for GenerateMeetingPrep (%.2fs)", time.Since(startTime).Seconds())
-
-		// Cache the response
-		tokens := h.gemini.estimateTokens(prompt + prep)
-		cache := &db.LLMCache{
-			Hash:      hash,
-			Prompt:    prompt,
-			Response:  prep,
-			Model:     "claude-haiku",
-			Tokens:    tokens,
-			ExpiresAt: time.Now().Add(h.gemini.cacheTTL),
-		}
-		h.db.SaveCachedResponse(cache)
+	var answer string
+	err = h.tryProviders("AnswerQuestion", map[string]func() error{
+		config.ProviderClaude: func() error {
+			result, err := h.callClaude(ctx, "ask", prompt)
+			if err != nil {
+				return err
+			}
+			h.cacheResponse(hash, prompt, result, "claude-haiku", h.gemini.cacheTTL)
+			answer = result
+			return nil
+		},
+		config.ProviderGemini: func() error {
+			result, err := h.gemini.AnswerQuestion(ctx, question, messages, tasks)
+			answer = result
+			return err
+		},
+	})
+	return answer, err
+}
 
-		// Log usage
-		h.db.LogUsage("claude", "meeting_prep", tokens, 0, time.Since(startTime), nil)
+// ExtractWaitingRequest finds what a sent email asks for (Claude CLI and Gemini, in configured order)
+func (h *HybridClient) ExtractWaitingRequest(ctx context.Context, msg *db.Message) (*WaitingRequest, error) {
+	prompt := h.prompts.BuildWaitingRequest(msg)
 
-		return prep, nil
-	}
+	var request *WaitingRequest
+	err := h.tryProviders("ExtractWaitingRequest", map[string]func() error{
+		config.ProviderClaude: func() error {
+			response, err := h.callClaude(ctx, "waiting_request", prompt)
+			if err != nil {
+				return err
+			}
+			request, err = parseWaitingRequest(response)
+			return err
+		},
+		config.ProviderGemini: func() error {
+			result, err := h.gemini.ExtractWaitingRequest(ctx, msg)
+			request = result
+			return err
+		},
+	})
+	return request, err
+}
+
+// ExtractCommitments finds what a sent email promises (Claude CLI and Gemini, in configured order)
+func (h *HybridClient) ExtractCommitments(ctx context.Context, msg *db.Message) ([]*Commitment, error) {
+	prompt := h.prompts.BuildCommitments(msg)
+
+	var commitments []*Commitment
+	err := h.tryProviders("ExtractCommitments", map[string]func() error{
+		config.ProviderClaude: func() error {
+			response, err := h.callClaude(ctx, "commitments", prompt)
+			if err != nil {
+				return err
+			}
+			commitments, err = parseCommitments(response)
+			return err
+		},
+		config.ProviderGemini: func() error {
+			result, err := h.gemini.ExtractCommitments(ctx, msg)
+			commitments = result
+			return err
+		},
+	})
+	return commitments, err
+}
 
-	// Fallback to Gemini
-	log.Printf("⚠ Claude CLI failed, falling back to Gemini: %v", err)
-	return h.gemini.GenerateMeetingPrep(ctx, event, relatedDocs)
+// WriteWeeklyReview writes a short narrative of the week (Claude CLI and Gemini, in configured order)
+func (h *HybridClient) WriteWeeklyReview(ctx context.Context, review *db.WeeklyReview) (string, error) {
+	prompt := h.prompts.BuildWeeklyReview(review)
+
+	var narrative string
+	err := h.tryProviders("WriteWeeklyReview", map[string]func() error{
+		config.ProviderClaude: func() error {
+			result, err := h.callClaude(ctx, "weekly_review", prompt)
+			narrative = result
+			return err
+		},
+		config.ProviderGemini: func() error {
+			result, err := h.gemini.WriteWeeklyReview(ctx, review)
+			narrative = result
+			return err
+		},
+	})
+	return narrative, err
 }
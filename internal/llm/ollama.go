@@ -8,7 +8,6 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
@@ -20,8 +19,10 @@ import (
 type OllamaClient struct {
 	baseURL    string
 	model      string
+	keepAlive  string // Passed as keep_alive so the host holds the model between requests ("" = host default)
 	httpClient *http.Client
 	prompts    *PromptBuilder
+	schedule   *config.Schedule // Due dates are read in its timezone (nil = local time)
 }
 
 // NewOllamaClient creates a new Ollama client
@@ -45,9 +46,13 @@ func NewOllamaClient(baseURL, model string, prompts *PromptBuilder) *OllamaClien
 
 // GenerateRequest represents a request to generate text
 type GenerateRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
-	Stream bool   `json:"stream"`
+	Model     string `json:"model"`
+	Prompt    string `json:"prompt"`
+	Stream    bool   `json:"stream"`
+	KeepAlive string `json:"keep_alive,omitempty"`
+
+	// Format constrains the output: "json", or a JSON schema the response must match
+	Format interface{} `json:"format,omitempty"`
 }
 
 // GenerateResponse represents the response from the generate API
@@ -60,10 +65,28 @@ type GenerateResponse struct {
 
 // Generate generates text using the configured model
 func (c *OllamaClient) Generate(ctx context.Context, prompt string) (string, error) {
+	return c.generate(ctx, prompt, nil)
+}
+
+// GenerateWithFormat generates text with optional JSON format
+func (c *OllamaClient) GenerateWithFormat(ctx context.Context, prompt string, format string) (string, error) {
+	if format != "json" {
+		return c.generate(ctx, prompt, nil)
+	}
+
+	// Ollama constrains the output to JSON; the instruction keeps the model from padding it
+	prompt = prompt + "\n\nIMPORTANT: Respond with ONLY a valid JSON object, no markdown formatting or explanation."
+	return c.generate(ctx, prompt, format)
+}
+
+// generate sends a generate request, with the output constrained by format unless it's nil
+func (c *OllamaClient) generate(ctx context.Context, prompt string, format interface{}) (string, error) {
 	reqBody := GenerateRequest{
-		Model:  c.model,
-		Prompt: prompt,
-		Stream: false,
+		Model:     c.model,
+		Prompt:    prompt,
+		Stream:    false,
+		KeepAlive: c.keepAlive,
+		Format:    format,
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -97,68 +120,99 @@ func (c *OllamaClient) Generate(ctx context.Context, prompt string) (string, err
 	return genResp.Response, nil
 }
 
-// GenerateWithFormat generates text with optional JSON format
-func (c *OllamaClient) GenerateWithFormat(ctx context.Context, prompt string, format string) (string, error) {
-	// If JSON format requested, add JSON instruction to prompt
-	if format == "json" {
-		prompt = prompt + "\n\nIMPORTANT: Respond with ONLY a valid JSON object, no markdown formatting or explanation."
+// now returns the current time in the schedule's time zone, which due dates are relative to
+func (c *OllamaClient) now() time.Time {
+	if c.schedule == nil {
+		return time.Now()
 	}
+	return c.schedule.Now()
+}
 
-	reqBody := GenerateRequest{
-		Model:  c.model,
-		Prompt: prompt,
-		Stream: false,
+// Ping checks if the Ollama server is reachable
+func (c *OllamaClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/tags", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	jsonData, err := json.Marshal(reqBody)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return fmt.Errorf("failed to connect to ollama: %w", err)
 	}
+	defer resp.Body.Close()
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/generate", bytes.NewBuffer(jsonData))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ollama returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Preload loads the model into memory without generating anything, so the next request
+// doesn't wait for it to load
+func (c *OllamaClient) Preload(ctx context.Context) error {
+	jsonData, err := json.Marshal(GenerateRequest{Model: c.model, KeepAlive: c.keepAlive})
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/generate", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to make request: %w", err)
+		return fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("ollama API error %d: %s", resp.StatusCode, string(body))
-	}
-
-	var genResp GenerateResponse
-	if err := json.NewDecoder(resp.Body).Decode(&genResp); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return fmt.Errorf("ollama API error %d: %s", resp.StatusCode, string(body))
 	}
+	return nil
+}
 
-	return genResp.Response, nil
+// runningModelsResponse is the response from the ps API, listing the models in memory
+type runningModelsResponse struct {
+	Models []struct {
+		Name      string    `json:"name"`
+		Model     string    `json:"model"`
+		ExpiresAt time.Time `json:"expires_at"`
+	} `json:"models"`
 }
 
-// Ping checks if the Ollama server is reachable
-func (c *OllamaClient) Ping(ctx context.Context) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/tags", nil)
+// ModelLoaded reports whether the model is in memory, and when the host will unload it if
+// left idle
+func (c *OllamaClient) ModelLoaded(ctx context.Context) (bool, *time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/ps", nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return false, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to connect to ollama: %w", err)
+		return false, nil, fmt.Errorf("failed to connect to ollama: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ollama returned status %d", resp.StatusCode)
+		return false, nil, fmt.Errorf("ollama returned status %d", resp.StatusCode)
 	}
 
-	return nil
+	var running runningModelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&running); err != nil {
+		return false, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	for _, model := range running.Models {
+		if model.Name == c.model || model.Model == c.model {
+			expiresAt := model.ExpiresAt
+			return true, &expiresAt, nil
+		}
+	}
+	return false, nil, nil
 }
 
 // EvaluateStrategicAlignment evaluates how well a task aligns with strategic priorities
@@ -245,12 +299,12 @@ func (c *OllamaClient) SummarizeThread(ctx context.Context, messages []*db.Messa
 func (c *OllamaClient) ExtractTasks(ctx context.Context, content, userEmail string) ([]*db.Task, error) {
 	prompt := c.prompts.BuildTaskExtraction(content)
 
-	response, err := c.Generate(ctx, prompt)
+	response, err := c.generate(ctx, prompt, taskJSONSchema)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract tasks: %w", err)
 	}
 
-	tasks := c.parseTasksFromResponse(response)
+	tasks := parseTaskResponse(response, c.now())
 	if len(tasks) > 0 {
 		log.Printf("Ollama extracted %d tasks", len(tasks))
 	} else {
@@ -265,158 +319,7 @@ func (c *OllamaClient) EnrichTaskDescription(ctx context.Context, prompt string)
 	return c.GenerateWithFormat(ctx, prompt, "json")
 }
 
-// isMeetingInvitation checks if a task title is a meeting invitation (should be filtered)
-func isMeetingInvitation(title string) bool {
-	titleLower := strings.ToLower(title)
-	meetingPatterns := []string{
-		"respond to meeting invitation",
-		"accept meeting",
-		"decline meeting",
-		"confirm availability for meeting",
-		"rsvp to",
-		"reply to invitation",
-		"accept invitation",
-		"respond to invitation",
-		// Calendar event patterns
-		"join ",
-		"attend ",
-		"lead ",
-		"host ",
-		"participate in",
-	}
-
-	for _, pattern := range meetingPatterns {
-		if strings.Contains(titleLower, pattern) {
-			return true
-		}
-	}
-	return false
-}
-
-// parseTasksFromResponse parses the task extraction response
-func (c *OllamaClient) parseTasksFromResponse(response string) []*db.Task {
-	var tasks []*db.Task
-	seenTitles := make(map[string]bool) // Track duplicate titles
-
-	// Check for "no tasks" response
-	if strings.Contains(strings.ToLower(response), "no tasks found") ||
-		strings.Contains(strings.ToLower(response), "no action items") {
-		return tasks
-	}
-
-	// Parse numbered list format
-	lines := strings.Split(response, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		// Match numbered list items: "1. Title: X | Owner: Y | Due: Z | Priority: W"
-		// More flexible regex to catch various formats
-		if !strings.HasPrefix(line, "1") && !strings.HasPrefix(line, "2") &&
-			!strings.HasPrefix(line, "3") && !strings.HasPrefix(line, "4") &&
-			!strings.HasPrefix(line, "5") && !strings.HasPrefix(line, "6") &&
-			!strings.HasPrefix(line, "7") && !strings.HasPrefix(line, "8") &&
-			!strings.HasPrefix(line, "9") {
-			continue
-		}
-
-		// Remove number prefix
-		line = strings.TrimLeft(line, "0123456789.")
-		line = strings.TrimSpace(line)
-
-		task := &db.Task{
-			Source:  "gmail",
-			Status:  "pending",
-			Impact:  3, // Default medium impact
-			Urgency: 3, // Default medium urgency
-			Effort:  "M", // Default medium effort
-		}
-
-		// Parse pipe-delimited fields
-		parts := strings.Split(line, "|")
-		for _, part := range parts {
-			part = strings.TrimSpace(part)
-			partLower := strings.ToLower(part)
-
-			if strings.HasPrefix(partLower, "title:") {
-				task.Title = strings.TrimSpace(strings.TrimPrefix(part, "Title:"))
-				task.Title = strings.TrimSpace(strings.TrimPrefix(task.Title, "title:"))
-			} else if strings.HasPrefix(partLower, "impact:") {
-				impactStr := strings.TrimSpace(strings.TrimPrefix(part, "Impact:"))
-				impactStr = strings.TrimSpace(strings.TrimPrefix(impactStr, "impact:"))
-				// Parse as integer 1-5
-				if val, err := strconv.Atoi(impactStr); err == nil && val >= 1 && val <= 5 {
-					task.Impact = val
-				}
-			} else if strings.HasPrefix(partLower, "urgency:") {
-				urgencyStr := strings.TrimSpace(strings.TrimPrefix(part, "Urgency:"))
-				urgencyStr = strings.TrimSpace(strings.TrimPrefix(urgencyStr, "urgency:"))
-				// Parse as integer 1-5
-				if val, err := strconv.Atoi(urgencyStr); err == nil && val >= 1 && val <= 5 {
-					task.Urgency = val
-				}
-			} else if strings.HasPrefix(partLower, "effort:") {
-				effortStr := strings.TrimSpace(strings.TrimPrefix(part, "Effort:"))
-				effortStr = strings.TrimSpace(strings.TrimPrefix(effortStr, "effort:"))
-				effortStr = strings.ToUpper(effortStr)
-				// Validate S/M/L
-				if effortStr == "S" || effortStr == "M" || effortStr == "L" {
-					task.Effort = effortStr
-				}
-			} else if strings.HasPrefix(partLower, "stakeholder:") {
-				stakeholderStr := strings.TrimSpace(strings.TrimPrefix(part, "Stakeholder:"))
-				stakeholderStr = strings.TrimSpace(strings.TrimPrefix(stakeholderStr, "stakeholder:"))
-				// Validate stakeholder is a person's name, not a team/department/company
-				if isValidStakeholder(stakeholderStr) {
-					task.Stakeholder = stakeholderStr
-				} else {
-					task.Stakeholder = "" // Clear invalid stakeholders
-				}
-			} else if strings.HasPrefix(partLower, "project:") {
-				projectStr := strings.TrimSpace(strings.TrimPrefix(part, "Project:"))
-				projectStr = strings.TrimSpace(strings.TrimPrefix(projectStr, "project:"))
-				task.Project = projectStr
-			} else if strings.HasPrefix(partLower, "due:") {
-				dueStr := strings.TrimSpace(strings.TrimPrefix(part, "Due:"))
-				dueStr = strings.TrimSpace(strings.TrimPrefix(dueStr, "due:"))
-				// Store due date string for later parsing
-				// TODO: Parse relative dates like "tomorrow", "Friday", "Oct 30"
-				task.Description = "Due: " + dueStr
-			} else if strings.HasPrefix(partLower, "priority:") {
-				// Legacy support for old format
-				priority := strings.TrimSpace(strings.TrimPrefix(part, "Priority:"))
-				priority = strings.TrimSpace(strings.TrimPrefix(priority, "priority:"))
-				priority = strings.ToLower(priority)
-
-				// Map to impact and urgency scores (fallback if not explicitly set)
-				if task.Impact == 3 { // Only override default
-					switch priority {
-					case "high", "critical", "urgent":
-						task.Impact = 5
-						task.Urgency = 4
-					case "medium", "moderate":
-						task.Impact = 3
-						task.Urgency = 3
-					case "low":
-						task.Impact = 2
-						task.Urgency = 2
-					}
-				}
-			}
-		}
-
-		// Only add if we got a title, it's not a meeting invitation, and not a duplicate
-		if task.Title != "" && !isMeetingInvitation(task.Title) {
-			// Check for duplicate title
-			titleLower := strings.ToLower(task.Title)
-			if !seenTitles[titleLower] {
-				seenTitles[titleLower] = true
-				tasks = append(tasks, task)
-			}
-		}
-	}
-
-	return tasks
+// Complete generates a response to a prompt ("json" format or "" for plain text)
+func (c *OllamaClient) Complete(ctx context.Context, prompt, format string) (string, error) {
+	return c.GenerateWithFormat(ctx, prompt, format)
 }
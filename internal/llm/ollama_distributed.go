@@ -55,6 +55,8 @@ type HostStats struct {
 type DistributedOllamaClient struct {
 	hosts      []config.OllamaHost
 	model      string
+	keepAlive  string
+	schedule   *config.Schedule
 	timeout    time.Duration
 	jobs       chan *OllamaJob
 	stats      map[string]*HostStats
@@ -89,6 +91,8 @@ func NewDistributedOllamaClient(cfg *config.Config, prompts *PromptBuilder) *Dis
 	client := &DistributedOllamaClient{
 		hosts:      cfg.Ollama.Hosts,
 		model:      cfg.Ollama.Model,
+		keepAlive:  cfg.Ollama.KeepAlive,
+		schedule:   &cfg.Schedule,
 		timeout:    timeout,
 		jobs:       make(chan *OllamaJob, jobQueueSize),
 		stats:      make(map[string]*HostStats),
@@ -128,6 +132,8 @@ func (c *DistributedOllamaClient) startWorkers() {
 
 		// Create a simple OllamaClient for each host
 		hostClient := NewOllamaClient(host.URL, c.model, c.prompts)
+		hostClient.keepAlive = c.keepAlive
+		hostClient.schedule = c.schedule
 		hostClient.httpClient.Timeout = c.timeout
 
 		// Spawn N workers for this host
@@ -382,6 +388,12 @@ func (c *DistributedOllamaClient) ExtractTasks(ctx context.Context, content, use
 
 // EnrichTaskDescription submits a task enrichment job to the worker pool with retry logic
 func (c *DistributedOllamaClient) EnrichTaskDescription(ctx context.Context, prompt string) (string, error) {
+	return c.Complete(ctx, prompt, "json")
+}
+
+// Complete submits a prompt to the worker pool with retry logic. format is passed to Ollama
+// ("json" or "" for plain text).
+func (c *DistributedOllamaClient) Complete(ctx context.Context, prompt, format string) (string, error) {
 	var lastError error
 
 	for attempt := 0; attempt < c.maxRetries; attempt++ {
@@ -402,7 +414,7 @@ func (c *DistributedOllamaClient) EnrichTaskDescription(ctx context.Context, pro
 			ID:      fmt.Sprintf("enrich-%d", time.Now().UnixNano()),
 			Type:    "enrich",
 			Prompt:  prompt,
-			Format:  "json",
+			Format:  format,
 			Result:  make(chan OllamaResult, 1),
 			Context: ctx,
 		}
@@ -418,7 +430,7 @@ func (c *DistributedOllamaClient) EnrichTaskDescription(ctx context.Context, pro
 		select {
 		case result := <-job.Result:
 			if result.Error == nil {
-				log.Printf("Task enriched by %s in %s", result.HostName, result.Duration)
+				log.Printf("Prompt completed by %s in %s", result.HostName, result.Duration)
 				return result.Text, nil
 			}
 
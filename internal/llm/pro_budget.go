@@ -0,0 +1,129 @@
+package llm
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// proBudget spreads the daily Pro-model allocation across the working day. The allocation is
+// released in hourly slices between the configured start and end times, and part of each slice is
+// reserved for key-stakeholder threads, so a morning backlog can't exhaust the Pro model.
+type proBudget struct {
+	mu       sync.Mutex
+	cfg      config.ProBudget
+	schedule *config.Schedule // The working day is in its timezone, which may be overridden at runtime
+	db       *db.DB           // Pro calls already logged today count against the budget
+	day      string           // Date the used count belongs to
+	used     int
+}
+
+// newProBudget creates a budget from config; it returns nil (no budget) when daily_calls is 0
+func newProBudget(cfg *config.Config, database *db.DB) *proBudget {
+	if cfg.Gemini.ProBudget.DailyCalls <= 0 {
+		return nil
+	}
+
+	return &proBudget{
+		cfg:      cfg.Gemini.ProBudget,
+		schedule: &cfg.Schedule,
+		db:       database,
+	}
+}
+
+// usedToday counts the Pro calls logged in the usage table since midnight, so a restart or a
+// one-off run doesn't start the day's allocation afresh
+func (b *proBudget) usedToday(now time.Time) int {
+	if b.db == nil {
+		return 0
+	}
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	used, err := b.db.CountModelCallsSince("gemini", proModelName, midnight)
+	if err != nil {
+		log.Printf("Pro budget: counting from zero: %v", err)
+		return 0
+	}
+	return used
+}
+
+// allocation returns how many Pro calls may have been made by now. Key-stakeholder threads may use
+// the whole allocation; everything else stops short of the reserved share.
+func (b *proBudget) allocation(now time.Time, keyStakeholder bool) int {
+	now = now.In(b.schedule.Location())
+	start := b.clock(now, b.cfg.StartTime)
+	end := b.clock(now, b.cfg.EndTime)
+
+	allocated := b.cfg.DailyCalls
+	if now.Before(end) {
+		slices := int(end.Sub(start).Hours())
+		if slices < 1 {
+			slices = 1
+		}
+		// The first slice is available before the start time so early work isn't starved
+		slice := 1
+		if now.After(start) {
+			slice = int(now.Sub(start).Hours()) + 1
+		}
+		if slice > slices {
+			slice = slices
+		}
+		allocated = b.cfg.DailyCalls * slice / slices
+	}
+
+	if !keyStakeholder {
+		allocated -= allocated * b.cfg.ReservePercent / 100
+	}
+	return allocated
+}
+
+// clock returns the given "HH:MM" time on now's date
+func (b *proBudget) clock(now time.Time, hhmm string) time.Time {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return now
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+}
+
+// take claims a Pro call if the allocation accrued so far has room for it. A nil budget always allows.
+func (b *proBudget) take(keyStakeholder bool) bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.schedule.Now()
+	if day := now.Format("2006-01-02"); day != b.day {
+		b.day = day
+		b.used = b.usedToday(now)
+	}
+
+	allocated := b.allocation(now, keyStakeholder)
+	if b.used >= allocated {
+		log.Printf("Pro budget: %d/%d calls used of today's %d so far, holding the rest for later",
+			b.used, allocated, b.cfg.DailyCalls)
+		return false
+	}
+
+	b.used++
+	return true
+}
+
+// release returns a claimed call that was not made (e.g. the rate limiter had no capacity)
+func (b *proBudget) release() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.used > 0 {
+		b.used--
+	}
+}
@@ -13,18 +13,24 @@ import (
 // This eliminates duplication across gemini.go, ollama.go, hybrid.go
 type PromptBuilder struct {
 	userEmail string
+	templates *promptTemplates // Overrides for the built-in prompts, nil for none
 }
 
-// NewPromptBuilder creates a new prompt builder
-func NewPromptBuilder(userEmail string) *PromptBuilder {
+// NewPromptBuilder creates a new prompt builder. Templates in templatesDir replace the
+// built-in prompts they're named after.
+func NewPromptBuilder(userEmail, templatesDir string) *PromptBuilder {
 	if userEmail == "" {
 		userEmail = "the user"
 	}
-	return &PromptBuilder{userEmail: userEmail}
+	return &PromptBuilder{userEmail: userEmail, templates: newPromptTemplates(templatesDir)}
 }
 
 // BuildThreadSummary creates a prompt for summarizing email threads
 func (p *PromptBuilder) BuildThreadSummary(messages []*db.Message) string {
+	if prompt, ok := p.fromTemplate(promptThreadSummary, PromptData{Messages: messages}); ok {
+		return prompt
+	}
+
 	var prompt strings.Builder
 
 	prompt.WriteString("Summarize this email thread concisely. Focus on:\n")
@@ -39,7 +45,9 @@ func (p *PromptBuilder) BuildThreadSummary(messages []*db.Message) string {
 		prompt.WriteString(fmt.Sprintf("From: %s\n", msg.From))
 		prompt.WriteString(fmt.Sprintf("Date: %s\n", msg.Timestamp.Format("Jan 2, 3:04 PM")))
 		prompt.WriteString(fmt.Sprintf("Subject: %s\n", msg.Subject))
-		prompt.WriteString(fmt.Sprintf("Content: %s\n\n", msg.Snippet))
+		prompt.WriteString(fmt.Sprintf("Content: %s\n", msg.Snippet))
+		writeAttachments(&prompt, msg.Attachments, summaryAttachmentChars)
+		prompt.WriteString("\n")
 	}
 
 	prompt.WriteString("Summary (be concise, max 200 words):")
@@ -47,9 +55,112 @@ func (p *PromptBuilder) BuildThreadSummary(messages []*db.Message) string {
 	return prompt.String()
 }
 
+// BuildThreadGist creates a prompt for a one-line gist of a low-priority thread
+func (p *PromptBuilder) BuildThreadGist(messages []*db.Message) string {
+	if prompt, ok := p.fromTemplate(promptThreadGist, PromptData{Messages: messages}); ok {
+		return prompt
+	}
+
+	var prompt strings.Builder
+
+	prompt.WriteString("Sum up this email thread in one line of at most 25 words: what it is and whether ")
+	prompt.WriteString("anything is needed from me. No preamble.\n\n")
+
+	prompt.WriteString("Thread:\n")
+	for _, msg := range messages {
+		prompt.WriteString(fmt.Sprintf("From: %s\n", msg.From))
+		prompt.WriteString(fmt.Sprintf("Subject: %s\n", msg.Subject))
+		prompt.WriteString(fmt.Sprintf("Content: %s\n\n", msg.Snippet))
+	}
+
+	prompt.WriteString("Gist:")
+
+	return prompt.String()
+}
+
+// BuildThreadDeepSummary creates a prompt for a structured summary of an important thread
+func (p *PromptBuilder) BuildThreadDeepSummary(messages []*db.Message) string {
+	if prompt, ok := p.fromTemplate(promptThreadDeepSummary, PromptData{Messages: messages}); ok {
+		return prompt
+	}
+
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("Summarize this email thread for %s, who needs to act on it or discuss it ", p.userEmail))
+	prompt.WriteString("in a meeting.\n\n")
+
+	prompt.WriteString("Thread:\n")
+	for _, msg := range messages {
+		prompt.WriteString(fmt.Sprintf("From: %s\n", msg.From))
+		prompt.WriteString(fmt.Sprintf("Date: %s\n", msg.Timestamp.Format("Jan 2, 3:04 PM")))
+		prompt.WriteString(fmt.Sprintf("Subject: %s\n", msg.Subject))
+		prompt.WriteString(fmt.Sprintf("Content: %s\n", msg.Snippet))
+		writeAttachments(&prompt, msg.Attachments, summaryAttachmentChars)
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString("Respond with ONLY a JSON object with these fields:\n")
+	prompt.WriteString("- summary (string): the main topic and where it stands, max 150 words\n")
+	prompt.WriteString("- decisions (array of strings): what has been decided\n")
+	prompt.WriteString("- owners (array of strings): who is doing what, as \"Name: action\"\n")
+	prompt.WriteString("- risks (array of strings): risks, blockers and deadlines at risk\n")
+	prompt.WriteString("- open_questions (array of strings): questions still unanswered\n")
+	prompt.WriteString("Use empty arrays for anything the thread doesn't contain. Keep each item under 120 characters.\n")
+
+	return prompt.String()
+}
+
+// Excerpt lengths, in characters, for attachment text and meeting prep context in each prompt
+const (
+	summaryAttachmentChars    = 2000
+	enrichmentAttachmentChars = 3000
+	meetingPrepContextChars   = 500
+)
+
+// writeAttachments adds an excerpt of each attachment's extracted text to a prompt
+func writeAttachments(prompt *strings.Builder, attachments []*db.Attachment, maxChars int) {
+	for _, attachment := range attachments {
+		prompt.WriteString(fmt.Sprintf("Attachment: %s\n%s\n", attachment.Filename, excerpt(attachment.Text, maxChars)))
+	}
+}
+
+// writeInsights adds the decisions, owners, risks and open questions of a thread's deep summary
+// to a prompt, indented under the thread
+func writeInsights(prompt *strings.Builder, insights *db.ThreadInsights) {
+	if insights == nil {
+		return
+	}
+	for _, field := range []struct {
+		label string
+		items []string
+	}{
+		{"Decisions", insights.Decisions},
+		{"Owners", insights.Owners},
+		{"Risks", insights.Risks},
+		{"Open questions", insights.OpenQuestions},
+	} {
+		if len(field.items) > 0 {
+			prompt.WriteString(fmt.Sprintf("  %s: %s\n", field.label, strings.Join(field.items, "; ")))
+		}
+	}
+}
+
+// excerpt cuts text to at most maxChars characters, marking the cut
+func excerpt(text string, maxChars int) string {
+	runes := []rune(text)
+	if len(runes) > maxChars {
+		return string(runes[:maxChars]) + "..."
+	}
+	return text
+}
+
 // BuildTaskExtraction creates a prompt for extracting tasks from received emails
 // UPDATED: Added noise reduction + enhanced date/stakeholder extraction
 func (p *PromptBuilder) BuildTaskExtraction(content string) string {
+	if prompt, ok := p.fromTemplate(promptTaskExtraction, PromptData{Content: content, OutputFormat: taskOutputFormat}); ok {
+		return prompt
+	}
+
 	return fmt.Sprintf(`Extract action items from this content that I (%s) need to do or respond to.
 
 ⚠️  CRITICAL: NOISE REDUCTION RULES (CHECK FIRST)
@@ -58,7 +169,7 @@ func (p *PromptBuilder) BuildTaskExtraction(content string) string {
    - noreply@, no-reply@, donotreply@, payments-noreply@
    - notifications@, alerts@, support@, marketing@, newsletter@
    - System emails: receipts, confirmations, password resets
-   → These are informational. Return empty list.
+   → These are informational. Return {"tasks": []}.
 
 2. LIMIT TO 1-2 TASKS MAXIMUM:
    - One email = at most 1-2 tasks (consolidate duplicates)
@@ -91,14 +202,14 @@ For each task:
 
 - title: Action verb + object (20-60 chars) - NOT timestamp, NOT meta-comment
 
-- due_date: Extract ANY temporal reference:
+- due: Extract ANY temporal reference:
   * Explicit dates: "Oct 30", "12/15", "2025-01-15", "January 15th"
   * Relative dates: "tomorrow", "in 2 days", "next Monday", "end of week"
   * Deadline phrases: "by Friday", "before EOD", "by end of day"
   * Event-relative: "before the meeting", "after launch"
   * Urgency signals: "URGENT" → "today", "ASAP" → "within 24 hours"
   * Format as natural language (e.g., "Friday", "Oct 30", "tomorrow")
-  * If NO temporal reference, use "N/A"
+  * If NO temporal reference, use ""
 
 - impact: (1-5) - **USE FULL RANGE**
   * 1 = Nice to have (blog draft, organize files)
@@ -122,7 +233,7 @@ For each task:
   * THIRD: Parse signatures for full names
   * Extract FULL NAME: "Sarah Chen" not "Sarah"
   * Normalize emails: "s.chen@company.com" → "Sarah Chen"
-  * ONLY use N/A if no person identifiable
+  * ONLY use "" if no person identifiable
   * VALID: "Sarah Chen", "Tim Davis"
   * INVALID: "Finance Team", "Customer Support", "Marketing"
 
@@ -131,16 +242,15 @@ For each task:
 Content:
 %s
 
-Format (pipe-delimited):
-1. Title: [Action] | Due: [Deadline] | Impact: [1-5] | Urgency: [1-5] | Effort: [S/M/L] | Stakeholder: [Name] | Project: [Context]
-
-If no actionable tasks, return empty list.
-
-YOUR EXTRACTED TASKS:`, p.userEmail, content)
+%s`, p.userEmail, content, taskOutputFormat)
 }
 
 // BuildSentEmailTaskExtraction creates a prompt for extracting self-commitments from sent emails
 func (p *PromptBuilder) BuildSentEmailTaskExtraction(content string, recipients []string) string {
+	if prompt, ok := p.fromTemplate(promptSentEmailTaskExtraction, PromptData{Content: content, Recipients: recipients, OutputFormat: taskOutputFormat}); ok {
+		return prompt
+	}
+
 	recipientList := "others"
 	if len(recipients) > 0 {
 		recipientList = strings.Join(recipients, ", ")
@@ -163,26 +273,24 @@ IMPORTANT RULES:
 - SKIP general statements that aren't commitments (e.g., "That sounds good")
 
 For each commitment, provide:
-- Title (brief description of what I committed to)
-- Recipient (from: %s)
-- Due date/urgency (if mentioned, otherwise leave blank)
-- Priority (High/Medium/Low based on context and urgency)
+- title: brief description of what I committed to (e.g. "Send Q3 report to Sarah")
+- stakeholder: the recipient I made the commitment to (from: %s)
+- due: the deadline if mentioned (e.g. "Friday EOD"), otherwise ""
+- impact and urgency: 4-5 for high priority, 3 for medium, 1-2 for low, based on context and urgency
+- effort: S (< 1h), M (1-4h), L (> 4h)
+- project: related context (if mentioned)
 
 Content:
 %s
 
-Format as a numbered list. Example:
-1. Title: Send Q3 report to Sarah | Recipient: sarah@example.com | Due: Friday EOD | Priority: High
-2. Title: Review proposal for team | Recipient: team@example.com | Due: This week | Priority: Medium
-
-Extract my commitments:`, recipientList, p.userEmail, recipientList, content)
+%s`, recipientList, p.userEmail, recipientList, content, taskOutputFormat)
 }
 
 // BuildTaskExtractionWithMetadata creates thread-aware extraction with automated sender filtering
 // Now includes Front comments when available
 func (p *PromptBuilder) BuildTaskExtractionWithMetadata(messages []*db.Message, frontComments []*db.FrontComment, frontMetadata *db.FrontMetadata) string {
 	if len(messages) == 0 {
-		return "No messages provided. Return empty list."
+		return `No messages provided. Return {"tasks": []}.`
 	}
 
 	// Detect automated senders from most recent message
@@ -210,13 +318,18 @@ func (p *PromptBuilder) BuildTaskExtractionWithMetadata(messages []*db.Message,
 	// If automated sender, skip task extraction
 	if isAutomatedSender {
 		log.Printf("Skipping automated sender: %s", lastMsg.From)
-		return "This is an automated system notification. Do not extract any tasks. Return empty list."
+		return `This is an automated system notification. Do not extract any tasks. Return {"tasks": []}.`
 	}
 
 	// Skip archived conversations (they're closed/done)
 	if frontMetadata != nil && frontMetadata.Status == "archived" {
 		log.Printf("Skipping archived Front conversation: %s", frontMetadata.ConversationID)
-		return "This conversation is archived in Front. Do not extract any tasks. Return empty list."
+		return `This conversation is archived in Front. Do not extract any tasks. Return {"tasks": []}.`
+	}
+
+	templateData := PromptData{Messages: messages, FrontComments: frontComments, FrontMetadata: frontMetadata, OutputFormat: taskOutputFormat}
+	if prompt, ok := p.fromTemplate(promptThreadTaskExtraction, templateData); ok {
+		return prompt
 	}
 
 	var prompt strings.Builder
@@ -280,7 +393,7 @@ func (p *PromptBuilder) BuildTaskExtractionWithMetadata(messages []*db.Message,
 		prompt.WriteString("   - DO NOT create one task per message\n")
 		prompt.WriteString("   - DO NOT use timestamps as task titles\n")
 		prompt.WriteString("   - BAD: '1. Oct 2, 12:00 PM - Chris says...', '2. Oct 3, 2:15 PM - Alex responds...'\n")
-		prompt.WriteString("   - GOOD: '1. Title: Prepare NDA for client review | Due: Thursday | ...'\n")
+		prompt.WriteString("   - GOOD: one task titled 'Prepare NDA for client review', due Thursday\n")
 		prompt.WriteString("   - Look at the FINAL outcome of the conversation, not every message\n\n")
 	}
 
@@ -302,7 +415,7 @@ func (p *PromptBuilder) BuildTaskExtractionWithMetadata(messages []*db.Message,
 	prompt.WriteString("     * 'No clear requests identified'\n")
 	prompt.WriteString("     * 'Thread requires further review'\n")
 	prompt.WriteString("     * 'Action items unclear'\n")
-	prompt.WriteString("   - If no actionable tasks exist, return empty list\n\n")
+	prompt.WriteString("   - If no actionable tasks exist, return no tasks\n\n")
 
 	prompt.WriteString("5. FYI vs ACTIONABLE:\n")
 	prompt.WriteString("   - SKIP: 'FYI', 'for your information', 'keeping you in the loop', 'no action needed'\n")
@@ -321,14 +434,14 @@ func (p *PromptBuilder) BuildTaskExtractionWithMetadata(messages []*db.Message,
 	prompt.WriteString("  * NOT a meta-comment: ❌ 'No clear requests identified'\n")
 	prompt.WriteString("  * GOOD: ✅ 'Review Q4 budget variance report'\n\n")
 
-	prompt.WriteString("- due_date: Extract ANY temporal reference from the email:\n")
+	prompt.WriteString("- due: Extract ANY temporal reference from the email:\n")
 	prompt.WriteString("  * Explicit dates: 'Oct 30', '12/15', '2025-01-15', 'January 15th'\n")
 	prompt.WriteString("  * Relative dates: 'tomorrow', 'in 2 days', 'next Monday', 'end of week'\n")
 	prompt.WriteString("  * Deadline phrases: 'by Friday', 'before EOD', 'by end of day'\n")
 	prompt.WriteString("  * Event-relative: 'before the meeting', 'after launch'\n")
 	prompt.WriteString("  * Urgency signals: 'URGENT' → 'today', 'ASAP' → 'within 24 hours'\n")
 	prompt.WriteString("  * Format as natural language (e.g., 'Friday', 'Oct 30', 'tomorrow')\n")
-	prompt.WriteString("  * If NO temporal reference, leave as 'N/A'\n\n")
+	prompt.WriteString("  * If NO temporal reference, leave as ''\n\n")
 
 	prompt.WriteString("- impact: Business impact (1-5) - **FORCE YOURSELF TO USE FULL RANGE**\n")
 	prompt.WriteString("  * 1 = Nice to have, minimal consequence (e.g., 'Review blog draft', 'Update profile')\n")
@@ -358,19 +471,14 @@ func (p *PromptBuilder) BuildTaskExtractionWithMetadata(messages []*db.Message,
 	prompt.WriteString("  * FOURTH: Check To/CC headers for context\n")
 	prompt.WriteString("  * Extract FULL NAME when possible (e.g., 'Sarah Chen' not 'Sarah')\n")
 	prompt.WriteString("  * Normalize email addresses (e.g., 's.chen@company.com' → 'Sarah Chen')\n")
-	prompt.WriteString("  * ONLY use 'N/A' if no person identifiable\n")
+	prompt.WriteString("  * ONLY leave empty if no person identifiable\n")
 	prompt.WriteString("  * VALID: 'Sarah Chen', 'Tim Davis'\n")
 	prompt.WriteString("  * INVALID: 'Finance Team', 'Customer Support', 'Marketing'\n\n")
 
 	prompt.WriteString("- project: Related project/initiative (if mentioned)\n\n")
 
 	prompt.WriteString("\n=== OUTPUT FORMAT ===\n")
-	prompt.WriteString("Numbered list with pipe-delimited fields:\n")
-	prompt.WriteString("1. Title: [Action] | Due: [Deadline] | Impact: [1-5] | Urgency: [1-5] | Effort: [S/M/L] | Stakeholder: [Name] | Project: [Context]\n\n")
-
-	prompt.WriteString("If no actionable tasks, return empty list.\n\n")
-
-	prompt.WriteString("YOUR EXTRACTED TASKS:\n")
+	prompt.WriteString(taskOutputFormat + "\n")
 
 	return prompt.String()
 }
@@ -378,6 +486,10 @@ func (p *PromptBuilder) BuildTaskExtractionWithMetadata(messages []*db.Message,
 // BuildTaskEnrichment creates a prompt for enriching task descriptions with full email context
 // UPDATED: PRESERVE + ADD approach, 400-600 chars, enhanced date/stakeholder extraction
 func (p *PromptBuilder) BuildTaskEnrichment(task *db.Task, messages []*db.Message) string {
+	if prompt, ok := p.fromTemplate(promptTaskEnrichment, PromptData{Task: task, Messages: messages}); ok {
+		return prompt
+	}
+
 	var prompt strings.Builder
 
 	prompt.WriteString("You are enriching a task description by ADDING context from the email thread.\n")
@@ -421,6 +533,7 @@ func (p *PromptBuilder) BuildTaskEnrichment(task *db.Task, messages []*db.Messag
 			}
 			prompt.WriteString(fmt.Sprintf("Content: %s\n", body))
 		}
+		writeAttachments(&prompt, msg.Attachments, enrichmentAttachmentChars)
 	}
 	prompt.WriteString("\n")
 
@@ -476,6 +589,10 @@ func (p *PromptBuilder) BuildTaskEnrichment(task *db.Task, messages []*db.Messag
 
 // BuildStrategicAlignment creates a prompt for evaluating task alignment with strategic priorities
 func (p *PromptBuilder) BuildStrategicAlignment(task *db.Task, priorities *config.Priorities) string {
+	if prompt, ok := p.fromTemplate(promptStrategicAlignment, PromptData{Task: task, Priorities: priorities}); ok {
+		return prompt
+	}
+
 	var prompt strings.Builder
 
 	prompt.WriteString("Evaluate how well this task aligns with the following strategic priorities.\n\n")
@@ -493,70 +610,130 @@ func (p *PromptBuilder) BuildStrategicAlignment(task *db.Task, priorities *confi
 	}
 	prompt.WriteString("\n")
 
-	prompt.WriteString("STRATEGIC PRIORITIES:\n\n")
+	writeStrategicPriorities(&prompt, priorities)
+	writeAlignmentRules(&prompt)
+
+	prompt.WriteString("Return:\n")
+	prompt.WriteString("- score: 0.0 (no alignment) to 5.0 (perfect alignment)\n")
+	prompt.WriteString("- okrs: array of OKR names that genuinely align (empty array if none)\n")
+	prompt.WriteString("- focus_areas: array of Focus Area names that align (empty array if none)\n")
+	prompt.WriteString("- projects: array of Project names that align (empty array if none)\n")
+	prompt.WriteString("- key_stakeholder: true if task stakeholder matches any Key Stakeholder, false otherwise\n")
+	prompt.WriteString("- reasoning: brief explanation of your evaluation (include why you excluded matches if any)\n")
+
+	return prompt.String()
+}
+
+// BuildStrategicAlignmentBatch creates a prompt evaluating several tasks against the strategic
+// priorities in one call. Tasks are numbered from 1 in the order given, and the response
+// refers to them by number.
+func (p *PromptBuilder) BuildStrategicAlignmentBatch(tasks []*db.Task, priorities *config.Priorities) string {
+	if prompt, ok := p.fromTemplate(promptStrategicAlignmentBatch, PromptData{Tasks: tasks, Priorities: priorities}); ok {
+		return prompt
+	}
+
+	var prompt strings.Builder
+
+	prompt.WriteString("Evaluate how well each of these tasks aligns with the following strategic priorities. Evaluate every task on its own.\n\n")
+
+	prompt.WriteString("TASKS:\n")
+	for i, task := range tasks {
+		prompt.WriteString(fmt.Sprintf("Task %d:\n", i+1))
+		prompt.WriteString(fmt.Sprintf("  Title: %s\n", task.Title))
+		if task.Description != "" {
+			prompt.WriteString(fmt.Sprintf("  Description: %s\n", task.Description))
+		}
+		if task.Project != "" {
+			prompt.WriteString(fmt.Sprintf("  Project: %s\n", task.Project))
+		}
+		if task.Stakeholder != "" {
+			prompt.WriteString(fmt.Sprintf("  Stakeholder: %s\n", task.Stakeholder))
+		}
+	}
+	prompt.WriteString("\n")
+
+	writeStrategicPriorities(&prompt, priorities)
+	writeAlignmentRules(&prompt)
+
+	prompt.WriteString("Return a JSON object with a \"results\" array holding one entry per task, each with:\n")
+	prompt.WriteString("- task: the task number\n")
+	prompt.WriteString("- score: 0.0 (no alignment) to 5.0 (perfect alignment)\n")
+	prompt.WriteString("- okrs: array of OKR names that genuinely align (empty array if none)\n")
+	prompt.WriteString("- focus_areas: array of Focus Area names that align (empty array if none)\n")
+	prompt.WriteString("- projects: array of Project names that align (empty array if none)\n")
+	prompt.WriteString("- key_stakeholder: true if task stakeholder matches any Key Stakeholder, false otherwise\n")
+	prompt.WriteString("- reasoning: one short sentence\n\n")
+	prompt.WriteString(`Example: {"results": [{"task": 1, "score": 3.5, "okrs": [], "focus_areas": ["Known for distinctive service"], "projects": [], "key_stakeholder": false, "reasoning": "Directly improves member experience"}]}`)
+	prompt.WriteString("\n")
+
+	return prompt.String()
+}
+
+// writeStrategicPriorities writes the priorities section of the strategic alignment prompts
+func writeStrategicPriorities(b *strings.Builder, priorities *config.Priorities) {
+	b.WriteString("STRATEGIC PRIORITIES:\n\n")
 
 	if len(priorities.OKRs) > 0 {
-		prompt.WriteString("OKRs (Objectives & Key Results):\n")
+		b.WriteString("OKRs (Objectives & Key Results):\n")
 		for _, okr := range priorities.OKRs {
-			prompt.WriteString(fmt.Sprintf("  - %s\n", okr))
+			b.WriteString(fmt.Sprintf("  - %s\n", okr))
 		}
-		prompt.WriteString("\n")
+		b.WriteString("\n")
 	}
 
 	if len(priorities.FocusAreas) > 0 {
-		prompt.WriteString("Focus Areas:\n")
+		b.WriteString("Focus Areas:\n")
 		for _, area := range priorities.FocusAreas {
-			prompt.WriteString(fmt.Sprintf("  - %s\n", area))
+			b.WriteString(fmt.Sprintf("  - %s\n", area))
 		}
-		prompt.WriteString("\n")
+		b.WriteString("\n")
 	}
 
 	if len(priorities.KeyProjects) > 0 {
-		prompt.WriteString("Key Projects:\n")
+		b.WriteString("Key Projects:\n")
 		for _, project := range priorities.KeyProjects {
-			prompt.WriteString(fmt.Sprintf("  - %s\n", project))
+			b.WriteString(fmt.Sprintf("  - %s\n", project))
 		}
-		prompt.WriteString("\n")
+		b.WriteString("\n")
 	}
 
 	if len(priorities.KeyStakeholders) > 0 {
-		prompt.WriteString("Key Stakeholders (VIP contacts - tasks from these people are high priority):\n")
+		b.WriteString("Key Stakeholders (VIP contacts - tasks from these people are high priority):\n")
 		for _, stakeholder := range priorities.KeyStakeholders {
-			prompt.WriteString(fmt.Sprintf("  - %s\n", stakeholder))
+			b.WriteString(fmt.Sprintf("  - %s\n", stakeholder))
 		}
-		prompt.WriteString("\n")
+		b.WriteString("\n")
 	}
+}
 
-	prompt.WriteString("INSTRUCTIONS:\n")
-	prompt.WriteString("Evaluate the DIRECT, MEANINGFUL alignment between this task and the strategic priorities.\n\n")
-	prompt.WriteString("STRICT MATCHING RULES:\n")
-	prompt.WriteString("1. Only match if the task DIRECTLY advances or relates to the priority\n")
-	prompt.WriteString("2. Shared keywords alone are NOT sufficient (e.g., 'data team' ≠ 'Data lake project')\n")
-	prompt.WriteString("3. Generic administrative tasks (scheduling, coordinating, reporting) should NOT match strategic priorities unless they're specifically about implementing/advancing that priority\n")
-	prompt.WriteString("4. Be conservative - when in doubt, DON'T match\n")
-	prompt.WriteString("5. Check if the task stakeholder matches any Key Stakeholder (exact email match or similar)\n\n")
-	prompt.WriteString("EXAMPLES OF POOR MATCHES TO AVOID:\n")
-	prompt.WriteString("- 'Schedule meeting about X' does NOT align with X unless the meeting is to implement/advance X\n")
-	prompt.WriteString("- 'Send report to team' does NOT align with 'Improved forecasting' just because both involve data\n")
-	prompt.WriteString("- 'Coordinate with data team' does NOT align with 'Data lake' unless specifically about the data lake\n")
-	prompt.WriteString("- 'Review budget' does NOT align with 'Profitability' unless it's specifically about improving margins\n\n")
-	prompt.WriteString("EXAMPLES OF GOOD MATCHES:\n")
-	prompt.WriteString("- 'Implement new CRM dashboard' → 'Scalable systems - CRM implementation'\n")
-	prompt.WriteString("- 'Analyze margin trends for cost optimization' → 'Sector Leading Profitability'\n")
-	prompt.WriteString("- 'Design brand guidelines for member experience' → 'Known for distinctive service'\n\n")
-	prompt.WriteString("Return:\n")
-	prompt.WriteString("- score: 0.0 (no alignment) to 5.0 (perfect alignment)\n")
-	prompt.WriteString("- okrs: array of OKR names that genuinely align (empty array if none)\n")
-	prompt.WriteString("- focus_areas: array of Focus Area names that align (empty array if none)\n")
-	prompt.WriteString("- projects: array of Project names that align (empty array if none)\n")
-	prompt.WriteString("- key_stakeholder: true if task stakeholder matches any Key Stakeholder, false otherwise\n")
-	prompt.WriteString("- reasoning: brief explanation of your evaluation (include why you excluded matches if any)\n")
-
-	return prompt.String()
+// writeAlignmentRules writes the matching rules of the strategic alignment prompts
+func writeAlignmentRules(b *strings.Builder) {
+	b.WriteString("INSTRUCTIONS:\n")
+	b.WriteString("Evaluate the DIRECT, MEANINGFUL alignment between this task and the strategic priorities.\n\n")
+	b.WriteString("STRICT MATCHING RULES:\n")
+	b.WriteString("1. Only match if the task DIRECTLY advances or relates to the priority\n")
+	b.WriteString("2. Shared keywords alone are NOT sufficient (e.g., 'data team' ≠ 'Data lake project')\n")
+	b.WriteString("3. Generic administrative tasks (scheduling, coordinating, reporting) should NOT match strategic priorities unless they're specifically about implementing/advancing that priority\n")
+	b.WriteString("4. Be conservative - when in doubt, DON'T match\n")
+	b.WriteString("5. Check if the task stakeholder matches any Key Stakeholder (exact email match or similar)\n\n")
+	b.WriteString("EXAMPLES OF POOR MATCHES TO AVOID:\n")
+	b.WriteString("- 'Schedule meeting about X' does NOT align with X unless the meeting is to implement/advance X\n")
+	b.WriteString("- 'Send report to team' does NOT align with 'Improved forecasting' just because both involve data\n")
+	b.WriteString("- 'Coordinate with data team' does NOT align with 'Data lake' unless specifically about the data lake\n")
+	b.WriteString("- 'Review budget' does NOT align with 'Profitability' unless it's specifically about improving margins\n\n")
+	b.WriteString("EXAMPLES OF GOOD MATCHES:\n")
+	b.WriteString("- 'Implement new CRM dashboard' → 'Scalable systems - CRM implementation'\n")
+	b.WriteString("- 'Analyze margin trends for cost optimization' → 'Sector Leading Profitability'\n")
+	b.WriteString("- 'Design brand guidelines for member experience' → 'Known for distinctive service'\n\n")
 }
 
-// BuildReply creates a prompt for drafting email replies
-func (p *PromptBuilder) BuildReply(thread []*db.Message, goal string) string {
+// BuildReply creates a prompt for drafting email replies. A nil persona keeps the default
+// concise, professional tone.
+func (p *PromptBuilder) BuildReply(thread []*db.Message, goal string, persona *config.Persona) string {
+	if prompt, ok := p.fromTemplate(promptReply, PromptData{Messages: thread, Goal: goal, Persona: persona}); ok {
+		return prompt
+	}
+
 	var prompt strings.Builder
 
 	prompt.WriteString("Draft a concise, professional email reply.\n\n")
@@ -569,19 +746,31 @@ func (p *PromptBuilder) BuildReply(thread []*db.Message, goal string) string {
 		prompt.WriteString(fmt.Sprintf("Content: %s\n\n", msg.Snippet))
 	}
 
+	maxWords := 150
 	prompt.WriteString("Draft a reply that:\n")
-	prompt.WriteString("- Is concise and to the point\n")
-	prompt.WriteString("- Maintains professional tone\n")
-	prompt.WriteString("- Addresses the goal clearly\n")
-	prompt.WriteString("- Uses my typical writing style (direct, friendly)\n\n")
+	if persona != nil {
+		prompt.WriteString(fmt.Sprintf("- Follows this style: %s\n", persona.Instructions))
+		prompt.WriteString("- Addresses the goal clearly\n\n")
+		maxWords = persona.MaxWords
+	} else {
+		prompt.WriteString("- Is concise and to the point\n")
+		prompt.WriteString("- Maintains professional tone\n")
+		prompt.WriteString("- Addresses the goal clearly\n")
+		prompt.WriteString("- Uses my typical writing style (direct, friendly)\n\n")
+	}
 
-	prompt.WriteString("Reply (max 150 words):")
+	prompt.WriteString(fmt.Sprintf("Reply (max %d words):", maxWords))
 
 	return prompt.String()
 }
 
-// BuildMeetingPrep creates a prompt for meeting preparation
-func (p *PromptBuilder) BuildMeetingPrep(event *db.Event, docs []*db.Document) string {
+// BuildMeetingPrep creates a prompt for meeting preparation from the meeting and its related
+// documents and email threads
+func (p *PromptBuilder) BuildMeetingPrep(event *db.Event, docs []*db.Document, threads []*db.ContactInteraction) string {
+	if prompt, ok := p.fromTemplate(promptMeetingPrep, PromptData{Event: event, Documents: docs, Interactions: threads}); ok {
+		return prompt
+	}
+
 	var prompt strings.Builder
 
 	prompt.WriteString("Generate a one-page meeting preparation brief.\n\n")
@@ -600,7 +789,24 @@ func (p *PromptBuilder) BuildMeetingPrep(event *db.Event, docs []*db.Document) s
 	if len(docs) > 0 {
 		prompt.WriteString("\nRelated documents:\n")
 		for _, doc := range docs {
-			prompt.WriteString(fmt.Sprintf("- %s\n", doc.Title))
+			if doc.Summary != "" {
+				prompt.WriteString(fmt.Sprintf("- %s: %s\n", doc.Title, excerpt(doc.Summary, meetingPrepContextChars)))
+			} else {
+				prompt.WriteString(fmt.Sprintf("- %s\n", doc.Title))
+			}
+		}
+	}
+
+	if len(threads) > 0 {
+		prompt.WriteString("\nRelated email threads:\n")
+		for _, thread := range threads {
+			detail := thread.Summary
+			if detail == "" {
+				detail = thread.Snippet
+			}
+			prompt.WriteString(fmt.Sprintf("- %s (last from %s, %s): %s\n", thread.Subject, thread.LastFrom,
+				thread.LastTS.Format("Jan 2"), excerpt(detail, meetingPrepContextChars)))
+			writeInsights(&prompt, thread.Insights)
 		}
 	}
 
@@ -616,6 +822,202 @@ func (p *PromptBuilder) BuildMeetingPrep(event *db.Event, docs []*db.Document) s
 	return prompt.String()
 }
 
+// BuildRelationshipBrief asks for the sentiment trend and topics of a relationship ahead of a meeting
+func (p *PromptBuilder) BuildRelationshipBrief(event *db.Event, history *db.ContactHistory) string {
+	if prompt, ok := p.fromTemplate(promptRelationshipBrief, PromptData{Event: event, History: history}); ok {
+		return prompt
+	}
+
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("I am about to meet %s.\n", history.Email))
+	prompt.WriteString(fmt.Sprintf("Meeting: %s at %s\n\n", event.Title, event.StartTS.Format("Monday, Jan 2, 3:04 PM")))
+
+	prompt.WriteString("Our recent email threads (newest first):\n")
+	for _, interaction := range history.Interactions {
+		summary := interaction.Summary
+		if summary == "" {
+			summary = interaction.Snippet
+		}
+		prompt.WriteString(fmt.Sprintf("- %s | %s | last from %s\n  %s\n",
+			interaction.LastTS.Format("Jan 2"), interaction.Subject, interaction.LastFrom, summary))
+	}
+
+	if len(history.FrontComments) > 0 {
+		prompt.WriteString("\nInternal team comments about these conversations:\n")
+		for _, comment := range history.FrontComments {
+			prompt.WriteString(fmt.Sprintf("- %s: %s\n", comment.AuthorName, comment.Body))
+		}
+	}
+
+	if len(history.FrontTags) > 0 {
+		prompt.WriteString(fmt.Sprintf("\nTags: %s\n", strings.Join(history.FrontTags, ", ")))
+	}
+
+	prompt.WriteString("\nReply with exactly two short sections and nothing else:\n")
+	prompt.WriteString("Sentiment: one line - improving, stable or declining, and why\n")
+	prompt.WriteString("Topics: up to 5 comma-separated topics we have discussed\n")
+
+	return prompt.String()
+}
+
+// BuildQuestionAnswer asks for an answer to a question about the user's mail and tasks,
+// grounded only in the retrieved messages and tasks
+func (p *PromptBuilder) BuildQuestionAnswer(question string, messages []*db.Message, tasks []*db.Task) string {
+	if prompt, ok := p.fromTemplate(promptQuestionAnswer, PromptData{Question: question, Messages: messages, Tasks: tasks}); ok {
+		return prompt
+	}
+
+	var prompt strings.Builder
+
+	prompt.WriteString("Answer my question using only the emails and tasks below.\n")
+	prompt.WriteString("If they don't contain the answer, say so rather than guessing.\n\n")
+
+	if len(messages) > 0 {
+		prompt.WriteString("Emails:\n")
+		for i, msg := range messages {
+			body := msg.Body
+			if len(body) > 1500 {
+				body = body[:1500] + "..."
+			}
+			prompt.WriteString(fmt.Sprintf("[M%d] %s | From: %s | Subject: %s\n%s\n\n",
+				i+1, msg.Timestamp.Format("Jan 2, 2006"), msg.From, msg.Subject, body))
+		}
+	}
+
+	if len(tasks) > 0 {
+		prompt.WriteString("Tasks:\n")
+		for i, task := range tasks {
+			prompt.WriteString(fmt.Sprintf("[T%d] %s (status: %s", i+1, task.Title, task.Status))
+			if task.Stakeholder != "" {
+				prompt.WriteString(fmt.Sprintf(", stakeholder: %s", task.Stakeholder))
+			}
+			if task.DueTS != nil {
+				prompt.WriteString(fmt.Sprintf(", due: %s", task.DueTS.Format("Jan 2")))
+			}
+			prompt.WriteString(")\n")
+			if task.Description != "" {
+				prompt.WriteString(fmt.Sprintf("  %s\n", task.Description))
+			}
+		}
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString(fmt.Sprintf("Question: %s\n\n", question))
+	prompt.WriteString("Answer concisely and cite the emails or tasks you used, e.g. [M2] or [T1].\n")
+
+	return prompt.String()
+}
+
+// BuildWeeklyReview asks for a short narrative of the week from what got done, what slipped,
+// unanswered mail and time spent in meetings
+func (p *PromptBuilder) BuildWeeklyReview(review *db.WeeklyReview) string {
+	if prompt, ok := p.fromTemplate(promptWeeklyReview, PromptData{Review: review}); ok {
+		return prompt
+	}
+
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("Here is my week from %s to %s.\n\n",
+		review.Since.Format("Monday, Jan 2"), review.Until.Format("Monday, Jan 2")))
+
+	prompt.WriteString(fmt.Sprintf("Completed (%d):\n", len(review.Completed)))
+	for _, task := range review.Completed {
+		prompt.WriteString(fmt.Sprintf("- %s", task.Title))
+		if task.Project != "" {
+			prompt.WriteString(fmt.Sprintf(" (project: %s)", task.Project))
+		}
+		prompt.WriteString("\n")
+	}
+
+	prompt.WriteString(fmt.Sprintf("\nSlipped past their due date, still open (%d):\n", len(review.Slipped)))
+	for _, task := range review.Slipped {
+		prompt.WriteString(fmt.Sprintf("- %s (due %s)\n", task.Title, task.DueTS.Format("Mon Jan 2")))
+	}
+
+	prompt.WriteString(fmt.Sprintf("\nEmails still waiting on my reply (%d):\n", len(review.AwaitingReply)))
+	for _, thread := range review.AwaitingReply {
+		prompt.WriteString(fmt.Sprintf("- %s from %s, %s\n", thread.Subject, thread.From, thread.ReceivedAt.Format("Mon Jan 2")))
+	}
+
+	prompt.WriteString(fmt.Sprintf("\nMeetings: %d, %.1f hours in total\n\n", review.MeetingCount, review.MeetingHours))
+
+	prompt.WriteString("Write a short review of the week in 3-5 sentences of plain prose, addressed to me: ")
+	prompt.WriteString("the main themes of what got done, what slipped and any pattern behind it, and one ")
+	prompt.WriteString("concrete suggestion for next week. No headings, lists or preamble.\n")
+
+	return prompt.String()
+}
+
+// waitingRequestBodyChars caps how much of a sent email is given to the LLM when checking it
+// for a request
+const waitingRequestBodyChars = 2000
+
+// BuildWaitingRequest creates a prompt for finding what an email the user sent asks the
+// recipient to do
+func (p *PromptBuilder) BuildWaitingRequest(msg *db.Message) string {
+	if prompt, ok := p.fromTemplate(promptWaitingRequest, PromptData{Message: msg}); ok {
+		return prompt
+	}
+
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("I (%s) sent this email on %s.\n\n", p.userEmail, msg.Timestamp.Format("Monday, 2006-01-02")))
+	prompt.WriteString(fmt.Sprintf("To: %s\n", msg.To))
+	prompt.WriteString(fmt.Sprintf("Subject: %s\n\n", msg.Subject))
+	body := msg.Body
+	if body == "" {
+		body = msg.Snippet
+	}
+	prompt.WriteString(excerpt(body, waitingRequestBodyChars))
+	prompt.WriteString("\n\n")
+
+	prompt.WriteString("Does this email ask the recipient for something I'm now waiting on: an answer, a decision, ")
+	prompt.WriteString("a document, an approval or some work? Quoted earlier messages, thanks, FYIs, offers and ")
+	prompt.WriteString("\"let me know if you have questions\" don't count.\n\n")
+
+	prompt.WriteString("Respond with ONLY a JSON object with these fields:\n")
+	prompt.WriteString("- is_request (boolean)\n")
+	prompt.WriteString("- request (string): what I asked for, in under 80 characters, e.g. \"Sign-off on the Q3 budget\"\n")
+	prompt.WriteString("- recipient (string): email address of the person expected to reply\n")
+	prompt.WriteString("- reply_by (string): the date a reply was asked for as YYYY-MM-DD (\"by Friday\" means the ")
+	prompt.WriteString("Friday after the send date), or empty if the email doesn't say\n")
+
+	return prompt.String()
+}
+
+// BuildCommitments creates a prompt for finding what the user promised in an email they sent
+func (p *PromptBuilder) BuildCommitments(msg *db.Message) string {
+	if prompt, ok := p.fromTemplate(promptCommitments, PromptData{Message: msg}); ok {
+		return prompt
+	}
+
+	var prompt strings.Builder
+
+	prompt.WriteString(fmt.Sprintf("I (%s) sent this email on %s.\n\n", p.userEmail, msg.Timestamp.Format("Monday, 2006-01-02")))
+	prompt.WriteString(fmt.Sprintf("To: %s\n", msg.To))
+	prompt.WriteString(fmt.Sprintf("Subject: %s\n\n", msg.Subject))
+	body := msg.Body
+	if body == "" {
+		body = msg.Snippet
+	}
+	prompt.WriteString(excerpt(body, waitingRequestBodyChars))
+	prompt.WriteString("\n\n")
+
+	prompt.WriteString("List what I promised to do or send in this email: \"I'll send the deck by Friday\", ")
+	prompt.WriteString("\"let me check and get back to you\", \"I'll review it tomorrow\". Quoted earlier messages, ")
+	prompt.WriteString("pleasantries, \"let me know if you have questions\" and things already done in this email don't count.\n\n")
+
+	prompt.WriteString("Respond with ONLY a JSON array, empty if I promised nothing. Each element is an object with:\n")
+	prompt.WriteString("- promise (string): what I promised, in under 80 characters, e.g. \"Send the Q3 budget deck\"\n")
+	prompt.WriteString("- recipient (string): email address of the person I promised it to\n")
+	prompt.WriteString("- due_by (string): the date I promised it by as YYYY-MM-DD (\"by Friday\" means the Friday ")
+	prompt.WriteString("after the send date), or empty if the email doesn't say\n")
+	prompt.WriteString("- deliverable (boolean): true if I promised to send a file or document, false if a reply will do\n")
+
+	return prompt.String()
+}
+
 // BuildTaskExtractionWithConversationFlow creates an advanced prompt with conversation awareness
 // UPDATED: Now delegates to BuildTaskExtractionWithMetadata for consistency and noise reduction
 func (p *PromptBuilder) BuildTaskExtractionWithConversationFlow(messages []*db.Message, frontComments []*db.FrontComment, frontMetadata *db.FrontMetadata) string {
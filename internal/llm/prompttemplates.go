@@ -0,0 +1,159 @@
+package llm
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// Prompt template names. A file named after one, with a .tmpl extension, in the prompts
+// directory replaces the built-in prompt.
+const (
+	promptThreadSummary           = "thread_summary"
+	promptThreadGist              = "thread_gist"
+	promptThreadDeepSummary       = "thread_deep_summary"
+	promptTaskExtraction          = "task_extraction"
+	promptSentEmailTaskExtraction = "sent_email_task_extraction"
+	promptThreadTaskExtraction    = "thread_task_extraction"
+	promptTaskEnrichment          = "task_enrichment"
+	promptStrategicAlignment      = "strategic_alignment"
+	promptStrategicAlignmentBatch = "strategic_alignment_batch"
+	promptReply                   = "reply"
+	promptMeetingPrep             = "meeting_prep"
+	promptRelationshipBrief       = "relationship_brief"
+	promptQuestionAnswer          = "question_answer"
+	promptWeeklyReview            = "weekly_review"
+	promptWaitingRequest          = "waiting_request"
+	promptCommitments             = "commitments"
+)
+
+// PromptData is what a prompt template is executed with. Each prompt fills in the fields it
+// has; the rest are left empty.
+type PromptData struct {
+	UserEmail string
+	Now       time.Time
+
+	Content       string                   // task_extraction, sent_email_task_extraction
+	Recipients    []string                 // sent_email_task_extraction
+	Messages      []*db.Message            // Thread, oldest first
+	Message       *db.Message              // waiting_request, commitments
+	FrontComments []*db.FrontComment       // thread_task_extraction
+	FrontMetadata *db.FrontMetadata        // thread_task_extraction, nil outside Front
+	Task          *db.Task                 // task_enrichment, strategic_alignment
+	Tasks         []*db.Task               // question_answer, strategic_alignment_batch
+	Priorities    *config.Priorities       // strategic_alignment, strategic_alignment_batch
+	Goal          string                   // reply
+	Persona       *config.Persona          // reply, nil for the default tone
+	Question      string                   // question_answer
+	Event         *db.Event                // meeting_prep, relationship_brief
+	Documents     []*db.Document           // meeting_prep
+	Interactions  []*db.ContactInteraction // meeting_prep
+	History       *db.ContactHistory       // relationship_brief
+	Review        *db.WeeklyReview         // weekly_review
+
+	// OutputFormat is the JSON output instructions the task extraction parser expects
+	OutputFormat string
+}
+
+// promptFuncs are the functions available to prompt templates besides text/template's own
+var promptFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"lower":   strings.ToLower,
+	"excerpt": func(maxChars int, text string) string { return excerpt(text, maxChars) },
+}
+
+// fromTemplate builds a prompt from its template in the prompts directory, if there is one
+func (p *PromptBuilder) fromTemplate(name string, data PromptData) (string, bool) {
+	data.UserEmail = p.userEmail
+	data.Now = time.Now()
+	return p.templates.render(name, &data)
+}
+
+// promptTemplates loads prompt templates from a directory. Each file is checked for changes
+// whenever its prompt is built, so edits take effect without a restart.
+type promptTemplates struct {
+	dir string
+
+	mu     sync.Mutex
+	loaded map[string]*loadedTemplate
+}
+
+// loadedTemplate is a parsed template file and the version of the file it was parsed from
+type loadedTemplate struct {
+	modTime time.Time
+	size    int64
+	tmpl    *template.Template // nil when the file failed to parse
+}
+
+func newPromptTemplates(dir string) *promptTemplates {
+	return &promptTemplates{dir: dir, loaded: make(map[string]*loadedTemplate)}
+}
+
+// render executes the template for a prompt. It reports false when there is no template for
+// the prompt, or it's invalid, so the built-in prompt is used.
+func (t *promptTemplates) render(name string, data *PromptData) (string, bool) {
+	if t == nil || t.dir == "" {
+		return "", false
+	}
+
+	tmpl := t.load(name)
+	if tmpl == nil {
+		return "", false
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		log.Printf("Prompt template %s failed, using the built-in prompt: %v", name, err)
+		return "", false
+	}
+	return buf.String(), true
+}
+
+// load returns the current template for a prompt, parsing the file again if it changed
+func (t *promptTemplates) load(name string) *template.Template {
+	path := filepath.Join(t.dir, name+".tmpl")
+	info, err := os.Stat(path)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err != nil {
+		if _, ok := t.loaded[name]; ok {
+			log.Printf("Prompt template %s removed, using the built-in prompt", name)
+			delete(t.loaded, name)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Failed to read prompt template %s: %v", path, err)
+		}
+		return nil
+	}
+
+	if loaded, ok := t.loaded[name]; ok && loaded.modTime.Equal(info.ModTime()) && loaded.size == info.Size() {
+		return loaded.tmpl
+	}
+
+	text, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("Failed to read prompt template %s: %v", path, err)
+		return nil
+	}
+
+	// An invalid file is remembered too, so it's only reported again once it changes
+	loaded := &loadedTemplate{modTime: info.ModTime(), size: info.Size()}
+	t.loaded[name] = loaded
+	loaded.tmpl, err = template.New(name).Funcs(promptFuncs).Parse(string(text))
+	if err != nil {
+		log.Printf("Invalid prompt template %s, using the built-in prompt: %v", path, err)
+		return nil
+	}
+	log.Printf("Loaded prompt template %s", path)
+	return loaded.tmpl
+}
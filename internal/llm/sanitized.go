@@ -0,0 +1,93 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/sanitize"
+)
+
+// sanitizedClient strips HTML, scripts, tracking images and unsafe links from the text a client
+// generates. Models read untrusted email, so anything they write is untrusted too before it's
+// stored and later shown in a browser.
+type sanitizedClient struct {
+	Client
+}
+
+// Sanitized wraps a client so summaries, extracted tasks, enriched descriptions and other
+// generated prose are sanitized. Reply drafts go to Gmail as plain text and are left as written.
+func Sanitized(client Client) Client {
+	return &sanitizedClient{Client: client}
+}
+
+func (c *sanitizedClient) SummarizeThread(ctx context.Context, messages []*db.Message) (string, error) {
+	summary, err := c.Client.SummarizeThread(ctx, messages)
+	return sanitize.Markdown(summary), err
+}
+
+func (c *sanitizedClient) SummarizeThreadWithModelSelection(ctx context.Context, messages []*db.Message, metadata ThreadMetadata) (string, error) {
+	summary, err := c.Client.SummarizeThreadWithModelSelection(ctx, messages, metadata)
+	return sanitize.Markdown(summary), err
+}
+
+func (c *sanitizedClient) SummarizeThreadGist(ctx context.Context, messages []*db.Message) (string, error) {
+	gist, err := c.Client.SummarizeThreadGist(ctx, messages)
+	return sanitize.Markdown(gist), err
+}
+
+func (c *sanitizedClient) SummarizeThreadDeep(ctx context.Context, messages []*db.Message, metadata ThreadMetadata) (*DeepSummary, error) {
+	summary, err := c.Client.SummarizeThreadDeep(ctx, messages, metadata)
+	if summary != nil {
+		summary.Summary = sanitize.Markdown(summary.Summary)
+		for _, items := range [][]string{summary.Decisions, summary.Owners, summary.Risks, summary.OpenQuestions} {
+			for i := range items {
+				items[i] = sanitize.Markdown(items[i])
+			}
+		}
+	}
+	return summary, err
+}
+
+func (c *sanitizedClient) ExtractTasks(ctx context.Context, content string) ([]*db.Task, error) {
+	tasks, err := c.Client.ExtractTasks(ctx, content)
+	return sanitizeTasks(tasks), err
+}
+
+func (c *sanitizedClient) ExtractTasksFromMessages(ctx context.Context, content string, messages []*db.Message, frontComments []*db.FrontComment, frontMetadata *db.FrontMetadata) ([]*db.Task, error) {
+	tasks, err := c.Client.ExtractTasksFromMessages(ctx, content, messages, frontComments, frontMetadata)
+	return sanitizeTasks(tasks), err
+}
+
+func (c *sanitizedClient) EnrichTaskDescription(ctx context.Context, task *db.Task, messages []*db.Message) (string, error) {
+	description, err := c.Client.EnrichTaskDescription(ctx, task, messages)
+	return sanitize.Markdown(description), err
+}
+
+func (c *sanitizedClient) GenerateMeetingPrep(ctx context.Context, event *db.Event, relatedDocs []*db.Document, relatedThreads []*db.ContactInteraction) (string, error) {
+	prep, err := c.Client.GenerateMeetingPrep(ctx, event, relatedDocs, relatedThreads)
+	return sanitize.Markdown(prep), err
+}
+
+func (c *sanitizedClient) GenerateRelationshipBrief(ctx context.Context, event *db.Event, history *db.ContactHistory) (string, error) {
+	brief, err := c.Client.GenerateRelationshipBrief(ctx, event, history)
+	return sanitize.Markdown(brief), err
+}
+
+func (c *sanitizedClient) AnswerQuestion(ctx context.Context, question string, messages []*db.Message, tasks []*db.Task) (string, error) {
+	answer, err := c.Client.AnswerQuestion(ctx, question, messages, tasks)
+	return sanitize.Markdown(answer), err
+}
+
+func (c *sanitizedClient) WriteWeeklyReview(ctx context.Context, review *db.WeeklyReview) (string, error) {
+	narrative, err := c.Client.WriteWeeklyReview(ctx, review)
+	return sanitize.Markdown(narrative), err
+}
+
+// sanitizeTasks cleans the text fields of extracted tasks in place
+func sanitizeTasks(tasks []*db.Task) []*db.Task {
+	for _, task := range tasks {
+		task.Title = sanitize.Markdown(task.Title)
+		task.Description = sanitize.Markdown(task.Description)
+	}
+	return tasks
+}
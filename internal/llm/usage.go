@@ -0,0 +1,151 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// claudeDefaultModel is what Claude CLI calls are priced as when the CLI doesn't say which
+// model answered
+const claudeDefaultModel = "claude-haiku"
+
+// tokenUsage is the tokens one LLM call read and wrote
+type tokenUsage struct {
+	input  int
+	output int
+}
+
+// geminiUsage returns the tokens Gemini reported for a response, or estimates them from the
+// prompt and response when it reported none
+func (g *GeminiClient) geminiUsage(resp *genai.GenerateContentResponse, prompt, response string) tokenUsage {
+	if resp == nil || resp.UsageMetadata == nil || resp.UsageMetadata.PromptTokenCount == 0 {
+		return tokenUsage{input: g.estimateTokens(prompt), output: g.estimateTokens(response)}
+	}
+
+	meta := resp.UsageMetadata
+	output := int(meta.CandidatesTokenCount)
+	// Thinking tokens are billed as output but only show up in the total
+	if thinking := int(meta.TotalTokenCount - meta.PromptTokenCount - meta.CandidatesTokenCount); thinking > 0 {
+		output += thinking
+	}
+	return tokenUsage{input: int(meta.PromptTokenCount), output: output}
+}
+
+// logUsage records a successful Gemini call, priced for the model that answered, and returns
+// the tokens it used
+func (g *GeminiClient) logUsage(key, action, model string, resp *genai.GenerateContentResponse, prompt, response string, startTime time.Time) int {
+	usage := g.geminiUsage(resp, prompt, response)
+	g.db.LogLLMUsage(&db.LLMUsage{
+		Service:      "gemini",
+		APIKey:       key,
+		Action:       action,
+		Model:        model,
+		InputTokens:  usage.input,
+		OutputTokens: usage.output,
+		Cost:         g.config.LLM.Cost(model, usage.input, usage.output),
+		Duration:     time.Since(startTime),
+	})
+	return usage.input + usage.output
+}
+
+// logOllamaUsage records a successful Ollama call. Ollama doesn't report tokens through our
+// client, so they're estimated; local models cost nothing unless priced in config.
+func (h *HybridClient) logOllamaUsage(action, prompt, response string, startTime time.Time) {
+	model := h.config.Ollama.Model
+	input, output := h.gemini.estimateTokens(prompt), h.gemini.estimateTokens(response)
+	h.db.LogLLMUsage(&db.LLMUsage{
+		Service:      "ollama",
+		Action:       action,
+		Model:        model,
+		InputTokens:  input,
+		OutputTokens: output,
+		Cost:         h.config.LLM.Cost(model, input, output),
+		Duration:     time.Since(startTime),
+	})
+}
+
+// claudeResult is the JSON the Claude CLI prints with --output-format json
+type claudeResult struct {
+	Result  string `json:"result"`
+	IsError bool   `json:"is_error"`
+	Usage   struct {
+		InputTokens              int `json:"input_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+	} `json:"usage"`
+	ModelUsage map[string]json.RawMessage `json:"modelUsage"`
+}
+
+// model returns the model that answered, when the CLI used just one
+func (r *claudeResult) model() string {
+	if len(r.ModelUsage) == 1 {
+		for model := range r.ModelUsage {
+			return model
+		}
+	}
+	return claudeDefaultModel
+}
+
+// callClaude executes the claude CLI with the given prompt and records the call's usage as
+// the given action
+func (h *HybridClient) callClaude(ctx context.Context, action, prompt string) (string, error) {
+	if h.claudePath == "" {
+		return "", fmt.Errorf("claude CLI not available")
+	}
+
+	cmd := exec.CommandContext(ctx,
+		h.claudePath,
+		"-p",
+		"--model", "haiku",
+		"--dangerously-skip-permissions",
+		"--output-format", "json",
+		prompt,
+	)
+
+	startTime := time.Now()
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("claude CLI failed: %w (stderr: %s)", err, string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("claude CLI execution failed: %w", err)
+	}
+
+	var result claudeResult
+	if err := json.Unmarshal(output, &result); err != nil {
+		return "", fmt.Errorf("failed to parse claude CLI output: %w", err)
+	}
+	if result.IsError {
+		return "", fmt.Errorf("claude CLI failed: %s", excerpt(result.Result, 200))
+	}
+	response := strings.TrimSpace(result.Result)
+
+	usage := tokenUsage{
+		input:  result.Usage.InputTokens + result.Usage.CacheCreationInputTokens + result.Usage.CacheReadInputTokens,
+		output: result.Usage.OutputTokens,
+	}
+	if usage.input == 0 {
+		usage = tokenUsage{input: h.gemini.estimateTokens(prompt), output: h.gemini.estimateTokens(response)}
+	}
+	model := result.model()
+	h.db.LogLLMUsage(&db.LLMUsage{
+		Service:      "claude",
+		Action:       action,
+		Model:        model,
+		InputTokens:  usage.input,
+		OutputTokens: usage.output,
+		Cost:         h.config.LLM.Cost(model, usage.input, usage.output),
+		Duration:     time.Since(startTime),
+	})
+
+	return response, nil
+}
@@ -0,0 +1,236 @@
+// Package metrics keeps in-process counters and histograms and writes them in the Prometheus
+// text format for GET /metrics. Values reset when the agent restarts; the usage table keeps
+// the long-term record.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Agent metrics. Label values are passed in the order the labels are listed.
+var (
+	SyncDuration = NewHistogram("focus_agent_sync_duration_seconds",
+		"Time taken by each sync run", []float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}, "service")
+	Syncs = NewCounter("focus_agent_syncs_total",
+		"Sync runs by outcome (ok or error)", "service", "result")
+
+	LLMCalls = NewCounter("focus_agent_llm_calls_total",
+		"LLM calls by outcome (ok or error)", "provider", "result")
+	LLMTokens = NewCounter("focus_agent_llm_tokens_total",
+		"Tokens read and written by LLM calls", "provider", "direction")
+	LLMCost = NewCounter("focus_agent_llm_cost_usd_total",
+		"Estimated cost of LLM calls in USD", "provider")
+	LLMDuration = NewHistogram("focus_agent_llm_call_duration_seconds",
+		"Time taken by successful LLM calls", []float64{0.25, 0.5, 1, 2.5, 5, 10, 30, 60}, "provider")
+	CacheLookups = NewCounter("focus_agent_llm_cache_lookups_total",
+		"LLM response cache lookups by result (hit or miss)", "result")
+
+	TasksExtracted = NewCounter("focus_agent_tasks_extracted_total",
+		"Tasks extracted from synced sources", "source")
+	GoogleAPICalls = NewCounter("focus_agent_google_api_calls_total",
+		"Requests sent to Google APIs", "service")
+	QuotaErrors = NewCounter("focus_agent_quota_errors_total",
+		"Failures caused by a rate limit or exhausted quota", "service")
+
+	JobLateness = NewHistogram("focus_agent_job_lateness_seconds",
+		"How long after its scheduled time a scheduler job started", []float64{0.1, 1, 5, 30, 60, 300, 900, 3600}, "job")
+)
+
+var (
+	registryMu sync.Mutex
+	registry   []metric
+)
+
+// metric is anything that can write itself in the text format
+type metric interface {
+	metricName() string
+	write(w io.Writer)
+}
+
+func register(m metric) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, m)
+}
+
+// WriteText writes every metric in the Prometheus text exposition format
+func WriteText(w io.Writer) {
+	registryMu.Lock()
+	metrics := append([]metric(nil), registry...)
+	registryMu.Unlock()
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].metricName() < metrics[j].metricName() })
+	for _, m := range metrics {
+		m.write(w)
+	}
+}
+
+// ContentType is the media type of WriteText's output
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Counter is a value per label set that only goes up
+type Counter struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]float64 // Keyed by rendered label set
+}
+
+// NewCounter creates and registers a counter
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{name: name, help: help, labels: labels, values: make(map[string]float64)}
+	register(c)
+	return c
+}
+
+// Inc adds one to the counter for the given label values
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds a non-negative amount to the counter for the given label values
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	key := renderLabels(c.labels, labelValues, "", "")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] += v
+}
+
+// Value returns the counter for the given label values
+func (c *Counter) Value(labelValues ...string) float64 {
+	key := renderLabels(c.labels, labelValues, "", "")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[key]
+}
+
+func (c *Counter) metricName() string { return c.name }
+
+func (c *Counter) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, key, formatValue(c.values[key]))
+	}
+}
+
+// Histogram counts observations into cumulative buckets per label set
+type Histogram struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64 // Upper bounds, ascending; +Inf is implied
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries // Keyed by label values joined with \xff
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64 // Per bucket, not cumulative
+	count       uint64
+	sum         float64
+}
+
+// NewHistogram creates and registers a histogram with the given bucket upper bounds
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{name: name, help: help, labels: labels, buckets: buckets, series: make(map[string]*histogramSeries)}
+	register(h)
+	return h
+}
+
+// Observe records a value for the given label values
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{labelValues: labelValues, counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	for i, bound := range h.buckets {
+		if v <= bound {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+func (h *Histogram) metricName() string { return h.name }
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, renderLabels(h.labels, s.labelValues, "le", formatValue(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, renderLabels(h.labels, s.labelValues, "le", "+Inf"), s.count)
+
+		labels := renderLabels(h.labels, s.labelValues, "", "")
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, labels, formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, labels, s.count)
+	}
+}
+
+// renderLabels formats label pairs as {a="1",b="2"}, with an optional extra pair such as a
+// histogram's le. Missing values are empty.
+func renderLabels(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+
+	var pairs []string
+	for i, name := range names {
+		var value string
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs = append(pairs, name+"="+strconv.Quote(value))
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+"="+strconv.Quote(extraValue))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	counter := &Counter{name: "test_calls_total", help: "Calls", labels: []string{"provider", "result"}, values: make(map[string]float64)}
+	counter.Inc("gemini", "ok")
+	counter.Add(2, "gemini", "ok")
+	counter.Inc("ollama", "error")
+
+	histogram := &Histogram{name: "test_seconds", help: "Durations", labels: []string{"service"},
+		buckets: []float64{1, 5}, series: make(map[string]*histogramSeries)}
+	histogram.Observe(0.5, "gmail")
+	histogram.Observe(3, "gmail")
+	histogram.Observe(10, "gmail")
+
+	var buf bytes.Buffer
+	counter.write(&buf)
+	histogram.write(&buf)
+	got := buf.String()
+
+	for _, want := range []string{
+		"# TYPE test_calls_total counter\n",
+		`test_calls_total{provider="gemini",result="ok"} 3` + "\n",
+		`test_calls_total{provider="ollama",result="error"} 1` + "\n",
+		"# TYPE test_seconds histogram\n",
+		`test_seconds_bucket{service="gmail",le="1"} 1` + "\n",
+		`test_seconds_bucket{service="gmail",le="5"} 2` + "\n",
+		`test_seconds_bucket{service="gmail",le="+Inf"} 3` + "\n",
+		`test_seconds_sum{service="gmail"} 13.5` + "\n",
+		`test_seconds_count{service="gmail"} 3` + "\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// getToken loads the saved token, refreshing it if needed, or runs the browser OAuth flow
+func getToken(ctx context.Context, config *oauth2.Config, tokenFile string) (*oauth2.Token, error) {
+	// Expand home directory
+	if strings.HasPrefix(tokenFile, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		tokenFile = filepath.Join(home, tokenFile[2:])
+	}
+
+	if token, err := tokenFromFile(tokenFile); err == nil {
+		newToken, err := config.TokenSource(ctx, token).Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to refresh token (delete %s to sign in again): %w", tokenFile, err)
+		}
+		if newToken.AccessToken != token.AccessToken {
+			// Microsoft rotates refresh tokens, so persist every refresh
+			if err := saveToken(tokenFile, newToken); err != nil {
+				log.Printf("Warning: failed to save refreshed Microsoft token: %v", err)
+			}
+		}
+		return newToken, nil
+	}
+
+	log.Printf("Starting Microsoft OAuth flow...")
+	token, err := getTokenFromWeb(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token from web: %w", err)
+	}
+
+	if err := saveToken(tokenFile, token); err != nil {
+		return nil, fmt.Errorf("failed to save token: %w", err)
+	}
+	log.Printf("Microsoft token saved to %s", tokenFile)
+
+	return token, nil
+}
+
+// tokenFromFile retrieves a token from a local file
+func tokenFromFile(file string) (*oauth2.Token, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	token := &oauth2.Token{}
+	err = json.NewDecoder(f).Decode(token)
+	return token, err
+}
+
+// saveToken saves a token to a file path
+func saveToken(path string, token *oauth2.Token) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(token)
+}
+
+// getTokenFromWeb runs the authorization code flow with a local callback server on the redirect URL
+func getTokenFromWeb(config *oauth2.Config) (*oauth2.Token, error) {
+	redirect, err := url.Parse(config.RedirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirect URL: %w", err)
+	}
+
+	codeCh := make(chan string, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if errMsg := r.URL.Query().Get("error_description"); errMsg != "" {
+			log.Printf("Microsoft sign-in failed: %s", errMsg)
+			fmt.Fprintf(w, "Error: %s", errMsg)
+			return
+		}
+		code := r.URL.Query().Get("code")
+		if code == "" {
+			fmt.Fprintf(w, "Error: No authorization code received")
+			return
+		}
+		select {
+		case codeCh <- code:
+		default:
+		}
+		fmt.Fprintf(w, "<html><body><h1>Authorization Successful!</h1><p>You can close this window and return to the terminal.</p></body></html>")
+	})
+
+	server := &http.Server{Addr: redirect.Host, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("Server error: %v", err)
+		}
+	}()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	authURL := config.AuthCodeURL("state-token")
+	fmt.Printf("Go to the following link in your browser to connect Microsoft 365:\n%v\n\n", authURL)
+
+	code := <-codeCh
+
+	token, err := config.Exchange(context.Background(), code)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve token from web: %w", err)
+	}
+	return token, nil
+}
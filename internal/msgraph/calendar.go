@@ -0,0 +1,231 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// CalendarClient syncs the Outlook calendar into the events table
+type CalendarClient struct {
+	api    *apiClient
+	Config *config.Config
+}
+
+// CalendarSyncState stores the calendar view delta link and the window it covers.
+// A calendar view delta only tracks its original window, so it is rebuilt as the window runs out.
+type CalendarSyncState struct {
+	DeltaLink string    `json:"delta_link"`
+	WindowEnd time.Time `json:"window_end"`
+}
+
+// graphEvent is the subset of a Graph event that is synced
+type graphEvent struct {
+	ID          string `json:"id"`
+	Subject     string `json:"subject"`
+	BodyPreview string `json:"bodyPreview"`
+	Start       struct {
+		DateTime string `json:"dateTime"`
+	} `json:"start"`
+	End struct {
+		DateTime string `json:"dateTime"`
+	} `json:"end"`
+	Location struct {
+		DisplayName string `json:"displayName"`
+	} `json:"location"`
+	Attendees []emailAddress `json:"attendees"`
+	Organizer emailAddress   `json:"organizer"`
+	// The user's RSVP: none, organizer, tentativelyAccepted, accepted, declined or notResponded
+	ResponseStatus struct {
+		Response string `json:"response"`
+	} `json:"responseStatus"`
+	OnlineMeeting *struct {
+		JoinURL string `json:"joinUrl"`
+	} `json:"onlineMeeting"`
+	WebLink     string `json:"webLink"`
+	IsCancelled bool   `json:"isCancelled"`
+	Removed     *struct {
+		Reason string `json:"reason"` // "deleted", or "changed" when it left the sync window
+	} `json:"@removed"`
+}
+
+// eventPage is one page of a calendar view delta query
+type eventPage struct {
+	Value     []graphEvent `json:"value"`
+	NextLink  string       `json:"@odata.nextLink"`
+	DeltaLink string       `json:"@odata.deltaLink"`
+}
+
+// graphTimeLayout is the dateTime format Graph returns (UTC, given the outlook.timezone preference)
+const graphTimeLayout = "2006-01-02T15:04:05.0000000"
+
+// SyncEvents performs incremental sync of calendar events
+func (c *CalendarClient) SyncEvents(ctx context.Context, database *db.DB) error {
+	syncState, err := database.GetSyncState("msgraph_calendar")
+	if err != nil {
+		return fmt.Errorf("failed to get sync state: %w", err)
+	}
+
+	var state CalendarSyncState
+	if syncState.State != "" && syncState.State != "{}" {
+		if err := json.Unmarshal([]byte(syncState.State), &state); err != nil {
+			log.Printf("Warning: invalid sync state, doing full sync: %v", err)
+			state = CalendarSyncState{}
+		}
+	}
+
+	daysAhead := c.Config.Limits.CalendarDaysAhead
+	if daysAhead == 0 {
+		daysAhead = 30 // Default to 30 days
+	}
+
+	// Rebuild the window once less than half of it is left
+	if state.DeltaLink != "" && time.Until(state.WindowEnd) < time.Duration(daysAhead)*24*time.Hour/2 {
+		state.DeltaLink = ""
+	}
+
+	if state.DeltaLink != "" {
+		count, deltaLink, err := c.followDelta(ctx, database, state.DeltaLink)
+		if err != nil {
+			log.Printf("Incremental Outlook calendar sync failed, falling back to full sync: %v", err)
+			state.DeltaLink = ""
+		} else {
+			state.DeltaLink = deltaLink
+			log.Printf("Outlook calendar incremental sync completed: %d changes processed", count)
+		}
+	}
+
+	if state.DeltaLink == "" {
+		now := time.Now()
+		timeMin := now.AddDate(0, 0, -7) // Past week for context
+		timeMax := now.AddDate(0, 0, daysAhead)
+
+		log.Printf("Syncing Outlook calendar events from %s to %s (%d days ahead)",
+			timeMin.Format("2006-01-02"), timeMax.Format("2006-01-02"), daysAhead)
+
+		query := url.Values{}
+		query.Set("startDateTime", timeMin.UTC().Format(time.RFC3339))
+		query.Set("endDateTime", timeMax.UTC().Format(time.RFC3339))
+
+		count, deltaLink, err := c.followDelta(ctx, database, "/me/calendarView/delta?"+query.Encode())
+		if err != nil {
+			return fmt.Errorf("full sync failed: %w", err)
+		}
+		state.DeltaLink = deltaLink
+		state.WindowEnd = timeMax
+		log.Printf("Outlook calendar full sync completed: %d events synced", count)
+	}
+
+	// Save sync state
+	stateJSON, _ := json.Marshal(state)
+	syncState.State = string(stateJSON)
+	syncState.LastSync = time.Now()
+	syncState.NextSync = time.Now().Add(time.Duration(c.Config.MSGraph.PollingMinutes.Calendar) * time.Minute)
+
+	if err := database.SaveSyncState(syncState); err != nil {
+		return fmt.Errorf("failed to save sync state: %w", err)
+	}
+
+	return nil
+}
+
+// followDelta pages through a calendar view delta query and returns the link for the next sync
+func (c *CalendarClient) followDelta(ctx context.Context, database *db.DB, link string) (int, string, error) {
+	count := 0
+
+	for link != "" {
+		var page eventPage
+		if err := c.api.get(ctx, link, &page); err != nil {
+			return count, "", err
+		}
+
+		for _, event := range page.Value {
+			if event.Removed != nil {
+				// Deleted events only carry an ID, so mark the stored copy cancelled. Events that
+				// just moved out of the sync window are left alone.
+				log.Printf("Event removed: %s", event.ID)
+				if event.Removed.Reason != "changed" {
+					if err := database.CancelEvent(event.ID); err != nil {
+						log.Printf("Failed to cancel Outlook event %s: %v", event.ID, err)
+					}
+				}
+				continue
+			}
+			if err := c.processEvent(database, &event); err != nil {
+				log.Printf("Failed to process Outlook event %s: %v", event.ID, err)
+				continue
+			}
+			count++
+		}
+
+		if page.DeltaLink != "" {
+			return count, page.DeltaLink, nil
+		}
+		link = page.NextLink
+	}
+
+	return count, "", fmt.Errorf("delta query ended without a delta link")
+}
+
+// processEvent saves a Graph event in the Google Calendar event shape
+// graphResponses maps Graph RSVPs to Google's, which the planner reads
+var graphResponses = map[string]string{
+	"organizer":           "accepted",
+	"accepted":            "accepted",
+	"tentativelyAccepted": "tentative",
+	"declined":            "declined",
+	"notResponded":        "needsAction",
+}
+
+func (c *CalendarClient) processEvent(database *db.DB, event *graphEvent) error {
+	startTime, err := time.ParseInLocation(graphTimeLayout, event.Start.DateTime, time.UTC)
+	if err != nil {
+		return fmt.Errorf("failed to parse start time: %w", err)
+	}
+
+	endTime, err := time.ParseInLocation(graphTimeLayout, event.End.DateTime, time.UTC)
+	if err != nil {
+		return fmt.Errorf("failed to parse end time: %w", err)
+	}
+
+	var attendees []string
+	for _, attendee := range event.Attendees {
+		attendees = append(attendees, attendee.EmailAddress.Address)
+	}
+
+	var meetingLink string
+	if event.OnlineMeeting != nil {
+		meetingLink = event.OnlineMeeting.JoinURL
+	}
+
+	// Google statuses are used so cancelled events are filtered the same way
+	status := "confirmed"
+	if event.IsCancelled {
+		status = "cancelled"
+	}
+
+	eventRecord := &db.Event{
+		ID:          event.ID,
+		Title:       event.Subject,
+		StartTS:     startTime,
+		EndTS:       endTime,
+		Location:    event.Location.DisplayName,
+		Description: event.BodyPreview,
+		Attendees:   attendees,
+		MeetingLink: meetingLink,
+		Status:      status,
+		Organizer:   event.Organizer.EmailAddress.Address,
+		Response:    graphResponses[event.ResponseStatus.Response],
+	}
+
+	if err := database.SaveEvent(eventRecord); err != nil {
+		return fmt.Errorf("failed to save event: %w", err)
+	}
+	return nil
+}
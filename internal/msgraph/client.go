@@ -0,0 +1,120 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/microsoft"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+)
+
+// graphBaseURL is the Microsoft Graph v1.0 endpoint
+const graphBaseURL = "https://graph.microsoft.com/v1.0"
+
+// Client holds the Microsoft Graph mail and calendar clients
+type Client struct {
+	Mail     *MailClient
+	Calendar *CalendarClient
+}
+
+// NewClient authenticates with Microsoft Graph and creates the mail and calendar clients
+func NewClient(ctx context.Context, cfg *config.Config) (*Client, error) {
+	oauth2Config := &oauth2.Config{
+		ClientID:     cfg.MSGraph.ClientID,
+		ClientSecret: cfg.MSGraph.ClientSecret,
+		RedirectURL:  cfg.MSGraph.RedirectURL,
+		Scopes:       cfg.MSGraph.Scopes,
+		Endpoint:     microsoft.AzureADEndpoint(cfg.MSGraph.TenantID),
+	}
+
+	token, err := getToken(ctx, oauth2Config, cfg.MSGraph.TokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Microsoft token: %w", err)
+	}
+
+	api := &apiClient{httpClient: oauth2Config.Client(ctx, token)}
+
+	return &Client{
+		Mail:     &MailClient{api: api, Config: cfg},
+		Calendar: &CalendarClient{api: api, Config: cfg},
+	}, nil
+}
+
+// apiClient makes authenticated Graph requests
+type apiClient struct {
+	httpClient *http.Client
+}
+
+// graphError is the error body returned by Graph
+type graphError struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// errSyncStateInvalid means a delta link has expired and a full sync is needed
+var errSyncStateInvalid = fmt.Errorf("delta sync state is no longer valid")
+
+// get fetches a Graph URL (relative to the API root, or an absolute next/delta link) into out
+func (a *apiClient) get(ctx context.Context, url string, out interface{}) error {
+	if !strings.HasPrefix(url, "https://") {
+		url = graphBaseURL + url
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	// Plain-text bodies and UTC times match what the Gmail and Calendar sync store
+	req.Header.Add("Prefer", `outlook.body-content-type="text"`)
+	req.Header.Add("Prefer", `outlook.timezone="UTC"`)
+	req.Header.Add("Prefer", "odata.maxpagesize=50")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		var gErr graphError
+		json.Unmarshal(body, &gErr)
+
+		if resp.StatusCode == http.StatusGone || gErr.Error.Code == "syncStateNotFound" || gErr.Error.Code == "resyncRequired" {
+			return errSyncStateInvalid
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return fmt.Errorf("graph API throttled, retry after %s seconds", resp.Header.Get("Retry-After"))
+		}
+		if gErr.Error.Message != "" {
+			return fmt.Errorf("graph API error (%d %s): %s", resp.StatusCode, gErr.Error.Code, gErr.Error.Message)
+		}
+		return fmt.Errorf("graph API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// emailAddress is a Graph recipient
+type emailAddress struct {
+	EmailAddress struct {
+		Name    string `json:"name"`
+		Address string `json:"address"`
+	} `json:"emailAddress"`
+}
+
+// String formats the address like an email header: "Name <address>"
+func (e emailAddress) String() string {
+	if e.EmailAddress.Name == "" || e.EmailAddress.Name == e.EmailAddress.Address {
+		return e.EmailAddress.Address
+	}
+	return fmt.Sprintf("%s <%s>", e.EmailAddress.Name, e.EmailAddress.Address)
+}
@@ -0,0 +1,238 @@
+package msgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// MailClient syncs Outlook mail into the messages and threads tables
+type MailClient struct {
+	api    *apiClient
+	Config *config.Config
+}
+
+// MailSyncState stores the delta links for each synced folder
+type MailSyncState struct {
+	DeltaLinks map[string]string `json:"delta_links"` // Folder -> @odata.deltaLink
+}
+
+// mailFolders are the well-known folders synced, with the Gmail-style label their messages get
+// so the rest of the pipeline (e.g. INBOX checks during processing) treats them the same
+var mailFolders = []struct {
+	Name  string
+	Label string
+}{
+	{"inbox", "INBOX"},
+	{"sentitems", "SENT"},
+}
+
+// graphMessage is the subset of a Graph message that is synced
+type graphMessage struct {
+	ID             string `json:"id"`
+	ConversationID string `json:"conversationId"`
+	Subject        string `json:"subject"`
+	BodyPreview    string `json:"bodyPreview"`
+	Body           struct {
+		Content string `json:"content"`
+	} `json:"body"`
+	From             *emailAddress  `json:"from"`
+	ToRecipients     []emailAddress `json:"toRecipients"`
+	ReceivedDateTime time.Time      `json:"receivedDateTime"`
+	Categories       []string       `json:"categories"`
+	Importance       string         `json:"importance"`
+	Flag             struct {
+		FlagStatus string `json:"flagStatus"`
+	} `json:"flag"`
+	Removed *struct{} `json:"@removed"`
+}
+
+// messagePage is one page of a messages delta query
+type messagePage struct {
+	Value     []graphMessage `json:"value"`
+	NextLink  string         `json:"@odata.nextLink"`
+	DeltaLink string         `json:"@odata.deltaLink"`
+}
+
+const messageSelect = "id,conversationId,subject,bodyPreview,body,from,toRecipients,receivedDateTime,categories,importance,flag"
+
+// SyncMail performs incremental sync of the inbox and sent items
+func (m *MailClient) SyncMail(ctx context.Context, database *db.DB) error {
+	syncState, err := database.GetSyncState("msgraph_mail")
+	if err != nil {
+		return fmt.Errorf("failed to get sync state: %w", err)
+	}
+
+	var state MailSyncState
+	if syncState.State != "" && syncState.State != "{}" {
+		if err := json.Unmarshal([]byte(syncState.State), &state); err != nil {
+			log.Printf("Warning: invalid sync state, doing full sync: %v", err)
+			state = MailSyncState{}
+		}
+	}
+	if state.DeltaLinks == nil {
+		state.DeltaLinks = make(map[string]string)
+	}
+
+	messageCount := 0
+	for _, folder := range mailFolders {
+		count, err := m.syncFolder(ctx, database, folder.Name, folder.Label, &state)
+		if err != nil {
+			return fmt.Errorf("failed to sync %s: %w", folder.Name, err)
+		}
+		messageCount += count
+	}
+	log.Printf("Outlook mail sync completed: %d messages processed", messageCount)
+
+	// Save sync state
+	stateJSON, _ := json.Marshal(state)
+	syncState.State = string(stateJSON)
+	syncState.LastSync = time.Now()
+	syncState.NextSync = time.Now().Add(time.Duration(m.Config.MSGraph.PollingMinutes.Mail) * time.Minute)
+
+	if err := database.SaveSyncState(syncState); err != nil {
+		return fmt.Errorf("failed to save sync state: %w", err)
+	}
+
+	return nil
+}
+
+// syncFolder follows a folder's delta query to the end, falling back to a full sync if the
+// saved delta link has expired
+func (m *MailClient) syncFolder(ctx context.Context, database *db.DB, folder, label string, state *MailSyncState) (int, error) {
+	link := state.DeltaLinks[folder]
+	if link != "" {
+		count, deltaLink, err := m.followDelta(ctx, database, link, label)
+		if err == nil {
+			state.DeltaLinks[folder] = deltaLink
+			return count, nil
+		}
+		log.Printf("Incremental Outlook sync of %s failed, falling back to full sync: %v", folder, err)
+	}
+
+	log.Printf("Starting Outlook %s full sync...", folder)
+
+	query := url.Values{}
+	query.Set("$select", messageSelect)
+	days := m.Config.Limits.DaysOfHistory
+	if days <= 0 {
+		days = 7
+	}
+	since := time.Now().AddDate(0, 0, -days).UTC().Format(time.RFC3339)
+	query.Set("$filter", "receivedDateTime ge "+since)
+
+	initial := fmt.Sprintf("/me/mailFolders/%s/messages/delta?%s", folder, query.Encode())
+	count, deltaLink, err := m.followDelta(ctx, database, initial, label)
+	if err != nil {
+		return count, err
+	}
+	state.DeltaLinks[folder] = deltaLink
+	return count, nil
+}
+
+// followDelta pages through a delta query, saving messages and their threads, and returns the
+// delta link for the next sync
+func (m *MailClient) followDelta(ctx context.Context, database *db.DB, link, label string) (int, string, error) {
+	count := 0
+	threads := make(map[string]bool)
+
+	for link != "" {
+		var page messagePage
+		if err := m.api.get(ctx, link, &page); err != nil {
+			return count, "", err
+		}
+
+		for _, msg := range page.Value {
+			if msg.Removed != nil || msg.ConversationID == "" {
+				continue
+			}
+			if err := m.processMessage(database, &msg, label); err != nil {
+				log.Printf("Failed to process Outlook message %s: %v", msg.ID, err)
+				continue
+			}
+			threads[msg.ConversationID] = true
+			count++
+		}
+
+		if page.DeltaLink != "" {
+			if err := saveThreads(database, threads); err != nil {
+				return count, "", err
+			}
+			return count, page.DeltaLink, nil
+		}
+		link = page.NextLink
+	}
+
+	return count, "", fmt.Errorf("delta query ended without a delta link")
+}
+
+// processMessage stores a Graph message in the Gmail message shape
+func (m *MailClient) processMessage(database *db.DB, msg *graphMessage, label string) error {
+	var from string
+	if msg.From != nil {
+		from = msg.From.String()
+	}
+
+	var to []string
+	for _, recipient := range msg.ToRecipients {
+		to = append(to, recipient.String())
+	}
+
+	labels := []string{label}
+	if msg.Importance == "high" {
+		labels = append(labels, "IMPORTANT")
+	}
+	if msg.Flag.FlagStatus == "flagged" {
+		labels = append(labels, "STARRED")
+	}
+	labels = append(labels, msg.Categories...)
+
+	// Mirror Gmail sync: confidential/sensitive categories mark the message sensitive
+	sensitivity := "low"
+	for _, category := range msg.Categories {
+		lower := strings.ToLower(category)
+		if strings.Contains(lower, "confidential") || strings.Contains(lower, "sensitive") {
+			sensitivity = "high"
+			break
+		}
+	}
+
+	message := &db.Message{
+		ID:          msg.ID,
+		ThreadID:    msg.ConversationID,
+		From:        from,
+		To:          strings.Join(to, ", "),
+		Subject:     msg.Subject,
+		Snippet:     msg.BodyPreview,
+		Body:        msg.Body.Content,
+		Timestamp:   msg.ReceivedDateTime,
+		Labels:      labels,
+		Sensitivity: sensitivity,
+	}
+
+	if err := database.SaveMessage(message); err != nil {
+		return fmt.Errorf("failed to save message: %w", err)
+	}
+	return nil
+}
+
+// saveThreads records the conversations touched by a sync so they are queued for processing
+func saveThreads(database *db.DB, threads map[string]bool) error {
+	for threadID := range threads {
+		thread := &db.Thread{
+			ID:         threadID,
+			LastSynced: time.Now(),
+		}
+		if err := database.SaveThread(thread); err != nil {
+			return fmt.Errorf("failed to save thread: %w", err)
+		}
+	}
+	return nil
+}
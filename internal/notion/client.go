@@ -0,0 +1,178 @@
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+)
+
+const (
+	defaultBaseURL = "https://api.notion.com/v1"
+	apiVersion     = "2022-06-28"
+
+	// maxTextChars is Notion's limit on a single rich text item
+	maxTextChars = 2000
+)
+
+// Client writes pages to Notion databases through the Notion API
+type Client struct {
+	config     config.Notion
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a Notion client, or returns nil when the export is disabled
+func NewClient(cfg *config.Config) *Client {
+	if !cfg.Notion.Enabled {
+		return nil
+	}
+
+	return &Client{
+		config:  cfg.Notion,
+		baseURL: defaultBaseURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// page is the part of a Notion page the exporter reads back
+type page struct {
+	ID         string                     `json:"id"`
+	Properties map[string]json.RawMessage `json:"properties"`
+}
+
+// queryDatabase returns every page in a database matching a filter (nil for all pages)
+func (c *Client) queryDatabase(ctx context.Context, databaseID string, filter interface{}) ([]page, error) {
+	var pages []page
+	cursor := ""
+	for {
+		body := map[string]interface{}{"page_size": 100}
+		if filter != nil {
+			body["filter"] = filter
+		}
+		if cursor != "" {
+			body["start_cursor"] = cursor
+		}
+
+		var result struct {
+			Results    []page `json:"results"`
+			HasMore    bool   `json:"has_more"`
+			NextCursor string `json:"next_cursor"`
+		}
+		if err := c.do(ctx, http.MethodPost, "/databases/"+databaseID+"/query", body, &result); err != nil {
+			return nil, err
+		}
+		pages = append(pages, result.Results...)
+
+		if !result.HasMore || result.NextCursor == "" {
+			return pages, nil
+		}
+		cursor = result.NextCursor
+	}
+}
+
+// createPage adds a page to a database
+func (c *Client) createPage(ctx context.Context, databaseID string, properties map[string]interface{}, children []interface{}) error {
+	body := map[string]interface{}{
+		"parent":     map[string]string{"database_id": databaseID},
+		"properties": properties,
+	}
+	if len(children) > 0 {
+		body["children"] = children
+	}
+	return c.do(ctx, http.MethodPost, "/pages", body, nil)
+}
+
+// updatePage sets a page's properties
+func (c *Client) updatePage(ctx context.Context, pageID string, properties map[string]interface{}) error {
+	return c.do(ctx, http.MethodPatch, "/pages/"+pageID, map[string]interface{}{"properties": properties}, nil)
+}
+
+// archivePage removes a page from its database (Notion keeps it in the trash)
+func (c *Client) archivePage(ctx context.Context, pageID string) error {
+	return c.do(ctx, http.MethodPatch, "/pages/"+pageID, map[string]interface{}{"archived": true}, nil)
+}
+
+// do sends a request to the Notion API and decodes the response into out (unless nil)
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode Notion request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	req.Header.Set("Notion-Version", apiVersion)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Notion request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("Notion API error %d (%s): %s", resp.StatusCode, apiErr.Code, apiErr.Message)
+		}
+		return fmt.Errorf("Notion API error %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode Notion response: %w", err)
+	}
+	return nil
+}
+
+// richText builds a rich text value, truncated to Notion's limit
+func richText(text string) []interface{} {
+	if text == "" {
+		return []interface{}{}
+	}
+	if len(text) > maxTextChars {
+		text = text[:maxTextChars-3] + "..."
+	}
+	return []interface{}{
+		map[string]interface{}{"type": "text", "text": map[string]string{"content": text}},
+	}
+}
+
+// plainText reads the text of a title or rich text property
+func plainText(raw json.RawMessage) string {
+	var prop struct {
+		Title []struct {
+			PlainText string `json:"plain_text"`
+		} `json:"title"`
+		RichText []struct {
+			PlainText string `json:"plain_text"`
+		} `json:"rich_text"`
+	}
+	if err := json.Unmarshal(raw, &prop); err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, item := range append(prop.Title, prop.RichText...) {
+		b.WriteString(item.PlainText)
+	}
+	return b.String()
+}
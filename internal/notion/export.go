@@ -0,0 +1,160 @@
+package notion
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// Property names the exported databases need
+const (
+	propName        = "Name"        // Title, in both databases
+	propTaskID      = "Task ID"     // Text; matches pages to tasks between exports
+	propRank        = "Rank"        // Number
+	propScore       = "Score"       // Number
+	propDue         = "Due"         // Date
+	propProject     = "Project"     // Text
+	propStakeholder = "Stakeholder" // Text
+	propDate        = "Date"        // Date, in the briefs database
+)
+
+// SyncTasks mirrors the given tasks, highest priority first, into the tasks database. Pages are
+// matched to tasks by Task ID and updated in place; pages for tasks no longer in the list are
+// archived.
+func (c *Client) SyncTasks(ctx context.Context, tasks []*db.Task) error {
+	if c.config.TasksDatabaseID == "" {
+		return nil
+	}
+
+	pages, err := c.queryDatabase(ctx, c.config.TasksDatabaseID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list Notion task pages: %w", err)
+	}
+
+	existing := make(map[string]string) // Task ID -> page ID
+	for _, p := range pages {
+		if taskID := plainText(p.Properties[propTaskID]); taskID != "" {
+			existing[taskID] = p.ID
+		}
+	}
+
+	created, updated, archived := 0, 0, 0
+	for i, task := range tasks {
+		properties := taskProperties(task, i+1)
+
+		if pageID, ok := existing[task.ID]; ok {
+			delete(existing, task.ID)
+			if err := c.updatePage(ctx, pageID, properties); err != nil {
+				return fmt.Errorf("failed to update Notion page for task %s: %w", task.ID, err)
+			}
+			updated++
+			continue
+		}
+
+		if err := c.createPage(ctx, c.config.TasksDatabaseID, properties, nil); err != nil {
+			return fmt.Errorf("failed to create Notion page for task %s: %w", task.ID, err)
+		}
+		created++
+	}
+
+	// Tasks that were completed or fell out of the top list
+	for taskID, pageID := range existing {
+		if err := c.archivePage(ctx, pageID); err != nil {
+			return fmt.Errorf("failed to archive Notion page for task %s: %w", taskID, err)
+		}
+		archived++
+	}
+
+	log.Printf("Notion tasks export: %d created, %d updated, %d archived", created, updated, archived)
+	return nil
+}
+
+// taskProperties builds the tasks database properties for a task at a rank
+func taskProperties(task *db.Task, rank int) map[string]interface{} {
+	properties := map[string]interface{}{
+		propName:        map[string]interface{}{"title": richText(task.Title)},
+		propTaskID:      map[string]interface{}{"rich_text": richText(task.ID)},
+		propRank:        map[string]interface{}{"number": rank},
+		propScore:       map[string]interface{}{"number": task.Score},
+		propProject:     map[string]interface{}{"rich_text": richText(task.Project)},
+		propStakeholder: map[string]interface{}{"rich_text": richText(task.Stakeholder)},
+		propDue:         map[string]interface{}{"date": nil},
+	}
+	if task.DueTS != nil {
+		properties[propDue] = map[string]interface{}{"date": map[string]string{"start": task.DueTS.Format(time.RFC3339)}}
+	}
+	return properties
+}
+
+// ExportDailyBrief adds the day's brief to the briefs database as a page listing its tasks and
+// meetings, replacing any page already exported for the day
+func (c *Client) ExportDailyBrief(ctx context.Context, day time.Time, tasks []*db.Task, events []*db.Event) error {
+	if c.config.BriefsDatabaseID == "" {
+		return nil
+	}
+
+	date := day.Format("2006-01-02")
+	filter := map[string]interface{}{
+		"property": propDate,
+		"date":     map[string]string{"equals": date},
+	}
+	pages, err := c.queryDatabase(ctx, c.config.BriefsDatabaseID, filter)
+	if err != nil {
+		return fmt.Errorf("failed to find earlier Notion brief: %w", err)
+	}
+	for _, p := range pages {
+		if err := c.archivePage(ctx, p.ID); err != nil {
+			return fmt.Errorf("failed to replace earlier Notion brief: %w", err)
+		}
+	}
+
+	properties := map[string]interface{}{
+		propName: map[string]interface{}{"title": richText("Daily brief - " + day.Format("Monday, January 2"))},
+		propDate: map[string]interface{}{"date": map[string]string{"start": date}},
+	}
+	if err := c.createPage(ctx, c.config.BriefsDatabaseID, properties, briefBlocks(day, tasks, events)); err != nil {
+		return fmt.Errorf("failed to create Notion brief: %w", err)
+	}
+	return nil
+}
+
+// briefBlocks lays out a brief's top tasks and the day's meetings as page content
+func briefBlocks(day time.Time, tasks []*db.Task, events []*db.Event) []interface{} {
+	blocks := []interface{}{block("heading_2", "🎯 Top priorities")}
+	if len(tasks) == 0 {
+		blocks = append(blocks, block("paragraph", "No open tasks."))
+	}
+	for _, task := range tasks {
+		text := fmt.Sprintf("%s (score %.0f)", task.Title, task.Score)
+		if task.DueTS != nil {
+			text += " - due " + task.DueTS.Format("Mon Jan 2")
+		}
+		blocks = append(blocks, block("numbered_list_item", text))
+	}
+
+	var meetings []interface{}
+	for _, event := range events {
+		if event.StartTS.YearDay() != day.YearDay() || event.StartTS.Year() != day.Year() {
+			continue
+		}
+		meetings = append(meetings, block("bulleted_list_item", fmt.Sprintf("%s - %s: %s",
+			event.StartTS.Format("3:04 PM"), event.EndTS.Format("3:04 PM"), event.Title)))
+	}
+	if len(meetings) > 0 {
+		blocks = append(blocks, block("heading_2", "📅 Meetings"))
+		blocks = append(blocks, meetings...)
+	}
+	return blocks
+}
+
+// block builds a text block of a type, e.g. paragraph or bulleted_list_item
+func block(blockType, text string) map[string]interface{} {
+	return map[string]interface{}{
+		"object":  "block",
+		"type":    blockType,
+		blockType: map[string]interface{}{"rich_text": richText(text)},
+	}
+}
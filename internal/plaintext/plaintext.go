@@ -0,0 +1,71 @@
+// Package plaintext renders notification text without emoji, for screen readers and clients
+// that display them badly (notifications.plain_formatting)
+package plaintext
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// indicators are emoji that carry meaning on their own, so they're replaced with words rather
+// than dropped. Each is listed as Unicode and as a Slack emoji code.
+var indicators = []struct{ emoji, code, words string }{
+	{"🔴", ":red_circle:", "High priority:"},
+	{"🟡", ":large_yellow_circle:", "Medium priority:"},
+	{"🟢", ":large_green_circle:", "Low priority:"},
+}
+
+// slackEmoji matches a Slack emoji code and the space after it
+var slackEmoji = regexp.MustCompile(`:[a-z0-9_+-]+: ?`)
+
+// keep are symbols that aren't emoji even though Unicode files them with them
+var keep = map[rune]bool{'©': true, '®': true, '™': true, '°': true}
+
+// Text makes notification text read well as plain text. Priority indicators become words, other
+// emoji are dropped, leaving the section labels that followed them, and bullets and separators
+// become dashes.
+func Text(text string) string {
+	for _, indicator := range indicators {
+		text = strings.ReplaceAll(text, indicator.emoji, indicator.words)
+		text = strings.ReplaceAll(text, indicator.code, indicator.words)
+	}
+	text = slackEmoji.ReplaceAllString(text, "")
+	text = strings.ReplaceAll(text, "• ", "- ")
+
+	var b strings.Builder
+	dropped := false
+	for _, r := range text {
+		if isEmoji(r) {
+			dropped = true
+			continue
+		}
+		// Drop the space after an emoji too when it would double up or start a line
+		if dropped && r == ' ' && (b.Len() == 0 || strings.HasSuffix(b.String(), " ") || strings.HasSuffix(b.String(), "\n")) {
+			dropped = false
+			continue
+		}
+		dropped = false
+		b.WriteRune(r)
+	}
+
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isEmoji reports whether a rune is part of an emoji: a pictographic symbol, a variation
+// selector, a zero-width joiner or a skin tone modifier
+func isEmoji(r rune) bool {
+	switch {
+	case keep[r]:
+		return false
+	case r == '\u200d', r == '\ufe0e', r == '\ufe0f':
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff:
+		return true
+	}
+	return unicode.Is(unicode.So, r)
+}
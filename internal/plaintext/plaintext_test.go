@@ -0,0 +1,50 @@
+package plaintext
+
+import "testing"
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "Chat section heading",
+			text: "📋 *Top Priority Tasks*\n",
+			want: "*Top Priority Tasks*\n",
+		},
+		{
+			name: "Chat priority indicator",
+			text: "\n🔴 Review Q4 budget\nGmail • Due: 3:00 PM\n",
+			want: "\nHigh priority: Review Q4 budget\nGmail - Due: 3:00 PM\n",
+		},
+		{
+			name: "Emoji with variation selector",
+			text: "⚠️ Slipped Past Due (2)",
+			want: "Slipped Past Due (2)",
+		},
+		{
+			name: "Slack heading and priority",
+			text: "\n:warning: *Calendar Changes*\n• Standup moved\n:large_yellow_circle: Send NDA",
+			want: "\n*Calendar Changes*\n- Standup moved\nMedium priority: Send NDA",
+		},
+		{
+			name: "Emoji mid-line",
+			text: "Shipped 🎉 today 🚀",
+			want: "Shipped today",
+		},
+		{
+			name: "Times, links and symbols kept",
+			text: "Due: 3:04 PM <https://mail.google.com/mail/u/0/#inbox/abc|Gmail> © 2026 → 20°C",
+			want: "Due: 3:04 PM <https://mail.google.com/mail/u/0/#inbox/abc|Gmail> © 2026 → 20°C",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(tt.text); got != tt.want {
+				t.Errorf("Text(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
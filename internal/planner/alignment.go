@@ -0,0 +1,60 @@
+package planner
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// alignmentWarmDelay waits for a burst of priority edits to settle before re-evaluating tasks,
+// since the TUI saves after every change
+const alignmentWarmDelay = 30 * time.Second
+
+// WarmAlignment re-evaluates the top planner.alignment_warm_tasks pending tasks against the
+// current priorities in the background, once edits have settled. Alignment results are cached
+// per version of the priorities, so this fills the cache for the new version ahead of the next
+// scheduled rescore.
+func (p *Planner) WarmAlignment() {
+	if p.config.Planner.AlignmentWarmTasks <= 0 {
+		return
+	}
+
+	p.warmMu.Lock()
+	defer p.warmMu.Unlock()
+	if p.warmTimer != nil {
+		p.warmTimer.Stop()
+	}
+	p.warmTimer = time.AfterFunc(alignmentWarmDelay, func() {
+		if err := p.warmAlignment(context.Background()); err != nil {
+			log.Printf("Failed to re-evaluate tasks after priority change: %v", err)
+		}
+	})
+}
+
+func (p *Planner) warmAlignment(ctx context.Context) error {
+	tasks, err := p.db.GetPendingTasks(p.config.Planner.AlignmentWarmTasks)
+	if err != nil {
+		return err
+	}
+
+	updated := 0
+	for _, task := range tasks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := p.PrioritizeTask(ctx, task); err != nil {
+			log.Printf("Failed to re-evaluate task %s: %v", task.ID, err)
+			continue
+		}
+		updated++
+	}
+
+	// Thread priorities follow their tasks' scores
+	if err := p.RecalculateThreadPriorities(ctx); err != nil {
+		log.Printf("Failed to recalculate thread priorities: %v", err)
+	}
+
+	log.Printf("Re-evaluated %d top tasks against updated priorities", updated)
+	p.db.LogUsage("planner", "alignment_warm", 0, 0, 0, nil)
+	return nil
+}
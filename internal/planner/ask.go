@@ -0,0 +1,137 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/embeddings"
+)
+
+const (
+	askMessageLimit = 8
+	askTaskLimit    = 6
+	askKeywordLimit = 8
+)
+
+// askStopWords are dropped from questions before keyword search
+var askStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "was": true, "were": true, "what": true,
+	"when": true, "where": true, "who": true, "why": true, "how": true, "did": true, "does": true,
+	"about": true, "with": true, "from": true, "that": true, "this": true, "have": true, "has": true,
+	"any": true, "ask": true, "asked": true, "tell": true, "said": true, "say": true, "there": true,
+	"which": true, "into": true, "can": true, "you": true, "your": true, "our": true, "their": true,
+}
+
+// Answer is the response to a question about the user's mail and tasks, with the sources used
+type Answer struct {
+	Question string
+	Answer   string
+	Messages []*db.Message
+	Tasks    []*db.Task
+}
+
+// Ask answers a natural-language question by retrieving related messages (full-text search, falling
+// back to keyword matching) and tasks (embedding similarity) and passing them to the LLM as context.
+func (p *Planner) Ask(ctx context.Context, question string) (*Answer, error) {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return nil, fmt.Errorf("question is empty")
+	}
+
+	answer := &Answer{Question: question}
+	keywords := askKeywords(question)
+
+	answer.Messages = p.askMessages(keywords)
+	answer.Tasks = p.askTasks(ctx, question)
+
+	if len(answer.Messages) == 0 && len(answer.Tasks) == 0 {
+		answer.Answer = "I couldn't find any emails or tasks related to that."
+		return answer, nil
+	}
+
+	text, err := p.llm.AnswerQuestion(ctx, question, answer.Messages, answer.Tasks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to answer question: %w", err)
+	}
+	answer.Answer = strings.TrimSpace(text)
+
+	p.db.LogUsage("planner", "ask", 0, 0, 0, nil)
+	return answer, nil
+}
+
+// askMessages finds messages matching the question's keywords
+func (p *Planner) askMessages(keywords []string) []*db.Message {
+	if len(keywords) == 0 {
+		return nil
+	}
+
+	messages, err := p.db.SearchMessages(strings.Join(keywords, " OR "), askMessageLimit)
+	if err == nil && len(messages) > 0 {
+		return messages
+	}
+
+	messages, err = p.db.SearchMessagesByKeywords(keywords, askMessageLimit)
+	if err != nil {
+		log.Printf("Failed to search messages for question: %v", err)
+		return nil
+	}
+	return messages
+}
+
+// askTasks finds the tasks semantically closest to the question
+func (p *Planner) askTasks(ctx context.Context, question string) []*db.Task {
+	if p.embeddings == nil {
+		return nil
+	}
+
+	embedCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	embedding, err := p.embeddings.Generate(embedCtx, question)
+	if err != nil {
+		log.Printf("Failed to embed question, answering from messages only: %v", err)
+		return nil
+	}
+
+	taskIDs, err := embeddings.FindSimilarTasks(p.db, embedding, askTaskLimit)
+	if err != nil {
+		log.Printf("Failed to search tasks for question: %v", err)
+		return nil
+	}
+
+	var tasks []*db.Task
+	for _, id := range taskIDs {
+		task, err := p.db.GetTaskByID(id)
+		if err != nil || task == nil {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+// askKeywords extracts the distinctive words of a question for search
+func askKeywords(question string) []string {
+	words := strings.FieldsFunc(strings.ToLower(question), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool)
+	var keywords []string
+	for _, word := range words {
+		if len(word) < 2 || askStopWords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		keywords = append(keywords, word)
+		if len(keywords) == askKeywordLimit {
+			break
+		}
+	}
+	return keywords
+}
@@ -0,0 +1,32 @@
+package planner
+
+import (
+	"log"
+	"sort"
+	"time"
+)
+
+// AutoArchiveTasks archives pending tasks that have gone untouched for longer than their
+// source's planner.auto_archive_days, returning how many were archived
+func (p *Planner) AutoArchiveTasks() (int, error) {
+	sources := make([]string, 0, len(p.config.Planner.AutoArchiveDays))
+	for source := range p.config.Planner.AutoArchiveDays {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	now := time.Now()
+	total := 0
+	for _, source := range sources {
+		days := p.config.Planner.AutoArchiveDays[source]
+		archived, err := p.db.AutoArchiveTasks(source, now.AddDate(0, 0, -days))
+		if err != nil {
+			return total, err
+		}
+		if archived > 0 {
+			log.Printf("Archived %d %s tasks untouched for %d days", archived, source, days)
+		}
+		total += archived
+	}
+	return total, nil
+}
@@ -0,0 +1,189 @@
+package planner
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// backToBackGap is the longest break between two meetings that still counts as back to back
+const backToBackGap = 5 * time.Minute
+
+// maxDeclineSuggestions caps the meetings the brief suggests declining
+const maxDeclineSuggestions = 3
+
+// minDeclineAttendees is the fewest attendees a meeting needs before it's suggested for
+// declining; the user is less likely to be missed in a bigger meeting
+const minDeclineAttendees = 3
+
+// calendarWarnings checks today's calendar for double-booked meetings, long back-to-back runs
+// and too little focus time, or returns none when the checks are off
+func (p *Planner) calendarWarnings(now time.Time) []*db.CalendarWarning {
+	checks := p.config.Planner.CalendarChecks
+	if !checks.Enabled {
+		return nil
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	events, err := p.db.GetEventsBetween(midnight, midnight.AddDate(0, 0, 1))
+	if err != nil {
+		log.Printf("Failed to get events for calendar checks: %v", err)
+		return nil
+	}
+	own, err := p.db.GetTimeBlockEventIDs()
+	if err != nil {
+		log.Printf("Failed to get time block events: %v", err)
+		return nil
+	}
+
+	// Our own focus blocks, all-day events and meetings the user declined don't take up the time
+	var meetings []*db.Event
+	for _, event := range events {
+		if own[event.ID] || event.EndTS.Sub(event.StartTS) >= 24*time.Hour || event.Response == "declined" {
+			continue
+		}
+		meetings = append(meetings, event)
+	}
+
+	minGap := time.Duration(p.config.Planner.TimeBlocking.MinMinutes) * time.Minute
+	return checkCalendar(meetings, checks, clockOn(midnight, checks.WorkdayStart),
+		clockOn(midnight, checks.WorkdayEnd), minGap, p.config.Google.UserEmail)
+}
+
+// checkCalendar flags overlapping meetings, runs of back-to-back meetings longer than
+// max_chain_hours and a workday with less than min_focus_hours free in gaps of at least minGap.
+// When anything is flagged, the biggest of the meetings involved that the user didn't organize
+// are suggested for declining. meetings must be sorted by start time.
+func checkCalendar(meetings []*db.Event, checks config.CalendarChecks, workdayStart, workdayEnd time.Time, minGap time.Duration, userEmail string) []*db.CalendarWarning {
+	var warnings []*db.CalendarWarning
+	involved := make(map[string]*db.Event)
+
+	// Double-booked meetings
+	for i, event := range meetings {
+		for _, other := range meetings[i+1:] {
+			if !other.StartTS.Before(event.EndTS) {
+				break
+			}
+			warnings = append(warnings, &db.CalendarWarning{Kind: db.CalendarConflict, Events: []*db.Event{event, other}})
+			involved[event.ID], involved[other.ID] = event, other
+		}
+	}
+
+	// Back-to-back runs
+	maxChain := time.Duration(checks.MaxChainHours * float64(time.Hour))
+	for i := 0; i < len(meetings); {
+		chain := []*db.Event{meetings[i]}
+		chainEnd := meetings[i].EndTS
+		j := i + 1
+		for ; j < len(meetings) && meetings[j].StartTS.Sub(chainEnd) <= backToBackGap; j++ {
+			chain = append(chain, meetings[j])
+			if meetings[j].EndTS.After(chainEnd) {
+				chainEnd = meetings[j].EndTS
+			}
+		}
+		if length := chainEnd.Sub(chain[0].StartTS); maxChain > 0 && len(chain) > 1 && length > maxChain {
+			warnings = append(warnings, &db.CalendarWarning{
+				Kind:    db.CalendarChain,
+				Events:  chain,
+				Minutes: int(length.Minutes()),
+				Limit:   int(maxChain.Minutes()),
+			})
+			for _, event := range chain {
+				involved[event.ID] = event
+			}
+		}
+		i = j
+	}
+
+	// Focus time left in the workday
+	minFocus := time.Duration(checks.MinFocusHours * float64(time.Hour))
+	if minFocus > 0 {
+		var free time.Duration
+		cursor := workdayStart
+		for _, event := range meetings {
+			if !event.EndTS.After(workdayStart) || !event.StartTS.Before(workdayEnd) {
+				continue
+			}
+			if gap := event.StartTS.Sub(cursor); gap >= minGap {
+				free += gap
+			}
+			if event.EndTS.After(cursor) {
+				cursor = event.EndTS
+			}
+		}
+		if gap := workdayEnd.Sub(cursor); gap >= minGap {
+			free += gap
+		}
+		if free < minFocus {
+			warnings = append(warnings, &db.CalendarWarning{
+				Kind:    db.CalendarLowFocus,
+				Minutes: int(free.Minutes()),
+				Limit:   int(minFocus.Minutes()),
+			})
+			for _, event := range meetings {
+				if event.EndTS.After(workdayStart) && event.StartTS.Before(workdayEnd) {
+					involved[event.ID] = event
+				}
+			}
+		}
+	}
+
+	return append(warnings, declineSuggestions(involved, userEmail)...)
+}
+
+// declineSuggestions picks the meetings the user could most easily skip: ones someone else
+// organized, with the most attendees, preferring those the user hasn't accepted yet
+func declineSuggestions(involved map[string]*db.Event, userEmail string) []*db.CalendarWarning {
+	var candidates []*db.Event
+	for _, event := range involved {
+		if event.Organizer == "" || strings.EqualFold(event.Organizer, userEmail) ||
+			len(event.Attendees) < minDeclineAttendees {
+			continue
+		}
+		candidates = append(candidates, event)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if accepted(a) != accepted(b) {
+			return !accepted(a)
+		}
+		if len(a.Attendees) != len(b.Attendees) {
+			return len(a.Attendees) > len(b.Attendees)
+		}
+		return a.StartTS.Before(b.StartTS)
+	})
+	if len(candidates) > maxDeclineSuggestions {
+		candidates = candidates[:maxDeclineSuggestions]
+	}
+
+	warnings := make([]*db.CalendarWarning, 0, len(candidates))
+	for _, event := range candidates {
+		reason := fmt.Sprintf("%d attendees, organized by %s", len(event.Attendees), event.Organizer)
+		if !accepted(event) {
+			reason += ", not accepted yet"
+		}
+		warnings = append(warnings, &db.CalendarWarning{Kind: db.CalendarDecline, Events: []*db.Event{event}, Reason: reason})
+	}
+	return warnings
+}
+
+// accepted reports whether the user has accepted a meeting
+func accepted(event *db.Event) bool {
+	return event.Response == "accepted"
+}
+
+// clockOn returns an HH:MM time of day on the given day, or the start of the day if it doesn't
+// parse
+func clockOn(day time.Time, hhmm string) time.Time {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return day
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location())
+}
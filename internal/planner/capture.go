@@ -0,0 +1,138 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// CaptureTasks turns a dictated note into scored tasks. Action items are extracted with the
+// LLM; if none are found the whole transcript becomes a single task so nothing is lost.
+func (p *Planner) CaptureTasks(ctx context.Context, transcript string) ([]*db.Task, error) {
+	transcript = strings.TrimSpace(transcript)
+	if transcript == "" {
+		return nil, fmt.Errorf("transcript is empty")
+	}
+
+	tasks, err := p.captureText(ctx, transcript, "voice", "")
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Captured %d task(s) from voice note", len(tasks))
+	return tasks, nil
+}
+
+// CaptureNotes turns new Google Keep notes and inbox doc paragraphs into tasks, recording each
+// note so it's only processed once, and trashing processed Keep notes if configured. Keep notes
+// from before capture was turned on are left alone; the inbox doc is captured in full.
+func (p *Planner) CaptureNotes(ctx context.Context) error {
+	capture := p.config.Google.Capture
+	if p.google == nil || (!capture.Keep && capture.InboxDocID == "") {
+		return nil
+	}
+
+	var notes []*db.CapturedNote
+	var errs []string
+	if capture.Keep {
+		keepNotes, err := p.google.Keep.NewNotes(ctx, p.db)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		notes = append(notes, keepNotes...)
+	}
+	if capture.InboxDocID != "" {
+		docNotes, err := p.google.Drive.InboxNotes(ctx, capture.InboxDocID)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("inbox doc: %v", err))
+		}
+		notes = append(notes, docNotes...)
+	}
+
+	captured := make(map[string]map[string]bool)
+	total := 0
+	for _, note := range notes {
+		if captured[note.Source] == nil {
+			ids, err := p.db.GetCapturedNoteIDs(note.Source)
+			if err != nil {
+				return err
+			}
+			captured[note.Source] = ids
+		}
+		if captured[note.Source][note.ID] {
+			continue
+		}
+
+		tasks, err := p.captureText(ctx, note.Text, note.Source, note.ID)
+		if err != nil {
+			// Left unrecorded, so it's tried again on the next run
+			log.Printf("Failed to capture note %s: %v", note.ID, err)
+			continue
+		}
+		if err := p.db.SaveCapturedNote(note, len(tasks)); err != nil {
+			return err
+		}
+		total += len(tasks)
+
+		if note.Source == db.NoteSourceKeep && capture.TrashProcessed {
+			if err := p.google.Keep.TrashNote(ctx, note.ID); err != nil {
+				log.Printf("Failed to trash captured Keep note %s: %v", note.ID, err)
+			}
+		}
+	}
+
+	if total > 0 {
+		log.Printf("Captured %d task(s) from quick notes", total)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to read quick notes: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// captureText extracts tasks from captured text, saves them under the given source and scores
+// them. If no action items are found the whole text becomes a single task so nothing is lost.
+func (p *Planner) captureText(ctx context.Context, text, source, sourceID string) ([]*db.Task, error) {
+	tasks, err := p.llm.ExtractTasks(ctx, text)
+	if err != nil {
+		log.Printf("Task extraction failed for %s capture, saving text as task: %v", source, err)
+	}
+
+	if len(tasks) == 0 {
+		title := text
+		if len(title) > 120 {
+			title = title[:117] + "..."
+		}
+		tasks = []*db.Task{{
+			Title:       title,
+			Description: text,
+			Status:      "pending",
+			Impact:      3,
+			Urgency:     3,
+			Effort:      "M",
+		}}
+	}
+
+	now := time.Now()
+	for i, task := range tasks {
+		task.ID = fmt.Sprintf("%s_%d_%d", source, now.UnixNano(), i)
+		task.Source = source
+		task.SourceID = sourceID
+		if task.Description == "" {
+			task.Description = text
+		}
+
+		if err := p.db.SaveTask(task); err != nil {
+			return nil, fmt.Errorf("failed to save captured task: %w", err)
+		}
+
+		if err := p.PrioritizeTask(ctx, task); err != nil {
+			log.Printf("Failed to prioritize captured task '%s': %v", task.Title, err)
+		}
+	}
+	return tasks, nil
+}
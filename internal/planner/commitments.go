@@ -0,0 +1,87 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// CheckCommitments keeps the commitment ledger current: commitments the user followed up on are
+// closed, and new sent mail is checked for promises
+func (p *Planner) CheckCommitments(ctx context.Context) error {
+	userEmail := p.config.Google.UserEmail
+	if userEmail == "" {
+		return fmt.Errorf("google.user_email is needed to find sent mail")
+	}
+
+	delivered, err := p.db.ResolveDeliveredCommitments(userEmail)
+	if err != nil {
+		return err
+	}
+
+	since := time.Now().AddDate(0, 0, -p.config.Commitments.LookbackDays)
+	messages, err := p.db.GetSentMessagesForCommitments(userEmail, since, p.config.Commitments.MaxPerRun)
+	if err != nil {
+		return err
+	}
+
+	tracked := 0
+	for _, msg := range messages {
+		found, err := p.llm.ExtractCommitments(ctx, msg)
+		if err != nil {
+			// Left unchecked, so it's tried again on the next run
+			log.Printf("Failed to check sent message %s for commitments: %v", msg.ID, err)
+			continue
+		}
+
+		commitments := make([]*db.Commitment, 0, len(found))
+		for _, promise := range found {
+			commitment := &db.Commitment{
+				Promise:   promise.Promise,
+				Recipient: promise.Recipient,
+				Kind:      db.CommitmentReply,
+				Status:    db.CommitmentOpen,
+			}
+			if commitment.Recipient == "" {
+				commitment.Recipient = firstAddress(msg.To)
+			}
+			if promise.Deliverable {
+				commitment.Kind = db.CommitmentAttachment
+			}
+			if promise.DueBy != "" {
+				if date, err := time.ParseInLocation("2006-01-02", promise.DueBy, time.Local); err == nil && date.After(msg.Timestamp) {
+					due := date.AddDate(0, 0, 1)
+					commitment.DueTS = &due
+				}
+			}
+			commitments = append(commitments, commitment)
+		}
+
+		if err := p.db.SaveCommitments(msg, commitments); err != nil {
+			log.Printf("Warning: %v", err)
+			continue
+		}
+		tracked += len(commitments)
+	}
+
+	if delivered > 0 || tracked > 0 {
+		log.Printf("Commitments: %d new, %d delivered", tracked, delivered)
+	}
+	return nil
+}
+
+// outstandingCommitments returns the open commitments listed in the daily brief, or none when
+// the ledger is off
+func (p *Planner) outstandingCommitments() []*db.Commitment {
+	if !p.config.Commitments.Enabled || p.config.Commitments.MaxInBrief <= 0 {
+		return nil
+	}
+	commitments, err := p.db.GetOpenCommitments(p.config.Commitments.MaxInBrief)
+	if err != nil {
+		log.Printf("Failed to get outstanding commitments: %v", err)
+	}
+	return commitments
+}
@@ -0,0 +1,89 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/scoring"
+)
+
+// CorrectTaskField fixes one field the LLM guessed when extracting a task: due (RFC3339, empty
+// clears it), impact (1-5) or stakeholder. The correction is recorded as training signal, and
+// an impact change is also fed back as priority feedback.
+func (p *Planner) CorrectTaskField(ctx context.Context, taskID, field, value string) (*db.Task, error) {
+	task, err := p.db.GetTaskByID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	in := TaskInputFrom(task)
+	value = strings.TrimSpace(value)
+	var oldValue string
+
+	switch field {
+	case db.CorrectionDue:
+		if task.DueTS != nil {
+			oldValue = task.DueTS.Format(time.RFC3339)
+		}
+		in.DueTS = nil
+		if value != "" {
+			due, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("due must be an RFC3339 time")
+			}
+			in.DueTS = &due
+			value = due.Format(time.RFC3339)
+		}
+	case db.CorrectionImpact:
+		oldValue = strconv.Itoa(task.Impact)
+		impact, err := strconv.Atoi(value)
+		if err != nil || impact < 1 || impact > 5 {
+			return nil, fmt.Errorf("impact must be between 1 and 5")
+		}
+		in.Impact = impact
+	case db.CorrectionStakeholder:
+		oldValue = task.Stakeholder
+		in.Stakeholder = value
+	default:
+		return nil, fmt.Errorf("unknown field %q (want due, impact or stakeholder)", field)
+	}
+
+	if value == oldValue {
+		return task, nil
+	}
+
+	oldScore := task.Score
+	updated, err := p.UpdateTask(ctx, taskID, in)
+	if err != nil {
+		return nil, err
+	}
+
+	correction := &db.TaskCorrection{
+		TaskID:   taskID,
+		Source:   task.Source,
+		Field:    field,
+		OldValue: oldValue,
+		NewValue: value,
+	}
+	if err := p.db.SaveTaskCorrection(correction); err != nil {
+		log.Printf("Failed to record correction to '%s': %v", task.Title, err)
+	}
+
+	if field == db.CorrectionImpact {
+		vote := 1
+		if updated.Impact < task.Impact {
+			vote = -1
+		}
+		reason := fmt.Sprintf("impact corrected from %s to %s", oldValue, value)
+		if err := scoring.SaveFeedback(p.db, taskID, vote, reason, oldScore, updated.Score); err != nil {
+			log.Printf("Failed to record impact feedback for '%s': %v", task.Title, err)
+		}
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,210 @@
+package planner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// TimeBlockChange is an edit made to a block while planning the day: moving it, changing its
+// length or taking a task out of it
+type TimeBlockChange struct {
+	Shift      time.Duration // Moves the whole block later (or earlier when negative)
+	Resize     time.Duration // Lengthens the block (or shortens it when negative)
+	DropTaskID string        // Task to take out of the block
+}
+
+// AcceptTimeBlock keeps a proposed block in the day's plan. It isn't written to the calendar
+// until the plan is committed.
+func (p *Planner) AcceptTimeBlock(id string) (*db.TimeBlock, error) {
+	block, err := p.db.GetTimeBlock(id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Status {
+	case db.TimeBlockAccepted, db.TimeBlockScheduled:
+		p.addTaskTitles([]*db.TimeBlock{block})
+		return block, nil
+	case db.TimeBlockDeclined:
+		return nil, fmt.Errorf("%s was declined", block.Name)
+	case db.TimeBlockConflict:
+		return nil, fmt.Errorf("%s conflicts with %q: move it to a free time first", block.Name, block.Conflict)
+	}
+
+	block.Status = db.TimeBlockAccepted
+	if err := p.db.UpdateTimeBlock(block); err != nil {
+		return nil, err
+	}
+	p.addTaskTitles([]*db.TimeBlock{block})
+	return block, nil
+}
+
+// AdjustTimeBlock applies a change to one of today's blocks. A block in conflict is fitted
+// around events again at its new time, and one already on the calendar has its event moved.
+func (p *Planner) AdjustTimeBlock(ctx context.Context, id string, change TimeBlockChange) (*db.TimeBlock, error) {
+	block, err := p.db.GetTimeBlock(id)
+	if err != nil {
+		return nil, err
+	}
+	if block.Status == db.TimeBlockDeclined {
+		return nil, fmt.Errorf("%s was declined", block.Name)
+	}
+	p.addTaskTitles([]*db.TimeBlock{block})
+
+	if change.DropTaskID != "" {
+		var ids, titles []string
+		for i, taskID := range block.TaskIDs {
+			if taskID != change.DropTaskID {
+				ids = append(ids, taskID)
+				titles = append(titles, block.TaskTitles[i])
+			}
+		}
+		if len(ids) == len(block.TaskIDs) {
+			return nil, fmt.Errorf("task not found in %s: %s", block.Name, change.DropTaskID)
+		}
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("%s has no other tasks: decline it instead", block.Name)
+		}
+		block.TaskIDs, block.TaskTitles = ids, titles
+	}
+
+	start := block.StartTS.Add(change.Shift)
+	end := block.EndTS.Add(change.Shift + change.Resize)
+	moved := !start.Equal(block.StartTS) || !end.Equal(block.EndTS)
+	if moved {
+		now := time.Now()
+		minLength := time.Duration(p.config.Planner.TimeBlocking.MinMinutes) * time.Minute
+		switch {
+		case end.Sub(start) < minLength:
+			return nil, fmt.Errorf("%s can't be shorter than %d minutes", block.Name, p.config.Planner.TimeBlocking.MinMinutes)
+		case !end.After(now) || (change.Shift < 0 && start.Before(now)):
+			return nil, fmt.Errorf("%s can't be moved into the past", block.Name)
+		case db.TimeBlockDay(start) != block.Day || db.TimeBlockDay(end.Add(-time.Second)) != block.Day:
+			return nil, fmt.Errorf("%s has to stay on %s", block.Name, block.Day)
+		}
+		block.StartTS, block.EndTS = start, end
+
+		if block.Status == db.TimeBlockConflict {
+			if err := p.fitTimeBlock(block); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if moved && block.Status == db.TimeBlockScheduled {
+		if p.google == nil || p.google.Calendar == nil {
+			return nil, fmt.Errorf("calendar client not available")
+		}
+		if err := p.google.Calendar.MoveEvent(ctx, block.CalendarEventID, block.StartTS, block.EndTS); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.db.UpdateTimeBlock(block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// CommitDayPlan finishes planning the day. Accepted blocks are written to the calendar when
+// calendar is set (otherwise they stay in the plan only), and blocks left undecided are
+// declined. Blocks that couldn't be scheduled are reported together after the rest are done.
+func (p *Planner) CommitDayPlan(ctx context.Context, calendar bool) ([]*db.TimeBlock, error) {
+	day := db.TimeBlockDay(time.Now())
+	blocks, err := p.db.GetTimeBlocks(day)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	accepted := 0
+	for _, block := range blocks {
+		switch block.Status {
+		case db.TimeBlockProposed, db.TimeBlockConflict:
+			block.Status = db.TimeBlockDeclined
+			if err := p.db.UpdateTimeBlock(block); err != nil {
+				return nil, err
+			}
+		case db.TimeBlockAccepted:
+			accepted++
+			// Blocks that are already over stay in the plan for the evening's review
+			if !calendar || !block.EndTS.After(time.Now()) {
+				continue
+			}
+			if _, err := p.ConfirmTimeBlock(ctx, block.ID); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", block.Name, err))
+			}
+		case db.TimeBlockScheduled:
+			accepted++
+		}
+	}
+	log.Printf("Committed the plan for %s: %d block(s), calendar: %v", day, accepted, calendar)
+
+	blocks, err = p.db.GetTimeBlocks(day)
+	if err != nil {
+		return nil, err
+	}
+	p.addTaskTitles(blocks)
+	return blocks, errors.Join(errs...)
+}
+
+// planActuals compares the day's committed focus blocks with the planned tasks completed that
+// day and the focus session time spent inside each block
+func (p *Planner) planActuals(startOfDay, now time.Time) ([]*db.PlanActual, error) {
+	blocks, err := p.db.GetTimeBlocks(db.TimeBlockDay(startOfDay))
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := p.db.GetFocusSessionsBetween(startOfDay, now)
+	if err != nil {
+		return nil, err
+	}
+	tomorrow := startOfDay.AddDate(0, 0, 1)
+
+	var actuals []*db.PlanActual
+	for _, block := range blocks {
+		if block.Status != db.TimeBlockAccepted && block.Status != db.TimeBlockScheduled {
+			continue
+		}
+
+		actual := &db.PlanActual{
+			Name:         block.Name,
+			StartTS:      block.StartTS,
+			EndTS:        block.EndTS,
+			TasksPlanned: len(block.TaskIDs),
+		}
+		for _, id := range block.TaskIDs {
+			task, err := p.db.GetTaskByID(id)
+			if err == nil && task.Status == "completed" && task.CompletedAt != nil &&
+				!task.CompletedAt.Before(startOfDay) && task.CompletedAt.Before(tomorrow) {
+				actual.TasksDone++
+			}
+		}
+
+		var focused time.Duration
+		for _, session := range sessions {
+			start, end := session.StartedAt, session.End()
+			if end.After(now) {
+				end = now
+			}
+			if start.Before(block.StartTS) {
+				start = block.StartTS
+			}
+			if end.After(block.EndTS) {
+				end = block.EndTS
+			}
+			if end.After(start) {
+				focused += end.Sub(start)
+			}
+		}
+		actual.FocusMinutes = int(focused.Minutes())
+
+		actuals = append(actuals, actual)
+	}
+	return actuals, nil
+}
@@ -0,0 +1,113 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// Digest is the weekly "everything else" roundup of items kept out of the daily brief, with a
+// per-tag review of the week
+type Digest struct {
+	Tasks    []*db.Task     `json:"tasks"`
+	Threads  []*db.Thread   `json:"threads"`
+	TagStats []*db.TagStats `json:"tag_stats"`
+	Since    time.Time      `json:"since"`
+}
+
+// DigestArchiveResult reports how many digest items were archived
+type DigestArchiveResult struct {
+	TasksArchived   int `json:"tasks_archived"`
+	ThreadsArchived int `json:"threads_archived"`
+}
+
+// BuildDigest collects low-scoring pending tasks and FYI threads from the past week
+func (p *Planner) BuildDigest() (*Digest, error) {
+	threshold := p.config.Planner.DigestScoreThreshold
+	since := time.Now().AddDate(0, 0, -7)
+
+	tasks, err := p.db.GetLowPriorityTasks(threshold, 100)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get low-priority tasks: %w", err)
+	}
+
+	threads, err := p.db.GetFYIThreads(threshold, since, 50)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get FYI threads: %w", err)
+	}
+
+	tagStats, err := p.db.GetTagStats(since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag stats: %w", err)
+	}
+
+	return &Digest{Tasks: tasks, Threads: threads, TagStats: tagStats, Since: since}, nil
+}
+
+// GenerateWeeklyDigest builds and sends the weekly digest
+func (p *Planner) GenerateWeeklyDigest(ctx context.Context) error {
+	digest, err := p.BuildDigest()
+	if err != nil {
+		return err
+	}
+
+	if len(digest.Tasks) == 0 && len(digest.Threads) == 0 && len(digest.TagStats) == 0 {
+		log.Println("Weekly digest is empty, nothing to send")
+		return nil
+	}
+
+	err = p.notify("weekly_digest", briefDelivery{
+		config.ChannelChat: func() error {
+			return p.google.Chat.SendWeeklyDigest(ctx, p.db, digest.Tasks, digest.Threads, digest.TagStats)
+		},
+		config.ChannelSlack: func() error {
+			return p.slack.SendWeeklyDigest(ctx, p.db, digest.Tasks, digest.Threads, digest.TagStats)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send weekly digest: %w", err)
+	}
+
+	p.db.LogUsage("planner", "weekly_digest", 0, 0, 0, nil)
+	return nil
+}
+
+// ArchiveDigest archives the given digest tasks and threads in one go
+func (p *Planner) ArchiveDigest(taskIDs, threadIDs []string) (*DigestArchiveResult, error) {
+	if len(taskIDs) == 0 && len(threadIDs) == 0 {
+		return nil, fmt.Errorf("nothing to archive")
+	}
+
+	result := &DigestArchiveResult{}
+
+	archived, err := p.db.ArchiveTasks(taskIDs)
+	result.TasksArchived = archived
+	if err != nil {
+		return result, err
+	}
+
+	archived, err = p.db.ArchiveThreads(threadIDs)
+	result.ThreadsArchived = archived
+	if err != nil {
+		return result, err
+	}
+
+	log.Printf("Archived digest: %d tasks, %d threads", result.TasksArchived, result.ThreadsArchived)
+	return result, nil
+}
+
+// aboveDigestThreshold drops tasks that belong in the weekly digest rather than the daily brief
+func (p *Planner) aboveDigestThreshold(tasks []*db.Task) []*db.Task {
+	threshold := p.config.Planner.DigestScoreThreshold
+	filtered := make([]*db.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Score >= threshold {
+			filtered = append(filtered, task)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,157 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/embeddings"
+)
+
+// docUpdatesKey is the prefs key holding when the last daily brief listed updated documents
+const docUpdatesKey = "doc_updates_since"
+
+// Documents updated since the last brief are looked back for at least a day and at most a week,
+// and only this many of the most recent are checked for relevance
+const (
+	minDocUpdatesLookback  = 24 * time.Hour
+	maxDocUpdatesLookback  = 7 * 24 * time.Hour
+	maxDocUpdateCandidates = 50
+)
+
+// documentUpdates returns the Drive documents edited since the last daily brief that relate to
+// the priorities, most recent first. Nothing is returned when planner.doc_updates is off.
+func (p *Planner) documentUpdates(ctx context.Context, now time.Time) []*db.DocumentUpdate {
+	cfg := p.config.Planner.DocUpdates
+	if !cfg.Enabled || cfg.MaxDocs == 0 {
+		return nil
+	}
+
+	since := now.Add(-minDocUpdatesLookback)
+	if saved, err := p.db.GetPreference(docUpdatesKey); err != nil {
+		log.Printf("Failed to get last document updates time: %v", err)
+	} else if unix, err := strconv.ParseInt(saved, 10, 64); err == nil {
+		since = time.Unix(unix, 0)
+	}
+	if oldest := now.Add(-maxDocUpdatesLookback); since.Before(oldest) {
+		since = oldest
+	} else if latest := now.Add(-minDocUpdatesLookback); since.After(latest) {
+		since = latest
+	}
+
+	docs, err := p.db.GetDocumentsUpdatedSince(since, maxDocUpdateCandidates)
+	if err != nil {
+		log.Printf("Failed to get updated documents: %v", err)
+		return nil
+	}
+
+	priorities := p.GetPriorities()
+	var vectors []priorityVector
+	if cfg.MinSimilarity >= 0 && p.embeddings != nil {
+		if vectors, err = p.priorityVectors(ctx, priorities); err != nil {
+			log.Printf("Matching updated documents without embeddings: %v", err)
+		}
+	}
+
+	userEmail := strings.ToLower(p.config.Google.UserEmail)
+	var updates []*db.DocumentUpdate
+	for _, doc := range docs {
+		if len(updates) >= cfg.MaxDocs {
+			break
+		}
+		if userEmail != "" && strings.EqualFold(doc.ModifiedBy, userEmail) {
+			continue
+		}
+
+		reason := documentMatch(doc, priorities)
+		if reason == "" && len(vectors) > 0 {
+			embedding, err := p.embed(ctx, documentEmbeddingText(doc))
+			if err != nil {
+				// Most likely Ollama is down, so don't try the rest
+				log.Printf("Matching updated documents without embeddings: %v", err)
+				vectors = nil
+			} else {
+				reason = similarPriority(embedding, vectors, cfg.MinSimilarity)
+			}
+		}
+		if reason != "" {
+			updates = append(updates, &db.DocumentUpdate{Document: doc, Reason: reason})
+		}
+	}
+	return updates
+}
+
+// markDocumentUpdatesReported records that documents edited up to now have been considered for
+// a brief, so the next brief only looks at later edits
+func (p *Planner) markDocumentUpdatesReported(now time.Time) {
+	if !p.config.Planner.DocUpdates.Enabled {
+		return
+	}
+	if err := p.db.SetPreference(docUpdatesKey, strconv.FormatInt(now.Unix(), 10)); err != nil {
+		log.Printf("Failed to save last document updates time: %v", err)
+	}
+}
+
+// documentMatch says why a document relates to the priorities without embeddings: a key
+// stakeholder last edited or owns it, or its title names a key project or focus area. It returns
+// "" when nothing matches.
+func documentMatch(doc *db.Document, priorities *config.Priorities) string {
+	for _, stakeholder := range priorities.KeyStakeholders {
+		stakeholder = strings.ToLower(strings.TrimSpace(stakeholder))
+		if stakeholder == "" {
+			continue
+		}
+		if doc.ModifiedBy != "" && strings.Contains(strings.ToLower(doc.ModifiedBy), stakeholder) {
+			return "edited by " + doc.ModifiedBy
+		}
+		if doc.Owner != "" && strings.Contains(strings.ToLower(doc.Owner), stakeholder) {
+			return "owned by " + doc.Owner
+		}
+	}
+
+	title := strings.ToLower(doc.Title)
+	for _, group := range []struct {
+		label string
+		names []string
+	}{
+		{"project", priorities.KeyProjects},
+		{"focus area", priorities.FocusAreas},
+	} {
+		for _, name := range group.names {
+			if trimmed := strings.ToLower(strings.TrimSpace(name)); trimmed != "" && strings.Contains(title, trimmed) {
+				return group.label + ": " + strings.TrimSpace(name)
+			}
+		}
+	}
+	return ""
+}
+
+// documentEmbeddingText is the text of a document compared with the priorities
+func documentEmbeddingText(doc *db.Document) string {
+	text := "Document: " + doc.Title
+	if doc.Summary != "" {
+		text += "\nSummary: " + doc.Summary
+	}
+	return text
+}
+
+// similarPriority names the priority most similar to an embedding, if any is at least
+// minSimilarity, or returns ""
+func similarPriority(embedding []float64, vectors []priorityVector, minSimilarity float64) string {
+	best, reason := minSimilarity, ""
+	labels := map[string]string{"okr": "OKR", "focus_area": "focus area", "project": "project"}
+	for _, vector := range vectors {
+		similarity, err := embeddings.CosineSimilarity(embedding, vector.embedding)
+		if err != nil || similarity < best {
+			continue
+		}
+		best = similarity
+		reason = fmt.Sprintf("related to %s: %s", labels[vector.kind], vector.name)
+	}
+	return reason
+}
@@ -0,0 +1,156 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// ReplyDraft is a reply drafted for an email thread
+type ReplyDraft struct {
+	ThreadID string `json:"thread_id"`
+	To       string `json:"to,omitempty"`
+	Persona  string `json:"persona"`
+	Text     string `json:"text"`
+	Saved    bool   `json:"saved"` // Saved to Gmail as a draft in the thread
+}
+
+// DraftThreadReply drafts a reply to an email thread that works towards goal, in the named
+// persona. Without one the recipient's remembered persona is used, then
+// drafting.default_persona; a persona named explicitly becomes the recipient's default. With
+// save the draft goes straight to Gmail; otherwise it's returned for the user to edit and pass to
+// SaveThreadReply.
+func (p *Planner) DraftThreadReply(ctx context.Context, threadID, goal, persona string, save bool) (*ReplyDraft, error) {
+	if persona != "" {
+		if _, ok := p.config.Drafting.Persona(persona); !ok {
+			return nil, fmt.Errorf("unknown persona %q", persona)
+		}
+	}
+
+	thread, err := p.db.GetThreadMessages(threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load thread: %w", err)
+	}
+	if len(thread) == 0 {
+		return nil, fmt.Errorf("thread %s has no messages", threadID)
+	}
+	if goal == "" {
+		goal = "Reply to the latest message, answering any questions asked."
+	}
+
+	draft := &ReplyDraft{ThreadID: threadID, To: replyRecipient(thread, p.config.Google.UserEmail)}
+	name, chosen := p.replyPersona(draft.To, persona)
+	draft.Persona = name
+
+	text, err := p.llm.DraftReply(ctx, thread, goal, chosen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to draft reply: %w", err)
+	}
+	draft.Text = strings.TrimSpace(text)
+
+	if persona != "" && draft.To != "" {
+		if err := p.db.SetContactPersona(draft.To, draft.Persona); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if !save {
+		return draft, nil
+	}
+	if draft.Saved, err = p.saveReplyDraft(ctx, thread, draft.To, draft.Text, threadID); err != nil {
+		log.Printf("Failed to save reply draft for thread %s: %v", threadID, err)
+	}
+	return draft, nil
+}
+
+// SaveThreadReply saves a reply the user has reviewed to Gmail as a draft in its thread, or sends
+// it when send is set. An empty to replies to whoever last wrote.
+func (p *Planner) SaveThreadReply(ctx context.Context, threadID, to, body string, send bool) error {
+	if body = strings.TrimSpace(body); body == "" {
+		return fmt.Errorf("reply is empty")
+	}
+	if p.google == nil || p.google.Gmail == nil {
+		return fmt.Errorf("Gmail isn't set up")
+	}
+
+	thread, err := p.db.GetThreadMessages(threadID)
+	if err != nil {
+		return fmt.Errorf("failed to load thread: %w", err)
+	}
+	if len(thread) == 0 {
+		return fmt.Errorf("thread %s has no messages", threadID)
+	}
+	if to = strings.TrimSpace(to); to == "" {
+		to = replyRecipient(thread, p.config.Google.UserEmail)
+	}
+	if to == "" {
+		return fmt.Errorf("no one to reply to in thread %s", threadID)
+	}
+
+	if !send {
+		_, err := p.saveReplyDraft(ctx, thread, to, body, threadID)
+		return err
+	}
+	if _, err := p.google.Gmail.SendMessage(ctx, to, replySubject(thread), body, threadID); err != nil {
+		return err
+	}
+	log.Printf("Sent reply to %s in thread %s", to, threadID)
+	return nil
+}
+
+// replyPersona picks the persona for a reply: the one requested, else the one remembered for the
+// recipient, else drafting.default_persona. It returns the persona's name along with it.
+func (p *Planner) replyPersona(recipient, requested string) (string, *config.Persona) {
+	name := requested
+	if name == "" && recipient != "" {
+		remembered, err := p.db.GetContactPersona(recipient)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		// A persona removed from config since it was remembered falls through to the default
+		if _, ok := p.config.Drafting.Persona(remembered); ok {
+			name = remembered
+		}
+	}
+	if name == "" {
+		name = p.config.Drafting.DefaultPersona
+	}
+	persona, _ := p.config.Drafting.Persona(name)
+	return strings.ToLower(name), persona
+}
+
+// saveReplyDraft saves a reply to a thread as a Gmail draft, reporting whether it was saved.
+// Without Gmail or a recipient there's nowhere to save it.
+func (p *Planner) saveReplyDraft(ctx context.Context, thread []*db.Message, to, body, threadID string) (bool, error) {
+	if len(thread) == 0 || to == "" || p.google == nil || p.google.Gmail == nil {
+		return false, nil
+	}
+	if _, err := p.google.Gmail.CreateDraft(ctx, to, replySubject(thread), body, threadID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// replySubject is the subject of a reply to a thread: its latest subject, marked as a reply
+func replySubject(thread []*db.Message) string {
+	subject := thread[len(thread)-1].Subject
+	if !strings.HasPrefix(strings.ToLower(subject), "re:") {
+		subject = "Re: " + subject
+	}
+	return subject
+}
+
+// DraftingPersonas returns the names of the configured drafting personas, sorted
+func (p *Planner) DraftingPersonas() []string {
+	names := make([]string, 0, len(p.config.Drafting.Personas))
+	for name := range p.config.Drafting.Personas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,83 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// RecalibrateEffort relearns the effort factors from the tasks completed within
+// planner.effort_calibration.lookback_days and rescores pending tasks if they changed. It returns
+// nil when effort calibration is off.
+func (p *Planner) RecalibrateEffort(ctx context.Context) (*db.EffortCalibration, error) {
+	settings := p.config.Planner.EffortCalibration
+	if !settings.Enabled {
+		return nil, nil
+	}
+
+	since := time.Now().AddDate(0, 0, -settings.LookbackDays)
+	calibration, err := p.db.CalibrateEffort(since, settings.MinSamples, settings.MaxFactor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calibrate effort: %w", err)
+	}
+	if err := p.db.SaveEffortCalibration(calibration); err != nil {
+		return nil, err
+	}
+	log.Printf("Calibrated effort for %d projects and %d stakeholders", len(calibration.Projects), len(calibration.Stakeholders))
+
+	// The new factors change the rescore version, so every pending task is rescored
+	if err := p.PrioritizeTasks(ctx); err != nil {
+		log.Printf("Failed to rescore tasks after effort calibration: %v", err)
+	}
+	return calibration, nil
+}
+
+// EffortCalibration returns the effort factors scoring uses, or nil when effort calibration is
+// off or hasn't run yet
+func (p *Planner) EffortCalibration() *db.EffortCalibration {
+	p.calibrationMu.Lock()
+	calibration := p.calibration
+	p.calibrationMu.Unlock()
+	if calibration == nil {
+		calibration = p.loadEffortCalibration()
+	}
+	if calibration.ComputedAt.IsZero() {
+		return nil
+	}
+	return calibration
+}
+
+// loadEffortCalibration reloads the saved effort factors for the scores that follow. An empty
+// calibration, which scales nothing, stands in when calibration is off or hasn't run.
+func (p *Planner) loadEffortCalibration() *db.EffortCalibration {
+	calibration := &db.EffortCalibration{}
+	if p.config.Planner.EffortCalibration.Enabled {
+		saved, err := p.db.GetEffortCalibration()
+		if err != nil {
+			log.Printf("Failed to load effort calibration: %v", err)
+		} else if saved != nil {
+			calibration = saved
+		}
+	}
+
+	p.calibrationMu.Lock()
+	p.calibration = calibration
+	p.calibrationMu.Unlock()
+	return calibration
+}
+
+// effortFactor returns how much a task's effort is scaled by in scoring
+func (p *Planner) effortFactor(task *db.Task) float64 {
+	p.calibrationMu.Lock()
+	calibration := p.calibration
+	p.calibrationMu.Unlock()
+	if calibration == nil {
+		calibration = p.loadEffortCalibration()
+	}
+
+	factor, _ := calibration.For(task.Project, task.Stakeholder)
+	return factor
+}
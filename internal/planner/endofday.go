@@ -0,0 +1,130 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// tomorrowMorningEnd is the hour tomorrow morning's commitments run until
+const tomorrowMorningEnd = 12
+
+// GenerateEndOfDayBrief builds the end-of-day shutdown brief, keeps it in plan history and
+// sends it to the configured channels
+func (p *Planner) GenerateEndOfDayBrief(ctx context.Context) error {
+	brief, err := p.BuildEndOfDayBrief(p.config.Schedule.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := p.db.SavePlanHistory(db.PlanKindEndOfDay, brief.Date, brief.PlanSeed, brief); err != nil {
+		log.Printf("Failed to save end-of-day brief to plan history: %v", err)
+	}
+
+	err = p.notify("end_of_day_brief", briefDelivery{
+		config.ChannelChat:  func() error { return p.google.Chat.SendEndOfDayBrief(ctx, p.db, brief) },
+		config.ChannelSlack: func() error { return p.slack.SendEndOfDayBrief(ctx, p.db, brief) },
+		config.ChannelEmail: func() error { return p.google.Gmail.SendEndOfDayBriefEmail(ctx, p.briefEmailTo(), brief) },
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send end-of-day brief: %w", err)
+	}
+
+	p.db.LogUsage("planner", "end_of_day_brief", 0, 0, 0, nil)
+	return nil
+}
+
+// BuildEndOfDayBrief gathers what was completed today, how the morning's plan held up, what's
+// slipping to tomorrow and tomorrow morning's commitments
+func (p *Planner) BuildEndOfDayBrief(now time.Time) (*db.EndOfDayBrief, error) {
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tomorrow := startOfDay.AddDate(0, 0, 1)
+	tomorrowNoon := tomorrow.Add(tomorrowMorningEnd * time.Hour)
+
+	brief := &db.EndOfDayBrief{Date: startOfDay}
+
+	var err error
+	if brief.Completed, err = p.db.GetTasksCompletedBetween(startOfDay, tomorrow); err != nil {
+		return nil, err
+	}
+
+	// Slipping: due today or overdue, plus anything planned into today's focus blocks
+	if brief.Slipping, err = p.db.GetOpenTasksDueBetween(time.Time{}, tomorrow); err != nil {
+		return nil, err
+	}
+	brief.Slipping = append(brief.Slipping, p.openTimeBlockTasks(startOfDay, brief.Slipping)...)
+
+	if brief.DueTomorrowAM, err = p.db.GetOpenTasksDueBetween(tomorrow, tomorrowNoon); err != nil {
+		return nil, err
+	}
+	if brief.TomorrowMorning, err = p.db.GetEventsBetween(tomorrow, tomorrowNoon); err != nil {
+		return nil, err
+	}
+	if brief.Plan, err = p.planActuals(startOfDay, now); err != nil {
+		return nil, err
+	}
+
+	brief.PlanSeed = p.endOfDayPlanSeed(brief)
+	return brief, nil
+}
+
+// openTimeBlockTasks returns tasks planned into the day's focus blocks that are still open,
+// leaving out those already listed
+func (p *Planner) openTimeBlockTasks(day time.Time, listed []*db.Task) []*db.Task {
+	blocks, err := p.db.GetTimeBlocks(db.TimeBlockDay(day))
+	if err != nil {
+		log.Printf("Failed to get today's time blocks: %v", err)
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for _, task := range listed {
+		seen[task.ID] = true
+	}
+
+	var tasks []*db.Task
+	for _, block := range blocks {
+		for _, id := range block.TaskIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			task, err := p.db.GetTaskByID(id)
+			if err != nil || (task.Status != "pending" && task.Status != "in_progress") {
+				continue
+			}
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// endOfDayPlanSeed suggests where tomorrow starts: the most pressing carried-over or morning
+// task, fitted before the first morning meeting
+func (p *Planner) endOfDayPlanSeed(brief *db.EndOfDayBrief) string {
+	var first *db.Task
+	switch {
+	case len(brief.DueTomorrowAM) > 0:
+		first = brief.DueTomorrowAM[0]
+	case len(brief.Slipping) > 0:
+		first = brief.Slipping[0]
+	default:
+		if tasks, err := p.db.GetPendingTasks(1); err == nil && len(tasks) > 0 {
+			first = tasks[0]
+		}
+	}
+
+	if first == nil {
+		return "Nothing carried over: pick tomorrow's first task fresh"
+	}
+	seed := fmt.Sprintf("Start with %q", first.Title)
+	if len(brief.TomorrowMorning) > 0 {
+		meeting := brief.TomorrowMorning[0]
+		seed += fmt.Sprintf(" before %s at %s", meeting.Title, meeting.StartTS.Format("3:04 PM"))
+	}
+	return seed
+}
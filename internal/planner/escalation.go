@@ -0,0 +1,155 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// escalationWindow is how long after its due date a task keeps escalating. Work overdue for
+// longer is left to the daily brief rather than chased all at once, e.g. when escalation is
+// first turned on.
+const escalationWindow = 7 * 24 * time.Hour
+
+// EscalateOverdueTasks sends the next escalation step for each overdue high-impact task: a
+// reminder, then an urgent push, then a suggested apology or request for more time. Only the
+// furthest step a task has reached is sent, so a task first seen a day late gets the suggested
+// email rather than all three.
+func (p *Planner) EscalateOverdueTasks(ctx context.Context) error {
+	now := time.Now()
+	overdue, err := p.db.GetOverdueTasks(now.Add(-escalationWindow))
+	if err != nil {
+		return err
+	}
+
+	sent := 0
+	for _, escalation := range overdue {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		task := escalation.Task
+		level := escalationLevel(p.config.Escalation.PolicyFor(task.Project, task.Stakeholder), task, now)
+		if level <= escalation.Level {
+			continue
+		}
+		escalation.Level = level
+
+		// Chat and Slack deliveries that fail stay queued in the outbox, so record the step
+		// either way to avoid repeats
+		if err := p.escalate(ctx, escalation); err != nil {
+			log.Printf("Failed to escalate overdue task %s: %v", task.ID, err)
+		}
+		if err := p.db.SetTaskEscalation(task.ID, level, *task.DueTS); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		log.Printf("Escalated %d overdue tasks", sent)
+	}
+	return nil
+}
+
+// escalationLevel returns the furthest escalation step an overdue task has reached under a
+// policy, or db.EscalationNone if its impact is below the policy's threshold
+func escalationLevel(policy config.EscalationPolicy, task *db.Task, now time.Time) int {
+	impact := task.Impact
+	if impact == 0 {
+		impact = 3 // Same default as scoring
+	}
+	if impact < policy.MinImpact {
+		return db.EscalationNone
+	}
+
+	overdue := now.Sub(*task.DueTS)
+	level := db.EscalationNone
+	for step, minutes := range map[int]int{
+		db.EscalationReminder: policy.ReminderMinutes,
+		db.EscalationPush:     policy.PushMinutes,
+		db.EscalationDraft:    policy.DraftMinutes,
+	} {
+		if minutes >= 0 && overdue >= time.Duration(minutes)*time.Minute {
+			level = max(level, step)
+		}
+	}
+	return level
+}
+
+// escalate sends one escalation step for a task. The push step goes to the phone when push is
+// set up; every other step, and pushes that can't be sent, go out as an alert.
+func (p *Planner) escalate(ctx context.Context, escalation *db.TaskEscalation) error {
+	task := escalation.Task
+	title := "Overdue: " + task.Title
+	text := fmt.Sprintf("Was due %s", task.DueTS.In(p.config.Schedule.Location()).Format("Mon Jan 2 at 3:04 PM MST"))
+
+	switch escalation.Level {
+	case db.EscalationPush:
+		if p.push != nil {
+			err := p.push.Send(ctx, title, text)
+			if err == nil {
+				return nil
+			}
+			log.Printf("Failed to push escalation of task %s, sending an alert instead: %v", task.ID, err)
+		}
+	case db.EscalationDraft:
+		p.draftEscalation(ctx, escalation)
+		if escalation.Draft != "" {
+			text += "\n\nSuggested note:\n" + escalation.Draft
+		}
+	}
+
+	return p.alert(ctx, "escalation", title, text, briefDelivery{
+		config.ChannelChat:  func() error { return p.google.Chat.SendEscalation(ctx, p.db, escalation) },
+		config.ChannelSlack: func() error { return p.slack.SendEscalation(ctx, p.db, escalation) },
+	})
+}
+
+// draftEscalation suggests an apology or request for more time for an overdue task. When the
+// task came from an email thread the note is a reply to it, saved as a Gmail draft.
+func (p *Planner) draftEscalation(ctx context.Context, escalation *db.TaskEscalation) {
+	task := escalation.Task
+
+	var thread []*db.Message
+	if task.Source == "gmail" && task.SourceID != "" {
+		messages, err := p.db.GetThreadMessages(task.SourceID)
+		if err != nil {
+			log.Printf("Failed to load thread for escalation of task %s: %v", task.ID, err)
+		}
+		thread = messages
+	}
+
+	goal := fmt.Sprintf("Apologise that \"%s\" wasn't done by its deadline (%s). Say when it will be done, "+
+		"or ask whether a new date would work.", task.Title, task.DueTS.In(p.config.Schedule.Location()).Format("Monday, January 2"))
+	to := replyRecipient(thread, p.config.Google.UserEmail)
+	_, persona := p.replyPersona(to, "")
+	draft, err := p.llm.DraftReply(ctx, thread, goal, persona)
+	if err != nil {
+		log.Printf("Failed to draft escalation note for task %s: %v", task.ID, err)
+		return
+	}
+	escalation.Draft = strings.TrimSpace(draft)
+
+	saved, err := p.saveReplyDraft(ctx, thread, to, escalation.Draft, task.SourceID)
+	if err != nil {
+		log.Printf("Failed to save escalation draft for task %s: %v", task.ID, err)
+	}
+	escalation.DraftSaved = saved
+}
+
+// replyRecipient returns who a reply to a thread goes to: the sender of its latest message
+// that isn't from the user
+func replyRecipient(thread []*db.Message, userEmail string) string {
+	for i := len(thread) - 1; i >= 0; i-- {
+		from := firstAddress(thread[i].From)
+		if from != "" && !strings.EqualFold(from, userEmail) {
+			return from
+		}
+	}
+	return ""
+}
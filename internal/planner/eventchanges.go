@@ -0,0 +1,134 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// HandleEventChanges carries moved and cancelled meetings through to the tasks and time blocks
+// that depend on them. Changes that fail are retried after the next sync.
+func (p *Planner) HandleEventChanges(ctx context.Context) error {
+	changes, err := p.db.GetUnprocessedEventChanges()
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		if err := p.handleEventChange(ctx, change); err != nil {
+			log.Printf("Failed to handle change to %s: %v", change.Title, err)
+			continue
+		}
+		if err := p.db.MarkEventChangeProcessed(change.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Planner) handleEventChange(ctx context.Context, change *db.EventChange) error {
+	// A focus block that was moved or deleted in the calendar follows the edit
+	block, err := p.db.GetTimeBlockByEvent(change.EventID)
+	if err != nil {
+		return err
+	}
+	if block != nil {
+		if change.Kind == db.EventCancelled {
+			block.Status = db.TimeBlockDeclined
+		} else {
+			block.StartTS, block.EndTS = change.NewStart, change.NewEnd
+		}
+		return p.db.UpdateTimeBlock(block)
+	}
+
+	start, end := change.OldStart, change.OldEnd
+	switch change.Kind {
+	case db.EventCancelled:
+		n, err := p.db.CancelEventTasks(change.EventID)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			log.Printf("Cancelled %d prep task(s) for cancelled event %s", n, change.Title)
+		}
+	case db.EventMoved:
+		n, err := p.db.ShiftEventTasks(change.EventID, change.NewStart.Sub(change.OldStart))
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			log.Printf("Moved %d prep task(s) with %s", n, change.Title)
+		}
+		if change.NewStart.Before(start) {
+			start = change.NewStart
+		}
+		if change.NewEnd.After(end) {
+			end = change.NewEnd
+		}
+	}
+
+	if !p.config.Planner.TimeBlocking.Enabled {
+		return nil
+	}
+	return p.refitTimeBlocks(ctx, start, end)
+}
+
+// refitTimeBlocks re-fits blocks that haven't started yet around the events in a period. A
+// scheduled block that shrinks is moved in the calendar, and one with no room left is taken
+// off it. Blocks that were in conflict are proposed again once there is room.
+func (p *Planner) refitTimeBlocks(ctx context.Context, start, end time.Time) error {
+	blocks, err := p.db.GetTimeBlocksBetween(start, end)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, block := range blocks {
+		if !block.StartTS.After(now) {
+			continue
+		}
+
+		oldStatus, oldStart, oldEnd := block.Status, block.StartTS, block.EndTS
+		if err := p.fitTimeBlock(block); err != nil {
+			return err
+		}
+		if block.Status == oldStatus && block.StartTS.Equal(oldStart) && block.EndTS.Equal(oldEnd) {
+			continue
+		}
+
+		switch {
+		case oldStatus == db.TimeBlockScheduled && block.Status == db.TimeBlockConflict:
+			if p.google == nil || p.google.Calendar == nil {
+				return fmt.Errorf("calendar client not available")
+			}
+			// The event ID is kept so the deletion isn't reported as a cancelled meeting
+			if err := p.google.Calendar.DeleteEvent(ctx, block.CalendarEventID); err != nil {
+				return err
+			}
+			log.Printf("Removed %s from the calendar: clashes with %s", block.Name, block.Conflict)
+		case oldStatus == db.TimeBlockScheduled:
+			if p.google == nil || p.google.Calendar == nil {
+				return fmt.Errorf("calendar client not available")
+			}
+			if err := p.google.Calendar.MoveEvent(ctx, block.CalendarEventID, block.StartTS, block.EndTS); err != nil {
+				return err
+			}
+			log.Printf("Moved %s to %s-%s", block.Name, block.StartTS.Format("15:04"), block.EndTS.Format("15:04"))
+		case oldStatus == db.TimeBlockConflict && block.Status == db.TimeBlockProposed &&
+			p.config.Planner.TimeBlocking.Mode == config.TimeBlockingAuto:
+			if err := p.writeTimeBlock(ctx, block); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := p.db.UpdateTimeBlock(block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/scoring"
+)
+
+// feedbackLookback is how far back priority feedback is learned from, so boosts follow
+// preferences as they change
+const feedbackLookback = 180 * 24 * time.Hour
+
+// RecordFeedback records that a task ranks too low (vote 1) or too high (vote -1). Votes are
+// learned as boosts for tasks from the same project and sender, so pending tasks are rescored
+// straight away and the task is returned with its new score.
+func (p *Planner) RecordFeedback(ctx context.Context, taskID string, vote int, reason string) (*db.Task, error) {
+	if vote != 1 && vote != -1 {
+		return nil, fmt.Errorf("vote must be -1 or 1")
+	}
+
+	task, err := p.db.GetTaskByID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	if err := scoring.SaveFeedback(p.db, taskID, vote, reason, task.Score, task.Score); err != nil {
+		return nil, err
+	}
+
+	if err := p.PrioritizeTasks(ctx); err != nil {
+		log.Printf("Failed to rescore tasks after feedback: %v", err)
+	}
+
+	return p.db.GetTaskByID(taskID)
+}
+
+// FeedbackBoosts returns the boosts learned from priority feedback, in score points
+func (p *Planner) FeedbackBoosts() *db.FeedbackBoosts {
+	boosts := p.loadFeedbackBoosts()
+	points := &db.FeedbackBoosts{
+		Projects: make(map[string]float64, len(boosts.Projects)),
+		Senders:  make(map[string]float64, len(boosts.Senders)),
+	}
+	for project, boost := range boosts.Projects {
+		points.Projects[project] = boost * p.config.Planner.FeedbackMaxBoost
+	}
+	for sender, boost := range boosts.Senders {
+		points.Senders[sender] = boost * p.config.Planner.FeedbackMaxBoost
+	}
+	return points
+}
+
+// loadFeedbackBoosts relearns the boosts from priority feedback for the scores that follow
+func (p *Planner) loadFeedbackBoosts() *db.FeedbackBoosts {
+	boosts := &db.FeedbackBoosts{}
+	if p.config.Planner.FeedbackMaxBoost > 0 {
+		learned, err := p.db.GetFeedbackBoosts(time.Now().Add(-feedbackLookback))
+		if err != nil {
+			log.Printf("Failed to learn from priority feedback: %v", err)
+		} else {
+			boosts = learned
+		}
+	}
+
+	p.feedbackMu.Lock()
+	p.feedback = boosts
+	p.feedbackMu.Unlock()
+	return boosts
+}
+
+// feedbackBoost returns the points priority feedback adds to or takes off a task's score
+func (p *Planner) feedbackBoost(task *db.Task) float64 {
+	if p.config.Planner.FeedbackMaxBoost <= 0 {
+		return 0
+	}
+
+	p.feedbackMu.Lock()
+	boosts := p.feedback
+	p.feedbackMu.Unlock()
+	if boosts == nil {
+		boosts = p.loadFeedbackBoosts()
+	}
+
+	var sender string
+	if task.Source == "gmail" && len(boosts.Senders) > 0 {
+		var ok bool
+		if sender, ok = boosts.Threads[task.SourceID]; !ok {
+			// A task newer than the boosts
+			sender, _ = p.db.GetThreadSender(task.SourceID)
+		}
+	}
+	return boosts.For(task.Project, sender) * p.config.Planner.FeedbackMaxBoost
+}
@@ -0,0 +1,113 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// StartFocus starts a focus session. Notifications are held until it ends;
+// minutes <= 0 uses the configured default length.
+func (p *Planner) StartFocus(minutes int) (*db.FocusSession, error) {
+	if minutes <= 0 {
+		minutes = p.config.Focus.DefaultMinutes
+	}
+
+	session, err := p.db.StartFocusSession(time.Duration(minutes) * time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Focus mode on for %d minutes", minutes)
+	return session, nil
+}
+
+// GetFocusSession returns the active focus session, or nil if focus mode is off
+func (p *Planner) GetFocusSession() (*db.FocusSession, error) {
+	return p.db.GetActiveFocusSession()
+}
+
+// StopFocus ends the active focus session early, sends its exit summary and returns it.
+// Returns nil if focus mode was off.
+func (p *Planner) StopFocus(ctx context.Context) (*db.FocusSummary, error) {
+	session, err := p.db.EndFocusSession()
+	if err != nil || session == nil {
+		return nil, err
+	}
+
+	summary, err := p.db.GetFocusSummary(session, p.config.Google.UserEmail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize focus session: %w", err)
+	}
+
+	if err := p.SendFocusSummaries(ctx); err != nil {
+		log.Printf("Failed to send focus summary: %v", err)
+	}
+
+	return summary, nil
+}
+
+// SendFocusSummaries sends the exit summary of every focus session that has ended,
+// then releases the notifications held during it
+func (p *Planner) SendFocusSummaries(ctx context.Context) error {
+	sessions, err := p.db.GetFinishedFocusSessions()
+	if err != nil {
+		return fmt.Errorf("failed to get finished focus sessions: %w", err)
+	}
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	// Another session may have started right after; its notifications stay held
+	if p.db.InFocusMode() {
+		for _, session := range sessions {
+			p.db.MarkFocusSummarySent(session.ID)
+		}
+		return nil
+	}
+
+	for _, session := range sessions {
+		summary, err := p.db.GetFocusSummary(session, p.config.Google.UserEmail)
+		if err != nil {
+			return fmt.Errorf("failed to summarize focus session: %w", err)
+		}
+
+		err = p.notify("focus_summary", briefDelivery{
+			config.ChannelChat:  func() error { return p.google.Chat.SendFocusSummary(ctx, p.db, summary) },
+			config.ChannelSlack: func() error { return p.slack.SendFocusSummary(ctx, p.db, summary) },
+		})
+		if err != nil {
+			log.Printf("Failed to deliver focus summary (queued for retry): %v", err)
+		}
+
+		if err := p.db.MarkFocusSummarySent(session.ID); err != nil {
+			log.Printf("Warning: failed to record focus summary: %v", err)
+		}
+		log.Printf("Focus session ended: %d threads, %d tasks arrived", len(summary.Threads), len(summary.Tasks))
+	}
+
+	p.releaseHeldNotifications(ctx)
+	return nil
+}
+
+// releaseHeldNotifications delivers the notifications queued while focus mode was on
+func (p *Planner) releaseHeldNotifications(ctx context.Context) {
+	if p.google != nil && p.google.Chat != nil {
+		if sent, err := p.google.Chat.RetryPending(ctx, p.db); err != nil {
+			log.Printf("Failed to release held Chat notifications: %v", err)
+		} else if sent > 0 {
+			log.Printf("Delivered %d held Chat message(s)", sent)
+		}
+	}
+	if p.slack != nil {
+		if sent, err := p.slack.RetryPending(ctx, p.db); err != nil {
+			log.Printf("Failed to release held Slack notifications: %v", err)
+		} else if sent > 0 {
+			log.Printf("Delivered %d held Slack message(s)", sent)
+		}
+	}
+}
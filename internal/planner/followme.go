@@ -0,0 +1,105 @@
+package planner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/events"
+)
+
+// alert delivers an urgent notification. When notifications.follow_me covers its kind it goes
+// only to the first channel on the route that can reach the user, so they aren't notified
+// everywhere at once; otherwise it's sent like any other brief. The title and text are used
+// for the TUI and push, which have no formatted message of their own. During quiet hours the TUI
+// and push are skipped, so the alert waits in the Chat or Slack outbox until the quiet ends.
+func (p *Planner) alert(ctx context.Context, kind, title, text string, delivery briefDelivery) error {
+	followMe := p.config.Notify.FollowMe
+	if !followMe.Routes(kind) {
+		return p.notify(kind, delivery)
+	}
+	_, quiet := p.config.Notify.QuietHours.Until(kind, time.Now().In(p.config.Schedule.Location()))
+
+	var errs []error
+	delivered := ""
+	for _, channel := range followMe.Route {
+		var err error
+		switch channel {
+		case config.ChannelTUI:
+			if quiet || !p.userAtTUI() {
+				continue
+			}
+			p.events.Publish(events.Alert, map[string]interface{}{"kind": kind, "title": title, "text": text})
+		case config.ChannelPush:
+			if quiet || p.push == nil {
+				continue
+			}
+			err = p.push.Send(ctx, title, text)
+		default:
+			send, ok := delivery[channel]
+			if !ok || !p.channelReady(channel) {
+				continue
+			}
+			err = send()
+		}
+
+		// Fall through to the next channel rather than leave the user unaware
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+			continue
+		}
+		delivered = channel
+		break
+	}
+
+	err := errors.Join(errs...)
+	if delivered == "" && err == nil {
+		err = fmt.Errorf("no channel on notifications.follow_me.route could reach the user")
+	}
+	if delivered != "" {
+		if err != nil {
+			log.Printf("Delivered %s to %s after earlier channels failed: %v", kind, delivered, err)
+		}
+		err = nil
+	}
+
+	data := map[string]interface{}{"kind": kind, "channel": delivered}
+	if err != nil {
+		data["error"] = err.Error()
+	}
+	p.events.Publish(events.BriefSent, data)
+
+	return err
+}
+
+// userAtTUI reports whether the user has used a remote TUI recently enough to see an alert there
+func (p *Planner) userAtTUI() bool {
+	// Alerts reach remote TUIs through the API's event stream
+	if p.events == nil {
+		return false
+	}
+
+	lastActive, err := p.db.GetTUILastActive()
+	if err != nil {
+		log.Printf("Failed to check TUI presence: %v", err)
+		return false
+	}
+	timeout := time.Duration(p.config.Notify.FollowMe.PresenceSeconds) * time.Second
+	return time.Since(lastActive) < timeout
+}
+
+// firstLine returns the first line of a summary, for alerts that list several items
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	if len(s) > 120 {
+		s = s[:117] + "..."
+	}
+	return s
+}
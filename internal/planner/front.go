@@ -0,0 +1,231 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/front"
+)
+
+// defaultFrontCommentGoal is what a drafted Front comment does when the user doesn't say
+const defaultFrontCommentGoal = "summarize where the conversation stands and what's needed next"
+
+// frontConversation returns the Front conversation linked to a thread
+func (p *Planner) frontConversation(threadID string) (*db.FrontMetadata, error) {
+	if p.front == nil {
+		return nil, fmt.Errorf("Front isn't enabled")
+	}
+	return p.db.GetFrontMetadata(threadID)
+}
+
+// ArchiveFrontConversation archives the Front conversation linked to a thread
+func (p *Planner) ArchiveFrontConversation(ctx context.Context, threadID string) (*db.FrontMetadata, error) {
+	metadata, err := p.frontConversation(threadID)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.front.Archive(ctx, metadata.ConversationID); err != nil {
+		return nil, fmt.Errorf("failed to archive conversation: %w", err)
+	}
+
+	metadata.Status = "archived"
+	return metadata, p.saveFrontMetadata(metadata)
+}
+
+// SnoozeFrontConversation snoozes the Front conversation linked to a thread for some hours, or
+// front.default_snooze_hours when hours is 0, returning when it wakes up
+func (p *Planner) SnoozeFrontConversation(ctx context.Context, threadID string, hours int) (time.Time, error) {
+	metadata, err := p.frontConversation(threadID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if hours <= 0 {
+		hours = p.config.Front.DefaultSnoozeHours
+	}
+	until := time.Now().Add(time.Duration(hours) * time.Hour)
+	if err := p.front.Snooze(ctx, metadata.ConversationID, until); err != nil {
+		return time.Time{}, fmt.Errorf("failed to snooze conversation: %w", err)
+	}
+
+	metadata.Status = "snoozed"
+	return until, p.saveFrontMetadata(metadata)
+}
+
+// AssignFrontConversation assigns the Front conversation linked to a thread to a teammate, given
+// by email, username, name or ID. An empty teammate unassigns it.
+func (p *Planner) AssignFrontConversation(ctx context.Context, threadID, teammate string) (*db.FrontMetadata, error) {
+	metadata, err := p.frontConversation(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	var assignee *front.Teammate
+	if teammate = strings.TrimSpace(teammate); teammate != "" {
+		teammates, err := p.front.GetTeammates(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get teammates: %w", err)
+		}
+		if assignee = findTeammate(teammates, teammate); assignee == nil {
+			return nil, fmt.Errorf("no Front teammate matches %q", teammate)
+		}
+	}
+
+	assigneeID := ""
+	if assignee != nil {
+		assigneeID = assignee.ID
+	}
+	if err := p.front.Assign(ctx, metadata.ConversationID, assigneeID); err != nil {
+		return nil, fmt.Errorf("failed to assign conversation: %w", err)
+	}
+
+	metadata.AssigneeID, metadata.AssigneeName, metadata.Status = "", "", "unassigned"
+	if assignee != nil {
+		metadata.AssigneeID, metadata.AssigneeName, metadata.Status = assignee.ID, assignee.FirstName, "assigned"
+	}
+	return metadata, p.saveFrontMetadata(metadata)
+}
+
+// FrontTeammates returns the teammates Front conversations can be assigned to
+func (p *Planner) FrontTeammates(ctx context.Context) ([]front.Teammate, error) {
+	if p.front == nil {
+		return nil, fmt.Errorf("Front isn't enabled")
+	}
+	return p.front.GetTeammates(ctx)
+}
+
+// DraftFrontComment drafts an internal comment for teammates on the Front conversation linked to
+// a thread. goal says what the comment should do, such as a handover note or a question for the
+// team; when empty it sums up where the conversation stands.
+func (p *Planner) DraftFrontComment(ctx context.Context, threadID, goal string) (string, error) {
+	if _, err := p.frontConversation(threadID); err != nil {
+		return "", err
+	}
+	messages, err := p.db.GetThreadMessages(threadID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get thread: %w", err)
+	}
+	if len(messages) == 0 {
+		return "", fmt.Errorf("thread has no messages")
+	}
+
+	if goal = strings.TrimSpace(goal); goal == "" {
+		goal = defaultFrontCommentGoal
+	}
+	goal = "Write a short internal comment for teammates on this conversation, not a reply to the " +
+		"sender. It should " + goal + "."
+
+	text, err := p.llm.DraftReply(ctx, messages, goal, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to draft comment: %w", err)
+	}
+	return strings.TrimSpace(text), nil
+}
+
+// PostFrontComment posts an internal comment on the Front conversation linked to a thread
+func (p *Planner) PostFrontComment(ctx context.Context, threadID, body string) (*db.FrontComment, error) {
+	if body = strings.TrimSpace(body); body == "" {
+		return nil, fmt.Errorf("comment is empty")
+	}
+	metadata, err := p.frontConversation(threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	posted, err := p.front.AddComment(ctx, metadata.ConversationID, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post comment: %w", err)
+	}
+
+	comment := &db.FrontComment{
+		ID:             posted.ID,
+		ThreadID:       threadID,
+		ConversationID: metadata.ConversationID,
+		AuthorName:     posted.Author.GetFullName(),
+		Body:           body,
+		CreatedAt:      time.Now(),
+	}
+	if posted.CreatedAt > 0 {
+		comment.CreatedAt = time.Unix(posted.CreatedAt, 0)
+	}
+	if err := p.db.SaveFrontComment(comment); err != nil {
+		return nil, fmt.Errorf("failed to save comment: %w", err)
+	}
+	return comment, nil
+}
+
+// ApplyFrontEvent updates the threads linked to a conversation from a Front webhook event: their
+// status, assignee and tags from the conversation's new state, and the comment if one was added.
+// It returns how many threads were updated; events for conversations not linked to a thread are
+// ignored.
+func (p *Planner) ApplyFrontEvent(event *front.Event) (int, error) {
+	conv := event.Conversation
+	if conv == nil || conv.ID == "" {
+		return 0, nil
+	}
+	threadIDs, err := p.db.GetFrontThreadIDs(conv.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find threads for conversation: %w", err)
+	}
+
+	comment := event.Comment()
+	for _, threadID := range threadIDs {
+		metadata, err := p.db.GetFrontMetadata(threadID)
+		if err != nil {
+			return 0, err
+		}
+		if conv.Status != "" {
+			metadata.Status = conv.Status
+		}
+		metadata.AssigneeID, metadata.AssigneeName = "", ""
+		if conv.Assignee != nil {
+			metadata.AssigneeID, metadata.AssigneeName = conv.Assignee.ID, conv.Assignee.Name
+		}
+		metadata.Tags = nil
+		for _, tag := range conv.Tags {
+			metadata.Tags = append(metadata.Tags, tag.Name)
+		}
+		if err := p.saveFrontMetadata(metadata); err != nil {
+			return 0, err
+		}
+
+		if comment != nil {
+			err := p.db.SaveFrontComment(&db.FrontComment{
+				ID:             comment.ID,
+				ThreadID:       threadID,
+				ConversationID: conv.ID,
+				AuthorName:     comment.Author.GetFullName(),
+				Body:           comment.Body,
+				CreatedAt:      time.Unix(comment.CreatedAt, 0),
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to save comment: %w", err)
+			}
+		}
+	}
+	return len(threadIDs), nil
+}
+
+// saveFrontMetadata records a conversation's new state after acting on it
+func (p *Planner) saveFrontMetadata(metadata *db.FrontMetadata) error {
+	metadata.UpdatedAt = time.Now()
+	if err := p.db.SaveFrontMetadata(metadata); err != nil {
+		return fmt.Errorf("failed to save Front metadata: %w", err)
+	}
+	return nil
+}
+
+// findTeammate finds a teammate by ID, email, username or full name, ignoring case
+func findTeammate(teammates []front.Teammate, query string) *front.Teammate {
+	for i := range teammates {
+		t := &teammates[i]
+		for _, key := range []string{t.ID, t.Email, t.Username, t.GetFullName()} {
+			if key != "" && strings.EqualFold(key, query) {
+				return t
+			}
+		}
+	}
+	return nil
+}
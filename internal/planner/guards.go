@@ -0,0 +1,34 @@
+package planner
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+)
+
+// SendGuardAlert warns the user that the database, its disk or a Google API quota is nearing its
+// limit, so they can free space or raise the quota before syncs start failing
+func (p *Planner) SendGuardAlert(ctx context.Context, warnings []string) error {
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	return p.alert(ctx, "guard_alert", "Running short on resources", strings.Join(warnings, "\n"), briefDelivery{
+		config.ChannelChat:  func() error { return p.google.Chat.SendGuardAlert(ctx, p.db, warnings) },
+		config.ChannelSlack: func() error { return p.slack.SendGuardAlert(ctx, p.db, warnings) },
+	})
+}
+
+// SendCanaryAlert tells the user the nightly pipeline canary found a stage failing or running
+// slow, before it shows up as a missing task or an empty brief
+func (p *Planner) SendCanaryAlert(ctx context.Context, problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return p.alert(ctx, "canary_alert", "Pipeline canary failed", strings.Join(problems, "\n"), briefDelivery{
+		config.ChannelChat:  func() error { return p.google.Chat.SendCanaryAlert(ctx, p.db, problems) },
+		config.ChannelSlack: func() error { return p.slack.SendCanaryAlert(ctx, p.db, problems) },
+	})
+}
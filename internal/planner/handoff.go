@@ -0,0 +1,142 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// handoffRelatedLimit caps the related threads and documents listed in a handoff email
+const handoffRelatedLimit = 5
+
+// HandoffTask hands a task off to a colleague. It emails them the task's description, deadline,
+// source thread and related threads and documents, then takes the task off your list and tracks
+// the email on the waiting-on list, so you're nudged if it goes unanswered.
+func (p *Planner) HandoffTask(ctx context.Context, taskID, to, note string) (*db.WaitingItem, error) {
+	if p.google == nil || p.google.Gmail == nil {
+		return nil, fmt.Errorf("gmail is needed to hand off tasks")
+	}
+
+	address, err := mail.ParseAddress(strings.TrimSpace(to))
+	if err != nil {
+		return nil, fmt.Errorf("invalid email address %q", to)
+	}
+
+	task, err := p.db.GetTaskByID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if task.Status != "pending" && task.Status != "in_progress" {
+		return nil, fmt.Errorf("only open tasks can be handed off")
+	}
+
+	subject := "Handing off: " + task.Title
+	body := handoffBody(task, strings.TrimSpace(note), p.handoffRelated(ctx, task), p.config.Schedule.Location())
+	sent, err := p.google.Gmail.SendMessage(ctx, address.Address, subject, body, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to send handoff email: %w", err)
+	}
+
+	// The email is out, so from here failures are logged rather than returned
+	now := time.Now()
+	item := &db.WaitingItem{
+		MessageID:  sent.Id,
+		ThreadID:   sent.ThreadId,
+		Subject:    subject,
+		Recipient:  address.Address,
+		Request:    task.Title,
+		SentAt:     now,
+		ExpectedBy: p.replyDue(now, ""),
+		Status:     db.WaitingOpen,
+	}
+	if task.DueTS != nil && task.DueTS.After(now) {
+		item.ExpectedBy = *task.DueTS
+	}
+	if err := p.db.SaveWaitingItem(item); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	if _, err := p.db.AddTaskComment(taskID, db.OwnerAuthor, "Handed off to "+address.Address); err != nil {
+		log.Printf("Warning: failed to record handoff on task %s: %v", taskID, err)
+	}
+	query := `UPDATE tasks SET status = 'cancelled', updated_at = ? WHERE id = ?`
+	if _, err := p.db.Exec(query, now.Unix(), taskID); err != nil {
+		log.Printf("Warning: failed to close handed-off task %s: %v", taskID, err)
+	}
+
+	log.Printf("Handed off task %s to %s", taskID, address.Address)
+	return item, nil
+}
+
+// handoffRelated returns threads and documents related to a task, leaving out its own thread
+func (p *Planner) handoffRelated(ctx context.Context, task *db.Task) []*db.SearchResult {
+	results, err := p.Search(ctx, task.Title, db.ContentKindThread, db.ContentKindDocument)
+	if err != nil {
+		log.Printf("Failed to find context for handoff of task %s: %v", task.ID, err)
+		return nil
+	}
+
+	var related []*db.SearchResult
+	for _, result := range results {
+		if result.Kind == db.ContentKindThread && task.Source == "gmail" && result.ID == task.SourceID {
+			continue
+		}
+		related = append(related, result)
+		if len(related) == handoffRelatedLimit {
+			break
+		}
+	}
+	return related
+}
+
+// handoffBody writes the plain text handoff email, giving the deadline in the given location
+func handoffBody(task *db.Task, note string, related []*db.SearchResult, location *time.Location) string {
+	var b strings.Builder
+	b.WriteString("Hi,\n\nI'm handing this over to you:\n\n")
+	b.WriteString(task.Title + "\n")
+	if note != "" {
+		b.WriteString("\n" + note + "\n")
+	}
+
+	b.WriteString("\n")
+	if task.DueTS != nil {
+		b.WriteString(fmt.Sprintf("Deadline: %s\n", task.DueTS.In(location).Format("Monday, January 2 at 3:04 PM MST")))
+	}
+	if task.Project != "" {
+		b.WriteString(fmt.Sprintf("Project: %s\n", task.Project))
+	}
+	if task.Stakeholder != "" {
+		b.WriteString(fmt.Sprintf("For: %s\n", task.Stakeholder))
+	}
+
+	if description := strings.TrimSpace(task.Description); description != "" {
+		b.WriteString("\nDetails:\n" + description + "\n")
+	}
+
+	if task.Source == "gmail" && task.SourceID != "" {
+		b.WriteString("\nOriginal thread:\n" + fmt.Sprintf("https://mail.google.com/mail/u/0/#inbox/%s", task.SourceID) + "\n")
+	}
+
+	for _, section := range []struct{ kind, title string }{
+		{db.ContentKindThread, "Related threads"},
+		{db.ContentKindDocument, "Related documents"},
+	} {
+		var lines []string
+		for _, result := range related {
+			if result.Kind == section.kind {
+				lines = append(lines, fmt.Sprintf("- %s: %s", result.Title, result.Link))
+			}
+		}
+		if len(lines) > 0 {
+			b.WriteString("\n" + section.title + ":\n" + strings.Join(lines, "\n") + "\n")
+		}
+	}
+
+	b.WriteString("\nThanks!\n")
+	return b.String()
+}
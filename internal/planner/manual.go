@@ -0,0 +1,177 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// TaskInput holds the fields of a task entered by hand. Urgency is recalculated from the due
+// date when there is one.
+type TaskInput struct {
+	Title       string
+	Description string
+	DueTS       *time.Time
+	Impact      int    // 1-5 (0 = 3)
+	Urgency     int    // 1-5 (0 = 3)
+	Effort      string // S, M or L ("" = M)
+	Project     string
+	Stakeholder string
+}
+
+// TaskInputFrom returns a task's editable fields
+func TaskInputFrom(task *db.Task) TaskInput {
+	return TaskInput{
+		Title:       task.Title,
+		Description: task.Description,
+		DueTS:       task.DueTS,
+		Impact:      task.Impact,
+		Urgency:     task.Urgency,
+		Effort:      task.Effort,
+		Project:     task.Project,
+		Stakeholder: task.Stakeholder,
+	}
+}
+
+// normalize fills in defaults and checks the fields against the tasks table's constraints
+func (in *TaskInput) normalize() error {
+	in.Title = strings.TrimSpace(in.Title)
+	in.Project = strings.TrimSpace(in.Project)
+	in.Stakeholder = strings.TrimSpace(in.Stakeholder)
+	in.Effort = strings.ToUpper(strings.TrimSpace(in.Effort))
+
+	if in.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if in.Impact == 0 {
+		in.Impact = 3
+	}
+	if in.Urgency == 0 {
+		in.Urgency = 3
+	}
+	if in.Effort == "" {
+		in.Effort = "M"
+	}
+
+	if in.Impact < 1 || in.Impact > 5 {
+		return fmt.Errorf("impact must be between 1 and 5")
+	}
+	if in.Urgency < 1 || in.Urgency > 5 {
+		return fmt.Errorf("urgency must be between 1 and 5")
+	}
+	if in.Effort != "S" && in.Effort != "M" && in.Effort != "L" {
+		return fmt.Errorf("effort must be S, M or L")
+	}
+	return nil
+}
+
+// CreateTask saves and scores a task entered by hand, for ad-hoc work that never arrived by
+// email
+func (p *Planner) CreateTask(ctx context.Context, in TaskInput) (*db.Task, error) {
+	return p.CreateTaskFrom(ctx, "manual", "", in)
+}
+
+// CreateTaskFrom saves and scores a task entered by hand in another app, e.g. a CalDAV task
+// app, remembering the app's ID for it
+func (p *Planner) CreateTaskFrom(ctx context.Context, source, sourceID string, in TaskInput) (*db.Task, error) {
+	if err := in.normalize(); err != nil {
+		return nil, err
+	}
+
+	task := &db.Task{
+		ID:          fmt.Sprintf("%s_%d", source, time.Now().UnixNano()),
+		Source:      source,
+		SourceID:    sourceID,
+		Title:       in.Title,
+		Description: in.Description,
+		DueTS:       in.DueTS,
+		Project:     in.Project,
+		Stakeholder: in.Stakeholder,
+		Impact:      in.Impact,
+		Urgency:     in.Urgency,
+		Effort:      in.Effort,
+		Status:      "pending",
+	}
+	if err := p.db.SaveTask(task); err != nil {
+		return nil, fmt.Errorf("failed to save task: %w", err)
+	}
+
+	if err := p.PrioritizeTask(ctx, task); err != nil {
+		log.Printf("Failed to prioritize task '%s': %v", task.Title, err)
+	}
+
+	log.Printf("Created task: %s", task.Title)
+	return task, nil
+}
+
+// UpdateTask replaces a task's editable fields and rescores it
+func (p *Planner) UpdateTask(ctx context.Context, taskID string, in TaskInput) (*db.Task, error) {
+	if err := in.normalize(); err != nil {
+		return nil, err
+	}
+
+	task, err := p.db.GetTaskByID(taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	edited := editedFields(task, in)
+	task.Title = in.Title
+	task.Description = in.Description
+	task.DueTS = in.DueTS
+	task.Project = in.Project
+	task.Stakeholder = in.Stakeholder
+	task.Impact = in.Impact
+	task.Urgency = in.Urgency
+	task.Effort = in.Effort
+	if err := p.db.SaveTask(task); err != nil {
+		return nil, fmt.Errorf("failed to update task: %w", err)
+	}
+
+	// due_ts is indexed, so SaveTask's upsert can't change it
+	var dueTS interface{}
+	if in.DueTS != nil {
+		dueTS = in.DueTS.Unix()
+	}
+	if _, err := p.db.Exec(`UPDATE tasks SET due_ts = ?, updated_at = ? WHERE id = ?`, dueTS, time.Now().Unix(), taskID); err != nil {
+		return nil, fmt.Errorf("failed to update task due date: %w", err)
+	}
+
+	// Remembered so extracting the task again doesn't undo the edit
+	if err := p.db.SaveTaskEdits(taskID, edited); err != nil {
+		log.Printf("Failed to record edits to '%s': %v", task.Title, err)
+	}
+
+	if err := p.PrioritizeTask(ctx, task); err != nil {
+		log.Printf("Failed to prioritize task '%s': %v", task.Title, err)
+	}
+
+	return task, nil
+}
+
+// editedFields returns the fields an edit changes. Due dates aren't included, since
+// re-extraction never changes them.
+func editedFields(task *db.Task, in TaskInput) []string {
+	var fields []string
+	for _, f := range []struct {
+		field   string
+		changed bool
+	}{
+		{db.EditTitle, in.Title != task.Title},
+		{db.EditDescription, in.Description != task.Description},
+		{db.EditProject, in.Project != task.Project},
+		{db.CorrectionStakeholder, in.Stakeholder != task.Stakeholder},
+		{db.CorrectionImpact, in.Impact != task.Impact},
+		{db.EditUrgency, in.Urgency != task.Urgency},
+		{db.EditEffort, in.Effort != task.Effort},
+	} {
+		if f.changed {
+			fields = append(fields, f.field)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,121 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+const (
+	maxMeetingPrepsPerRun  = 5 // Prep notes generated per run, to spread LLM calls out
+	meetingPrepContextSize = 5 // Related documents and threads given to the LLM
+)
+
+// PrepareMeetings generates prep notes for meetings starting within meetings.prep_hours_ahead and
+// sends each meeting's notes meetings.prep_send_minutes before it starts
+func (p *Planner) PrepareMeetings(ctx context.Context) error {
+	window := time.Duration(p.config.Meetings.PrepHoursAhead) * time.Hour
+	events, err := p.db.GetEventsNeedingPrep(window)
+	if err != nil {
+		return err
+	}
+
+	generated := 0
+	for _, event := range events {
+		if generated >= maxMeetingPrepsPerRun {
+			break
+		}
+
+		// Focus blocks and other events without anyone else get no prep
+		if !p.hasOtherAttendees(event) {
+			if err := p.db.SaveMeetingPrep(event.ID, ""); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+			continue
+		}
+
+		if err := p.generateMeetingPrep(ctx, event); err != nil {
+			log.Printf("Failed to generate prep for %s: %v", event.Title, err)
+			continue
+		}
+		generated++
+	}
+
+	sendLead := time.Duration(p.config.Meetings.PrepSendMinutes) * time.Minute
+	preps, err := p.db.GetMeetingPrepsToSend(sendLead)
+	if err != nil {
+		return err
+	}
+
+	sent := 0
+	for _, prep := range preps {
+		if err := p.sendMeetingPrep(ctx, prep); err != nil {
+			log.Printf("Failed to send prep for %s: %v", prep.Event.Title, err)
+			continue
+		}
+		sent++
+	}
+
+	if generated > 0 || sent > 0 {
+		log.Printf("Meeting prep: %d generated, %d sent", generated, sent)
+		p.db.LogUsage("planner", "meeting_prep", 0, 0, 0, nil)
+	}
+	return nil
+}
+
+// generateMeetingPrep writes prep notes for a meeting from its related documents and threads
+func (p *Planner) generateMeetingPrep(ctx context.Context, event *db.Event) error {
+	meetingContext, err := p.db.GetMeetingContext(event, p.config.Google.UserEmail, meetingPrepContextSize)
+	if err != nil {
+		return err
+	}
+
+	notes, err := p.llm.GenerateMeetingPrep(ctx, event, meetingContext.Documents, meetingContext.Threads)
+	if err != nil {
+		return err
+	}
+	notes = strings.TrimSpace(notes)
+	if notes == "" {
+		return fmt.Errorf("empty prep notes")
+	}
+
+	return p.db.SaveMeetingPrep(event.ID, notes)
+}
+
+// sendMeetingPrep delivers a meeting's prep notes with links to its related documents
+func (p *Planner) sendMeetingPrep(ctx context.Context, prep *db.MeetingPrep) error {
+	meetingContext, err := p.db.GetMeetingContext(prep.Event, p.config.Google.UserEmail, meetingPrepContextSize)
+	if err != nil {
+		return err
+	}
+	docs := meetingContext.Documents
+
+	title := fmt.Sprintf("Prep for %s at %s", prep.Event.Title, prep.Event.StartTS.Format("3:04 PM"))
+	err = p.alert(ctx, "meeting_prep", title, prep.Notes, briefDelivery{
+		config.ChannelChat:  func() error { return p.google.Chat.SendMeetingPrep(ctx, p.db, prep, docs) },
+		config.ChannelSlack: func() error { return p.slack.SendMeetingPrep(ctx, p.db, prep, docs) },
+	})
+
+	// Failed deliveries stay queued in the outbox, so mark the prep sent either way to avoid repeats
+	if markErr := p.db.MarkMeetingPrepSent(prep.Event.ID); markErr != nil {
+		log.Printf("Warning: %v", markErr)
+	}
+	return err
+}
+
+// hasOtherAttendees reports whether anyone besides the user is invited to an event
+func (p *Planner) hasOtherAttendees(event *db.Event) bool {
+	userEmail := strings.ToLower(p.config.Google.UserEmail)
+	for _, attendee := range event.Attendees {
+		attendee = strings.ToLower(strings.TrimSpace(attendee))
+		if attendee != "" && attendee != userEmail && !strings.HasSuffix(attendee, "calendar.google.com") {
+			return true
+		}
+	}
+	return false
+}
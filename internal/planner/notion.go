@@ -0,0 +1,20 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+)
+
+// ExportTasksToNotion mirrors the top notion.max_tasks pending tasks into the Notion tasks
+// database, so teammates can see what the user is committed to
+func (p *Planner) ExportTasksToNotion(ctx context.Context) error {
+	if p.notion == nil {
+		return nil
+	}
+
+	tasks, err := p.db.GetPendingTasks(p.config.Notion.MaxTasks)
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+	return p.notion.SyncTasks(ctx, tasks)
+}
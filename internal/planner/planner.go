@@ -2,62 +2,278 @@ package planner
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/alexrabarts/focus-agent/internal/config"
 	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/embeddings"
+	"github.com/alexrabarts/focus-agent/internal/events"
+	"github.com/alexrabarts/focus-agent/internal/front"
 	"github.com/alexrabarts/focus-agent/internal/google"
 	"github.com/alexrabarts/focus-agent/internal/llm"
+	"github.com/alexrabarts/focus-agent/internal/notion"
+	"github.com/alexrabarts/focus-agent/internal/push"
+	"github.com/alexrabarts/focus-agent/internal/slack"
 )
 
+// maxBriefSummaryChanges caps the threads whose summary changes are called out in a daily brief
+const maxBriefSummaryChanges = 5
+
 // Planner handles task prioritization and planning
 type Planner struct {
 	db     *db.DB
 	google *google.Clients
+	slack  *slack.Client  // Slack client (nil unless slack is a notification channel)
+	push   *push.Client   // Mobile push client (nil unless push.url is set)
+	notion *notion.Client // Notion exporter (nil unless notion.enabled)
+	front  *front.Client  // Front client for conversation actions (nil unless front.enabled)
 	llm    llm.Client
 	config *config.Config
+	events *events.Bus // Event bus for /api/events (nil if not serving the API)
+
+	embeddings *embeddings.Client // Embeddings client for question answering
+
+	warmMu    sync.Mutex
+	warmTimer *time.Timer // Pending re-evaluation after a priority edit
+
+	scoredMu      sync.Mutex
+	scoredVersion string // Priorities hash, feedback boosts, weights and effort factors of the last full rescore
+
+	feedbackMu sync.Mutex
+	feedback   *db.FeedbackBoosts // Boosts learned from priority feedback, reloaded on each rescore
+
+	calibrationMu sync.Mutex
+	calibration   *db.EffortCalibration // Effort factors learned from completion history, reloaded on each rescore
+
+	scoringMu      sync.Mutex
+	scoringName    string                // Scoring profile in use, reloaded on each rescore
+	scoringWeights config.ScoringWeights // Weights of the scoring profile in use
+
+	prefilterMu      sync.Mutex
+	prefilterHash    string           // Priorities hash the priority embeddings are for
+	prefilterVectors []priorityVector // Embedding of each priority, for the alignment pre-filter
 }
 
 // New creates a new planner
 func New(database *db.DB, googleClients *google.Clients, llmClient llm.Client, cfg *config.Config) *Planner {
-	return &Planner{
-		db:     database,
-		google: googleClients,
-		llm:    llmClient,
-		config: cfg,
+	var slackClient *slack.Client
+	if cfg.Notify.HasChannel(config.ChannelSlack) {
+		slackClient = slack.NewClient(cfg)
+	}
+
+	ollamaURL := "http://localhost:11434"
+	if len(cfg.Ollama.Hosts) > 0 {
+		ollamaURL = cfg.Ollama.Hosts[0].URL
 	}
+
+	p := &Planner{
+		db:         database,
+		google:     googleClients,
+		slack:      slackClient,
+		push:       push.NewClient(cfg),
+		notion:     notion.NewClient(cfg),
+		llm:        llmClient,
+		config:     cfg,
+		embeddings: embeddings.NewClient(ollamaURL, "nomic-embed-text"),
+	}
+	p.loadTimezone()
+	return p
+}
+
+// SetEvents sets the bus that delivery events are published to
+func (p *Planner) SetEvents(bus *events.Bus) {
+	p.events = bus
+}
+
+// SetFront sets the client used to act on Front conversations
+func (p *Planner) SetFront(client *front.Client) {
+	p.front = client
 }
 
-// PrioritizeTasks recalculates scores for all pending tasks
+// briefDelivery maps each delivery channel to the function that sends a brief there. Channels
+// a kind of brief has no format for are left out.
+type briefDelivery map[string]func() error
+
+// notify sends a brief to each channel configured for its kind, in configured order.
+// Every channel is attempted even if an earlier one fails.
+func (p *Planner) notify(kind string, delivery briefDelivery) error {
+	var errs []error
+
+	for _, channel := range p.config.Notify.ChannelsFor(kind) {
+		send, ok := delivery[channel]
+		if !ok || !p.channelReady(channel) {
+			continue
+		}
+		err := send()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+		}
+		// Chat and Slack messages go through the outbox; emails are added to its history here
+		if channel == config.ChannelEmail {
+			if recordErr := p.db.RecordDelivery(channel, kind, err); recordErr != nil {
+				log.Printf("Warning: %v", recordErr)
+			}
+		}
+	}
+
+	err := errors.Join(errs...)
+
+	data := map[string]interface{}{"kind": kind}
+	if err != nil {
+		data["error"] = err.Error()
+	}
+	p.events.Publish(events.BriefSent, data)
+
+	return err
+}
+
+// channelReady reports whether the client a delivery channel sends with was set up
+func (p *Planner) channelReady(channel string) bool {
+	switch channel {
+	case config.ChannelChat:
+		return p.google != nil && p.google.Chat != nil
+	case config.ChannelSlack:
+		return p.slack != nil
+	case config.ChannelEmail:
+		return p.google != nil && p.google.Gmail != nil
+	}
+	return false
+}
+
+// briefEmailTo returns the address emailed briefs are sent to
+func (p *Planner) briefEmailTo() string {
+	if p.config.Notify.EmailTo != "" {
+		return p.config.Notify.EmailTo
+	}
+	return p.config.Google.UserEmail
+}
+
+// DeliverDailyBrief sends the daily brief to every configured channel, along with recurring
+// tasks coming up in the next week, meetings moved or cancelled since the last brief, notable
+// changes to thread summaries, documents related to the priorities edited since the last brief,
+// outstanding commitments and warnings about today's calendar
+func (p *Planner) DeliverDailyBrief(ctx context.Context, tasks []*db.Task, events []*db.Event) error {
+	now := p.config.Schedule.Now()
+	upcoming := p.upcomingRecurrences(tasks)
+	commitments := p.outstandingCommitments()
+	warnings := p.calendarWarnings(now)
+	docs := p.documentUpdates(ctx, now)
+
+	changes, err := p.db.GetUnreportedEventChanges()
+	if err != nil {
+		log.Printf("Failed to get calendar changes: %v", err)
+	}
+
+	threadChanges, err := p.db.GetUnreportedSummaryChanges(maxBriefSummaryChanges)
+	if err != nil {
+		log.Printf("Failed to get thread summary changes: %v", err)
+	}
+
+	err = p.notify("daily_brief", briefDelivery{
+		config.ChannelChat: func() error {
+			return p.google.Chat.SendDailyBrief(ctx, p.db, tasks, events, upcoming, changes, threadChanges, docs, commitments, warnings)
+		},
+		config.ChannelSlack: func() error {
+			return p.slack.SendDailyBrief(ctx, p.db, tasks, events, upcoming, changes, threadChanges, docs, commitments, warnings)
+		},
+		config.ChannelEmail: func() error {
+			return p.google.Gmail.SendDailyBriefEmail(ctx, p.briefEmailTo(), tasks, events, upcoming, changes, threadChanges, docs, commitments, warnings)
+		},
+	})
+	if err != nil {
+		return err
+	}
+	p.markDocumentUpdatesReported(now)
+
+	if len(threadChanges) > 0 {
+		threadIDs := make([]string, 0, len(threadChanges))
+		for _, change := range threadChanges {
+			threadIDs = append(threadIDs, change.ThreadID)
+		}
+		if err := p.db.MarkSummaryChangesReported(threadIDs); err != nil {
+			log.Printf("Failed to mark thread summary changes reported: %v", err)
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	eventIDs := make([]string, 0, len(changes))
+	for _, change := range changes {
+		eventIDs = append(eventIDs, change.EventID)
+	}
+	if err := p.db.MarkEventChangesReported(eventIDs); err != nil {
+		log.Printf("Failed to mark calendar changes reported: %v", err)
+	}
+	return nil
+}
+
+// PrioritizeTasks recalculates scores for pending tasks. Only tasks that are new, were updated
+// or crossed a due date urgency band since they were last scored are rescored, unless the
+// priorities, the boosts learned from priority feedback, the scoring weights or the effort
+// calibration changed since the last full rescore (always the case on the first run). Strategic
+// alignment is only re-evaluated for tasks whose title, description, project, stakeholder or the
+// priorities have changed since their last evaluation; those not settled by embedding similarity
+// go to the LLM in batches.
 func (p *Planner) PrioritizeTasks(ctx context.Context) error {
-	// Get all pending tasks
+	priorities := p.GetPriorities()
+	prioritiesHash := priorities.Hash()
+	_, weights := p.loadScoringProfile()
+	version := prioritiesHash + p.loadFeedbackBoosts().Version() + weights.String() + p.loadEffortCalibration().Version()
+
+	p.scoredMu.Lock()
+	full := p.scoredVersion != version
+	p.scoredMu.Unlock()
+
+	now := time.Now()
+
+	// Get pending tasks, or just the dirty ones when the priorities haven't changed
 	query := `
 		SELECT id, source, source_id, title, description, due_ts, project,
-		       impact, urgency, effort, stakeholder, status
+		       impact, urgency, effort, stakeholder, status,
+		       COALESCE(matched_priorities, ''), COALESCE(strategic_score, 0), COALESCE(alignment_hash, '')
 		FROM tasks
 		WHERE status = 'pending'
 	`
+	var args []interface{}
+	if !full {
+		conditions := []string{"last_scored_at IS NULL", "updated_at >= last_scored_at"}
+		for _, boundary := range urgencyBoundaries {
+			secs := int64(boundary.Seconds())
+			conditions = append(conditions, "(due_ts - ? > last_scored_at AND due_ts - ? <= ?)")
+			args = append(args, secs, secs, now.Unix())
+		}
+		query += " AND (" + strings.Join(conditions, " OR ") + ")"
+	}
 
-	rows, err := p.db.Query(query)
+	rows, err := p.db.Query(query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to query tasks: %w", err)
 	}
-	defer rows.Close()
 
 	var tasks []*db.Task
+	strategicScores := make(map[string]float64)
+	alignmentHashes := make(map[string]string)
+	var stale []*db.Task
 	for rows.Next() {
 		task := &db.Task{}
 		var dueTS *int64
+		var strategicScore float64
+		var storedHash string
 
 		err := rows.Scan(
 			&task.ID, &task.Source, &task.SourceID, &task.Title,
 			&task.Description, &dueTS, &task.Project,
 			&task.Impact, &task.Urgency, &task.Effort, &task.Stakeholder,
-			&task.Status,
+			&task.Status, &task.MatchedPriorities, &strategicScore, &storedHash,
 		)
 		if err != nil {
 			log.Printf("Failed to scan task: %v", err)
@@ -72,35 +288,110 @@ func (p *Planner) PrioritizeTasks(ctx context.Context) error {
 			task.Urgency = p.calculateUrgencyFromDue(t)
 		}
 
-		// Get strategic alignment and matched priorities (single LLM call)
-		strategicScore, matches := p.CalculateStrategicAlignmentWithMatches(task)
+		hash := alignmentHash(task, prioritiesHash)
+		if hash == storedHash {
+			strategicScores[task.ID] = strategicScore
+			alignmentHashes[task.ID] = hash
+		} else {
+			stale = append(stale, task)
+		}
+		tasks = append(tasks, task)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	// Evaluate strategic alignment for new and changed tasks. Tasks clearly related or unrelated
+	// to the priorities by embedding similarity are settled without the LLM.
+	results := p.prefilterAlignment(ctx, stale, priorities)
+	var ambiguous []*db.Task
+	for _, task := range stale {
+		if results[task.ID] == nil {
+			ambiguous = append(ambiguous, task)
+		}
+	}
+
+	batchSize := max(p.config.Planner.AlignmentBatchSize, 1)
+	for start := 0; start < len(ambiguous); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch := ambiguous[start:min(start+batchSize, len(ambiguous))]
+
+		batchResults, err := p.llm.EvaluateStrategicAlignmentBatch(ctx, batch, priorities)
+		if err != nil {
+			log.Printf("Failed to evaluate strategic alignment for some tasks: %v", err)
+		}
+		for i, task := range batch {
+			if i < len(batchResults) && batchResults[i] != nil {
+				results[task.ID] = batchResults[i]
+			}
+		}
+	}
 
-		// Calculate score using pre-calculated strategic score (avoids double LLM call)
-		task.Score = p.calculateScoreWithStrategic(task, strategicScore)
+	for _, task := range stale {
+		result := results[task.ID]
+		score, matches := p.priorityMatches(task, result, priorities)
+		strategicScores[task.ID] = score
+		if result != nil {
+			// Tasks that failed keep no hash, so they're evaluated again next time
+			alignmentHashes[task.ID] = alignmentHash(task, prioritiesHash)
+		}
 
-		// Store matched priorities
 		matchesJSON, err := json.Marshal(matches)
 		if err != nil {
 			log.Printf("Failed to marshal matched priorities: %v", err)
 			matchesJSON = []byte("{}")
 		}
 		task.MatchedPriorities = string(matchesJSON)
-
-		tasks = append(tasks, task)
 	}
 
 	// Update scores and matched priorities in database
 	for _, task := range tasks {
-		updateQuery := `UPDATE tasks SET score = ?, urgency = ?, matched_priorities = ? WHERE id = ?`
-		if _, err := p.db.Exec(updateQuery, task.Score, task.Urgency, task.MatchedPriorities, task.ID); err != nil {
+		// Calculate score using the strategic score (avoids another LLM call)
+		task.Score = p.calculateScoreWithStrategic(task, strategicScores[task.ID])
+
+		// Tasks whose alignment couldn't be evaluated stay dirty so the next run picks them up
+		var scoredAt *int64
+		if alignmentHashes[task.ID] != "" {
+			ts := now.Unix()
+			scoredAt = &ts
+		}
+
+		updateQuery := `
+			UPDATE tasks SET score = ?, urgency = ?, matched_priorities = ?, strategic_score = ?,
+			       alignment_hash = NULLIF(?, ''), last_scored_at = ?
+			WHERE id = ?
+		`
+		if _, err := p.db.Exec(updateQuery, task.Score, task.Urgency, task.MatchedPriorities,
+			strategicScores[task.ID], alignmentHashes[task.ID], scoredAt, task.ID); err != nil {
 			log.Printf("Failed to update task score: %v", err)
 		}
 	}
 
-	log.Printf("Prioritized %d tasks", len(tasks))
+	if full {
+		p.scoredMu.Lock()
+		p.scoredVersion = version
+		p.scoredMu.Unlock()
+		log.Printf("Prioritized all %d pending tasks (%d re-evaluated for strategic alignment)", len(tasks), len(stale))
+	} else {
+		log.Printf("Prioritized %d changed tasks (%d re-evaluated for strategic alignment)", len(tasks), len(stale))
+	}
 	return nil
 }
 
+// alignmentHash identifies what a task's strategic alignment was evaluated on: the task fields
+// in the prompt and the version of the priorities
+func alignmentHash(task *db.Task, prioritiesHash string) string {
+	h := sha256.New()
+	for _, field := range []string{task.Title, task.Description, task.Project, task.Stakeholder, prioritiesHash} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))[:16]
+}
+
 // PrioritizeTask scores a single task immediately (used during extraction)
 func (p *Planner) PrioritizeTask(ctx context.Context, task *db.Task) error {
 	// Update urgency based on due date if present
@@ -123,8 +414,8 @@ func (p *Planner) PrioritizeTask(ctx context.Context, task *db.Task) error {
 	task.MatchedPriorities = string(matchesJSON)
 
 	// Update score, urgency, and matched priorities in database
-	updateQuery := `UPDATE tasks SET score = ?, urgency = ?, matched_priorities = ? WHERE id = ?`
-	if _, err := p.db.Exec(updateQuery, task.Score, task.Urgency, task.MatchedPriorities, task.ID); err != nil {
+	updateQuery := `UPDATE tasks SET score = ?, urgency = ?, matched_priorities = ?, strategic_score = ?, last_scored_at = ? WHERE id = ?`
+	if _, err := p.db.Exec(updateQuery, task.Score, task.Urgency, task.MatchedPriorities, strategicScore, time.Now().Unix(), task.ID); err != nil {
 		return fmt.Errorf("failed to update task score: %w", err)
 	}
 
@@ -138,54 +429,17 @@ func (p *Planner) calculateScore(task *db.Task) float64 {
 	return p.calculateScoreWithStrategic(task, strategicScore)
 }
 
-// calculateScoreWithStrategic implements the scoring formula with a pre-calculated strategic score
+// calculateScoreWithStrategic implements the scoring formula with a pre-calculated strategic
+// score, using the weights of the scoring profile in use
 func (p *Planner) calculateScoreWithStrategic(task *db.Task, strategicScore float64) float64 {
-	// Default values if not set
-	impact := float64(task.Impact)
-	if impact == 0 {
-		impact = 3
-	}
-
-	urgency := float64(task.Urgency)
-	if urgency == 0 {
-		urgency = 3
-	}
-
-	// Effort factor (S=0.5, M=1.0, L=1.5)
-	effortFactor := 1.0
-	switch task.Effort {
-	case "S":
-		effortFactor = 0.5
-	case "L":
-		effortFactor = 1.5
-	}
-
-	// Stakeholder weight (internal=1.0, external=1.5, executive=2.0)
-	stakeholderWeight := 1.0
-	switch task.Stakeholder {
-	case "external":
-		stakeholderWeight = 1.5
-	case "executive":
-		stakeholderWeight = 2.0
-	}
-
-	// Apply formula: score = 0.3*strategic + 0.25*urgency + 0.2*impact + 0.15*stakeholder - 0.1*effort
-	rawScore := 0.3*strategicScore +
-		0.25*urgency +
-		0.2*impact +
-		0.15*stakeholderWeight -
-		0.1*effortFactor
-
-	// Clamp to valid range
-	if rawScore > 4.0 {
-		rawScore = 4.0
-	} else if rawScore < 0 {
-		rawScore = 0
-	}
-
-	// Convert to percentage (0-100) and round to whole number
-	percentage := (rawScore / 4.0) * 100.0
-	return float64(int(percentage + 0.5)) // Round to nearest integer
+	_, weights := p.ScoringProfile()
+	factors := TaskScoreFactors(task, strategicScore)
+	factors.Effort *= p.effortFactor(task)
+	score := factors.Score(weights)
+
+	// Adjust for priority feedback and round to whole number
+	score = min(max(score+p.feedbackBoost(task), 0), 100)
+	return float64(int(score + 0.5)) // Round to nearest integer
 }
 
 // calculateStrategicAlignment scores how well a task aligns with strategic priorities
@@ -220,29 +474,46 @@ func (p *Planner) GetPriorities() *config.Priorities {
 	return priorities
 }
 
-// SavePriorities saves priorities to the database
+// SavePriorities saves priorities to the database and re-evaluates the top tasks against them
 func (p *Planner) SavePriorities(priorities *config.Priorities) error {
-	return p.db.UpdatePriorities(priorities)
+	if err := p.db.UpdatePriorities(priorities); err != nil {
+		return err
+	}
+	p.WarmAlignment()
+	return nil
 }
 
 // CalculateStrategicAlignmentWithMatches scores alignment and returns which priorities matched
 // Uses LLM for semantic understanding rather than keyword matching
 func (p *Planner) CalculateStrategicAlignmentWithMatches(task *db.Task) (float64, *db.PriorityMatches) {
+	// Get priorities (database-first, config fallback)
+	priorities := p.GetPriorities()
+
+	// Settle clear cases by embedding similarity, and use the LLM for the rest
+	ctx := context.Background()
+	result := p.prefilterAlignment(ctx, []*db.Task{task}, priorities)[task.ID]
+	if result == nil {
+		var err error
+		result, err = p.llm.EvaluateStrategicAlignment(ctx, task, priorities)
+		if err != nil {
+			log.Printf("Failed to evaluate strategic alignment for task %s: %v", task.ID, err)
+			// Fall back to zero score if LLM fails
+			result = nil
+		}
+	}
+
+	return p.priorityMatches(task, result, priorities)
+}
+
+// priorityMatches converts an LLM strategic alignment result to a score and PriorityMatches.
+// A nil result (the LLM failed) scores zero with no matches.
+func (p *Planner) priorityMatches(task *db.Task, result *llm.StrategicAlignmentResult, priorities *config.Priorities) (float64, *db.PriorityMatches) {
 	matches := &db.PriorityMatches{
 		OKRs:       []string{},
 		FocusAreas: []string{},
 		Projects:   []string{},
 	}
-
-	// Get priorities (database-first, config fallback)
-	priorities := p.GetPriorities()
-
-	// Use LLM to evaluate strategic alignment
-	ctx := context.Background()
-	result, err := p.llm.EvaluateStrategicAlignment(ctx, task, priorities)
-	if err != nil {
-		log.Printf("Failed to evaluate strategic alignment for task %s: %v", task.ID, err)
-		// Fall back to zero score if LLM fails
+	if result == nil {
 		return 0.0, matches
 	}
 
@@ -265,6 +536,10 @@ func (p *Planner) CalculateStrategicAlignmentWithMatches(task *db.Task) (float64
 	return result.Score, matches
 }
 
+// urgencyBoundaries are the times before a due date at which calculateUrgencyFromDue moves a task
+// into a more urgent band
+var urgencyBoundaries = []time.Duration{720 * time.Hour, 168 * time.Hour, 72 * time.Hour, 24 * time.Hour}
+
 // calculateUrgencyFromDue calculates urgency based on due date
 func (p *Planner) calculateUrgencyFromDue(dueDate time.Time) int {
 	hoursUntil := time.Until(dueDate).Hours()
@@ -285,12 +560,28 @@ func (p *Planner) calculateUrgencyFromDue(dueDate time.Time) int {
 	}
 }
 
+// BriefTasks returns the tasks the daily brief leads with: the top-scored pending tasks, less
+// the low-priority ones that wait for the weekly digest
+func (p *Planner) BriefTasks() ([]*db.Task, error) {
+	tasks, err := p.db.GetPendingTasks(p.config.Planner.MaxTasksPerBrief)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	return p.aboveDigestThreshold(tasks), nil
+}
+
 // GenerateDailyBrief generates and sends the daily brief
 func (p *Planner) GenerateDailyBrief(ctx context.Context) error {
-	// Get top priority tasks
-	tasks, err := p.db.GetPendingTasks(p.config.Planner.MaxTasksPerBrief)
+	// Plan today's focus blocks before the brief goes out
+	if p.config.Planner.TimeBlocking.Enabled {
+		if _, err := p.ScheduleTimeBlocks(ctx); err != nil {
+			log.Printf("Failed to plan time blocks: %v", err)
+		}
+	}
+
+	tasks, err := p.BriefTasks()
 	if err != nil {
-		return fmt.Errorf("failed to get tasks: %w", err)
+		return err
 	}
 
 	// Get today's events
@@ -299,21 +590,30 @@ func (p *Planner) GenerateDailyBrief(ctx context.Context) error {
 		return fmt.Errorf("failed to get events: %w", err)
 	}
 
-	// Send to Chat
-	if err := p.google.Chat.SendDailyBrief(ctx, tasks, events); err != nil {
+	// Send to the configured channels
+	if err := p.DeliverDailyBrief(ctx, tasks, events); err != nil {
 		return fmt.Errorf("failed to send brief: %w", err)
 	}
 
 	// Log the brief generation
 	p.db.LogUsage("planner", "daily_brief", 0, 0, 0, nil)
 
+	// Mirror the brief to Notion for teammates
+	if p.notion != nil {
+		err := p.notion.ExportDailyBrief(ctx, p.config.Schedule.Now(), tasks, events)
+		if err != nil {
+			log.Printf("Failed to export daily brief to Notion: %v", err)
+		}
+		p.db.TrackResult("notion", "export_brief", err)
+	}
+
 	return nil
 }
 
 // GenerateReplanBrief generates and sends the midday replan brief
 func (p *Planner) GenerateReplanBrief(ctx context.Context) error {
 	// Get completed tasks count for today
-	now := time.Now()
+	now := p.config.Schedule.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
 	var completedCount int
@@ -341,7 +641,15 @@ func (p *Planner) GenerateReplanBrief(ctx context.Context) error {
 	}
 
 	// Send replan brief
-	if err := p.google.Chat.SendReplanBrief(ctx, completedCount, remainingTasks, afternoonEvents); err != nil {
+	err = p.notify("replan_brief", briefDelivery{
+		config.ChannelChat: func() error {
+			return p.google.Chat.SendReplanBrief(ctx, p.db, completedCount, remainingTasks, afternoonEvents)
+		},
+		config.ChannelSlack: func() error {
+			return p.slack.SendReplanBrief(ctx, p.db, completedCount, remainingTasks, afternoonEvents)
+		},
+	})
+	if err != nil {
 		return fmt.Errorf("failed to send replan brief: %w", err)
 	}
 
@@ -351,8 +659,26 @@ func (p *Planner) GenerateReplanBrief(ctx context.Context) error {
 	return nil
 }
 
-// CheckFollowUps checks for threads needing follow-up
+// CheckFollowUps checks for threads needing follow-up and, with waiting_on enabled, for
+// requests the user sent that are still waiting on a reply, and with commitments enabled, for
+// promises the user made in sent mail
 func (p *Planner) CheckFollowUps(ctx context.Context) error {
+	if err := p.checkThreadFollowUps(ctx); err != nil {
+		return err
+	}
+	if p.config.WaitingOn.Enabled {
+		if err := p.CheckWaitingOn(ctx); err != nil {
+			return err
+		}
+	}
+	if p.config.Commitments.Enabled {
+		return p.CheckCommitments(ctx)
+	}
+	return nil
+}
+
+// checkThreadFollowUps reminds the user about threads whose follow-up time has come
+func (p *Planner) checkThreadFollowUps(ctx context.Context) error {
 	// Get threads with follow-ups due
 	query := `
 		SELECT id, summary FROM threads
@@ -383,7 +709,16 @@ func (p *Planner) CheckFollowUps(ctx context.Context) error {
 	}
 
 	// Send follow-up reminder
-	if err := p.google.Chat.SendFollowUpReminder(ctx, threads); err != nil {
+	var text strings.Builder
+	for _, thread := range threads {
+		text.WriteString("• " + firstLine(thread.Summary) + "\n")
+	}
+	title := fmt.Sprintf("%d threads need a follow-up", len(threads))
+	err = p.alert(ctx, "follow_up", title, text.String(), briefDelivery{
+		config.ChannelChat:  func() error { return p.google.Chat.SendFollowUpReminder(ctx, p.db, threads) },
+		config.ChannelSlack: func() error { return p.slack.SendFollowUpReminder(ctx, p.db, threads) },
+	})
+	if err != nil {
 		return fmt.Errorf("failed to send follow-up reminder: %w", err)
 	}
 
@@ -423,28 +758,24 @@ func (p *Planner) GeneratePlan(ctx context.Context) (string, error) {
 
 // formatPlan creates a formatted daily plan
 func (p *Planner) formatPlan(tasks []*db.Task, events []*db.Event) string {
-	now := time.Now()
+	now := p.config.Schedule.Now()
 	var plan string
 
 	plan += fmt.Sprintf("Daily Plan - %s\n", now.Format("Monday, January 2"))
 	plan += "=" + strings.Repeat("=", 40) + "\n\n"
 
-	// Morning focus block
-	plan += "Morning Focus Block (9:00 - 11:00 AM)\n"
-	plan += "-" + strings.Repeat("-", 40) + "\n"
-
-	// Assign top 2-3 tasks to morning
-	morningTasks := 0
-	for _, task := range tasks {
-		if morningTasks >= 3 {
-			break
-		}
-		if task.Effort != "L" { // Skip large tasks for morning
-			plan += fmt.Sprintf("- %s\n", task.Title)
-			morningTasks++
+	blocks := p.planBlocks(tasks, now)
+	formatBlock := func(block *planBlock) string {
+		text := fmt.Sprintf("%s (%s - %s)\n", block.Name, block.Start.Format("3:04"), block.End.Format("3:04 PM"))
+		text += "-" + strings.Repeat("-", 40) + "\n"
+		for _, task := range block.Tasks {
+			text += fmt.Sprintf("- %s\n", task.Title)
 		}
+		return text
 	}
-	plan += "\n"
+
+	// Morning focus block
+	plan += formatBlock(blocks[0]) + "\n"
 
 	// Check for meetings
 	hasMeetings := false
@@ -468,39 +799,19 @@ func (p *Planner) formatPlan(tasks []*db.Task, events []*db.Event) string {
 		plan += "\n"
 	}
 
-	// Afternoon focus block
-	plan += "Afternoon Focus Block (2:00 - 4:00 PM)\n"
-	plan += "-" + strings.Repeat("-", 40) + "\n"
-
-	// Assign next 2-3 tasks
-	afternoonTasks := 0
-	for i := morningTasks; i < len(tasks) && afternoonTasks < 3; i++ {
-		plan += fmt.Sprintf("- %s\n", tasks[i].Title)
-		afternoonTasks++
-	}
-	plan += "\n"
-
-	// Quick wins (small tasks)
-	plan += "Quick Wins (4:00 - 5:00 PM)\n"
-	plan += "-" + strings.Repeat("-", 40) + "\n"
-
-	quickWins := 0
-	for _, task := range tasks {
-		if task.Effort == "S" && quickWins < 5 {
-			plan += fmt.Sprintf("- %s\n", task.Title)
-			quickWins++
-		}
-	}
+	// Afternoon focus block, then quick wins (small tasks)
+	plan += formatBlock(blocks[1]) + "\n"
+	plan += formatBlock(blocks[2])
 
 	return plan
 }
 
-// CompleteTask marks a task as completed and adjusts scores
+// CompleteTask marks a task as completed and adjusts scores.
+// Completing a recurring task creates its next occurrence.
 func (p *Planner) CompleteTask(ctx context.Context, taskID string) error {
-	// First, get the task to check if it's from Google Tasks
-	var task db.Task
-	query := `SELECT source, source_id, metadata FROM tasks WHERE id = ?`
-	if err := p.db.QueryRow(query, taskID).Scan(&task.Source, &task.SourceID, &task.Metadata); err != nil {
+	// First, get the task to check if it's from Google Tasks or recurring
+	task, err := p.db.GetTaskByID(taskID)
+	if err != nil {
 		return fmt.Errorf("failed to get task: %w", err)
 	}
 
@@ -511,7 +822,20 @@ func (p *Planner) CompleteTask(ctx context.Context, taskID string) error {
 		return fmt.Errorf("failed to complete task: %w", err)
 	}
 
-	// If task is from Google Tasks, sync completion back to Google
+	p.syncGoogleTaskCompletion(ctx, task)
+
+	if task.Recurrence != "" {
+		if _, err := p.spawnNextOccurrence(task, now); err != nil {
+			log.Printf("Warning: failed to create next occurrence of task %s: %v", taskID, err)
+		}
+	}
+
+	// Trigger re-prioritization
+	return p.PrioritizeTasks(ctx)
+}
+
+// syncGoogleTaskCompletion pushes a local completion back to Google Tasks for gtasks-sourced tasks
+func (p *Planner) syncGoogleTaskCompletion(ctx context.Context, task *db.Task) {
 	log.Printf("Task completion: source=%s, source_id=%s, metadata=%s", task.Source, task.SourceID, task.Metadata)
 	if task.Source == "gtasks" && task.SourceID != "" {
 		// Parse metadata to get list name
@@ -550,9 +874,6 @@ func (p *Planner) CompleteTask(ctx context.Context, taskID string) error {
 			}
 		}
 	}
-
-	// Trigger re-prioritization
-	return p.PrioritizeTasks(ctx)
 }
 
 // UncompleteTask marks a task as pending (undo completion)
@@ -564,12 +885,19 @@ func (p *Planner) UncompleteTask(ctx context.Context, taskID string) error {
 		return fmt.Errorf("failed to get task: %w", err)
 	}
 
-	updateQuery := `UPDATE tasks SET status = 'pending', completed_at = NULL WHERE id = ?`
+	updateQuery := `UPDATE tasks SET status = 'pending', completed_at = NULL, updated_at = ? WHERE id = ?`
 
-	if _, err := p.db.Exec(updateQuery, taskID); err != nil {
+	if _, err := p.db.Exec(updateQuery, time.Now().Unix(), taskID); err != nil {
 		return fmt.Errorf("failed to uncomplete task: %w", err)
 	}
 
+	// The task is open again, so the occurrence its completion created is no longer needed
+	if removed, err := p.db.DeleteSpawnedOccurrences(taskID); err != nil {
+		log.Printf("Warning: %v", err)
+	} else if removed > 0 {
+		log.Printf("Removed %d spawned occurrence(s) of task %s", removed, taskID)
+	}
+
 	// If task is from Google Tasks, sync uncomplete back to Google
 	if task.Source == "gtasks" && task.SourceID != "" {
 		// Parse metadata to get list name
@@ -612,9 +940,9 @@ func (p *Planner) UncompleteTask(ctx context.Context, taskID string) error {
 // SnoozeTask defers a task to a later time
 func (p *Planner) SnoozeTask(ctx context.Context, taskID string, until time.Time) error {
 	// Update due date
-	query := `UPDATE tasks SET due_ts = ? WHERE id = ?`
+	query := `UPDATE tasks SET due_ts = ?, updated_at = ? WHERE id = ?`
 
-	if _, err := p.db.Exec(query, until.Unix(), taskID); err != nil {
+	if _, err := p.db.Exec(query, until.Unix(), time.Now().Unix(), taskID); err != nil {
 		return fmt.Errorf("failed to snooze task: %w", err)
 	}
 
@@ -622,6 +950,56 @@ func (p *Planner) SnoozeTask(ctx context.Context, taskID string, until time.Time
 	return p.PrioritizeTasks(ctx)
 }
 
+// SetTaskDue sets or, with nil, clears a task's due date, e.g. to undo a snooze
+func (p *Planner) SetTaskDue(ctx context.Context, taskID string, due *time.Time) error {
+	var dueTS interface{}
+	if due != nil {
+		dueTS = due.Unix()
+	}
+
+	if _, err := p.db.Exec(`UPDATE tasks SET due_ts = ?, updated_at = ? WHERE id = ?`, dueTS, time.Now().Unix(), taskID); err != nil {
+		return fmt.Errorf("failed to set task due date: %w", err)
+	}
+
+	// Trigger re-prioritization
+	return p.PrioritizeTasks(ctx)
+}
+
+// DeleteTask drops a task from the task list, returning the status it had so the delete can be
+// undone. The tasks status CHECK constraint has no "deleted" value, so deleted tasks are stored
+// as cancelled.
+func (p *Planner) DeleteTask(ctx context.Context, taskID string) (string, error) {
+	task, err := p.db.GetTaskByID(taskID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get task: %w", err)
+	}
+
+	query := `UPDATE tasks SET status = 'cancelled', updated_at = ? WHERE id = ?`
+	if _, err := p.db.Exec(query, time.Now().Unix(), taskID); err != nil {
+		return "", fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	return task.Status, p.PrioritizeTasks(ctx)
+}
+
+// RestoreTask undoes DeleteTask, returning the task to the status it had before
+func (p *Planner) RestoreTask(ctx context.Context, taskID, status string) error {
+	switch status {
+	case "":
+		status = "pending"
+	case "pending", "in_progress", "completed":
+	default:
+		return fmt.Errorf("cannot restore a task to status %q", status)
+	}
+
+	query := `UPDATE tasks SET status = ?, updated_at = ? WHERE id = ?`
+	if _, err := p.db.Exec(query, status, time.Now().Unix(), taskID); err != nil {
+		return fmt.Errorf("failed to restore task: %w", err)
+	}
+
+	return p.PrioritizeTasks(ctx)
+}
+
 // GetTaskStats returns statistics about tasks
 func (p *Planner) GetTaskStats() (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
@@ -649,7 +1027,7 @@ func (p *Planner) GetTaskStats() (map[string]interface{}, error) {
 	stats["in_progress"] = inProgress
 
 	// Today's completions
-	now := time.Now()
+	now := p.config.Schedule.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
 	var todayCompleted int
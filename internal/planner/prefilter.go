@@ -0,0 +1,169 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/embeddings"
+	"github.com/alexrabarts/focus-agent/internal/llm"
+)
+
+// prefilterEmbedTimeout bounds each embedding call, so an unreachable Ollama host doesn't hold up
+// rescoring
+const prefilterEmbedTimeout = 20 * time.Second
+
+// priorityVector is the embedding of one OKR, focus area or project
+type priorityVector struct {
+	kind      string // "okr", "focus_area" or "project"
+	name      string
+	embedding []float64
+}
+
+// prefilterAlignment settles the strategic alignment of tasks that are clearly unrelated or
+// clearly related to the priorities by embedding similarity, so only the ambiguous ones need
+// the LLM. It returns the results it settled, keyed by task ID, and settles nothing when
+// embeddings aren't available.
+func (p *Planner) prefilterAlignment(ctx context.Context, tasks []*db.Task, priorities *config.Priorities) map[string]*llm.StrategicAlignmentResult {
+	settled := make(map[string]*llm.StrategicAlignmentResult)
+	low, high := p.config.Planner.AlignmentSimilarityLow, p.config.Planner.AlignmentSimilarityHigh
+	if p.embeddings == nil || len(tasks) == 0 || (low < 0 && high < 0) {
+		return settled
+	}
+
+	vectors, err := p.priorityVectors(ctx, priorities)
+	if err != nil {
+		log.Printf("Skipping alignment pre-filter: %v", err)
+		return settled
+	}
+	if len(vectors) == 0 {
+		return settled
+	}
+
+	for _, task := range tasks {
+		embedding, err := p.embed(ctx, alignmentEmbeddingText(task))
+		if err != nil {
+			// Most likely Ollama is down, so don't try the rest
+			log.Printf("Skipping alignment pre-filter: %v", err)
+			break
+		}
+		if result := similarityAlignment(task, embedding, vectors, priorities, low, high); result != nil {
+			settled[task.ID] = result
+		}
+	}
+
+	if len(settled) > 0 {
+		log.Printf("Settled strategic alignment of %d of %d tasks by embedding similarity", len(settled), len(tasks))
+	}
+	return settled
+}
+
+// priorityVectors returns the embedding of each priority. They're kept until the priorities
+// change.
+func (p *Planner) priorityVectors(ctx context.Context, priorities *config.Priorities) ([]priorityVector, error) {
+	hash := priorities.Hash()
+	p.prefilterMu.Lock()
+	defer p.prefilterMu.Unlock()
+	if p.prefilterHash == hash {
+		return p.prefilterVectors, nil
+	}
+
+	var vectors []priorityVector
+	for _, group := range []struct {
+		kind, label string
+		names       []string
+	}{
+		{"okr", "OKR", priorities.OKRs},
+		{"focus_area", "Focus area", priorities.FocusAreas},
+		{"project", "Project", priorities.KeyProjects},
+	} {
+		for _, name := range group.names {
+			if strings.TrimSpace(name) == "" {
+				continue
+			}
+			embedding, err := p.embed(ctx, fmt.Sprintf("%s: %s", group.label, name))
+			if err != nil {
+				return nil, fmt.Errorf("failed to embed priority %q: %w", name, err)
+			}
+			vectors = append(vectors, priorityVector{kind: group.kind, name: name, embedding: embedding})
+		}
+	}
+
+	p.prefilterHash = hash
+	p.prefilterVectors = vectors
+	return vectors, nil
+}
+
+// embed embeds text for the pre-filter
+func (p *Planner) embed(ctx context.Context, text string) ([]float64, error) {
+	embedCtx, cancel := context.WithTimeout(ctx, prefilterEmbedTimeout)
+	defer cancel()
+	return p.embeddings.Generate(embedCtx, text)
+}
+
+// alignmentEmbeddingText is the text of a task compared with the priorities: the fields the
+// LLM is shown, less the stakeholder
+func alignmentEmbeddingText(task *db.Task) string {
+	parts := []string{"Task: " + task.Title}
+	if task.Description != "" {
+		parts = append(parts, "Description: "+task.Description)
+	}
+	if task.Project != "" {
+		parts = append(parts, "Project: "+task.Project)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// similarityAlignment scores a task's strategic alignment from how similar it is to its closest
+// priority: unaligned below low, aligned with every priority above high, scoring from 3 at high
+// to 5 for a perfect match. It returns nil when the similarity is in between and the LLM should
+// decide. Tasks that mention a key stakeholder are never settled as unaligned, since people
+// don't embed like goals.
+func similarityAlignment(task *db.Task, embedding []float64, vectors []priorityVector, priorities *config.Priorities, low, high float64) *llm.StrategicAlignmentResult {
+	result := &llm.StrategicAlignmentResult{OKRs: []string{}, FocusAreas: []string{}, Projects: []string{}}
+	best := -1.0
+	for _, vector := range vectors {
+		similarity, err := embeddings.CosineSimilarity(embedding, vector.embedding)
+		if err != nil {
+			continue
+		}
+		best = max(best, similarity)
+		if high < 0 || similarity < high {
+			continue
+		}
+		switch vector.kind {
+		case "okr":
+			result.OKRs = append(result.OKRs, vector.name)
+		case "focus_area":
+			result.FocusAreas = append(result.FocusAreas, vector.name)
+		case "project":
+			result.Projects = append(result.Projects, vector.name)
+		}
+	}
+
+	switch {
+	case high >= 0 && best >= high:
+		result.Score = min(3+2*(best-high)/max(1-high, 0.01), 5)
+		result.Reasoning = fmt.Sprintf("Closely matches priorities by embedding similarity (%.2f)", best)
+		return result
+	case low >= 0 && best > -1 && best < low && !mentionsKeyStakeholder(task, priorities):
+		result.Reasoning = fmt.Sprintf("No priority is similar by embedding (closest %.2f)", best)
+		return result
+	}
+	return nil
+}
+
+// mentionsKeyStakeholder reports whether a task names one of the key stakeholders
+func mentionsKeyStakeholder(task *db.Task, priorities *config.Priorities) bool {
+	text := strings.ToLower(task.Title + "\n" + task.Description + "\n" + task.SourceID)
+	for _, stakeholder := range priorities.KeyStakeholders {
+		if stakeholder = strings.ToLower(strings.TrimSpace(stakeholder)); stakeholder != "" && strings.Contains(text, stakeholder) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// Project close actions
+const (
+	ProjectCloseComplete = "complete" // Mark remaining tasks as completed
+	ProjectCloseRehome   = "rehome"   // Move remaining tasks to another project
+	ProjectCloseArchive  = "archive"  // Drop remaining tasks without completing them
+)
+
+// ProjectCloseResult summarises what happened when a project was closed
+type ProjectCloseResult struct {
+	Project       string   `json:"project"`
+	Action        string   `json:"action"`
+	TargetProject string   `json:"target_project,omitempty"`
+	TaskIDs       []string `json:"task_ids"`
+	Skipped       []string `json:"skipped,omitempty"`
+}
+
+// GetProjectTasks returns the open tasks that would be affected by closing a project
+func (p *Planner) GetProjectTasks(project string) ([]*db.Task, error) {
+	project = strings.TrimSpace(project)
+	if project == "" {
+		return nil, fmt.Errorf("project name is required")
+	}
+	return p.db.GetOpenTasksByProject(project)
+}
+
+// CloseProject bulk-completes, re-homes or archives the open tasks of a project.
+// If taskIDs is empty every open task in the project is affected, otherwise only
+// the listed tasks (as reviewed by the user) are touched.
+func (p *Planner) CloseProject(ctx context.Context, project, action, targetProject string, taskIDs []string) (*ProjectCloseResult, error) {
+	tasks, err := p.GetProjectTasks(project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project tasks: %w", err)
+	}
+
+	targetProject = strings.TrimSpace(targetProject)
+	switch action {
+	case ProjectCloseComplete, ProjectCloseArchive:
+	case ProjectCloseRehome:
+		if targetProject == "" {
+			return nil, fmt.Errorf("target project is required to re-home tasks")
+		}
+		if strings.EqualFold(targetProject, project) {
+			return nil, fmt.Errorf("target project must differ from %q", project)
+		}
+	default:
+		return nil, fmt.Errorf("unknown project close action: %s", action)
+	}
+
+	selected := make(map[string]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		selected[id] = true
+	}
+
+	result := &ProjectCloseResult{
+		Project:       project,
+		Action:        action,
+		TargetProject: targetProject,
+		TaskIDs:       []string{},
+	}
+
+	now := time.Now()
+	for _, task := range tasks {
+		if len(selected) > 0 && !selected[task.ID] {
+			continue
+		}
+
+		switch action {
+		case ProjectCloseComplete:
+			updateQuery := `UPDATE tasks SET status = 'completed', completed_at = ?, updated_at = ? WHERE id = ?`
+			if _, err := p.db.Exec(updateQuery, now.Unix(), now.Unix(), task.ID); err != nil {
+				log.Printf("Failed to complete task %s while closing project %s: %v", task.ID, project, err)
+				result.Skipped = append(result.Skipped, task.ID)
+				continue
+			}
+			p.syncGoogleTaskCompletion(ctx, task)
+		case ProjectCloseRehome:
+			if err := p.db.SetTaskProject(task.ID, targetProject); err != nil {
+				log.Printf("Failed to re-home task %s: %v", task.ID, err)
+				result.Skipped = append(result.Skipped, task.ID)
+				continue
+			}
+		case ProjectCloseArchive:
+			// The tasks status CHECK constraint has no "archived" value, so archived tasks are stored as cancelled
+			updateQuery := `UPDATE tasks SET status = 'cancelled', updated_at = ? WHERE id = ?`
+			if _, err := p.db.Exec(updateQuery, now.Unix(), task.ID); err != nil {
+				log.Printf("Failed to archive task %s while closing project %s: %v", task.ID, project, err)
+				result.Skipped = append(result.Skipped, task.ID)
+				continue
+			}
+		}
+
+		result.TaskIDs = append(result.TaskIDs, task.ID)
+	}
+
+	log.Printf("Closed project %q (%s): %d tasks updated, %d skipped", project, action, len(result.TaskIDs), len(result.Skipped))
+
+	if len(result.TaskIDs) == 0 {
+		return result, nil
+	}
+
+	// Re-prioritize once for the whole batch
+	if err := p.PrioritizeTasks(ctx); err != nil {
+		return result, fmt.Errorf("failed to re-prioritize after closing project: %w", err)
+	}
+
+	return result, nil
+}
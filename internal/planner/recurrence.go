@@ -0,0 +1,134 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// SetTaskRecurrence validates and stores a task's recurrence rule. An empty rule stops the
+// task recurring. A recurring task without a due date is given one today so there is a date
+// to repeat from.
+func (p *Planner) SetTaskRecurrence(ctx context.Context, taskID, rule string) (string, error) {
+	task, err := p.db.GetTaskByID(taskID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get task: %w", err)
+	}
+
+	if strings.TrimSpace(rule) == "" {
+		return "", p.db.SetTaskRecurrence(taskID, "")
+	}
+
+	recurrence, err := db.ParseRecurrence(rule)
+	if err != nil {
+		return "", err
+	}
+
+	due := task.DueTS
+	if due == nil {
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 17, 0, 0, 0, now.Location())
+		if _, err := p.db.Exec(`UPDATE tasks SET due_ts = ?, updated_at = ? WHERE id = ?`, today.Unix(), time.Now().Unix(), taskID); err != nil {
+			return "", fmt.Errorf("failed to set due date: %w", err)
+		}
+		due = &today
+	}
+
+	// Pin monthly rules to the due date's day so a task due on the 31st doesn't drift
+	// to the 28th after February
+	if recurrence.Freq == db.FreqMonthly && recurrence.ByMonthDay == 0 {
+		recurrence.ByMonthDay = due.Day()
+	}
+
+	stored := recurrence.String()
+	if err := p.db.SetTaskRecurrence(taskID, stored); err != nil {
+		return "", err
+	}
+
+	return stored, p.PrioritizeTasks(ctx)
+}
+
+// spawnNextOccurrence creates the next pending occurrence of a completed recurring task.
+// The next due date is counted from the previous due date (or the completion time), skipping
+// occurrences that are already in the past so a late completion doesn't create overdue work.
+func (p *Planner) spawnNextOccurrence(task *db.Task, completedAt time.Time) (*db.Task, error) {
+	recurrence, err := db.ParseRecurrence(task.Recurrence)
+	if err != nil {
+		return nil, fmt.Errorf("task %s has an invalid recurrence rule: %w", task.ID, err)
+	}
+
+	exists, err := p.db.HasSpawnedOccurrence(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, nil
+	}
+
+	base := completedAt
+	if task.DueTS != nil {
+		base = *task.DueTS
+	}
+	due := recurrence.Next(base)
+	for !due.After(completedAt) {
+		due = recurrence.Next(due)
+	}
+
+	next := &db.Task{
+		ID:          fmt.Sprintf("recur_%d", time.Now().UnixNano()),
+		Source:      task.Source,
+		SourceID:    task.SourceID,
+		Title:       task.Title,
+		Description: task.Description,
+		DueTS:       &due,
+		Project:     task.Project,
+		Impact:      task.Impact,
+		Urgency:     task.Urgency,
+		Effort:      task.Effort,
+		Stakeholder: task.Stakeholder,
+		Status:      "pending",
+		Metadata:    task.Metadata,
+		Recurrence:  task.Recurrence,
+		RecursFrom:  task.ID,
+	}
+
+	// Google Tasks has no recurrence, so the next occurrence lives only in Focus Agent
+	if next.Source == "gtasks" {
+		next.Source = "recurring"
+		next.SourceID = ""
+	}
+
+	next.Score = p.calculateScore(next)
+	if err := p.db.SaveTask(next); err != nil {
+		return nil, fmt.Errorf("failed to save next occurrence: %w", err)
+	}
+
+	log.Printf("Recurring task %q: next occurrence %s due %s", task.Title, next.ID, due.Format("Mon Jan 2 15:04"))
+	return next, nil
+}
+
+// upcomingRecurrences returns recurring tasks due in the coming week that aren't already in the brief
+func (p *Planner) upcomingRecurrences(briefTasks []*db.Task) []*db.Task {
+	upcoming, err := p.db.GetUpcomingRecurringTasks(time.Now().AddDate(0, 0, 7), 10)
+	if err != nil {
+		log.Printf("Failed to get upcoming recurring tasks: %v", err)
+		return nil
+	}
+
+	inBrief := make(map[string]bool, len(briefTasks))
+	for _, task := range briefTasks {
+		inBrief[task.ID] = true
+	}
+
+	var result []*db.Task
+	for _, task := range upcoming {
+		if !inBrief[task.ID] {
+			result = append(result, task)
+		}
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// SendRelationshipBriefs sends a relationship brief for each external attendee of meetings
+// starting within the configured lead time. Each (meeting, contact) pair is only briefed once.
+func (p *Planner) SendRelationshipBriefs(ctx context.Context) error {
+	lead := time.Duration(p.config.Meetings.LeadMinutes) * time.Minute
+	events, err := p.db.GetUpcomingEvents(int(lead.Hours()) + 1)
+	if err != nil {
+		return fmt.Errorf("failed to get upcoming events: %w", err)
+	}
+
+	cutoff := time.Now().Add(lead)
+	sent := 0
+
+	for _, event := range events {
+		if event.StartTS.After(cutoff) || event.Status == "cancelled" {
+			continue
+		}
+
+		for _, attendee := range event.Attendees {
+			if !p.isExternalContact(attendee) {
+				continue
+			}
+
+			done, err := p.db.HasRelationshipBrief(event.ID, attendee)
+			if err != nil {
+				log.Printf("Failed to check relationship brief for %s: %v", attendee, err)
+				continue
+			}
+			if done {
+				continue
+			}
+
+			if err := p.sendRelationshipBrief(ctx, event, attendee); err != nil {
+				log.Printf("Failed to send relationship brief for %s: %v", attendee, err)
+				continue
+			}
+			sent++
+		}
+	}
+
+	if sent > 0 {
+		log.Printf("Sent %d relationship brief(s)", sent)
+		p.db.LogUsage("planner", "relationship_briefs", 0, 0, 0, nil)
+	}
+	return nil
+}
+
+// sendRelationshipBrief assembles and delivers the brief for one contact
+func (p *Planner) sendRelationshipBrief(ctx context.Context, event *db.Event, contact string) error {
+	history, err := p.db.GetContactHistory(contact, p.config.Google.UserEmail, 5)
+	if err != nil {
+		return err
+	}
+
+	// Sentiment and topics need something to go on; the rest of the brief is still useful without them
+	var analysis string
+	if len(history.Interactions) > 0 {
+		analysis, err = p.llm.GenerateRelationshipBrief(ctx, event, history)
+		if err != nil {
+			log.Printf("Failed to analyze relationship with %s: %v", contact, err)
+		}
+		analysis = strings.TrimSpace(analysis)
+	}
+
+	err = p.notify("relationship_brief", briefDelivery{
+		config.ChannelChat:  func() error { return p.google.Chat.SendRelationshipBrief(ctx, p.db, event, history, analysis) },
+		config.ChannelSlack: func() error { return p.slack.SendRelationshipBrief(ctx, p.db, event, history, analysis) },
+	})
+
+	// Failed deliveries stay queued in the outbox, so record the brief either way to avoid repeats
+	if saveErr := p.db.SaveRelationshipBrief(event.ID, contact, analysis); saveErr != nil {
+		log.Printf("Warning: %v", saveErr)
+	}
+	return err
+}
+
+// isExternalContact reports whether an attendee email belongs to someone outside the user's organisation
+func (p *Planner) isExternalContact(email string) bool {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+
+	// Rooms and group calendars show up as attendees
+	if strings.HasSuffix(domain, "calendar.google.com") {
+		return false
+	}
+
+	userEmail := strings.ToLower(p.config.Google.UserEmail)
+	if email == userEmail {
+		return false
+	}
+	if userAt := strings.LastIndex(userEmail, "@"); userAt >= 0 && userEmail[userAt+1:] == domain {
+		return false
+	}
+
+	for _, internal := range p.config.Meetings.InternalDomains {
+		if strings.EqualFold(strings.TrimPrefix(internal, "@"), domain) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,83 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// RSVP responses accepted by RespondToEvent, as Google Calendar names them
+const (
+	RSVPAccepted  = "accepted"
+	RSVPTentative = "tentative"
+	RSVPDeclined  = "declined"
+)
+
+// declineNoteGoal is what a drafted decline note is asked to do
+const declineNoteGoal = "Politely decline this meeting invitation in two or three sentences. " +
+	"Don't invent a reason; offer to catch up asynchronously or to be sent the notes instead."
+
+// RespondToEvent accepts, tentatively accepts or declines a calendar invite, with an optional
+// note to the organizer. Needs meetings.rsvp for write access to the calendar.
+func (p *Planner) RespondToEvent(ctx context.Context, eventID, response, note string) (*db.Event, error) {
+	switch response {
+	case RSVPAccepted, RSVPTentative, RSVPDeclined:
+	default:
+		return nil, fmt.Errorf("unknown response %q (expected accepted, tentative or declined)", response)
+	}
+	if !p.config.Meetings.RSVP {
+		return nil, fmt.Errorf("replying to invites is off; set meetings.rsvp and re-run -auth")
+	}
+	if p.google == nil || p.google.Calendar == nil {
+		return nil, fmt.Errorf("replying to invites needs Google Calendar")
+	}
+
+	event, err := p.db.GetEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, fmt.Errorf("event not found: %s", eventID)
+	}
+
+	if err := p.google.Calendar.RespondToEvent(ctx, eventID, response, strings.TrimSpace(note)); err != nil {
+		return nil, err
+	}
+	if err := p.db.SetEventResponse(eventID, response); err != nil {
+		return nil, err
+	}
+	event.Response = response
+	return event, nil
+}
+
+// DraftDeclineNote drafts a short, polite note declining a meeting, in the persona remembered
+// for its organizer or the default one
+func (p *Planner) DraftDeclineNote(ctx context.Context, eventID string) (string, error) {
+	event, err := p.db.GetEvent(eventID)
+	if err != nil {
+		return "", err
+	}
+	if event == nil {
+		return "", fmt.Errorf("event not found: %s", eventID)
+	}
+
+	// The invite stands in for the email being replied to
+	invite := &db.Message{
+		ID:        event.ID,
+		From:      event.Organizer,
+		To:        p.config.Google.UserEmail,
+		Subject:   "Invitation: " + event.Title + " @ " + event.StartTS.Format("Mon Jan 2 3:04 PM"),
+		Body:      event.Description,
+		Timestamp: time.Now(),
+	}
+	_, persona := p.replyPersona(event.Organizer, "")
+
+	text, err := p.llm.DraftReply(ctx, []*db.Message{invite}, declineNoteGoal, persona)
+	if err != nil {
+		return "", fmt.Errorf("failed to draft decline note: %w", err)
+	}
+	return strings.TrimSpace(text), nil
+}
@@ -0,0 +1,160 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// scoringProfileKey is the prefs key holding the scoring profile switched to from the TUI or API
+const scoringProfileKey = "scoring_profile"
+
+// ScoreFactors are a task's scoring factors, or their contributions to its score
+type ScoreFactors struct {
+	Strategic   float64 `json:"strategic"`
+	Urgency     float64 `json:"urgency"`
+	Impact      float64 `json:"impact"`
+	Stakeholder float64 `json:"stakeholder"`
+	Effort      float64 `json:"effort"`
+}
+
+// TaskScoreFactors returns the factors a task is scored on given its strategic alignment (0-5).
+// Unset impact and urgency count as 3, effort is S=0.5, M=1.0, L=1.5 and stakeholder is
+// internal=1.0, external=1.5, executive=2.0.
+func TaskScoreFactors(task *db.Task, strategicScore float64) ScoreFactors {
+	factors := ScoreFactors{
+		Strategic:   strategicScore,
+		Urgency:     float64(task.Urgency),
+		Impact:      float64(task.Impact),
+		Stakeholder: 1.0,
+		Effort:      1.0,
+	}
+	if factors.Urgency == 0 {
+		factors.Urgency = 3
+	}
+	if factors.Impact == 0 {
+		factors.Impact = 3
+	}
+
+	switch task.Effort {
+	case "S":
+		factors.Effort = 0.5
+	case "L":
+		factors.Effort = 1.5
+	}
+
+	switch task.Stakeholder {
+	case "external":
+		factors.Stakeholder = 1.5
+	case "executive":
+		factors.Stakeholder = 2.0
+	}
+	return factors
+}
+
+// Contributions returns the points out of 100 each factor adds to the score with the given
+// weights; effort's are negative
+func (f ScoreFactors) Contributions(weights config.ScoringWeights) ScoreFactors {
+	points := func(weight, factor float64) float64 {
+		return weight * factor / 4.0 * 100.0
+	}
+	return ScoreFactors{
+		Strategic:   points(weights.Strategic, f.Strategic),
+		Urgency:     points(weights.Urgency, f.Urgency),
+		Impact:      points(weights.Impact, f.Impact),
+		Stakeholder: points(weights.Stakeholder, f.Stakeholder),
+		Effort:      -points(weights.Effort, f.Effort),
+	}
+}
+
+// Score returns the score out of 100 before priority feedback: the sum of the contributions,
+// clamped to 0-100
+func (f ScoreFactors) Score(weights config.ScoringWeights) float64 {
+	c := f.Contributions(weights)
+	return min(max(c.Strategic+c.Urgency+c.Impact+c.Stakeholder+c.Effort, 0), 100)
+}
+
+// Scoring is the scoring profile in use and the profiles that can be switched to
+type Scoring struct {
+	Profile  string                           `json:"profile"`
+	Weights  config.ScoringWeights            `json:"weights"`
+	Profiles map[string]config.ScoringWeights `json:"profiles"` // Every profile, including default
+
+	// Effort factors learned from completion history, if effort calibration is on and has run
+	EffortCalibration *db.EffortCalibration `json:"effort_calibration,omitempty"`
+}
+
+// ScoringProfile returns the scoring profile tasks are scored with and its weights: the one last
+// switched to, or planner.scoring.profile
+func (p *Planner) ScoringProfile() (string, config.ScoringWeights) {
+	p.scoringMu.Lock()
+	profile, weights := p.scoringName, p.scoringWeights
+	p.scoringMu.Unlock()
+	if profile == "" {
+		return p.loadScoringProfile()
+	}
+	return profile, weights
+}
+
+// loadScoringProfile reloads the scoring profile in use. A switched-to profile that's since been
+// removed from the config falls back to planner.scoring.profile.
+func (p *Planner) loadScoringProfile() (string, config.ScoringWeights) {
+	scoring := p.config.Planner.Scoring
+	profile := scoring.Profile
+	saved, err := p.db.GetPreference(scoringProfileKey)
+	if err != nil {
+		log.Printf("Failed to get scoring profile: %v", err)
+	}
+	if saved != "" {
+		if _, ok := scoring.ProfileWeights(saved); ok {
+			profile = saved
+		} else {
+			log.Printf("Scoring profile %q is no longer configured, using %q", saved, profile)
+		}
+	}
+	weights, _ := scoring.ProfileWeights(profile)
+
+	p.scoringMu.Lock()
+	p.scoringName, p.scoringWeights = profile, weights
+	p.scoringMu.Unlock()
+	return profile, weights
+}
+
+// GetScoring returns the scoring profile in use and every profile that can be switched to
+func (p *Planner) GetScoring() *Scoring {
+	profile, weights := p.ScoringProfile()
+	scoring := &Scoring{
+		Profile:           profile,
+		Weights:           weights,
+		Profiles:          make(map[string]config.ScoringWeights),
+		EffortCalibration: p.EffortCalibration(),
+	}
+	for _, name := range p.config.Planner.Scoring.ProfileNames() {
+		scoring.Profiles[name], _ = p.config.Planner.Scoring.ProfileWeights(name)
+	}
+	return scoring
+}
+
+// SetScoringProfile switches the weights tasks are scored with to a configured profile and
+// rescores every pending task with them
+func (p *Planner) SetScoringProfile(ctx context.Context, profile string) error {
+	profile = strings.ToLower(strings.TrimSpace(profile))
+	weights, ok := p.config.Planner.Scoring.ProfileWeights(profile)
+	if !ok || profile == "" {
+		return fmt.Errorf("unknown scoring profile %q (expected one of %s)",
+			profile, strings.Join(p.config.Planner.Scoring.ProfileNames(), ", "))
+	}
+	if err := p.db.SetPreference(scoringProfileKey, profile); err != nil {
+		return fmt.Errorf("failed to save scoring profile: %w", err)
+	}
+
+	p.scoringMu.Lock()
+	p.scoringName, p.scoringWeights = profile, weights
+	p.scoringMu.Unlock()
+	log.Printf("Scoring with the %s profile: %s", profile, weights)
+	return p.PrioritizeTasks(ctx)
+}
@@ -0,0 +1,74 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// searchLimit is how many results a search returns
+const searchLimit = 20
+
+// Search finds tasks, threads, messages, calendar events and Drive documents related to a query,
+// or only those of the given kinds (db.SearchKinds). Keyword matches are blended with
+// semantically similar tasks, thread summaries and documents (search.semantic), so related
+// context turns up even when it shares no words with the query, and recent results rank higher.
+// Without embeddings, or when they can't be generated, it falls back to keyword search alone.
+func (p *Planner) Search(ctx context.Context, query string, kinds ...string) ([]*db.SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("search query is empty")
+	}
+	for _, kind := range kinds {
+		if !slices.Contains(db.SearchKinds, kind) {
+			return nil, fmt.Errorf("unknown search type %q", kind)
+		}
+	}
+
+	keyword, err := p.db.KeywordSearch(askKeywords(query), kinds, searchLimit)
+	if err != nil {
+		log.Printf("Keyword search failed: %v", err)
+	}
+
+	semantic := p.semanticSearch(ctx, query, kinds)
+	if keyword == nil && semantic == nil && err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	return db.BlendSearchResults(searchLimit, keyword, semantic), nil
+}
+
+// semanticSearch returns the tasks, thread summaries and documents closest in meaning to the query
+func (p *Planner) semanticSearch(ctx context.Context, query string, kinds []string) []*db.SearchResult {
+	if !p.config.Search.Semantic || p.embeddings == nil {
+		return nil
+	}
+	embedded := len(kinds) == 0
+	for _, kind := range kinds {
+		embedded = embedded || kind != db.ContentKindMessage && kind != db.ContentKindEvent
+	}
+	if !embedded {
+		return nil // Messages and events are only found by keyword
+	}
+
+	embedCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	embedding, err := p.embeddings.Generate(embedCtx, query)
+	if err != nil {
+		log.Printf("Failed to embed search query, using keyword search only: %v", err)
+		return nil
+	}
+
+	results, err := p.db.SemanticSearch(embedding, kinds, searchLimit)
+	if err != nil {
+		log.Printf("Semantic search failed: %v", err)
+		return nil
+	}
+	return results
+}
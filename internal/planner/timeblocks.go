@@ -0,0 +1,301 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// planBlock is one block of the daily plan and the tasks assigned to it
+type planBlock struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+	Tasks []*db.Task
+}
+
+// planBlocks lays out the day: a morning focus block with up to three tasks that aren't
+// large, an afternoon focus block with the next three, then up to five small tasks as
+// quick wins. Focus blocks last planner.focus_block_hours.
+func (p *Planner) planBlocks(tasks []*db.Task, day time.Time) []*planBlock {
+	at := func(hour int) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, 0, 0, 0, day.Location())
+	}
+	hours := p.config.Planner.FocusBlockHours
+
+	morning := &planBlock{Name: "Morning Focus Block", Start: at(9), End: at(9 + hours)}
+	afternoon := &planBlock{Name: "Afternoon Focus Block", Start: at(14), End: at(14 + hours)}
+	quickWins := &planBlock{Name: "Quick Wins", Start: at(14 + hours), End: at(15 + hours)}
+
+	assigned := make(map[string]bool)
+	for _, task := range tasks {
+		if len(morning.Tasks) >= 3 {
+			break
+		}
+		if task.Effort != "L" { // Skip large tasks for morning
+			morning.Tasks = append(morning.Tasks, task)
+			assigned[task.ID] = true
+		}
+	}
+	for _, task := range tasks {
+		if len(afternoon.Tasks) >= 3 {
+			break
+		}
+		if !assigned[task.ID] {
+			afternoon.Tasks = append(afternoon.Tasks, task)
+			assigned[task.ID] = true
+		}
+	}
+	for _, task := range tasks {
+		if len(quickWins.Tasks) >= 5 {
+			break
+		}
+		if task.Effort == "S" && !assigned[task.ID] {
+			quickWins.Tasks = append(quickWins.Tasks, task)
+		}
+	}
+
+	return []*planBlock{morning, afternoon, quickWins}
+}
+
+// ScheduleTimeBlocks plans today's focus blocks, fitting each around existing events. In auto
+// mode they are written to the calendar straight away; in confirm mode they wait as proposals
+// for the TUI. Blocks are only planned once a day, so later calls return the existing ones.
+func (p *Planner) ScheduleTimeBlocks(ctx context.Context) ([]*db.TimeBlock, error) {
+	cfg := p.config.Planner.TimeBlocking
+	if !cfg.Enabled {
+		return nil, fmt.Errorf("time blocking is not enabled (planner.time_blocking.enabled)")
+	}
+
+	now := time.Now()
+	day := db.TimeBlockDay(now)
+
+	existing, err := p.db.GetTimeBlocks(day)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		p.addTaskTitles(existing)
+		return existing, nil
+	}
+
+	tasks, err := p.db.GetPendingTasks(15)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	var blocks []*db.TimeBlock
+	for _, planned := range p.planBlocks(tasks, now) {
+		if len(planned.Tasks) == 0 || !planned.End.After(now) {
+			continue
+		}
+
+		block := &db.TimeBlock{
+			Day:     day,
+			Name:    planned.Name,
+			StartTS: planned.Start,
+			EndTS:   planned.End,
+			Status:  db.TimeBlockProposed,
+		}
+		for _, task := range planned.Tasks {
+			block.TaskIDs = append(block.TaskIDs, task.ID)
+		}
+
+		// A block already under way starts from the next quarter hour
+		if block.StartTS.Before(now) {
+			block.StartTS = now.Truncate(15 * time.Minute).Add(15 * time.Minute)
+			if block.EndTS.Sub(block.StartTS) < time.Duration(cfg.MinMinutes)*time.Minute {
+				continue
+			}
+		}
+
+		if err := p.fitTimeBlock(block); err != nil {
+			return nil, err
+		}
+		if err := p.db.SaveTimeBlock(block); err != nil {
+			return nil, err
+		}
+
+		if cfg.Mode == config.TimeBlockingAuto && block.Status == db.TimeBlockProposed {
+			if err := p.writeTimeBlock(ctx, block); err != nil {
+				log.Printf("Failed to schedule %s: %v", block.Name, err)
+			}
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	log.Printf("Planned %d time block(s) for %s (%s mode)", len(blocks), day, cfg.Mode)
+	p.addTaskTitles(blocks)
+	return blocks, nil
+}
+
+// GetTimeBlocks returns today's time blocks
+func (p *Planner) GetTimeBlocks() ([]*db.TimeBlock, error) {
+	blocks, err := p.db.GetTimeBlocks(db.TimeBlockDay(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	p.addTaskTitles(blocks)
+	return blocks, nil
+}
+
+// addTaskTitles looks up the titles of each block's tasks, leaving tasks that were deleted out
+// of its task IDs so the two stay in step
+func (p *Planner) addTaskTitles(blocks []*db.TimeBlock) {
+	for _, block := range blocks {
+		block.TaskTitles = nil
+		var ids []string
+		for _, id := range block.TaskIDs {
+			if task, err := p.db.GetTaskByID(id); err == nil {
+				ids = append(ids, id)
+				block.TaskTitles = append(block.TaskTitles, task.Title)
+			}
+		}
+		block.TaskIDs = ids
+	}
+}
+
+// ConfirmTimeBlock writes a proposed block to the calendar, re-checking it against events
+// that were added since it was planned
+func (p *Planner) ConfirmTimeBlock(ctx context.Context, id string) (*db.TimeBlock, error) {
+	block, err := p.db.GetTimeBlock(id)
+	if err != nil {
+		return nil, err
+	}
+	if block.Status == db.TimeBlockScheduled {
+		return block, nil
+	}
+	now := time.Now()
+	if !block.EndTS.After(now) {
+		return nil, fmt.Errorf("%s has already ended", block.Name)
+	}
+	if block.StartTS.Before(now) {
+		block.StartTS = now.Truncate(15 * time.Minute).Add(15 * time.Minute)
+	}
+
+	if err := p.fitTimeBlock(block); err != nil {
+		return nil, err
+	}
+	if block.Status == db.TimeBlockConflict {
+		if err := p.db.UpdateTimeBlock(block); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s conflicts with %q", block.Name, block.Conflict)
+	}
+
+	if err := p.writeTimeBlock(ctx, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// DeclineTimeBlock dismisses a block, removing its calendar event if it was already scheduled
+func (p *Planner) DeclineTimeBlock(ctx context.Context, id string) (*db.TimeBlock, error) {
+	block, err := p.db.GetTimeBlock(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The event ID is kept so the deletion isn't reported as a cancelled meeting
+	if block.Status == db.TimeBlockScheduled && block.CalendarEventID != "" {
+		if p.google == nil || p.google.Calendar == nil {
+			return nil, fmt.Errorf("calendar client not available")
+		}
+		if err := p.google.Calendar.DeleteEvent(ctx, block.CalendarEventID); err != nil {
+			return nil, err
+		}
+	}
+
+	block.Status = db.TimeBlockDeclined
+	if err := p.db.UpdateTimeBlock(block); err != nil {
+		return nil, err
+	}
+	p.addTaskTitles([]*db.TimeBlock{block})
+	return block, nil
+}
+
+// fitTimeBlock moves a block into the longest stretch of its window that's free of other
+// events. If no stretch is at least planner.time_blocking.min_minutes long the block is
+// marked as a conflict with the first event in the way.
+func (p *Planner) fitTimeBlock(block *db.TimeBlock) error {
+	events, err := p.db.GetEventsBetween(block.StartTS, block.EndTS)
+	if err != nil {
+		return fmt.Errorf("failed to get events: %w", err)
+	}
+	own, err := p.db.GetTimeBlockEventIDs()
+	if err != nil {
+		return err
+	}
+
+	bestStart, bestEnd := block.StartTS, block.StartTS
+	cursor := block.StartTS
+	conflict := ""
+	for _, event := range events {
+		// Our own focus blocks and all-day events don't take up the time
+		if own[event.ID] || event.EndTS.Sub(event.StartTS) >= 24*time.Hour {
+			continue
+		}
+		if conflict == "" {
+			conflict = event.Title
+		}
+		if event.StartTS.Sub(cursor) > bestEnd.Sub(bestStart) {
+			bestStart, bestEnd = cursor, event.StartTS
+		}
+		if event.EndTS.After(cursor) {
+			cursor = event.EndTS
+		}
+	}
+	if block.EndTS.Sub(cursor) > bestEnd.Sub(bestStart) {
+		bestStart, bestEnd = cursor, block.EndTS
+	}
+
+	minLength := time.Duration(p.config.Planner.TimeBlocking.MinMinutes) * time.Minute
+	if bestEnd.Sub(bestStart) < minLength {
+		block.Status = db.TimeBlockConflict
+		block.Conflict = conflict
+		return nil
+	}
+
+	block.StartTS, block.EndTS = bestStart, bestEnd
+	if block.Status == db.TimeBlockConflict {
+		block.Status = db.TimeBlockProposed
+	}
+	block.Conflict = ""
+	return nil
+}
+
+// writeTimeBlock creates the block's tentative calendar event and marks it scheduled
+func (p *Planner) writeTimeBlock(ctx context.Context, block *db.TimeBlock) error {
+	if p.google == nil || p.google.Calendar == nil {
+		return fmt.Errorf("calendar client not available")
+	}
+
+	p.addTaskTitles([]*db.TimeBlock{block})
+	var description strings.Builder
+	description.WriteString("Focus block planned by Focus Agent.\n\n")
+	for _, title := range block.TaskTitles {
+		description.WriteString("• " + title + "\n")
+	}
+
+	event, err := p.google.Calendar.CreateFocusBlock(ctx, "🎯 "+block.Name, description.String(),
+		block.StartTS, block.EndTS, block.ID, block.TaskIDs)
+	if err != nil {
+		p.db.LogUsage("calendar", "time_block", 0, 0, 0, err)
+		return err
+	}
+
+	block.Status = db.TimeBlockScheduled
+	block.CalendarEventID = event.Id
+	if err := p.db.UpdateTimeBlock(block); err != nil {
+		return err
+	}
+
+	log.Printf("Scheduled %s %s-%s", block.Name, block.StartTS.Format("15:04"), block.EndTS.Format("15:04"))
+	return nil
+}
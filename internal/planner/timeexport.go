@@ -0,0 +1,109 @@
+package planner
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// Time export formats
+const (
+	TimeExportCSV     = "csv"     // One row per entry with every field
+	TimeExportHarvest = "harvest" // Harvest's time entry import
+	TimeExportToggl   = "toggl"   // Toggl Track's CSV import
+)
+
+// TimeExportFormats lists the formats WriteTimeExport accepts
+var TimeExportFormats = []string{TimeExportCSV, TimeExportHarvest, TimeExportToggl}
+
+// TimeExportRange parses an inclusive range of YYYY-MM-DD days in local time. An empty from
+// defaults to the first of the current month and an empty to defaults to today.
+func TimeExportRange(from, to string) (time.Time, time.Time, error) {
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	var err error
+	if from != "" {
+		if start, err = time.ParseInLocation("2006-01-02", from, time.Local); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from date %q (use YYYY-MM-DD)", from)
+		}
+	}
+	if to != "" {
+		if end, err = time.ParseInLocation("2006-01-02", to, time.Local); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to date %q (use YYYY-MM-DD)", to)
+		}
+	}
+	end = end.AddDate(0, 0, 1)
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from date must not be after to date")
+	}
+	return start, end, nil
+}
+
+// WriteTimeExport writes time entries as CSV in one of the time export formats. Toggl's import
+// needs the email of the user the time belongs to.
+func WriteTimeExport(w io.Writer, entries []*db.TimeEntry, format, userEmail string) error {
+	var header []string
+	var row func(e *db.TimeEntry) []string
+
+	switch strings.ToLower(format) {
+	case TimeExportCSV, "":
+		header = []string{"date", "start", "end", "hours", "client", "project", "task", "source", "billable"}
+		row = func(e *db.TimeEntry) []string {
+			return []string{
+				e.Start.Format("2006-01-02"), e.Start.Format("15:04"), e.End.Format("15:04"),
+				formatHours(e.Duration), e.Client, e.Project, e.Task, e.Source, fmt.Sprintf("%t", e.Billable),
+			}
+		}
+	case TimeExportHarvest:
+		header = []string{"Date", "Client", "Project", "Task", "Notes", "Hours", "First name", "Last name"}
+		row = func(e *db.TimeEntry) []string {
+			return []string{
+				e.Start.Format("2006-01-02"), e.Client, e.Project, "Focus time", e.Task,
+				formatHours(e.Duration), "", "",
+			}
+		}
+	case TimeExportToggl:
+		header = []string{"Email", "Start date", "Start time", "Duration", "Project", "Client", "Description", "Billable"}
+		row = func(e *db.TimeEntry) []string {
+			billable := "No"
+			if e.Billable {
+				billable = "Yes"
+			}
+			return []string{
+				userEmail, e.Start.Format("2006-01-02"), e.Start.Format("15:04:05"), formatClock(e.Duration),
+				e.Project, e.Client, e.Task, billable,
+			}
+		}
+	default:
+		return fmt.Errorf("unknown time export format %q (use %s)", format, strings.Join(TimeExportFormats, ", "))
+	}
+
+	out := csv.NewWriter(w)
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := out.Write(row(entry)); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// formatHours renders a duration as decimal hours, the way invoicing tools expect
+func formatHours(d time.Duration) string {
+	return fmt.Sprintf("%.2f", d.Hours())
+}
+
+// formatClock renders a duration as HH:MM:SS
+func formatClock(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
@@ -0,0 +1,78 @@
+package planner
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// timezoneKey is the prefs key holding the timezone switched to from the TUI or API while travelling
+const timezoneKey = "schedule_timezone"
+
+// Timezone is the timezone briefs are scheduled and due dates are read in
+type Timezone struct {
+	Active     string `json:"active"`
+	Home       string `json:"home"`       // schedule.timezone, used when not overridden
+	Overridden bool   `json:"overridden"` // Active was switched to at runtime
+	Zone       string `json:"zone"`       // Abbreviation and UTC offset right now, e.g. "JST +09:00"
+}
+
+// loadTimezone applies the timezone last switched to, if any, over schedule.timezone
+func (p *Planner) loadTimezone() {
+	saved, err := p.db.GetPreference(timezoneKey)
+	if err != nil {
+		log.Printf("Failed to get timezone override: %v", err)
+		return
+	}
+	if saved == "" {
+		return
+	}
+	if _, err := time.LoadLocation(saved); err != nil {
+		log.Printf("Timezone override %q is no longer valid, using %s: %v", saved, p.config.Schedule.Timezone, err)
+		return
+	}
+	p.config.Schedule.SetActiveTimezone(saved)
+	log.Printf("Using timezone %s (home: %s)", saved, p.config.Schedule.Timezone)
+}
+
+// Timezone returns the timezone in use and whether it overrides schedule.timezone
+func (p *Planner) Timezone() *Timezone {
+	schedule := p.config.Schedule
+	active := schedule.ActiveTimezone()
+	return &Timezone{
+		Active:     active,
+		Home:       schedule.Timezone,
+		Overridden: active != schedule.Timezone,
+		Zone:       schedule.Now().Format("MST -07:00"),
+	}
+}
+
+// SetTimezone switches the timezone briefs are scheduled and due dates are read in to an IANA
+// name such as "Asia/Tokyo". An empty name switches back to schedule.timezone. Scheduled
+// briefs only move once the scheduler is told; see Scheduler.RescheduleClockJobs.
+func (p *Planner) SetTimezone(name string) (*Timezone, error) {
+	name = strings.TrimSpace(name)
+	home := p.config.Schedule.Timezone
+	if name == "" {
+		name = home
+	}
+	if strings.EqualFold(name, "local") {
+		return nil, fmt.Errorf("timezone must be an IANA name such as Asia/Tokyo, not %q", name)
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return nil, fmt.Errorf("unknown timezone %q: %w", name, err)
+	}
+
+	saved := name
+	if name == home {
+		saved = ""
+	}
+	if err := p.db.SetPreference(timezoneKey, saved); err != nil {
+		return nil, fmt.Errorf("failed to save timezone: %w", err)
+	}
+
+	p.config.Schedule.SetActiveTimezone(name)
+	log.Printf("Switched timezone to %s (home: %s)", name, home)
+	return p.Timezone(), nil
+}
@@ -0,0 +1,139 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// CheckWaitingOn keeps the waiting-on list current: requests that got a reply are closed, new
+// sent mail is checked for requests, and overdue requests are sent as a nudge
+func (p *Planner) CheckWaitingOn(ctx context.Context) error {
+	userEmail := p.config.Google.UserEmail
+	if userEmail == "" {
+		return fmt.Errorf("google.user_email is needed to find sent mail")
+	}
+
+	replied, err := p.db.ResolveRepliedWaitingItems(userEmail)
+	if err != nil {
+		return err
+	}
+
+	tracked, err := p.trackSentRequests(ctx, userEmail)
+	if err != nil {
+		return err
+	}
+
+	nudged, err := p.nudgeWaitingItems(ctx)
+	if err != nil {
+		return err
+	}
+
+	if replied > 0 || tracked > 0 || nudged > 0 {
+		log.Printf("Waiting on: %d new, %d replied, %d nudged", tracked, replied, nudged)
+	}
+	return nil
+}
+
+// trackSentRequests checks recently sent messages for requests and records them as waiting items
+func (p *Planner) trackSentRequests(ctx context.Context, userEmail string) (int, error) {
+	since := time.Now().AddDate(0, 0, -p.config.WaitingOn.LookbackDays)
+	messages, err := p.db.GetSentMessagesToCheck(userEmail, since, p.config.WaitingOn.MaxPerRun)
+	if err != nil {
+		return 0, err
+	}
+
+	tracked := 0
+	for _, msg := range messages {
+		request, err := p.llm.ExtractWaitingRequest(ctx, msg)
+		if err != nil {
+			// Left unchecked, so it's tried again on the next run
+			log.Printf("Failed to check sent message %s for a request: %v", msg.ID, err)
+			continue
+		}
+
+		item := &db.WaitingItem{
+			MessageID: msg.ID,
+			ThreadID:  msg.ThreadID,
+			Subject:   msg.Subject,
+			SentAt:    msg.Timestamp,
+			Status:    db.WaitingNone,
+		}
+		if request.IsRequest && request.Request != "" {
+			item.Status = db.WaitingOpen
+			item.Request = request.Request
+			item.Recipient = request.Recipient
+			if item.Recipient == "" {
+				item.Recipient = firstAddress(msg.To)
+			}
+			item.ExpectedBy = p.replyDue(msg.Timestamp, request.ReplyBy)
+			tracked++
+		}
+
+		if err := p.db.SaveWaitingItem(item); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	return tracked, nil
+}
+
+// replyDue returns when a reply to a request is due: the end of the day the email asked for,
+// or waiting_on.nudge_after_days after it was sent
+func (p *Planner) replyDue(sentAt time.Time, replyBy string) time.Time {
+	if replyBy != "" {
+		if date, err := time.ParseInLocation("2006-01-02", replyBy, time.Local); err == nil && date.After(sentAt) {
+			return date.AddDate(0, 0, 1)
+		}
+	}
+	return sentAt.AddDate(0, 0, p.config.WaitingOn.NudgeAfterDays)
+}
+
+// nudgeWaitingItems sends a reminder listing overdue requests, at most once per
+// waiting_on.nudge_after_days for each
+func (p *Planner) nudgeWaitingItems(ctx context.Context) (int, error) {
+	interval := time.Duration(p.config.WaitingOn.NudgeAfterDays) * 24 * time.Hour
+	items, err := p.db.GetWaitingItemsToNudge(interval)
+	if err != nil {
+		return 0, err
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	var text strings.Builder
+	for _, item := range items {
+		text.WriteString(fmt.Sprintf("• %s: %s\n", item.Recipient, item.Request))
+	}
+	title := fmt.Sprintf("Still waiting on %d replies", len(items))
+	err = p.alert(ctx, "follow_up", title, text.String(), briefDelivery{
+		config.ChannelChat:  func() error { return p.google.Chat.SendWaitingReminder(ctx, p.db, items) },
+		config.ChannelSlack: func() error { return p.slack.SendWaitingReminder(ctx, p.db, items) },
+	})
+
+	// Failed deliveries stay queued in the outbox, so mark the items nudged either way to avoid repeats
+	for _, item := range items {
+		if markErr := p.db.MarkWaitingItemNudged(item.MessageID); markErr != nil {
+			log.Printf("Warning: %v", markErr)
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to send waiting-on reminder: %w", err)
+	}
+	return len(items), nil
+}
+
+// firstAddress returns the first email address in an address header
+func firstAddress(header string) string {
+	addresses, err := mail.ParseAddressList(header)
+	if err == nil && len(addresses) > 0 {
+		return addresses[0].Address
+	}
+	first, _, _ := strings.Cut(header, ",")
+	return strings.TrimSpace(first)
+}
@@ -0,0 +1,106 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// maxAwaitingReply caps the threads listed as awaiting a reply in the weekly review
+const maxAwaitingReply = 20
+
+// GenerateWeeklyReview builds the weekly review, has the LLM write its narrative, keeps it in
+// plan history and sends it to the configured channels
+func (p *Planner) GenerateWeeklyReview(ctx context.Context) error {
+	review, err := p.BuildWeeklyReview(time.Now())
+	if err != nil {
+		return err
+	}
+
+	review.Narrative, err = p.llm.WriteWeeklyReview(ctx, review)
+	if err != nil {
+		// The lists still stand on their own
+		log.Printf("Failed to write weekly review narrative: %v", err)
+		review.Narrative = ""
+	}
+
+	summary := fmt.Sprintf("%d completed, %d slipped, %d awaiting reply, %.1fh in meetings",
+		len(review.Completed), len(review.Slipped), len(review.AwaitingReply), review.MeetingHours)
+	if err := p.db.SavePlanHistory(db.PlanKindWeeklyReview, review.Until, summary, review); err != nil {
+		log.Printf("Failed to save weekly review to plan history: %v", err)
+	}
+
+	err = p.notify("weekly_review", briefDelivery{
+		config.ChannelChat:  func() error { return p.google.Chat.SendWeeklyReview(ctx, p.db, review) },
+		config.ChannelSlack: func() error { return p.slack.SendWeeklyReview(ctx, p.db, review) },
+		config.ChannelEmail: func() error { return p.google.Gmail.SendWeeklyReviewEmail(ctx, p.briefEmailTo(), review) },
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send weekly review: %w", err)
+	}
+
+	p.db.LogUsage("planner", "weekly_review", 0, 0, 0, nil)
+	return nil
+}
+
+// BuildWeeklyReview gathers the past seven days: tasks completed, tasks that slipped past their
+// due date, threads still awaiting a reply, time spent in meetings and tasks archived
+// automatically. The last review of each month also lists projects that have gone quiet.
+func (p *Planner) BuildWeeklyReview(now time.Time) (*db.WeeklyReview, error) {
+	review := &db.WeeklyReview{
+		Since: now.AddDate(0, 0, -7),
+		Until: now,
+	}
+
+	var err error
+	if review.Completed, err = p.db.GetTasksCompletedBetween(review.Since, now); err != nil {
+		return nil, err
+	}
+	if review.Slipped, err = p.db.GetOpenTasksDueBetween(review.Since, now); err != nil {
+		return nil, err
+	}
+
+	review.AwaitingReply, err = p.db.GetThreadsAwaitingReply(p.config.Google.UserEmail, review.Since, now, maxAwaitingReply)
+	if err != nil {
+		log.Printf("Failed to get threads awaiting reply: %v", err)
+	}
+
+	events, err := p.db.GetEventsBetween(review.Since, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		// Meetings have other people in them; all-day events aren't time spent
+		duration := event.EndTS.Sub(event.StartTS)
+		if len(event.Attendees) == 0 || duration >= 24*time.Hour {
+			continue
+		}
+		start, end := event.StartTS, event.EndTS
+		if start.Before(review.Since) {
+			start = review.Since
+		}
+		if end.After(now) {
+			end = now
+		}
+		review.MeetingCount++
+		review.MeetingHours += end.Sub(start).Hours()
+	}
+
+	if review.AutoArchived, err = p.db.GetAutoArchivedCounts(review.Since, now); err != nil {
+		log.Printf("Failed to count auto-archived tasks: %v", err)
+	}
+
+	// Once a month, the last review of the month, suggest closing projects that have gone quiet
+	if now.AddDate(0, 0, 7).Month() != now.Month() {
+		quietSince := now.AddDate(0, 0, -7*p.config.Planner.StaleProjectWeeks)
+		if review.StaleProjects, err = p.db.GetStaleProjects(quietSince); err != nil {
+			log.Printf("Failed to get stale projects: %v", err)
+		}
+	}
+
+	return review, nil
+}
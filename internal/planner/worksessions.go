@@ -0,0 +1,78 @@
+package planner
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// EffortStats compares tracked time with estimated effort, per task and per week
+type EffortStats struct {
+	Tasks map[string]*db.TaskEffort `json:"tasks"` // By task ID, for tasks with tracked time
+	Weeks []*db.WeekEffort          `json:"weeks"` // Oldest first, ending with this week
+}
+
+// StartWork starts timing work on a task, stopping the timer on any other. Focus mode is turned
+// on for its default length too, unless it's already on, so notifications wait until the work is
+// done.
+func (p *Planner) StartWork(taskID string) (*db.WorkSession, error) {
+	task, err := p.db.GetTaskByID(taskID)
+	if err != nil || task == nil {
+		return nil, fmt.Errorf("task %s not found", taskID)
+	}
+	if task.Status == "completed" {
+		return nil, fmt.Errorf("task %q is already completed", task.Title)
+	}
+
+	session, err := p.db.StartWorkSession(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Started work on %q", task.Title)
+
+	if !p.db.InFocusMode() {
+		if _, err := p.StartFocus(0); err != nil {
+			log.Printf("Failed to start focus mode: %v", err)
+		}
+	}
+	return session, nil
+}
+
+// GetWorkSession returns the running work session, or nil if no task is being timed
+func (p *Planner) GetWorkSession() (*db.WorkSession, error) {
+	return p.db.GetActiveWorkSession()
+}
+
+// StopWork stops the work timer and returns the finished session, or nil if none was running.
+// Focus mode is left to run out, or be ended separately.
+func (p *Planner) StopWork() (*db.WorkSession, error) {
+	session, err := p.db.StopWorkSession()
+	if err != nil || session == nil {
+		return session, err
+	}
+	log.Printf("Stopped work on %q after %s (%d interruptions)",
+		session.Task, session.Duration(time.Now()).Round(time.Minute), session.Interruptions)
+	return session, nil
+}
+
+// InterruptWork counts an interruption against the running work session and returns it, or nil
+// if none is running
+func (p *Planner) InterruptWork() (*db.WorkSession, error) {
+	return p.db.AddWorkInterruption()
+}
+
+// GetEffortStats returns the time tracked on each task against its estimate, and the last few
+// weeks of tracked time
+func (p *Planner) GetEffortStats() (*EffortStats, error) {
+	tasks, err := p.db.GetTaskEfforts()
+	if err != nil {
+		return nil, err
+	}
+	weeks, err := p.db.GetWeeklyEfforts(db.EffortWeeks)
+	if err != nil {
+		return nil, err
+	}
+	return &EffortStats{Tasks: tasks, Weeks: weeks}, nil
+}
@@ -0,0 +1,65 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+)
+
+// maxMessageChars keeps notifications short enough to read on a lock screen
+const maxMessageChars = 1000
+
+// Client sends mobile push notifications by publishing to an ntfy topic
+type Client struct {
+	config     config.Push
+	httpClient *http.Client
+}
+
+// NewClient creates a push client, or returns nil when push.url isn't set
+func NewClient(cfg *config.Config) *Client {
+	if cfg.Push.URL == "" {
+		return nil
+	}
+
+	return &Client{
+		config: cfg.Push,
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+	}
+}
+
+// Send publishes a notification with a title to the topic
+func (c *Client) Send(ctx context.Context, title, message string) error {
+	if len(message) > maxMessageChars {
+		message = message[:maxMessageChars-3] + "..."
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.URL, strings.NewReader(message))
+	if err != nil {
+		return fmt.Errorf("failed to create push request: %w", err)
+	}
+	req.Header.Set("Title", title)
+	req.Header.Set("Priority", "high")
+	req.Header.Set("Tags", "bell")
+	if c.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send push notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("push notification failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Package sanitize cleans LLM-generated text before it's stored or served. Summaries and task
+// descriptions are written by a model reading untrusted email, so they may carry HTML, scripts,
+// tracking images or javascript: links that a browser would act on when rendering them.
+package sanitize
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// blockTags are elements dropped along with everything inside them
+var blockTags = []string{"script", "style", "iframe", "object", "embed", "noscript", "template", "svg", "math"}
+
+var (
+	blocks   []*regexp.Regexp
+	comments = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+	// htmlTag matches an opening or closing tag, but not an email address in angle brackets
+	htmlTag = regexp.MustCompile(`(?i)</?[a-z][a-z0-9-]*(?:\s[^>]*)?/?>`)
+
+	// Markdown images are how tracking pixels survive as Markdown; only their alt text is kept.
+	// Targets may contain one level of parentheses, as in javascript:alert(1).
+	markdownImage = regexp.MustCompile(`!\[([^\]]*)\]\((?:[^()]|\([^()]*\))*\)`)
+	markdownLink  = regexp.MustCompile(`\[([^\]]*)\]\(\s*((?:[^()\s]|\([^()]*\))*)(?:\s[^)]*)?\)`)
+)
+
+func init() {
+	for _, tag := range blockTags {
+		blocks = append(blocks, regexp.MustCompile(`(?is)<`+tag+`\b.*?</`+tag+`\s*>`))
+	}
+}
+
+// Markdown strips HTML and unsafe links from Markdown or plain text. Scripts, styles and
+// embedded content go entirely; other tags are removed leaving their text; images become their
+// alt text; and links keep their target only if it's http, https or mailto.
+func Markdown(text string) string {
+	if !strings.ContainsAny(text, "<[") {
+		return text
+	}
+
+	for _, block := range blocks {
+		text = block.ReplaceAllString(text, "")
+	}
+	text = comments.ReplaceAllString(text, "")
+	text = htmlTag.ReplaceAllString(text, "")
+	text = markdownImage.ReplaceAllString(text, "$1")
+
+	return markdownLink.ReplaceAllStringFunc(text, func(link string) string {
+		parts := markdownLink.FindStringSubmatch(link)
+		if URL(parts[2]) == "" {
+			return parts[1]
+		}
+		return link
+	})
+}
+
+// URL returns a link target if it's safe to put in an href: absolute http, https or mailto.
+// Anything else, including javascript: and data: URLs, returns "".
+func URL(target string) string {
+	u, err := url.Parse(strings.TrimSpace(target))
+	if err != nil {
+		return ""
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		if u.Host == "" {
+			return ""
+		}
+		return u.String()
+	case "mailto":
+		return u.String()
+	}
+	return ""
+}
@@ -0,0 +1,201 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/llm"
+)
+
+// canarySender is who the canary thread comes from; the .invalid domain can never be a real
+// contact
+const canarySender = "Focus Agent Canary <canary@focus-agent.invalid>"
+
+// RunCanary sends a synthetic thread through summarization, task extraction, scoring and brief
+// selection, timing each stage against canary.budget_seconds, and alerts the user if a stage fails
+// or runs over its budget. The canary's thread and task are removed afterwards, and any left by a
+// run that didn't finish are removed first. It returns the problems found, if any.
+func (s *Scheduler) RunCanary() []string {
+	// Hold the processing lock so a regular run can't pick up the canary thread, or be slowed by it
+	s.processingMutex.Lock()
+	defer s.processingMutex.Unlock()
+
+	s.cleanupCanary()
+	defer s.cleanupCanary()
+
+	log.Println("Running pipeline canary...")
+	problems := s.runCanaryStages()
+	if len(problems) == 0 {
+		log.Println("Pipeline canary passed")
+		return nil
+	}
+
+	for _, problem := range problems {
+		log.Printf("Pipeline canary: %s", problem)
+	}
+	if s.planner != nil {
+		if err := s.planner.SendCanaryAlert(s.ctx, problems); err != nil {
+			log.Printf("Failed to send canary alert: %v", err)
+		}
+	}
+	return problems
+}
+
+// runCanaryStages runs the canary through each stage in turn, stopping at the first that fails
+func (s *Scheduler) runCanaryStages() []string {
+	var problems []string
+	stage := func(name string, fn func() error) bool {
+		start := time.Now()
+		err := fn()
+		elapsed := time.Since(start)
+		s.db.LogUsage("canary", name, 0, 0, elapsed, err)
+
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s failed: %v", name, err))
+			return false
+		}
+		if budget := time.Duration(s.config.Canary.BudgetSeconds[name]) * time.Second; budget > 0 && elapsed > budget {
+			problems = append(problems, fmt.Sprintf("%s took %s, over its %s budget",
+				name, elapsed.Round(100*time.Millisecond), budget))
+		}
+		log.Printf("Canary %s took %s", name, elapsed.Round(100*time.Millisecond))
+		return true
+	}
+
+	threadID, messages, err := s.createCanaryThread()
+	if err != nil {
+		return []string{fmt.Sprintf("failed to create the canary thread: %v", err)}
+	}
+
+	var summary string
+	ok := stage("summarize", func() error {
+		metadata := llm.ThreadMetadata{
+			QueueSize:    1,
+			SenderEmail:  messages[0].From,
+			Timestamp:    messages[0].Timestamp,
+			MessageCount: len(messages),
+		}
+		if summary, err = s.summarizeThread(threadID, messages, metadata); err != nil {
+			return err
+		}
+		if summary == "" {
+			return fmt.Errorf("summary is empty")
+		}
+		return s.db.SaveThread(&db.Thread{ID: threadID, Summary: summary, LastSynced: time.Now()})
+	})
+	if !ok {
+		return problems
+	}
+
+	var task *db.Task
+	ok = stage("extract", func() error {
+		tasks, err := s.llm.ExtractTasksFromMessages(s.ctx, summary, messages, nil, nil)
+		if err != nil {
+			return err
+		}
+		if len(tasks) == 0 {
+			return fmt.Errorf("no task was extracted from a thread asking for one")
+		}
+		task = tasks[0]
+		return nil
+	})
+	if !ok {
+		return problems
+	}
+
+	ok = stage("score", func() error {
+		// The canary's own ID and source keep it from being merged into a real task
+		task.ID = threadID + "-task"
+		task.Source = "canary"
+		task.SourceID = threadID
+		task.Stakeholder = ""
+		task.Status = "pending"
+		if err := s.db.SaveTask(task); err != nil {
+			return err
+		}
+		if err := s.planner.PrioritizeTask(s.ctx, task); err != nil {
+			return err
+		}
+		if task.Score <= 0 {
+			return fmt.Errorf("task scored %.2f", task.Score)
+		}
+		return nil
+	})
+	if !ok {
+		return problems
+	}
+
+	stage("brief", func() error {
+		// Lift the canary to the top so the check is whether brief selection works, not whether
+		// its score beats today's real tasks
+		if _, err := s.db.Exec(`UPDATE tasks SET score = (SELECT COALESCE(MAX(score), 0) + 1 FROM tasks) WHERE id = ?`, task.ID); err != nil {
+			return fmt.Errorf("failed to raise canary score: %w", err)
+		}
+		tasks, err := s.planner.BriefTasks()
+		if err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(tasks, func(t *db.Task) bool { return t.ID == task.ID }) {
+			return fmt.Errorf("the canary task wasn't selected for the brief")
+		}
+		return nil
+	})
+	return problems
+}
+
+// createCanaryThread saves a short thread from the canary sender to the user with a clear
+// request and due date, returning its ID and messages newest first
+func (s *Scheduler) createCanaryThread() (string, []*db.Message, error) {
+	now := time.Now()
+	threadID := fmt.Sprintf("%s%d", db.CanaryPrefix, now.UnixNano())
+	if err := s.db.SaveThread(&db.Thread{ID: threadID, LastSynced: now}); err != nil {
+		return "", nil, err
+	}
+
+	to := s.config.Google.UserEmail
+	if to == "" {
+		to = "me"
+	}
+	due := now.AddDate(0, 0, 1).Format("Monday, January 2")
+	messages := []*db.Message{
+		{
+			ID:        threadID + "-2",
+			ThreadID:  threadID,
+			From:      canarySender,
+			To:        to,
+			Subject:   "Re: Quarterly vendor report",
+			Snippet:   "Could you send me the signed vendor report by " + due + "?",
+			Body:      "Hi,\n\nThanks for the draft. Could you send me the signed quarterly vendor report by " + due + "? Finance needs it to close the quarter.\n\nThanks",
+			Timestamp: now,
+		},
+		{
+			ID:        threadID + "-1",
+			ThreadID:  threadID,
+			From:      to,
+			To:        canarySender,
+			Subject:   "Quarterly vendor report",
+			Snippet:   "Here's the draft of the quarterly vendor report.",
+			Body:      "Hi,\n\nHere's the draft of the quarterly vendor report for your review.\n\nBest",
+			Timestamp: now.Add(-time.Hour),
+		},
+	}
+	for _, msg := range messages {
+		msg.LastMsgID = messages[0].ID
+		if err := s.db.SaveMessage(msg); err != nil {
+			return "", nil, err
+		}
+	}
+	return threadID, messages, nil
+}
+
+// cleanupCanary removes the canary's thread, task and anything derived from them
+func (s *Scheduler) cleanupCanary() {
+	if n, err := s.db.DeleteCanaryData(); err != nil {
+		log.Printf("Failed to clean up canary data: %v", err)
+	} else if n > 0 {
+		log.Printf("Removed %d canary thread(s)", n)
+	}
+}
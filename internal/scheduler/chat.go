@@ -0,0 +1,182 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/events"
+)
+
+// Chat messages taken through task extraction per run, and the earlier messages in the same
+// space shown with a thread's new messages for context
+const (
+	maxChatMessagesPerRun = 200
+	chatContextMessages   = 10
+)
+
+// syncChat reads new messages from the Chat spaces and DMs under chat.sync, then extracts tasks
+// from them
+func (s *Scheduler) syncChat() {
+	log.Println("Starting Chat sync...")
+	s.events.Publish(events.SyncStarted, map[string]interface{}{"source": "chat"})
+
+	started := time.Now()
+
+	err := s.google.Chat.SyncMessages(s.ctx, s.db)
+	s.publishSyncResult("chat", started, err)
+	if err != nil {
+		log.Printf("Chat sync failed: %v", err)
+		s.db.LogUsage("chat", "sync", 0, 0, 0, err)
+	}
+
+	// Messages read before a failure are processed all the same
+	s.processChatMessages()
+}
+
+// processChatMessages extracts tasks from Chat messages that haven't been processed yet. Each
+// thread's new messages go to the LLM as one conversation, after the last few messages before
+// them in the space, so every task links to the thread it was asked in. A thread whose
+// extraction fails is tried again on the next run.
+func (s *Scheduler) processChatMessages() {
+	s.processingMutex.Lock()
+	defer s.processingMutex.Unlock()
+
+	if !s.config.Limits.EnableAIProcessing {
+		return
+	}
+
+	messages, err := s.db.GetUnprocessedChatMessages(maxChatMessagesPerRun)
+	if err != nil {
+		log.Printf("Failed to get unprocessed Chat messages: %v", err)
+		return
+	}
+	if len(messages) == 0 {
+		return
+	}
+
+	start := time.Now()
+	total := 0
+	for _, conversation := range chatConversations(messages) {
+		created, err := s.extractChatTasks(conversation)
+		if err != nil {
+			log.Printf("Failed to extract tasks from Chat thread %s: %v", chatSourceID(conversation[0]), err)
+			continue
+		}
+		total += created
+
+		ids := make([]string, 0, len(conversation))
+		for _, msg := range conversation {
+			ids = append(ids, msg.ID)
+		}
+		if err := s.db.MarkChatMessagesProcessed(ids); err != nil {
+			log.Printf("Failed to mark Chat messages processed: %v", err)
+		}
+	}
+
+	if total > 0 {
+		log.Printf("Extracted %d task(s) from Chat messages", total)
+		s.applyTagRules(start)
+		if err := s.planner.PrioritizeTasks(s.ctx); err != nil {
+			log.Printf("Failed to prioritize tasks: %v", err)
+		}
+	}
+}
+
+// chatConversations groups messages by thread, keeping their order within each thread and
+// ordering threads by their first message
+func chatConversations(messages []*db.ChatMessage) [][]*db.ChatMessage {
+	var conversations [][]*db.ChatMessage
+	index := map[string]int{}
+	for _, msg := range messages {
+		key := chatSourceID(msg)
+		i, ok := index[key]
+		if !ok {
+			i = len(conversations)
+			index[key] = i
+			conversations = append(conversations, nil)
+		}
+		conversations[i] = append(conversations[i], msg)
+	}
+	return conversations
+}
+
+// chatSourceID is the source ID of tasks extracted from a message: its thread, or its space when
+// Chat gave it no thread
+func chatSourceID(msg *db.ChatMessage) string {
+	if msg.Thread != "" {
+		return msg.Thread
+	}
+	return msg.Space
+}
+
+// extractChatTasks extracts and saves the tasks in a thread's new messages, returning how many
+// were new
+func (s *Scheduler) extractChatTasks(conversation []*db.ChatMessage) (int, error) {
+	first := conversation[0]
+	earlier, err := s.db.GetChatMessagesBefore(first.Space, first.Timestamp, chatContextMessages)
+	if err != nil {
+		return 0, err
+	}
+
+	tasks, err := s.llm.ExtractTasks(s.ctx, chatTranscript(earlier, conversation))
+	if err != nil {
+		return 0, err
+	}
+
+	sourceID := chatSourceID(first)
+
+	created := 0
+	for _, task := range tasks {
+		task.Source = db.TaskSourceGoogleChat
+		task.SourceID = sourceID
+		if err := s.db.AssignExtractedTaskID(task); err != nil {
+			log.Printf("Failed to assign task ID: %v", err)
+			continue
+		}
+
+		merged, taskEmb := s.mergeIfDuplicate(task)
+		if merged {
+			continue
+		}
+
+		isNew, err := s.db.SaveExtractedTask(task)
+		if err != nil {
+			log.Printf("Failed to save extracted task: %v", err)
+			continue
+		}
+
+		s.saveDedupEmbedding(taskEmb)
+		if isNew {
+			s.publishTaskCreated(task)
+			created++
+		}
+	}
+	return created, nil
+}
+
+// chatTranscript lays out a Chat conversation for task extraction, with the messages before the
+// new ones so requests can be read in context without being extracted again
+func chatTranscript(earlier, conversation []*db.ChatMessage) string {
+	var b strings.Builder
+	space := conversation[0].SpaceName
+	if space == "" {
+		space = "a direct message"
+	}
+	fmt.Fprintf(&b, "Google Chat conversation in %s. My own messages are marked \"Me\".\n", space)
+
+	if len(earlier) > 0 {
+		b.WriteString("\nEarlier messages, for context only (tasks in them are extracted separately):\n")
+		for _, msg := range earlier {
+			b.WriteString(msg.Describe() + "\n")
+		}
+	}
+
+	b.WriteString("\nNew messages:\n")
+	for _, msg := range conversation {
+		b.WriteString(msg.Describe() + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+)
+
+// everyDay is the weekday of clock jobs that run daily
+const everyDay = -1
+
+// clockJob is a job run at a time of day in the schedule's timezone, such as a brief
+type clockJob struct {
+	name    string
+	clock   string // "HH:MM"
+	weekday int    // Cron day of week, or everyDay
+	run     func()
+}
+
+// spec returns the cron spec running the job at its time in the given timezone
+func (j clockJob) spec(timezone string) string {
+	weekday := "*"
+	if j.weekday != everyDay {
+		weekday = fmt.Sprint(j.weekday)
+	}
+	return fmt.Sprintf("CRON_TZ=%s 0 %s %s * * %s",
+		timezone,
+		j.clock[3:], // minutes
+		j.clock[:2], // hours
+		weekday,
+	)
+}
+
+// scheduleClockJob schedules a job at a time of day, on the given weekday or every day, in the
+// schedule's timezone. It follows the timezone when RescheduleClockJobs is called.
+func (s *Scheduler) scheduleClockJob(name, clock string, weekday int, run func()) error {
+	job := clockJob{name: name, clock: clock, weekday: weekday, run: run}
+
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+	if err := s.addClockJob(job); err != nil {
+		return err
+	}
+	s.clockJobs = append(s.clockJobs, job)
+	return nil
+}
+
+// addClockJob adds a clock job to cron in the schedule's current timezone. jobsMu must be held.
+func (s *Scheduler) addClockJob(job clockJob) error {
+	id, err := s.cron.AddFunc(job.spec(s.config.Schedule.ActiveTimezone()), s.observeJob(job.name, job.run))
+	if err != nil {
+		return err
+	}
+	s.jobs[job.name] = id
+	return nil
+}
+
+// RescheduleClockJobs moves the briefs and other jobs run at a time of day to the schedule's
+// current timezone, after it's been switched for travel
+func (s *Scheduler) RescheduleClockJobs() error {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	timezone := s.config.Schedule.ActiveTimezone()
+	for _, job := range s.clockJobs {
+		if id, ok := s.jobs[job.name]; ok {
+			s.cron.Remove(id)
+			delete(s.jobs, job.name)
+		}
+		if err := s.addClockJob(job); err != nil {
+			return fmt.Errorf("failed to reschedule %s in %s: %w", job.name, timezone, err)
+		}
+	}
+	if len(s.clockJobs) > 0 {
+		log.Printf("Rescheduled %d briefs and clock jobs in %s", len(s.clockJobs), timezone)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/embeddings"
+)
+
+// mergeIfDuplicate checks a newly extracted task against recent pending tasks and, if one is
+// semantically close enough, merges the new task into it. It returns true when the task was
+// merged and should not be inserted. The new task's embedding is returned so it can be saved
+// after insertion; it is nil if dedup is disabled or the embedding could not be generated.
+func (s *Scheduler) mergeIfDuplicate(task *db.Task) (bool, *embeddings.TaskEmbedding) {
+	if s.embeddings == nil || !s.config.Dedup.Enabled {
+		return false, nil
+	}
+
+	taskEmb, err := embeddings.GenerateTaskEmbedding(s.ctx, s.embeddings, task)
+	if err != nil {
+		log.Printf("Dedup: failed to embed task '%s', inserting without check: %v", task.Title, err)
+		return false, nil
+	}
+
+	since := time.Now().AddDate(0, 0, -s.config.Dedup.LookbackDays)
+	matchID, similarity, err := embeddings.FindMostSimilarPendingTask(s.db, taskEmb.Embedding, task.ID, since)
+	if err != nil {
+		log.Printf("Dedup: similarity search failed for '%s': %v", task.Title, err)
+		return false, taskEmb
+	}
+
+	if matchID == "" || similarity < s.config.Dedup.SimilarityThreshold {
+		return false, taskEmb
+	}
+
+	if err := s.db.MergeDuplicateTask(matchID, task, similarity); err != nil {
+		log.Printf("Dedup: failed to merge '%s' into %s, inserting instead: %v", task.Title, matchID, err)
+		return false, taskEmb
+	}
+
+	log.Printf("Dedup: merged '%s' into existing task %s (similarity %.3f)", task.Title, matchID, similarity)
+	return true, nil
+}
+
+// saveDedupEmbedding stores a new task's embedding so later tasks are compared against it
+func (s *Scheduler) saveDedupEmbedding(taskEmb *embeddings.TaskEmbedding) {
+	if taskEmb == nil {
+		return
+	}
+	if err := embeddings.SaveTaskEmbedding(s.db, taskEmb); err != nil {
+		log.Printf("Dedup: failed to save embedding for task %s: %v", taskEmb.TaskID, err)
+	}
+}
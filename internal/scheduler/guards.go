@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// checkGuards compares the database's size, the free space on its disk and today's Google API
+// requests with the limits in guards. Low-priority work pauses while any of them is near its
+// limit, and the user is alerted the first time each one gets there.
+func (s *Scheduler) checkGuards() {
+	guards := s.config.Guards
+	near := func(used, limit int64) bool {
+		return limit > 0 && used*100 >= limit*int64(guards.ThrottlePercent)
+	}
+	warnings := make(map[string]string)
+
+	if usage, err := s.db.GetDiskUsage(); err != nil {
+		log.Printf("Failed to check database disk usage: %v", err)
+	} else {
+		limit := int64(guards.MaxDBSizeMB) << 20
+		if near(usage.DBBytes, limit) {
+			warning := fmt.Sprintf("The database is %d MB, %d%% of guards.max_db_size_mb (%d MB)",
+				usage.DBBytes>>20, usage.DBBytes*100/limit, guards.MaxDBSizeMB)
+			if s.config.Database.ColdStorageDays == 0 {
+				warning += "; database.cold_storage_days can move old message bodies out of it"
+			}
+			warnings["database"] = warning
+		}
+		if minFree := uint64(guards.MinFreeDiskMB) << 20; minFree > 0 && usage.FreeBytes < minFree {
+			warnings["disk"] = fmt.Sprintf("Only %d MB is free on the database's disk, below guards.min_free_disk_mb (%d MB)",
+				usage.FreeBytes>>20, guards.MinFreeDiskMB)
+		}
+	}
+
+	if s.google != nil {
+		calls := s.google.APICallsToday()
+		for service, quota := range guards.GoogleDailyCalls {
+			if used := int64(calls[service]); near(used, int64(quota)) {
+				warnings["google:"+service] = fmt.Sprintf("The %s API has used %d of its %d requests today (quotas reset at midnight Pacific)",
+					service, used, quota)
+			}
+		}
+	}
+
+	s.guardMu.Lock()
+	var tripped []string
+	for resource, warning := range warnings {
+		if _, ok := s.guardWarnings[resource]; !ok {
+			tripped = append(tripped, warning)
+		}
+	}
+	cleared := len(s.guardWarnings) > 0 && len(warnings) == 0
+	s.guardWarnings = warnings
+	s.guardMu.Unlock()
+
+	if cleared {
+		log.Println("Resource guards clear, resuming low-priority work")
+	}
+	if len(tripped) == 0 {
+		return
+	}
+
+	sort.Strings(tripped)
+	for _, warning := range tripped {
+		log.Printf("Resource guard: %s", warning)
+	}
+	if s.planner != nil {
+		if err := s.planner.SendGuardAlert(s.ctx, tripped); err != nil {
+			log.Printf("Failed to send resource guard alert: %v", err)
+		}
+	}
+}
+
+// GuardWarnings returns why low-priority work is paused, or nothing if every resource is within
+// its limits
+func (s *Scheduler) GuardWarnings() []string {
+	s.guardMu.Lock()
+	defer s.guardMu.Unlock()
+
+	warnings := make([]string, 0, len(s.guardWarnings))
+	for _, warning := range s.guardWarnings {
+		warnings = append(warnings, warning)
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// lowPriorityPaused reports whether a piece of low-priority work should wait for the resource
+// guards to clear, logging why it's being skipped
+func (s *Scheduler) lowPriorityPaused(work string) bool {
+	warnings := s.GuardWarnings()
+	if len(warnings) == 0 {
+		return false
+	}
+	log.Printf("Skipping %s while resources are short: %s", work, strings.Join(warnings, "; "))
+	return true
+}
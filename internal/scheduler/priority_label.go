@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/alexrabarts/focus-agent/internal/google"
+)
+
+// updatePriorityLabel applies the Gmail priority label to a thread scored at or above the
+// threshold, and removes it from a previously labelled thread whose score has dropped.
+// Failures are logged and never fail thread processing.
+func (s *Scheduler) updatePriorityLabel(threadID string, priorityScore float64) {
+	cfg := s.config.Google.PriorityLabel
+	if !cfg.Enabled || s.google == nil || s.google.Gmail == nil || !google.IsGmailThreadID(threadID) {
+		return
+	}
+
+	labeled, err := s.db.IsThreadPriorityLabeled(threadID)
+	if err != nil {
+		log.Printf("Priority label: failed to read state for thread %s: %v", threadID, err)
+		return
+	}
+
+	apply := priorityScore >= cfg.Threshold
+	if apply == labeled {
+		return
+	}
+
+	labelID, err := s.priorityLabelID()
+	if err != nil {
+		log.Printf("Priority label: %v", err)
+		return
+	}
+
+	if err := s.google.Gmail.SetThreadLabel(s.ctx, threadID, labelID, apply); err != nil {
+		log.Printf("Priority label: %v", err)
+		s.db.LogUsage("gmail", "priority_label", 0, 0, 0, err)
+		return
+	}
+	if err := s.db.SetThreadPriorityLabeled(threadID, apply); err != nil {
+		log.Printf("Priority label: %v", err)
+		return
+	}
+
+	if apply {
+		log.Printf("Labelled thread %s %q (priority=%.0f)", threadID, cfg.Name, priorityScore)
+	} else {
+		log.Printf("Removed %q from thread %s (priority=%.0f)", cfg.Name, threadID, priorityScore)
+	}
+}
+
+// pendingThreadScore returns the highest score among a thread's pending tasks, which is what the
+// priority label follows; completed and cancelled tasks no longer make a thread a priority
+func (s *Scheduler) pendingThreadScore(threadID string) (float64, error) {
+	var score sql.NullFloat64
+	query := `SELECT MAX(score) FROM tasks WHERE source = 'gmail' AND source_id = ? AND status = 'pending'`
+	if err := s.db.QueryRow(query, threadID).Scan(&score); err != nil {
+		return 0, fmt.Errorf("failed to read task scores for thread %s: %w", threadID, err)
+	}
+	return score.Float64, nil
+}
+
+// maxPriorityLabelChanges caps the Gmail label changes made by one reconcile pass
+const maxPriorityLabelChanges = 50
+
+// reconcilePriorityLabels adds or removes the Gmail priority label on threads whose task scores
+// changed without the thread being processed again, e.g. after priority feedback or a change
+// of priorities, so the label follows rescoring
+func (s *Scheduler) reconcilePriorityLabels() {
+	cfg := s.config.Google.PriorityLabel
+	if !cfg.Enabled || s.google == nil || s.google.Gmail == nil {
+		return
+	}
+
+	changes, err := s.db.GetPriorityLabelChanges(cfg.Threshold, maxPriorityLabelChanges)
+	if err != nil {
+		log.Printf("Priority label: %v", err)
+		return
+	}
+	for threadID, score := range changes {
+		s.updatePriorityLabel(threadID, score)
+	}
+}
+
+// priorityLabelID looks up (or creates) the priority label once and caches its ID
+func (s *Scheduler) priorityLabelID() (string, error) {
+	s.priorityLabelMu.Lock()
+	defer s.priorityLabelMu.Unlock()
+
+	if s.priorityLabel != "" {
+		return s.priorityLabel, nil
+	}
+
+	id, err := s.google.Gmail.EnsureLabel(s.ctx, s.config.Google.PriorityLabel.Name)
+	if err != nil {
+		return "", err
+	}
+	s.priorityLabel = id
+	return id, nil
+}
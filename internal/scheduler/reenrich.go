@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// reenrichUpdatedTasks rewrites the descriptions of open tasks whose thread has had new
+// messages since they were enriched, a few per run and each at most once per
+// limits.reenrich_min_minutes. A description the user has edited is left alone.
+func (s *Scheduler) reenrichUpdatedTasks() {
+	if !s.config.Limits.ReenrichTasks || s.lowPriorityPaused("task re-enrichment") {
+		return
+	}
+
+	throttle := time.Duration(s.config.Limits.ReenrichMinMinutes) * time.Minute
+	candidates, err := s.db.GetTasksToReenrich(throttle, s.config.Limits.ReenrichPerRun)
+	if err != nil {
+		log.Printf("Failed to find tasks to re-enrich: %v", err)
+		return
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	log.Printf("Re-enriching %d task(s) whose threads have new messages", len(candidates))
+
+	updated := 0
+	for _, c := range candidates {
+		task, err := s.db.GetTaskByID(c.TaskID)
+		if err != nil {
+			log.Printf("Failed to load task %s for re-enrichment: %v", c.TaskID, err)
+			continue
+		}
+
+		messages, err := s.db.GetThreadMessages(c.ThreadID)
+		if err != nil || len(messages) == 0 {
+			log.Printf("Failed to get messages for thread %s: %v", c.ThreadID, err)
+			continue
+		}
+		slices.Reverse(messages) // Newest first, as when the task was extracted
+		s.loadAttachments(messages)
+
+		description, err := s.llm.EnrichTaskDescription(s.ctx, task, messages)
+		if err != nil {
+			// Left for the next run; the throttle only starts once a task is re-enriched
+			log.Printf("Failed to re-enrich task %s: %v", task.ID, err)
+			continue
+		}
+
+		// New messages often don't change what needs doing, e.g. a "thanks" or a scheduling
+		// reply, so only a description that actually reads differently is saved
+		if descriptionChanged(task.Description, description) {
+			if err := s.db.SetTaskDescription(task.ID, description); err != nil {
+				log.Printf("Failed to save re-enriched task %s: %v", task.ID, err)
+				continue
+			}
+			updated++
+		}
+		if err := s.db.MarkTaskEnriched(task.ID, c.MessageCount, c.LastMessageTS); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	log.Printf("Re-enrichment complete: %d of %d task description(s) changed", updated, len(candidates))
+}
+
+// descriptionChanged compares two descriptions ignoring case and whitespace
+func descriptionChanged(before, after string) bool {
+	normalize := func(text string) string {
+		return strings.ToLower(strings.Join(strings.Fields(text), " "))
+	}
+	return normalize(before) != normalize(after)
+}
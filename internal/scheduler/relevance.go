@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"log"
+	"strings"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/llm"
+)
+
+// relevantReputation is the sender reputation at or above which their threads are relevant
+const relevantReputation = 0.4
+
+// threadMetadata prepares the metadata for smart model selection, given a thread's messages
+// newest first
+func (s *Scheduler) threadMetadata(messages []*db.Message, queueSize int) llm.ThreadMetadata {
+	metadata := llm.ThreadMetadata{
+		QueueSize:    queueSize,
+		SenderEmail:  messages[0].From, // Most recent message's sender
+		Timestamp:    messages[0].Timestamp,
+		MessageCount: len(messages),
+	}
+	if stats := s.threadStarterStats(messages); stats != nil {
+		metadata.SenderReputation = stats.Reputation()
+		metadata.SenderKnown = true
+	}
+	return metadata
+}
+
+// threadStarterStats returns the stats of whoever started a thread, given its messages newest
+// first, or nil if the user started it or the sender has started too few threads to go by
+func (s *Scheduler) threadStarterStats(messages []*db.Message) *db.SenderStats {
+	starter := messages[len(messages)-1].From
+	if userEmail := strings.ToLower(s.config.Google.UserEmail); userEmail != "" && strings.Contains(strings.ToLower(starter), userEmail) {
+		return nil
+	}
+
+	stats, err := s.db.GetSenderStats(starter)
+	if err != nil {
+		log.Printf("Failed to get sender stats: %v", err)
+		return nil
+	}
+	if stats == nil || stats.Threads < s.config.People.SenderMinThreads {
+		return nil
+	}
+	return stats
+}
+
+// isRelevantToUser decides whether a thread is for the user to act on. Once its starter has
+// enough history that's learned from how the user treated their earlier threads; until then a
+// thread is relevant if it reached the inbox and tasks were extracted from it.
+func (s *Scheduler) isRelevantToUser(messages []*db.Message, tasks []*db.Task, inInbox bool) bool {
+	if stats := s.threadStarterStats(messages); stats != nil {
+		return stats.Reputation() >= relevantReputation
+	}
+	return inInbox && len(tasks) > 0
+}
@@ -2,9 +2,7 @@ package scheduler
 
 import (
 	"context"
-	"crypto/sha1"
 	"database/sql"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
@@ -16,10 +14,15 @@ import (
 
 	"github.com/alexrabarts/focus-agent/internal/config"
 	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/embeddings"
+	"github.com/alexrabarts/focus-agent/internal/events"
 	"github.com/alexrabarts/focus-agent/internal/front"
 	"github.com/alexrabarts/focus-agent/internal/google"
 	"github.com/alexrabarts/focus-agent/internal/llm"
+	"github.com/alexrabarts/focus-agent/internal/metrics"
+	"github.com/alexrabarts/focus-agent/internal/msgraph"
 	"github.com/alexrabarts/focus-agent/internal/planner"
+	"github.com/alexrabarts/focus-agent/internal/slack"
 )
 
 // Scheduler manages all scheduled jobs
@@ -30,19 +33,29 @@ type Scheduler struct {
 	llm               llm.Client
 	planner           *planner.Planner
 	front             *front.Client // Front client (nil if disabled)
+	slack             *slack.Client // Slack client (nil unless slack is a notification channel)
+	embeddings        *embeddings.Client // Embeddings client for task dedup and search (nil if both disabled)
+	events            *events.Bus        // Event bus for /api/events (nil if not serving the API)
+	msgraph           *msgraph.Client    // Microsoft 365 client (nil if disabled)
 	config            *config.Config
 	jobs              map[string]cron.EntryID
+	jobsMu            sync.Mutex // Guards jobs while clock jobs are rescheduled
+	clockJobs         []clockJob // Jobs run at a time of day, moved when the timezone changes
 	ctx               context.Context
 	cancel            context.CancelFunc
 	processingMutex   sync.Mutex // Prevents concurrent AI processing runs
+	priorityLabel     string     // Cached Gmail label ID for google.priority_label
+	priorityLabelMu   sync.Mutex
+	guardWarnings     map[string]string // Resources near their limit, keyed by resource, from the last guard check
+	guardMu           sync.Mutex
 }
 
 // New creates a new scheduler
 func New(database *db.DB, googleClients *google.Clients, llmClient llm.Client, plannerService *planner.Planner, frontClient *front.Client, cfg *config.Config) *Scheduler {
 	// Create cron with timezone
-	location, err := time.LoadLocation(cfg.Schedule.Timezone)
+	location, err := time.LoadLocation(cfg.Schedule.ActiveTimezone())
 	if err != nil {
-		log.Printf("Invalid timezone %s, using local: %v", cfg.Schedule.Timezone, err)
+		log.Printf("Invalid timezone %s, using local: %v", cfg.Schedule.ActiveTimezone(), err)
 		location = time.Local
 	}
 
@@ -57,27 +70,80 @@ func New(database *db.DB, googleClients *google.Clients, llmClient llm.Client, p
 
 	ctx, cancel := context.WithCancel(context.Background())
 
+	var slackClient *slack.Client
+	if cfg.Notify.HasChannel(config.ChannelSlack) {
+		slackClient = slack.NewClient(cfg)
+	}
+
+	var embClient *embeddings.Client
+	if cfg.Dedup.Enabled || cfg.Search.Semantic {
+		ollamaURL := "http://localhost:11434"
+		if len(cfg.Ollama.Hosts) > 0 {
+			ollamaURL = cfg.Ollama.Hosts[0].URL
+		}
+		embClient = embeddings.NewClient(ollamaURL, "nomic-embed-text")
+	}
+
 	return &Scheduler{
-		cron:    c,
-		db:      database,
-		google:  googleClients,
-		llm:     llmClient,
-		planner: plannerService,
-		front:   frontClient,
-		config:  cfg,
-		jobs:    make(map[string]cron.EntryID),
-		ctx:     ctx,
-		cancel:  cancel,
+		cron:       c,
+		db:         database,
+		google:     googleClients,
+		llm:        llmClient,
+		planner:    plannerService,
+		front:      frontClient,
+		slack:      slackClient,
+		embeddings: embClient,
+		config:     cfg,
+		jobs:       make(map[string]cron.EntryID),
+		ctx:        ctx,
+		cancel:     cancel,
 	}
 }
 
+// SetEvents sets the bus that sync and processing events are published to
+func (s *Scheduler) SetEvents(bus *events.Bus) {
+	s.events = bus
+}
+
+// SetMSGraph sets the Microsoft 365 client used for Outlook mail and calendar sync
+func (s *Scheduler) SetMSGraph(client *msgraph.Client) {
+	s.msgraph = client
+}
+
+// publishTaskCreated publishes a newly saved extracted task
+func (s *Scheduler) publishTaskCreated(task *db.Task) {
+	metrics.TasksExtracted.Inc(task.Source)
+	s.events.Publish(events.TaskCreated, map[string]interface{}{
+		"id":        task.ID,
+		"title":     task.Title,
+		"source":    task.Source,
+		"source_id": task.SourceID,
+		"score":     task.Score,
+	})
+}
+
+// publishSyncResult publishes the outcome of a sync run and records it in the metrics
+func (s *Scheduler) publishSyncResult(source string, started time.Time, err error) {
+	metrics.SyncDuration.Observe(time.Since(started).Seconds(), source)
+	if err != nil {
+		metrics.Syncs.Inc(source, "error")
+		s.events.Publish(events.SyncFailed, map[string]interface{}{"source": source, "error": err.Error()})
+		return
+	}
+
+	// Failed syncs are logged as usage, which records an issue; a successful one clears it
+	s.db.TrackResult(source, "sync", nil)
+	metrics.Syncs.Inc(source, "ok")
+	s.events.Publish(events.SyncCompleted, map[string]interface{}{"source": source})
+}
+
 // Start begins the scheduler
 func (s *Scheduler) Start() error {
 	log.Println("Starting scheduler...")
 
 	// Schedule Gmail sync
 	gmailSpec := fmt.Sprintf("@every %dm", s.config.Google.PollingMinutes.Gmail)
-	gmailID, err := s.cron.AddFunc(gmailSpec, s.syncGmail)
+	gmailID, err := s.cron.AddFunc(gmailSpec, s.observeJob("gmail", s.syncGmail))
 	if err != nil {
 		return fmt.Errorf("failed to schedule Gmail sync: %w", err)
 	}
@@ -86,7 +152,7 @@ func (s *Scheduler) Start() error {
 
 	// Schedule Drive sync
 	driveSpec := fmt.Sprintf("@every %dm", s.config.Google.PollingMinutes.Drive)
-	driveID, err := s.cron.AddFunc(driveSpec, s.syncDrive)
+	driveID, err := s.cron.AddFunc(driveSpec, s.observeJob("drive", s.syncDrive))
 	if err != nil {
 		return fmt.Errorf("failed to schedule Drive sync: %w", err)
 	}
@@ -95,7 +161,7 @@ func (s *Scheduler) Start() error {
 
 	// Schedule Calendar sync
 	calendarSpec := fmt.Sprintf("@every %dm", s.config.Google.PollingMinutes.Calendar)
-	calendarID, err := s.cron.AddFunc(calendarSpec, s.syncCalendar)
+	calendarID, err := s.cron.AddFunc(calendarSpec, s.observeJob("calendar", s.syncCalendar))
 	if err != nil {
 		return fmt.Errorf("failed to schedule Calendar sync: %w", err)
 	}
@@ -104,80 +170,289 @@ func (s *Scheduler) Start() error {
 
 	// Schedule Tasks sync (inbound: Google Tasks -> Focus Agent DB)
 	tasksSpec := fmt.Sprintf("@every %dm", s.config.Google.PollingMinutes.Tasks)
-	tasksID, err := s.cron.AddFunc(tasksSpec, s.syncTasks)
+	tasksID, err := s.cron.AddFunc(tasksSpec, s.observeJob("tasks", s.syncTasks))
 	if err != nil {
 		return fmt.Errorf("failed to schedule Tasks sync: %w", err)
 	}
 	s.jobs["tasks"] = tasksID
 	log.Printf("Scheduled Tasks sync every %d minutes", s.config.Google.PollingMinutes.Tasks)
 
+	// Schedule quick note capture (inbound: Google Keep and the inbox doc -> tasks)
+	if s.config.Google.Capture.Keep || s.config.Google.Capture.InboxDocID != "" {
+		captureSpec := fmt.Sprintf("@every %dm", s.config.Google.PollingMinutes.Capture)
+		captureID, err := s.cron.AddFunc(captureSpec, s.observeJob("capture", s.captureNotes))
+		if err != nil {
+			return fmt.Errorf("failed to schedule note capture: %w", err)
+		}
+		s.jobs["capture"] = captureID
+		log.Printf("Scheduled note capture every %d minutes", s.config.Google.PollingMinutes.Capture)
+	}
+
+	// Schedule Chat sync (inbound: Google Chat spaces and DMs -> tasks)
+	if s.config.Chat.Sync.Enabled {
+		chatSpec := fmt.Sprintf("@every %dm", s.config.Google.PollingMinutes.Chat)
+		chatID, err := s.cron.AddFunc(chatSpec, s.observeJob("chat", s.syncChat))
+		if err != nil {
+			return fmt.Errorf("failed to schedule Chat sync: %w", err)
+		}
+		s.jobs["chat"] = chatID
+		log.Printf("Scheduled Chat sync every %d minutes", s.config.Google.PollingMinutes.Chat)
+	}
+
+	// Schedule Outlook mail and calendar sync (Microsoft 365)
+	if s.msgraph != nil {
+		outlookMailSpec := fmt.Sprintf("@every %dm", s.config.MSGraph.PollingMinutes.Mail)
+		outlookMailID, err := s.cron.AddFunc(outlookMailSpec, s.observeJob("outlook_mail", s.syncOutlookMail))
+		if err != nil {
+			return fmt.Errorf("failed to schedule Outlook mail sync: %w", err)
+		}
+		s.jobs["outlook_mail"] = outlookMailID
+		log.Printf("Scheduled Outlook mail sync every %d minutes", s.config.MSGraph.PollingMinutes.Mail)
+
+		outlookCalendarSpec := fmt.Sprintf("@every %dm", s.config.MSGraph.PollingMinutes.Calendar)
+		outlookCalendarID, err := s.cron.AddFunc(outlookCalendarSpec, s.observeJob("outlook_calendar", s.syncOutlookCalendar))
+		if err != nil {
+			return fmt.Errorf("failed to schedule Outlook calendar sync: %w", err)
+		}
+		s.jobs["outlook_calendar"] = outlookCalendarID
+		log.Printf("Scheduled Outlook calendar sync every %d minutes", s.config.MSGraph.PollingMinutes.Calendar)
+	}
+
 	// Schedule prioritized tasks sync (outbound: Focus Agent DB -> Google Tasks)
 	prioritizedTasksSpec := fmt.Sprintf("@every %dm", s.config.Google.PollingMinutes.Tasks)
-	prioritizedTasksID, err := s.cron.AddFunc(prioritizedTasksSpec, s.syncPrioritizedTasks)
+	prioritizedTasksID, err := s.cron.AddFunc(prioritizedTasksSpec, s.observeJob("prioritized_tasks", s.syncPrioritizedTasks))
 	if err != nil {
 		return fmt.Errorf("failed to schedule prioritized tasks sync: %w", err)
 	}
 	s.jobs["prioritized_tasks"] = prioritizedTasksID
 	log.Printf("Scheduled prioritized tasks sync every %d minutes", s.config.Google.PollingMinutes.Tasks)
 
+	// Schedule the Notion export of top tasks (outbound: Focus Agent DB -> Notion)
+	if s.config.Notion.Enabled && s.config.Notion.TasksDatabaseID != "" {
+		notionSpec := fmt.Sprintf("@every %dm", s.config.Notion.IntervalMinutes)
+		notionID, err := s.cron.AddFunc(notionSpec, s.observeJob("notion_export", s.exportToNotion))
+		if err != nil {
+			return fmt.Errorf("failed to schedule Notion export: %w", err)
+		}
+		s.jobs["notion_export"] = notionID
+		log.Printf("Scheduled Notion task export every %d minutes", s.config.Notion.IntervalMinutes)
+	}
+
 	// Schedule daily brief
 	dailyTime := s.config.Schedule.DailyBriefTime
-	dailySpec := fmt.Sprintf("0 %s %s * * *",
-		dailyTime[3:], // minutes
-		dailyTime[:2], // hours
-	)
-	dailyID, err := s.cron.AddFunc(dailySpec, s.sendDailyBrief)
-	if err != nil {
+	if err := s.scheduleClockJob("daily_brief", dailyTime, everyDay, s.sendDailyBrief); err != nil {
 		return fmt.Errorf("failed to schedule daily brief: %w", err)
 	}
-	s.jobs["daily_brief"] = dailyID
 	log.Printf("Scheduled daily brief at %s", dailyTime)
 
 	// Schedule midday replan
 	replanTime := s.config.Schedule.ReplanTime
-	replanSpec := fmt.Sprintf("0 %s %s * * *",
-		replanTime[3:], // minutes
-		replanTime[:2], // hours
-	)
-	replanID, err := s.cron.AddFunc(replanSpec, s.sendReplanBrief)
-	if err != nil {
+	if err := s.scheduleClockJob("replan_brief", replanTime, everyDay, s.sendReplanBrief); err != nil {
 		return fmt.Errorf("failed to schedule replan brief: %w", err)
 	}
-	s.jobs["replan_brief"] = replanID
 	log.Printf("Scheduled replan brief at %s", replanTime)
 
+	// Schedule the end-of-day shutdown brief
+	if eodTime := s.config.Schedule.EndOfDayTime; eodTime != "" {
+		if err := s.scheduleClockJob("end_of_day_brief", eodTime, everyDay, s.sendEndOfDayBrief); err != nil {
+			return fmt.Errorf("failed to schedule end-of-day brief: %w", err)
+		}
+		log.Printf("Scheduled end-of-day brief at %s", eodTime)
+	}
+
+	// Schedule weekly "everything else" digest
+	digestTime := s.config.Schedule.DigestTime
+	digestDay := config.Weekdays[s.config.Schedule.DigestDay]
+	if err := s.scheduleClockJob("weekly_digest", digestTime, digestDay, s.sendWeeklyDigest); err != nil {
+		return fmt.Errorf("failed to schedule weekly digest: %w", err)
+	}
+	log.Printf("Scheduled weekly digest on %s at %s", s.config.Schedule.DigestDay, digestTime)
+
+	// Schedule the weekly review
+	if reviewTime := s.config.Schedule.WeeklyReviewTime; reviewTime != "" {
+		reviewDay := config.Weekdays[s.config.Schedule.WeeklyReviewDay]
+		if err := s.scheduleClockJob("weekly_review", reviewTime, reviewDay, s.sendWeeklyReview); err != nil {
+			return fmt.Errorf("failed to schedule weekly review: %w", err)
+		}
+		log.Printf("Scheduled weekly review on %s at %s", s.config.Schedule.WeeklyReviewDay, reviewTime)
+	}
+
 	// Schedule follow-up checker
 	followupSpec := fmt.Sprintf("@every %dm", s.config.Schedule.FollowUpMinutes)
-	followupID, err := s.cron.AddFunc(followupSpec, s.checkFollowUps)
+	followupID, err := s.cron.AddFunc(followupSpec, s.observeJob("followup", s.checkFollowUps))
 	if err != nil {
 		return fmt.Errorf("failed to schedule follow-up checker: %w", err)
 	}
 	s.jobs["followup"] = followupID
 	log.Printf("Scheduled follow-up checker every %d minutes", s.config.Schedule.FollowUpMinutes)
 
+	// Schedule relationship briefs ahead of meetings with external contacts
+	if s.config.Meetings.RelationshipBriefs {
+		relationshipID, err := s.cron.AddFunc("@every 5m", s.observeJob("relationship_briefs", s.sendRelationshipBriefs))
+		if err != nil {
+			return fmt.Errorf("failed to schedule relationship briefs: %w", err)
+		}
+		s.jobs["relationship_briefs"] = relationshipID
+		log.Printf("Scheduled relationship briefs %d minutes before external meetings", s.config.Meetings.LeadMinutes)
+	}
+
+	// Schedule prep notes for upcoming meetings
+	if s.config.Meetings.Prep {
+		prepID, err := s.cron.AddFunc("@every 5m", s.observeJob("meeting_prep", s.prepareMeetings))
+		if err != nil {
+			return fmt.Errorf("failed to schedule meeting prep: %w", err)
+		}
+		s.jobs["meeting_prep"] = prepID
+		log.Printf("Scheduled meeting prep %d hours ahead, sent %d minutes before meetings",
+			s.config.Meetings.PrepHoursAhead, s.config.Meetings.PrepSendMinutes)
+	}
+
+	// Schedule exit summaries for focus sessions that have run out
+	focusID, err := s.cron.AddFunc("@every 1m", s.observeJob("focus_summaries", s.sendFocusSummaries))
+	if err != nil {
+		return fmt.Errorf("failed to schedule focus summaries: %w", err)
+	}
+	s.jobs["focus_summaries"] = focusID
+	log.Println("Scheduled focus mode summaries every minute")
+
+	// Schedule escalation of overdue high-impact tasks
+	if s.config.Escalation.Enabled {
+		escalationID, err := s.cron.AddFunc("@every 5m", s.observeJob("escalation", s.escalateOverdueTasks))
+		if err != nil {
+			return fmt.Errorf("failed to schedule escalations: %w", err)
+		}
+		s.jobs["escalation"] = escalationID
+		log.Println("Scheduled escalation of overdue tasks every 5 minutes")
+	}
+
+	// Schedule archival of tasks from short-lived sources
+	if len(s.config.Planner.AutoArchiveDays) > 0 {
+		archiveID, err := s.cron.AddFunc("@every 1h", s.observeJob("auto_archive", s.autoArchiveTasks))
+		if err != nil {
+			return fmt.Errorf("failed to schedule task auto-archival: %w", err)
+		}
+		s.jobs["auto_archive"] = archiveID
+		log.Println("Scheduled task auto-archival every hour")
+	}
+
+	// Schedule relearning of effort factors from completed tasks
+	if s.config.Planner.EffortCalibration.Enabled {
+		calibrationSpec := fmt.Sprintf("@every %dh", s.config.Planner.EffortCalibration.IntervalHours)
+		calibrationID, err := s.cron.AddFunc(calibrationSpec, s.observeJob("effort_calibration", s.recalibrateEffort))
+		if err != nil {
+			return fmt.Errorf("failed to schedule effort calibration: %w", err)
+		}
+		s.jobs["effort_calibration"] = calibrationID
+		log.Printf("Scheduled effort calibration every %d hours", s.config.Planner.EffortCalibration.IntervalHours)
+	}
+
+	// Schedule rebuilds of the per-contact relationship stats
+	peopleSpec := fmt.Sprintf("@every %dm", s.config.People.RefreshMinutes)
+	peopleID, err := s.cron.AddFunc(peopleSpec, s.observeJob("people", s.refreshPeople))
+	if err != nil {
+		return fmt.Errorf("failed to schedule people stats: %w", err)
+	}
+	s.jobs["people"] = peopleID
+	log.Printf("Scheduled people stats every %d minutes", s.config.People.RefreshMinutes)
+
+	// Schedule retries of undelivered Chat and Slack messages
+	retrySpec := fmt.Sprintf("@every %dm", s.config.Chat.RetryMinutes)
+	retryID, err := s.cron.AddFunc(retrySpec, s.observeJob("delivery_retry", s.retryDeliveries))
+	if err != nil {
+		return fmt.Errorf("failed to schedule delivery retries: %w", err)
+	}
+	s.jobs["delivery_retry"] = retryID
+	log.Printf("Scheduled delivery retries every %d minutes", s.config.Chat.RetryMinutes)
+
 	// Schedule task prioritization every 10 minutes
 	prioritizeSpec := "@every 10m"
-	prioritizeID, err := s.cron.AddFunc(prioritizeSpec, s.prioritizeTasks)
+	prioritizeID, err := s.cron.AddFunc(prioritizeSpec, s.observeJob("prioritize", s.prioritizeTasks))
 	if err != nil {
 		return fmt.Errorf("failed to schedule task prioritization: %w", err)
 	}
 	s.jobs["prioritize"] = prioritizeID
 	log.Printf("Scheduled task prioritization every 10 minutes")
 
+	// Schedule Ollama keep-alive so the model is loaded when processing starts
+	if s.config.Ollama.Enabled && s.config.Ollama.KeepAliveMinutes > 0 {
+		keepAliveSpec := fmt.Sprintf("@every %dm", s.config.Ollama.KeepAliveMinutes)
+		keepAliveID, err := s.cron.AddFunc(keepAliveSpec, s.observeJob("ollama_keep_alive", s.keepOllamaWarm))
+		if err != nil {
+			return fmt.Errorf("failed to schedule Ollama keep-alive: %w", err)
+		}
+		s.jobs["ollama_keep_alive"] = keepAliveID
+		log.Printf("Scheduled Ollama keep-alive every %d minutes", s.config.Ollama.KeepAliveMinutes)
+	}
+
+	// Schedule embedding of thread summaries and documents for semantic search
+	if s.config.Search.Semantic && s.config.Search.EmbedInterval > 0 {
+		embedSpec := fmt.Sprintf("@every %dm", s.config.Search.EmbedInterval)
+		embedID, err := s.cron.AddFunc(embedSpec, s.observeJob("content_embeddings", s.embedContent))
+		if err != nil {
+			return fmt.Errorf("failed to schedule content embedding: %w", err)
+		}
+		s.jobs["content_embeddings"] = embedID
+		log.Printf("Scheduled content embedding every %d minutes", s.config.Search.EmbedInterval)
+	}
+
 	// Schedule cache cleanup daily at 3 AM
 	cleanupSpec := "0 0 3 * * *"
-	cleanupID, err := s.cron.AddFunc(cleanupSpec, s.cleanupCache)
+	cleanupID, err := s.cron.AddFunc(cleanupSpec, s.observeJob("cleanup", s.cleanupCache))
 	if err != nil {
 		return fmt.Errorf("failed to schedule cache cleanup: %w", err)
 	}
 	s.jobs["cleanup"] = cleanupID
 	log.Printf("Scheduled cache cleanup at 3:00 AM daily")
 
+	// Schedule the nightly database backup before cache cleanup
+	if s.config.Database.NightlyBackup {
+		backupID, err := s.cron.AddFunc("0 30 2 * * *", s.observeJob("backup", s.backupDatabase))
+		if err != nil {
+			return fmt.Errorf("failed to schedule database backup: %w", err)
+		}
+		s.jobs["backup"] = backupID
+		log.Printf("Scheduled database backup at 2:30 AM daily, keeping %d", s.config.Database.NightlyBackupKeep)
+	}
+
+	// Move old message bodies to cold storage after the backup, so it still has them
+	if s.config.Database.ColdStorageDays > 0 {
+		coldID, err := s.cron.AddFunc("0 45 2 * * *", s.observeJob("cold_storage", s.moveToColdStorage))
+		if err != nil {
+			return fmt.Errorf("failed to schedule cold storage: %w", err)
+		}
+		s.jobs["cold_storage"] = coldID
+		log.Printf("Scheduled cold storage at 2:45 AM daily for message bodies older than %d days", s.config.Database.ColdStorageDays)
+	}
+
+	// Check the database, disk and Google API quotas against their limits
+	guardsSpec := fmt.Sprintf("@every %dm", s.config.Guards.CheckMinutes)
+	guardsID, err := s.cron.AddFunc(guardsSpec, s.observeJob("guards", s.checkGuards))
+	if err != nil {
+		return fmt.Errorf("failed to schedule resource guards: %w", err)
+	}
+	s.jobs["guards"] = guardsID
+	log.Printf("Scheduled resource guard checks every %d minutes", s.config.Guards.CheckMinutes)
+
+	// Run the pipeline canary ahead of the daily brief
+	if s.config.Canary.Enabled {
+		canaryTime := s.config.Canary.Time
+		if err := s.scheduleClockJob("canary", canaryTime, everyDay, func() { s.RunCanary() }); err != nil {
+			return fmt.Errorf("failed to schedule pipeline canary: %w", err)
+		}
+		log.Printf("Scheduled pipeline canary at %s", canaryTime)
+	}
+
 	// Run initial sync after a short delay
 	go func() {
 		time.Sleep(5 * time.Second)
+		s.checkGuards()
 		log.Println("Running initial sync...")
 		s.syncAll()
+		s.refreshPeople()
+		// Calibrate effort straight away the first time, rather than a day after enabling it
+		if s.config.Planner.EffortCalibration.Enabled && s.planner.EffortCalibration() == nil {
+			s.recalibrateEffort()
+		}
 	}()
 
 	// Start the cron scheduler
@@ -206,8 +481,13 @@ func (s *Scheduler) Stop() {
 // syncGmail syncs Gmail messages
 func (s *Scheduler) syncGmail() {
 	log.Println("Starting Gmail sync...")
+	s.events.Publish(events.SyncStarted, map[string]interface{}{"source": "gmail"})
+
+	started := time.Now()
 
-	if err := s.google.Gmail.SyncThreads(s.ctx, s.db); err != nil {
+	err := s.google.Gmail.SyncThreads(s.ctx, s.db)
+	s.publishSyncResult("gmail", started, err)
+	if err != nil {
 		log.Printf("Gmail sync failed: %v", err)
 		s.db.LogUsage("gmail", "sync", 0, 0, 0, err)
 	} else {
@@ -226,8 +506,13 @@ func (s *Scheduler) syncGmail() {
 // syncDrive syncs Drive documents
 func (s *Scheduler) syncDrive() {
 	log.Println("Starting Drive sync...")
+	s.events.Publish(events.SyncStarted, map[string]interface{}{"source": "drive"})
+
+	started := time.Now()
 
-	if err := s.google.Drive.SyncDocuments(s.ctx, s.db); err != nil {
+	err := s.google.Drive.SyncDocuments(s.ctx, s.db)
+	s.publishSyncResult("drive", started, err)
+	if err != nil {
 		log.Printf("Drive sync failed: %v", err)
 		s.db.LogUsage("drive", "sync", 0, 0, 0, err)
 	} else {
@@ -238,20 +523,76 @@ func (s *Scheduler) syncDrive() {
 // syncCalendar syncs Calendar events
 func (s *Scheduler) syncCalendar() {
 	log.Println("Starting Calendar sync...")
+	s.events.Publish(events.SyncStarted, map[string]interface{}{"source": "calendar"})
+
+	started := time.Now()
 
-	if err := s.google.Calendar.SyncEvents(s.ctx, s.db); err != nil {
+	err := s.google.Calendar.SyncEvents(s.ctx, s.db)
+	s.publishSyncResult("calendar", started, err)
+	if err != nil {
 		log.Printf("Calendar sync failed: %v", err)
 		s.db.LogUsage("calendar", "sync", 0, 0, 0, err)
 	} else {
 		log.Println("Calendar sync completed")
+		s.handleEventChanges()
+	}
+}
+
+// handleEventChanges updates prep tasks and time blocks for meetings moved or cancelled in the last sync
+func (s *Scheduler) handleEventChanges() {
+	if err := s.planner.HandleEventChanges(s.ctx); err != nil {
+		log.Printf("Failed to handle calendar changes: %v", err)
+	}
+}
+
+// syncOutlookMail syncs Outlook mail into the same tables as Gmail
+func (s *Scheduler) syncOutlookMail() {
+	log.Println("Starting Outlook mail sync...")
+	s.events.Publish(events.SyncStarted, map[string]interface{}{"source": "outlook_mail"})
+
+	started := time.Now()
+
+	err := s.msgraph.Mail.SyncMail(s.ctx, s.db)
+	s.publishSyncResult("outlook_mail", started, err)
+	if err != nil {
+		log.Printf("Outlook mail sync failed: %v", err)
+		s.db.LogUsage("outlook_mail", "sync", 0, 0, 0, err)
+	} else {
+		log.Println("Outlook mail sync completed")
+
+		// Outlook threads go through the same task extraction as Gmail
+		go s.ProcessNewMessages()
+	}
+}
+
+// syncOutlookCalendar syncs Outlook calendar events
+func (s *Scheduler) syncOutlookCalendar() {
+	log.Println("Starting Outlook calendar sync...")
+	s.events.Publish(events.SyncStarted, map[string]interface{}{"source": "outlook_calendar"})
+
+	started := time.Now()
+
+	err := s.msgraph.Calendar.SyncEvents(s.ctx, s.db)
+	s.publishSyncResult("outlook_calendar", started, err)
+	if err != nil {
+		log.Printf("Outlook calendar sync failed: %v", err)
+		s.db.LogUsage("outlook_calendar", "sync", 0, 0, 0, err)
+	} else {
+		log.Println("Outlook calendar sync completed")
+		s.handleEventChanges()
 	}
 }
 
 // syncTasks syncs Google Tasks (inbound: Google Tasks -> DB)
 func (s *Scheduler) syncTasks() {
 	log.Println("Starting Tasks sync...")
+	s.events.Publish(events.SyncStarted, map[string]interface{}{"source": "tasks"})
 
-	if err := s.google.Tasks.SyncTasks(s.ctx, s.db); err != nil {
+	started := time.Now()
+
+	err := s.google.Tasks.SyncTasks(s.ctx, s.db)
+	s.publishSyncResult("tasks", started, err)
+	if err != nil {
 		log.Printf("Tasks sync failed: %v", err)
 		s.db.LogUsage("tasks", "sync", 0, 0, 0, err)
 	} else {
@@ -259,6 +600,23 @@ func (s *Scheduler) syncTasks() {
 	}
 }
 
+// captureNotes turns new Keep notes and inbox doc paragraphs into tasks
+func (s *Scheduler) captureNotes() {
+	log.Println("Starting note capture...")
+	s.events.Publish(events.SyncStarted, map[string]interface{}{"source": "capture"})
+
+	started := time.Now()
+
+	err := s.planner.CaptureNotes(s.ctx)
+	s.publishSyncResult("capture", started, err)
+	if err != nil {
+		log.Printf("Note capture failed: %v", err)
+		s.db.LogUsage("capture", "sync", 0, 0, 0, err)
+	} else {
+		log.Println("Note capture completed")
+	}
+}
+
 // syncPrioritizedTasks syncs prioritized tasks to Google Tasks (outbound: DB -> Google Tasks)
 func (s *Scheduler) syncPrioritizedTasks() {
 	log.Println("Starting prioritized tasks sync to Google Tasks...")
@@ -271,6 +629,14 @@ func (s *Scheduler) syncPrioritizedTasks() {
 	}
 }
 
+// exportToNotion mirrors the top tasks into the Notion tasks database
+func (s *Scheduler) exportToNotion() {
+	if err := s.planner.ExportTasksToNotion(s.ctx); err != nil {
+		log.Printf("Notion export failed: %v", err)
+		s.db.LogUsage("notion", "export_tasks", 0, 0, 0, err)
+	}
+}
+
 // syncAll runs all sync operations
 func (s *Scheduler) syncAll() {
 	s.syncGmail()
@@ -278,6 +644,19 @@ func (s *Scheduler) syncAll() {
 	s.syncCalendar()
 	s.syncTasks()
 	s.syncPrioritizedTasks()
+
+	if s.config.Google.Capture.Keep || s.config.Google.Capture.InboxDocID != "" {
+		s.captureNotes()
+	}
+
+	if s.config.Chat.Sync.Enabled {
+		s.syncChat()
+	}
+
+	if s.msgraph != nil {
+		s.syncOutlookMail()
+		s.syncOutlookCalendar()
+	}
 }
 
 // sendDailyBrief sends the morning daily brief
@@ -304,6 +683,73 @@ func (s *Scheduler) sendReplanBrief() {
 	}
 }
 
+// sendEndOfDayBrief sends the end-of-day shutdown brief
+func (s *Scheduler) sendEndOfDayBrief() {
+	log.Println("Generating end-of-day brief...")
+
+	if err := s.planner.GenerateEndOfDayBrief(s.ctx); err != nil {
+		log.Printf("Failed to generate end-of-day brief: %v", err)
+		s.db.LogUsage("planner", "end_of_day_brief", 0, 0, 0, err)
+	} else {
+		log.Println("End-of-day brief sent successfully")
+	}
+}
+
+// keepOllamaWarm preloads the Ollama model so it isn't unloaded between processing runs
+func (s *Scheduler) keepOllamaWarm() {
+	if err := s.llm.Warmup(s.ctx); err != nil {
+		log.Printf("Ollama keep-alive failed: %v", err)
+	}
+}
+
+// sendWeeklyReview sends the weekly review of completed work and misses
+func (s *Scheduler) sendWeeklyReview() {
+	log.Println("Generating weekly review...")
+
+	if err := s.planner.GenerateWeeklyReview(s.ctx); err != nil {
+		log.Printf("Failed to generate weekly review: %v", err)
+		s.db.LogUsage("planner", "weekly_review", 0, 0, 0, err)
+	} else {
+		log.Println("Weekly review sent successfully")
+	}
+}
+
+// sendWeeklyDigest sends the weekly digest of low-priority items
+func (s *Scheduler) sendWeeklyDigest() {
+	log.Println("Generating weekly digest...")
+
+	if err := s.planner.GenerateWeeklyDigest(s.ctx); err != nil {
+		log.Printf("Failed to generate weekly digest: %v", err)
+		s.db.LogUsage("planner", "weekly_digest", 0, 0, 0, err)
+	} else {
+		log.Println("Weekly digest completed")
+	}
+}
+
+// sendRelationshipBriefs briefs the user on external contacts in upcoming meetings
+func (s *Scheduler) sendRelationshipBriefs() {
+	if err := s.planner.SendRelationshipBriefs(s.ctx); err != nil {
+		log.Printf("Failed to send relationship briefs: %v", err)
+		s.db.LogUsage("planner", "relationship_briefs", 0, 0, 0, err)
+	}
+}
+
+// prepareMeetings generates and sends prep notes for upcoming meetings
+func (s *Scheduler) prepareMeetings() {
+	if err := s.planner.PrepareMeetings(s.ctx); err != nil {
+		log.Printf("Failed to prepare meetings: %v", err)
+		s.db.LogUsage("planner", "meeting_prep", 0, 0, 0, err)
+	}
+}
+
+// sendFocusSummaries summarizes ended focus sessions and releases held notifications
+func (s *Scheduler) sendFocusSummaries() {
+	if err := s.planner.SendFocusSummaries(s.ctx); err != nil {
+		log.Printf("Failed to send focus summaries: %v", err)
+		s.db.LogUsage("planner", "focus_summary", 0, 0, 0, err)
+	}
+}
+
 // checkFollowUps checks for threads needing follow-up
 func (s *Scheduler) checkFollowUps() {
 	log.Println("Checking for follow-ups...")
@@ -314,6 +760,74 @@ func (s *Scheduler) checkFollowUps() {
 	}
 }
 
+// escalateOverdueTasks chases overdue high-impact tasks
+func (s *Scheduler) escalateOverdueTasks() {
+	if err := s.planner.EscalateOverdueTasks(s.ctx); err != nil {
+		log.Printf("Failed to escalate overdue tasks: %v", err)
+		s.db.LogUsage("planner", "escalation", 0, 0, 0, err)
+	}
+}
+
+// autoArchiveTasks archives tasks left untouched past their source's planner.auto_archive_days
+func (s *Scheduler) autoArchiveTasks() {
+	if _, err := s.planner.AutoArchiveTasks(); err != nil {
+		log.Printf("Failed to auto-archive tasks: %v", err)
+		s.db.LogUsage("planner", "auto_archive", 0, 0, 0, err)
+	}
+}
+
+// recalibrateEffort relearns how long tasks from each project and stakeholder really take
+func (s *Scheduler) recalibrateEffort() {
+	if _, err := s.planner.RecalibrateEffort(s.ctx); err != nil {
+		log.Printf("Failed to calibrate effort: %v", err)
+		s.db.LogUsage("planner", "effort_calibration", 0, 0, 0, err)
+	}
+}
+
+// refreshPeople rebuilds the per-contact relationship stats and the per-sender stats behind
+// thread relevance from recent mail
+func (s *Scheduler) refreshPeople() {
+	since := time.Now().AddDate(0, 0, -s.config.People.LookbackDays)
+	count, err := s.db.RefreshPeople(s.config.Google.UserEmail, since)
+	if err != nil {
+		log.Printf("Failed to refresh people stats: %v", err)
+		s.db.LogUsage("planner", "people", 0, 0, 0, err)
+		return
+	}
+	log.Printf("Refreshed stats for %d people", count)
+
+	senders, err := s.db.RefreshSenderStats(s.config.Google.UserEmail, since)
+	if err != nil {
+		log.Printf("Failed to refresh sender stats: %v", err)
+		s.db.LogUsage("planner", "sender_stats", 0, 0, 0, err)
+		return
+	}
+	log.Printf("Refreshed stats for %d senders", senders)
+}
+
+// retryDeliveries re-sends briefs and reminders that failed to reach Chat or Slack
+func (s *Scheduler) retryDeliveries() {
+	sent, err := s.google.Chat.RetryPending(s.ctx, s.db)
+	if err != nil {
+		log.Printf("Chat delivery retry failed: %v", err)
+		s.db.LogUsage("chat", "retry_delivery", 0, 0, 0, err)
+	} else if sent > 0 {
+		log.Printf("Delivered %d queued Chat message(s)", sent)
+	}
+
+	if s.slack == nil {
+		return
+	}
+
+	sent, err = s.slack.RetryPending(s.ctx, s.db)
+	if err != nil {
+		log.Printf("Slack delivery retry failed: %v", err)
+		s.db.LogUsage("slack", "retry_delivery", 0, 0, 0, err)
+	} else if sent > 0 {
+		log.Printf("Delivered %d queued Slack message(s)", sent)
+	}
+}
+
 // prioritizeTasks recalculates task priorities
 func (s *Scheduler) prioritizeTasks() {
 	// Run in goroutine to avoid blocking the scheduler and API handlers
@@ -326,12 +840,16 @@ func (s *Scheduler) prioritizeTasks() {
 		} else {
 			log.Println("Task prioritization completed")
 		}
+
+		// Scores may have changed since threads were labelled, e.g. through priority feedback
+		s.reconcilePriorityLabels()
 	}()
 }
 
 // ProcessSingleThread processes a single thread with AI
 func (s *Scheduler) ProcessSingleThread(threadID string) error {
 	log.Printf("Processing thread %s with AI...", threadID)
+	start := time.Now()
 
 	// Get messages for thread (including labels to determine if in INBOX)
 	messagesQuery := `
@@ -370,17 +888,13 @@ func (s *Scheduler) ProcessSingleThread(threadID string) error {
 	if len(messages) == 0 {
 		return fmt.Errorf("no messages found for thread %s", threadID)
 	}
+	s.loadAttachments(messages)
 
 	// Prepare metadata for smart model selection
-	metadata := llm.ThreadMetadata{
-		QueueSize:    1, // Processing single thread
-		SenderEmail:  messages[0].From,
-		Timestamp:    messages[0].Timestamp,
-		MessageCount: len(messages),
-	}
+	metadata := s.threadMetadata(messages, 1) // Processing single thread
 
-	// Generate summary with smart model selection
-	summary, err := s.llm.SummarizeThreadWithModelSelection(s.ctx, messages, metadata)
+	// Generate summary at the depth the thread warrants
+	summary, err := s.summarizeThread(threadID, messages, metadata)
 	if err != nil {
 		return fmt.Errorf("failed to summarize thread %s: %w", threadID, err)
 	}
@@ -418,12 +932,16 @@ func (s *Scheduler) ProcessSingleThread(threadID string) error {
 	}
 
 	// Enrich and save extracted tasks
-	for taskIndex, task := range tasks {
+	for _, task := range tasks {
 		// Set source to gmail for email-extracted tasks
 		task.Source = "gmail"
 		// Set source_id to thread ID so we can link tasks to threads
 		task.SourceID = threadID
-		normalizedTitle := ensureGmailTaskID(task, threadID, taskIndex)
+		// Derive the ID from the thread and title so re-extraction updates the same task
+		if err := s.db.AssignExtractedTaskID(task); err != nil {
+			log.Printf("Failed to assign task ID: %v", err)
+			continue
+		}
 
 		// Enrich task description with full context from email thread BEFORE saving
 		// This avoids calling SaveTask twice, which causes duplicate INSERTs in the WAL
@@ -436,51 +954,27 @@ func (s *Scheduler) ProcessSingleThread(threadID string) error {
 			// Continue with original task description
 		}
 
-		// Purge duplicates from this thread with same normalized title, then save
-		// Wrapped in transaction to ensure atomicity and avoid WAL replay issues
-		err := s.db.WithTx(func(tx *sql.Tx) error {
-			// Delete any existing tasks from this thread with same normalized title
-			purgeQuery := `
-				DELETE FROM tasks
-				WHERE source = 'gmail'
-				  AND source_id = ?
-				  AND status = 'pending'
-				  AND lower(trim(regexp_replace(title, '\\s+', ' ', 'g'))) = ?
-			`
-			if _, err := tx.Exec(purgeQuery, threadID, normalizedTitle); err != nil {
-				return fmt.Errorf("purge failed: %w", err)
-			}
-
-			// Insert the new task (ON CONFLICT should not be needed since we just deleted)
-			var dueTS *int64
-			if task.DueTS != nil {
-				ts := task.DueTS.Unix()
-				dueTS = &ts
-			}
-			createdTS := task.CreatedAt.Unix()
-			if task.CreatedAt.IsZero() {
-				createdTS = time.Now().Unix()
-			}
-			updatedTS := time.Now().Unix()
-
-			insertQuery := `
-				INSERT INTO tasks (id, source, source_id, title, description, due_ts, project, impact, urgency, effort, stakeholder, score, status, metadata, completed_at, created_at, updated_at)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULL, ?, ?)
-			`
-			_, err := tx.Exec(insertQuery,
-				task.ID, task.Source, task.SourceID, task.Title, task.Description, dueTS,
-				task.Project, task.Impact, task.Urgency, task.Effort, task.Stakeholder,
-				task.Score, task.Status, task.Metadata, createdTS, updatedTS,
-			)
-			return err
-		})
+		// Merge into an existing task instead of inserting a semantic duplicate
+		merged, taskEmb := s.mergeIfDuplicate(task)
+		if merged {
+			continue
+		}
 
+		// Update the task if it was extracted from this thread before, keeping its history
+		created, err := s.db.SaveExtractedTask(task)
 		if err != nil {
 			log.Printf("Failed to save extracted task: %v", err)
 			continue
 		}
+
+		s.saveDedupEmbedding(taskEmb)
+		if created {
+			s.publishTaskCreated(task)
+		}
 	}
 
+	s.applyTagRules(start)
+
 	// Prioritize tasks (instant, no tokens - pure algorithm)
 	if err := s.planner.PrioritizeTasks(s.ctx); err != nil {
 		log.Printf("Failed to prioritize tasks: %v", err)
@@ -488,7 +982,6 @@ func (s *Scheduler) ProcessSingleThread(threadID string) error {
 
 	// Calculate thread priority score from task scores
 	var priorityScore float64
-	relevantToUser := false
 
 	if len(tasks) > 0 {
 		// Query saved tasks to get their scores
@@ -497,37 +990,43 @@ func (s *Scheduler) ProcessSingleThread(threadID string) error {
 		if err := s.db.QueryRow(taskQuery, threadID).Scan(&maxScore); err == nil && maxScore.Valid {
 			priorityScore = maxScore.Float64
 		}
-
-		// Determine if relevant to user:
-		// 1. If in INBOX (primary heuristic)
-		// 2. AND any task owner is unspecified, "me", "you", or matches user email
-		if hasInboxLabel {
-			relevantToUser = true // In inbox means it's for the user
-
-			// Could add more specific owner checking here if needed
-			userEmail := strings.ToLower(s.config.Google.UserEmail)
-			for _, task := range tasks {
-				owner := strings.ToLower(task.Stakeholder)
-				if owner == "" || owner == "me" || owner == "you" || owner == userEmail || strings.Contains(owner, userEmail) {
-					relevantToUser = true
-					break
-				}
-			}
-		}
 	}
 
+	// Relevance is learned from how the user treated the sender's earlier threads
+	relevantToUser := s.isRelevantToUser(messages, tasks, hasInboxLabel)
+
 	// Update thread with priority information
 	thread.PriorityScore = priorityScore
 	thread.RelevantToUser = relevantToUser
 	if err := s.db.SaveThread(thread); err != nil {
 		return fmt.Errorf("failed to update thread priority: %w", err)
 	}
+	if err := s.db.SetThreadRelevance(threadID, relevantToUser); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if labelScore, err := s.pendingThreadScore(threadID); err != nil {
+		log.Printf("Priority label: %v", err)
+	} else {
+		s.updatePriorityLabel(threadID, labelScore)
+	}
 
 	log.Printf("Processed thread %s: summary generated, %d tasks extracted, priority=%.2f, relevant=%v",
 		threadID, len(tasks), priorityScore, relevantToUser)
+	s.events.Publish(events.ThreadProcessed, map[string]interface{}{"thread_id": threadID, "tasks": len(tasks)})
 	return nil
 }
 
+// loadAttachments adds extracted attachment text to messages headed for summaries and task
+// enrichment
+func (s *Scheduler) loadAttachments(messages []*db.Message) {
+	if !s.config.Google.Attachments.Enabled {
+		return
+	}
+	if err := s.db.LoadAttachments(messages); err != nil {
+		log.Printf("Failed to load attachments: %v", err)
+	}
+}
+
 // ProcessNewMessages processes new messages for summaries and task extraction
 func (s *Scheduler) ProcessNewMessages() {
 	// Prevent concurrent processing runs to avoid duplicate task insertions
@@ -543,6 +1042,16 @@ func (s *Scheduler) ProcessNewMessages() {
 
 	log.Println("🔒 Processing new messages with AI (lock acquired)...")
 
+	// Tasks from threads that have moved on are refreshed after new threads, under the same lock
+	defer s.reenrichUpdatedTasks()
+
+	// Take threads matching "do not process" rules out of the queue before spending tokens on them
+	if skipped, err := s.db.ApplyProcessingRules(); err != nil {
+		log.Printf("Failed to apply processing rules: %v", err)
+	} else if skipped > 0 {
+		log.Printf("Skipped %d thread(s) matching processing rules", skipped)
+	}
+
 	// Get threads that need summarization
 	maxProcessing := s.config.Limits.MaxAIProcessingPerRun
 	var query string
@@ -554,7 +1063,7 @@ func (s *Scheduler) ProcessNewMessages() {
 		query = `
 			SELECT DISTINCT t.id
 			FROM threads t
-			WHERE t.summary IS NULL OR t.summary = ''
+			WHERE (t.summary IS NULL OR t.summary = '') AND t.skipped_by_rule IS NULL
 		`
 		rows, err = s.db.Query(query)
 	} else {
@@ -562,7 +1071,7 @@ func (s *Scheduler) ProcessNewMessages() {
 		query = `
 			SELECT DISTINCT t.id
 			FROM threads t
-			WHERE t.summary IS NULL OR t.summary = ''
+			WHERE (t.summary IS NULL OR t.summary = '') AND t.skipped_by_rule IS NULL
 			LIMIT ?
 		`
 		rows, err = s.db.Query(query, maxProcessing)
@@ -589,6 +1098,11 @@ func (s *Scheduler) ProcessNewMessages() {
 
 	log.Printf("Found %d threads needing AI processing", len(threadIDs))
 
+	// Load the model before the first thread rather than during it
+	if s.config.Ollama.Warmup {
+		s.llm.Warmup(s.ctx)
+	}
+
 	// Estimate token usage and cost
 	estimatedTokensPerThread := 500 // Conservative estimate
 	totalEstimatedTokens := len(threadIDs) * estimatedTokensPerThread
@@ -639,22 +1153,19 @@ func (s *Scheduler) ProcessNewMessages() {
 		if len(messages) == 0 {
 			continue
 		}
+		s.loadAttachments(messages)
 
 		// Prepare metadata for smart model selection
-		metadata := llm.ThreadMetadata{
-			QueueSize:    len(threadIDs),
-			SenderEmail:  messages[0].From, // Most recent message's sender
-			Timestamp:    messages[0].Timestamp,
-			MessageCount: len(messages),
-		}
+		metadata := s.threadMetadata(messages, len(threadIDs))
 
-		// Generate summary with smart model selection
-		summary, err := s.llm.SummarizeThreadWithModelSelection(s.ctx, messages, metadata)
+		// Generate summary at the depth the thread warrants
+		summary, err := s.summarizeThread(threadID, messages, metadata)
 		if err != nil {
 			// Check if daily quota is exhausted
 			var quotaErr *llm.DailyQuotaExceededError
 			if errors.As(err, &quotaErr) {
 				log.Printf("Daily quota exhausted. Stopping AI processing. %d/%d threads processed.", successCount, len(threadIDs))
+				s.events.Publish(events.QuotaExhausted, map[string]interface{}{"error": quotaErr.Error()})
 				break // Stop processing immediately
 			}
 			log.Printf("Failed to summarize thread %s: %v", threadID, err)
@@ -691,12 +1202,16 @@ func (s *Scheduler) ProcessNewMessages() {
 		}
 
 		// Enrich and save extracted tasks
-		for taskIndex, task := range tasks {
+		for _, task := range tasks {
 			// Set source to gmail for email-extracted tasks
 			task.Source = "gmail"
 			// Set source_id to thread ID so we can link tasks to threads
 			task.SourceID = threadID
-			normalizedTitle := ensureGmailTaskID(task, threadID, taskIndex)
+			// Derive the ID from the thread and title so re-extraction updates the same task
+			if err := s.db.AssignExtractedTaskID(task); err != nil {
+				log.Printf("Failed to assign task ID: %v", err)
+				continue
+			}
 
 			// Enrich task description with full context from email thread BEFORE saving
 			// This avoids calling SaveTask twice, which causes duplicate INSERTs in the WAL
@@ -708,57 +1223,34 @@ func (s *Scheduler) ProcessNewMessages() {
 				// Continue with original task description
 			}
 
-			// Purge duplicates from this thread with same normalized title, then save
-			// Wrapped in transaction to ensure atomicity and avoid WAL replay issues
-			err := s.db.WithTx(func(tx *sql.Tx) error {
-				// Delete any existing tasks from this thread with same normalized title
-				purgeQuery := `
-					DELETE FROM tasks
-					WHERE source = 'gmail'
-					  AND source_id = ?
-					  AND status = 'pending'
-					  AND lower(trim(regexp_replace(title, '\\s+', ' ', 'g'))) = ?
-				`
-				if _, err := tx.Exec(purgeQuery, threadID, normalizedTitle); err != nil {
-					return fmt.Errorf("purge failed: %w", err)
-				}
-
-				// Insert the new task
-				var dueTS *int64
-				if task.DueTS != nil {
-					ts := task.DueTS.Unix()
-					dueTS = &ts
-				}
-				createdTS := task.CreatedAt.Unix()
-				if task.CreatedAt.IsZero() {
-					createdTS = time.Now().Unix()
-				}
-				updatedTS := time.Now().Unix()
-
-				insertQuery := `
-					INSERT INTO tasks (id, source, source_id, title, description, due_ts, project, impact, urgency, effort, stakeholder, score, status, metadata, completed_at, created_at, updated_at)
-					VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULL, ?, ?)
-				`
-				_, err := tx.Exec(insertQuery,
-					task.ID, task.Source, task.SourceID, task.Title, task.Description, dueTS,
-					task.Project, task.Impact, task.Urgency, task.Effort, task.Stakeholder,
-					task.Score, task.Status, task.Metadata, createdTS, updatedTS,
-				)
-				return err
-			})
+			// Merge into an existing task instead of inserting a semantic duplicate
+			merged, taskEmb := s.mergeIfDuplicate(task)
+			if merged {
+				continue
+			}
 
+			// Update the task if it was extracted from this thread before, keeping its history
+			created, err := s.db.SaveExtractedTask(task)
 			if err != nil {
 				log.Printf("Failed to save extracted task: %v", err)
 				continue
 			}
+			s.saveDedupEmbedding(taskEmb)
+			if err := s.db.MarkTaskEnriched(task.ID, len(messages), messages[0].Timestamp); err != nil {
+				log.Printf("Warning: %v", err)
+			}
 
 			// Score task immediately after extraction (parallel scoring)
 			if err := s.planner.PrioritizeTask(s.ctx, task); err != nil {
 				log.Printf("Failed to prioritize task '%s': %v", task.Title, err)
 			}
+			if created {
+				s.publishTaskCreated(task)
+			}
 		}
 
 		log.Printf("Processed thread %s: summary generated, %d tasks extracted and enriched", threadID, len(tasks))
+		s.events.Publish(events.ThreadProcessed, map[string]interface{}{"thread_id": threadID, "tasks": len(tasks)})
 		successCount++
 
 		// Show progress every 10 threads
@@ -772,6 +1264,8 @@ func (s *Scheduler) ProcessNewMessages() {
 		}
 	}
 
+	s.applyTagRules(startTime)
+
 	// Final summary
 	elapsed := time.Since(startTime)
 
@@ -791,6 +1285,15 @@ func (s *Scheduler) ProcessNewMessages() {
 	log.Println("═══════════════════════════════════════════════════════")
 }
 
+// applyTagRules tags the tasks extracted since the given time using the tag rules
+func (s *Scheduler) applyTagRules(since time.Time) {
+	if tagged, err := s.db.ApplyTagRules(since); err != nil {
+		log.Printf("Failed to apply tag rules: %v", err)
+	} else if tagged > 0 {
+		log.Printf("Tagged %d task(s) from tag rules", tagged)
+	}
+}
+
 // EnrichExistingTasks enriches descriptions for existing email-extracted tasks
 func (s *Scheduler) EnrichExistingTasks() error {
 	log.Println("Finding email-extracted tasks that need enrichment...")
@@ -921,6 +1424,7 @@ func (s *Scheduler) EnrichExistingTasks() error {
 			log.Printf("No messages found for thread %s, skipping", info.threadID)
 			continue
 		}
+		s.loadAttachments(messages)
 
 		// Enrich the task description
 		enrichedDesc, err := s.llm.EnrichTaskDescription(s.ctx, info.task, messages)
@@ -983,10 +1487,61 @@ func (s *Scheduler) cleanupCache() {
 	}
 }
 
+// backupDatabase copies the database into the backup directory and removes the oldest copies
+func (s *Scheduler) backupDatabase() {
+	result, err := s.db.BackupInto(s.config.Database.BackupDir)
+	if err != nil {
+		log.Printf("Database backup failed: %v", err)
+		s.db.LogUsage("database", "backup", 0, 0, 0, err)
+		return
+	}
+	log.Printf("Database backed up to %s (%d tables, %d rows)", result.Path, result.Tables, result.Rows)
+
+	if keep := s.config.Database.NightlyBackupKeep; keep > 0 {
+		if err := db.PruneBackups(s.config.Database.BackupDir, keep); err != nil {
+			log.Printf("Failed to remove old backups: %v", err)
+		}
+	}
+}
+
+// moveToColdStorage moves the bodies of messages older than database.cold_storage_days out of
+// the database into a parquet file
+func (s *Scheduler) moveToColdStorage() {
+	cutoff := time.Now().AddDate(0, 0, -s.config.Database.ColdStorageDays)
+	path, moved, err := s.db.MoveToColdStorage(s.config.Database.ColdStorageDir, cutoff)
+	if err != nil {
+		log.Printf("Cold storage failed: %v", err)
+		s.db.LogUsage("database", "cold_storage", 0, 0, 0, err)
+		return
+	}
+	if moved > 0 {
+		log.Printf("Moved %d message bodies to cold storage in %s", moved, path)
+	}
+}
+
+// observeJob wraps a scheduled job to record how late it started. Cron sets the entry's Prev to
+// the time it was due before it answers the lookup, so this sees the run that's starting.
+func (s *Scheduler) observeJob(name string, job func()) func() {
+	return func() {
+		s.jobsMu.Lock()
+		id, ok := s.jobs[name]
+		s.jobsMu.Unlock()
+		if ok {
+			if due := s.cron.Entry(id).Prev; !due.IsZero() {
+				metrics.JobLateness.Observe(time.Since(due).Seconds(), name)
+			}
+		}
+		job()
+	}
+}
+
 // GetNextRuns returns the next scheduled run times for all jobs
 func (s *Scheduler) GetNextRuns() map[string]time.Time {
 	nextRuns := make(map[string]time.Time)
 
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
 	entries := s.cron.Entries()
 	for name, id := range s.jobs {
 		for _, entry := range entries {
@@ -1031,9 +1586,11 @@ func (s *Scheduler) ReprocessAITasks() error {
 
 	log.Printf("Found %d threads with summaries", len(threads))
 
-	// Step 2: Delete all existing AI tasks
-	deleteQuery := `DELETE FROM tasks WHERE source = 'ai'`
-	result, err := s.db.Exec(deleteQuery)
+	// Step 2: Delete all existing AI tasks, after snapshotting them
+	if err := s.snapshot("reprocess-tasks"); err != nil {
+		return err
+	}
+	result, err := s.db.Exec(`DELETE FROM tasks WHERE ` + db.ReprocessTasksFilter)
 	if err != nil {
 		return fmt.Errorf("failed to delete AI tasks: %w", err)
 	}
@@ -1073,13 +1630,16 @@ func (s *Scheduler) ReprocessAITasks() error {
 		}
 
 		// Save extracted tasks
-		for taskIndex, task := range tasks {
+		for _, task := range tasks {
 			// Set source to gmail for email-extracted tasks
 			task.Source = "gmail"
 			task.SourceID = thread.ID
-			normalizedTitle := ensureGmailTaskID(task, thread.ID, taskIndex)
-			purgeDuplicateGmailTasks(s.db, thread.ID, normalizedTitle)
-			if err := s.db.SaveTask(task); err != nil {
+			// Derive the ID from the thread and title so re-extraction updates the same task
+			if err := s.db.AssignExtractedTaskID(task); err != nil {
+				log.Printf("Failed to assign task ID: %v", err)
+				continue
+			}
+			if _, err := s.db.SaveExtractedTask(task); err != nil {
 				log.Printf("Failed to save task: %v", err)
 				continue
 			}
@@ -1100,6 +1660,7 @@ func (s *Scheduler) ReprocessAITasks() error {
 	if err := s.planner.RecalculateThreadPriorities(s.ctx); err != nil {
 		log.Printf("Warning: Failed to recalculate thread priorities: %v", err)
 	}
+	s.reconcilePriorityLabels()
 
 	log.Println("═══════════════════════════════════════════════════════")
 	log.Printf("✅ REPROCESSING COMPLETE:")
@@ -1127,15 +1688,7 @@ func (s *Scheduler) CleanupOtherPeoplesTasks() error {
 	// Query tasks that are NOT for the user
 	// Keep tasks where stakeholder is empty, "me", "you", or contains user email
 	// Delete everything else
-	query := `
-		SELECT id, title, stakeholder
-		FROM tasks
-		WHERE stakeholder IS NOT NULL
-		  AND stakeholder != ''
-		  AND LOWER(stakeholder) != 'me'
-		  AND LOWER(stakeholder) != 'you'
-		  AND LOWER(stakeholder) NOT LIKE ?
-	`
+	query := `SELECT id, title, stakeholder FROM tasks WHERE ` + db.OtherPeoplesTasksFilter
 
 	rows, err := s.db.Query(query, "%"+userEmail+"%")
 	if err != nil {
@@ -1173,17 +1726,11 @@ func (s *Scheduler) CleanupOtherPeoplesTasks() error {
 		return nil
 	}
 
-	// Delete the tasks
-	deleteQuery := `
-		DELETE FROM tasks
-		WHERE stakeholder IS NOT NULL
-		  AND stakeholder != ''
-		  AND LOWER(stakeholder) != 'me'
-		  AND LOWER(stakeholder) != 'you'
-		  AND LOWER(stakeholder) NOT LIKE ?
-	`
-
-	result, err := s.db.Exec(deleteQuery, "%"+userEmail+"%")
+	// Delete the tasks, after snapshotting them
+	if err := s.snapshot("cleanup-other-tasks"); err != nil {
+		return err
+	}
+	result, err := s.db.Exec(`DELETE FROM tasks WHERE `+db.OtherPeoplesTasksFilter, "%"+userEmail+"%")
 	if err != nil {
 		return fmt.Errorf("failed to delete tasks: %w", err)
 	}
@@ -1198,55 +1745,15 @@ func (s *Scheduler) CleanupOtherPeoplesTasks() error {
 	return nil
 }
 
-func ensureGmailTaskID(task *db.Task, threadID string, taskIndex int) string {
-	if task == nil {
-		return ""
-	}
-
-	normalizedTitle := normalizeTaskTitle(task.Title)
-
-	dueKey := ""
-	if task.DueTS != nil {
-		dueKey = task.DueTS.Format("2006-01-02")
-	}
-
-	// Include taskIndex to ensure uniqueness even for tasks with identical titles/due dates
-	fingerprint := fmt.Sprintf("%s|%s|%s|%d",
-		threadID,
-		normalizedTitle,
-		dueKey,
-		taskIndex,
-	)
-
-	hash := sha1.Sum([]byte(fingerprint))
-	task.ID = fmt.Sprintf("gmail_%s", hex.EncodeToString(hash[:8]))
-
-	return normalizedTitle
-}
-
-func normalizeTaskTitle(title string) string {
-	if title == "" {
-		return ""
-	}
-	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
-}
-
-func purgeDuplicateGmailTasks(database *db.DB, threadID, normalizedTitle string) {
-	if normalizedTitle == "" {
-		return
-	}
-
-	query := `
-		DELETE FROM tasks
-		WHERE source = 'gmail'
-		  AND source_id = ?
-		  AND status = 'pending'
-		  AND lower(trim(regexp_replace(title, '\\s+', ' ', 'g'))) = ?
-	`
-
-	if _, err := database.Exec(query, threadID, normalizedTitle); err != nil {
-		log.Printf("Failed to purge duplicate tasks for thread %s: %v", threadID, err)
+// snapshot exports the database to the backup directory before a destructive operation. The
+// operation shouldn't go ahead if this fails.
+func (s *Scheduler) snapshot(label string) error {
+	path, err := s.db.Snapshot(s.config.Database.BackupDir, label, s.config.Database.BackupKeep)
+	if err != nil {
+		return fmt.Errorf("not continuing without a snapshot: %w", err)
 	}
+	log.Printf("Snapshot written to %s", path)
+	return nil
 }
 
 // enrichWithFront enriches threads with Front metadata and comments
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"log"
+	"strings"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/embeddings"
+)
+
+// maxDocumentEmbedBytes caps the exported text of a Google Doc embedded for search
+const maxDocumentEmbedBytes = 16 << 10
+
+// embeddableDocTypes are the Drive file types whose text is exported for embedding; other files
+// are embedded by title alone
+var embeddableDocTypes = map[string]bool{
+	"application/vnd.google-apps.document":     true,
+	"application/vnd.google-apps.spreadsheet":  true,
+	"application/vnd.google-apps.presentation": true,
+}
+
+// embedContent embeds thread summaries and Drive documents that are new or have changed since
+// they were last embedded, so semantic search can find them
+func (s *Scheduler) embedContent() {
+	if s.embeddings == nil || s.lowPriorityPaused("content embedding") {
+		return
+	}
+
+	items, err := s.db.GetContentNeedingEmbedding(s.config.Search.EmbedBatch)
+	if err != nil {
+		log.Printf("Failed to find content to embed: %v", err)
+		return
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	embedded := 0
+	for _, item := range items {
+		if item.Kind == db.ContentKindDocument {
+			s.addDocumentText(item)
+		}
+		if err := embeddings.EmbedContent(s.ctx, s.embeddings, s.db, item); err != nil {
+			log.Printf("Failed to embed %s %s: %v", item.Kind, item.ID, err)
+			if s.ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		embedded++
+	}
+	log.Printf("Embedded %d/%d thread summaries and documents for search", embedded, len(items))
+}
+
+// addDocumentText fills in the text of a Google Doc, Sheet or Slides file so it's embedded by
+// content rather than title alone
+func (s *Scheduler) addDocumentText(item *db.ContentToEmbed) {
+	if s.google == nil || s.google.Drive == nil || !embeddableDocTypes[item.MimeType] {
+		return
+	}
+
+	_, text, err := s.google.Drive.ExportText(s.ctx, item.ID, maxDocumentEmbedBytes)
+	if err != nil {
+		log.Printf("Failed to export document %s for embedding, using its title: %v", item.ID, err)
+		return
+	}
+	if text = strings.TrimSpace(text); text != "" {
+		item.Text = text
+	}
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"log"
+	"strings"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/llm"
+)
+
+// summaryDepth picks how deeply to summarize a thread. Without summaries.adaptive every thread
+// gets the standard summary. Otherwise key stakeholders' threads and those with an open task
+// scoring summaries.deep_score or more get a deep summary; mail from automated senders, and
+// threads already summarized without an open task above the digest threshold, get a gist.
+func (s *Scheduler) summaryDepth(threadID string, messages []*db.Message) string {
+	if !s.config.Summaries.Adaptive {
+		return llm.SummaryStandard
+	}
+
+	for _, msg := range messages {
+		if s.isKeyStakeholder(msg.From) {
+			return llm.SummaryDeep
+		}
+	}
+
+	score, summarized, err := s.db.GetThreadTaskScore(threadID)
+	if err != nil {
+		log.Printf("Failed to score thread %s for summary depth: %v", threadID, err)
+		return llm.SummaryStandard
+	}
+	if score >= s.config.Summaries.DeepScore {
+		return llm.SummaryDeep
+	}
+	if summarized && score < s.config.Planner.DigestScoreThreshold {
+		return llm.SummaryGist
+	}
+	if db.IsAutomatedAddress(messages[0].From) && score < s.config.Planner.DigestScoreThreshold {
+		return llm.SummaryGist
+	}
+	return llm.SummaryStandard
+}
+
+// summarizeThread summarizes a thread at the depth it warrants and, with adaptive summaries on,
+// records the depth and the fields of a deep summary. A deep summary that fails falls back to
+// the standard one.
+func (s *Scheduler) summarizeThread(threadID string, messages []*db.Message, metadata llm.ThreadMetadata) (string, error) {
+	depth := s.summaryDepth(threadID, messages)
+	insights := &db.ThreadInsights{ThreadID: threadID, Depth: depth}
+
+	var summary string
+	var err error
+	switch depth {
+	case llm.SummaryGist:
+		summary, err = s.llm.SummarizeThreadGist(s.ctx, messages)
+	case llm.SummaryDeep:
+		var deep *llm.DeepSummary
+		if deep, err = s.llm.SummarizeThreadDeep(s.ctx, messages, metadata); err == nil {
+			summary = deep.Summary
+			insights.Decisions = deep.Decisions
+			insights.Owners = deep.Owners
+			insights.Risks = deep.Risks
+			insights.OpenQuestions = deep.OpenQuestions
+			break
+		}
+		log.Printf("Deep summary of thread %s failed, using a standard one: %v", threadID, err)
+		insights.Depth = llm.SummaryStandard
+		fallthrough
+	default:
+		summary, err = s.llm.SummarizeThreadWithModelSelection(s.ctx, messages, metadata)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if s.config.Summaries.Adaptive {
+		if err := s.db.SaveThreadInsights(insights); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	return summary, nil
+}
+
+// isKeyStakeholder reports whether an address matches one of the configured key stakeholders
+func (s *Scheduler) isKeyStakeholder(address string) bool {
+	address = strings.ToLower(address)
+	for _, stakeholder := range s.config.Priorities.KeyStakeholders {
+		if stakeholder = strings.ToLower(strings.TrimSpace(stakeholder)); stakeholder != "" && strings.Contains(address, stakeholder) {
+			return true
+		}
+	}
+	return false
+}
@@ -71,8 +71,11 @@ func (knn *KNNScorer) FindNearestNeighbors(ctx context.Context, targetTaskID str
 		WHERE te.task_id != ?
 	`
 
+	// Time the scan and in-process similarity together, as that is what grows with the table
+	start := time.Now()
 	rows, err := knn.db.Query(query, targetTaskID)
 	if err != nil {
+		knn.db.ObserveQuery(db.QueryKindKNN, start, err)
 		return nil, fmt.Errorf("failed to query neighbors: %w", err)
 	}
 	defer rows.Close()
@@ -113,6 +116,8 @@ func (knn *KNNScorer) FindNearestNeighbors(ctx context.Context, targetTaskID str
 		neighbors = append(neighbors, neighbor)
 	}
 
+	knn.db.ObserveQuery(db.QueryKindKNN, start, rows.Err())
+
 	if len(neighbors) == 0 {
 		return nil, nil // No neighbors with feedback yet
 	}
@@ -214,7 +219,7 @@ func (knn *KNNScorer) ScoreWithKNN(ctx context.Context, task *db.Task, baseScore
 // SaveFeedback records user feedback on a task's priority score
 func SaveFeedback(database *db.DB, taskID string, vote int, reason string, originalScore, adjustedScore float64) error {
 	// Generate feedback ID with timestamp
-	feedbackID := fmt.Sprintf("fb_%s_%d", taskID, time.Now().Unix())
+	feedbackID := fmt.Sprintf("fb_%s_%d", taskID, time.Now().UnixNano())
 
 	query := `
 		INSERT INTO priority_feedback
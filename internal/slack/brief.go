@@ -0,0 +1,538 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// SendDailyBrief posts the morning brief. It starts the day's thread when posting as a bot.
+// Recurring tasks due in the coming week are listed after today's meetings, and meetings moved
+// or cancelled since the last brief and warnings about today's calendar before the tasks.
+// Notable changes to thread summaries, related documents edited since the last brief and
+// outstanding commitments come last.
+func (c *Client) SendDailyBrief(ctx context.Context, database *db.DB, tasks []*db.Task, events []*db.Event, upcoming []*db.Task, changes []*db.EventChange, threadChanges []*db.SummaryChange, docs []*db.DocumentUpdate, commitments []*db.Commitment, warnings []*db.CalendarWarning) error {
+	now := time.Now()
+	var brief strings.Builder
+
+	brief.WriteString(fmt.Sprintf("*Daily Brief - %s*\n", now.Format("Monday, January 2")))
+	brief.WriteString("_Your focus plan for today_\n")
+
+	if len(changes) > 0 {
+		brief.WriteString("\n:warning: *Calendar Changes*\n")
+		for _, change := range changes {
+			brief.WriteString(fmt.Sprintf("• %s\n", change.Describe()))
+		}
+	}
+
+	if len(warnings) > 0 {
+		brief.WriteString("\n:rotating_light: *Calendar Warnings*\n")
+		for _, warning := range warnings {
+			brief.WriteString(fmt.Sprintf("• %s\n", warning.Describe()))
+		}
+	}
+
+	if len(tasks) > 0 {
+		brief.WriteString("\n:clipboard: *Top Priority Tasks*\n")
+		for idx, task := range tasks {
+			if idx >= 5 {
+				break
+			}
+			line := fmt.Sprintf("%s %s", priorityIndicator(task.Score), task.Title)
+			if task.DueTS != nil {
+				line += fmt.Sprintf(" • Due: %s", task.DueTS.Format("3:04 PM"))
+			}
+			if link := taskLink(task); link != "" {
+				line += fmt.Sprintf(" <%s|open>", link)
+			}
+			brief.WriteString(line + "\n")
+		}
+	}
+
+	todaysEvents := 0
+	for _, event := range events {
+		if event.StartTS.YearDay() != now.YearDay() {
+			continue
+		}
+		if todaysEvents == 0 {
+			brief.WriteString("\n:calendar: *Today's Meetings*\n")
+		}
+		brief.WriteString(fmt.Sprintf("• %s %s\n", event.StartTS.Format("3:04 PM"), event.Title))
+		todaysEvents++
+	}
+
+	if len(upcoming) > 0 {
+		brief.WriteString("\n:repeat: *Coming Up (Recurring)*\n")
+		for _, task := range upcoming {
+			brief.WriteString(fmt.Sprintf("• %s %s (%s)\n",
+				task.DueTS.Format("Mon Jan 2"), task.Title, db.DescribeRecurrence(task.Recurrence)))
+		}
+	}
+
+	if len(threadChanges) > 0 {
+		brief.WriteString("\n:memo: *Thread Updates*\n")
+		for _, change := range threadChanges {
+			subject := change.Subject
+			if subject == "" {
+				subject = "(no subject)"
+			}
+			brief.WriteString(fmt.Sprintf("• *%s*: %s\n", subject, change.Describe()))
+		}
+	}
+
+	if len(docs) > 0 {
+		brief.WriteString("\n:page_facing_up: *Docs Updated*\n")
+		for _, doc := range docs {
+			if doc.Link != "" {
+				brief.WriteString(fmt.Sprintf("• <%s|%s>\n", doc.Link, doc.Describe()))
+			} else {
+				brief.WriteString(fmt.Sprintf("• %s\n", doc.Describe()))
+			}
+		}
+	}
+
+	if len(commitments) > 0 {
+		brief.WriteString("\n:handshake: *Outstanding Commitments*\n")
+		for _, commitment := range commitments {
+			brief.WriteString(fmt.Sprintf("• %s\n", commitment.Describe(now)))
+		}
+	}
+
+	return c.Deliver(ctx, database, "daily_brief", &Message{Text: brief.String()})
+}
+
+// SendReplanBrief posts the midday replan as a reply in the day's thread
+func (c *Client) SendReplanBrief(ctx context.Context, database *db.DB, completedTasks int, remainingTasks []*db.Task, afternoonEvents []*db.Event) error {
+	var text strings.Builder
+
+	text.WriteString(fmt.Sprintf("*Midday Re-plan* - %s\n", time.Now().Format("3:04 PM")))
+	text.WriteString(fmt.Sprintf(":white_check_mark: %d tasks completed, %d remaining\n", completedTasks, len(remainingTasks)))
+
+	if len(remainingTasks) > 0 {
+		text.WriteString("\n:dart: *Afternoon Priorities*\n")
+		for i, task := range remainingTasks {
+			if i >= 5 {
+				break
+			}
+			text.WriteString(fmt.Sprintf("%s %s\n", priorityIndicator(task.Score), task.Title))
+		}
+	}
+
+	if len(afternoonEvents) > 0 {
+		text.WriteString("\n:calendar: *This Afternoon*\n")
+		for _, event := range afternoonEvents {
+			text.WriteString(fmt.Sprintf("• %s %s\n", event.StartTS.Format("3:04 PM"), event.Title))
+		}
+	}
+
+	return c.Deliver(ctx, database, "replan_brief", &Message{Text: text.String()})
+}
+
+// SendEndOfDayBrief posts the end-of-day shutdown brief as a reply in the day's thread
+func (c *Client) SendEndOfDayBrief(ctx context.Context, database *db.DB, brief *db.EndOfDayBrief) error {
+	var text strings.Builder
+
+	text.WriteString(fmt.Sprintf("*End of Day* - %s\n", brief.Date.Format("Monday, January 2")))
+
+	writeTasks := func(header string, tasks []*db.Task, describe func(*db.Task) string) {
+		if len(tasks) == 0 {
+			return
+		}
+		text.WriteString("\n" + header + "\n")
+		for i, task := range tasks {
+			if i >= 8 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(tasks)-8))
+				break
+			}
+			text.WriteString(describe(task) + "\n")
+		}
+	}
+
+	writeTasks(fmt.Sprintf(":white_check_mark: *Completed Today (%d)*", len(brief.Completed)), brief.Completed, func(task *db.Task) string {
+		return "• " + task.Title
+	})
+	if len(brief.Plan) > 0 {
+		text.WriteString("\n:clipboard: *Plan vs Actual*\n")
+		for _, actual := range brief.Plan {
+			text.WriteString("• " + actual.Describe() + "\n")
+		}
+	}
+	writeTasks(":arrow_right: *Slipping to Tomorrow*", brief.Slipping, func(task *db.Task) string {
+		if task.DueTS != nil && task.DueTS.Before(brief.Date) {
+			return fmt.Sprintf("• %s (overdue since %s)", task.Title, task.DueTS.Format("Mon Jan 2"))
+		}
+		return "• " + task.Title
+	})
+
+	if len(brief.TomorrowMorning) > 0 || len(brief.DueTomorrowAM) > 0 {
+		text.WriteString("\n:sunrise: *Tomorrow Morning*\n")
+		for _, event := range brief.TomorrowMorning {
+			text.WriteString(fmt.Sprintf("• %s %s\n", event.StartTS.Format("3:04 PM"), event.Title))
+		}
+		for _, task := range brief.DueTomorrowAM {
+			text.WriteString(fmt.Sprintf("• %s (due %s)\n", task.Title, task.DueTS.Format("3:04 PM")))
+		}
+	}
+
+	text.WriteString(fmt.Sprintf("\n:seedling: *Tomorrow's Plan*: %s\n", brief.PlanSeed))
+
+	return c.Deliver(ctx, database, "end_of_day_brief", &Message{Text: text.String()})
+}
+
+// SendFollowUpReminder posts a follow-up reminder as a reply in the day's thread
+func (c *Client) SendFollowUpReminder(ctx context.Context, database *db.DB, threads []*db.Thread) error {
+	if len(threads) == 0 {
+		return nil
+	}
+
+	var text strings.Builder
+	text.WriteString(":alarm_clock: *Follow-up Reminders*\n\n")
+
+	for i, thread := range threads {
+		if i >= 5 {
+			text.WriteString(fmt.Sprintf("... and %d more threads need attention\n", len(threads)-5))
+			break
+		}
+		text.WriteString(fmt.Sprintf("• Thread: %s\n", thread.Summary))
+	}
+
+	return c.Deliver(ctx, database, "follow_up", &Message{Text: text.String()})
+}
+
+// SendWaitingReminder nudges the user about requests they sent that haven't had a reply
+func (c *Client) SendWaitingReminder(ctx context.Context, database *db.DB, items []*db.WaitingItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	var text strings.Builder
+	text.WriteString(":hourglass_flowing_sand: *Still Waiting On*\n\n")
+
+	for i, item := range items {
+		if i >= 10 {
+			text.WriteString(fmt.Sprintf("... and %d more\n", len(items)-10))
+			break
+		}
+		text.WriteString(fmt.Sprintf("• %s - %s, asked %s (%s)\n", item.Recipient, item.Request,
+			item.SentAt.Format("Mon Jan 2"), item.Subject))
+	}
+
+	return c.Deliver(ctx, database, "follow_up", &Message{Text: text.String()})
+}
+
+// SendGuardAlert warns that the database, its disk or a Google API quota is nearing its limit and
+// low-priority work has paused
+func (c *Client) SendGuardAlert(ctx context.Context, database *db.DB, warnings []string) error {
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	var text strings.Builder
+	text.WriteString(":warning: *Running Short on Resources*\n\n")
+	for _, warning := range warnings {
+		text.WriteString(fmt.Sprintf("• %s\n", warning))
+	}
+	text.WriteString("\nSearch embedding and task re-enrichment are paused until usage drops.\n")
+
+	return c.Deliver(ctx, database, "guard_alert", &Message{Text: text.String()})
+}
+
+// SendCanaryAlert reports the pipeline stages the nightly canary thread failed or that ran over
+// their latency budget
+func (c *Client) SendCanaryAlert(ctx context.Context, database *db.DB, problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	var text strings.Builder
+	text.WriteString(":hatched_chick: *Pipeline Canary Failed*\n\n")
+	for _, problem := range problems {
+		text.WriteString(fmt.Sprintf("• %s\n", problem))
+	}
+	text.WriteString("\nNew email may not be turning into tasks; check the agent's logs.\n")
+
+	return c.Deliver(ctx, database, "canary_alert", &Message{Text: text.String()})
+}
+
+// SendEscalation chases an overdue high-impact task, with a suggested email to send once it's
+// reached the draft step
+func (c *Client) SendEscalation(ctx context.Context, database *db.DB, escalation *db.TaskEscalation) error {
+	task := escalation.Task
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf(":rotating_light: *Overdue*: %s\n", task.Title))
+	text.WriteString(fmt.Sprintf("Was due %s", task.DueTS.Format("Mon Jan 2 at 3:04 PM")))
+	if task.Project != "" {
+		text.WriteString(fmt.Sprintf(" (%s)", task.Project))
+	}
+	text.WriteString("\n")
+
+	if escalation.Draft != "" {
+		text.WriteString(fmt.Sprintf("\n:envelope: *Suggested note*:\n%s\n", escalation.Draft))
+		if escalation.DraftSaved {
+			text.WriteString("\nSaved as a Gmail draft on the thread.\n")
+		}
+	}
+
+	return c.Deliver(ctx, database, "escalation", &Message{Text: text.String()})
+}
+
+// SendWeeklyDigest posts the weekly "everything else" digest in its own thread
+func (c *Client) SendWeeklyDigest(ctx context.Context, database *db.DB, tasks []*db.Task, threads []*db.Thread, tagStats []*db.TagStats) error {
+	if len(tasks) == 0 && len(threads) == 0 && len(tagStats) == 0 {
+		return nil
+	}
+
+	year, week := time.Now().ISOWeek()
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf(":inbox_tray: *Everything Else* - week %d\n", week))
+	text.WriteString(fmt.Sprintf("_%d low-priority tasks, %d FYI threads_\n", len(tasks), len(threads)))
+
+	if len(tasks) > 0 {
+		text.WriteString("\n*Low-priority tasks*\n")
+		for i, task := range tasks {
+			if i >= 10 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(tasks)-10))
+				break
+			}
+			text.WriteString(fmt.Sprintf("• %s (%.0f%%)\n", task.Title, task.Score))
+		}
+	}
+
+	if len(threads) > 0 {
+		text.WriteString("\n*FYI threads*\n")
+		for i, thread := range threads {
+			if i >= 10 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(threads)-10))
+				break
+			}
+			summary := thread.Summary
+			if len(summary) > 120 {
+				summary = summary[:117] + "..."
+			}
+			text.WriteString(fmt.Sprintf("• %s\n", summary))
+		}
+	}
+
+	if len(tagStats) > 0 {
+		text.WriteString("\n*By tag*\n")
+		for i, stat := range tagStats {
+			if i >= 10 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(tagStats)-10))
+				break
+			}
+			text.WriteString(fmt.Sprintf("• #%s: %d done, %d added, %d open\n", stat.Tag, stat.Completed, stat.Added, stat.Open))
+		}
+	}
+
+	text.WriteString("\nPress *D* in the Tasks view to review and archive all of it in one go.")
+
+	message := &Message{
+		Text:      text.String(),
+		ThreadKey: fmt.Sprintf("%s-digest-%d-W%02d", c.threadKey, year, week),
+	}
+	return c.Deliver(ctx, database, "weekly_digest", message)
+}
+
+// SendRelationshipBrief posts a pre-meeting brief about an external contact.
+// Briefs for the same meeting share a thread.
+func (c *Client) SendRelationshipBrief(ctx context.Context, database *db.DB, event *db.Event, history *db.ContactHistory, analysis string) error {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf(":handshake: *Before %s* - %s\n", event.Title, event.StartTS.Format("3:04 PM")))
+	text.WriteString(fmt.Sprintf("_Relationship brief: %s_\n", history.Email))
+
+	if analysis != "" {
+		text.WriteString("\n" + analysis + "\n")
+	}
+
+	text.WriteString("\n*Last interactions*\n")
+	if len(history.Interactions) == 0 {
+		text.WriteString("No email history yet\n")
+	}
+	for _, interaction := range history.Interactions {
+		text.WriteString(fmt.Sprintf("• %s - %s\n", interaction.LastTS.Format("Jan 2"), interaction.Subject))
+	}
+
+	if len(history.YouOwe) > 0 {
+		text.WriteString("\n*You owe them*\n")
+		for i, task := range history.YouOwe {
+			if i >= 5 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(history.YouOwe)-5))
+				break
+			}
+			text.WriteString(fmt.Sprintf("• %s\n", task.Title))
+		}
+	}
+
+	if len(history.TheyOwe) > 0 {
+		text.WriteString("\n*Waiting on them*\n")
+		for i, interaction := range history.TheyOwe {
+			if i >= 5 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(history.TheyOwe)-5))
+				break
+			}
+			text.WriteString(fmt.Sprintf("• %s (since %s)\n", interaction.Subject, interaction.LastTS.Format("Jan 2")))
+		}
+	}
+
+	message := &Message{
+		Text:      text.String(),
+		ThreadKey: fmt.Sprintf("%s-meeting-%s", c.threadKey, event.ID),
+	}
+	return c.Deliver(ctx, database, "relationship_brief", message)
+}
+
+// SendMeetingPrep posts a meeting's prep notes with links to its related documents.
+// It shares a thread with the meeting's relationship briefs.
+func (c *Client) SendMeetingPrep(ctx context.Context, database *db.DB, prep *db.MeetingPrep, docs []*db.Document) error {
+	event := prep.Event
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf(":clipboard: *Prep: %s* - %s\n", event.Title, event.StartTS.Format("3:04 PM")))
+	if event.MeetingLink != "" {
+		text.WriteString(fmt.Sprintf("<%s|Join meeting>\n", event.MeetingLink))
+	}
+	text.WriteString("\n" + prep.Notes + "\n")
+
+	if len(docs) > 0 {
+		text.WriteString("\n*Related documents*\n")
+		for _, doc := range docs {
+			text.WriteString(fmt.Sprintf("• <%s|%s>\n", doc.Link, doc.Title))
+		}
+	}
+
+	message := &Message{
+		Text:      text.String(),
+		ThreadKey: fmt.Sprintf("%s-meeting-%s", c.threadKey, event.ID),
+	}
+	return c.Deliver(ctx, database, "meeting_prep", message)
+}
+
+// priorityIndicator returns an emoji for a 0-100 task score, matching the TUI's bands
+func priorityIndicator(score float64) string {
+	switch {
+	case score >= 80:
+		return ":red_circle:"
+	case score >= 62:
+		return ":large_yellow_circle:"
+	default:
+		return ":large_green_circle:"
+	}
+}
+
+// taskLink returns a deep link to the task's source, if there is one
+func taskLink(task *db.Task) string {
+	switch task.Source {
+	case "gmail":
+		if task.SourceID != "" {
+			return fmt.Sprintf("https://mail.google.com/mail/u/0/#inbox/%s", task.SourceID)
+		}
+	case "gtasks", "google_tasks":
+		return "https://tasks.google.com"
+	case db.TaskSourceGoogleChat:
+		return db.ChatThreadLink(task.SourceID)
+	}
+	return ""
+}
+
+// SendFocusSummary posts the exit summary of a focus session: what arrived while notifications were held
+func (c *Client) SendFocusSummary(ctx context.Context, database *db.DB, summary *db.FocusSummary) error {
+	session := summary.Session
+	minutes := int(session.End().Sub(session.StartedAt).Minutes())
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf(":headphones: *Focus session over* - %d min\n", minutes))
+	text.WriteString(fmt.Sprintf("_%d email threads, %d new tasks while you were heads-down_\n", len(summary.Threads), len(summary.Tasks)))
+
+	if len(summary.Threads) > 0 {
+		text.WriteString("\n*Email*\n")
+		for i, thread := range summary.Threads {
+			if i >= 10 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(summary.Threads)-10))
+				break
+			}
+			text.WriteString(fmt.Sprintf("• %s - %s (%d)\n", thread.Subject, thread.From, thread.Messages))
+		}
+	}
+
+	if len(summary.Tasks) > 0 {
+		text.WriteString("\n*New tasks*\n")
+		for i, task := range summary.Tasks {
+			if i >= 10 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(summary.Tasks)-10))
+				break
+			}
+			text.WriteString(fmt.Sprintf("• %s (%.0f%%)\n", task.Title, task.Score))
+		}
+	}
+
+	if len(summary.Notifications) > 0 {
+		var held []string
+		for kind, count := range summary.Notifications {
+			held = append(held, fmt.Sprintf("%d %s", count, strings.ReplaceAll(kind, "_", " ")))
+		}
+		sort.Strings(held)
+		text.WriteString(fmt.Sprintf("\nHeld notifications follow: %s", strings.Join(held, ", ")))
+	}
+
+	return c.Deliver(ctx, database, "focus_summary", &Message{Text: text.String()})
+}
+
+// SendWeeklyReview posts the weekly review of completed work and misses
+func (c *Client) SendWeeklyReview(ctx context.Context, database *db.DB, review *db.WeeklyReview) error {
+	var text strings.Builder
+
+	text.WriteString(fmt.Sprintf("*Weekly Review* - %s to %s\n", review.Since.Format("Jan 2"), review.Until.Format("Jan 2")))
+	if review.Narrative != "" {
+		text.WriteString("\n" + review.Narrative + "\n")
+	}
+
+	writeLines := func(header string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		text.WriteString("\n" + header + "\n")
+		for i, line := range lines {
+			if i >= 8 {
+				text.WriteString(fmt.Sprintf("... and %d more\n", len(lines)-8))
+				break
+			}
+			text.WriteString(line + "\n")
+		}
+	}
+
+	var completed, slipped, awaiting []string
+	for _, task := range review.Completed {
+		completed = append(completed, "• "+task.Title)
+	}
+	for _, task := range review.Slipped {
+		slipped = append(slipped, fmt.Sprintf("• %s (due %s)", task.Title, task.DueTS.Format("Mon Jan 2")))
+	}
+	for _, thread := range review.AwaitingReply {
+		awaiting = append(awaiting, fmt.Sprintf("• %s (from %s, %s)", thread.Subject, thread.From, thread.ReceivedAt.Format("Mon Jan 2")))
+	}
+	writeLines(fmt.Sprintf(":white_check_mark: *Completed (%d)*", len(review.Completed)), completed)
+	writeLines(fmt.Sprintf(":warning: *Slipped Past Due (%d)*", len(review.Slipped)), slipped)
+	writeLines(fmt.Sprintf(":mailbox_with_mail: *Awaiting Your Reply (%d)*", len(review.AwaitingReply)), awaiting)
+
+	text.WriteString(fmt.Sprintf("\n:calendar: *Meetings*: %d, %.1f hours\n", review.MeetingCount, review.MeetingHours))
+	if len(review.AutoArchived) > 0 {
+		text.WriteString(fmt.Sprintf(":file_cabinet: *Auto-Archived*: %s\n", db.FormatSourceCounts(review.AutoArchived)))
+	}
+
+	if len(review.StaleProjects) > 0 {
+		var stale []string
+		for _, project := range review.StaleProjects {
+			stale = append(stale, fmt.Sprintf("• %s (%d open, quiet since %s)", project.Name, project.OpenTasks, project.LastActivity.Format("Jan 2")))
+		}
+		writeLines(fmt.Sprintf(":city_sunset: *Consider Closing (%d)*", len(review.StaleProjects)), stale)
+		text.WriteString("_Archive a project and its remaining tasks with A on the Projects tab._\n")
+	}
+
+	return c.Deliver(ctx, database, "weekly_review", &Message{Text: text.String()})
+}
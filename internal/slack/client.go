@@ -0,0 +1,266 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/plaintext"
+)
+
+const (
+	postMessageURL = "https://slack.com/api/chat.postMessage"
+
+	// channelName is the outbox channel name used for Slack deliveries
+	channelName = config.ChannelSlack
+)
+
+// Client posts briefs and reminders to Slack via an incoming webhook or a bot token
+type Client struct {
+	config     config.Slack
+	threadKey  string
+	plain      bool // notifications.plain_formatting includes slack
+	quietHours config.QuietHours
+	schedule   *config.Schedule // Quiet hours are in its timezone, which may be overridden at runtime
+	httpClient *http.Client
+}
+
+// Message is a Slack message as stored in the outbox
+type Message struct {
+	Text      string `json:"text"`
+	ThreadKey string `json:"thread_key,omitempty"` // Replies are threaded under the first message with this key
+}
+
+// NewClient creates a new Slack client
+func NewClient(cfg *config.Config) *Client {
+	threadKey := cfg.Chat.ThreadKey
+	if threadKey == "" {
+		threadKey = "focus-agent"
+	}
+
+	return &Client{
+		config:     cfg.Slack,
+		threadKey:  threadKey,
+		plain:      cfg.Notify.Plain(config.ChannelSlack),
+		quietHours: cfg.Notify.QuietHours,
+		schedule:   &cfg.Schedule,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// dailyThreadKey returns the thread key shared by all of today's brief messages
+func (c *Client) dailyThreadKey(now time.Time) string {
+	return fmt.Sprintf("%s-%s", c.threadKey, now.Format("2006-01-02"))
+}
+
+// Deliver persists a message to the outbox and attempts delivery.
+// A failed attempt leaves the message queued; RetryPending will deliver it later.
+func (c *Client) Deliver(ctx context.Context, database *db.DB, kind string, message *Message) error {
+	if message.ThreadKey == "" {
+		message.ThreadKey = c.dailyThreadKey(time.Now())
+	}
+	if c.plain {
+		message.Text = plaintext.Text(message.Text)
+	}
+
+	// Without a database there is nowhere to persist the message, so send directly
+	if database == nil {
+		_, err := c.post(ctx, message.Text, "")
+		return err
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	item, err := database.EnqueueNotification(channelName, kind, string(payload), message.ThreadKey)
+	if err != nil {
+		// Persisting failed - still try to get the message out
+		log.Printf("Warning: failed to persist %s to outbox, sending directly: %v", kind, err)
+		_, err = c.post(ctx, message.Text, "")
+		return err
+	}
+
+	// Quiet hours hold some kinds in the outbox until the quiet ends
+	if until, quiet := c.quietHours.Until(kind, c.schedule.Now()); quiet {
+		if err := database.DeferNotification(item.ID, until); err != nil {
+			return err
+		}
+		log.Printf("Quiet hours, holding Slack %s until %s", kind, until.Format("Mon 15:04"))
+		return nil
+	}
+
+	// Focus mode holds messages in the outbox until it ends
+	if database.InFocusMode() {
+		log.Printf("Focus mode active, holding %s until it ends", kind)
+		return nil
+	}
+
+	if err := c.deliverItem(ctx, database, item, message); err != nil {
+		return fmt.Errorf("failed to deliver %s to Slack (queued for retry): %w", kind, err)
+	}
+
+	return nil
+}
+
+// RetryPending re-attempts delivery of queued Slack messages whose backoff has elapsed
+func (c *Client) RetryPending(ctx context.Context, database *db.DB) (int, error) {
+	if database.InFocusMode() {
+		return 0, nil
+	}
+
+	items, err := database.GetDueNotifications(channelName, 20)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load pending notifications: %w", err)
+	}
+
+	sent := 0
+	for _, item := range items {
+		var message Message
+		if err := json.Unmarshal([]byte(item.Payload), &message); err != nil {
+			log.Printf("Dropping undecodable %s notification %s: %v", item.Kind, item.ID, err)
+			database.MarkNotificationFailed(item.ID, err, time.Now(), 0)
+			continue
+		}
+
+		if err := c.deliverItem(ctx, database, item, &message); err != nil {
+			log.Printf("Retry of Slack %s notification %s failed (attempt %d): %v", item.Kind, item.ID, item.Attempts+1, err)
+			continue
+		}
+
+		sent++
+	}
+
+	return sent, nil
+}
+
+// deliverItem sends one outbox message and records the outcome
+func (c *Client) deliverItem(ctx context.Context, database *db.DB, item *db.OutboxItem, message *Message) error {
+	// Thread under the day's first message when posting as a bot
+	var threadTS string
+	if c.config.BotToken != "" && message.ThreadKey != "" {
+		rootTS, err := database.GetThreadRootRemoteID(channelName, message.ThreadKey)
+		if err != nil {
+			log.Printf("Warning: failed to look up Slack thread for %s: %v", message.ThreadKey, err)
+		}
+		threadTS = rootTS
+	}
+
+	ts, err := c.post(ctx, message.Text, threadTS)
+	if err != nil {
+		nextAttempt := time.Now().Add(retryBackoff(item.Attempts + 1))
+		if markErr := database.MarkNotificationFailed(item.ID, err, nextAttempt, c.config.MaxDeliveryAttempts); markErr != nil {
+			log.Printf("Warning: failed to record delivery failure for %s: %v", item.ID, markErr)
+		}
+		return err
+	}
+
+	if err := database.MarkNotificationSent(item.ID, ts); err != nil {
+		log.Printf("Warning: failed to record delivery of %s: %v", item.ID, err)
+	}
+	return nil
+}
+
+// post sends text to Slack and returns the message timestamp (bot token only)
+func (c *Client) post(ctx context.Context, text, threadTS string) (string, error) {
+	if c.config.BotToken != "" {
+		return c.postAsBot(ctx, text, threadTS)
+	}
+	if c.config.WebhookURL != "" {
+		return "", c.postToWebhook(ctx, text)
+	}
+	return "", fmt.Errorf("slack is not configured: set slack.webhook_url or slack.bot_token")
+}
+
+// postToWebhook posts a message to an incoming webhook
+func (c *Client) postToWebhook(ctx context.Context, text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Slack webhook error: %d %s", resp.StatusCode, string(respBody))
+	}
+
+	return nil
+}
+
+// postAsBot posts a message with chat.postMessage, optionally as a thread reply
+func (c *Client) postAsBot(ctx context.Context, text, threadTS string) (string, error) {
+	payload := map[string]string{
+		"channel": c.config.Channel,
+		"text":    text,
+	}
+	if threadTS != "" {
+		payload["thread_ts"] = threadTS
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", postMessageURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.config.BotToken))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Slack API error: %d", resp.StatusCode)
+	}
+
+	// Slack reports most failures with HTTP 200 and ok=false
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		TS    string `json:"ts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode Slack response: %w", err)
+	}
+	if !result.OK {
+		return "", fmt.Errorf("Slack API error: %s", result.Error)
+	}
+
+	return result.TS, nil
+}
+
+// retryBackoff returns an exponential delay (1m, 2m, 4m, ...) capped at one hour
+func retryBackoff(attempts int) time.Duration {
+	if attempts > 6 {
+		return time.Hour
+	}
+	return time.Minute << uint(attempts-1)
+}
@@ -0,0 +1,37 @@
+package stt
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+)
+
+// Transcriber turns an audio clip into text
+type Transcriber interface {
+	// Transcribe returns the transcript of audio. filename is the original upload name,
+	// used as a hint for the audio format.
+	Transcribe(ctx context.Context, audio []byte, filename string) (string, error)
+}
+
+// New returns the transcriber for the configured backend
+func New(cfg config.STT) (Transcriber, error) {
+	switch cfg.Backend {
+	case config.STTWhisperCpp:
+		if cfg.WhisperModel == "" {
+			return nil, fmt.Errorf("stt.whisper_model is required for the whisper_cpp backend")
+		}
+		return &WhisperCpp{
+			binary: cfg.WhisperBinary,
+			model:  cfg.WhisperModel,
+			ffmpeg: cfg.FFmpegBinary,
+		}, nil
+	case config.STTOpenAI:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("stt.api_key is required for the openai backend")
+		}
+		return NewOpenAI(cfg.APIURL, cfg.APIKey, cfg.Model), nil
+	default:
+		return nil, fmt.Errorf("unknown stt backend %q", cfg.Backend)
+	}
+}
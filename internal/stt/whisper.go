@@ -0,0 +1,58 @@
+package stt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// WhisperCpp transcribes locally with the whisper.cpp CLI
+type WhisperCpp struct {
+	binary string
+	model  string
+	ffmpeg string
+}
+
+// Transcribe converts the clip to 16kHz mono WAV (what whisper.cpp expects) and transcribes it
+func (w *WhisperCpp) Transcribe(ctx context.Context, audio []byte, filename string) (string, error) {
+	dir, err := os.MkdirTemp("", "focus-agent-stt-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ext := filepath.Ext(filename)
+	if ext == "" {
+		ext = ".audio"
+	}
+	inputPath := filepath.Join(dir, "input"+ext)
+	if err := os.WriteFile(inputPath, audio, 0600); err != nil {
+		return "", fmt.Errorf("failed to write audio: %w", err)
+	}
+
+	wavPath := filepath.Join(dir, "input.wav")
+	convert := exec.CommandContext(ctx, w.ffmpeg, "-y", "-i", inputPath, "-ar", "16000", "-ac", "1", "-c:a", "pcm_s16le", wavPath)
+	if output, err := convert.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("ffmpeg conversion failed: %w: %s", err, lastLine(output))
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, w.binary, "-m", w.model, "-f", wavPath, "--no-timestamps", "--no-prints")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("whisper.cpp failed: %w: %s", err, lastLine(stderr.Bytes()))
+	}
+
+	return strings.Join(strings.Fields(stdout.String()), " "), nil
+}
+
+// lastLine returns the last non-empty line of command output, which usually holds the error
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
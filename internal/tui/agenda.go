@@ -0,0 +1,501 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// agendaAttendeesShown is how many attendees are listed before the rest are counted
+const agendaAttendeesShown = 3
+
+// AgendaModel is the tab listing today's and tomorrow's meetings, or the coming week's, where
+// invites can be accepted or declined
+type AgendaModel struct {
+	database  *db.DB
+	apiClient *APIClient
+	planner   *planner.Planner
+	events    []*db.Event
+	cursor    int
+	week      bool            // Show the next seven days instead of today and tomorrow
+	expanded  map[string]bool // Events whose prep notes are shown
+	decline   *declineNote    // Decline note being drafted, if any
+	loading   bool
+	err       error
+	message   string // Result of the last open or reply
+	viewport  viewport.Model
+	ready     bool
+}
+
+// declineNote is a note drafted to decline a meeting with, shown for approval before it's sent
+type declineNote struct {
+	event    *db.Event
+	drafting bool
+	note     string
+	err      error
+}
+
+type agendaLoadedMsg struct {
+	events []*db.Event
+	err    error
+}
+
+type eventRespondedMsg struct {
+	event *db.Event
+	err   error
+}
+
+type declineNoteDraftedMsg struct {
+	eventID string
+	note    string
+	err     error
+}
+
+func NewAgendaModel(database *db.DB, apiClient *APIClient, plannerService *planner.Planner) AgendaModel {
+	return AgendaModel{
+		database:  database,
+		apiClient: apiClient,
+		planner:   plannerService,
+		expanded:  make(map[string]bool),
+		loading:   true,
+		viewport:  viewport.New(80, 20),
+	}
+}
+
+// IsDeclining reports whether the agenda is showing a drafted decline note
+func (m AgendaModel) IsDeclining() bool {
+	return m.decline != nil
+}
+
+// SetSize updates the viewport dimensions
+func (m *AgendaModel) SetSize(width, height int) {
+	m.viewport.Width = width
+	m.viewport.Height = height - 2 // Room for the help line
+	m.ready = true
+}
+
+func (m AgendaModel) fetchAgenda() tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			events, err := m.apiClient.GetAgenda(m.week)
+			return agendaLoadedMsg{events: events, err: err}
+		}
+
+		days := 2
+		if m.week {
+			days = 7
+		}
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		events, err := m.database.GetAgenda(today, today.AddDate(0, 0, days))
+		return agendaLoadedMsg{events: events, err: err}
+	}
+}
+
+// respond accepts, tentatively accepts or declines an invite, with an optional note
+func (m AgendaModel) respond(eventID, response, note string) tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			event, err := m.apiClient.RespondToEvent(eventID, response, note)
+			return eventRespondedMsg{event: event, err: err}
+		}
+		if m.planner == nil {
+			return eventRespondedMsg{err: fmt.Errorf("replying to invites needs the planner")}
+		}
+		event, err := m.planner.RespondToEvent(context.Background(), eventID, response, note)
+		return eventRespondedMsg{event: event, err: err}
+	}
+}
+
+// draftDeclineNote asks the LLM for a polite note to decline a meeting with
+func (m AgendaModel) draftDeclineNote(eventID string) tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			note, err := m.apiClient.DraftDeclineNote(eventID)
+			return declineNoteDraftedMsg{eventID: eventID, note: note, err: err}
+		}
+		if m.planner == nil {
+			return declineNoteDraftedMsg{eventID: eventID, err: fmt.Errorf("drafting needs the planner")}
+		}
+		note, err := m.planner.DraftDeclineNote(context.Background(), eventID)
+		return declineNoteDraftedMsg{eventID: eventID, note: note, err: err}
+	}
+}
+
+func (m AgendaModel) Update(msg tea.Msg) (AgendaModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case agendaLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.events = msg.events
+			// Start on the next meeting rather than the first of the day
+			if m.cursor == 0 {
+				m.cursor = nextEventIndex(m.events, time.Now())
+			}
+			if m.cursor >= len(m.events) {
+				m.cursor = max(len(m.events)-1, 0)
+			}
+		}
+		m.refreshViewport()
+		return m, nil
+
+	case eventRespondedMsg:
+		if msg.err != nil {
+			if m.decline != nil {
+				m.decline.err = msg.err
+			} else {
+				m.message = fmt.Sprintf("Error: %v", msg.err)
+			}
+			return m, nil
+		}
+		m.decline = nil
+		for i, event := range m.events {
+			if event.ID == msg.event.ID {
+				m.events[i].Response = msg.event.Response
+			}
+		}
+		m.message = fmt.Sprintf("Replied %s to %s", msg.event.Response, msg.event.Title)
+		m.refreshViewport()
+		return m, nil
+
+	case declineNoteDraftedMsg:
+		if m.decline == nil || m.decline.event.ID != msg.eventID {
+			return m, nil
+		}
+		m.decline.drafting = false
+		m.decline.note = msg.note
+		m.decline.err = msg.err
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.decline != nil {
+			return m.updateDecline(msg)
+		}
+
+		m.message = ""
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.events)-1 {
+				m.cursor++
+			}
+		case "enter", "p":
+			// Show or hide the selected meeting's prep notes
+			if m.cursor < len(m.events) {
+				id := m.events[m.cursor].ID
+				m.expanded[id] = !m.expanded[id]
+			}
+		case "o":
+			// Join the selected meeting
+			if m.cursor < len(m.events) {
+				event := m.events[m.cursor]
+				switch {
+				case event.MeetingLink == "":
+					m.message = "No meeting link for " + event.Title
+				case openBrowser(event.MeetingLink) != nil:
+					m.message = "Couldn't open a browser; link: " + event.MeetingLink
+				default:
+					m.message = "Opening " + event.Title
+				}
+			}
+		case "a", "t", "d":
+			// Reply to the selected invite
+			if m.cursor < len(m.events) {
+				event := m.events[m.cursor]
+				response := map[string]string{"a": planner.RSVPAccepted, "t": planner.RSVPTentative, "d": planner.RSVPDeclined}[msg.String()]
+				m.message = fmt.Sprintf("Replying %s to %s...", response, event.Title)
+				return m, m.respond(event.ID, response, "")
+			}
+		case "D":
+			// Draft a note to decline the selected meeting with
+			if m.cursor < len(m.events) {
+				m.decline = &declineNote{event: m.events[m.cursor], drafting: true}
+				return m, m.draftDeclineNote(m.decline.event.ID)
+			}
+		case "w":
+			// Switch between today and tomorrow, and the coming week
+			m.week = !m.week
+			m.loading = true
+			m.cursor = 0
+			return m, m.fetchAgenda()
+		case "r":
+			m.loading = true
+			return m, m.fetchAgenda()
+		}
+		m.refreshViewport()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// updateDecline handles keys while a drafted decline note is shown
+func (m AgendaModel) updateDecline(msg tea.KeyMsg) (AgendaModel, tea.Cmd) {
+	d := m.decline
+	switch msg.String() {
+	case "esc", "q", "n":
+		m.decline = nil
+		m.refreshViewport()
+	case "enter", "y":
+		if !d.drafting && d.note != "" {
+			d.err = nil
+			m.message = "Declining " + d.event.Title + "..."
+			return m, m.respond(d.event.ID, planner.RSVPDeclined, d.note)
+		}
+	case "r":
+		if !d.drafting {
+			d.drafting = true
+			d.err = nil
+			return m, m.draftDeclineNote(d.event.ID)
+		}
+	}
+	return m, nil
+}
+
+// nextEventIndex returns the first event that hasn't ended, or 0 if they all have
+func nextEventIndex(events []*db.Event, now time.Time) int {
+	for i, event := range events {
+		if event.EndTS.After(now) && event.Status != "cancelled" {
+			return i
+		}
+	}
+	return 0
+}
+
+// refreshViewport re-renders the agenda and scrolls to keep the selected meeting in view
+func (m *AgendaModel) refreshViewport() {
+	content, selectedLine := m.renderEvents()
+	m.viewport.SetContent(content)
+	if selectedLine < m.viewport.YOffset {
+		m.viewport.SetYOffset(selectedLine)
+	} else if selectedLine >= m.viewport.YOffset+m.viewport.Height-3 {
+		m.viewport.SetYOffset(selectedLine - m.viewport.Height + 4)
+	}
+}
+
+// renderEvents renders the meetings grouped by day, returning the line the selected one starts on
+func (m AgendaModel) renderEvents() (string, int) {
+	dayStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	notesStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Padding(0, 0, 0, 5).
+		Width(max(m.viewport.Width-6, 20))
+
+	if m.loading && len(m.events) == 0 {
+		return "  Loading agenda...\n", 0
+	}
+
+	var b strings.Builder
+	if m.err != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(0, 1)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n\n")
+	}
+	if len(m.events) == 0 {
+		empty := "No meetings today or tomorrow."
+		if m.week {
+			empty = "No meetings this week."
+		}
+		b.WriteString(dimStyle.Padding(0, 1).Render(empty) + "\n")
+		return b.String(), 0
+	}
+
+	now := time.Now()
+	selectedLine := 0
+	lastDay := ""
+	for i, event := range m.events {
+		day := event.StartTS.Format("2006-01-02")
+		if day != lastDay {
+			if lastDay != "" {
+				b.WriteString("\n")
+			}
+			label := event.StartTS.Format("Monday, January 2")
+			switch day {
+			case now.Format("2006-01-02"):
+				label = "Today · " + label
+			case now.AddDate(0, 0, 1).Format("2006-01-02"):
+				label = "Tomorrow · " + label
+			}
+			b.WriteString(dayStyle.Render(label) + "\n\n")
+			lastDay = day
+		}
+		if i == m.cursor {
+			selectedLine = strings.Count(b.String(), "\n")
+		}
+
+		title := event.Title
+		if event.MeetingLink != "" {
+			title = makeHyperlink(event.MeetingLink, title)
+		}
+		line := fmt.Sprintf("%s–%s  %s", event.StartTS.Format("15:04"), event.EndTS.Format("15:04"), title)
+		if i == m.cursor {
+			line = selectedStyle.Render("▶ " + line)
+		} else if event.Status == "cancelled" || !event.EndTS.After(now) {
+			line = dimStyle.Render("  " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(" " + line + "  " + eventStatus(event, now) + "\n")
+
+		details := []string{prepStatus(event)}
+		if attendees := formatAttendees(event.Attendees); attendees != "" {
+			details = append([]string{attendees}, details...)
+		}
+		if event.Location != "" && event.Location != event.MeetingLink {
+			details = append(details, event.Location)
+		}
+		b.WriteString(dimStyle.Render("     "+strings.Join(details, " · ")) + "\n")
+
+		if m.expanded[event.ID] && event.PrepNotes != "" {
+			b.WriteString("\n" + notesStyle.Render(event.PrepNotes) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String(), selectedLine
+}
+
+// eventStatus describes where a meeting stands: cancelled, tentative, on now, over, or how soon
+// it starts
+func eventStatus(event *db.Event, now time.Time) string {
+	warnStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	nowStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("196"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	switch {
+	case event.Status == "cancelled":
+		return dimStyle.Render("cancelled")
+	case !event.EndTS.After(now):
+		return dimStyle.Render("done")
+	case !event.StartTS.After(now):
+		return nowStyle.Render("● now")
+	}
+
+	status := ""
+	if until := event.StartTS.Sub(now); until < time.Hour {
+		status = nowStyle.Render(fmt.Sprintf("in %dm", int(until.Minutes())+1))
+	} else if until < 12*time.Hour {
+		status = dimStyle.Render(fmt.Sprintf("in %dh%02dm", int(until.Hours()), int(until.Minutes())%60))
+	}
+	switch {
+	case event.Response == "declined":
+		status = strings.TrimSpace(status + " " + dimStyle.Render("declined"))
+	case event.Response == "needsAction":
+		status = strings.TrimSpace(status + " " + warnStyle.Render("not answered"))
+	case event.Status == "tentative" || event.Response == "tentative":
+		status = strings.TrimSpace(status + " " + warnStyle.Render("tentative"))
+	}
+	return status
+}
+
+// prepStatus says whether prep notes are ready for a meeting
+func prepStatus(event *db.Event) string {
+	switch {
+	case event.PrepNotes != "":
+		return "📝 prep ready (enter)"
+	case event.PrepChecked:
+		return "no prep needed"
+	default:
+		return "no prep yet"
+	}
+}
+
+// formatAttendees lists the first few attendees and counts the rest
+func formatAttendees(attendees []string) string {
+	if len(attendees) == 0 {
+		return ""
+	}
+	shown := attendees
+	if len(shown) > agendaAttendeesShown {
+		shown = shown[:agendaAttendeesShown]
+	}
+	text := strings.Join(shown, ", ")
+	if more := len(attendees) - len(shown); more > 0 {
+		text += fmt.Sprintf(" +%d", more)
+	}
+	return text
+}
+
+func (m AgendaModel) View() string {
+	if !m.ready {
+		return "Initializing..."
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(0, 1)
+
+	if m.decline != nil {
+		return m.renderDecline()
+	}
+
+	help := "↑/↓: navigate | enter: prep notes | o: join | a/t/d: accept/tentative/decline | D: decline with note | w: week | r: refresh"
+	if m.message != "" {
+		help = m.message
+	}
+	return m.viewport.View() + "\n" + helpStyle.Render(help)
+}
+
+// renderDecline shows the note drafted to decline a meeting, for approval
+func (m AgendaModel) renderDecline() string {
+	d := m.decline
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(0, 2)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+
+	b.WriteString(headerStyle.Render("✉️  Decline "+d.event.Title) + "\n")
+	to := d.event.Organizer
+	if to == "" {
+		to = "the organizer"
+	}
+	b.WriteString(dimStyle.Render(fmt.Sprintf("%s · note to %s", d.event.StartTS.Format("Mon Jan 2 15:04"), to)) + "\n\n")
+
+	if d.err != nil {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Padding(0, 2)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", d.err)) + "\n\n")
+	}
+
+	switch {
+	case d.drafting:
+		b.WriteString(dimStyle.Render("Drafting a note...") + "\n")
+		b.WriteString(helpStyle.Render("esc: cancel"))
+	case d.note != "":
+		noteStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("252")).
+			Padding(0, 2).
+			Width(max(m.viewport.Width-4, 20))
+		b.WriteString(noteStyle.Render(d.note) + "\n")
+		b.WriteString(helpStyle.Render("enter/y: decline with this note | r: redraft | esc/n: cancel"))
+	default:
+		b.WriteString(helpStyle.Render("r: try again | esc: cancel"))
+	}
+	return b.String()
+}
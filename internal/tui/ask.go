@@ -0,0 +1,199 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// AskModel is a chat-style tab for asking questions about mail and tasks
+type AskModel struct {
+	planner   *planner.Planner
+	apiClient *APIClient
+	textInput textinput.Model
+	history   []*planner.Answer
+	asking    bool
+	err       error
+	viewport  viewport.Model
+	ready     bool
+}
+
+type answerLoadedMsg struct {
+	answer *planner.Answer
+	err    error
+}
+
+func NewAskModel(plannerService *planner.Planner, apiClient *APIClient) AskModel {
+	ti := textinput.New()
+	ti.Placeholder = "What did Sarah ask me about the Q3 report?"
+	ti.CharLimit = 500
+
+	return AskModel{
+		planner:   plannerService,
+		apiClient: apiClient,
+		textInput: ti,
+		viewport:  viewport.New(80, 20),
+	}
+}
+
+// SetSize updates the viewport dimensions
+func (m *AskModel) SetSize(width, height int) {
+	m.viewport.Width = width
+	m.viewport.Height = height - 3 // Room for the input line
+	m.textInput.Width = width - 10
+	m.ready = true
+}
+
+// IsInInputMode reports whether a question is being typed
+func (m AskModel) IsInInputMode() bool {
+	return m.textInput.Focused()
+}
+
+func (m AskModel) ask(question string) tea.Cmd {
+	return func() tea.Msg {
+		var answer *planner.Answer
+		var err error
+
+		if m.apiClient != nil {
+			answer, err = m.apiClient.Ask(question)
+		} else {
+			answer, err = m.planner.Ask(context.Background(), question)
+		}
+
+		return answerLoadedMsg{answer: answer, err: err}
+	}
+}
+
+func (m AskModel) Update(msg tea.Msg) (AskModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case answerLoadedMsg:
+		m.asking = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.history = append(m.history, msg.answer)
+		}
+		m.viewport.SetContent(m.renderHistory())
+		m.viewport.GotoBottom()
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.textInput.Focused() {
+			switch msg.String() {
+			case "esc":
+				m.textInput.Blur()
+				return m, nil
+			case "enter":
+				question := strings.TrimSpace(m.textInput.Value())
+				if question == "" || m.asking {
+					return m, nil
+				}
+				m.textInput.SetValue("")
+				m.asking = true
+				m.err = nil
+				return m, m.ask(question)
+			}
+
+			var cmd tea.Cmd
+			m.textInput, cmd = m.textInput.Update(msg)
+			return m, cmd
+		}
+
+		switch msg.String() {
+		case "enter", "i", "/":
+			m.textInput.Focus()
+			return m, textinput.Blink
+		case "x":
+			m.history = nil
+			m.err = nil
+			m.viewport.SetContent(m.renderHistory())
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// renderHistory renders the questions asked so far with their answers and sources
+func (m AskModel) renderHistory() string {
+	questionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("86")).
+		Padding(0, 1)
+
+	answerStyle := lipgloss.NewStyle().
+		Padding(0, 2).
+		Width(m.viewport.Width - 4)
+
+	sourceStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(0, 2)
+
+	if len(m.history) == 0 {
+		return sourceStyle.Render("Ask about your email and tasks, e.g. \"what did Sarah ask me about the Q3 report?\"")
+	}
+
+	var b strings.Builder
+	for _, answer := range m.history {
+		b.WriteString(questionStyle.Render("❯ "+answer.Question) + "\n\n")
+		b.WriteString(answerStyle.Render(answer.Answer) + "\n\n")
+
+		for i, msg := range answer.Messages {
+			b.WriteString(sourceStyle.Render(fmt.Sprintf("[M%d] %s — %s (%s)",
+				i+1, msg.Subject, msg.From, msg.Timestamp.Format("Jan 2"))) + "\n")
+		}
+		for i, task := range answer.Tasks {
+			b.WriteString(sourceStyle.Render(fmt.Sprintf("[T%d] %s (%s)", i+1, task.Title, task.Status)) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func (m AskModel) View() string {
+	if !m.ready {
+		return "Initializing..."
+	}
+
+	var b strings.Builder
+
+	m.viewport.SetContent(m.renderHistory())
+	b.WriteString(m.viewport.View() + "\n")
+
+	inputStyle := lipgloss.NewStyle().
+		Padding(0, 1).
+		Foreground(lipgloss.Color("86"))
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(0, 1)
+
+	switch {
+	case m.asking:
+		b.WriteString(inputStyle.Render("⏳ Searching and thinking...") + "\n")
+	case m.err != nil:
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(0, 1)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n")
+	default:
+		b.WriteString("\n")
+	}
+
+	b.WriteString(inputStyle.Render("Ask: ") + m.textInput.View() + "\n")
+	if m.textInput.Focused() {
+		b.WriteString(helpStyle.Render("enter: ask | esc: stop typing"))
+	} else {
+		b.WriteString(helpStyle.Render("enter/i: type a question | ↑/↓: scroll | x: clear"))
+	}
+
+	return b.String()
+}
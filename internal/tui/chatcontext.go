@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// chatContextMessages is how many of a Chat task's messages its details show
+const chatContextMessages = 8
+
+// renderChatContext shows the Chat conversation a task was extracted from: the thread, or for
+// DMs and unthreaded spaces the messages leading up to it
+func (m *TasksModel) renderChatContext(task *db.Task) string {
+	var b strings.Builder
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("13")).
+		Padding(0, 1)
+	contextStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("250")).
+		Padding(0, 2)
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 2)
+	bodyStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Width(88).
+		Padding(0, 4)
+
+	b.WriteString("\n" + titleStyle.Render("💬 Chat Context:") + "\n")
+
+	var messages []*db.ChatMessage
+	var err error
+	if m.apiClient != nil {
+		messages, err = m.apiClient.GetChatConversation(task.SourceID)
+	} else {
+		messages, err = m.database.GetChatConversation(task.SourceID, chatContextMessages)
+	}
+	if err != nil || len(messages) == 0 {
+		b.WriteString(contextStyle.Render("(No messages found for this conversation)") + "\n")
+		return b.String()
+	}
+
+	if space := messages[0].SpaceName; space != "" {
+		b.WriteString(contextStyle.Render("Space: "+space) + "\n")
+	}
+	if len(messages) > chatContextMessages {
+		b.WriteString(contextStyle.Render(fmt.Sprintf("(Showing last %d of %d messages)", chatContextMessages, len(messages))) + "\n")
+		messages = messages[len(messages)-chatContextMessages:]
+	}
+	b.WriteString("\n")
+
+	for _, msg := range messages {
+		sender := msg.SenderName
+		switch {
+		case msg.FromUser:
+			sender = "You"
+		case sender == "":
+			sender = "Someone"
+		}
+		b.WriteString(headerStyle.Render(fmt.Sprintf("%s • %s", sender, msg.Timestamp.Format("Jan 2, 3:04 PM"))) + "\n")
+
+		text := msg.Text
+		if len(text) > 200 {
+			text = text[:200] + "..."
+		}
+		b.WriteString(bodyStyle.Render(text) + "\n")
+	}
+	return b.String()
+}
@@ -2,50 +2,94 @@ package tui
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/alexrabarts/focus-agent/internal/config"
 	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/llm"
+	"github.com/alexrabarts/focus-agent/internal/planner"
 )
 
 // APIClient wraps HTTP calls to the remote API server
 type APIClient struct {
-	baseURL string
-	authKey string
-	client  *http.Client
+	baseURL   string
+	authKey   string
+	client    *http.Client
+	transport http.RoundTripper // Shared by every client, so remote.ca_cert applies to all calls
 }
 
 // NewAPIClient creates a new API client
 func NewAPIClient(cfg *config.Config) *APIClient {
+	transport, err := remoteTransport(cfg)
+	if err != nil {
+		log.Printf("Failed to load remote.ca_cert, using system CAs: %v", err)
+		transport = http.DefaultTransport
+	}
 	return &APIClient{
 		baseURL: cfg.Remote.URL,
 		authKey: cfg.Remote.AuthKey,
 		client: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: transport,
 		},
+		transport: transport,
 	}
 }
 
+// remoteTransport trusts remote.ca_cert as well as the system CAs, for servers using a
+// self-signed api.tls_cert
+func remoteTransport(cfg *config.Config) (http.RoundTripper, error) {
+	if cfg.Remote.CACert == "" {
+		return http.DefaultTransport, nil
+	}
+
+	pem, err := os.ReadFile(cfg.Remote.CACert)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", cfg.Remote.CACert)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	return transport, nil
+}
+
 // TaskResponse matches the API response structure
 type TaskResponse struct {
-	ID          string  `json:"id"`
-	Source      string  `json:"source"`
-	SourceID    string  `json:"source_id"`
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	DueTS       *string `json:"due_ts,omitempty"`
-	Project     string  `json:"project"`
-	Impact      int     `json:"impact"`
-	Urgency     int     `json:"urgency"`
-	Effort      string  `json:"effort"`
-	Stakeholder string  `json:"stakeholder"`
-	Score       float64 `json:"score"`
-	Status      string  `json:"status"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	ID          string            `json:"id"`
+	Source      string            `json:"source"`
+	SourceID    string            `json:"source_id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	DueTS       *string           `json:"due_ts,omitempty"`
+	Project     string            `json:"project"`
+	Impact      int               `json:"impact"`
+	Urgency     int               `json:"urgency"`
+	Effort      string            `json:"effort"`
+	Stakeholder string            `json:"stakeholder"`
+	Score       float64           `json:"score"`
+	Status      string            `json:"status"`
+	Recurrence  string            `json:"recurrence,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	ClaimedBy   string            `json:"claimed_by,omitempty"`
+	Comments    []*db.TaskComment `json:"comments,omitempty"`
+	CreatedAt   string            `json:"created_at"`
+	UpdatedAt   string            `json:"updated_at"`
 }
 
 // PrioritiesResponse matches the API response structure
@@ -72,6 +116,18 @@ type StatsResponse struct {
 	LastDriveSync     *string `json:"last_drive_sync,omitempty"`
 	LastCalendarSync  *string `json:"last_calendar_sync,omitempty"`
 	LastTasksSync     *string `json:"last_tasks_sync,omitempty"`
+
+	Setup *db.SetupProgress `json:"setup,omitempty"`
+}
+
+// BudgetResponse matches the API response structure
+type BudgetResponse struct {
+	Provider   string  `json:"provider"`
+	MaxTokens  int     `json:"max_tokens"`
+	MaxCost    float64 `json:"max_cost"`
+	UsedTokens int     `json:"used_tokens"`
+	UsedCost   float64 `json:"used_cost"`
+	ResetsAt   string  `json:"resets_at"`
 }
 
 // ThreadResponse matches the API response structure
@@ -81,10 +137,18 @@ type ThreadResponse struct {
 	Summary        string  `json:"summary"`
 	SummaryHash    *string `json:"summary_hash,omitempty"`
 	TaskCount      int     `json:"task_count"`
+	PriorityScore  float64 `json:"priority_score"`
 	NextFollowupTS *string `json:"next_followup_ts,omitempty"`
 	LastSynced     string  `json:"last_synced"`
 }
 
+// DigestResponse matches the API response structure
+type DigestResponse struct {
+	Tasks   []TaskResponse   `json:"tasks"`
+	Threads []ThreadResponse `json:"threads"`
+	Since   string           `json:"since"`
+}
+
 // MessageResponse matches the API response structure
 type MessageResponse struct {
 	ID          string   `json:"id"`
@@ -99,6 +163,14 @@ type MessageResponse struct {
 	Sensitivity string   `json:"sensitivity"`
 }
 
+// AskResponse matches the API response structure
+type AskResponse struct {
+	Question string            `json:"question"`
+	Answer   string            `json:"answer"`
+	Messages []MessageResponse `json:"messages"`
+	Tasks    []TaskResponse    `json:"tasks"`
+}
+
 // QueueItemResponse matches the API response structure
 type QueueItemResponse struct {
 	ThreadID  string `json:"thread_id"`
@@ -109,6 +181,12 @@ type QueueItemResponse struct {
 
 // Helper to make authenticated requests
 func (c *APIClient) doRequest(method, path string, body interface{}) (*http.Response, error) {
+	return c.doRequestWith(c.client, method, path, body)
+}
+
+// doRequestWith makes an authenticated request with the given HTTP client, for calls that
+// need a longer timeout than the default
+func (c *APIClient) doRequestWith(client *http.Client, method, path string, body interface{}) (*http.Response, error) {
 	var reqBody *bytes.Buffer
 	if body != nil {
 		jsonData, err := json.Marshal(body)
@@ -132,7 +210,7 @@ func (c *APIClient) doRequest(method, path string, body interface{}) (*http.Resp
 	req.Header.Set("Authorization", "Bearer "+c.authKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
@@ -165,40 +243,49 @@ func (c *APIClient) GetTasks() ([]*db.Task, error) {
 	// Convert to db.Task
 	result := make([]*db.Task, 0, len(tasks))
 	for _, t := range tasks {
-		var dueTS *time.Time
-		if t.DueTS != nil {
-			parsed, err := time.Parse(time.RFC3339, *t.DueTS)
-			if err == nil {
-				dueTS = &parsed
-			}
-		}
-
-		// Parse timestamps
-		createdAt, _ := time.Parse(time.RFC3339, t.CreatedAt)
-		updatedAt, _ := time.Parse(time.RFC3339, t.UpdatedAt)
-
-		result = append(result, &db.Task{
-			ID:          t.ID,
-			Source:      t.Source,
-			SourceID:    t.SourceID,
-			Title:       t.Title,
-			Description: t.Description,
-			DueTS:       dueTS,
-			Project:     t.Project,
-			Impact:      t.Impact,
-			Urgency:     t.Urgency,
-			Effort:      t.Effort,
-			Stakeholder: t.Stakeholder,
-			Score:       t.Score,
-			Status:      t.Status,
-			CreatedAt:   createdAt,
-			UpdatedAt:   updatedAt,
-		})
+		result = append(result, t.toTask())
 	}
 
 	return result, nil
 }
 
+// toTask converts an API task response to a db.Task
+func (t TaskResponse) toTask() *db.Task {
+	var dueTS *time.Time
+	if t.DueTS != nil {
+		parsed, err := time.Parse(time.RFC3339, *t.DueTS)
+		if err == nil {
+			dueTS = &parsed
+		}
+	}
+
+	// Parse timestamps
+	createdAt, _ := time.Parse(time.RFC3339, t.CreatedAt)
+	updatedAt, _ := time.Parse(time.RFC3339, t.UpdatedAt)
+
+	return &db.Task{
+		ID:          t.ID,
+		Source:      t.Source,
+		SourceID:    t.SourceID,
+		Title:       t.Title,
+		Description: t.Description,
+		DueTS:       dueTS,
+		Project:     t.Project,
+		Impact:      t.Impact,
+		Urgency:     t.Urgency,
+		Effort:      t.Effort,
+		Stakeholder: t.Stakeholder,
+		Score:       t.Score,
+		Status:      t.Status,
+		Recurrence:  t.Recurrence,
+		Tags:        t.Tags,
+		ClaimedBy:   t.ClaimedBy,
+		Comments:    t.Comments,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
+	}
+}
+
 // CompleteTask marks a task as complete via the remote API
 func (c *APIClient) CompleteTask(taskID string) error {
 	path := fmt.Sprintf("/api/tasks/%s/complete", taskID)
@@ -259,6 +346,68 @@ func (c *APIClient) UpdatePriorities(priorities *config.Priorities) error {
 	return nil
 }
 
+// GetScoring fetches the scoring profile in use and the profiles to switch to
+func (c *APIClient) GetScoring() (*planner.Scoring, error) {
+	resp, err := c.doRequest("GET", "/api/scoring", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var scoring planner.Scoring
+	if err := json.NewDecoder(resp.Body).Decode(&scoring); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &scoring, nil
+}
+
+// SetScoringProfile switches the scoring profile, returning the new scoring once every pending
+// task is rescored
+func (c *APIClient) SetScoringProfile(profile string) (*planner.Scoring, error) {
+	// Rescoring every pending task takes longer than the default timeout
+	client := &http.Client{Timeout: 2 * time.Minute, Transport: c.transport}
+	resp, err := c.doRequestWith(client, "PUT", "/api/scoring", map[string]string{"profile": profile})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var scoring planner.Scoring
+	if err := json.NewDecoder(resp.Body).Decode(&scoring); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &scoring, nil
+}
+
+// GetTimezone fetches the timezone briefs are scheduled and due dates are read in
+func (c *APIClient) GetTimezone() (*planner.Timezone, error) {
+	return c.timezoneRequest("GET", nil)
+}
+
+// SetTimezone switches the timezone while travelling; an empty name switches back to the home
+// timezone
+func (c *APIClient) SetTimezone(name string) (*planner.Timezone, error) {
+	if name == "" {
+		return c.timezoneRequest("DELETE", nil)
+	}
+	return c.timezoneRequest("PUT", map[string]string{"timezone": name})
+}
+
+// timezoneRequest makes a request to /api/timezone and decodes the timezone it returns
+func (c *APIClient) timezoneRequest(method string, body interface{}) (*planner.Timezone, error) {
+	resp, err := c.doRequest(method, "/api/timezone", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var timezone planner.Timezone
+	if err := json.NewDecoder(resp.Body).Decode(&timezone); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &timezone, nil
+}
+
 // GetStats fetches database statistics from the remote API
 func (c *APIClient) GetStats() (Stats, error) {
 	resp, err := c.doRequest("GET", "/api/stats", nil)
@@ -283,6 +432,7 @@ func (c *APIClient) GetStats() (Stats, error) {
 		CompletedToday:    statsResp.CompletedToday,
 		HighPriorityTasks: statsResp.HighPriorityTasks,
 		ThreadsNeedingAI:  statsResp.ThreadsNeedingAI,
+		Setup:             statsResp.Setup,
 	}
 
 	// Parse sync times
@@ -314,6 +464,175 @@ func (c *APIClient) GetStats() (Stats, error) {
 	return stats, nil
 }
 
+// GetBudgets fetches today's LLM provider budget usage from the remote API
+func (c *APIClient) GetBudgets() ([]*db.ProviderBudgetStatus, error) {
+	resp, err := c.doRequest("GET", "/api/budget", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var budgets []BudgetResponse
+	if err := json.NewDecoder(resp.Body).Decode(&budgets); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	result := make([]*db.ProviderBudgetStatus, 0, len(budgets))
+	for _, b := range budgets {
+		status := &db.ProviderBudgetStatus{
+			Provider:   b.Provider,
+			MaxTokens:  b.MaxTokens,
+			MaxCost:    b.MaxCost,
+			UsedTokens: b.UsedTokens,
+			UsedCost:   b.UsedCost,
+		}
+		if t, err := time.Parse(time.RFC3339, b.ResetsAt); err == nil {
+			status.ResetsAt = t
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+// GetProviderHealth fetches LLM provider availability and Ollama load state from the remote API
+func (c *APIClient) GetProviderHealth() ([]*llm.ProviderHealth, error) {
+	resp, err := c.doRequest("GET", "/api/llm/health", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var health []*llm.ProviderHealth
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return health, nil
+}
+
+// GetIssues fetches the operations that are currently failing from the remote API
+func (c *APIClient) GetIssues() ([]*db.Issue, error) {
+	resp, err := c.doRequest("GET", "/api/issues", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var issues []*db.Issue
+	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return issues, nil
+}
+
+// GetDeliveries fetches the last week's briefs and reminders and their delivery status from the
+// remote API
+func (c *APIClient) GetDeliveries() ([]*db.OutboxItem, error) {
+	resp, err := c.doRequest("GET", "/api/deliveries", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var items []*db.OutboxItem
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return items, nil
+}
+
+// GetWaitingItems fetches the requests still waiting on a reply from the remote API
+func (c *APIClient) GetWaitingItems() ([]*db.WaitingItem, error) {
+	resp, err := c.doRequest("GET", "/api/waiting", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var items []*db.WaitingItem
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return items, nil
+}
+
+// GetChatConversation fetches the synced Chat messages of a thread, oldest first
+func (c *APIClient) GetChatConversation(thread string) ([]*db.ChatMessage, error) {
+	resp, err := c.doRequest("GET", "/api/chat/messages?thread="+url.QueryEscape(thread), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var messages []*db.ChatMessage
+	if err := json.NewDecoder(resp.Body).Decode(&messages); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return messages, nil
+}
+
+// DismissWaitingItem stops waiting on a request via the remote API
+func (c *APIClient) DismissWaitingItem(messageID string) error {
+	resp, err := c.doRequest("POST", "/api/waiting/"+url.PathEscape(messageID)+"/dismiss", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// GetAgenda fetches today's and tomorrow's events, or the coming week's, from the remote API
+func (c *APIClient) GetAgenda(week bool) ([]*db.Event, error) {
+	path := "/api/agenda"
+	if week {
+		path += "?range=week"
+	}
+	resp, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var events []*db.Event
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return events, nil
+}
+
+// RespondToEvent accepts, tentatively accepts or declines an invite via the remote API
+func (c *APIClient) RespondToEvent(eventID, response, note string) (*db.Event, error) {
+	reqBody := map[string]string{"response": response, "note": note}
+	resp, err := c.doRequest("POST", "/api/agenda/"+url.PathEscape(eventID)+"/respond", reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var event db.Event
+	if err := json.NewDecoder(resp.Body).Decode(&event); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &event, nil
+}
+
+// DraftDeclineNote drafts a note declining a meeting via the remote API
+func (c *APIClient) DraftDeclineNote(eventID string) (string, error) {
+	resp, err := c.doRequest("POST", "/api/agenda/"+url.PathEscape(eventID)+"/decline-note", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Note string `json:"note"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result.Note, nil
+}
+
 // GetThreads fetches threads with summaries from the remote API
 func (c *APIClient) GetThreads() ([]*db.Thread, error) {
 	resp, err := c.doRequest("GET", "/api/threads", nil)
@@ -330,28 +649,34 @@ func (c *APIClient) GetThreads() ([]*db.Thread, error) {
 	// Convert to db.Thread
 	result := make([]*db.Thread, 0, len(threads))
 	for _, t := range threads {
-		var nextFollowupTS *time.Time
-		if t.NextFollowupTS != nil {
-			parsed, err := time.Parse(time.RFC3339, *t.NextFollowupTS)
-			if err == nil {
-				nextFollowupTS = &parsed
-			}
-		}
+		result = append(result, t.toThread())
+	}
 
-		lastSynced, _ := time.Parse(time.RFC3339, t.LastSynced)
+	return result, nil
+}
 
-		result = append(result, &db.Thread{
-			ID:             t.ID,
-			LastHistoryID:  t.LastHistoryID,
-			Summary:        t.Summary,
-			SummaryHash:    t.SummaryHash,
-			TaskCount:      t.TaskCount,
-			NextFollowupTS: nextFollowupTS,
-			LastSynced:     lastSynced,
-		})
+// toThread converts an API thread response to a db.Thread
+func (t ThreadResponse) toThread() *db.Thread {
+	var nextFollowupTS *time.Time
+	if t.NextFollowupTS != nil {
+		parsed, err := time.Parse(time.RFC3339, *t.NextFollowupTS)
+		if err == nil {
+			nextFollowupTS = &parsed
+		}
 	}
 
-	return result, nil
+	lastSynced, _ := time.Parse(time.RFC3339, t.LastSynced)
+
+	return &db.Thread{
+		ID:             t.ID,
+		LastHistoryID:  t.LastHistoryID,
+		Summary:        t.Summary,
+		SummaryHash:    t.SummaryHash,
+		TaskCount:      t.TaskCount,
+		PriorityScore:  t.PriorityScore,
+		NextFollowupTS: nextFollowupTS,
+		LastSynced:     lastSynced,
+	}
 }
 
 // GetThreadByID fetches a single thread by ID from the remote API
@@ -426,6 +751,21 @@ func (c *APIClient) GetThreadMessages(threadID string) ([]*db.Message, error) {
 	return result, nil
 }
 
+// GetSummaryChanges fetches what changed each time a thread was re-summarized from the remote API
+func (c *APIClient) GetSummaryChanges(threadID string) ([]*db.SummaryChange, error) {
+	resp, err := c.doRequest("GET", fmt.Sprintf("/api/threads/%s/summary-changes", threadID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var changes []*db.SummaryChange
+	if err := json.NewDecoder(resp.Body).Decode(&changes); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return changes, nil
+}
+
 // GetQueue fetches threads waiting for AI processing from the remote API
 func (c *APIClient) GetQueue() ([]QueueItem, error) {
 	resp, err := c.doRequest("GET", "/api/queue", nil)
@@ -465,14 +805,103 @@ func (c *APIClient) TriggerProcessing() error {
 	return nil
 }
 
-// SubmitFeedback submits priority feedback for a task via the remote API
-func (c *APIClient) SubmitFeedback(taskID string, vote int, reason string) error {
+// SubmitFeedback submits priority feedback for a task via the remote API, returning its new score
+func (c *APIClient) SubmitFeedback(taskID string, vote int, reason string) (float64, error) {
 	reqBody := map[string]interface{}{
 		"vote":   vote,
 		"reason": reason,
 	}
 
 	resp, err := c.doRequest("POST", fmt.Sprintf("/api/tasks/%s/feedback", taskID), reqBody)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Score float64 `json:"score"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result.Score, nil
+}
+
+// GetProjectTasks fetches the open tasks for a project via the remote API
+func (c *APIClient) GetProjectTasks(project string) ([]*db.Task, error) {
+	resp, err := c.doRequest("GET", fmt.Sprintf("/api/projects/%s/tasks", url.PathEscape(project)), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tasks []TaskResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tasks); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	result := make([]*db.Task, 0, len(tasks))
+	for _, t := range tasks {
+		result = append(result, t.toTask())
+	}
+
+	return result, nil
+}
+
+// CloseProject bulk-completes, re-homes or archives a project's tasks via the remote API
+func (c *APIClient) CloseProject(project, action, targetProject string, taskIDs []string) (*planner.ProjectCloseResult, error) {
+	reqBody := map[string]interface{}{
+		"action":         action,
+		"target_project": targetProject,
+		"task_ids":       taskIDs,
+	}
+
+	resp, err := c.doRequest("POST", fmt.Sprintf("/api/projects/%s/close", url.PathEscape(project)), reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result planner.ProjectCloseResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// GetDigest fetches the weekly digest of low-priority tasks and FYI threads
+func (c *APIClient) GetDigest() ([]*db.Task, []*db.Thread, error) {
+	resp, err := c.doRequest("GET", "/api/digest", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	var digest DigestResponse
+	if err := json.NewDecoder(resp.Body).Decode(&digest); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	tasks := make([]*db.Task, 0, len(digest.Tasks))
+	for _, t := range digest.Tasks {
+		tasks = append(tasks, t.toTask())
+	}
+	threads := make([]*db.Thread, 0, len(digest.Threads))
+	for _, t := range digest.Threads {
+		threads = append(threads, t.toThread())
+	}
+
+	return tasks, threads, nil
+}
+
+// ArchiveDigest bulk-archives digest tasks and threads via the remote API
+func (c *APIClient) ArchiveDigest(taskIDs, threadIDs []string) error {
+	reqBody := map[string]interface{}{
+		"task_ids":   taskIDs,
+		"thread_ids": threadIDs,
+	}
+
+	resp, err := c.doRequest("POST", "/api/digest/archive", reqBody)
 	if err != nil {
 		return err
 	}
@@ -480,3 +909,264 @@ func (c *APIClient) SubmitFeedback(taskID string, vote int, reason string) error
 
 	return nil
 }
+
+// Search searches tasks, threads, messages, events and Drive documents via the remote API,
+// optionally only those of the given kinds
+func (c *APIClient) Search(query string, kinds ...string) ([]*db.SearchResult, error) {
+	path := "/api/search?q=" + url.QueryEscape(query)
+	if len(kinds) > 0 {
+		path += "&types=" + url.QueryEscape(strings.Join(kinds, ","))
+	}
+	resp, err := c.doRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var results []*db.SearchResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return results, nil
+}
+
+// Ask asks the remote agent a question about mail and tasks
+func (c *APIClient) Ask(question string) (*planner.Answer, error) {
+	// Retrieval plus an LLM call takes longer than the default timeout
+	client := &http.Client{Timeout: 2 * time.Minute, Transport: c.transport}
+	resp, err := c.doRequestWith(client, "POST", "/api/ask", map[string]string{"question": question})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var askResp AskResponse
+	if err := json.NewDecoder(resp.Body).Decode(&askResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	answer := &planner.Answer{
+		Question: askResp.Question,
+		Answer:   askResp.Answer,
+	}
+	for _, m := range askResp.Messages {
+		timestamp, _ := time.Parse(time.RFC3339, m.Timestamp)
+		answer.Messages = append(answer.Messages, &db.Message{
+			ID:        m.ID,
+			ThreadID:  m.ThreadID,
+			From:      m.From,
+			Subject:   m.Subject,
+			Snippet:   m.Snippet,
+			Timestamp: timestamp,
+		})
+	}
+	for _, t := range askResp.Tasks {
+		answer.Tasks = append(answer.Tasks, t.toTask())
+	}
+
+	return answer, nil
+}
+
+// FocusResponse matches the API response structure
+type FocusResponse struct {
+	Active    bool    `json:"active"`
+	StartedAt *string `json:"started_at,omitempty"`
+	EndsAt    *string `json:"ends_at,omitempty"`
+}
+
+func (f FocusResponse) toSession() *db.FocusSession {
+	if !f.Active || f.StartedAt == nil || f.EndsAt == nil {
+		return nil
+	}
+	startedAt, _ := time.Parse(time.RFC3339, *f.StartedAt)
+	endsAt, _ := time.Parse(time.RFC3339, *f.EndsAt)
+	return &db.FocusSession{StartedAt: startedAt, EndsAt: endsAt}
+}
+
+// FocusSummaryResponse matches the API response structure
+type FocusSummaryResponse struct {
+	StartedAt string `json:"started_at"`
+	EndedAt   string `json:"ended_at"`
+	Threads   []struct {
+		ThreadID string `json:"thread_id"`
+		Subject  string `json:"subject"`
+		From     string `json:"from"`
+		Messages int    `json:"messages"`
+	} `json:"threads"`
+	Tasks         []TaskResponse `json:"tasks"`
+	Notifications map[string]int `json:"notifications"`
+}
+
+// GetFocus fetches the active focus session (nil if focus mode is off) from the remote API
+func (c *APIClient) GetFocus() (*db.FocusSession, error) {
+	resp, err := c.doRequest("GET", "/api/focus", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var focus FocusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&focus); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return focus.toSession(), nil
+}
+
+// StartFocus starts a focus session via the remote API
+func (c *APIClient) StartFocus(minutes int) (*db.FocusSession, error) {
+	resp, err := c.doRequest("POST", "/api/focus", map[string]int{"minutes": minutes})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var focus FocusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&focus); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return focus.toSession(), nil
+}
+
+// StopFocus ends the focus session via the remote API and returns its exit summary
+func (c *APIClient) StopFocus() (*db.FocusSummary, error) {
+	resp, err := c.doRequest("DELETE", "/api/focus", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var summaryResp FocusSummaryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&summaryResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	startedAt, _ := time.Parse(time.RFC3339, summaryResp.StartedAt)
+	endedAt, _ := time.Parse(time.RFC3339, summaryResp.EndedAt)
+	summary := &db.FocusSummary{
+		Session:       &db.FocusSession{StartedAt: startedAt, EndsAt: endedAt, EndedAt: &endedAt},
+		Notifications: summaryResp.Notifications,
+	}
+	for _, t := range summaryResp.Threads {
+		summary.Threads = append(summary.Threads, &db.FocusThread{
+			ThreadID: t.ThreadID,
+			Subject:  t.Subject,
+			From:     t.From,
+			Messages: t.Messages,
+		})
+	}
+	for _, t := range summaryResp.Tasks {
+		summary.Tasks = append(summary.Tasks, t.toTask())
+	}
+
+	return summary, nil
+}
+
+// WorkResponse matches the API response structure
+type WorkResponse struct {
+	Active  bool            `json:"active"`
+	Session *db.WorkSession `json:"session,omitempty"`
+}
+
+// workRequest calls one of the work timer endpoints and returns the session in its response
+func (c *APIClient) workRequest(method, path string, body interface{}) (*db.WorkSession, error) {
+	resp, err := c.doRequest(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var work WorkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&work); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return work.Session, nil
+}
+
+// GetWorkSession fetches the running work session (nil if no task is being timed) from the
+// remote API
+func (c *APIClient) GetWorkSession() (*db.WorkSession, error) {
+	return c.workRequest("GET", "/api/work", nil)
+}
+
+// StartWork starts timing work on a task via the remote API
+func (c *APIClient) StartWork(taskID string) (*db.WorkSession, error) {
+	return c.workRequest("POST", "/api/work", map[string]string{"task_id": taskID})
+}
+
+// StopWork stops the work timer via the remote API and returns the finished session
+func (c *APIClient) StopWork() (*db.WorkSession, error) {
+	return c.workRequest("DELETE", "/api/work", nil)
+}
+
+// InterruptWork counts an interruption against the running work session via the remote API
+func (c *APIClient) InterruptWork() (*db.WorkSession, error) {
+	return c.workRequest("POST", "/api/work/interrupt", nil)
+}
+
+// GetEffortStats fetches tracked time against estimated effort from the remote API
+func (c *APIClient) GetEffortStats() (*planner.EffortStats, error) {
+	resp, err := c.doRequest("GET", "/api/work/stats", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var stats planner.EffortStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &stats, nil
+}
+
+// GetPersonas fetches the names of the drafting personas from the remote API
+func (c *APIClient) GetPersonas() ([]string, error) {
+	resp, err := c.doRequest("GET", "/api/personas", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var personas []struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&personas); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	names := make([]string, 0, len(personas))
+	for _, persona := range personas {
+		names = append(names, persona.Name)
+	}
+	return names, nil
+}
+
+// DraftReply drafts a reply to a thread in a persona via the remote API, for review before it's
+// saved. An empty persona uses the recipient's default.
+func (c *APIClient) DraftReply(threadID, persona string) (*planner.ReplyDraft, error) {
+	// Drafting is an LLM call, which takes longer than the default timeout
+	client := &http.Client{Timeout: 2 * time.Minute, Transport: c.transport}
+	resp, err := c.doRequestWith(client, "POST", fmt.Sprintf("/api/threads/%s/draft", threadID), map[string]interface{}{"persona": persona, "review": true})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var draft planner.ReplyDraft
+	if err := json.NewDecoder(resp.Body).Decode(&draft); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &draft, nil
+}
+
+// SaveReply saves a reviewed reply to a thread to Gmail Drafts, or sends it, via the remote API
+func (c *APIClient) SaveReply(threadID, to, body string, send bool) error {
+	resp, err := c.doRequest("POST", fmt.Sprintf("/api/threads/%s/reply", threadID), map[string]interface{}{
+		"to":   to,
+		"body": body,
+		"send": send,
+	})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
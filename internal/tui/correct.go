@@ -0,0 +1,199 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// fieldEditor holds the state of the single-field prompt for correcting an extracted due date
+// (d), impact (i) or stakeholder (w)
+type fieldEditor struct {
+	task   *db.Task
+	field  string // db.CorrectionDue, db.CorrectionImpact or db.CorrectionStakeholder
+	input  textinput.Model
+	saving bool
+	err    error
+}
+
+type taskCorrectedMsg struct {
+	task   *db.Task
+	field  string
+	before planner.TaskInput
+	err    error
+}
+
+// IsCorrecting reports whether the tasks view is prompting for a corrected task field
+func (m TasksModel) IsCorrecting() bool {
+	return m.fieldEditor != nil
+}
+
+// startFieldEditor opens the prompt for one field, filled in with the extracted value
+func (m *TasksModel) startFieldEditor(task *db.Task, field string) tea.Cmd {
+	ti := textinput.New()
+	ti.CharLimit = 100
+	ti.Width = 50
+
+	switch field {
+	case db.CorrectionDue:
+		ti.Placeholder = "YYYY-MM-DD, YYYY-MM-DD HH:MM or Mar 14 (empty clears it)"
+		if task.DueTS != nil {
+			ti.SetValue(task.DueTS.Format("2006-01-02 15:04"))
+		}
+	case db.CorrectionImpact:
+		ti.Placeholder = "1-5"
+		ti.CharLimit = 1
+		if task.Impact > 0 {
+			ti.SetValue(strconv.Itoa(task.Impact))
+		}
+	case db.CorrectionStakeholder:
+		ti.Placeholder = "Who is this for? (empty clears it)"
+		ti.SetValue(task.Stakeholder)
+	}
+	ti.CursorEnd()
+	ti.Focus()
+
+	m.fieldEditor = &fieldEditor{task: task, field: field, input: ti}
+	return textinput.Blink
+}
+
+func (m *TasksModel) updateFieldEditor(msg tea.KeyMsg) (*TasksModel, tea.Cmd) {
+	e := m.fieldEditor
+	if e.saving {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.fieldEditor = nil
+		return m, nil
+	case "enter":
+		value, err := e.value(time.Now())
+		if err != nil {
+			e.err = err
+			return m, nil
+		}
+		e.saving = true
+		e.err = nil
+		return m, m.correctTask(e.task, e.field, value)
+	}
+
+	var cmd tea.Cmd
+	e.input, cmd = e.input.Update(msg)
+	return m, cmd
+}
+
+// value reads the prompt into the value the planner expects for the field
+func (e *fieldEditor) value(now time.Time) (string, error) {
+	value := strings.TrimSpace(e.input.Value())
+	switch e.field {
+	case db.CorrectionDue:
+		if value == "" {
+			return "", nil
+		}
+		due, err := parseDateInput(value, now, dueHour)
+		if err != nil {
+			return "", err
+		}
+		return due.Format(time.RFC3339), nil
+	case db.CorrectionImpact:
+		if n, err := strconv.Atoi(value); err != nil || n < 1 || n > 5 {
+			return "", fmt.Errorf("impact must be a number from 1 to 5")
+		}
+	}
+	return value, nil
+}
+
+func (m TasksModel) correctTask(task *db.Task, field, value string) tea.Cmd {
+	before := planner.TaskInputFrom(task)
+	return func() tea.Msg {
+		var corrected *db.Task
+		var err error
+		if m.apiClient != nil {
+			corrected, err = m.apiClient.CorrectTask(task.ID, field, value)
+		} else {
+			corrected, err = m.planner.CorrectTaskField(context.Background(), task.ID, field, value)
+		}
+		return taskCorrectedMsg{task: corrected, field: field, before: before, err: err}
+	}
+}
+
+// handleTaskCorrected closes the prompt and reloads tasks, reopening the corrected task
+func (m *TasksModel) handleTaskCorrected(msg taskCorrectedMsg) tea.Cmd {
+	if msg.err != nil {
+		if m.fieldEditor != nil {
+			m.fieldEditor.saving = false
+			m.fieldEditor.err = msg.err
+		}
+		return nil
+	}
+
+	m.fieldEditor = nil
+	before := msg.before
+	m.pushUndo(undoOp{kind: undoEdit, taskID: msg.task.ID, title: msg.task.Title, before: &before})
+	m.feedbackMessage = fmt.Sprintf("✓ Corrected %s: %s", msg.field, msg.task.Title)
+	if m.selectedTask != nil {
+		m.openTaskID = msg.task.ID
+	}
+	m.loading = true
+	return m.fetchTasks()
+}
+
+func (m *TasksModel) renderFieldEditor() string {
+	e := m.fieldEditor
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	b.WriteString(headerStyle.Render(fmt.Sprintf("✏️  Correct %s: %s", e.field, e.task.Title)) + "\n\n")
+
+	inputStyle := lipgloss.NewStyle().Padding(0, 2)
+	b.WriteString(inputStyle.Render(e.input.View()) + "\n")
+
+	if e.err != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(1, 1, 0, 1)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", e.err)) + "\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+
+	if e.saving {
+		b.WriteString(helpStyle.Render("Saving..."))
+		return b.String()
+	}
+
+	b.WriteString(helpStyle.Render("enter: save correction | esc: cancel"))
+	return b.String()
+}
+
+// CorrectTask corrects one extracted task field via the remote API
+func (c *APIClient) CorrectTask(taskID, field, value string) (*db.Task, error) {
+	body := map[string]string{"field": field, "value": value}
+	resp, err := c.doRequest("POST", "/api/tasks/"+url.PathEscape(taskID)+"/correct", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var task TaskResponse
+	if err := json.NewDecoder(resp.Body).Decode(&task); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return task.toTask(), nil
+}
@@ -0,0 +1,408 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// DelegateModel is the TUI an assistant sees when connecting with the delegate token
+// (remote.delegate): only tasks tagged for delegation, which they can claim, comment on and
+// complete
+type DelegateModel struct {
+	apiClient  *APIClient
+	config     *config.Config
+	tasks      []*db.Task
+	cursor     int
+	selected   *db.Task // Task shown in detail, if any
+	commenting bool
+	input      textinput.Model
+	loading    bool
+	saving     bool
+	err        error
+	message    string
+	width      int
+}
+
+type delegateTasksLoadedMsg struct {
+	tasks []*db.Task
+	err   error
+}
+
+type delegateActionMsg struct {
+	task   *db.Task
+	action string
+	err    error
+}
+
+func NewDelegateModel(cfg *config.Config) DelegateModel {
+	ti := textinput.New()
+	ti.Placeholder = "Leave a comment..."
+	ti.CharLimit = 500
+	ti.Width = 70
+
+	return DelegateModel{
+		apiClient: NewAPIClient(cfg),
+		config:    cfg,
+		input:     ti,
+		loading:   true,
+	}
+}
+
+func (m DelegateModel) Init() tea.Cmd {
+	return tea.Batch(m.fetchTasks(), tick(m.config))
+}
+
+func (m DelegateModel) fetchTasks() tea.Cmd {
+	return func() tea.Msg {
+		tasks, err := m.apiClient.GetDelegatedTasks()
+		return delegateTasksLoadedMsg{tasks: tasks, err: err}
+	}
+}
+
+func (m DelegateModel) runAction(taskID, action string, body interface{}) tea.Cmd {
+	return func() tea.Msg {
+		task, err := m.apiClient.DelegateTaskAction(taskID, action, body)
+		return delegateActionMsg{task: task, action: action, err: err}
+	}
+}
+
+// current returns the task the keys act on: the one in detail, or the one under the cursor
+func (m DelegateModel) current() *db.Task {
+	if m.selected != nil {
+		return m.selected
+	}
+	if m.cursor < len(m.tasks) {
+		return m.tasks[m.cursor]
+	}
+	return nil
+}
+
+func (m DelegateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
+
+	case tickMsg:
+		// Pick up tasks newly tagged for delegation, and the owner's changes
+		if m.commenting || m.saving {
+			return m, tick(m.config)
+		}
+		return m, tea.Batch(m.fetchTasks(), tick(m.config))
+
+	case delegateTasksLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.tasks = msg.tasks
+		if m.cursor >= len(m.tasks) {
+			m.cursor = max(len(m.tasks)-1, 0)
+		}
+		if m.selected != nil {
+			// Refresh the task in detail, or drop it if it's no longer delegated
+			id := m.selected.ID
+			m.selected = nil
+			for _, task := range m.tasks {
+				if task.ID == id {
+					m.selected = task
+				}
+			}
+		}
+		return m, nil
+
+	case delegateActionMsg:
+		m.saving = false
+		if msg.err != nil {
+			m.message = fmt.Sprintf("❌ Failed to %s: %v", msg.action, msg.err)
+			return m, nil
+		}
+		switch msg.action {
+		case "claim":
+			m.message = "✓ Claimed: " + msg.task.Title
+		case "unclaim":
+			m.message = "✓ Handed back: " + msg.task.Title
+		case "comment":
+			m.message = "✓ Commented on: " + msg.task.Title
+		case "complete":
+			m.message = "✓ Completed: " + msg.task.Title
+			m.selected = nil
+		}
+		return m, m.fetchTasks()
+
+	case tea.KeyMsg:
+		if m.commenting {
+			switch msg.String() {
+			case "esc":
+				m.commenting = false
+				m.input.Blur()
+				return m, nil
+			case "enter":
+				body := strings.TrimSpace(m.input.Value())
+				m.commenting = false
+				m.input.Blur()
+				task := m.current()
+				if body == "" || task == nil {
+					return m, nil
+				}
+				m.saving = true
+				return m, m.runAction(task.ID, "comment", map[string]string{"body": body})
+			}
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
+
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "q", "esc":
+			if m.selected != nil {
+				m.selected = nil
+				return m, nil
+			}
+			if msg.String() == "q" {
+				return m, tea.Quit
+			}
+		case "up", "k":
+			if m.selected == nil && m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.selected == nil && m.cursor < len(m.tasks)-1 {
+				m.cursor++
+			}
+		case "enter":
+			if m.selected == nil && m.cursor < len(m.tasks) {
+				m.selected = m.tasks[m.cursor]
+			}
+		case "r":
+			m.loading = true
+			return m, m.fetchTasks()
+		}
+
+		task := m.current()
+		if task == nil || m.saving {
+			return m, nil
+		}
+		switch msg.String() {
+		case "a":
+			// Claim the task, or hand it back
+			m.saving = true
+			if task.ClaimedBy != "" {
+				return m, m.runAction(task.ID, "unclaim", nil)
+			}
+			return m, m.runAction(task.ID, "claim", nil)
+		case "m":
+			m.commenting = true
+			m.input.SetValue("")
+			m.input.Focus()
+			return m, textinput.Blink
+		case "c":
+			m.saving = true
+			return m, m.runAction(task.ID, "complete", nil)
+		}
+	}
+
+	return m, nil
+}
+
+func (m DelegateModel) View() string {
+	var b strings.Builder
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("86")).
+		Padding(0, 1)
+	b.WriteString(titleStyle.Render("Focus Agent — Delegated Tasks") + "\n\n")
+
+	switch {
+	case m.loading && m.tasks == nil:
+		b.WriteString("Loading tasks...\n")
+	case m.err != nil:
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(0, 1)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n")
+	case m.selected != nil:
+		b.WriteString(m.renderDetail())
+	default:
+		b.WriteString(m.renderList())
+	}
+
+	if m.commenting {
+		b.WriteString("\n  " + m.input.View() + "\n")
+	}
+
+	if m.message != "" {
+		messageStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("42")).
+			Padding(1, 0, 0, 1)
+		b.WriteString(messageStyle.Render(m.message) + "\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+	help := "↑/↓: navigate | enter: details | a: claim/hand back | m: comment | c: complete | r: refresh | q: quit"
+	switch {
+	case m.commenting:
+		help = "enter: post comment | esc: cancel"
+	case m.selected != nil:
+		help = "a: claim/hand back | m: comment | c: complete | esc/q: back"
+	}
+	b.WriteString(helpStyle.Render(help))
+
+	return b.String()
+}
+
+func (m DelegateModel) renderList() string {
+	if len(m.tasks) == 0 {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")).
+			Padding(0, 1)
+		return emptyStyle.Render("Nothing delegated right now.") + "\n"
+	}
+
+	var b strings.Builder
+	itemStyle := lipgloss.NewStyle().Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("236")).
+		Padding(0, 2)
+
+	for i, task := range m.tasks {
+		cursor := "  "
+		style := itemStyle
+		if i == m.cursor {
+			cursor = "→ "
+			style = selectedStyle
+		}
+
+		title := task.Title
+		if len(title) > 55 {
+			title = title[:52] + "..."
+		}
+		line := fmt.Sprintf("%s%d. %s", cursor, i+1, title)
+		if task.DueTS != nil {
+			line += " - due " + task.DueTS.Format("Mon Jan 2")
+		}
+		if task.ClaimedBy != "" {
+			line += " 🤝 " + task.ClaimedBy
+		}
+		if len(task.Comments) > 0 {
+			line += fmt.Sprintf(" 💬 %d", len(task.Comments))
+		}
+		b.WriteString(style.Render(line) + "\n")
+	}
+	return b.String()
+}
+
+func (m DelegateModel) renderDetail() string {
+	task := m.selected
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1).
+		Width(90)
+	b.WriteString(headerStyle.Render(task.Title) + "\n\n")
+
+	infoStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Padding(0, 2)
+	if task.Project != "" {
+		b.WriteString(infoStyle.Render("Project: "+task.Project) + "\n")
+	}
+	if task.DueTS != nil {
+		b.WriteString(infoStyle.Render("Due: "+task.DueTS.Format("Mon, Jan 2, 2006 15:04")) + "\n")
+	}
+	claimed := "nobody yet"
+	if task.ClaimedBy != "" {
+		claimed = task.ClaimedBy
+	}
+	b.WriteString(infoStyle.Render("Claimed by: "+claimed) + "\n")
+
+	if task.Description != "" {
+		descStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("252")).
+			Width(90).
+			Padding(1, 2, 0, 2)
+		b.WriteString(descStyle.Render(task.Description) + "\n")
+	}
+
+	if len(task.Comments) > 0 {
+		titleStyle := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("208")).
+			Padding(1, 1, 0, 1)
+		b.WriteString(titleStyle.Render("💬 Comments:") + "\n")
+		b.WriteString(renderTaskComments(task.Comments))
+	}
+
+	return b.String()
+}
+
+// renderTaskComments lists delegation comments, oldest first
+func renderTaskComments(comments []*db.TaskComment) string {
+	var b strings.Builder
+	authorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		PaddingLeft(2)
+	bodyStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Width(90).
+		PaddingLeft(4)
+
+	for _, comment := range comments {
+		author := comment.Author
+		if author == db.OwnerAuthor {
+			author = "You"
+		}
+		b.WriteString(authorStyle.Render(fmt.Sprintf("%s, %s", author, comment.CreatedAt.Format("Jan 2 15:04"))) + "\n")
+		b.WriteString(bodyStyle.Render(comment.Body) + "\n")
+	}
+	return b.String()
+}
+
+// GetDelegatedTasks fetches the open tasks tagged for delegation from the remote API
+func (c *APIClient) GetDelegatedTasks() ([]*db.Task, error) {
+	resp, err := c.doRequest("GET", "/api/delegate/tasks", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tasks []TaskResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tasks); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	result := make([]*db.Task, 0, len(tasks))
+	for _, t := range tasks {
+		result = append(result, t.toTask())
+	}
+	return result, nil
+}
+
+// DelegateTaskAction claims, unclaims, comments on or completes a delegated task via the
+// remote API
+func (c *APIClient) DelegateTaskAction(taskID, action string, body interface{}) (*db.Task, error) {
+	resp, err := c.doRequest("POST", "/api/delegate/tasks/"+url.PathEscape(taskID)+"/"+action, body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var task TaskResponse
+	if err := json.NewDecoder(resp.Body).Decode(&task); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return task.toTask(), nil
+}
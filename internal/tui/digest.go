@@ -0,0 +1,185 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// digestReview holds the state of the weekly "everything else" digest view
+type digestReview struct {
+	tasks     []*db.Task
+	threads   []*db.Thread
+	loading   bool
+	archiving bool
+	offset    int
+	err       error
+}
+
+type digestLoadedMsg struct {
+	tasks   []*db.Task
+	threads []*db.Thread
+	err     error
+}
+
+type digestArchivedMsg struct {
+	tasks   int
+	threads int
+	err     error
+}
+
+// IsInDigest reports whether the tasks view is showing the weekly digest
+func (m TasksModel) IsInDigest() bool {
+	return m.digest != nil
+}
+
+// startDigest opens the digest view and loads its contents
+func (m *TasksModel) startDigest() tea.Cmd {
+	m.digest = &digestReview{loading: true}
+	return m.fetchDigest()
+}
+
+func (m TasksModel) fetchDigest() tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			tasks, threads, err := m.apiClient.GetDigest()
+			return digestLoadedMsg{tasks: tasks, threads: threads, err: err}
+		}
+
+		digest, err := m.planner.BuildDigest()
+		if err != nil {
+			return digestLoadedMsg{err: err}
+		}
+		return digestLoadedMsg{tasks: digest.Tasks, threads: digest.Threads}
+	}
+}
+
+func (m TasksModel) archiveDigest(taskIDs, threadIDs []string) tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			err := m.apiClient.ArchiveDigest(taskIDs, threadIDs)
+			return digestArchivedMsg{tasks: len(taskIDs), threads: len(threadIDs), err: err}
+		}
+
+		result, err := m.planner.ArchiveDigest(taskIDs, threadIDs)
+		if err != nil {
+			return digestArchivedMsg{err: err}
+		}
+		return digestArchivedMsg{tasks: result.TasksArchived, threads: result.ThreadsArchived}
+	}
+}
+
+func (m *TasksModel) updateDigest(msg tea.KeyMsg) (*TasksModel, tea.Cmd) {
+	d := m.digest
+
+	switch msg.String() {
+	case "esc", "q":
+		m.digest = nil
+	case "up", "k":
+		if d.offset > 0 {
+			d.offset--
+		}
+	case "down", "j":
+		if d.offset < len(d.tasks)+len(d.threads)-1 {
+			d.offset++
+		}
+	case "A":
+		// One key archives everything shown in the digest
+		if d.loading || d.archiving || len(d.tasks)+len(d.threads) == 0 {
+			return m, nil
+		}
+		taskIDs := make([]string, 0, len(d.tasks))
+		for _, task := range d.tasks {
+			taskIDs = append(taskIDs, task.ID)
+		}
+		threadIDs := make([]string, 0, len(d.threads))
+		for _, thread := range d.threads {
+			threadIDs = append(threadIDs, thread.ID)
+		}
+		d.archiving = true
+		return m, m.archiveDigest(taskIDs, threadIDs)
+	}
+
+	return m, nil
+}
+
+func (m *TasksModel) renderDigest() string {
+	d := m.digest
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+
+	b.WriteString(headerStyle.Render("📥 Everything Else - weekly digest") + "\n\n")
+
+	if d.loading {
+		b.WriteString("  Loading digest...\n")
+		return b.String()
+	}
+
+	if d.err != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(0, 1)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", d.err)) + "\n\n")
+	}
+
+	if len(d.tasks) == 0 && len(d.threads) == 0 {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")).
+			Padding(0, 1)
+		b.WriteString(emptyStyle.Render("Nothing low-priority has piled up.") + "\n")
+	}
+
+	// Flatten both sections so j/k scrolls through one list
+	var lines []string
+	if len(d.tasks) > 0 {
+		lines = append(lines, fmt.Sprintf("Low-priority tasks (%d)", len(d.tasks)))
+		for _, task := range d.tasks {
+			title := task.Title
+			if len(title) > 60 {
+				title = title[:57] + "..."
+			}
+			lines = append(lines, fmt.Sprintf("  • %s - Score: %.0f%%", title, task.Score))
+		}
+	}
+	if len(d.threads) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, fmt.Sprintf("FYI threads (%d)", len(d.threads)))
+		for _, thread := range d.threads {
+			summary := thread.Summary
+			if len(summary) > 70 {
+				summary = summary[:67] + "..."
+			}
+			lines = append(lines, fmt.Sprintf("  • %s", summary))
+		}
+	}
+
+	lineStyle := lipgloss.NewStyle().Padding(0, 1)
+	start := d.offset
+	if start > len(lines) {
+		start = len(lines)
+	}
+	for _, line := range lines[start:] {
+		b.WriteString(lineStyle.Render(line) + "\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+
+	if d.archiving {
+		b.WriteString(helpStyle.Render("Archiving..."))
+		return b.String()
+	}
+
+	b.WriteString(helpStyle.Render("A: archive all | j/k: scroll | esc: back"))
+	return b.String()
+}
@@ -0,0 +1,223 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// replyDraft holds the state of drafting a reply to the selected thread: picking a persona,
+// then editing the draft the LLM wrote before saving it to Gmail Drafts or sending it
+type replyDraft struct {
+	threadID    string
+	personas    []string // The first entry, "", is the recipient's default
+	cursor      int
+	drafting    bool
+	draft       *planner.ReplyDraft
+	editor      textarea.Model // The draft, as the user edits it
+	confirmSend bool           // Asking whether to send the reply
+	saving      bool
+	done        string // What happened to the reply, once saved or sent
+	err         error
+}
+
+type personasLoadedMsg struct {
+	personas []string
+	err      error
+}
+
+type replyDraftedMsg struct {
+	draft *planner.ReplyDraft
+	err   error
+}
+
+type replySavedMsg struct {
+	sent bool
+	err  error
+}
+
+// startDraft opens the persona picker for the selected thread
+func (m *ThreadsModel) startDraft() tea.Cmd {
+	m.draft = &replyDraft{threadID: m.selectedThread.ID}
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			personas, err := m.apiClient.GetPersonas()
+			return personasLoadedMsg{personas: personas, err: err}
+		}
+		if m.planner == nil {
+			return personasLoadedMsg{err: fmt.Errorf("drafting needs the planner")}
+		}
+		return personasLoadedMsg{personas: m.planner.DraftingPersonas()}
+	}
+}
+
+func (m ThreadsModel) draftReply(threadID, persona string) tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			draft, err := m.apiClient.DraftReply(threadID, persona)
+			return replyDraftedMsg{draft: draft, err: err}
+		}
+		draft, err := m.planner.DraftThreadReply(context.Background(), threadID, "", persona, false)
+		return replyDraftedMsg{draft: draft, err: err}
+	}
+}
+
+// editDraft opens a drafted reply for editing
+func (m *ThreadsModel) editDraft(draft *planner.ReplyDraft) {
+	editor := textarea.New()
+	editor.ShowLineNumbers = false
+	editor.CharLimit = 0
+	editor.SetWidth(max(m.viewport.Width-4, 20))
+	editor.SetHeight(max(m.viewport.Height-10, 5))
+	editor.SetValue(draft.Text)
+	editor.Focus()
+
+	m.draft.draft = draft
+	m.draft.editor = editor
+}
+
+// saveReply saves the edited reply to Gmail Drafts, or sends it
+func (m ThreadsModel) saveReply(send bool) tea.Cmd {
+	d := m.draft
+	threadID, to, body := d.threadID, d.draft.To, d.editor.Value()
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			return replySavedMsg{sent: send, err: m.apiClient.SaveReply(threadID, to, body, send)}
+		}
+		err := m.planner.SaveThreadReply(context.Background(), threadID, to, body, send)
+		return replySavedMsg{sent: send, err: err}
+	}
+}
+
+func (m ThreadsModel) updateDraft(msg tea.KeyMsg) (ThreadsModel, tea.Cmd) {
+	d := m.draft
+
+	if d.draft != nil && d.done == "" {
+		return m.updateDraftEditor(msg)
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.draft = nil
+	case "up", "k":
+		if d.cursor > 0 {
+			d.cursor--
+		}
+	case "down", "j":
+		if d.cursor < len(d.personas)-1 {
+			d.cursor++
+		}
+	case "enter":
+		if d.draft == nil && !d.drafting && d.cursor < len(d.personas) {
+			d.drafting = true
+			d.err = nil
+			return m, m.draftReply(d.threadID, d.personas[d.cursor])
+		}
+	}
+	return m, nil
+}
+
+// updateDraftEditor handles keys while the drafted reply is being edited: most go to the editor,
+// ctrl+s saves it to Gmail Drafts and ctrl+x sends it once confirmed
+func (m ThreadsModel) updateDraftEditor(msg tea.KeyMsg) (ThreadsModel, tea.Cmd) {
+	d := m.draft
+	if d.saving {
+		return m, nil
+	}
+
+	if d.confirmSend {
+		d.confirmSend = false
+		if msg.String() == "y" {
+			d.saving, d.err = true, nil
+			return m, m.saveReply(true)
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.draft = nil
+		return m, nil
+	case "ctrl+s":
+		d.saving, d.err = true, nil
+		return m, m.saveReply(false)
+	case "ctrl+x":
+		if strings.TrimSpace(d.editor.Value()) != "" {
+			d.confirmSend = true
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	d.editor, cmd = d.editor.Update(msg)
+	return m, cmd
+}
+
+func (m ThreadsModel) renderDraft() string {
+	d := m.draft
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(0, 2)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+
+	b.WriteString(headerStyle.Render("✉️  Draft Reply") + "\n\n")
+
+	if d.err != nil {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Padding(0, 2)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", d.err)) + "\n\n")
+	}
+
+	switch {
+	case d.done != "":
+		b.WriteString(dimStyle.Render(d.done) + "\n")
+		b.WriteString(helpStyle.Render("esc/q: back"))
+	case d.draft != nil:
+		to := d.draft.To
+		if to == "" {
+			to = "unknown recipient"
+		}
+		b.WriteString(dimStyle.Render(fmt.Sprintf("To: %s | Persona: %s", to, d.draft.Persona)) + "\n\n")
+		b.WriteString(lipgloss.NewStyle().Padding(0, 2).Render(d.editor.View()) + "\n")
+		switch {
+		case d.saving:
+			b.WriteString(helpStyle.Render("Saving..."))
+		case d.confirmSend:
+			b.WriteString(helpStyle.Render(fmt.Sprintf("Send this reply to %s now? y: send | any other key: keep editing", to)))
+		default:
+			b.WriteString(helpStyle.Render("ctrl+s: save to Gmail Drafts | ctrl+x: send | esc: discard"))
+		}
+	case d.drafting:
+		b.WriteString(dimStyle.Render("Drafting...") + "\n")
+	case d.personas == nil:
+		b.WriteString(dimStyle.Render("Loading personas...") + "\n")
+	default:
+		b.WriteString(dimStyle.Render("Choose a persona:") + "\n\n")
+		for i, persona := range d.personas {
+			label := persona
+			if label == "" {
+				label = "recipient's default"
+			}
+			cursor := "  "
+			if i == d.cursor {
+				cursor = "→ "
+			}
+			b.WriteString(fmt.Sprintf("  %s%s\n", cursor, label))
+		}
+		b.WriteString(helpStyle.Render("↑/↓: choose | enter: draft | esc/q: cancel"))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ServerEvent is an event pushed by the remote API's /api/events stream
+type ServerEvent struct {
+	ID   int64                  `json:"id"`
+	Type string                 `json:"type"`
+	Time time.Time              `json:"time"`
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// serverEventMsg delivers a pushed event to the model
+type serverEventMsg ServerEvent
+
+// StreamEvents follows /api/events and sends each event to out, reconnecting until ctx is cancelled.
+// Events are dropped if out is full; the next auto-refresh catches up.
+func (c *APIClient) StreamEvents(ctx context.Context, out chan<- ServerEvent) {
+	// The stream stays open indefinitely, so it can't share the request timeout
+	client := &http.Client{Transport: c.transport}
+	var lastID int64
+
+	for {
+		err := c.readEvents(ctx, client, &lastID, out)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			// Older servers don't have the endpoint; auto-refresh still works
+			if strings.Contains(err.Error(), "404") {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// readEvents reads a single SSE connection until it closes
+func (c *APIClient) readEvents(ctx context.Context, client *http.Client, lastID *int64, out chan<- ServerEvent) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/events", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.authKey)
+	req.Header.Set("Accept", "text/event-stream")
+	if *lastID > 0 {
+		req.Header.Set("Last-Event-ID", fmt.Sprintf("%d", *lastID))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("event stream returned %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+
+		var event ServerEvent
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+			continue
+		}
+		*lastID = event.ID
+
+		select {
+		case out <- event:
+		default:
+		}
+	}
+	return scanner.Err()
+}
+
+// waitForServerEvent returns a command that waits for the next pushed event
+func waitForServerEvent(ch <-chan ServerEvent) tea.Cmd {
+	if ch == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		event, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return serverEventMsg(event)
+	}
+}
@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+type focusLoadedMsg struct {
+	session *db.FocusSession
+	err     error
+}
+
+type focusEndedMsg struct {
+	summary *db.FocusSummary
+	err     error
+}
+
+func (m Model) fetchFocus() tea.Cmd {
+	return func() tea.Msg {
+		var session *db.FocusSession
+		var err error
+
+		if m.apiClient != nil {
+			session, err = m.apiClient.GetFocus()
+		} else {
+			session, err = m.planner.GetFocusSession()
+		}
+
+		return focusLoadedMsg{session: session, err: err}
+	}
+}
+
+// toggleFocus starts a focus session of the default length, or ends the running one
+func (m Model) toggleFocus() tea.Cmd {
+	active := m.focusSession != nil && m.focusSession.Active(time.Now())
+
+	return func() tea.Msg {
+		if active {
+			var summary *db.FocusSummary
+			var err error
+			if m.apiClient != nil {
+				summary, err = m.apiClient.StopFocus()
+			} else {
+				summary, err = m.planner.StopFocus(context.Background())
+			}
+			return focusEndedMsg{summary: summary, err: err}
+		}
+
+		var session *db.FocusSession
+		var err error
+		if m.apiClient != nil {
+			session, err = m.apiClient.StartFocus(m.config.Focus.DefaultMinutes)
+		} else {
+			session, err = m.planner.StartFocus(m.config.Focus.DefaultMinutes)
+		}
+		return focusLoadedMsg{session: session, err: err}
+	}
+}
+
+// focusStatus returns the footer countdown while focus mode is on
+func (m Model) focusStatus() string {
+	if m.focusSession == nil {
+		return ""
+	}
+
+	remaining := time.Until(m.focusSession.EndsAt)
+	if remaining <= 0 {
+		return "🎧 Focus over"
+	}
+	return fmt.Sprintf("🎧 Focus %d:%02d", int(remaining.Minutes()), int(remaining.Seconds())%60)
+}
+
+// renderFocusSummary renders what arrived during the focus session that just ended
+func (m Model) renderFocusSummary() string {
+	summary := m.focusSummary
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("86")).
+		Padding(0, 1)
+
+	itemStyle := lipgloss.NewStyle().
+		Padding(0, 2)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+
+	var b strings.Builder
+	minutes := int(summary.Session.End().Sub(summary.Session.StartedAt).Minutes())
+	b.WriteString(titleStyle.Render(fmt.Sprintf("🎧 Focus session over (%d min)", minutes)) + "\n\n")
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("📧 Email (%d threads)", len(summary.Threads))) + "\n")
+	for i, thread := range summary.Threads {
+		if i >= 10 {
+			b.WriteString(itemStyle.Render(fmt.Sprintf("... and %d more", len(summary.Threads)-10)) + "\n")
+			break
+		}
+		b.WriteString(itemStyle.Render(fmt.Sprintf("• %s - %s (%d)", thread.Subject, thread.From, thread.Messages)) + "\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("✅ New tasks (%d)", len(summary.Tasks))) + "\n")
+	for i, task := range summary.Tasks {
+		if i >= 10 {
+			b.WriteString(itemStyle.Render(fmt.Sprintf("... and %d more", len(summary.Tasks)-10)) + "\n")
+			break
+		}
+		b.WriteString(itemStyle.Render(fmt.Sprintf("• %s (%.0f%%)", task.Title, task.Score)) + "\n")
+	}
+
+	if len(summary.Notifications) > 0 {
+		var held []string
+		for kind, count := range summary.Notifications {
+			held = append(held, fmt.Sprintf("%d %s", count, strings.ReplaceAll(kind, "_", " ")))
+		}
+		sort.Strings(held)
+		b.WriteString("\n" + itemStyle.Render("Held notifications now being delivered: "+strings.Join(held, ", ")) + "\n")
+	}
+
+	b.WriteString(helpStyle.Render("Press any key to continue"))
+	return b.String()
+}
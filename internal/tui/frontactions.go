@@ -0,0 +1,457 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/front"
+)
+
+// Steps of the Front action menu
+const (
+	frontMenuActions = iota // Picking an action
+	frontMenuAssign         // Entering the teammate to assign to
+	frontMenuComment        // Entering what the comment should say, then approving the draft
+)
+
+// maxTeammatesShown caps the teammates listed while assigning
+const maxTeammatesShown = 8
+
+// frontMenu holds the state of the action menu for the Front conversation linked to the selected
+// thread ("F")
+type frontMenu struct {
+	threadID  string
+	step      int
+	input     textinput.Model // Teammate to assign to, or what the comment should do
+	teammates []front.Teammate
+	comment   string // Drafted comment awaiting approval
+	working   bool
+	err       error
+}
+
+// frontThread is a thread's Front conversation and comments, as fetched from the remote API
+type frontThread struct {
+	metadata *db.FrontMetadata
+	comments []*db.FrontComment
+}
+
+type frontActionMsg struct {
+	threadID string
+	done     string // What was done, for the confirmation
+	err      error
+}
+
+type frontTeammatesMsg struct {
+	teammates []front.Teammate
+	err       error
+}
+
+type frontCommentDraftedMsg struct {
+	threadID string
+	comment  string
+	err      error
+}
+
+// frontThread returns the Front conversation linked to a thread and its comments, or nil when
+// the thread isn't in Front
+func (m ThreadsModel) frontThread(threadID string) (*db.FrontMetadata, []*db.FrontComment) {
+	if m.database != nil {
+		metadata, err := m.database.GetFrontMetadata(threadID)
+		if err != nil {
+			return nil, nil
+		}
+		comments, _ := m.database.GetFrontComments(threadID)
+		return metadata, comments
+	}
+	if m.apiClient == nil {
+		return nil, nil
+	}
+
+	cached, ok := m.frontThreads[threadID]
+	if !ok {
+		metadata, comments, err := m.apiClient.GetFrontThread(threadID)
+		if err == nil {
+			cached = &frontThread{metadata: metadata, comments: comments}
+		}
+		m.frontThreads[threadID] = cached
+	}
+	if cached == nil {
+		return nil, nil
+	}
+	return cached.metadata, cached.comments
+}
+
+// startFrontMenu opens the Front action menu for the selected thread
+func (m *ThreadsModel) startFrontMenu() {
+	input := textinput.New()
+	input.CharLimit = 200
+	input.Width = 50
+	m.frontMenu = &frontMenu{threadID: m.selectedThread.ID, input: input}
+}
+
+// runFrontAction archives, snoozes or assigns the conversation, or posts a comment. arg is the
+// teammate to assign to or the comment.
+func (m ThreadsModel) runFrontAction(threadID, action, arg string) tea.Cmd {
+	return func() tea.Msg {
+		done := map[string]string{
+			"archive": "Archived in Front",
+			"snooze":  "Snoozed in Front",
+			"assign":  "Assigned to " + arg,
+			"comment": "Comment posted to Front",
+		}[action]
+		if action == "assign" && arg == "" {
+			done = "Unassigned in Front"
+		}
+
+		if m.apiClient != nil {
+			err := m.apiClient.FrontAction(threadID, action, arg)
+			return frontActionMsg{threadID: threadID, done: done, err: err}
+		}
+		if m.planner == nil {
+			return frontActionMsg{threadID: threadID, err: fmt.Errorf("Front actions need the planner")}
+		}
+
+		ctx := context.Background()
+		var err error
+		switch action {
+		case "archive":
+			_, err = m.planner.ArchiveFrontConversation(ctx, threadID)
+		case "snooze":
+			var until time.Time
+			if until, err = m.planner.SnoozeFrontConversation(ctx, threadID, 0); err == nil {
+				done += " until " + until.Format("Mon Jan 2 15:04")
+			}
+		case "assign":
+			_, err = m.planner.AssignFrontConversation(ctx, threadID, arg)
+		case "comment":
+			_, err = m.planner.PostFrontComment(ctx, threadID, arg)
+		}
+		return frontActionMsg{threadID: threadID, done: done, err: err}
+	}
+}
+
+func (m ThreadsModel) fetchFrontTeammates() tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			teammates, err := m.apiClient.GetFrontTeammates()
+			return frontTeammatesMsg{teammates: teammates, err: err}
+		}
+		if m.planner == nil {
+			return frontTeammatesMsg{err: fmt.Errorf("Front actions need the planner")}
+		}
+		teammates, err := m.planner.FrontTeammates(context.Background())
+		return frontTeammatesMsg{teammates: teammates, err: err}
+	}
+}
+
+func (m ThreadsModel) draftFrontComment(threadID, goal string) tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			comment, err := m.apiClient.DraftFrontComment(threadID, goal)
+			return frontCommentDraftedMsg{threadID: threadID, comment: comment, err: err}
+		}
+		if m.planner == nil {
+			return frontCommentDraftedMsg{threadID: threadID, err: fmt.Errorf("drafting needs the planner")}
+		}
+		comment, err := m.planner.DraftFrontComment(context.Background(), threadID, goal)
+		return frontCommentDraftedMsg{threadID: threadID, comment: comment, err: err}
+	}
+}
+
+// handleFrontMsg applies the result of a Front action, teammate lookup or comment draft
+func (m ThreadsModel) handleFrontMsg(msg tea.Msg) (ThreadsModel, tea.Cmd) {
+	f := m.frontMenu
+	switch msg := msg.(type) {
+	case frontActionMsg:
+		if f == nil || f.threadID != msg.threadID {
+			return m, nil
+		}
+		f.working = false
+		if msg.err != nil {
+			f.err = msg.err
+			return m, nil
+		}
+		// Refetch the conversation's new state next time it's shown
+		delete(m.frontThreads, msg.threadID)
+		m.frontMenu = nil
+		m.frontMessage = "✓ " + msg.done
+
+	case frontTeammatesMsg:
+		if f != nil {
+			f.teammates = msg.teammates
+			if msg.err != nil {
+				f.err = msg.err
+			}
+		}
+
+	case frontCommentDraftedMsg:
+		if f == nil || f.threadID != msg.threadID {
+			return m, nil
+		}
+		f.working = false
+		f.err = msg.err
+		f.comment = msg.comment
+	}
+	return m, nil
+}
+
+func (m ThreadsModel) updateFrontMenu(msg tea.KeyMsg) (ThreadsModel, tea.Cmd) {
+	f := m.frontMenu
+	if f.working {
+		if msg.String() == "esc" {
+			m.frontMenu = nil
+		}
+		return m, nil
+	}
+
+	switch f.step {
+	case frontMenuActions:
+		switch msg.String() {
+		case "esc", "q", "F":
+			m.frontMenu = nil
+		case "a":
+			f.working, f.err = true, nil
+			return m, m.runFrontAction(f.threadID, "archive", "")
+		case "s":
+			f.working, f.err = true, nil
+			return m, m.runFrontAction(f.threadID, "snooze", "")
+		case "g":
+			f.step, f.err = frontMenuAssign, nil
+			f.input.SetValue("")
+			f.input.Placeholder = "Teammate email, username or name (empty unassigns)"
+			return m, tea.Batch(f.input.Focus(), m.fetchFrontTeammates())
+		case "c":
+			f.step, f.err = frontMenuComment, nil
+			f.input.SetValue("")
+			f.input.Placeholder = "What the comment should do (empty sums up where it stands)"
+			return m, f.input.Focus()
+		}
+		return m, nil
+
+	case frontMenuAssign:
+		switch msg.String() {
+		case "esc":
+			f.step, f.err = frontMenuActions, nil
+			f.input.Blur()
+			return m, nil
+		case "tab":
+			// Complete the first matching teammate
+			if matches := matchTeammates(f.teammates, f.input.Value()); len(matches) > 0 {
+				f.input.SetValue(matches[0].Email)
+				f.input.CursorEnd()
+			}
+			return m, nil
+		case "enter":
+			f.working, f.err = true, nil
+			return m, m.runFrontAction(f.threadID, "assign", strings.TrimSpace(f.input.Value()))
+		}
+
+	case frontMenuComment:
+		// A drafted comment waits for approval
+		if f.comment != "" {
+			switch msg.String() {
+			case "esc", "n":
+				f.step, f.comment, f.err = frontMenuActions, "", nil
+				f.input.Blur()
+			case "enter", "y":
+				f.working, f.err = true, nil
+				return m, m.runFrontAction(f.threadID, "comment", f.comment)
+			case "r":
+				f.working, f.comment, f.err = true, "", nil
+				return m, m.draftFrontComment(f.threadID, f.input.Value())
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "esc":
+			f.step, f.err = frontMenuActions, nil
+			f.input.Blur()
+			return m, nil
+		case "enter":
+			f.working, f.err = true, nil
+			return m, m.draftFrontComment(f.threadID, f.input.Value())
+		}
+	}
+
+	var cmd tea.Cmd
+	f.input, cmd = f.input.Update(msg)
+	return m, cmd
+}
+
+func (m ThreadsModel) renderFrontMenu() string {
+	f := m.frontMenu
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("141")).
+		Padding(0, 1)
+	dimStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(0, 2)
+	itemStyle := lipgloss.NewStyle().Padding(0, 2)
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+
+	b.WriteString(headerStyle.Render("📋 Front Actions") + "\n")
+	if metadata, _ := m.frontThread(f.threadID); metadata != nil {
+		status := metadata.Status
+		if metadata.AssigneeName != "" {
+			status += " · assigned to " + metadata.AssigneeName
+		}
+		b.WriteString(dimStyle.Render(status) + "\n")
+	}
+	b.WriteString("\n")
+
+	if f.err != nil {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Padding(0, 2)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", f.err)) + "\n\n")
+	}
+
+	switch f.step {
+	case frontMenuActions:
+		b.WriteString(itemStyle.Render("a  Archive") + "\n")
+		b.WriteString(itemStyle.Render("s  Snooze") + "\n")
+		b.WriteString(itemStyle.Render("g  Assign to a teammate") + "\n")
+		b.WriteString(itemStyle.Render("c  Draft an internal comment") + "\n")
+		if f.working {
+			b.WriteString(helpStyle.Render("Working..."))
+		} else {
+			b.WriteString(helpStyle.Render("esc: back"))
+		}
+
+	case frontMenuAssign:
+		b.WriteString(itemStyle.Render("Assign to:") + "\n")
+		b.WriteString(itemStyle.Render(f.input.View()) + "\n")
+		matches := matchTeammates(f.teammates, f.input.Value())
+		for i, teammate := range matches {
+			if i == maxTeammatesShown {
+				b.WriteString(dimStyle.Render(fmt.Sprintf("+%d more", len(matches)-i)) + "\n")
+				break
+			}
+			b.WriteString(dimStyle.Render(fmt.Sprintf("%s  %s", teammate.GetFullName(), teammate.Email)) + "\n")
+		}
+		if f.working {
+			b.WriteString(helpStyle.Render("Assigning..."))
+		} else {
+			b.WriteString(helpStyle.Render("tab: complete | enter: assign | esc: back"))
+		}
+
+	case frontMenuComment:
+		switch {
+		case f.working && f.comment == "":
+			b.WriteString(dimStyle.Render("Drafting a comment...") + "\n")
+		case f.comment != "":
+			commentStyle := lipgloss.NewStyle().
+				Foreground(lipgloss.Color("252")).
+				Padding(0, 2).
+				Width(max(m.viewport.Width-4, 20))
+			b.WriteString(commentStyle.Render(f.comment) + "\n")
+			if f.working {
+				b.WriteString(helpStyle.Render("Posting..."))
+			} else {
+				b.WriteString(helpStyle.Render("enter/y: post | r: redraft | esc/n: discard"))
+			}
+		default:
+			b.WriteString(itemStyle.Render("Comment should:") + "\n")
+			b.WriteString(itemStyle.Render(f.input.View()) + "\n")
+			b.WriteString(helpStyle.Render("enter: draft | esc: back"))
+		}
+	}
+
+	return b.String()
+}
+
+// matchTeammates returns the teammates whose name, email or username contains the query
+func matchTeammates(teammates []front.Teammate, query string) []front.Teammate {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var matches []front.Teammate
+	for _, teammate := range teammates {
+		if query == "" ||
+			strings.Contains(strings.ToLower(teammate.GetFullName()), query) ||
+			strings.Contains(strings.ToLower(teammate.Email), query) ||
+			strings.Contains(strings.ToLower(teammate.Username), query) {
+			matches = append(matches, teammate)
+		}
+	}
+	return matches
+}
+
+// GetFrontThread fetches the Front conversation linked to a thread from the remote API
+func (c *APIClient) GetFrontThread(threadID string) (*db.FrontMetadata, []*db.FrontComment, error) {
+	resp, err := c.doRequest("GET", "/api/front/"+url.PathEscape(threadID), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Metadata *db.FrontMetadata  `json:"metadata"`
+		Comments []*db.FrontComment `json:"comments"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result.Metadata, result.Comments, nil
+}
+
+// FrontAction archives, snoozes or assigns the Front conversation linked to a thread, or posts a
+// comment on it, via the remote API. arg is the teammate to assign to or the comment.
+func (c *APIClient) FrontAction(threadID, action, arg string) error {
+	var body map[string]string
+	switch action {
+	case "assign":
+		body = map[string]string{"teammate": arg}
+	case "comment":
+		body = map[string]string{"body": arg}
+	}
+	resp, err := c.doRequest("POST", "/api/front/"+url.PathEscape(threadID)+"/"+action, body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// GetFrontTeammates fetches the Front teammates conversations can be assigned to
+func (c *APIClient) GetFrontTeammates() ([]front.Teammate, error) {
+	resp, err := c.doRequest("GET", "/api/front/teammates", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var teammates []front.Teammate
+	if err := json.NewDecoder(resp.Body).Decode(&teammates); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return teammates, nil
+}
+
+// DraftFrontComment drafts an internal comment for a thread's Front conversation via the remote
+// API
+func (c *APIClient) DraftFrontComment(threadID, goal string) (string, error) {
+	resp, err := c.doRequest("POST", "/api/front/"+url.PathEscape(threadID)+"/draft-comment", map[string]string{"goal": goal})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Comment string `json:"comment"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result.Comment, nil
+}
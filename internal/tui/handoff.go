@@ -0,0 +1,201 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// handoffPrompt holds the state of the prompt for handing a task off to a colleague (H)
+type handoffPrompt struct {
+	task      *db.Task
+	to        textinput.Model
+	note      textinput.Model
+	onNote    bool           // The note field has focus
+	suggested []*db.Workload // The least loaded people to hand off to
+	pick      int            // Suggestion filled into the To field, or -1
+	sending   bool
+	err       error
+}
+
+type taskHandedOffMsg struct {
+	task *db.Task
+	to   string
+	err  error
+}
+
+// IsHandingOff reports whether the tasks view is prompting for a colleague to hand a task to
+func (m TasksModel) IsHandingOff() bool {
+	return m.handoff != nil
+}
+
+// startHandoff opens the handoff prompt for a task
+func (m *TasksModel) startHandoff(task *db.Task) tea.Cmd {
+	to := textinput.New()
+	to.Placeholder = "colleague@example.com"
+	to.CharLimit = 200
+	to.Width = 50
+	to.Focus()
+
+	note := textinput.New()
+	note.Placeholder = "Optional note for them"
+	note.CharLimit = 500
+	note.Width = 50
+
+	m.handoff = &handoffPrompt{task: task, to: to, note: note, pick: -1}
+	return tea.Batch(textinput.Blink, fetchWorkloads(m.database, m.apiClient))
+}
+
+func (m *TasksModel) updateHandoff(msg tea.KeyMsg) (*TasksModel, tea.Cmd) {
+	h := m.handoff
+	if h.sending {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.handoff = nil
+		return m, nil
+	case "tab", "shift+tab":
+		h.onNote = !h.onNote
+		if h.onNote {
+			h.to.Blur()
+			return m, h.note.Focus()
+		}
+		h.note.Blur()
+		return m, h.to.Focus()
+	case "up", "down":
+		// Cycle the To field through the suggested people
+		if h.onNote || len(h.suggested) == 0 {
+			break
+		}
+		if msg.String() == "down" {
+			h.pick = (h.pick + 1) % len(h.suggested)
+		} else {
+			h.pick = (h.pick - 1 + len(h.suggested)) % len(h.suggested)
+		}
+		h.to.SetValue(h.suggested[h.pick].Email)
+		h.to.CursorEnd()
+		return m, nil
+	case "enter":
+		to := strings.TrimSpace(h.to.Value())
+		if to == "" {
+			h.err = fmt.Errorf("enter the email address to hand off to")
+			return m, nil
+		}
+		h.sending = true
+		h.err = nil
+		return m, m.handoffTask(h.task, to, strings.TrimSpace(h.note.Value()))
+	}
+
+	var cmd tea.Cmd
+	if h.onNote {
+		h.note, cmd = h.note.Update(msg)
+	} else {
+		h.to, cmd = h.to.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m TasksModel) handoffTask(task *db.Task, to, note string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if m.apiClient != nil {
+			err = m.apiClient.HandoffTask(task.ID, to, note)
+		} else {
+			_, err = m.planner.HandoffTask(context.Background(), task.ID, to, note)
+		}
+		return taskHandedOffMsg{task: task, to: to, err: err}
+	}
+}
+
+// handleTaskHandedOff closes the prompt and reloads tasks, which no longer include the task
+func (m *TasksModel) handleTaskHandedOff(msg taskHandedOffMsg) tea.Cmd {
+	if msg.err != nil {
+		if m.handoff != nil {
+			m.handoff.sending = false
+			m.handoff.err = msg.err
+		}
+		return nil
+	}
+
+	m.handoff = nil
+	m.selectedTask = nil
+	m.detailScroll = 0
+	m.feedbackMessage = fmt.Sprintf("✓ Handed off to %s: %s", msg.to, msg.task.Title)
+	m.loading = true
+	return m.fetchTasks()
+}
+
+func (m *TasksModel) renderHandoff() string {
+	h := m.handoff
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	b.WriteString(headerStyle.Render(fmt.Sprintf("📨 Hand off: %s", h.task.Title)) + "\n\n")
+
+	labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Padding(0, 1)
+	inputStyle := lipgloss.NewStyle().Padding(0, 2)
+	b.WriteString(labelStyle.Render("To:") + "\n")
+	b.WriteString(inputStyle.Render(h.to.View()) + "\n")
+	if len(h.suggested) > 0 {
+		hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+		pickedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("86"))
+		b.WriteString(labelStyle.Render(hintStyle.Render("Least loaded:")) + "\n")
+		for i, w := range h.suggested {
+			line := fmt.Sprintf("%s  %d open, answers in %s", workloadLabel(w), w.Delegated, formatResponseTime(w.TurnaroundSecs))
+			if i == h.pick {
+				line = pickedStyle.Render("→ " + line)
+			} else {
+				line = hintStyle.Render("  " + line)
+			}
+			b.WriteString(inputStyle.Render(line) + "\n")
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(labelStyle.Render("Note:") + "\n")
+	b.WriteString(inputStyle.Render(h.note.View()) + "\n")
+
+	if h.err != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(1, 1, 0, 1)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", h.err)) + "\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+
+	if h.sending {
+		b.WriteString(helpStyle.Render("Sending..."))
+		return b.String()
+	}
+
+	help := "tab: switch field | enter: send | esc: cancel"
+	if len(h.suggested) > 0 {
+		help = "↑/↓: pick suggested | " + help
+	}
+	b.WriteString(helpStyle.Render(help))
+	return b.String()
+}
+
+// HandoffTask hands a task off to a colleague via the remote API
+func (c *APIClient) HandoffTask(taskID, to, note string) error {
+	body := map[string]string{"to": to, "note": note}
+	resp, err := c.doRequest("POST", "/api/tasks/"+url.PathEscape(taskID)+"/handoff", body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
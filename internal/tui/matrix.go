@@ -0,0 +1,251 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// Tasks with an urgency or impact (1-5) at or above these are urgent or important in the
+// Eisenhower matrix. Unset values count as 3, as they do when scoring.
+const (
+	matrixUrgentAt    = 4
+	matrixImportantAt = 4
+)
+
+// Eisenhower quadrants, in reading order: the top row is important, the left column urgent
+const (
+	quadrantDo = iota
+	quadrantSchedule
+	quadrantDelegate
+	quadrantDrop
+)
+
+// quadrantInfo is how each quadrant is titled and colored
+var quadrantInfo = [4]struct {
+	title string
+	hint  string
+	color string
+}{
+	quadrantDo:       {"🔥 Do first", "urgent · important", "196"},
+	quadrantSchedule: {"📅 Schedule", "important · not urgent", "39"},
+	quadrantDelegate: {"🤝 Delegate", "urgent · not important", "226"},
+	quadrantDrop:     {"💤 Drop", "neither", "241"},
+}
+
+// eisenhowerMatrix holds the state of the matrix view, which places pending tasks in the four
+// urgent/important quadrants for triage
+type eisenhowerMatrix struct {
+	quadrant int
+	cursors  [4]int // Highlighted task in each quadrant
+}
+
+// taskQuadrant places a task in the matrix by its urgency and impact
+func taskQuadrant(task *db.Task) int {
+	urgency, impact := task.Urgency, task.Impact
+	if urgency == 0 {
+		urgency = 3
+	}
+	if impact == 0 {
+		impact = 3
+	}
+
+	urgent, important := urgency >= matrixUrgentAt, impact >= matrixImportantAt
+	switch {
+	case urgent && important:
+		return quadrantDo
+	case important:
+		return quadrantSchedule
+	case urgent:
+		return quadrantDelegate
+	default:
+		return quadrantDrop
+	}
+}
+
+// IsInMatrix reports whether the tasks view is showing the Eisenhower matrix
+func (m TasksModel) IsInMatrix() bool {
+	return m.matrix != nil
+}
+
+// toggleMatrix switches between the task list and the Eisenhower matrix
+func (m *TasksModel) toggleMatrix() {
+	if m.matrix != nil {
+		m.matrix = nil
+		return
+	}
+	m.matrix = &eisenhowerMatrix{}
+}
+
+// matrixQuadrants sorts the pending tasks shown in the list into quadrants, highest score first
+func (m TasksModel) matrixQuadrants() [4][]*db.Task {
+	var quadrants [4][]*db.Task
+	for _, task := range m.tasks {
+		if task.Status != "pending" {
+			continue
+		}
+		q := taskQuadrant(task)
+		quadrants[q] = append(quadrants[q], task)
+	}
+	for _, tasks := range quadrants {
+		sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Score > tasks[j].Score })
+	}
+	return quadrants
+}
+
+// matrixSelected returns the highlighted task in the selected quadrant, or nil if it's empty
+func (m TasksModel) matrixSelected() *db.Task {
+	tasks := m.matrixQuadrants()[m.matrix.quadrant]
+	if len(tasks) == 0 {
+		return nil
+	}
+	return tasks[min(m.matrix.cursors[m.matrix.quadrant], len(tasks)-1)]
+}
+
+func (m *TasksModel) updateMatrix(msg tea.KeyMsg) (*TasksModel, tea.Cmd) {
+	mx := m.matrix
+	quadrants := m.matrixQuadrants()
+	cursor := min(mx.cursors[mx.quadrant], max(len(quadrants[mx.quadrant])-1, 0))
+
+	switch msg.String() {
+	case "esc", "q", "M":
+		m.matrix = nil
+	case "left", "h", "right", "l":
+		// Across to the other column
+		mx.quadrant ^= 1
+	case "tab":
+		mx.quadrant = (mx.quadrant + 1) % 4
+	case "shift+tab":
+		mx.quadrant = (mx.quadrant + 3) % 4
+	case "up", "k":
+		// Up the quadrant, then into the one above
+		if cursor > 0 {
+			mx.cursors[mx.quadrant] = cursor - 1
+		} else if mx.quadrant >= 2 {
+			mx.quadrant -= 2
+			mx.cursors[mx.quadrant] = max(len(quadrants[mx.quadrant])-1, 0)
+		}
+	case "down", "j":
+		// Down the quadrant, then into the one below
+		if cursor < len(quadrants[mx.quadrant])-1 {
+			mx.cursors[mx.quadrant] = cursor + 1
+		} else if mx.quadrant < 2 {
+			mx.quadrant += 2
+			mx.cursors[mx.quadrant] = 0
+		}
+	case "enter":
+		// Open the task's details; esc comes back to the matrix
+		if task := m.matrixSelected(); task != nil {
+			m.openTask(task.ID)
+		}
+	case "c":
+		if task := m.matrixSelected(); task != nil {
+			m.pushUndo(undoOp{kind: undoComplete, taskID: task.ID, title: task.Title})
+			return m, m.completeTask(task)
+		}
+	case "s":
+		if task := m.matrixSelected(); task != nil {
+			m.startSnooze(task)
+		}
+	case "F":
+		if task := m.matrixSelected(); task != nil {
+			return m, m.toggleWork(task)
+		}
+	case "!":
+		return m, m.interruptWork()
+	case "u":
+		return m, m.popUndo()
+	case "r":
+		m.loading = true
+		return m, m.fetchTasks()
+	}
+	return m, nil
+}
+
+func (m *TasksModel) renderMatrix() string {
+	mx := m.matrix
+	quadrants := m.matrixQuadrants()
+
+	// Two quadrants side by side, each with room for as many tasks as half the height allows
+	width := max((m.viewport.Width-4)/2, 30)
+	rows := max((m.viewport.Height-10)/2-2, 3)
+
+	boxes := make([]string, 4)
+	for q, tasks := range quadrants {
+		info := quadrantInfo[q]
+		border := lipgloss.Color("238")
+		if q == mx.quadrant {
+			border = lipgloss.Color(info.color)
+		}
+		boxStyle := lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(border).
+			Width(width).
+			Padding(0, 1)
+		titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(info.color))
+		dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+		selectedStyle := lipgloss.NewStyle().Background(lipgloss.Color("236"))
+
+		lines := []string{titleStyle.Render(fmt.Sprintf("%s (%d)", info.title, len(tasks))) + " " + dimStyle.Render(info.hint)}
+
+		// Scroll so the highlighted task stays in view
+		cursor := min(mx.cursors[q], max(len(tasks)-1, 0))
+		start := max(cursor-rows+1, 0)
+		end := min(start+rows, len(tasks))
+		for i := start; i < end; i++ {
+			task := tasks[i]
+			title := task.Title
+			if limit := width - 12; len(title) > limit {
+				title = title[:limit-3] + "..."
+			}
+			line := fmt.Sprintf("%3.0f %s", task.Score, title)
+			if q == mx.quadrant && i == cursor {
+				lines = append(lines, selectedStyle.Render("→ "+line))
+			} else {
+				lines = append(lines, "  "+line)
+			}
+		}
+		if len(tasks) == 0 {
+			lines = append(lines, dimStyle.Render("  Nothing here"))
+		}
+		for len(lines) <= rows {
+			lines = append(lines, "")
+		}
+		more := ""
+		if hidden := len(tasks) - (end - start); hidden > 0 {
+			more = dimStyle.Render(fmt.Sprintf("  + %d more", hidden))
+		}
+		lines = append(lines, more)
+		boxes[q] = boxStyle.Render(strings.Join(lines, "\n"))
+	}
+
+	var b strings.Builder
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	b.WriteString(headerStyle.Render("Eisenhower Matrix") + "\n")
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, boxes[quadrantDo], boxes[quadrantSchedule]) + "\n")
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, boxes[quadrantDelegate], boxes[quadrantDrop]) + "\n")
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+	helpText := "h/l: column | j/k: move | tab: next quadrant | enter: view details | c: complete | s: snooze | F: start/stop timer | r: refresh | M/esc: list"
+	if len(m.undo) > 0 {
+		helpText += fmt.Sprintf(" | u: undo %s", m.undo[len(m.undo)-1].label())
+	}
+	b.WriteString(helpStyle.Render(helpText))
+
+	if m.feedbackMessage != "" {
+		messageStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("46")).
+			Padding(0, 1)
+		b.WriteString("\n" + messageStyle.Render(m.feedbackMessage))
+	}
+	return b.String()
+}
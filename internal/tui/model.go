@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -21,9 +22,13 @@ type view int
 
 const (
 	tasksView view = iota
+	agendaView
+	projectsView
+	peopleView
 	prioritiesView
 	queueView
 	threadsView
+	askView
 	statsView
 )
 
@@ -64,12 +69,31 @@ type Model struct {
 	config    *config.Config
 	apiClient *APIClient
 
+	// Events pushed by the remote API (nil in local mode)
+	serverEvents chan ServerEvent
+
 	// Sub-models
 	tasksModel      TasksModel
+	agendaModel     AgendaModel
+	projectsModel   ProjectsModel
+	peopleModel     PeopleModel
 	prioritiesModel PrioritiesModel
 	queueModel      QueueModel
 	statsModel      StatsModel
 	threadsModel    ThreadsModel
+	askModel        AskModel
+
+	// Focus mode
+	focusSession *db.FocusSession // Active session (nil when off)
+	focusSummary *db.FocusSummary // Exit summary shown until dismissed
+
+	// Presence, for routing urgent alerts here while the user is at this terminal
+	lastInput time.Time // Last keypress
+	alert     *tuiAlert // Alert shown until dismissed
+
+	// Profile switching
+	profileSwitcher *profileSwitcher // Open picker (nil when closed)
+	switchTo        string           // Profile chosen in the picker, relaunched after the program exits
 
 	// State
 	lastRefreshTime time.Time
@@ -80,9 +104,11 @@ func NewModel(database *db.DB, clients *google.Clients, llmClient llm.Client, pl
 	// Initialize API client if remote mode is configured
 	var apiClient *APIClient
 	var sched *scheduler.Scheduler
+	var serverEvents chan ServerEvent
 
 	if cfg.Remote.URL != "" {
 		apiClient = NewAPIClient(cfg)
+		serverEvents = make(chan ServerEvent, 16)
 	} else {
 		// For local mode, create a scheduler for processing
 		sched = scheduler.New(database, clients, llmClient, plannerService, frontClient, cfg)
@@ -97,11 +123,17 @@ func NewModel(database *db.DB, clients *google.Clients, llmClient llm.Client, pl
 		front:           frontClient,
 		config:          cfg,
 		apiClient:       apiClient,
+		serverEvents:    serverEvents,
 		tasksModel:      NewTasksModel(database, plannerService, apiClient),
+		agendaModel:     NewAgendaModel(database, apiClient, plannerService),
+		projectsModel:   NewProjectsModel(database, plannerService, apiClient),
+		peopleModel:     NewPeopleModel(database, apiClient, cfg),
 		prioritiesModel: NewPrioritiesModel(cfg, plannerService, apiClient),
 		queueModel:      NewQueueModel(database, apiClient, sched, cfg),
-		statsModel:      NewStatsModel(database, apiClient),
-		threadsModel:    NewThreadsModel(database, apiClient, frontClient),
+		statsModel:      NewStatsModel(database, apiClient, llmClient, plannerService, cfg),
+		threadsModel:    NewThreadsModel(database, plannerService, apiClient),
+		askModel:        NewAskModel(plannerService, apiClient),
+		lastInput:       time.Now(),
 		lastRefreshTime: time.Now(),
 		logBuffer:       logBuffer,
 	}
@@ -113,8 +145,13 @@ func (m Model) Init() tea.Cmd {
 		m.statsModel.fetchStats(),
 		m.queueModel.fetchQueue(),
 		m.threadsModel.fetchThreads(),
+		m.fetchFocus(),
+		m.fetchSession(), // Restore this terminal's last tab and filters
 		tick(m.config),  // Start auto-refresh ticker
 		renderTick(),    // Start render ticker for timestamp updates
+		waitForServerEvent(m.serverEvents),
+		m.sendPresence(),
+		m.presenceTick(),
 	)
 }
 
@@ -134,10 +171,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Update all sub-model viewports
 		m.tasksModel.SetSize(m.width-4, contentHeight)
+		m.agendaModel.SetSize(m.width-4, contentHeight)
+		m.projectsModel.SetSize(m.width-4, contentHeight)
+		m.peopleModel.SetSize(m.width-4, contentHeight)
 		m.threadsModel.SetSize(m.width-4, contentHeight)
 		m.queueModel.SetSize(m.width-4, contentHeight)
 		m.prioritiesModel.SetSize(m.width-4, contentHeight)
 		m.statsModel.SetSize(m.width-4, contentHeight)
+		m.askModel.SetSize(m.width-4, contentHeight)
 
 		return m, nil
 
@@ -147,29 +188,157 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(
 			m.refreshCurrentView(),
 			m.statsModel.fetchStats(), // Always refresh stats for footer queue count
+			m.fetchFocus(),
+			m.tasksModel.fetchWork(),
 			tick(m.config),
 		)
 
+	case presenceTickMsg:
+		return m, tea.Batch(m.sendPresence(), m.presenceTick())
+
+	case serverEventMsg:
+		// Refresh as soon as the server reports new data instead of waiting for the next tick
+		switch msg.Type {
+		case "alert":
+			// An urgent alert followed the user here
+			title, _ := msg.Data["title"].(string)
+			text, _ := msg.Data["text"].(string)
+			m.alert = &tuiAlert{title: title, text: text, receivedAt: msg.Time}
+		case "task.created", "task.delegated", "thread.processed", "sync.completed":
+			m.lastRefreshTime = time.Now()
+			return m, tea.Batch(
+				m.refreshCurrentView(),
+				m.statsModel.fetchStats(),
+				waitForServerEvent(m.serverEvents),
+			)
+		case "task.open":
+			// A Chat brief button asked for a task; load the tasks first if it's new
+			if id, ok := msg.Data["task_id"].(string); ok {
+				m.currentView = tasksView
+				if !m.tasksModel.openTask(id) {
+					m.tasksModel.openTaskID = id
+					return m, tea.Batch(m.tasksModel.fetchTasks(), waitForServerEvent(m.serverEvents))
+				}
+			}
+		}
+		return m, waitForServerEvent(m.serverEvents)
+
+	case openTaskMsg:
+		// The Projects tab asked for a task's details
+		m.currentView = tasksView
+		if !m.tasksModel.openTask(msg.taskID) {
+			m.tasksModel.openTaskID = msg.taskID
+			return m, tea.Batch(m.tasksModel.fetchTasks(), m.saveSession())
+		}
+		return m, m.saveSession()
+
+	case sessionLoadedMsg:
+		if msg.err != nil || msg.session == nil {
+			return m, nil
+		}
+		m.applySession(msg.session)
+		return m, m.refreshCurrentView()
+
+	case focusLoadedMsg:
+		if msg.err == nil {
+			m.focusSession = msg.session
+		}
+		return m, nil
+
+	case workLoadedMsg:
+		// The footer shows the work timer whichever tab is open
+		m.tasksModel.handleWorkLoaded(msg)
+		if msg.action == workStarted {
+			// Starting work turns focus mode on
+			return m, m.fetchFocus()
+		}
+		return m, nil
+
+	case focusEndedMsg:
+		m.focusSession = nil
+		if msg.err == nil && msg.summary != nil {
+			m.focusSummary = msg.summary
+		}
+		return m, nil
+
 	case renderTickMsg:
+		// Clear the countdown once the session runs out; the agent sends the exit summary
+		if m.focusSession != nil && !m.focusSession.Active(time.Now()) {
+			m.focusSession = nil
+		}
 		// Just schedule the next render tick - this triggers a re-render to update the timestamp
 		return m, renderTick()
 
 	case tea.KeyMsg:
+		m.lastInput = time.Now()
+
+		// Any key dismisses an alert, then the focus exit summary
+		if m.alert != nil {
+			m.alert = nil
+			return m, nil
+		}
+		if m.focusSummary != nil {
+			m.focusSummary = nil
+			return m, nil
+		}
+
+		// The profile picker takes all keys while open
+		if m.profileSwitcher != nil {
+			return m.updateProfileSwitcher(msg)
+		}
+
 		// Check if priorities view is in input mode
-		inInputMode := m.currentView == prioritiesView && m.prioritiesModel.IsInInputMode()
+		inInputMode := (m.currentView == prioritiesView && m.prioritiesModel.IsInInputMode()) ||
+			(m.currentView == askView && m.askModel.IsInInputMode())
+
+		// Check if threads view is in detail, search or waiting-on mode
+		inThreadDetail := m.currentView == threadsView && (m.threadsModel.selectedThread != nil || m.threadsModel.IsSearching() || m.threadsModel.IsInWaiting())
 
-		// Check if threads view is in detail mode
-		inThreadDetail := m.currentView == threadsView && m.threadsModel.selectedThread != nil
+		// Check if queue view is in detail mode or showing its rules
+		inQueueDetail := m.currentView == queueView && (m.queueModel.selectedItem != nil || m.queueModel.showRules)
 
-		// Check if queue view is in detail mode
-		inQueueDetail := m.currentView == queueView && m.queueModel.selectedItem != nil
+		// Check if projects view is showing a project's tasks or prompting for a client
+		inProject := m.currentView == projectsView && (m.projectsModel.IsInProject() || m.projectsModel.IsEditingClient())
+
+		// Check if people view is showing a person's details
+		inPerson := m.currentView == peopleView && m.peopleModel.IsInPerson()
+
+		// Check if agenda view is showing a drafted decline note
+		inDecline := m.currentView == agendaView && m.agendaModel.IsDeclining()
+
+		// Check if tasks view is reviewing a project close
+		inProjectReview := m.currentView == tasksView && m.tasksModel.IsInProjectReview()
+
+		// Check if tasks view is showing the weekly digest
+		inDigest := m.currentView == tasksView && m.tasksModel.IsInDigest()
+
+		// Check if tasks view is showing today's time blocks
+		inTimeBlocks := m.currentView == tasksView && m.tasksModel.IsInTimeBlocks()
+
+		// Check if tasks view is showing the Eisenhower matrix
+		inMatrix := m.currentView == tasksView && m.tasksModel.IsInMatrix()
+
+		// Check if tasks view is prompting for tags, a snooze time, a task's fields or a handoff
+		inTaskPrompt := m.currentView == tasksView && (m.tasksModel.IsEditingTags() || m.tasksModel.IsSnoozing() || m.tasksModel.IsEditingTask() || m.tasksModel.IsCorrecting() || m.tasksModel.IsHandingOff())
+
+		// Check if the About tab is prompting for a timezone
+		inTimezonePrompt := m.currentView == statsView && m.statsModel.IsSettingTimezone()
 
 		// Only handle navigation keys when not in input mode or detail view
-		if !inInputMode && !inThreadDetail && !inQueueDetail {
+		if !inInputMode && !inThreadDetail && !inQueueDetail && !inProject && !inPerson && !inDecline && !inProjectReview && !inDigest && !inTimeBlocks && !inMatrix && !inTaskPrompt && !inTimezonePrompt {
 			switch msg.String() {
 			case "ctrl+c", "q":
 				return m, tea.Quit
 
+			case "f":
+				// Toggle focus mode
+				return m, m.toggleFocus()
+
+			case "ctrl+p":
+				// Switch to another profile
+				m.openProfileSwitcher()
+				return m, nil
+
 			case "left", "h":
 				// Move to previous tab
 				if m.currentView > 0 {
@@ -177,7 +346,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					m.currentView = statsView
 				}
-				return m, m.refreshCurrentView()
+				return m, tea.Batch(m.refreshCurrentView(), m.saveSession())
 
 			case "right", "l":
 				// Move to next tab
@@ -186,7 +355,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					m.currentView = tasksView
 				}
-				return m, m.refreshCurrentView()
+				return m, tea.Batch(m.refreshCurrentView(), m.saveSession())
 			}
 		} else {
 			// In input mode, only allow quit
@@ -196,23 +365,51 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	// Always update stats model (for footer), then update current view
-	m.statsModel, _ = m.statsModel.Update(msg)
+	// Always update stats model (for footer), then update current view. Keys only reach it on its
+	// own tab, so its prompt doesn't open from another.
+	var statsCmd tea.Cmd
+	if _, isKey := msg.(tea.KeyMsg); !isKey || m.currentView == statsView {
+		m.statsModel, statsCmd = m.statsModel.Update(msg)
+	}
+	if _, ok := msg.(statsLoadedMsg); ok {
+		m.tasksModel.setup = m.statsModel.stats.Setup
+	}
 
 	var cmd tea.Cmd
 	switch m.currentView {
 	case tasksView:
+		sourceFilter := m.tasksModel.sourceFilter
 		tasksModelPtr, tasksCmd := m.tasksModel.Update(msg)
 		m.tasksModel = *tasksModelPtr
 		cmd = tasksCmd
+		if m.tasksModel.sourceFilter != sourceFilter {
+			cmd = tea.Batch(cmd, m.saveSession())
+		}
+	case agendaView:
+		m.agendaModel, cmd = m.agendaModel.Update(msg)
+	case projectsView:
+		m.projectsModel, cmd = m.projectsModel.Update(msg)
+	case peopleView:
+		m.peopleModel, cmd = m.peopleModel.Update(msg)
 	case prioritiesView:
 		m.prioritiesModel, cmd = m.prioritiesModel.Update(msg)
 	case queueView:
 		m.queueModel, cmd = m.queueModel.Update(msg)
 	case statsView:
 		// Already updated above
+		cmd = statsCmd
 	case threadsView:
 		m.threadsModel, cmd = m.threadsModel.Update(msg)
+	case askView:
+		m.askModel, cmd = m.askModel.Update(msg)
+	}
+
+	// Answers can arrive after switching tabs
+	if _, ok := msg.(answerLoadedMsg); ok && m.currentView != askView {
+		m.askModel, cmd = m.askModel.Update(msg)
+	}
+	if _, ok := msg.(searchResultsMsg); ok && m.currentView != threadsView {
+		m.threadsModel, cmd = m.threadsModel.Update(msg)
 	}
 
 	return m, cmd
@@ -222,6 +419,21 @@ func (m Model) refreshCurrentView() tea.Cmd {
 	switch m.currentView {
 	case tasksView:
 		return m.tasksModel.fetchTasks()
+	case agendaView:
+		return m.agendaModel.fetchAgenda()
+	case projectsView:
+		if m.projectsModel.selected != nil {
+			return m.projectsModel.fetchTasks(m.projectsModel.selected.Name)
+		}
+		return m.projectsModel.fetchProjects()
+	case peopleView:
+		if m.peopleModel.selected != nil {
+			return m.peopleModel.fetchPerson(m.peopleModel.selected.Email)
+		}
+		if m.peopleModel.workload {
+			return fetchWorkloads(m.peopleModel.database, m.peopleModel.apiClient)
+		}
+		return m.peopleModel.fetchPeople()
 	case prioritiesView:
 		return m.prioritiesModel.fetchPriorities()
 	case queueView:
@@ -245,17 +457,33 @@ func (m Model) View() string {
 
 	// Content
 	var content string
-	switch m.currentView {
-	case tasksView:
-		content = m.tasksModel.View()
-	case prioritiesView:
-		content = m.prioritiesModel.View()
-	case queueView:
-		content = m.queueModel.View()
-	case statsView:
-		content = m.statsModel.View()
-	case threadsView:
-		content = m.threadsModel.View()
+	if m.profileSwitcher != nil {
+		content = m.renderProfileSwitcher()
+	} else if m.alert != nil {
+		content = m.renderAlert()
+	} else if m.focusSummary != nil {
+		content = m.renderFocusSummary()
+	} else {
+		switch m.currentView {
+		case tasksView:
+			content = m.tasksModel.View()
+		case agendaView:
+			content = m.agendaModel.View()
+		case projectsView:
+			content = m.projectsModel.View()
+		case peopleView:
+			content = m.peopleModel.View()
+		case prioritiesView:
+			content = m.prioritiesModel.View()
+		case queueView:
+			content = m.queueModel.View()
+		case statsView:
+			content = m.statsModel.View()
+		case threadsView:
+			content = m.threadsModel.View()
+		case askView:
+			content = m.askModel.View()
+		}
 	}
 
 	// Footer
@@ -280,9 +508,12 @@ func (m Model) renderHeader() string {
 		Padding(0, 2)
 
 	title := titleStyle.Render("Focus Agent")
+	if m.config.Profile != "" && m.config.Profile != config.DefaultProfile {
+		title = titleStyle.Render("Focus Agent · " + m.config.Profile)
+	}
 
 	tabs := ""
-	for i, label := range []string{"Tasks", "Priorities", "Queue", "Threads", "About"} {
+	for i, label := range []string{"Tasks", "Agenda", "Projects", "People", "Priorities", "Queue", "Threads", "Ask", "About"} {
 		if view(i) == m.currentView {
 			tabs += activeTabStyle.Render(label)
 		} else {
@@ -307,13 +538,26 @@ func (m Model) renderFooter() string {
 		Foreground(lipgloss.Color("245")).
 		Padding(0, 1)
 
-	footer := "q: quit | ←/→: switch tabs | ↑/↓: navigate | enter: select | c: complete task"
+	footer := "q: quit | ←/→: switch tabs | ↑/↓: navigate | enter: select | c: complete task | f: focus | ctrl+p: profile"
+
+	// Show the focus mode countdown
+	if status := m.focusStatus(); status != "" {
+		footer += " | " + status
+	}
+
+	// And the work timer
+	if status := m.tasksModel.workStatus(); status != "" {
+		footer += " | " + status
+	}
 
 	// Add status information
 	stats := m.statsModel.stats
 	if stats.ThreadsNeedingAI > 0 {
 		footer += fmt.Sprintf(" | 📋 Queue: %d", stats.ThreadsNeedingAI)
 	}
+	if len(stats.Issues) > 0 {
+		footer += fmt.Sprintf(" | ⚠️  Issues: %d (About tab)", len(stats.Issues))
+	}
 
 	// Add last refresh time if auto-refresh is enabled
 	if m.config.TUI.AutoRefreshSeconds > 0 {
@@ -368,10 +612,17 @@ func (m Model) formatLastRefresh() string {
 	}
 }
 
-func Start(database *db.DB, clients *google.Clients, llmClient llm.Client, plannerService *planner.Planner, frontClient *front.Client, cfg *config.Config) error {
+// Start runs the TUI until the user quits. If the user switched profiles it returns the chosen
+// profile, which the caller relaunches with.
+func Start(database *db.DB, clients *google.Clients, llmClient llm.Client, plannerService *planner.Planner, frontClient *front.Client, cfg *config.Config) (string, error) {
 	// Validate remote mode configuration
 	if cfg.Remote.URL != "" && cfg.Remote.AuthKey == "" {
-		return fmt.Errorf("remote mode is configured (url=%s) but auth_key is missing\n\nPlease set FOCUS_AGENT_AUTH_KEY environment variable in ~/.env and restart your shell", cfg.Remote.URL)
+		return "", fmt.Errorf("remote mode is configured (url=%s) but auth_key is missing\n\nPlease set FOCUS_AGENT_AUTH_KEY environment variable in ~/.env and restart your shell", cfg.Remote.URL)
+	}
+	if cfg.Remote.URL != "" {
+		if _, err := remoteTransport(cfg); err != nil {
+			return "", fmt.Errorf("failed to load remote.ca_cert: %w", err)
+		}
 	}
 
 	// Create log buffer to capture background logs
@@ -393,12 +644,35 @@ func Start(database *db.DB, clients *google.Clients, llmClient llm.Client, plann
 		}()
 	}
 
+	// An assistant connecting with the delegate token only gets the delegated tasks
+	if cfg.Remote.Delegate {
+		if cfg.Remote.URL == "" {
+			return "", fmt.Errorf("remote.delegate needs remote.url")
+		}
+		if _, err := tea.NewProgram(NewDelegateModel(cfg), tea.WithAltScreen()).Run(); err != nil {
+			return "", fmt.Errorf("TUI error: %w", err)
+		}
+		return "", nil
+	}
+
 	m := NewModel(database, clients, llmClient, plannerService, frontClient, cfg, logBuffer)
+
+	// Follow the remote event stream for live updates
+	if m.apiClient != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go m.apiClient.StreamEvents(ctx, m.serverEvents)
+	}
+
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
-	if _, err := p.Run(); err != nil {
-		return fmt.Errorf("TUI error: %w", err)
+	final, err := p.Run()
+	if err != nil {
+		return "", fmt.Errorf("TUI error: %w", err)
 	}
 
-	return nil
+	if final, ok := final.(Model); ok {
+		return final.switchTo, nil
+	}
+	return "", nil
 }
@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// setupStep is one item on the first-run checklist, with how to complete it
+type setupStep struct {
+	label string
+	done  bool
+	hint  string
+}
+
+func setupSteps(setup *db.SetupProgress) []setupStep {
+	return []setupStep{
+		{"Connect your Google account", setup.Authenticated, "run focus-agent -auth"},
+		{"Sync your mail, calendar and docs", setup.Synced, "start the agent with focus-agent; it syncs every few minutes"},
+		{"Get your first daily brief", setup.BriefSent, "arrives each morning, or run focus-agent -brief now"},
+		{"Add your priorities", setup.PrioritiesAdded, "Priorities tab, press a to add OKRs, focus areas and key people"},
+	}
+}
+
+// renderSetupChecklist lists the setup steps, with a hint on each one still to do
+func renderSetupChecklist(setup *db.SetupProgress, itemStyle lipgloss.Style) string {
+	doneStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+
+	var b strings.Builder
+	for _, step := range setupSteps(setup) {
+		if step.done {
+			b.WriteString(itemStyle.Render(doneStyle.Render("✓ "+step.label)) + "\n")
+			continue
+		}
+		b.WriteString(itemStyle.Render("○ "+step.label+"  "+hintStyle.Render("→ "+step.hint)) + "\n")
+	}
+	return b.String()
+}
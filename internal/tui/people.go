@@ -0,0 +1,500 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// PeopleModel is the People tab: everyone the user has mail with and how each relationship
+// stands, with a drill-down into a person's recent threads and what's owed both ways, and a
+// workload report of who is waiting on what for delegation decisions
+type PeopleModel struct {
+	database  *db.DB
+	apiClient *APIClient
+	config    *config.Config
+	people    []*db.Person
+	cursor    int
+	loading   bool
+	err       error
+	selected  *db.Person         // Person drilled into, if any
+	history   *db.ContactHistory // Recent threads and open items of the selected person
+	workload  bool               // Showing the workload report instead of the people list
+	workloads []*db.Workload
+	suggested []*db.Workload // Who to delegate to next
+	viewport  viewport.Model
+	ready     bool
+}
+
+type peopleLoadedMsg struct {
+	people []*db.Person
+	err    error
+}
+
+type workloadLoadedMsg struct {
+	workloads []*db.Workload
+	suggested []*db.Workload
+	err       error
+}
+
+type personLoadedMsg struct {
+	email   string
+	person  *db.Person
+	history *db.ContactHistory
+	err     error
+}
+
+func NewPeopleModel(database *db.DB, apiClient *APIClient, cfg *config.Config) PeopleModel {
+	return PeopleModel{
+		database:  database,
+		apiClient: apiClient,
+		config:    cfg,
+		loading:   true,
+		viewport:  viewport.New(80, 20),
+	}
+}
+
+// SetSize updates the viewport dimensions
+func (m *PeopleModel) SetSize(width, height int) {
+	m.viewport.Width = width
+	m.viewport.Height = height
+	m.ready = true
+}
+
+// IsInPerson reports whether the people view is showing one person's details
+func (m PeopleModel) IsInPerson() bool {
+	return m.selected != nil
+}
+
+func (m PeopleModel) fetchPeople() tea.Cmd {
+	return func() tea.Msg {
+		var people []*db.Person
+		var err error
+
+		if m.apiClient != nil {
+			people, err = m.apiClient.GetPeople()
+		} else {
+			people, err = m.database.GetPeople("", 200)
+		}
+
+		return peopleLoadedMsg{people: people, err: err}
+	}
+}
+
+// fetchWorkloads loads everyone's workload and who to delegate to next, for the People tab and
+// the handoff prompt
+func fetchWorkloads(database *db.DB, apiClient *APIClient) tea.Cmd {
+	return func() tea.Msg {
+		if apiClient != nil {
+			workloads, suggested, err := apiClient.GetWorkload()
+			return workloadLoadedMsg{workloads: workloads, suggested: suggested, err: err}
+		}
+
+		workloads, err := database.GetWorkloads()
+		if err != nil {
+			return workloadLoadedMsg{err: err}
+		}
+		return workloadLoadedMsg{workloads: workloads, suggested: db.SuggestDelegates(workloads, db.MaxDelegateSuggestions)}
+	}
+}
+
+func (m PeopleModel) fetchPerson(email string) tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			person, history, err := m.apiClient.GetPerson(email)
+			return personLoadedMsg{email: email, person: person, history: history, err: err}
+		}
+
+		person, err := m.database.GetPerson(email)
+		if err != nil || person == nil {
+			return personLoadedMsg{email: email, err: err}
+		}
+		history, err := m.database.GetContactHistory(email, m.config.Google.UserEmail, 10)
+		return personLoadedMsg{email: email, person: person, history: history, err: err}
+	}
+}
+
+func (m PeopleModel) Update(msg tea.Msg) (PeopleModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case peopleLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.people = msg.people
+		if m.cursor >= len(m.people) {
+			m.cursor = max(len(m.people)-1, 0)
+		}
+		return m, nil
+
+	case workloadLoadedMsg:
+		if !m.workload {
+			return m, nil
+		}
+		m.loading = false
+		m.err = msg.err
+		m.workloads = msg.workloads
+		m.suggested = msg.suggested
+		return m, nil
+
+	case personLoadedMsg:
+		if m.selected == nil || m.selected.Email != msg.email {
+			return m, nil
+		}
+		m.loading = false
+		m.err = msg.err
+		if msg.person != nil {
+			m.selected = msg.person
+		}
+		m.history = msg.history
+		return m, nil
+
+	case tea.KeyMsg:
+		// Drilled into a person: their threads and open items
+		if m.selected != nil {
+			switch msg.String() {
+			case "esc", "q":
+				m.selected = nil
+				m.history = nil
+				m.err = nil
+			case "r":
+				m.loading = true
+				return m, m.fetchPerson(m.selected.Email)
+			}
+			return m, nil
+		}
+
+		// Workload report
+		if m.workload {
+			switch msg.String() {
+			case "esc", "w":
+				m.workload = false
+				m.err = nil
+			case "r":
+				m.loading = true
+				return m, fetchWorkloads(m.database, m.apiClient)
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.people)-1 {
+				m.cursor++
+			}
+		case "w":
+			// Who is waiting on what, for deciding who to delegate to
+			m.workload = true
+			m.loading = true
+			return m, fetchWorkloads(m.database, m.apiClient)
+		case "enter":
+			// Drill into the person's threads
+			if m.cursor < len(m.people) {
+				m.selected = m.people[m.cursor]
+				m.history = nil
+				m.loading = true
+				return m, m.fetchPerson(m.selected.Email)
+			}
+		case "r":
+			m.loading = true
+			return m, m.fetchPeople()
+		}
+	}
+
+	return m, nil
+}
+
+func (m PeopleModel) View() string {
+	if !m.ready {
+		return "Initializing..."
+	}
+
+	if m.loading {
+		return "Loading people..."
+	}
+
+	if m.err != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(1)
+		return errorStyle.Render(fmt.Sprintf("Error: %v", m.err))
+	}
+
+	var content string
+	switch {
+	case m.selected != nil:
+		content = m.renderPerson()
+	case m.workload:
+		content = m.renderWorkload()
+	default:
+		content = m.renderPeople()
+	}
+
+	m.viewport.SetContent(content)
+	return m.viewport.View()
+}
+
+func (m PeopleModel) renderPeople() string {
+	if len(m.people) == 0 {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")).
+			Padding(1)
+		return emptyStyle.Render("No one yet. People are built from synced mail and refreshed every hour.")
+	}
+
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	b.WriteString(headerStyle.Render(fmt.Sprintf("👥 People (%d)", len(m.people))) + "\n\n")
+
+	itemStyle := lipgloss.NewStyle().Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("236")).
+		Padding(0, 2)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	owedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+
+	for i, person := range m.people {
+		cursor := "  "
+		style := itemStyle
+		if i == m.cursor {
+			cursor = "→ "
+			style = selectedStyle
+		}
+
+		name := personLabel(person)
+		if len(name) > 35 {
+			name = name[:32] + "..."
+		}
+		line := fmt.Sprintf("%s%-35s %3d threads", cursor, name, person.Threads)
+		if person.TasksOwed > 0 {
+			line += "  " + owedStyle.Render(fmt.Sprintf("you owe %d", person.TasksOwed))
+		}
+		if person.WaitingOn > 0 {
+			line += "  " + owedStyle.Render(fmt.Sprintf("they owe %d", person.WaitingOn))
+		}
+		line += "  " + hintStyle.Render(formatRelativeTime(person.LastInteraction))
+		b.WriteString(style.Render(line) + "\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+	b.WriteString(helpStyle.Render("↑/↓: navigate | enter: view person | w: workload | r: refresh"))
+
+	return b.String()
+}
+
+func (m PeopleModel) renderWorkload() string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("86")).
+		Padding(1, 1, 0, 1)
+	itemStyle := lipgloss.NewStyle().Padding(0, 2)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	owedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+
+	b.WriteString(headerStyle.Render("📊 Workload") + "\n")
+
+	if len(m.workloads) == 0 {
+		b.WriteString(itemStyle.Render(hintStyle.Render("Nothing delegated or promised yet. Requests you send and tasks you hand off show up here.")) + "\n")
+		b.WriteString(helpStyle.Render("r: refresh | esc/w: back to people"))
+		return b.String()
+	}
+
+	if len(m.suggested) > 0 {
+		b.WriteString(sectionStyle.Render("Delegate to next") + "\n")
+		for _, w := range m.suggested {
+			b.WriteString(itemStyle.Render(fmt.Sprintf("• %s  %s", workloadLabel(w),
+				hintStyle.Render(fmt.Sprintf("%d open, answers in %s", w.Delegated, formatResponseTime(w.TurnaroundSecs))))) + "\n")
+		}
+	}
+
+	b.WriteString(sectionStyle.Render(fmt.Sprintf("Everyone (%d)", len(m.workloads))) + "\n")
+	b.WriteString(itemStyle.Render(hintStyle.Render(fmt.Sprintf("%-35s %9s %9s %9s %10s", "", "delegated", "promised", "you owe", "turnaround"))) + "\n")
+	for _, w := range m.workloads {
+		name := workloadLabel(w)
+		if len(name) > 35 {
+			name = name[:32] + "..."
+		}
+		line := fmt.Sprintf("%-35s %9d %9d %9d %10s", name, w.Delegated, w.Promised, w.TasksOwed, formatResponseTime(w.TurnaroundSecs))
+		if w.Overdue > 0 {
+			line += "  " + owedStyle.Render(fmt.Sprintf("%d overdue", w.Overdue))
+		}
+		b.WriteString(itemStyle.Render(line) + "\n")
+	}
+
+	b.WriteString(helpStyle.Render("r: refresh | esc/w: back to people"))
+	return b.String()
+}
+
+func (m PeopleModel) renderPerson() string {
+	var b strings.Builder
+	person := m.selected
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	sectionStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("86")).
+		Padding(1, 1, 0, 1)
+	itemStyle := lipgloss.NewStyle().Padding(0, 2)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	b.WriteString(headerStyle.Render("👤 "+personLabel(person)) + "\n")
+	if person.Name != "" {
+		b.WriteString(itemStyle.Render(hintStyle.Render(person.Email)) + "\n")
+	}
+	b.WriteString("\n")
+
+	stats := fmt.Sprintf("%d threads, %d messages, last %s", person.Threads, person.Messages, formatRelativeTime(person.LastInteraction))
+	b.WriteString(itemStyle.Render(stats) + "\n")
+	b.WriteString(itemStyle.Render(fmt.Sprintf("You reply in %s, they reply in %s",
+		formatResponseTime(person.MyResponseSecs), formatResponseTime(person.TheirResponseSecs))) + "\n")
+	if person.DraftPersona != "" {
+		b.WriteString(itemStyle.Render(hintStyle.Render("Drafting persona: "+person.DraftPersona)) + "\n")
+	}
+
+	if m.history != nil {
+		if len(m.history.YouOwe) > 0 {
+			b.WriteString(sectionStyle.Render(fmt.Sprintf("You owe (%d)", len(m.history.YouOwe))) + "\n")
+			for _, task := range m.history.YouOwe {
+				b.WriteString(itemStyle.Render("• "+task.Title) + "\n")
+			}
+		}
+
+		if len(m.history.TheyOwe) > 0 {
+			b.WriteString(sectionStyle.Render(fmt.Sprintf("They owe a reply (%d)", len(m.history.TheyOwe))) + "\n")
+			for _, thread := range m.history.TheyOwe {
+				b.WriteString(itemStyle.Render("• "+thread.Subject+"  "+hintStyle.Render("sent "+formatRelativeTime(thread.LastTS))) + "\n")
+			}
+		}
+
+		if len(m.history.Interactions) > 0 {
+			b.WriteString(sectionStyle.Render("Recent threads") + "\n")
+			for _, thread := range m.history.Interactions {
+				subject := thread.Subject
+				if len(subject) > 60 {
+					subject = subject[:57] + "..."
+				}
+				b.WriteString(itemStyle.Render("• "+subject+"  "+hintStyle.Render(formatRelativeTime(thread.LastTS))) + "\n")
+				if thread.Summary != "" {
+					b.WriteString(itemStyle.Render("  "+hintStyle.Render(thread.Summary)) + "\n")
+				}
+			}
+		}
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+	b.WriteString(helpStyle.Render("r: refresh | esc/q: back to people"))
+
+	return b.String()
+}
+
+// personLabel names a person by name when known, otherwise by address
+func personLabel(person *db.Person) string {
+	if person.Name != "" {
+		return person.Name
+	}
+	return person.Email
+}
+
+// workloadLabel names a person in the workload report by name when known, otherwise by address
+func workloadLabel(w *db.Workload) string {
+	if w.Name != "" {
+		return w.Name
+	}
+	return w.Email
+}
+
+// formatResponseTime shows an average reply time, or "n/a" when there's nothing to measure
+func formatResponseTime(secs int64) string {
+	d := time.Duration(secs) * time.Second
+	switch {
+	case secs <= 0:
+		return "n/a"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%.1fh", d.Hours())
+	default:
+		return fmt.Sprintf("%.1fd", d.Hours()/24)
+	}
+}
+
+// GetPeople fetches everyone the user has mail with from the remote API
+func (c *APIClient) GetPeople() ([]*db.Person, error) {
+	resp, err := c.doRequest("GET", "/api/people", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var people []*db.Person
+	if err := json.NewDecoder(resp.Body).Decode(&people); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return people, nil
+}
+
+// GetPerson fetches a person's stats and recent threads from the remote API
+func (c *APIClient) GetPerson(email string) (*db.Person, *db.ContactHistory, error) {
+	resp, err := c.doRequest("GET", "/api/people/"+url.PathEscape(email), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	var person struct {
+		*db.Person
+		History *db.ContactHistory `json:"history"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&person); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return person.Person, person.History, nil
+}
+
+// GetWorkload fetches everyone's workload and who to delegate to next from the remote API
+func (c *APIClient) GetWorkload() ([]*db.Workload, []*db.Workload, error) {
+	resp, err := c.doRequest("GET", "/api/workload", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		People    []*db.Workload `json:"people"`
+		Suggested []*db.Workload `json:"suggested"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result.People, result.Suggested, nil
+}
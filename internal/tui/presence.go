@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+// presenceInterval is how often a remote TUI tells the server when the user last pressed a key
+const presenceInterval = 30 * time.Second
+
+// presenceTickMsg is sent when it's time for the next presence heartbeat
+type presenceTickMsg struct{}
+
+// tuiAlert is an urgent alert the server routed here because the user is at this TUI
+type tuiAlert struct {
+	title      string
+	text       string
+	receivedAt time.Time
+}
+
+// presenceTick schedules the next heartbeat. Only remote TUIs send them, since alerts reach the
+// TUI through the API's event stream.
+func (m Model) presenceTick() tea.Cmd {
+	if m.apiClient == nil {
+		return nil
+	}
+	return tea.Tick(presenceInterval, func(time.Time) tea.Msg {
+		return presenceTickMsg{}
+	})
+}
+
+// sendPresence reports how long the user has been idle at this terminal
+func (m Model) sendPresence() tea.Cmd {
+	if m.apiClient == nil {
+		return nil
+	}
+	idle := time.Since(m.lastInput)
+	clientID := m.config.TUI.ClientID
+
+	return func() tea.Msg {
+		// Older servers don't have the endpoint; alerts then go to the other channels
+		if err := m.apiClient.SendPresence(clientID, idle); err != nil && !strings.Contains(err.Error(), "404") {
+			log.Printf("Failed to send presence: %v", err)
+		}
+		return nil
+	}
+}
+
+// renderAlert shows an alert until any key is pressed
+func (m Model) renderAlert() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("214")).
+		Padding(0, 1)
+
+	textStyle := lipgloss.NewStyle().
+		Padding(0, 2)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("🔔 %s", m.alert.title)) + "\n\n")
+	b.WriteString(textStyle.Render(strings.TrimSpace(m.alert.text)) + "\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("Received %s · press any key to dismiss", m.alert.receivedAt.Format("3:04 PM"))))
+	return b.String()
+}
+
+// SendPresence sends a presence heartbeat to the remote API
+func (c *APIClient) SendPresence(clientID string, idle time.Duration) error {
+	reqBody := map[string]interface{}{
+		"client_id":    clientID,
+		"idle_seconds": int(idle.Seconds()),
+	}
+
+	resp, err := c.doRequest("POST", "/api/presence", reqBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
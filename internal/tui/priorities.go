@@ -1,7 +1,6 @@
 package tui
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -49,6 +48,8 @@ type PrioritiesModel struct {
 	editingIndex       int
 	previousPriorities *config.Priorities
 	message            string
+	scoring            *planner.Scoring // Scoring profile in use, switched with w
+	switchingScoring   bool             // A scoring profile switch is rescoring tasks
 	viewport viewport.Model
 	ready bool
 }
@@ -77,7 +78,7 @@ func NewPrioritiesModel(cfg *config.Config, plannerService *planner.Planner, api
 	return m
 }
 
-// loadPriorities loads priorities from the database via planner or API
+// loadPriorities loads priorities and the scoring profile from the database via planner or API
 func (m *PrioritiesModel) loadPriorities() {
 	if m.apiClient != nil {
 		// Remote mode: fetch from API
@@ -90,11 +91,15 @@ func (m *PrioritiesModel) loadPriorities() {
 		dbPriorities := m.planner.GetPriorities()
 		m.config.Priorities = *dbPriorities
 	}
+
+	if msg, ok := fetchScoring(m.planner, m.apiClient)().(scoringLoadedMsg); ok && msg.err == nil {
+		m.scoring = msg.scoring
+	}
 }
 
 // fetchPriorities returns a command to reload priorities from the database/API
 func (m PrioritiesModel) fetchPriorities() tea.Cmd {
-	return func() tea.Msg {
+	fetch := func() tea.Msg {
 		var priorities *config.Priorities
 		var err error
 
@@ -108,6 +113,7 @@ func (m PrioritiesModel) fetchPriorities() tea.Cmd {
 
 		return prioritiesLoadedMsg{priorities: priorities, err: err}
 	}
+	return tea.Batch(fetch, fetchScoring(m.planner, m.apiClient))
 }
 
 // SetSize updates the viewport dimensions
@@ -133,6 +139,20 @@ func (m PrioritiesModel) Update(msg tea.Msg) (PrioritiesModel, tea.Cmd) {
 			m.config.Priorities = *msg.priorities
 		}
 		return m, nil
+
+	case scoringLoadedMsg:
+		if msg.switched {
+			m.switchingScoring = false
+			if msg.err != nil {
+				m.message = fmt.Sprintf("Error switching scoring profile: %v", msg.err)
+			} else {
+				m.message = fmt.Sprintf("Scoring with the %s profile, tasks rescored", msg.scoring.Profile)
+			}
+		}
+		if msg.err == nil && msg.scoring != nil {
+			m.scoring = msg.scoring
+		}
+		return m, nil
 	}
 
 	// Handle adding mode
@@ -348,6 +368,14 @@ func (m PrioritiesModel) Update(msg tea.Msg) (PrioritiesModel, tea.Cmd) {
 				}
 			}
 
+		case "w":
+			// Switch to the next scoring profile and rescore every task
+			if !m.switchingScoring {
+				m.switchingScoring = true
+				m.message = fmt.Sprintf("Switching to the %s scoring profile (rescoring tasks...)", nextScoringProfile(m.scoring))
+				return m, switchScoring(m.planner, m.apiClient, m.scoring)
+			}
+
 		case "u":
 			// Undo last change
 			if m.previousPriorities != nil {
@@ -548,29 +576,13 @@ func (m PrioritiesModel) saveConfig() error {
 		return m.apiClient.UpdatePriorities(&m.config.Priorities)
 	}
 
-	// Local mode: save to database via planner
+	// Local mode: save to database via planner, which re-evaluates the top tasks in the background
 	if m.planner != nil {
 		if err := m.planner.SavePriorities(&m.config.Priorities); err != nil {
 			return fmt.Errorf("failed to save priorities to database: %w", err)
 		}
 	}
 
-	// Trigger rescore of tasks with new priorities asynchronously
-	// This can take several minutes due to LLM API calls, so don't block the UI
-	if m.planner != nil {
-		go func() {
-			ctx := context.Background()
-			// Recalculate task priorities with updated strategic priorities
-			if err := m.planner.PrioritizeTasks(ctx); err != nil {
-				// Silently fail - will retry on next scheduled run
-				return
-			}
-
-			// Recalculate thread priorities based on updated task scores
-			_ = m.planner.RecalculateThreadPriorities(ctx)
-		}()
-	}
-
 	return nil
 }
 
@@ -617,6 +629,13 @@ func (m PrioritiesModel) View() string {
 		b.WriteString(contentStyle.Render("Press Enter to move, Esc to cancel\n"))
 	}
 
+	// Scoring profile in use
+	scoringStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("245")).
+		Padding(1, 2, 0, 2)
+	b.WriteString("\n")
+	b.WriteString(scoringStyle.Render("⚖️  " + formatScoring(m.scoring)))
+
 	// Status message
 	if m.message != "" {
 		messageStyle := lipgloss.NewStyle().
@@ -631,7 +650,7 @@ func (m PrioritiesModel) View() string {
 		Foreground(lipgloss.Color("241")).
 		Padding(1, 0, 0, 2)
 
-	helpText := "tab: switch sections | enter: edit | a: add | o: reorder | d: delete | u: undo"
+	helpText := "tab: switch sections | enter: edit | a: add | o: reorder | d: delete | u: undo | w: scoring profile"
 	if m.previousPriorities != nil {
 		helpText += " | ↶ Undo available"
 	}
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+)
+
+// profileSwitcher lists the configured profiles so the TUI can relaunch on another one
+type profileSwitcher struct {
+	profiles []string
+	cursor   int
+	err      error
+}
+
+// currentProfile returns the profile the TUI is running for
+func (m Model) currentProfile() string {
+	if m.config.Profile == "" {
+		return config.DefaultProfile
+	}
+	return m.config.Profile
+}
+
+// openProfileSwitcher shows the profile picker with the current profile selected
+func (m *Model) openProfileSwitcher() {
+	profiles, err := config.ListProfiles()
+	switcher := &profileSwitcher{profiles: profiles, err: err}
+	for i, name := range profiles {
+		if name == m.currentProfile() {
+			switcher.cursor = i
+		}
+	}
+	m.profileSwitcher = switcher
+}
+
+// updateProfileSwitcher handles keys while the profile picker is open. Choosing another profile
+// quits the program so Start can hand the profile back to be relaunched.
+func (m Model) updateProfileSwitcher(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.profileSwitcher
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "ctrl+p":
+		m.profileSwitcher = nil
+	case "up", "k":
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case "down", "j":
+		if s.cursor < len(s.profiles)-1 {
+			s.cursor++
+		}
+	case "enter":
+		m.profileSwitcher = nil
+		if s.cursor < len(s.profiles) && s.profiles[s.cursor] != m.currentProfile() {
+			m.switchTo = s.profiles[s.cursor]
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m Model) renderProfileSwitcher() string {
+	s := m.profileSwitcher
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("🗂  Switch Profile"))
+	b.WriteString("\n\n")
+
+	if s.err != nil {
+		b.WriteString(fmt.Sprintf("Error listing profiles: %v\n", s.err))
+	} else if len(s.profiles) == 0 {
+		b.WriteString(dimStyle.Render("No profiles configured. Run focus-agent -profile <name> to create one."))
+		b.WriteString("\n")
+	}
+
+	for i, name := range s.profiles {
+		line := "  " + name
+		if name == m.currentProfile() {
+			line += " (current)"
+		}
+		if i == s.cursor {
+			line = selectedStyle.Render("▶ " + strings.TrimPrefix(line, "  "))
+		}
+		b.WriteString(line + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("enter: switch | esc: cancel"))
+	return b.String()
+}
@@ -0,0 +1,479 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// ProjectsModel is the Projects tab: open tasks rolled up by project, with a drill-down into
+// each project's task list
+type ProjectsModel struct {
+	database   *db.DB
+	planner    *planner.Planner
+	apiClient  *APIClient
+	projects   []*db.ProjectSummary
+	cursor     int
+	loading    bool
+	err        error
+	selected   *db.ProjectSummary // Project drilled into, if any
+	tasks      []*db.Task         // Open tasks of the selected project
+	taskCursor int
+	viewport   viewport.Model
+	ready      bool
+
+	// Client prompt (C) for the project under the cursor
+	editingClient bool
+	clientInput   textinput.Model
+
+	feedback string // Result of the last archive (A)
+}
+
+type projectsLoadedMsg struct {
+	projects []*db.ProjectSummary
+	err      error
+}
+
+type projectListTasksLoadedMsg struct {
+	project string
+	tasks   []*db.Task
+	err     error
+}
+
+type projectBillingSavedMsg struct {
+	err error
+}
+
+type projectArchivedMsg struct {
+	project string
+	count   int
+	err     error
+}
+
+// openTaskMsg asks the Tasks tab to show a task's details
+type openTaskMsg struct {
+	taskID string
+}
+
+func NewProjectsModel(database *db.DB, planner *planner.Planner, apiClient *APIClient) ProjectsModel {
+	return ProjectsModel{
+		database:  database,
+		planner:   planner,
+		apiClient: apiClient,
+		loading:   true,
+		viewport:  viewport.New(80, 20),
+	}
+}
+
+// SetSize updates the viewport dimensions
+func (m *ProjectsModel) SetSize(width, height int) {
+	m.viewport.Width = width
+	m.viewport.Height = height
+	m.ready = true
+}
+
+// IsInProject reports whether the projects view is showing one project's tasks
+func (m ProjectsModel) IsInProject() bool {
+	return m.selected != nil
+}
+
+func (m ProjectsModel) fetchProjects() tea.Cmd {
+	return func() tea.Msg {
+		var projects []*db.ProjectSummary
+		var err error
+
+		if m.apiClient != nil {
+			projects, err = m.apiClient.GetProjects()
+		} else {
+			projects, err = m.database.GetProjectSummaries()
+		}
+
+		return projectsLoadedMsg{projects: projects, err: err}
+	}
+}
+
+// IsEditingClient reports whether the projects view is prompting for a project's client
+func (m ProjectsModel) IsEditingClient() bool {
+	return m.editingClient
+}
+
+// setBilling saves who a project is invoiced to and whether its time is billable
+func (m ProjectsModel) setBilling(project, client string, billable bool) tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			return projectBillingSavedMsg{err: m.apiClient.SetProjectBilling(project, client, billable)}
+		}
+		return projectBillingSavedMsg{err: m.database.SetProjectBilling(project, client, billable)}
+	}
+}
+
+// archiveProject archives every open task of a project, closing it without marking the
+// work as done
+func (m ProjectsModel) archiveProject(project string) tea.Cmd {
+	return func() tea.Msg {
+		var result *planner.ProjectCloseResult
+		var err error
+		if m.apiClient != nil {
+			result, err = m.apiClient.CloseProject(project, planner.ProjectCloseArchive, "", nil)
+		} else {
+			result, err = m.planner.CloseProject(context.Background(), project, planner.ProjectCloseArchive, "", nil)
+		}
+		count := 0
+		if result != nil {
+			count = len(result.TaskIDs)
+		}
+		return projectArchivedMsg{project: project, count: count, err: err}
+	}
+}
+
+func (m ProjectsModel) fetchTasks(project string) tea.Cmd {
+	return func() tea.Msg {
+		var tasks []*db.Task
+		var err error
+
+		if m.apiClient != nil {
+			tasks, err = m.apiClient.GetProjectTasks(project)
+		} else {
+			tasks, err = m.planner.GetProjectTasks(project)
+		}
+
+		return projectListTasksLoadedMsg{project: project, tasks: tasks, err: err}
+	}
+}
+
+func (m ProjectsModel) Update(msg tea.Msg) (ProjectsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case projectsLoadedMsg:
+		m.loading = false
+		m.err = msg.err
+		m.projects = msg.projects
+		if m.cursor >= len(m.projects) {
+			m.cursor = max(len(m.projects)-1, 0)
+		}
+		return m, nil
+
+	case projectBillingSavedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		return m, m.fetchProjects()
+
+	case projectArchivedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.feedback = fmt.Sprintf("✓ Archived %s: %d task(s)", msg.project, msg.count)
+		return m, m.fetchProjects()
+
+	case projectListTasksLoadedMsg:
+		if m.selected == nil || m.selected.Name != msg.project {
+			return m, nil
+		}
+		m.loading = false
+		m.err = msg.err
+		m.tasks = msg.tasks
+		if m.taskCursor >= len(m.tasks) {
+			m.taskCursor = max(len(m.tasks)-1, 0)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		// Prompting for the client of the project under the cursor
+		if m.editingClient {
+			switch msg.String() {
+			case "esc":
+				m.editingClient = false
+				return m, nil
+			case "enter":
+				m.editingClient = false
+				if m.cursor < len(m.projects) {
+					project := m.projects[m.cursor]
+					return m, m.setBilling(project.Name, strings.TrimSpace(m.clientInput.Value()), project.Billable)
+				}
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.clientInput, cmd = m.clientInput.Update(msg)
+			return m, cmd
+		}
+
+		// Drilled into a project: its task list
+		if m.selected != nil {
+			switch msg.String() {
+			case "esc", "q":
+				m.selected = nil
+				m.tasks = nil
+				m.err = nil
+			case "up", "k":
+				if m.taskCursor > 0 {
+					m.taskCursor--
+				}
+			case "down", "j":
+				if m.taskCursor < len(m.tasks)-1 {
+					m.taskCursor++
+				}
+			case "enter":
+				// Show the task on the Tasks tab
+				if m.taskCursor < len(m.tasks) {
+					taskID := m.tasks[m.taskCursor].ID
+					return m, func() tea.Msg { return openTaskMsg{taskID: taskID} }
+				}
+			case "r":
+				m.loading = true
+				return m, m.fetchTasks(m.selected.Name)
+			}
+			return m, nil
+		}
+
+		m.feedback = ""
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.projects)-1 {
+				m.cursor++
+			}
+		case "enter":
+			// Drill into the project's tasks
+			if m.cursor < len(m.projects) {
+				m.selected = m.projects[m.cursor]
+				m.tasks = nil
+				m.taskCursor = 0
+				m.loading = true
+				return m, m.fetchTasks(m.selected.Name)
+			}
+		case "b":
+			// Toggle whether the project's time is billable
+			if m.cursor < len(m.projects) {
+				project := m.projects[m.cursor]
+				return m, m.setBilling(project.Name, project.Client, !project.Billable)
+			}
+		case "C":
+			// Set the client the project is invoiced to
+			if m.cursor < len(m.projects) {
+				ti := textinput.New()
+				ti.Placeholder = "Client (empty clears it)"
+				ti.CharLimit = 100
+				ti.Width = 50
+				ti.SetValue(m.projects[m.cursor].Client)
+				ti.CursorEnd()
+				ti.Focus()
+				m.clientInput = ti
+				m.editingClient = true
+				return m, textinput.Blink
+			}
+		case "A":
+			// Archive the project: its remaining tasks are cancelled rather than completed
+			if m.cursor < len(m.projects) {
+				project := m.projects[m.cursor]
+				return m, m.archiveProject(project.Name)
+			}
+		case "r":
+			m.loading = true
+			return m, m.fetchProjects()
+		}
+	}
+
+	return m, nil
+}
+
+func (m ProjectsModel) View() string {
+	if !m.ready {
+		return "Initializing..."
+	}
+
+	if m.loading {
+		return "Loading projects..."
+	}
+
+	if m.err != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(1)
+		return errorStyle.Render(fmt.Sprintf("Error: %v", m.err))
+	}
+
+	var content string
+	if m.selected != nil {
+		content = m.renderProjectTasks()
+	} else {
+		content = m.renderProjects()
+	}
+
+	m.viewport.SetContent(content)
+	return m.viewport.View()
+}
+
+func (m ProjectsModel) renderProjects() string {
+	if len(m.projects) == 0 {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")).
+			Padding(1)
+		return emptyStyle.Render("No open tasks have a project. Add key projects on the Priorities tab and\nmatching tasks are grouped here.")
+	}
+
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	b.WriteString(headerStyle.Render(fmt.Sprintf("📁 Projects (%d)", len(m.projects))) + "\n\n")
+
+	itemStyle := lipgloss.NewStyle().Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("236")).
+		Padding(0, 2)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	now := time.Now()
+	for i, project := range m.projects {
+		cursor := "  "
+		style := itemStyle
+		if i == m.cursor {
+			cursor = "→ "
+			style = selectedStyle
+		}
+
+		name := project.Name
+		if len(name) > 40 {
+			name = name[:37] + "..."
+		}
+		line := fmt.Sprintf("%s%-40s %3d open  max %3.0f%%  avg %3.0f%%", cursor, name, project.TaskCount, project.MaxScore, project.AvgScore)
+		if project.NextDue != nil {
+			line += "  " + hintStyle.Render("next due "+formatNextDue(*project.NextDue, now))
+		}
+		if project.Billable || project.Client != "" {
+			billing := "non-billable"
+			if project.Billable {
+				billing = "billable"
+			}
+			if project.Client != "" {
+				billing = project.Client + ", " + billing
+			}
+			line += "  " + hintStyle.Render("💰 "+billing)
+		}
+		b.WriteString(style.Render(line) + "\n")
+	}
+
+	if m.editingClient && m.cursor < len(m.projects) {
+		promptStyle := lipgloss.NewStyle().Padding(1, 1, 0, 1)
+		b.WriteString(promptStyle.Render(fmt.Sprintf("Client for %s: %s", m.projects[m.cursor].Name, m.clientInput.View())) + "\n")
+		b.WriteString(hintStyle.Padding(0, 1).Render("enter: save | esc: cancel"))
+		return b.String()
+	}
+
+	if m.feedback != "" {
+		feedbackStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("46")).
+			Padding(1, 0, 0, 1)
+		b.WriteString(feedbackStyle.Render(m.feedback) + "\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+	b.WriteString(helpStyle.Render("↑/↓: navigate | enter: view project's tasks | b: toggle billable | C: set client | A: archive project | r: refresh"))
+
+	return b.String()
+}
+
+func (m ProjectsModel) renderProjectTasks() string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	b.WriteString(headerStyle.Render(fmt.Sprintf("📁 %s (%d open)", m.selected.Name, len(m.tasks))) + "\n\n")
+
+	itemStyle := lipgloss.NewStyle().Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("236")).
+		Padding(0, 2)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	if len(m.tasks) == 0 {
+		b.WriteString(itemStyle.Render(hintStyle.Render("No open tasks left in this project.")) + "\n")
+	}
+
+	now := time.Now()
+	for i, task := range m.tasks {
+		cursor := "  "
+		style := itemStyle
+		if i == m.taskCursor {
+			cursor = "→ "
+			style = selectedStyle
+		}
+
+		title := task.Title
+		if len(title) > 55 {
+			title = title[:52] + "..."
+		}
+		line := fmt.Sprintf("%s%d. %s - Score: %.0f%%", cursor, i+1, title, task.Score)
+		if task.DueTS != nil {
+			line += "  " + hintStyle.Render("due "+formatNextDue(*task.DueTS, now))
+		}
+		b.WriteString(style.Render(line) + "\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+	b.WriteString(helpStyle.Render("↑/↓: navigate | enter: open in Tasks | r: refresh | esc/q: back to projects"))
+
+	return b.String()
+}
+
+// formatNextDue shows a due date, flagging overdue ones
+func formatNextDue(due, now time.Time) string {
+	if due.Before(now) {
+		return "overdue since " + due.Format("Mon Jan 2")
+	}
+	return due.Format("Mon Jan 2 3:04 PM")
+}
+
+// GetProjects fetches the open tasks rolled up by project from the remote API
+func (c *APIClient) GetProjects() ([]*db.ProjectSummary, error) {
+	resp, err := c.doRequest("GET", "/api/projects", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var projects []*db.ProjectSummary
+	if err := json.NewDecoder(resp.Body).Decode(&projects); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return projects, nil
+}
+
+// SetProjectBilling sets a project's client and billable flag via the remote API
+func (c *APIClient) SetProjectBilling(project, client string, billable bool) error {
+	reqBody := map[string]interface{}{
+		"client":   client,
+		"billable": billable,
+	}
+
+	resp, err := c.doRequest("POST", fmt.Sprintf("/api/projects/%s/billing", url.PathEscape(project)), reqBody)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
@@ -0,0 +1,249 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// projectReview holds the state of the "close project" review list
+type projectReview struct {
+	project   string
+	tasks     []*db.Task
+	selected  map[string]bool
+	cursor    int
+	loading   bool
+	rehoming  bool
+	textInput textinput.Model
+	err       error
+}
+
+type projectTasksLoadedMsg struct {
+	project string
+	tasks   []*db.Task
+	err     error
+}
+
+type projectClosedMsg struct {
+	project string
+	action  string
+	target  string
+	count   int
+	err     error
+}
+
+func newProjectReview(project string) *projectReview {
+	ti := textinput.New()
+	ti.Placeholder = "Move remaining tasks to project..."
+	ti.CharLimit = 100
+
+	return &projectReview{
+		project:   project,
+		selected:  make(map[string]bool),
+		loading:   true,
+		textInput: ti,
+	}
+}
+
+// IsInProjectReview reports whether the tasks view is reviewing a project close
+func (m TasksModel) IsInProjectReview() bool {
+	return m.review != nil
+}
+
+// startProjectReview opens the review list for the given task's project
+func (m *TasksModel) startProjectReview(task *db.Task) tea.Cmd {
+	if task.Project == "" {
+		m.feedbackMessage = "Task has no project to close"
+		return nil
+	}
+
+	m.review = newProjectReview(task.Project)
+	return m.fetchProjectTasks(task.Project)
+}
+
+func (m TasksModel) fetchProjectTasks(project string) tea.Cmd {
+	return func() tea.Msg {
+		var tasks []*db.Task
+		var err error
+
+		if m.apiClient != nil {
+			tasks, err = m.apiClient.GetProjectTasks(project)
+		} else {
+			tasks, err = m.planner.GetProjectTasks(project)
+		}
+
+		return projectTasksLoadedMsg{project: project, tasks: tasks, err: err}
+	}
+}
+
+func (m TasksModel) closeProject(project, action, target string, taskIDs []string) tea.Cmd {
+	return func() tea.Msg {
+		var result *planner.ProjectCloseResult
+		var err error
+		count := len(taskIDs)
+
+		if m.apiClient != nil {
+			result, err = m.apiClient.CloseProject(project, action, target, taskIDs)
+		} else {
+			result, err = m.planner.CloseProject(context.Background(), project, action, target, taskIDs)
+		}
+		if result != nil {
+			count = len(result.TaskIDs)
+		}
+
+		return projectClosedMsg{project: project, action: action, target: target, count: count, err: err}
+	}
+}
+
+// selectedTaskIDs returns the IDs of tasks ticked in the review list, in display order
+func (r *projectReview) selectedTaskIDs() []string {
+	var ids []string
+	for _, task := range r.tasks {
+		if r.selected[task.ID] {
+			ids = append(ids, task.ID)
+		}
+	}
+	return ids
+}
+
+func (m *TasksModel) updateProjectReview(msg tea.KeyMsg) (*TasksModel, tea.Cmd) {
+	r := m.review
+
+	if r.rehoming {
+		switch msg.String() {
+		case "esc":
+			r.rehoming = false
+			r.textInput.Blur()
+			return m, nil
+		case "enter":
+			target := strings.TrimSpace(r.textInput.Value())
+			if target == "" {
+				return m, nil
+			}
+			r.rehoming = false
+			r.textInput.Blur()
+			return m, m.closeProject(r.project, planner.ProjectCloseRehome, target, r.selectedTaskIDs())
+		}
+
+		var cmd tea.Cmd
+		r.textInput, cmd = r.textInput.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.review = nil
+	case "up", "k":
+		if r.cursor > 0 {
+			r.cursor--
+		}
+	case "down", "j":
+		if r.cursor < len(r.tasks)-1 {
+			r.cursor++
+		}
+	case " ", "x":
+		if r.cursor < len(r.tasks) {
+			id := r.tasks[r.cursor].ID
+			r.selected[id] = !r.selected[id]
+		}
+	case "a":
+		// Toggle all: select everything unless everything is already selected
+		allSelected := len(r.selectedTaskIDs()) == len(r.tasks)
+		for _, task := range r.tasks {
+			r.selected[task.ID] = !allSelected
+		}
+	case "c":
+		if ids := r.selectedTaskIDs(); len(ids) > 0 {
+			return m, m.closeProject(r.project, planner.ProjectCloseComplete, "", ids)
+		}
+	case "m":
+		if len(r.selectedTaskIDs()) > 0 {
+			r.rehoming = true
+			r.textInput.SetValue("")
+			r.textInput.Focus()
+			return m, textinput.Blink
+		}
+	}
+
+	return m, nil
+}
+
+func (m *TasksModel) renderProjectReview() string {
+	r := m.review
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf("📁 Close project: %s", r.project)) + "\n\n")
+
+	if r.loading {
+		b.WriteString("  Loading project tasks...\n")
+		return b.String()
+	}
+
+	if r.err != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(0, 1)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", r.err)) + "\n\n")
+	}
+
+	if len(r.tasks) == 0 {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")).
+			Padding(0, 1)
+		b.WriteString(emptyStyle.Render("No open tasks left in this project.") + "\n")
+	}
+
+	taskStyle := lipgloss.NewStyle().Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("236")).
+		Padding(0, 2)
+
+	for i, task := range r.tasks {
+		cursor := "  "
+		if i == r.cursor {
+			cursor = "→ "
+		}
+		check := "[ ]"
+		if r.selected[task.ID] {
+			check = "[x]"
+		}
+
+		title := task.Title
+		if len(title) > 60 {
+			title = title[:57] + "..."
+		}
+
+		line := fmt.Sprintf("%s%s %s - Score: %.0f%%", cursor, check, title, task.Score)
+		if i == r.cursor {
+			b.WriteString(selectedStyle.Render(line) + "\n")
+		} else {
+			b.WriteString(taskStyle.Render(line) + "\n")
+		}
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+
+	if r.rehoming {
+		b.WriteString("\n  Move selected tasks to: " + r.textInput.View() + "\n")
+		b.WriteString(helpStyle.Render("enter: confirm | esc: cancel"))
+		return b.String()
+	}
+
+	b.WriteString(helpStyle.Render(fmt.Sprintf("%d of %d selected | space: toggle | a: toggle all | c: complete selected | m: move to project | esc: cancel",
+		len(r.selectedTaskIDs()), len(r.tasks))))
+
+	return b.String()
+}
@@ -28,6 +28,10 @@ type QueueModel struct {
 	detailScroll int        // Scroll position in detail view
 	viewport     viewport.Model
 	ready        bool
+	rules        []*db.ProcessingRule // "Do not process" rules, shown with R
+	showRules    bool
+	ruleCursor   int
+	message      string // Confirmation of the last rule change
 }
 
 type QueueItem struct {
@@ -83,7 +87,7 @@ func (m QueueModel) fetchQueue() tea.Cmd {
 				SELECT DISTINCT t.id, ANY_VALUE(m.subject) as subject, ANY_VALUE(m.from_addr) as from_addr, MAX(m.ts) as ts
 				FROM threads t
 				JOIN messages m ON t.id = m.thread_id
-				WHERE t.summary IS NULL OR t.summary = ''
+				WHERE (t.summary IS NULL OR t.summary = '') AND t.skipped_by_rule IS NULL
 				GROUP BY t.id
 				ORDER BY MAX(m.ts) DESC
 				LIMIT 500
@@ -151,6 +155,25 @@ func (m QueueModel) Update(msg tea.Msg) (QueueModel, tea.Cmd) {
 		m.queueItems = msg.items
 		return m, nil
 
+	case rulesLoadedMsg:
+		if msg.err != nil {
+			m.message = fmt.Sprintf("❌ Failed to load rules: %v", msg.err)
+			return m, nil
+		}
+		m.rules = msg.rules
+		if m.ruleCursor >= len(m.rules) {
+			m.ruleCursor = max(len(m.rules)-1, 0)
+		}
+		return m, nil
+
+	case ruleChangedMsg:
+		if msg.err != nil {
+			m.message = fmt.Sprintf("❌ %v", msg.err)
+			return m, nil
+		}
+		m.message = msg.message
+		return m, tea.Batch(m.fetchRules(), m.fetchQueue())
+
 	case tickProcessMsg:
 		// Now actually trigger the processing after the UI has rendered
 		return m, m.triggerProcessing()
@@ -174,6 +197,11 @@ func (m QueueModel) Update(msg tea.Msg) (QueueModel, tea.Cmd) {
 		return m, m.fetchQueue()
 
 	case tea.KeyMsg:
+		// The rules list takes over all keys while open
+		if m.showRules {
+			return m.updateRules(msg)
+		}
+
 		// If in detail view, handle detail-specific keys
 		if m.selectedItem != nil {
 			switch msg.String() {
@@ -220,6 +248,26 @@ func (m QueueModel) Update(msg tea.Msg) (QueueModel, tea.Cmd) {
 			// Refresh queue
 			m.loading = true
 			return m, m.fetchQueue()
+		case "x":
+			// Never process this thread
+			if m.cursor < len(m.queueItems) {
+				return m, m.addRule(db.RuleThread, m.queueItems[m.cursor])
+			}
+		case "s":
+			// Never process threads from this sender
+			if m.cursor < len(m.queueItems) {
+				return m, m.addRule(db.RuleSender, m.queueItems[m.cursor])
+			}
+		case "S":
+			// Never process threads from this sender's domain
+			if m.cursor < len(m.queueItems) {
+				return m, m.addRule(db.RuleDomain, m.queueItems[m.cursor])
+			}
+		case "R":
+			// Show "do not process" rules
+			m.showRules = true
+			m.message = ""
+			return m, m.fetchRules()
 		case "p":
 			// Trigger processing
 			if !m.processing && len(m.queueItems) > 0 {
@@ -251,6 +299,12 @@ func (m QueueModel) View() string {
 		return errorStyle.Render(fmt.Sprintf("Error: %v", m.err))
 	}
 
+	// The rules list replaces the queue while open
+	if m.showRules {
+		m.viewport.SetContent(m.renderRules())
+		return m.viewport.View()
+	}
+
 	// If in detail view, show thread detail
 	if m.selectedItem != nil {
 		content := m.renderQueueItemDetail()
@@ -340,9 +394,15 @@ func (m QueueModel) View() string {
 
 	b.WriteString("\n")
 	if queueCount > 0 {
-		b.WriteString(helpStyle.Render("↑/↓: navigate | enter: view details | r: refresh | p: process selected"))
+		b.WriteString(helpStyle.Render("↑/↓: navigate | enter: view details | r: refresh | p: process selected | x/s/S: skip thread/sender/domain | R: rules"))
 	} else {
-		b.WriteString(helpStyle.Render("r: refresh"))
+		b.WriteString(helpStyle.Render("r: refresh | R: rules"))
+	}
+	if m.message != "" {
+		messageStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("46")).
+			Padding(0, 1)
+		b.WriteString("\n" + messageStyle.Render(m.message))
 	}
 
 	content := b.String()
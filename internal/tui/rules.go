@@ -0,0 +1,218 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+type rulesLoadedMsg struct {
+	rules []*db.ProcessingRule
+	err   error
+}
+
+type ruleChangedMsg struct {
+	message string
+	err     error
+}
+
+func (m QueueModel) fetchRules() tea.Cmd {
+	return func() tea.Msg {
+		var rules []*db.ProcessingRule
+		var err error
+
+		if m.apiClient != nil {
+			rules, err = m.apiClient.GetRules()
+		} else {
+			rules, err = m.database.GetProcessingRules()
+			if err == nil {
+				var tagRules []*db.ProcessingRule
+				tagRules, err = m.database.GetTagRules()
+				rules = append(rules, tagRules...)
+			}
+		}
+
+		return rulesLoadedMsg{rules: rules, err: err}
+	}
+}
+
+// addRule excludes the selected queue item's thread, sender or sender domain from AI processing
+func (m QueueModel) addRule(kind string, item QueueItem) tea.Cmd {
+	pattern := item.ThreadID
+	switch kind {
+	case db.RuleSender, db.RuleDomain:
+		address := item.From
+		if addr, err := mail.ParseAddress(item.From); err == nil {
+			address = addr.Address
+		}
+		pattern = address
+		if kind == db.RuleDomain {
+			at := strings.LastIndex(address, "@")
+			if at < 0 {
+				return func() tea.Msg {
+					return ruleChangedMsg{err: fmt.Errorf("no domain in sender %q", item.From)}
+				}
+			}
+			pattern = address[at+1:]
+		}
+	}
+	note := item.Subject
+
+	return func() tea.Msg {
+		var rule *db.ProcessingRule
+		var err error
+
+		if m.apiClient != nil {
+			rule, err = m.apiClient.AddRule(kind, pattern, note)
+		} else {
+			rule, err = m.database.AddProcessingRule(kind, pattern, note)
+			if err == nil {
+				_, err = m.database.ApplyProcessingRules()
+			}
+		}
+		if err != nil {
+			return ruleChangedMsg{err: err}
+		}
+
+		return ruleChangedMsg{message: fmt.Sprintf("✓ Won't process %s %s", rule.Kind, rule.Pattern)}
+	}
+}
+
+func (m QueueModel) deleteRule(rule *db.ProcessingRule) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if m.apiClient != nil {
+			err = m.apiClient.DeleteRule(rule.ID)
+		} else if rule.Tag != "" {
+			err = m.database.DeleteTagRule(rule.ID)
+		} else {
+			err = m.database.DeleteProcessingRule(rule.ID)
+		}
+		if err != nil {
+			return ruleChangedMsg{err: err}
+		}
+		return ruleChangedMsg{message: fmt.Sprintf("✓ Removed rule for %s %s", rule.Kind, rule.Pattern)}
+	}
+}
+
+// updateRules handles keys while the rules list is open
+func (m QueueModel) updateRules(msg tea.KeyMsg) (QueueModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q", "R":
+		m.showRules = false
+	case "up", "k":
+		if m.ruleCursor > 0 {
+			m.ruleCursor--
+		}
+	case "down", "j":
+		if m.ruleCursor < len(m.rules)-1 {
+			m.ruleCursor++
+		}
+	case "d":
+		if m.ruleCursor < len(m.rules) {
+			return m, m.deleteRule(m.rules[m.ruleCursor])
+		}
+	}
+	return m, nil
+}
+
+func (m QueueModel) renderRules() string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	b.WriteString(headerStyle.Render(fmt.Sprintf("🚫 Do Not Process and Tag Rules (%d)", len(m.rules))) + "\n\n")
+
+	if len(m.rules) == 0 {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")).
+			Padding(0, 2)
+		b.WriteString(emptyStyle.Render("No rules. In the queue, x skips a thread, s its sender and S the sender's domain.") + "\n")
+	}
+
+	itemStyle := lipgloss.NewStyle().Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("236")).
+		Padding(0, 2)
+	noteStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	for i, rule := range m.rules {
+		cursor := "  "
+		style := itemStyle
+		if i == m.ruleCursor {
+			cursor = "→ "
+			style = selectedStyle
+		}
+		line := fmt.Sprintf("%s%-7s %s", cursor, rule.Kind, rule.Pattern)
+		if rule.Tag != "" {
+			line += " → #" + rule.Tag
+		}
+		if rule.Note != "" {
+			line += "  " + noteStyle.Render(rule.Note)
+		}
+		b.WriteString(style.Render(line) + "\n")
+	}
+
+	if m.message != "" {
+		messageStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("46")).
+			Padding(1, 1, 0, 1)
+		b.WriteString(messageStyle.Render(m.message) + "\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+	b.WriteString(helpStyle.Render("↑/↓: navigate | d: delete rule (skipped threads return to the queue, tags stay) | esc: back to queue"))
+
+	return b.String()
+}
+
+// GetRules fetches processing rules from the remote API
+func (c *APIClient) GetRules() ([]*db.ProcessingRule, error) {
+	resp, err := c.doRequest("GET", "/api/rules", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var rules []*db.ProcessingRule
+	if err := json.NewDecoder(resp.Body).Decode(&rules); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return rules, nil
+}
+
+// AddRule adds a processing rule via the remote API
+func (c *APIClient) AddRule(kind, pattern, note string) (*db.ProcessingRule, error) {
+	body := map[string]string{"kind": kind, "pattern": pattern, "note": note}
+	resp, err := c.doRequest("POST", "/api/rules", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var rule db.ProcessingRule
+	if err := json.NewDecoder(resp.Body).Decode(&rule); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &rule, nil
+}
+
+// DeleteRule removes a processing rule via the remote API
+func (c *APIClient) DeleteRule(id string) error {
+	resp, err := c.doRequest("DELETE", "/api/rules/"+url.PathEscape(id), nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/alexrabarts/focus-agent/internal/config"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// scoringLoadedMsg carries the scoring profile in use, after loading it or switching to another
+type scoringLoadedMsg struct {
+	scoring  *planner.Scoring
+	switched bool // Result of switching the profile and rescoring tasks
+	err      error
+}
+
+// fetchScoring loads the scoring profile in use from the API or the planner
+func fetchScoring(plannerService *planner.Planner, apiClient *APIClient) tea.Cmd {
+	return func() tea.Msg {
+		if apiClient != nil {
+			scoring, err := apiClient.GetScoring()
+			return scoringLoadedMsg{scoring: scoring, err: err}
+		}
+		if plannerService != nil {
+			return scoringLoadedMsg{scoring: plannerService.GetScoring()}
+		}
+		return nil
+	}
+}
+
+// switchScoring switches to the scoring profile after the one in use, rescoring every task
+func switchScoring(plannerService *planner.Planner, apiClient *APIClient, current *planner.Scoring) tea.Cmd {
+	return func() tea.Msg {
+		profile := nextScoringProfile(current)
+		if apiClient != nil {
+			scoring, err := apiClient.SetScoringProfile(profile)
+			return scoringLoadedMsg{scoring: scoring, switched: true, err: err}
+		}
+		if plannerService == nil {
+			return nil
+		}
+		if err := plannerService.SetScoringProfile(context.Background(), profile); err != nil {
+			return scoringLoadedMsg{switched: true, err: err}
+		}
+		return scoringLoadedMsg{scoring: plannerService.GetScoring(), switched: true}
+	}
+}
+
+// nextScoringProfile returns the profile after the one in use: default first, then the named
+// profiles alphabetically, wrapping around
+func nextScoringProfile(scoring *planner.Scoring) string {
+	if scoring == nil {
+		return config.DefaultScoringProfile
+	}
+	names := make([]string, 0, len(scoring.Profiles))
+	for name := range scoring.Profiles {
+		if name != config.DefaultScoringProfile {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	names = append([]string{config.DefaultScoringProfile}, names...)
+
+	i := slices.Index(names, scoring.Profile)
+	return names[(i+1)%len(names)]
+}
+
+// scoringWeights returns the weights of the scoring profile in use, or the default weights until
+// it's loaded
+func scoringWeights(scoring *planner.Scoring) config.ScoringWeights {
+	if scoring == nil {
+		return config.DefaultScoringWeights
+	}
+	return scoring.Weights
+}
+
+// formatScoring describes the scoring profile in use for the Priorities tab
+func formatScoring(scoring *planner.Scoring) string {
+	if scoring == nil {
+		return "Scoring: loading..."
+	}
+	return fmt.Sprintf("Scoring: %s (%s)", scoring.Profile, scoring.Weights)
+}
@@ -0,0 +1,267 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// SearchModel is the Threads tab's search mode: tasks, threads, messages, events and Drive
+// documents matching a query by keyword or by meaning
+type SearchModel struct {
+	planner   *planner.Planner
+	apiClient *APIClient
+	input     textinput.Model
+	active    bool
+	query     string
+	kind      string // Only results of this kind (db.SearchKinds), or all when empty
+	results   []*db.SearchResult
+	cursor    int
+	searching bool
+	err       error
+}
+
+type searchResultsMsg struct {
+	query   string
+	results []*db.SearchResult
+	err     error
+}
+
+func NewSearchModel(plannerService *planner.Planner, apiClient *APIClient) SearchModel {
+	ti := textinput.New()
+	ti.Placeholder = "Search tasks, mail, events and documents..."
+	ti.CharLimit = 200
+	ti.Width = 70
+
+	return SearchModel{
+		planner:   plannerService,
+		apiClient: apiClient,
+		input:     ti,
+	}
+}
+
+// Start enters search mode with the query line focused
+func (m *SearchModel) Start() tea.Cmd {
+	m.active = true
+	m.input.SetValue(m.query)
+	m.input.Focus()
+	return textinput.Blink
+}
+
+func (m SearchModel) search(query string) tea.Cmd {
+	var kinds []string
+	if m.kind != "" {
+		kinds = []string{m.kind}
+	}
+
+	return func() tea.Msg {
+		var results []*db.SearchResult
+		var err error
+
+		if m.apiClient != nil {
+			results, err = m.apiClient.Search(query, kinds...)
+		} else {
+			results, err = m.planner.Search(context.Background(), query, kinds...)
+		}
+
+		return searchResultsMsg{query: query, results: results, err: err}
+	}
+}
+
+// Update handles search keys. It returns the result chosen with enter, if any, for the
+// Threads tab to open.
+func (m SearchModel) Update(msg tea.Msg) (SearchModel, *db.SearchResult, tea.Cmd) {
+	switch msg := msg.(type) {
+	case searchResultsMsg:
+		if msg.query != m.query {
+			return m, nil, nil // Superseded by a newer search
+		}
+		m.searching = false
+		m.err = msg.err
+		m.results = msg.results
+		m.cursor = 0
+		return m, nil, nil
+
+	case tea.KeyMsg:
+		if m.input.Focused() {
+			switch msg.String() {
+			case "esc":
+				m.input.Blur()
+				if m.query == "" {
+					m.active = false
+				}
+				return m, nil, nil
+			case "enter":
+				query := strings.TrimSpace(m.input.Value())
+				if query == "" {
+					return m, nil, nil
+				}
+				m.input.Blur()
+				m.query = query
+				m.searching = true
+				m.err = nil
+				return m, nil, m.search(query)
+			}
+
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, nil, cmd
+		}
+
+		switch msg.String() {
+		case "esc", "q":
+			m.active = false
+			m.query = ""
+			m.results = nil
+			m.err = nil
+		case "/":
+			return m, nil, m.Start()
+		case "t":
+			// Cycle the type filter: all, then each kind in turn
+			m.kind = nextSearchKind(m.kind)
+			if m.query != "" {
+				m.searching = true
+				m.err = nil
+				return m, nil, m.search(m.query)
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.results)-1 {
+				m.cursor++
+			}
+		case "enter":
+			if m.cursor < len(m.results) {
+				return m, m.results[m.cursor], nil
+			}
+		}
+	}
+
+	return m, nil, nil
+}
+
+func (m SearchModel) View() string {
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	header := "🔍 Search Everything"
+	if m.kind != "" {
+		header = fmt.Sprintf("🔍 Search %ss", strings.ToUpper(m.kind[:1])+m.kind[1:])
+	}
+	b.WriteString(headerStyle.Render(header) + "\n\n")
+	b.WriteString("  " + m.input.View() + "\n\n")
+
+	mutedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(0, 2)
+
+	switch {
+	case m.searching:
+		b.WriteString(mutedStyle.Render("Searching...") + "\n")
+	case m.err != nil:
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(0, 2)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)) + "\n")
+	case m.query != "" && len(m.results) == 0:
+		b.WriteString(mutedStyle.Render("Nothing found.") + "\n")
+	default:
+		b.WriteString(m.renderResults())
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+	help := "↑/↓: navigate | enter: open | t: filter by type | /: new search | esc: back to threads"
+	if m.input.Focused() {
+		help = "enter: search | esc: cancel"
+	}
+	b.WriteString(helpStyle.Render(help))
+
+	return b.String()
+}
+
+func (m SearchModel) renderResults() string {
+	var b strings.Builder
+	itemStyle := lipgloss.NewStyle().Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("236")).
+		Padding(0, 2)
+	snippetStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("250")).
+		Padding(0, 4)
+	matchStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241"))
+
+	for i, result := range m.results {
+		cursor := "  "
+		style := itemStyle
+		if i == m.cursor {
+			cursor = "→ "
+			style = selectedStyle
+		}
+
+		icon := searchIcons[result.Kind]
+		title := result.Title
+		if title == "" {
+			title = "(no subject)"
+		}
+		if len(title) > 60 {
+			title = title[:57] + "..."
+		}
+		b.WriteString(style.Render(fmt.Sprintf("%s%s %s", cursor, icon, title)) + " " + matchStyle.Render(describeMatch(result)) + "\n")
+
+		snippet := strings.Join(strings.Fields(result.Snippet), " ")
+		if len(snippet) > 120 {
+			snippet = snippet[:117] + "..."
+		}
+		if snippet != "" {
+			b.WriteString(snippetStyle.Render(snippet) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// searchIcons mark each kind of search result
+var searchIcons = map[string]string{
+	db.ContentKindTask:     "✅",
+	db.ContentKindThread:   "📧",
+	db.ContentKindMessage:  "✉️ ",
+	db.ContentKindEvent:    "📅",
+	db.ContentKindDocument: "📄",
+}
+
+// nextSearchKind returns the type filter after kind, cycling through all kinds and back to none
+func nextSearchKind(kind string) string {
+	for i, k := range db.SearchKinds {
+		if k == kind && i+1 < len(db.SearchKinds) {
+			return db.SearchKinds[i+1]
+		}
+	}
+	if kind == "" {
+		return db.SearchKinds[0]
+	}
+	return ""
+}
+
+// describeMatch says how a search result was found
+func describeMatch(result *db.SearchResult) string {
+	switch {
+	case result.KeywordMatch && result.Similarity > 0:
+		return fmt.Sprintf("(keyword + related %.0f%%)", result.Similarity*100)
+	case result.Similarity > 0:
+		return fmt.Sprintf("(related %.0f%%)", result.Similarity*100)
+	}
+	return "(keyword)"
+}
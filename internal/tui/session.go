@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// tabNames are the saved names of each view, indexed by view
+var tabNames = []string{"tasks", "agenda", "projects", "people", "priorities", "queue", "threads", "ask", "about"}
+
+type sessionLoadedMsg struct {
+	session *db.TUISession
+	err     error
+}
+
+// fetchSession loads this terminal's saved layout
+func (m Model) fetchSession() tea.Cmd {
+	return func() tea.Msg {
+		var session *db.TUISession
+		var err error
+
+		if m.apiClient != nil {
+			session, err = m.apiClient.GetTUISession(m.config.TUI.ClientID)
+		} else {
+			session, err = m.database.GetTUISession(m.config.TUI.ClientID)
+		}
+
+		return sessionLoadedMsg{session: session, err: err}
+	}
+}
+
+// saveSession stores the current tab and filters for this terminal
+func (m Model) saveSession() tea.Cmd {
+	session := &db.TUISession{
+		Tab:        tabNames[m.currentView],
+		TaskSource: m.tasksModel.sourceFilter,
+	}
+
+	return func() tea.Msg {
+		var err error
+		if m.apiClient != nil {
+			err = m.apiClient.SaveTUISession(m.config.TUI.ClientID, session)
+		} else {
+			err = m.database.SaveTUISession(m.config.TUI.ClientID, session)
+		}
+		if err != nil {
+			log.Printf("Failed to save TUI session: %v", err)
+		}
+		return nil
+	}
+}
+
+// applySession restores a saved layout
+func (m *Model) applySession(session *db.TUISession) {
+	for i, name := range tabNames {
+		if name == session.Tab {
+			m.currentView = view(i)
+			break
+		}
+	}
+	m.tasksModel.setSourceFilter(session.TaskSource)
+}
+
+// GetTUISession fetches the saved layout for a TUI client from the remote API
+func (c *APIClient) GetTUISession(clientID string) (*db.TUISession, error) {
+	resp, err := c.doRequest("GET", "/api/tui/session?client_id="+url.QueryEscape(clientID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var session db.TUISession
+	if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &session, nil
+}
+
+// SaveTUISession stores the layout for a TUI client via the remote API
+func (c *APIClient) SaveTUISession(clientID string, session *db.TUISession) error {
+	resp, err := c.doRequest("PUT", "/api/tui/session?client_id="+url.QueryEscape(clientID), session)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
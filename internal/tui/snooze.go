@@ -0,0 +1,279 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// snoozeMorningHour is when "tomorrow", "next week" and custom dates snooze until
+const snoozeMorningHour = 9
+
+// snoozePicker holds the state of the snooze picker opened with s
+type snoozePicker struct {
+	task   *db.Task
+	cursor int
+	custom bool // Typing a custom date
+	input  textinput.Model
+	saving bool
+	err    error
+}
+
+type snoozeOption struct {
+	label string
+	until func(now time.Time) time.Time // Nil for the custom date
+}
+
+var snoozeOptions = []snoozeOption{
+	{"1 hour", func(now time.Time) time.Time { return now.Add(time.Hour) }},
+	{"Tomorrow", func(now time.Time) time.Time { return snoozeMorning(now.AddDate(0, 0, 1)) }},
+	{"Next week", func(now time.Time) time.Time {
+		// Monday morning
+		days := (8 - int(now.Weekday())) % 7
+		if days == 0 {
+			days = 7
+		}
+		return snoozeMorning(now.AddDate(0, 0, days))
+	}},
+	{"Custom date", nil},
+}
+
+type taskSnoozedMsg struct {
+	taskID string
+	until  time.Time
+	err    error
+}
+
+// snoozeMorning returns the snooze hour on the given day
+func snoozeMorning(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), snoozeMorningHour, 0, 0, 0, day.Location())
+}
+
+// parseSnoozeDate reads a custom snooze date: 2006-01-02, 2006-01-02 15:04 or Jan 2. Dates
+// without a time snooze until the morning, and dates without a year until the next one to come.
+func parseSnoozeDate(value string, now time.Time) (time.Time, error) {
+	return parseDateInput(value, now, snoozeMorningHour)
+}
+
+// parseDateInput reads a typed date: 2006-01-02, 2006-01-02 15:04 or Jan 2. Dates without a
+// time fall at the given hour, and dates without a year at the next one to come.
+func parseDateInput(value string, now time.Time, hour int) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	atHour := func(day time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, 0, 0, 0, day.Location())
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02 15:04", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return atHour(t), nil
+	}
+	for _, layout := range []string{"Jan 2", "January 2"} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			t = atHour(time.Date(now.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location()))
+			if !t.After(now) {
+				t = t.AddDate(1, 0, 0)
+			}
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("couldn't read %q (try YYYY-MM-DD, YYYY-MM-DD HH:MM or Mar 14)", value)
+}
+
+// IsSnoozing reports whether the tasks view is showing the snooze picker
+func (m TasksModel) IsSnoozing() bool {
+	return m.snooze != nil
+}
+
+// startSnooze opens the snooze picker for a task
+func (m *TasksModel) startSnooze(task *db.Task) {
+	ti := textinput.New()
+	ti.Placeholder = "YYYY-MM-DD, YYYY-MM-DD HH:MM or Mar 14"
+	ti.CharLimit = 40
+	ti.Width = 40
+
+	m.snooze = &snoozePicker{task: task, input: ti}
+}
+
+func (m *TasksModel) updateSnooze(msg tea.KeyMsg) (*TasksModel, tea.Cmd) {
+	s := m.snooze
+	if s.saving {
+		return m, nil
+	}
+
+	if s.custom {
+		switch msg.String() {
+		case "esc":
+			s.custom = false
+			s.err = nil
+			s.input.Blur()
+			return m, nil
+		case "enter":
+			now := time.Now()
+			until, err := parseSnoozeDate(s.input.Value(), now)
+			if err == nil && !until.After(now) {
+				err = fmt.Errorf("%s is in the past", until.Format("Mon Jan 2 3:04 PM"))
+			}
+			if err != nil {
+				s.err = err
+				return m, nil
+			}
+			return m, m.snoozeUntil(until)
+		}
+
+		var cmd tea.Cmd
+		s.input, cmd = s.input.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.snooze = nil
+	case "up", "k":
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case "down", "j":
+		if s.cursor < len(snoozeOptions)-1 {
+			s.cursor++
+		}
+	case "1", "2", "3", "4":
+		s.cursor = int(msg.String()[0] - '1')
+		return m.chooseSnoozeOption()
+	case "enter":
+		return m.chooseSnoozeOption()
+	}
+
+	return m, nil
+}
+
+// chooseSnoozeOption snoozes until the selected option, or starts typing a custom date
+func (m *TasksModel) chooseSnoozeOption() (*TasksModel, tea.Cmd) {
+	s := m.snooze
+	option := snoozeOptions[s.cursor]
+	if option.until == nil {
+		s.custom = true
+		s.err = nil
+		s.input.Focus()
+		return m, textinput.Blink
+	}
+	return m, m.snoozeUntil(option.until(time.Now()))
+}
+
+func (m *TasksModel) snoozeUntil(until time.Time) tea.Cmd {
+	m.snooze.saving = true
+	m.snooze.err = nil
+	return m.snoozeTask(m.snooze.task.ID, until)
+}
+
+func (m TasksModel) snoozeTask(taskID string, until time.Time) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if m.apiClient != nil {
+			err = m.apiClient.SnoozeTask(taskID, until)
+		} else {
+			err = m.planner.SnoozeTask(context.Background(), taskID, until)
+		}
+		return taskSnoozedMsg{taskID: taskID, until: until, err: err}
+	}
+}
+
+// handleTaskSnoozed closes the picker and reloads tasks, since snoozing rescores them
+func (m *TasksModel) handleTaskSnoozed(msg taskSnoozedMsg) tea.Cmd {
+	if msg.err != nil {
+		if m.snooze != nil {
+			m.snooze.saving = false
+			m.snooze.err = msg.err
+		}
+		return nil
+	}
+
+	title := ""
+	if m.snooze != nil {
+		title = m.snooze.task.Title
+		m.pushUndo(undoOp{kind: undoSnooze, taskID: msg.taskID, title: title, dueTS: m.snooze.task.DueTS})
+	}
+	m.snooze = nil
+	m.selectedTask = nil
+	m.detailScroll = 0
+	m.feedbackMessage = fmt.Sprintf("💤 Snoozed until %s: %s", msg.until.Format("Mon Jan 2 3:04 PM"), title)
+	m.loading = true
+	return m.fetchTasks()
+}
+
+func (m *TasksModel) renderSnooze() string {
+	s := m.snooze
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	b.WriteString(headerStyle.Render("💤 Snooze: "+s.task.Title) + "\n\n")
+
+	itemStyle := lipgloss.NewStyle().Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("236")).
+		Padding(0, 2)
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	now := time.Now()
+	for i, option := range snoozeOptions {
+		cursor := "  "
+		style := itemStyle
+		if i == s.cursor {
+			cursor = "→ "
+			style = selectedStyle
+		}
+		line := fmt.Sprintf("%s%d. %s", cursor, i+1, option.label)
+		if option.until != nil {
+			line += "  " + hintStyle.Render(option.until(now).Format("Mon Jan 2 3:04 PM"))
+		}
+		b.WriteString(style.Render(line) + "\n")
+	}
+
+	if s.custom {
+		b.WriteString("\n" + itemStyle.Render(s.input.View()) + "\n")
+	}
+
+	if s.err != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(1, 1, 0, 1)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", s.err)) + "\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+
+	switch {
+	case s.saving:
+		b.WriteString(helpStyle.Render("Snoozing..."))
+	case s.custom:
+		b.WriteString(helpStyle.Render("enter: snooze | esc: back to options"))
+	default:
+		b.WriteString(helpStyle.Render("↑/↓: select | 1-4/enter: snooze | esc: cancel"))
+	}
+	return b.String()
+}
+
+// SnoozeTask defers a task via the remote API
+func (c *APIClient) SnoozeTask(taskID string, until time.Time) error {
+	body := map[string]string{"until": until.Format(time.RFC3339)}
+	resp, err := c.doRequest("POST", "/api/tasks/"+url.PathEscape(taskID)+"/snooze", body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -1,24 +1,35 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/config"
 	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/llm"
+	"github.com/alexrabarts/focus-agent/internal/planner"
 )
 
 type StatsModel struct {
 	database  *db.DB
 	apiClient *APIClient
+	llm       llm.Client
+	planner   *planner.Planner
+	config    *config.Config
 	stats     Stats
 	loading   bool
 	err       error
 	viewport  viewport.Model
 	ready     bool
+
+	timezoneInput *textinput.Model // Prompt for the timezone to switch to (nil when closed)
+	timezoneErr   error
 }
 
 type Stats struct {
@@ -35,17 +46,30 @@ type Stats struct {
 	LastDriveSync     *time.Time
 	LastCalendarSync  *time.Time
 	LastTasksSync     *time.Time
+	Budgets           []*db.ProviderBudgetStatus // LLM provider usage against daily caps
+	Providers         []*llm.ProviderHealth      // LLM provider availability and Ollama load state
+	Issues            []*db.Issue                // Operations that are currently failing
+	Setup             *db.SetupProgress          // First-run checklist (nil if unknown)
+	Effort            []*db.WeekEffort           // Tracked time against estimates, oldest week first
+	Deliveries        []*db.OutboxItem           // Briefs and reminders from the last week, newest first
+	Timezone          *planner.Timezone          // Timezone briefs are scheduled in (nil if unknown)
 }
 
+// maxDeliveries is how many recent deliveries the Stats tab lists
+const maxDeliveries = 10
+
 type statsLoadedMsg struct {
 	stats Stats
 	err   error
 }
 
-func NewStatsModel(database *db.DB, apiClient *APIClient) StatsModel {
+func NewStatsModel(database *db.DB, apiClient *APIClient, llmClient llm.Client, plannerService *planner.Planner, cfg *config.Config) StatsModel {
 	return StatsModel{
 		database:  database,
 		apiClient: apiClient,
+		llm:       llmClient,
+		planner:   plannerService,
+		config:    cfg,
 		loading:   true,
 		viewport:  viewport.New(80, 20),
 	}
@@ -66,6 +90,17 @@ func (m StatsModel) fetchStats() tea.Cmd {
 		if m.apiClient != nil {
 			// Use remote API
 			stats, err = m.apiClient.GetStats()
+			if err == nil {
+				// Older servers have no budget endpoint; the section is simply left out
+				stats.Budgets, _ = m.apiClient.GetBudgets()
+				stats.Providers, _ = m.apiClient.GetProviderHealth()
+				stats.Issues, _ = m.apiClient.GetIssues()
+				if effort, err := m.apiClient.GetEffortStats(); err == nil {
+					stats.Effort = effort.Weeks
+				}
+				stats.Deliveries, _ = m.apiClient.GetDeliveries()
+				stats.Timezone, _ = m.apiClient.GetTimezone()
+			}
 			return statsLoadedMsg{stats: stats, err: err}
 		}
 
@@ -78,7 +113,7 @@ func (m StatsModel) fetchStats() tea.Cmd {
 		m.database.QueryRow("SELECT COUNT(*) FROM tasks").Scan(&stats.TaskCount)
 		m.database.QueryRow("SELECT COUNT(*) FROM tasks WHERE status = 'pending'").Scan(&stats.PendingTasks)
 		m.database.QueryRow("SELECT COUNT(*) FROM tasks WHERE status = 'pending' AND score >= 4.0").Scan(&stats.HighPriorityTasks)
-		m.database.QueryRow("SELECT COUNT(*) FROM threads WHERE summary IS NULL OR summary = ''").Scan(&stats.ThreadsNeedingAI)
+		m.database.QueryRow("SELECT COUNT(*) FROM threads WHERE (summary IS NULL OR summary = '') AND skipped_by_rule IS NULL").Scan(&stats.ThreadsNeedingAI)
 
 		// Completed today
 		today := time.Now().Format("2006-01-02")
@@ -110,6 +145,23 @@ func (m StatsModel) fetchStats() tea.Cmd {
 			stats.LastTasksSync = &t
 		}
 
+		if setup, err := m.database.GetSetupProgress(); err == nil {
+			setup.Authenticated = m.config.Google.UserEmail != ""
+			setup.PrioritiesAdded = setup.PrioritiesAdded || !m.config.Priorities.IsEmpty()
+			stats.Setup = setup
+		}
+
+		stats.Budgets, _ = m.database.GetProviderBudgets(m.config.LLM.Budgets)
+		stats.Issues, _ = m.database.GetOpenIssues()
+		stats.Effort, _ = m.database.GetWeeklyEfforts(db.EffortWeeks)
+		stats.Deliveries, _ = m.database.GetDeliveryHistory(time.Now().AddDate(0, 0, -db.DeliveryHistoryDays), 100)
+		if m.llm != nil {
+			stats.Providers = m.llm.ProviderHealth(context.Background())
+		}
+		if m.planner != nil {
+			stats.Timezone = m.planner.Timezone()
+		}
+
 		return statsLoadedMsg{stats: stats}
 	}
 }
@@ -125,11 +177,26 @@ func (m StatsModel) Update(msg tea.Msg) (StatsModel, tea.Cmd) {
 		m.stats = msg.stats
 		return m, nil
 
+	case timezoneSetMsg:
+		if msg.err != nil {
+			m.timezoneErr = msg.err
+			return m, nil
+		}
+		m.stats.Timezone = msg.timezone
+		m.timezoneInput = nil
+		m.timezoneErr = nil
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.timezoneInput != nil {
+			return m.updateTimezoneInput(msg)
+		}
 		switch msg.String() {
 		case "r":
 			m.loading = true
 			return m, m.fetchStats()
+		case "z":
+			return m, m.startTimezoneInput()
 		}
 	}
 
@@ -182,6 +249,17 @@ func (m StatsModel) View() string {
 	itemStyle := lipgloss.NewStyle().
 		Padding(0, 2)
 
+	// Setup checklist, until every step is done
+	if m.stats.Setup != nil && !m.stats.Setup.Done() {
+		b.WriteString(headerStyle.Render("🚀 Getting Started") + "\n\n")
+		b.WriteString(renderSetupChecklist(m.stats.Setup, itemStyle) + "\n")
+	}
+
+	// Issues section, ahead of the counts so current problems aren't missed
+	if len(m.stats.Issues) > 0 {
+		b.WriteString(m.renderIssues(headerStyle, itemStyle))
+	}
+
 	b.WriteString(headerStyle.Render("📊 Data Synced") + "\n\n")
 	b.WriteString(itemStyle.Render(fmt.Sprintf("Email Threads: %d", m.stats.ThreadCount)) + "\n")
 	b.WriteString(itemStyle.Render(fmt.Sprintf("Messages: %d", m.stats.MessageCount)) + "\n")
@@ -197,6 +275,18 @@ func (m StatsModel) View() string {
 	b.WriteString(itemStyle.Render(fmt.Sprintf("Completed Today: %d", m.stats.CompletedToday)) + "\n")
 	b.WriteString("\n")
 
+	// Tracked time section, once any work has been timed
+	if tracked := m.renderEffort(itemStyle); tracked != "" {
+		b.WriteString(headerStyle.Render("⏱  Tracked Time") + "\n\n")
+		b.WriteString(tracked + "\n")
+	}
+
+	// Timezone section, so briefs moving while travelling aren't a surprise
+	if m.stats.Timezone != nil || m.timezoneInput != nil {
+		b.WriteString(headerStyle.Render("🌍 Timezone") + "\n\n")
+		b.WriteString(m.renderTimezone(itemStyle) + "\n")
+	}
+
 	// Last Sync section
 	b.WriteString(headerStyle.Render("🔄 Last Sync") + "\n\n")
 	b.WriteString(itemStyle.Render(fmt.Sprintf("Gmail: %s", m.formatTime(m.stats.LastGmailSync))) + "\n")
@@ -204,18 +294,209 @@ func (m StatsModel) View() string {
 	b.WriteString(itemStyle.Render(fmt.Sprintf("Calendar: %s", m.formatTime(m.stats.LastCalendarSync))) + "\n")
 	b.WriteString(itemStyle.Render(fmt.Sprintf("Tasks: %s", m.formatTime(m.stats.LastTasksSync))) + "\n")
 
+	// Deliveries section, so a brief that never went out doesn't go unnoticed
+	if len(m.stats.Deliveries) > 0 {
+		b.WriteString("\n")
+		b.WriteString(headerStyle.Render("📬 Deliveries") + "\n\n")
+		b.WriteString(m.renderDeliveries(itemStyle))
+	}
+
+	// LLM budgets section
+	if len(m.stats.Budgets) > 0 {
+		b.WriteString("\n")
+		b.WriteString(headerStyle.Render("💰 LLM Budgets Today") + "\n\n")
+		for _, budget := range m.stats.Budgets {
+			b.WriteString(itemStyle.Render(m.formatBudget(budget)) + "\n")
+		}
+	}
+
+	// LLM provider health section
+	if len(m.stats.Providers) > 0 {
+		b.WriteString("\n")
+		b.WriteString(headerStyle.Render("🧠 LLM Providers") + "\n\n")
+		for _, provider := range m.stats.Providers {
+			status := "available"
+			if !provider.Available {
+				status = "unavailable"
+			}
+			b.WriteString(itemStyle.Render(fmt.Sprintf("%s: %s", provider.Provider, status)) + "\n")
+			for _, host := range provider.Hosts {
+				b.WriteString(itemStyle.Render("  "+m.formatOllamaHost(host)) + "\n")
+			}
+		}
+	}
+
 	helpStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("241")).
 		Padding(1, 0, 0, 1)
 
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("r: refresh"))
+	if m.timezoneInput != nil {
+		b.WriteString(helpStyle.Render("enter: switch timezone • esc: cancel"))
+	} else {
+		b.WriteString(helpStyle.Render("r: refresh • z: switch timezone"))
+	}
 
 	content := b.String()
 	m.viewport.SetContent(content)
 	return m.viewport.View()
 }
 
+// renderEffort lists the time tracked in recent weeks and, for the tasks completed each week,
+// the time they took against their effort estimates. It returns "" until any time is tracked.
+func (m StatsModel) renderEffort(itemStyle lipgloss.Style) string {
+	var b strings.Builder
+	for i := len(m.stats.Effort) - 1; i >= 0; i-- {
+		week := m.stats.Effort[i]
+		if week.Sessions == 0 && week.Completed == 0 {
+			continue
+		}
+		line := fmt.Sprintf("Week of %s: %s in %d sessions, %d interruptions",
+			week.Start.Format("Jan 2"), formatTracked(week.Tracked), week.Sessions, week.Interruptions)
+		if week.Completed > 0 && week.Estimate > 0 {
+			line += fmt.Sprintf(" · %d done in %s vs %s estimated (%.0f%%)",
+				week.Completed, formatTracked(week.Actual), formatTracked(week.Estimate),
+				float64(week.Actual)/float64(week.Estimate)*100)
+		}
+		b.WriteString(itemStyle.Render(line) + "\n")
+	}
+	return b.String()
+}
+
+// renderDeliveries says whether today's daily brief went out, then lists the latest briefs and
+// reminders with how their delivery went
+func (m StatsModel) renderDeliveries(itemStyle lipgloss.Style) string {
+	var b strings.Builder
+
+	today := time.Now().Format("2006-01-02")
+	var sent, failed []string
+	for _, item := range m.stats.Deliveries {
+		if item.Kind != "daily_brief" || item.CreatedAt.Format("2006-01-02") != today {
+			continue
+		}
+		switch item.Status {
+		case db.OutboxSent:
+			sent = append(sent, item.Channel)
+		case db.OutboxFailed:
+			failed = append(failed, item.Channel)
+		}
+	}
+	brief := "Today's brief: not sent yet"
+	if len(sent) > 0 {
+		brief = "Today's brief: delivered to " + strings.Join(sent, ", ")
+	}
+	if len(failed) > 0 {
+		brief += " · failed on " + strings.Join(failed, ", ")
+	}
+	b.WriteString(itemStyle.Render(brief) + "\n")
+
+	for i, item := range m.stats.Deliveries {
+		if i == maxDeliveries {
+			break
+		}
+		b.WriteString(itemStyle.Render(formatDelivery(item)) + "\n")
+	}
+	return b.String()
+}
+
+// formatDelivery describes one brief or reminder and where its delivery stands
+func formatDelivery(item *db.OutboxItem) string {
+	line := fmt.Sprintf("%s %s → %s", item.CreatedAt.Format("Mon 15:04"), strings.ReplaceAll(item.Kind, "_", " "), item.Channel)
+
+	lastError := item.LastError
+	if runes := []rune(lastError); len(runes) > 60 {
+		lastError = string(runes[:57]) + "..."
+	}
+	switch {
+	case item.Status == db.OutboxSent:
+		line = "✓ " + line
+		if item.Attempts > 1 {
+			line += fmt.Sprintf(" (after %d attempts)", item.Attempts)
+		}
+	case item.Status == db.OutboxFailed && item.Attempts > 1:
+		line = fmt.Sprintf("✗ %s (gave up after %d attempts: %s)", line, item.Attempts, lastError)
+	case item.Status == db.OutboxFailed:
+		line = fmt.Sprintf("✗ %s (failed: %s)", line, lastError)
+	case item.Attempts > 0:
+		line = fmt.Sprintf("↻ %s (retrying at %s after %d attempts: %s)", line, item.NextAttemptAt.Format("15:04"), item.Attempts, lastError)
+	case item.NextAttemptAt.After(time.Now()):
+		line = fmt.Sprintf("⏸ %s (held until %s)", line, item.NextAttemptAt.Format("Mon 15:04"))
+	default:
+		line = fmt.Sprintf("⏸ %s (held)", line)
+	}
+	return line
+}
+
+// renderIssues lists the operations that are currently failing, each with a suggested fix
+func (m StatsModel) renderIssues(headerStyle, itemStyle lipgloss.Style) string {
+	issueStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("214")).
+		Padding(0, 2)
+
+	fixStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("245")).
+		Padding(0, 4)
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(fmt.Sprintf("⚠️  Issues (%d)", len(m.stats.Issues))) + "\n\n")
+	for _, issue := range m.stats.Issues {
+		failures := "failed once"
+		if issue.Count > 1 {
+			failures = fmt.Sprintf("failed %d times since %s", issue.Count, m.formatTime(&issue.FirstSeen))
+		}
+		b.WriteString(issueStyle.Render(fmt.Sprintf("%s %s: %s error, %s", issue.Service, issue.Action, issue.Category, failures)) + "\n")
+
+		message := issue.Message
+		if runes := []rune(message); len(runes) > 100 {
+			message = string(runes[:97]) + "..."
+		}
+		b.WriteString(itemStyle.Render("  "+message) + "\n")
+		b.WriteString(fixStyle.Render("→ "+issue.Fix) + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// formatBudget describes a provider's remaining daily budget
+func (m StatsModel) formatBudget(budget *db.ProviderBudgetStatus) string {
+	var parts []string
+	if budget.MaxTokens > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d tokens left", budget.RemainingTokens(), budget.MaxTokens))
+	}
+	if budget.MaxCost > 0 {
+		parts = append(parts, fmt.Sprintf("$%.2f/$%.2f left", budget.RemainingCost(), budget.MaxCost))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%d tokens used, no cap", budget.UsedTokens))
+	}
+
+	line := fmt.Sprintf("%s: %s", budget.Provider, strings.Join(parts, ", "))
+	if budget.Exhausted() {
+		line += fmt.Sprintf(" (exhausted, resets %s)", budget.ResetsAt.Format("3:04 PM"))
+	}
+	return line
+}
+
+// formatOllamaHost describes whether a host has the model loaded, and for how long
+func (m StatsModel) formatOllamaHost(host *llm.OllamaHostHealth) string {
+	name := host.Name
+	if name == "" {
+		name = host.URL
+	}
+	switch {
+	case !host.Reachable:
+		return fmt.Sprintf("%s: unreachable", name)
+	case !host.ModelLoaded:
+		return fmt.Sprintf("%s: model cold (first request will wait for it to load)", name)
+	case host.ExpiresAt != nil && host.ExpiresAt.Year() > time.Now().Year()+100:
+		// keep_alive -1: never unloaded
+		return fmt.Sprintf("%s: model loaded", name)
+	case host.ExpiresAt != nil:
+		return fmt.Sprintf("%s: model loaded until %s", name, host.ExpiresAt.Local().Format("3:04 PM"))
+	}
+	return fmt.Sprintf("%s: model loaded", name)
+}
+
 func (m StatsModel) formatTime(t *time.Time) string {
 	if t == nil {
 		return "Never"
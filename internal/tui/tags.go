@@ -0,0 +1,276 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// tagEditor holds the state of the tag prompt opened with t
+type tagEditor struct {
+	task   *db.Task
+	input  textinput.Model
+	saving bool
+	err    error
+}
+
+type tagsUpdatedMsg struct {
+	taskID string
+	tags   []string
+	err    error
+}
+
+// IsEditingTags reports whether the tasks view is prompting for tags
+func (m TasksModel) IsEditingTags() bool {
+	return m.tagEditor != nil
+}
+
+// startTagEditor opens the tag prompt for a task
+func (m *TasksModel) startTagEditor(task *db.Task) tea.Cmd {
+	ti := textinput.New()
+	ti.Placeholder = "finance deep-work -hiring"
+	ti.CharLimit = 200
+	ti.Width = 50
+	ti.Focus()
+
+	m.tagEditor = &tagEditor{task: task, input: ti}
+	return textinput.Blink
+}
+
+func (m *TasksModel) updateTagEditor(msg tea.KeyMsg) (*TasksModel, tea.Cmd) {
+	e := m.tagEditor
+
+	switch msg.String() {
+	case "esc":
+		m.tagEditor = nil
+		return m, nil
+	case "enter":
+		if e.saving {
+			return m, nil
+		}
+		add, remove := parseTagInput(e.input.Value())
+		if len(add) == 0 && len(remove) == 0 {
+			m.tagEditor = nil
+			return m, nil
+		}
+		for _, tag := range append(append([]string{}, add...), remove...) {
+			if _, err := db.NormalizeTag(tag); err != nil {
+				e.err = err
+				return m, nil
+			}
+		}
+		e.saving = true
+		e.err = nil
+		return m, m.saveTaskTags(e.task.ID, add, remove)
+	}
+
+	var cmd tea.Cmd
+	e.input, cmd = e.input.Update(msg)
+	return m, cmd
+}
+
+// parseTagInput splits the prompt into tags to add and, prefixed with '-', tags to remove
+func parseTagInput(value string) (add, remove []string) {
+	for _, word := range strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == ',' }) {
+		if strings.HasPrefix(word, "-") {
+			if tag := strings.TrimPrefix(word, "-"); tag != "" {
+				remove = append(remove, tag)
+			}
+			continue
+		}
+		add = append(add, word)
+	}
+	return add, remove
+}
+
+func (m TasksModel) saveTaskTags(taskID string, add, remove []string) tea.Cmd {
+	return func() tea.Msg {
+		tags, err := m.updateTaskTags(taskID, add, remove)
+		return tagsUpdatedMsg{taskID: taskID, tags: tags, err: err}
+	}
+}
+
+// updateTaskTags adds and removes a task's tags, returning its tags
+func (m TasksModel) updateTaskTags(taskID string, add, remove []string) ([]string, error) {
+	if m.apiClient != nil {
+		return m.apiClient.UpdateTaskTags(taskID, add, remove)
+	}
+
+	for _, tag := range add {
+		if _, err := m.database.AddTaskTag(taskID, tag); err != nil {
+			return nil, err
+		}
+	}
+	for _, tag := range remove {
+		if err := m.database.RemoveTaskTag(taskID, tag); err != nil {
+			return nil, err
+		}
+	}
+	return m.database.GetTaskTags(taskID)
+}
+
+// handleTagsUpdated closes the tag prompt and updates the task in place
+func (m *TasksModel) handleTagsUpdated(msg tagsUpdatedMsg) {
+	if msg.err != nil {
+		if m.tagEditor != nil {
+			m.tagEditor.saving = false
+			m.tagEditor.err = msg.err
+		}
+		return
+	}
+
+	if m.tagEditor != nil {
+		added, removed := diffTags(m.tagEditor.task.Tags, msg.tags)
+		if len(added) > 0 || len(removed) > 0 {
+			m.pushUndo(undoOp{kind: undoTags, taskID: msg.taskID, title: m.tagEditor.task.Title, added: added, removed: removed})
+		}
+	}
+	m.tagEditor = nil
+	for _, task := range m.allTasks {
+		if task.ID == msg.taskID {
+			task.Tags = msg.tags
+		}
+	}
+	if m.selectedTask != nil && m.selectedTask.ID == msg.taskID {
+		m.selectedTask.Tags = msg.tags
+	}
+	if len(msg.tags) == 0 {
+		m.feedbackMessage = "✓ Tags cleared"
+	} else {
+		m.feedbackMessage = "✓ Tags: " + formatTags(msg.tags)
+	}
+
+	// Keep the task on screen in the detail view even if it no longer matches the tag filter
+	if m.selectedTask == nil {
+		m.applySourceFilter()
+	}
+}
+
+// nextTagFilter returns the tag after the current filter, cycling through the tags of the
+// loaded tasks and back to all
+func (m *TasksModel) nextTagFilter() string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, task := range m.allTasks {
+		for _, tag := range task.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+
+	if m.tagFilter == "" {
+		if len(tags) > 0 {
+			return tags[0]
+		}
+		return ""
+	}
+	for i, tag := range tags {
+		if tag == m.tagFilter && i+1 < len(tags) {
+			return tags[i+1]
+		}
+	}
+	return ""
+}
+
+// diffTags returns the tags in after but not before, and in before but not after
+func diffTags(before, after []string) (added, removed []string) {
+	for _, tag := range after {
+		if !slices.Contains(before, tag) {
+			added = append(added, tag)
+		}
+	}
+	for _, tag := range before {
+		if !slices.Contains(after, tag) {
+			removed = append(removed, tag)
+		}
+	}
+	return added, removed
+}
+
+// hasTag reports whether a task carries a tag
+func hasTag(task *db.Task, tag string) bool {
+	for _, t := range task.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// formatTags renders tags as "#finance #hiring"
+func formatTags(tags []string) string {
+	formatted := make([]string, len(tags))
+	for i, tag := range tags {
+		formatted[i] = "#" + tag
+	}
+	return strings.Join(formatted, " ")
+}
+
+func (m *TasksModel) renderTagEditor() string {
+	e := m.tagEditor
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	b.WriteString(headerStyle.Render("🏷  Tags: "+e.task.Title) + "\n\n")
+
+	infoStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252")).
+		Padding(0, 2)
+	current := "none"
+	if len(e.task.Tags) > 0 {
+		current = formatTags(e.task.Tags)
+	}
+	b.WriteString(infoStyle.Render("Current: "+current) + "\n\n")
+	b.WriteString(infoStyle.Render(e.input.View()) + "\n")
+
+	if e.err != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(1, 1, 0, 1)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", e.err)) + "\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+
+	if e.saving {
+		b.WriteString(helpStyle.Render("Saving..."))
+		return b.String()
+	}
+
+	b.WriteString(helpStyle.Render("words add tags, -word removes one | enter: save | esc: cancel"))
+	return b.String()
+}
+
+// UpdateTaskTags adds and removes a task's tags via the remote API, returning its tags
+func (c *APIClient) UpdateTaskTags(taskID string, add, remove []string) ([]string, error) {
+	body := map[string][]string{"add": add, "remove": remove}
+	resp, err := c.doRequest("POST", "/api/tasks/"+url.PathEscape(taskID)+"/tags", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result.Tags, nil
+}
@@ -0,0 +1,324 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// dueHour is when due dates typed without a time fall
+const dueHour = 17
+
+// Fields of the task form, in tab order
+const (
+	formTitle = iota
+	formDue
+	formImpact
+	formUrgency
+	formEffort
+	formProject
+	formFieldCount
+)
+
+var formLabels = [formFieldCount]string{"Title", "Due", "Impact", "Urgency", "Effort", "Project"}
+
+// taskForm holds the state of the new task form opened with n, or the edit form opened with e
+type taskForm struct {
+	task   *db.Task // Task being edited (nil = new task)
+	inputs [formFieldCount]textinput.Model
+	focus  int
+	saving bool
+	err    error
+}
+
+type taskSavedMsg struct {
+	task   *db.Task
+	before *planner.TaskInput // Fields before an edit, for undo (nil = new task)
+	err    error
+}
+
+// IsEditingTask reports whether the tasks view is showing the new or edit task form
+func (m TasksModel) IsEditingTask() bool {
+	return m.taskForm != nil
+}
+
+// startTaskForm opens the task form, empty for a new task or filled in from the task to edit
+func (m *TasksModel) startTaskForm(task *db.Task) tea.Cmd {
+	f := &taskForm{task: task}
+	placeholders := [formFieldCount]string{
+		"What needs doing?",
+		"YYYY-MM-DD, YYYY-MM-DD HH:MM or Mar 14 (optional)",
+		"1-5 (default 3)",
+		"1-5 (default 3, set from the due date if there is one)",
+		"S, M or L (default M)",
+		"optional",
+	}
+	for i := range f.inputs {
+		ti := textinput.New()
+		ti.Placeholder = placeholders[i]
+		ti.CharLimit = 200
+		ti.Width = 60
+		f.inputs[i] = ti
+	}
+
+	if task != nil {
+		f.inputs[formTitle].SetValue(task.Title)
+		if task.DueTS != nil {
+			f.inputs[formDue].SetValue(task.DueTS.Format("2006-01-02 15:04"))
+		}
+		if task.Impact > 0 {
+			f.inputs[formImpact].SetValue(strconv.Itoa(task.Impact))
+		}
+		if task.Urgency > 0 {
+			f.inputs[formUrgency].SetValue(strconv.Itoa(task.Urgency))
+		}
+		f.inputs[formEffort].SetValue(task.Effort)
+		f.inputs[formProject].SetValue(task.Project)
+	}
+
+	f.inputs[formTitle].Focus()
+	m.taskForm = f
+	return textinput.Blink
+}
+
+func (m *TasksModel) updateTaskForm(msg tea.KeyMsg) (*TasksModel, tea.Cmd) {
+	f := m.taskForm
+	if f.saving {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.taskForm = nil
+		return m, nil
+	case "tab", "down":
+		f.setFocus((f.focus + 1) % formFieldCount)
+		return m, nil
+	case "shift+tab", "up":
+		f.setFocus((f.focus + formFieldCount - 1) % formFieldCount)
+		return m, nil
+	case "enter":
+		if f.focus < formFieldCount-1 {
+			f.setFocus(f.focus + 1)
+			return m, nil
+		}
+		return m, m.submitTaskForm()
+	case "ctrl+s":
+		return m, m.submitTaskForm()
+	}
+
+	var cmd tea.Cmd
+	f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+	return m, cmd
+}
+
+func (f *taskForm) setFocus(field int) {
+	f.inputs[f.focus].Blur()
+	f.focus = field
+	f.inputs[f.focus].Focus()
+}
+
+// input reads the form into task fields
+func (f *taskForm) input(now time.Time) (planner.TaskInput, error) {
+	var in planner.TaskInput
+	if f.task != nil {
+		in = planner.TaskInputFrom(f.task) // Keeps the description, which the form doesn't show
+	}
+
+	in.Title = f.inputs[formTitle].Value()
+	in.Effort = f.inputs[formEffort].Value()
+	in.Project = f.inputs[formProject].Value()
+
+	in.DueTS = nil
+	if value := strings.TrimSpace(f.inputs[formDue].Value()); value != "" {
+		due, err := parseDateInput(value, now, dueHour)
+		if err != nil {
+			return in, err
+		}
+		in.DueTS = &due
+	}
+
+	for _, field := range []struct {
+		index int
+		value *int
+	}{{formImpact, &in.Impact}, {formUrgency, &in.Urgency}} {
+		*field.value = 0
+		value := strings.TrimSpace(f.inputs[field.index].Value())
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return in, fmt.Errorf("%s must be a number from 1 to 5", strings.ToLower(formLabels[field.index]))
+		}
+		*field.value = n
+	}
+
+	return in, nil
+}
+
+func (m *TasksModel) submitTaskForm() tea.Cmd {
+	f := m.taskForm
+	in, err := f.input(time.Now())
+	if err != nil {
+		f.err = err
+		return nil
+	}
+
+	f.saving = true
+	f.err = nil
+
+	var before *planner.TaskInput
+	if f.task != nil {
+		prev := planner.TaskInputFrom(f.task)
+		before = &prev
+	}
+	return m.saveTask(f.task, in, before)
+}
+
+func (m TasksModel) saveTask(task *db.Task, in planner.TaskInput, before *planner.TaskInput) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		var saved *db.Task
+		var err error
+
+		switch {
+		case task == nil && m.apiClient != nil:
+			saved, err = m.apiClient.CreateTask(in)
+		case task == nil:
+			saved, err = m.planner.CreateTask(ctx, in)
+		case m.apiClient != nil:
+			saved, err = m.apiClient.UpdateTask(task.ID, in)
+		default:
+			saved, err = m.planner.UpdateTask(ctx, task.ID, in)
+		}
+
+		return taskSavedMsg{task: saved, before: before, err: err}
+	}
+}
+
+// handleTaskSaved closes the form and reloads tasks, reopening an edited task's details
+func (m *TasksModel) handleTaskSaved(msg taskSavedMsg) tea.Cmd {
+	if msg.err != nil {
+		if m.taskForm != nil {
+			m.taskForm.saving = false
+			m.taskForm.err = msg.err
+		}
+		return nil
+	}
+
+	m.taskForm = nil
+	if msg.before != nil {
+		m.pushUndo(undoOp{kind: undoEdit, taskID: msg.task.ID, title: msg.task.Title, before: msg.before})
+		m.feedbackMessage = "✓ Saved: " + msg.task.Title
+		if m.selectedTask != nil {
+			m.openTaskID = msg.task.ID
+		}
+	} else {
+		m.feedbackMessage = "✓ Created: " + msg.task.Title
+	}
+	m.loading = true
+	return m.fetchTasks()
+}
+
+func (m *TasksModel) renderTaskForm() string {
+	f := m.taskForm
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+	header := "➕ New Task"
+	if f.task != nil {
+		header = "✏️  Edit: " + f.task.Title
+	}
+	b.WriteString(headerStyle.Render(header) + "\n\n")
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Width(10).
+		PaddingLeft(2)
+	focusedLabelStyle := labelStyle.
+		Foreground(lipgloss.Color("39")).
+		Bold(true)
+
+	for i, input := range f.inputs {
+		style := labelStyle
+		if i == f.focus {
+			style = focusedLabelStyle
+		}
+		b.WriteString(style.Render(formLabels[i]) + input.View() + "\n")
+	}
+
+	if f.err != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(1, 1, 0, 1)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", f.err)) + "\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+
+	if f.saving {
+		b.WriteString(helpStyle.Render("Saving..."))
+		return b.String()
+	}
+
+	b.WriteString(helpStyle.Render("tab/↑/↓: move between fields | enter on the last field or ctrl+s: save | esc: cancel"))
+	return b.String()
+}
+
+// taskRequestBody is the API body setting every field of a task
+func taskRequestBody(in planner.TaskInput) map[string]interface{} {
+	due := ""
+	if in.DueTS != nil {
+		due = in.DueTS.Format(time.RFC3339)
+	}
+	return map[string]interface{}{
+		"title":       in.Title,
+		"description": in.Description,
+		"due_ts":      due,
+		"impact":      in.Impact,
+		"urgency":     in.Urgency,
+		"effort":      in.Effort,
+		"project":     in.Project,
+		"stakeholder": in.Stakeholder,
+	}
+}
+
+// CreateTask creates a task via the remote API
+func (c *APIClient) CreateTask(in planner.TaskInput) (*db.Task, error) {
+	return c.postTask("/api/tasks", in)
+}
+
+// UpdateTask edits a task via the remote API
+func (c *APIClient) UpdateTask(taskID string, in planner.TaskInput) (*db.Task, error) {
+	return c.postTask("/api/tasks/"+url.PathEscape(taskID)+"/edit", in)
+}
+
+func (c *APIClient) postTask(path string, in planner.TaskInput) (*db.Task, error) {
+	resp, err := c.doRequest("POST", path, taskRequestBody(in))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var task TaskResponse
+	if err := json.NewDecoder(resp.Body).Decode(&task); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return task.toTask(), nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
@@ -12,7 +13,6 @@ import (
 	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/alexrabarts/focus-agent/internal/db"
 	"github.com/alexrabarts/focus-agent/internal/planner"
-	"github.com/alexrabarts/focus-agent/internal/scoring"
 )
 
 type TasksModel struct {
@@ -20,17 +20,34 @@ type TasksModel struct {
 	planner             *planner.Planner
 	apiClient           *APIClient
 	tasks               []*db.Task
+	allTasks            []*db.Task // Loaded tasks before the source filter
+	sourceFilter        string     // Only show tasks from this source ("" = all)
+	tagFilter           string     // Only show tasks with this tag ("" = all)
 	cursor              int
 	loading             bool
 	err                 error
-	lastCompletedTaskID string
+	undo                []undoOp // Operations u can reverse, most recent last
 	selectedTask        *db.Task // Currently selected task for detail view
 	detailScroll        int      // Scroll position in detail view
 	maxScroll           int      // Maximum scroll position for current task
 	viewport            viewport.Model
 	ready               bool
-	feedbackMessage     string   // Feedback confirmation message
-	feedbackMessageTime int      // Ticks since feedback message shown
+	feedbackMessage     string                    // Feedback confirmation message
+	feedbackMessageTime int                       // Ticks since feedback message shown
+	review              *projectReview            // Active "close project" review, if any
+	digest              *digestReview             // Open weekly digest, if any
+	timeBlocks          *timeBlockReview          // Open time blocks view, if any
+	tagEditor           *tagEditor                // Open tag prompt, if any
+	snooze              *snoozePicker             // Open snooze picker, if any
+	taskForm            *taskForm                 // Open new or edit task form, if any
+	fieldEditor         *fieldEditor              // Open single-field correction prompt, if any
+	handoff             *handoffPrompt            // Open handoff prompt, if any
+	matrix              *eisenhowerMatrix         // Open Eisenhower matrix, if any
+	openTaskID          string                    // Task to show once tasks load, e.g. from a Chat button
+	setup               *db.SetupProgress         // First-run checklist, shown while there are no tasks
+	scoring             *planner.Scoring          // Scoring profile in use, for the score breakdown
+	work                *db.WorkSession           // Running work timer, if any
+	efforts             map[string]*db.TaskEffort // Time tracked on each task, by task ID
 }
 
 type tasksLoadedMsg struct {
@@ -42,6 +59,8 @@ type feedbackSubmittedMsg struct {
 	success bool
 	err     error
 	vote    int
+	taskID  string
+	score   float64 // The task's score after rescoring
 }
 
 func NewTasksModel(database *db.DB, planner *planner.Planner, apiClient *APIClient) TasksModel {
@@ -62,7 +81,7 @@ func (m *TasksModel) SetSize(width, height int) {
 }
 
 func (m TasksModel) fetchTasks() tea.Cmd {
-	return func() tea.Msg {
+	fetch := func() tea.Msg {
 		var tasks []*db.Task
 		var err error
 
@@ -76,6 +95,7 @@ func (m TasksModel) fetchTasks() tea.Cmd {
 
 		return tasksLoadedMsg{tasks: tasks, err: err}
 	}
+	return tea.Batch(fetch, fetchScoring(m.planner, m.apiClient), m.fetchWork())
 }
 
 func (m *TasksModel) Update(msg tea.Msg) (*TasksModel, tea.Cmd) {
@@ -87,7 +107,18 @@ func (m *TasksModel) Update(msg tea.Msg) (*TasksModel, tea.Cmd) {
 	case tasksLoadedMsg:
 		m.loading = false
 		m.err = msg.err
-		m.tasks = msg.tasks
+		m.allTasks = msg.tasks
+		m.applySourceFilter()
+		if m.openTaskID != "" {
+			m.openTask(m.openTaskID)
+			m.openTaskID = ""
+		}
+		return m, nil
+
+	case scoringLoadedMsg:
+		if msg.err == nil && msg.scoring != nil {
+			m.scoring = msg.scoring
+		}
 		return m, nil
 
 	case feedbackSubmittedMsg:
@@ -98,12 +129,153 @@ func (m *TasksModel) Update(msg tea.Msg) (*TasksModel, tea.Cmd) {
 			if msg.vote == -1 {
 				voteText = "↓ priority too high"
 			}
-			m.feedbackMessage = fmt.Sprintf("✓ Feedback submitted: %s", voteText)
+			m.feedbackMessage = fmt.Sprintf("✓ Feedback submitted: %s (now %.0f)", voteText, msg.score)
 		}
 		m.feedbackMessageTime = 0
+		if msg.err != nil {
+			return m, nil
+		}
+		// Feedback shifts the scores of similar tasks too, so reload the ranking
+		if m.selectedTask != nil {
+			m.openTaskID = msg.taskID
+		}
+		m.loading = true
+		return m, m.fetchTasks()
+
+	case projectTasksLoadedMsg:
+		if m.review != nil && m.review.project == msg.project {
+			m.review.loading = false
+			m.review.err = msg.err
+			m.review.tasks = msg.tasks
+			// Closing a project affects every open task by default
+			for _, task := range msg.tasks {
+				m.review.selected[task.ID] = true
+			}
+		}
+		return m, nil
+
+	case projectClosedMsg:
+		if msg.err != nil {
+			if m.review != nil {
+				m.review.err = msg.err
+			}
+			return m, nil
+		}
+		m.review = nil
+		m.selectedTask = nil
+		if msg.action == planner.ProjectCloseRehome {
+			m.feedbackMessage = fmt.Sprintf("✓ Moved %d task(s) from %s to %s", msg.count, msg.project, msg.target)
+		} else {
+			m.feedbackMessage = fmt.Sprintf("✓ Closed %s: completed %d task(s)", msg.project, msg.count)
+		}
+		m.loading = true
+		return m, m.fetchTasks()
+
+	case digestLoadedMsg:
+		if m.digest != nil {
+			m.digest.loading = false
+			m.digest.err = msg.err
+			m.digest.tasks = msg.tasks
+			m.digest.threads = msg.threads
+		}
+		return m, nil
+
+	case digestArchivedMsg:
+		if msg.err != nil {
+			if m.digest != nil {
+				m.digest.archiving = false
+				m.digest.err = msg.err
+			}
+			return m, nil
+		}
+		m.digest = nil
+		m.feedbackMessage = fmt.Sprintf("✓ Archived %d task(s) and %d thread(s) from the digest", msg.tasks, msg.threads)
+		m.loading = true
+		return m, m.fetchTasks()
+
+	case timeBlocksLoadedMsg:
+		if m.timeBlocks != nil {
+			m.timeBlocks.loading = false
+			m.timeBlocks.err = msg.err
+			m.timeBlocks.blocks = msg.blocks
+		}
+		return m, nil
+
+	case timeBlockUpdatedMsg:
+		m.handleTimeBlockUpdated(msg)
+		return m, nil
+
+	case dayPlanCommittedMsg:
+		m.handleDayPlanCommitted(msg)
+		return m, nil
+
+	case tagsUpdatedMsg:
+		m.handleTagsUpdated(msg)
+		return m, nil
+
+	case taskSnoozedMsg:
+		return m, m.handleTaskSnoozed(msg)
+
+	case taskUndoneMsg:
+		return m, m.handleTaskUndone(msg)
+
+	case taskSavedMsg:
+		return m, m.handleTaskSaved(msg)
+
+	case taskCorrectedMsg:
+		return m, m.handleTaskCorrected(msg)
+
+	case taskHandedOffMsg:
+		return m, m.handleTaskHandedOff(msg)
+
+	case workloadLoadedMsg:
+		// Delegation suggestions for the handoff prompt; failing to load them just leaves them out
+		if m.handoff != nil && msg.err == nil {
+			m.handoff.suggested = msg.suggested
+		}
 		return m, nil
 
 	case tea.KeyMsg:
+		// Project close review takes over all keys while open
+		if m.review != nil {
+			return m.updateProjectReview(msg)
+		}
+
+		// So does the weekly digest
+		if m.digest != nil {
+			return m.updateDigest(msg)
+		}
+
+		// And the time blocks view
+		if m.timeBlocks != nil {
+			return m.updateTimeBlocks(msg)
+		}
+
+		// And the tag prompt
+		if m.tagEditor != nil {
+			return m.updateTagEditor(msg)
+		}
+
+		// And the snooze picker
+		if m.snooze != nil {
+			return m.updateSnooze(msg)
+		}
+
+		// And the task form
+		if m.taskForm != nil {
+			return m.updateTaskForm(msg)
+		}
+
+		// And the correction prompt
+		if m.fieldEditor != nil {
+			return m.updateFieldEditor(msg)
+		}
+
+		// And the handoff prompt
+		if m.handoff != nil {
+			return m.updateHandoff(msg)
+		}
+
 		// If in detail view, handle detail-specific keys
 		if m.selectedTask != nil {
 			switch msg.String() {
@@ -138,10 +310,46 @@ func (m *TasksModel) Update(msg tea.Msg) (*TasksModel, tea.Cmd) {
 			case "c":
 				// Complete task from detail view
 				task := m.selectedTask
-				m.lastCompletedTaskID = task.ID
+				m.pushUndo(undoOp{kind: undoComplete, taskID: task.ID, title: task.Title})
 				m.selectedTask = nil // Return to list
 				m.detailScroll = 0
 				return m, m.completeTask(task)
+			case "x":
+				// Delete task from detail view
+				task := m.selectedTask
+				m.selectedTask = nil // Return to list
+				m.detailScroll = 0
+				return m, m.deleteTask(task)
+			case "p":
+				// Close this task's project
+				return m, m.startProjectReview(m.selectedTask)
+			case "t":
+				// Add or remove tags
+				return m, m.startTagEditor(m.selectedTask)
+			case "s":
+				// Snooze this task
+				m.startSnooze(m.selectedTask)
+			case "e":
+				// Edit this task
+				return m, m.startTaskForm(m.selectedTask)
+			case "d":
+				// Correct the extracted due date
+				return m, m.startFieldEditor(m.selectedTask, db.CorrectionDue)
+			case "i":
+				// Correct the extracted impact
+				return m, m.startFieldEditor(m.selectedTask, db.CorrectionImpact)
+			case "w":
+				// Correct the extracted stakeholder
+				return m, m.startFieldEditor(m.selectedTask, db.CorrectionStakeholder)
+			case "H":
+				// Hand this task off to a colleague
+				return m, m.startHandoff(m.selectedTask)
+			case "F":
+				// Start or stop timing work on this task
+				return m, m.toggleWork(m.selectedTask)
+			case "!":
+				// Log an interruption to the running work session
+				return m, m.interruptWork()
 			case "+", "=":
 				// Priority too low - should be higher
 				return m, m.submitFeedback(m.selectedTask, 1, "")
@@ -152,6 +360,11 @@ func (m *TasksModel) Update(msg tea.Msg) (*TasksModel, tea.Cmd) {
 			return m, nil
 		}
 
+		// The Eisenhower matrix replaces the list while open
+		if m.matrix != nil {
+			return m.updateMatrix(msg)
+		}
+
 		// List view key handling
 		switch msg.String() {
 		case "up", "k":
@@ -172,16 +385,79 @@ func (m *TasksModel) Update(msg tea.Msg) (*TasksModel, tea.Cmd) {
 			// Complete task from list view
 			if m.cursor < len(m.tasks) {
 				task := m.tasks[m.cursor]
-				m.lastCompletedTaskID = task.ID // Store for undo
+				m.pushUndo(undoOp{kind: undoComplete, taskID: task.ID, title: task.Title})
 				return m, m.completeTask(task)
 			}
+		case "n":
+			// Add a task by hand
+			return m, m.startTaskForm(nil)
+		case "e":
+			// Edit the selected task
+			if m.cursor < len(m.tasks) {
+				return m, m.startTaskForm(m.tasks[m.cursor])
+			}
+		case "x":
+			// Delete the selected task
+			if m.cursor < len(m.tasks) {
+				return m, m.deleteTask(m.tasks[m.cursor])
+			}
 		case "u":
-			// Undo last completion
-			if m.lastCompletedTaskID != "" {
-				taskID := m.lastCompletedTaskID
-				m.lastCompletedTaskID = "" // Clear undo state
-				return m, m.uncompleteTask(taskID)
+			// Undo the last task operation
+			return m, m.popUndo()
+		case "p":
+			// Close the selected task's project
+			if m.cursor < len(m.tasks) {
+				return m, m.startProjectReview(m.tasks[m.cursor])
+			}
+		case "D":
+			// Open the weekly "everything else" digest
+			return m, m.startDigest()
+		case "B":
+			// Plan the day: accept, adjust or decline each focus block, then commit
+			return m, m.startTimeBlocks()
+		case "M":
+			// Triage pending tasks in the Eisenhower matrix
+			m.toggleMatrix()
+		case "s":
+			// Snooze the selected task
+			if m.cursor < len(m.tasks) {
+				m.startSnooze(m.tasks[m.cursor])
+			}
+		case "S":
+			// Cycle the source filter
+			m.setSourceFilter(m.nextSourceFilter())
+		case "T":
+			// Cycle the tag filter
+			m.tagFilter = m.nextTagFilter()
+			m.applySourceFilter()
+		case "t":
+			// Add or remove tags on the selected task
+			if m.cursor < len(m.tasks) {
+				return m, m.startTagEditor(m.tasks[m.cursor])
+			}
+		case "+", "=":
+			// The selected task ranks too low
+			if m.cursor < len(m.tasks) {
+				return m, m.submitFeedback(m.tasks[m.cursor], 1, "")
+			}
+		case "-", "_":
+			// The selected task ranks too high
+			if m.cursor < len(m.tasks) {
+				return m, m.submitFeedback(m.tasks[m.cursor], -1, "")
+			}
+		case "H":
+			// Hand the selected task off to a colleague
+			if m.cursor < len(m.tasks) {
+				return m, m.startHandoff(m.tasks[m.cursor])
 			}
+		case "F":
+			// Start or stop timing work on the selected task
+			if m.cursor < len(m.tasks) {
+				return m, m.toggleWork(m.tasks[m.cursor])
+			}
+		case "!":
+			// Log an interruption to the running work session
+			return m, m.interruptWork()
 		case "r":
 			// Refresh tasks
 			m.loading = true
@@ -192,53 +468,107 @@ func (m *TasksModel) Update(msg tea.Msg) (*TasksModel, tea.Cmd) {
 	return m, vpCmd
 }
 
-func (m TasksModel) completeTask(task *db.Task) tea.Cmd {
-	return func() tea.Msg {
-		var err error
+// setSourceFilter shows only tasks from one source ("" shows all)
+func (m *TasksModel) setSourceFilter(source string) {
+	m.sourceFilter = source
+	m.applySourceFilter()
+}
 
-		if m.apiClient != nil {
-			// Use remote API
-			err = m.apiClient.CompleteTask(task.ID)
-		} else {
-			// Use local planner
-			ctx := context.Background()
-			err = m.planner.CompleteTask(ctx, task.ID)
+// applySourceFilter rebuilds the visible task list from the loaded tasks, applying the source
+// and tag filters
+func (m *TasksModel) applySourceFilter() {
+	if m.sourceFilter == "" && m.tagFilter == "" {
+		m.tasks = m.allTasks
+	} else {
+		m.tasks = nil
+		for _, task := range m.allTasks {
+			if m.sourceFilter != "" && task.Source != m.sourceFilter {
+				continue
+			}
+			if m.tagFilter != "" && !hasTag(task, m.tagFilter) {
+				continue
+			}
+			m.tasks = append(m.tasks, task)
 		}
+	}
+	if m.cursor >= len(m.tasks) {
+		m.cursor = max(len(m.tasks)-1, 0)
+	}
+}
 
-		if err != nil {
-			return tasksLoadedMsg{err: err}
+// openTask shows a loaded task's details, closing any open review and clearing a source or tag
+// filter that hides the task. It reports whether the task was found.
+func (m *TasksModel) openTask(id string) bool {
+	for _, task := range m.allTasks {
+		if task.ID != id {
+			continue
+		}
+		m.review, m.digest, m.timeBlocks, m.tagEditor, m.snooze, m.taskForm = nil, nil, nil, nil, nil, nil
+		m.fieldEditor, m.handoff = nil, nil
+		if m.tagFilter != "" && !hasTag(task, m.tagFilter) {
+			m.tagFilter = ""
+			m.applySourceFilter()
+		}
+		if m.sourceFilter != "" && task.Source != m.sourceFilter {
+			m.setSourceFilter("")
 		}
+		for i, visible := range m.tasks {
+			if visible.ID == id {
+				m.cursor = i
+			}
+		}
+		m.selectedTask = task
+		m.detailScroll = 0
+		return true
+	}
+	return false
+}
 
-		// Refetch tasks after completion
-		var tasks []*db.Task
-		if m.apiClient != nil {
-			tasks, err = m.apiClient.GetTasks()
-		} else {
-			tasks, err = m.database.GetPendingTasks(50)
+// nextSourceFilter returns the source after the current filter, cycling through
+// the sources of the loaded tasks and back to all
+func (m *TasksModel) nextSourceFilter() string {
+	var sources []string
+	seen := make(map[string]bool)
+	for _, task := range m.allTasks {
+		if !seen[task.Source] {
+			seen[task.Source] = true
+			sources = append(sources, task.Source)
 		}
+	}
+	sort.Strings(sources)
 
-		return tasksLoadedMsg{tasks: tasks, err: err}
+	if m.sourceFilter == "" {
+		if len(sources) > 0 {
+			return sources[0]
+		}
+		return ""
+	}
+	for i, source := range sources {
+		if source == m.sourceFilter && i+1 < len(sources) {
+			return sources[i+1]
+		}
 	}
+	return ""
 }
 
-func (m TasksModel) uncompleteTask(taskID string) tea.Cmd {
+func (m TasksModel) completeTask(task *db.Task) tea.Cmd {
 	return func() tea.Msg {
 		var err error
 
 		if m.apiClient != nil {
 			// Use remote API
-			err = m.apiClient.UncompleteTask(taskID)
+			err = m.apiClient.CompleteTask(task.ID)
 		} else {
 			// Use local planner
 			ctx := context.Background()
-			err = m.planner.UncompleteTask(ctx, taskID)
+			err = m.planner.CompleteTask(ctx, task.ID)
 		}
 
 		if err != nil {
 			return tasksLoadedMsg{err: err}
 		}
 
-		// Refetch tasks after uncompleting
+		// Refetch tasks after completion
 		var tasks []*db.Task
 		if m.apiClient != nil {
 			tasks, err = m.apiClient.GetTasks()
@@ -252,23 +582,28 @@ func (m TasksModel) uncompleteTask(taskID string) tea.Cmd {
 
 func (m TasksModel) submitFeedback(task *db.Task, vote int, reason string) tea.Cmd {
 	return func() tea.Msg {
+		var score float64
 		var err error
 
 		if m.apiClient != nil {
 			// Submit feedback via API
-			err = m.apiClient.SubmitFeedback(task.ID, vote, reason)
+			score, err = m.apiClient.SubmitFeedback(task.ID, vote, reason)
 		} else {
-			// Save feedback locally
-			err = scoring.SaveFeedback(m.database, task.ID, vote, reason, task.Score, task.Score)
+			// Save feedback locally, rescoring with what it teaches
+			var updated *db.Task
+			updated, err = m.planner.RecordFeedback(context.Background(), task.ID, vote, reason)
+			if err == nil {
+				score = updated.Score
+			}
 		}
 
 		if err != nil {
 			log.Printf("Failed to save feedback: %v", err)
-			return feedbackSubmittedMsg{success: false, err: err, vote: vote}
+			return feedbackSubmittedMsg{success: false, err: err, vote: vote, taskID: task.ID}
 		}
 
 		log.Printf("Feedback saved for task %s: vote=%d", task.ID, vote)
-		return feedbackSubmittedMsg{success: true, err: nil, vote: vote}
+		return feedbackSubmittedMsg{success: true, err: nil, vote: vote, taskID: task.ID, score: score}
 	}
 }
 
@@ -288,6 +623,54 @@ func (m *TasksModel) View() string {
 		return errorStyle.Render(fmt.Sprintf("Error: %v", m.err))
 	}
 
+	// Project close review replaces the list while open
+	if m.review != nil {
+		m.viewport.SetContent(m.renderProjectReview())
+		return m.viewport.View()
+	}
+
+	// As does the weekly digest
+	if m.digest != nil {
+		m.viewport.SetContent(m.renderDigest())
+		return m.viewport.View()
+	}
+
+	// And the time blocks view
+	if m.timeBlocks != nil {
+		m.viewport.SetContent(m.renderTimeBlocks())
+		return m.viewport.View()
+	}
+
+	// And the tag prompt
+	if m.tagEditor != nil {
+		m.viewport.SetContent(m.renderTagEditor())
+		return m.viewport.View()
+	}
+
+	// And the snooze picker
+	if m.snooze != nil {
+		m.viewport.SetContent(m.renderSnooze())
+		return m.viewport.View()
+	}
+
+	// And the task form
+	if m.taskForm != nil {
+		m.viewport.SetContent(m.renderTaskForm())
+		return m.viewport.View()
+	}
+
+	// And the correction prompt
+	if m.fieldEditor != nil {
+		m.viewport.SetContent(m.renderFieldEditor())
+		return m.viewport.View()
+	}
+
+	// And the handoff prompt
+	if m.handoff != nil {
+		m.viewport.SetContent(m.renderHandoff())
+		return m.viewport.View()
+	}
+
 	// If in detail view, show task detail
 	if m.selectedTask != nil {
 		content := m.renderTaskDetail()
@@ -295,15 +678,46 @@ func (m *TasksModel) View() string {
 		return m.viewport.View()
 	}
 
+	// The Eisenhower matrix replaces the list while open
+	if m.matrix != nil {
+		m.viewport.SetContent(m.renderMatrix())
+		return m.viewport.View()
+	}
+
 	if len(m.tasks) == 0 {
 		emptyStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("241")).
 			Padding(1)
+		if m.tagFilter != "" {
+			return emptyStyle.Render(fmt.Sprintf("No #%s tasks. Press T to change the tag filter.", m.tagFilter))
+		}
+		if m.sourceFilter != "" {
+			return emptyStyle.Render(fmt.Sprintf("No %s tasks. Press S to change the source filter.", m.sourceFilter))
+		}
+		if m.setup != nil && !m.setup.Done() {
+			return emptyStyle.Render("No tasks yet. They're extracted from your mail, meetings and docs as they sync.") + "\n" +
+				renderSetupChecklist(m.setup, lipgloss.NewStyle().Padding(0, 1)) + "\n" +
+				emptyStyle.Render("Sync health and any failing steps are on the About tab.")
+		}
 		return emptyStyle.Render("No tasks found. All caught up!")
 	}
 
 	var b strings.Builder
 
+	if m.sourceFilter != "" || m.tagFilter != "" {
+		filterStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("39")).
+			Padding(0, 1)
+		var filters []string
+		if m.sourceFilter != "" {
+			filters = append(filters, "Source: "+m.sourceFilter)
+		}
+		if m.tagFilter != "" {
+			filters = append(filters, "Tag: #"+m.tagFilter)
+		}
+		b.WriteString(filterStyle.Render(fmt.Sprintf("%s (%d of %d)", strings.Join(filters, " | "), len(m.tasks), len(m.allTasks))) + "\n\n")
+	}
+
 	// Group tasks by priority
 	highPriority := []*db.Task{}
 	mediumPriority := []*db.Task{}
@@ -371,12 +785,19 @@ func (m *TasksModel) View() string {
 		Padding(1, 0, 0, 1)
 
 	b.WriteString("\n")
-	helpText := "enter: view details | c: complete task | r: refresh"
-	if m.lastCompletedTaskID != "" {
-		helpText += " | u: undo"
+	helpText := "enter: view details | n: new task | e: edit | c: complete task | x: delete | s: snooze | t: tags | H: hand off | F: start/stop timer | +/-: rank up/down | p: close project | D: digest | B: plan my day | M: matrix | S: source | T: tag filter | r: refresh"
+	if len(m.undo) > 0 {
+		helpText += fmt.Sprintf(" | u: undo %s", m.undo[len(m.undo)-1].label())
 	}
 	b.WriteString(helpStyle.Render(helpText))
 
+	if m.feedbackMessage != "" {
+		messageStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("46")).
+			Padding(0, 1)
+		b.WriteString("\n" + messageStyle.Render(m.feedbackMessage))
+	}
+
 	// Set viewport content and return viewport view
 	content := b.String()
 	m.viewport.SetContent(content)
@@ -409,6 +830,15 @@ func (m TasksModel) renderTask(task *db.Task, taskNumber int, selected bool) str
 	} else if task.Source != "" {
 		meta = fmt.Sprintf(" [%s]", task.Source)
 	}
+	if task.Recurrence != "" {
+		meta += " 🔁"
+	}
+	if len(task.Tags) > 0 {
+		meta += " " + formatTags(task.Tags)
+	}
+	if task.ClaimedBy != "" {
+		meta += " 🤝 " + task.ClaimedBy
+	}
 
 	taskText := fmt.Sprintf("%s%d. %s%s - Score: %.0f%%", cursor, taskNumber, title, meta, task.Score)
 
@@ -462,6 +892,9 @@ func (m *TasksModel) renderTaskDetail() string {
 		case "google_tasks":
 			linkURL = "https://tasks.google.com"
 			linkText = "🔗 View in Google Tasks"
+		case db.TaskSourceGoogleChat:
+			linkURL = db.ChatThreadLink(task.SourceID)
+			linkText = "🔗 View in Chat"
 		}
 
 		if linkURL != "" {
@@ -479,16 +912,36 @@ func (m *TasksModel) renderTaskDetail() string {
 
 	// Due date
 	if task.DueTS != nil {
-		dueStr := task.DueTS.Format("Mon, Jan 2, 2006 15:04")
+		dueStr := task.DueTS.Format("Mon, Jan 2, 2006 15:04 MST")
 		b.WriteString(infoStyle.Render(fmt.Sprintf("Due: %s", dueStr)) + "\n")
 	}
 
+	// Recurrence
+	if task.Recurrence != "" {
+		b.WriteString(infoStyle.Render(fmt.Sprintf("Repeats: %s", db.DescribeRecurrence(task.Recurrence))) + "\n")
+	}
+
+	// Tags
+	if len(task.Tags) > 0 {
+		b.WriteString(infoStyle.Render(fmt.Sprintf("Tags: %s", formatTags(task.Tags))) + "\n")
+	}
+
+	// Delegation
+	if task.ClaimedBy != "" {
+		b.WriteString(infoStyle.Render(fmt.Sprintf("Claimed by: %s", task.ClaimedBy)) + "\n")
+	}
+
 	// Status
 	b.WriteString(infoStyle.Render(fmt.Sprintf("Status: %s", task.Status)) + "\n")
 
 	// Timestamps
 	b.WriteString(infoStyle.Render(fmt.Sprintf("Created: %s", task.CreatedAt.Format("Jan 2, 15:04"))) + "\n")
 
+	// Time tracked against the effort estimate
+	if effort := m.formatEffort(task); effort != "" {
+		b.WriteString(infoStyle.Render(effort) + "\n")
+	}
+
 	// Description if exists
 	if task.Description != "" {
 		b.WriteString("\n")
@@ -500,6 +953,13 @@ func (m *TasksModel) renderTaskDetail() string {
 		b.WriteString(descStyle.Render(task.Description) + "\n")
 	}
 
+	// Comments from the delegate
+	if len(task.Comments) > 0 {
+		b.WriteString("\n")
+		b.WriteString(infoTitleStyle.Render("🤝 Comments:") + "\n")
+		b.WriteString(renderTaskComments(task.Comments))
+	}
+
 	// Score Breakdown section
 	b.WriteString("\n")
 	scoreTitleStyle := lipgloss.NewStyle().
@@ -513,35 +973,19 @@ func (m *TasksModel) renderTaskDetail() string {
 		Foreground(lipgloss.Color("250")).
 		Padding(0, 2)
 
-	// Calculate individual components
-	impact := float64(task.Impact)
-	if impact == 0 {
-		impact = 3
-	}
-	urgency := float64(task.Urgency)
-	if urgency == 0 {
-		urgency = 3
-	}
-
-	effortFactor := 1.0
 	effortLabel := "Medium"
 	switch task.Effort {
 	case "S":
-		effortFactor = 0.5
 		effortLabel = "Small"
 	case "L":
-		effortFactor = 1.5
 		effortLabel = "Large"
 	}
 
-	stakeholderWeight := 1.0
 	stakeholderLabel := "Internal"
 	switch task.Stakeholder {
 	case "external":
-		stakeholderWeight = 1.5
 		stakeholderLabel = "External"
 	case "executive":
-		stakeholderWeight = 2.0
 		stakeholderLabel = "Executive"
 	}
 
@@ -585,12 +1029,17 @@ func (m *TasksModel) renderTaskDetail() string {
 		strategicDetail = "No strategic matches"
 	}
 
-	// Calculate contributions as percentage points (0-100 scale)
-	impactContribution := (0.2 * impact / 4.0) * 100.0
-	urgencyContribution := (0.25 * urgency / 4.0) * 100.0
-	effortContribution := (-0.1 * effortFactor / 4.0) * 100.0
-	stakeholderContribution := (0.15 * stakeholderWeight / 4.0) * 100.0
-	strategicContribution := (0.3 * strategicScore / 4.0) * 100.0
+	// Calculate contributions as percentage points (0-100 scale) with the scoring profile in use
+	weights := scoringWeights(m.scoring)
+	factors := planner.TaskScoreFactors(task, strategicScore)
+	var calibrated string // What the effort factor was calibrated from, if it was
+	if m.scoring != nil {
+		if factor, from := m.scoring.EffortCalibration.For(task.Project, task.Stakeholder); from != "" {
+			factors.Effort *= factor
+			calibrated = fmt.Sprintf(", ×%.2f for %s", factor, from)
+		}
+	}
+	contributions := factors.Contributions(weights)
 
 	// Impact description
 	impactDesc := ""
@@ -633,17 +1082,21 @@ func (m *TasksModel) renderTaskDetail() string {
 		effortDesc = "Full day+ (>4 hours)"
 	}
 
-	b.WriteString(scoreStyle.Render("Formula: 0.3×strategic + 0.25×urgency + 0.2×impact - 0.1×effort + 0.15×stakeholder") + "\n\n")
-	b.WriteString(scoreStyle.Render(fmt.Sprintf("├─ Strategic Alignment: %.1f/5 - %s (weight: 0.3) → +%.0f%%", strategicScore, strategicDetail, strategicContribution)) + "\n")
-	b.WriteString(scoreStyle.Render(fmt.Sprintf("├─ Urgency: %.0f/5 - %s (weight: 0.25) → +%.0f%%", urgency, urgencyDesc, urgencyContribution)) + "\n")
-	b.WriteString(scoreStyle.Render(fmt.Sprintf("├─ Impact: %.0f/5 - %s (weight: 0.2) → +%.0f%%", impact, impactDesc, impactContribution)) + "\n")
-	b.WriteString(scoreStyle.Render(fmt.Sprintf("├─ Effort: %s - %s (%.1f, weight: -0.1) → %.0f%%", effortLabel, effortDesc, effortFactor, effortContribution)) + "\n")
+	formula := "Formula: " + weights.String()
+	if m.scoring != nil {
+		formula += fmt.Sprintf(" (%s profile)", m.scoring.Profile)
+	}
+	b.WriteString(scoreStyle.Render(formula) + "\n\n")
+	b.WriteString(scoreStyle.Render(fmt.Sprintf("├─ Strategic Alignment: %.1f/5 - %s (weight: %g) → +%.0f%%", factors.Strategic, strategicDetail, weights.Strategic, contributions.Strategic)) + "\n")
+	b.WriteString(scoreStyle.Render(fmt.Sprintf("├─ Urgency: %.0f/5 - %s (weight: %g) → +%.0f%%", factors.Urgency, urgencyDesc, weights.Urgency, contributions.Urgency)) + "\n")
+	b.WriteString(scoreStyle.Render(fmt.Sprintf("├─ Impact: %.0f/5 - %s (weight: %g) → +%.0f%%", factors.Impact, impactDesc, weights.Impact, contributions.Impact)) + "\n")
+	b.WriteString(scoreStyle.Render(fmt.Sprintf("├─ Effort: %s - %s (%.1f%s, weight: -%g) → %.0f%%", effortLabel, effortDesc, factors.Effort, calibrated, weights.Effort, contributions.Effort)) + "\n")
 
 	// Show stakeholder detail if there is one
 	if task.Stakeholder != "" {
-		b.WriteString(scoreStyle.Render(fmt.Sprintf("└─ Stakeholder: %s - %s (%.1f, weight: 0.15) → +%.0f%%", stakeholderLabel, task.Stakeholder, stakeholderWeight, stakeholderContribution)) + "\n")
+		b.WriteString(scoreStyle.Render(fmt.Sprintf("└─ Stakeholder: %s - %s (%.1f, weight: %g) → +%.0f%%", stakeholderLabel, task.Stakeholder, factors.Stakeholder, weights.Stakeholder, contributions.Stakeholder)) + "\n")
 	} else {
-		b.WriteString(scoreStyle.Render(fmt.Sprintf("└─ Stakeholder: %s (%.1f, weight: 0.15) → +%.0f%%", stakeholderLabel, stakeholderWeight, stakeholderContribution)) + "\n")
+		b.WriteString(scoreStyle.Render(fmt.Sprintf("└─ Stakeholder: %s (%.1f, weight: %g) → +%.0f%%", stakeholderLabel, factors.Stakeholder, weights.Stakeholder, contributions.Stakeholder)) + "\n")
 	}
 	b.WriteString(scoreStyle.Render("──────────────────────────────────────────") + "\n")
 	b.WriteString(scoreStyle.Render(fmt.Sprintf("Total Score: %.0f%%", task.Score)) + "\n")
@@ -807,6 +1260,11 @@ func (m *TasksModel) renderTaskDetail() string {
 		}
 	}
 
+	// Chat context section (for tasks extracted from Google Chat)
+	if task.Source == db.TaskSourceGoogleChat && task.SourceID != "" {
+		b.WriteString(m.renderChatContext(task))
+	}
+
 	// Priority Feedback section
 	b.WriteString("\n")
 	feedbackTitleStyle := lipgloss.NewStyle().
@@ -849,9 +1307,9 @@ func (m *TasksModel) renderTaskDetail() string {
 	}
 
 	// Updated help text with feedback keys
-	helpText := "↑/↓: prev/next task | c: complete | +/-: feedback | esc/q: back to list"
+	helpText := "↑/↓: prev/next task | e: edit | d/i/w: fix due/impact/stakeholder | c: complete | x: delete | s: snooze | t: tags | H: hand off | F: start/stop timer | p: close project | +/-: feedback | esc/q: back to list"
 	if m.maxScroll > 5 {
-		helpText = "↑/↓: scroll then navigate | c: complete | +/-: feedback | esc/q: back"
+		helpText = "↑/↓: scroll then navigate | e: edit | d/i/w: fix due/impact/stakeholder | c: complete | x: delete | s: snooze | t: tags | H: hand off | F: start/stop timer | +/-: feedback | esc/q: back"
 	}
 	b.WriteString(helpStyle.Render(helpText))
 
@@ -1,7 +1,6 @@
 package tui
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,15 +11,16 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss/v2"
 	"github.com/alexrabarts/focus-agent/internal/db"
-	"github.com/alexrabarts/focus-agent/internal/front"
+	"github.com/alexrabarts/focus-agent/internal/planner"
 )
 
 type ThreadsModel struct {
 	database       *db.DB
+	planner        *planner.Planner
 	apiClient      *APIClient
-	front          *front.Client
 	threads        []*db.Thread
-	messages       map[string][]*db.Message // thread ID -> messages
+	messages       map[string][]*db.Message       // thread ID -> messages
+	summaryChanges map[string][]*db.SummaryChange // thread ID -> summary changes, newest first
 	cursor         int
 	offset         int // For scrolling
 	loading        bool
@@ -29,6 +29,12 @@ type ThreadsModel struct {
 	detailScroll   int        // Scroll position in detail view
 	viewport       viewport.Model
 	ready          bool
+	search         SearchModel             // Search mode ("/")
+	waiting        *waitingList            // Open waiting-on list ("w"), if any
+	draft          *replyDraft             // Reply being drafted for the selected thread ("d"), if any
+	frontMenu      *frontMenu              // Open Front action menu for the selected thread ("F"), if any
+	frontThreads   map[string]*frontThread // thread ID -> Front conversation, in remote mode
+	frontMessage   string                  // Result of the last Front action
 }
 
 type threadsLoadedMsg struct {
@@ -36,17 +42,58 @@ type threadsLoadedMsg struct {
 	err     error
 }
 
-func NewThreadsModel(database *db.DB, apiClient *APIClient, frontClient *front.Client) ThreadsModel {
+func NewThreadsModel(database *db.DB, plannerService *planner.Planner, apiClient *APIClient) ThreadsModel {
 	return ThreadsModel{
-		database:  database,
-		apiClient: apiClient,
-		front:     frontClient,
-		messages:  make(map[string][]*db.Message),
-		loading:   true,
-		viewport:  viewport.New(80, 20),
+		database:       database,
+		planner:        plannerService,
+		apiClient:      apiClient,
+		messages:       make(map[string][]*db.Message),
+		summaryChanges: make(map[string][]*db.SummaryChange),
+		frontThreads:   make(map[string]*frontThread),
+		loading:        true,
+		viewport:       viewport.New(80, 20),
+		search:         NewSearchModel(plannerService, apiClient),
 	}
 }
 
+// IsSearching reports whether the tab is in search mode, where keys go to the search
+func (m ThreadsModel) IsSearching() bool {
+	return m.search.active
+}
+
+// openSearchResult opens the thread of a thread, message or email task found by search in the
+// detail view, or anything else with a link in the browser
+func (m ThreadsModel) openSearchResult(result *db.SearchResult) ThreadsModel {
+	threadID := result.ThreadID
+	if result.Kind == db.ContentKindThread {
+		threadID = result.ID
+	}
+	if threadID == "" {
+		if result.Link != "" {
+			openBrowser(result.Link)
+		}
+		return m
+	}
+
+	for _, thread := range m.threads {
+		if thread.ID == threadID {
+			m.selectedThread = thread
+			m.detailScroll = 0
+			return m
+		}
+	}
+
+	thread := &db.Thread{ID: threadID, Summary: result.Snippet}
+	if m.database != nil {
+		if stored, err := m.database.GetThreadByID(threadID); err == nil && stored != nil {
+			thread = stored
+		}
+	}
+	m.selectedThread = thread
+	m.detailScroll = 0
+	return m
+}
+
 // SetSize updates the viewport dimensions
 func (m *ThreadsModel) SetSize(width, height int) {
 	m.viewport.Width = width
@@ -82,9 +129,78 @@ func (m ThreadsModel) Update(msg tea.Msg) (ThreadsModel, tea.Cmd) {
 		m.threads = msg.threads
 		return m, nil
 
+	case searchResultsMsg:
+		m.search, _, _ = m.search.Update(msg)
+		return m, nil
+
+	case waitingLoadedMsg:
+		if m.waiting != nil {
+			m.waiting.loading = false
+			m.waiting.err = msg.err
+			m.waiting.items = msg.items
+			if m.waiting.cursor >= len(msg.items) {
+				m.waiting.cursor = max(len(msg.items)-1, 0)
+			}
+		}
+		return m, nil
+
+	case waitingDismissedMsg:
+		if m.waiting != nil {
+			if msg.err != nil {
+				m.waiting.err = msg.err
+				return m, nil
+			}
+			return m, m.fetchWaiting()
+		}
+		return m, nil
+
+	case personasLoadedMsg:
+		if m.draft != nil {
+			m.draft.err = msg.err
+			if msg.err == nil {
+				m.draft.personas = append([]string{""}, msg.personas...)
+			}
+		}
+		return m, nil
+
+	case replyDraftedMsg:
+		if m.draft != nil {
+			m.draft.drafting = false
+			m.draft.err = msg.err
+			if msg.err == nil {
+				m.editDraft(msg.draft)
+			}
+		}
+		return m, nil
+
+	case replySavedMsg:
+		if m.draft != nil {
+			m.draft.saving = false
+			m.draft.err = msg.err
+			if msg.err == nil {
+				m.draft.done = "Saved to Gmail Drafts"
+				if msg.sent {
+					m.draft.done = "Sent to " + m.draft.draft.To
+				}
+			}
+		}
+		return m, nil
+
+	case frontActionMsg, frontTeammatesMsg, frontCommentDraftedMsg:
+		return m.handleFrontMsg(msg)
+
 	case tea.KeyMsg:
+		if m.draft != nil {
+			return m.updateDraft(msg)
+		}
+
+		if m.frontMenu != nil {
+			return m.updateFrontMenu(msg)
+		}
+
 		// If in detail view, handle detail-specific keys
 		if m.selectedThread != nil {
+			m.frontMessage = ""
 			switch msg.String() {
 			case "esc", "q":
 				// Return to list view
@@ -97,31 +213,46 @@ func (m ThreadsModel) Update(msg tea.Msg) (ThreadsModel, tea.Cmd) {
 				}
 			case "down", "j":
 				m.detailScroll++
+			case "d":
+				// Draft a reply in a persona
+				return m, m.startDraft()
 			case "o":
 				// Open in Front (if Front metadata exists)
-				if m.database != nil {
-					if frontMetadata, err := m.database.GetFrontMetadata(m.selectedThread.ID); err == nil {
-						frontURL := fmt.Sprintf("https://app.frontapp.com/open/%s", frontMetadata.ConversationID)
-						// Use xdg-open on Linux, open on macOS
-						openBrowser(frontURL)
-					}
+				if frontMetadata, _ := m.frontThread(m.selectedThread.ID); frontMetadata != nil {
+					frontURL := fmt.Sprintf("https://app.frontapp.com/open/%s", frontMetadata.ConversationID)
+					// Use xdg-open on Linux, open on macOS
+					openBrowser(frontURL)
 				}
 			case "a":
 				// Archive conversation in Front
-				if m.front != nil && m.database != nil {
-					if frontMetadata, err := m.database.GetFrontMetadata(m.selectedThread.ID); err == nil {
-						ctx := context.Background()
-						if err := m.front.Archive(ctx, frontMetadata.ConversationID); err == nil {
-							// Update local status
-							frontMetadata.Status = "archived"
-							m.database.SaveFrontMetadata(frontMetadata)
-						}
-					}
+				if frontMetadata, _ := m.frontThread(m.selectedThread.ID); frontMetadata != nil {
+					m.startFrontMenu()
+					m.frontMenu.working = true
+					return m, m.runFrontAction(m.selectedThread.ID, "archive", "")
+				}
+			case "F":
+				// Archive, snooze, assign or comment on the conversation in Front
+				if frontMetadata, _ := m.frontThread(m.selectedThread.ID); frontMetadata != nil {
+					m.startFrontMenu()
 				}
 			}
 			return m, nil
 		}
 
+		if m.waiting != nil {
+			return m.updateWaiting(msg)
+		}
+
+		if m.search.active {
+			var result *db.SearchResult
+			var cmd tea.Cmd
+			m.search, result, cmd = m.search.Update(msg)
+			if result != nil {
+				m = m.openSearchResult(result)
+			}
+			return m, cmd
+		}
+
 		// List view key handling
 		switch msg.String() {
 		case "up", "k":
@@ -150,6 +281,11 @@ func (m ThreadsModel) Update(msg tea.Msg) (ThreadsModel, tea.Cmd) {
 			// Refresh threads
 			m.loading = true
 			return m, m.fetchThreads()
+		case "/":
+			return m, m.search.Start()
+		case "w":
+			// Requests you sent that are waiting on a reply
+			return m, m.startWaiting()
 		}
 	}
 
@@ -172,6 +308,16 @@ func (m ThreadsModel) View() string {
 		return errorStyle.Render(fmt.Sprintf("Error: %v", m.err))
 	}
 
+	if m.draft != nil {
+		m.viewport.SetContent(m.renderDraft())
+		return m.viewport.View()
+	}
+
+	if m.frontMenu != nil {
+		m.viewport.SetContent(m.renderFrontMenu())
+		return m.viewport.View()
+	}
+
 	// If in detail view, show thread detail
 	if m.selectedThread != nil {
 		content := m.renderThreadDetail()
@@ -179,11 +325,21 @@ func (m ThreadsModel) View() string {
 		return m.viewport.View()
 	}
 
+	if m.search.active {
+		m.viewport.SetContent(m.search.View())
+		return m.viewport.View()
+	}
+
+	if m.waiting != nil {
+		m.viewport.SetContent(m.renderWaiting())
+		return m.viewport.View()
+	}
+
 	if len(m.threads) == 0 {
 		emptyStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("241")).
 			Padding(1)
-		return emptyStyle.Render("No threads with summaries found.")
+		return emptyStyle.Render("No summarized threads yet. Threads show up here once Gmail syncs and the AI\nsummarizes them; the Queue tab lists any still waiting.")
 	}
 
 	var b strings.Builder
@@ -221,7 +377,7 @@ func (m ThreadsModel) View() string {
 		Padding(1, 0, 0, 1)
 
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("↑/↓: navigate | enter: view details | r: refresh"))
+	b.WriteString(helpStyle.Render("↑/↓: navigate | enter: view details | /: search | w: waiting on | r: refresh"))
 
 	content := b.String()
 	m.viewport.SetContent(content)
@@ -356,14 +512,7 @@ func (m ThreadsModel) renderThreadDetail() string {
 	b.WriteString(fmt.Sprintf("  \x1b[38;5;39m\x1b[4m%s\x1b[0m\n", hyperlink))
 
 	// Front metadata if available
-	var frontMetadata *db.FrontMetadata
-	var frontComments []*db.FrontComment
-	if m.database != nil {
-		frontMetadata, _ = m.database.GetFrontMetadata(m.selectedThread.ID)
-		frontComments, _ = m.database.GetFrontComments(m.selectedThread.ID)
-	} else if m.apiClient != nil {
-		// TODO: Add API endpoints to fetch Front data
-	}
+	frontMetadata, frontComments := m.frontThread(m.selectedThread.ID)
 
 	if frontMetadata != nil {
 		frontStyle := lipgloss.NewStyle().
@@ -400,6 +549,7 @@ func (m ThreadsModel) renderThreadDetail() string {
 
 	b.WriteString(summaryTitleStyle.Render("🤖 AI Summary:") + "\n")
 	b.WriteString(summaryStyle.Render(m.selectedThread.Summary) + "\n\n")
+	b.WriteString(m.renderSummaryChanges())
 
 	// Messages section
 	messagesTitleStyle := lipgloss.NewStyle().
@@ -494,15 +644,69 @@ func (m ThreadsModel) renderThreadDetail() string {
 		Padding(1, 0, 0, 1)
 
 	b.WriteString("\n")
-	helpText := "↑/↓: scroll | esc/q: back"
+	helpText := "↑/↓: scroll | d: draft reply | esc/q: back"
 	if frontMetadata != nil {
-		helpText += " | o: open in Front | a: archive"
+		helpText += " | o: open in Front | a: archive | F: Front actions"
+	}
+	if m.frontMessage != "" {
+		helpText = m.frontMessage
 	}
 	b.WriteString(helpStyle.Render(helpText))
 
 	return b.String()
 }
 
+// maxShownSummaryChanges is how many of a thread's summary changes the detail view lists
+const maxShownSummaryChanges = 3
+
+// renderSummaryChanges lists what changed the last few times the selected thread was
+// re-summarized
+func (m ThreadsModel) renderSummaryChanges() string {
+	changes, ok := m.summaryChanges[m.selectedThread.ID]
+	if !ok {
+		if m.apiClient != nil {
+			changes, _ = m.apiClient.GetSummaryChanges(m.selectedThread.ID)
+		} else {
+			changes, _ = m.database.GetSummaryChanges(m.selectedThread.ID)
+		}
+		m.summaryChanges[m.selectedThread.ID] = changes
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("214")).
+		Padding(0, 1)
+	dateStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(0, 2)
+	addedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("42")).
+		Padding(0, 3)
+	removedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")).
+		Padding(0, 3)
+
+	b.WriteString(titleStyle.Render("🔄 What Changed:") + "\n")
+	for i, change := range changes {
+		if i >= maxShownSummaryChanges {
+			break
+		}
+		b.WriteString(dateStyle.Render(change.DetectedAt.Format("Jan 2, 15:04")) + "\n")
+		for _, point := range change.Added {
+			b.WriteString(addedStyle.Render("+ "+point) + "\n")
+		}
+		for _, point := range change.Removed {
+			b.WriteString(removedStyle.Render("- "+point) + "\n")
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
 // openBrowser opens a URL in the default browser
 func openBrowser(url string) error {
 	var cmd string
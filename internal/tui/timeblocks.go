@@ -0,0 +1,460 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// blockStep is how far one keystroke moves or resizes a block while planning the day
+const blockStep = 15 * time.Minute
+
+// timeBlockReview holds the state of the time blocks view, where the day's proposed focus
+// blocks are accepted, adjusted or declined one by one and then committed as the day's plan
+type timeBlockReview struct {
+	blocks     []*db.TimeBlock
+	cursor     int
+	taskCursor int // Highlighted task in the selected block, for dropping it
+	loading    bool
+	busy       bool
+	notice     string
+	err        error
+}
+
+type timeBlocksLoadedMsg struct {
+	blocks []*db.TimeBlock
+	err    error
+}
+
+type timeBlockUpdatedMsg struct {
+	block   *db.TimeBlock
+	advance bool // Move on to the next undecided block
+	err     error
+}
+
+type dayPlanCommittedMsg struct {
+	blocks   []*db.TimeBlock
+	calendar bool
+	err      error
+}
+
+// TimeBlockResponse matches the API response structure
+type TimeBlockResponse struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Start      string   `json:"start"`
+	End        string   `json:"end"`
+	Status     string   `json:"status"`
+	Conflict   string   `json:"conflict"`
+	TaskIDs    []string `json:"task_ids"`
+	TaskTitles []string `json:"task_titles"`
+}
+
+func (r TimeBlockResponse) toTimeBlock() *db.TimeBlock {
+	start, _ := time.Parse(time.RFC3339, r.Start)
+	end, _ := time.Parse(time.RFC3339, r.End)
+	return &db.TimeBlock{
+		ID:         r.ID,
+		Name:       r.Name,
+		StartTS:    start,
+		EndTS:      end,
+		Status:     r.Status,
+		Conflict:   r.Conflict,
+		TaskIDs:    r.TaskIDs,
+		TaskTitles: r.TaskTitles,
+	}
+}
+
+// IsInTimeBlocks reports whether the tasks view is showing today's time blocks
+func (m TasksModel) IsInTimeBlocks() bool {
+	return m.timeBlocks != nil
+}
+
+// startTimeBlocks opens the time blocks view and loads today's blocks
+func (m *TasksModel) startTimeBlocks() tea.Cmd {
+	m.timeBlocks = &timeBlockReview{loading: true}
+	return m.fetchTimeBlocks(false)
+}
+
+// fetchTimeBlocks loads today's blocks, planning them first when plan is set
+func (m TasksModel) fetchTimeBlocks(plan bool) tea.Cmd {
+	return func() tea.Msg {
+		var blocks []*db.TimeBlock
+		var err error
+		switch {
+		case m.apiClient != nil:
+			blocks, err = m.apiClient.GetTimeBlocks(plan)
+		case plan:
+			blocks, err = m.planner.ScheduleTimeBlocks(context.Background())
+		default:
+			blocks, err = m.planner.GetTimeBlocks()
+		}
+		return timeBlocksLoadedMsg{blocks: blocks, err: err}
+	}
+}
+
+// updateTimeBlock accepts or declines a block, then moves on to the next one
+func (m TasksModel) updateTimeBlock(id, action string) tea.Cmd {
+	return func() tea.Msg {
+		var block *db.TimeBlock
+		var err error
+		switch {
+		case m.apiClient != nil:
+			block, err = m.apiClient.UpdateTimeBlock(id, action)
+		case action == "accept":
+			block, err = m.planner.AcceptTimeBlock(id)
+		default:
+			block, err = m.planner.DeclineTimeBlock(context.Background(), id)
+		}
+		return timeBlockUpdatedMsg{block: block, advance: true, err: err}
+	}
+}
+
+// adjustTimeBlock moves or resizes a block, or drops a task from it
+func (m TasksModel) adjustTimeBlock(id string, change planner.TimeBlockChange) tea.Cmd {
+	return func() tea.Msg {
+		var block *db.TimeBlock
+		var err error
+		if m.apiClient != nil {
+			block, err = m.apiClient.AdjustTimeBlock(id, change)
+		} else {
+			block, err = m.planner.AdjustTimeBlock(context.Background(), id, change)
+		}
+		return timeBlockUpdatedMsg{block: block, err: err}
+	}
+}
+
+// commitDayPlan finishes planning, writing the accepted blocks to the calendar when calendar is set
+func (m TasksModel) commitDayPlan(calendar bool) tea.Cmd {
+	return func() tea.Msg {
+		var blocks []*db.TimeBlock
+		var err error
+		if m.apiClient != nil {
+			blocks, err = m.apiClient.CommitDayPlan(calendar)
+		} else {
+			blocks, err = m.planner.CommitDayPlan(context.Background(), calendar)
+		}
+		return dayPlanCommittedMsg{blocks: blocks, calendar: calendar, err: err}
+	}
+}
+
+func (m *TasksModel) handleTimeBlockUpdated(msg timeBlockUpdatedMsg) {
+	tb := m.timeBlocks
+	if tb == nil {
+		return
+	}
+	tb.busy = false
+	tb.err = msg.err
+	if msg.err != nil {
+		return
+	}
+	for i, block := range tb.blocks {
+		if block.ID == msg.block.ID {
+			tb.blocks[i] = msg.block
+		}
+	}
+	if tb.taskCursor >= len(msg.block.TaskIDs) {
+		tb.taskCursor = 0
+	}
+
+	if !msg.advance {
+		return
+	}
+	for i := tb.cursor + 1; i < len(tb.blocks); i++ {
+		if status := tb.blocks[i].Status; status == db.TimeBlockProposed || status == db.TimeBlockConflict {
+			tb.cursor = i
+			tb.taskCursor = 0
+			return
+		}
+	}
+}
+
+func (m *TasksModel) handleDayPlanCommitted(msg dayPlanCommittedMsg) {
+	tb := m.timeBlocks
+	if tb == nil {
+		return
+	}
+	tb.busy = false
+	tb.err = msg.err
+	if msg.blocks != nil {
+		tb.blocks = msg.blocks
+	}
+	if msg.err != nil {
+		return
+	}
+	if msg.calendar {
+		tb.notice = "✓ Plan committed to the calendar"
+	} else {
+		tb.notice = "✓ Plan committed (not on the calendar)"
+	}
+}
+
+// adjustSelected applies a change to the selected block, unless it's been declined
+func (m *TasksModel) adjustSelected(change planner.TimeBlockChange) tea.Cmd {
+	tb := m.timeBlocks
+	if tb.busy || tb.cursor >= len(tb.blocks) || tb.blocks[tb.cursor].Status == db.TimeBlockDeclined {
+		return nil
+	}
+	tb.busy = true
+	tb.notice = ""
+	return m.adjustTimeBlock(tb.blocks[tb.cursor].ID, change)
+}
+
+func (m *TasksModel) updateTimeBlocks(msg tea.KeyMsg) (*TasksModel, tea.Cmd) {
+	tb := m.timeBlocks
+
+	switch msg.String() {
+	case "esc", "q":
+		m.timeBlocks = nil
+	case "up", "k":
+		if tb.cursor > 0 {
+			tb.cursor--
+			tb.taskCursor = 0
+		}
+	case "down", "j":
+		if tb.cursor < len(tb.blocks)-1 {
+			tb.cursor++
+			tb.taskCursor = 0
+		}
+	case "P":
+		// Plan today's blocks now instead of waiting for the morning brief
+		if tb.loading || tb.busy || len(tb.blocks) > 0 {
+			return m, nil
+		}
+		tb.loading = true
+		return m, m.fetchTimeBlocks(true)
+	case "y", "enter":
+		if tb.busy || tb.cursor >= len(tb.blocks) {
+			return m, nil
+		}
+		if status := tb.blocks[tb.cursor].Status; status == db.TimeBlockAccepted || status == db.TimeBlockScheduled {
+			return m, nil
+		}
+		tb.busy = true
+		tb.notice = ""
+		return m, m.updateTimeBlock(tb.blocks[tb.cursor].ID, "accept")
+	case "n", "x":
+		if tb.busy || tb.cursor >= len(tb.blocks) || tb.blocks[tb.cursor].Status == db.TimeBlockDeclined {
+			return m, nil
+		}
+		tb.busy = true
+		tb.notice = ""
+		return m, m.updateTimeBlock(tb.blocks[tb.cursor].ID, "decline")
+	case "left", "h":
+		return m, m.adjustSelected(planner.TimeBlockChange{Shift: -blockStep})
+	case "right", "l":
+		return m, m.adjustSelected(planner.TimeBlockChange{Shift: blockStep})
+	case "+", "=":
+		return m, m.adjustSelected(planner.TimeBlockChange{Resize: blockStep})
+	case "-":
+		return m, m.adjustSelected(planner.TimeBlockChange{Resize: -blockStep})
+	case "tab":
+		// Highlight the block's next task
+		if tb.cursor < len(tb.blocks) && len(tb.blocks[tb.cursor].TaskIDs) > 0 {
+			tb.taskCursor = (tb.taskCursor + 1) % len(tb.blocks[tb.cursor].TaskIDs)
+		}
+	case "d":
+		// Take the highlighted task out of the block
+		if tb.cursor >= len(tb.blocks) || tb.taskCursor >= len(tb.blocks[tb.cursor].TaskIDs) {
+			return m, nil
+		}
+		return m, m.adjustSelected(planner.TimeBlockChange{DropTaskID: tb.blocks[tb.cursor].TaskIDs[tb.taskCursor]})
+	case "c", "s":
+		// Commit the plan: c also writes the accepted blocks to the calendar
+		if tb.busy || len(tb.blocks) == 0 {
+			return m, nil
+		}
+		tb.busy = true
+		tb.notice = ""
+		return m, m.commitDayPlan(msg.String() == "c")
+	}
+
+	return m, nil
+}
+
+func (m *TasksModel) renderTimeBlocks() string {
+	tb := m.timeBlocks
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+
+	b.WriteString(headerStyle.Render("🎯 Plan My Day - "+time.Now().Format("Monday, January 2")) + "\n\n")
+
+	if tb.loading {
+		b.WriteString("  Loading time blocks...\n")
+		return b.String()
+	}
+
+	if tb.err != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(0, 1)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", tb.err)) + "\n\n")
+	}
+
+	if tb.notice != "" {
+		noticeStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("46")).
+			Padding(0, 1)
+		b.WriteString(noticeStyle.Render(tb.notice) + "\n\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+
+	if len(tb.blocks) == 0 {
+		emptyStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")).
+			Padding(0, 1)
+		b.WriteString(emptyStyle.Render("No focus blocks planned for today.") + "\n")
+		b.WriteString(helpStyle.Render("P: plan now | esc: back"))
+		return b.String()
+	}
+
+	statusStyles := map[string]lipgloss.Style{
+		db.TimeBlockProposed:  lipgloss.NewStyle().Foreground(lipgloss.Color("214")),
+		db.TimeBlockAccepted:  lipgloss.NewStyle().Foreground(lipgloss.Color("39")),
+		db.TimeBlockScheduled: lipgloss.NewStyle().Foreground(lipgloss.Color("46")),
+		db.TimeBlockConflict:  lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
+		db.TimeBlockDeclined:  lipgloss.NewStyle().Foreground(lipgloss.Color("241")),
+	}
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	lineStyle := lipgloss.NewStyle().Padding(0, 1)
+
+	for i, block := range tb.blocks {
+		cursor := "  "
+		name := block.Name
+		if i == tb.cursor {
+			cursor = "▶ "
+			name = selectedStyle.Render(name)
+		}
+
+		line := fmt.Sprintf("%s%s  %s-%s  %s", cursor, name,
+			block.StartTS.Format("15:04"), block.EndTS.Format("15:04"),
+			statusStyles[block.Status].Render(block.Status))
+		if block.Conflict != "" {
+			line += fmt.Sprintf(" (clashes with %s)", block.Conflict)
+		}
+		b.WriteString(lineStyle.Render(line) + "\n")
+
+		for j, title := range block.TaskTitles {
+			if len(title) > 60 {
+				title = title[:57] + "..."
+			}
+			bullet := "    • "
+			if i == tb.cursor && j == tb.taskCursor {
+				bullet = "    › "
+			}
+			b.WriteString(lineStyle.Render(bullet+title) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if tb.busy {
+		b.WriteString(helpStyle.Render("Updating plan..."))
+		return b.String()
+	}
+
+	b.WriteString(helpStyle.Render("y/enter: accept | n: decline | ←/→: move 15m | +/-: longer/shorter | tab/d: pick/drop task | ↑/↓: navigate\n" +
+		"c: commit to calendar | s: commit without calendar | esc: back"))
+	return b.String()
+}
+
+// GetTimeBlocks fetches today's time blocks from the remote API, planning them first when plan is set
+func (c *APIClient) GetTimeBlocks(plan bool) ([]*db.TimeBlock, error) {
+	method := "GET"
+	if plan {
+		method = "POST"
+	}
+	resp, err := c.doRequest(method, "/api/timeblocks", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var blocks []TimeBlockResponse
+	if err := json.NewDecoder(resp.Body).Decode(&blocks); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	result := make([]*db.TimeBlock, 0, len(blocks))
+	for _, block := range blocks {
+		result = append(result, block.toTimeBlock())
+	}
+	return result, nil
+}
+
+// UpdateTimeBlock confirms, accepts or declines a time block via the remote API
+func (c *APIClient) UpdateTimeBlock(id, action string) (*db.TimeBlock, error) {
+	resp, err := c.doRequest("POST", "/api/timeblocks/"+url.PathEscape(id)+"/"+action, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var block TimeBlockResponse
+	if err := json.NewDecoder(resp.Body).Decode(&block); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return block.toTimeBlock(), nil
+}
+
+// AdjustTimeBlock moves or resizes a time block, or drops one of its tasks, via the remote API
+func (c *APIClient) AdjustTimeBlock(id string, change planner.TimeBlockChange) (*db.TimeBlock, error) {
+	reqBody := map[string]interface{}{
+		"shift_minutes":  int(change.Shift.Minutes()),
+		"resize_minutes": int(change.Resize.Minutes()),
+		"drop_task_id":   change.DropTaskID,
+	}
+
+	resp, err := c.doRequest("POST", "/api/timeblocks/"+url.PathEscape(id)+"/adjust", reqBody)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var block TimeBlockResponse
+	if err := json.NewDecoder(resp.Body).Decode(&block); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return block.toTimeBlock(), nil
+}
+
+// CommitDayPlan commits today's plan via the remote API. Blocks that couldn't be written to
+// the calendar are returned as an error alongside the plan.
+func (c *APIClient) CommitDayPlan(calendar bool) ([]*db.TimeBlock, error) {
+	resp, err := c.doRequest("POST", "/api/timeblocks/commit", map[string]bool{"calendar": calendar})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var plan struct {
+		Blocks []TimeBlockResponse `json:"blocks"`
+		Error  string              `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&plan); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	blocks := make([]*db.TimeBlock, 0, len(plan.Blocks))
+	for _, block := range plan.Blocks {
+		blocks = append(blocks, block.toTimeBlock())
+	}
+	if plan.Error != "" {
+		return blocks, fmt.Errorf("%s", plan.Error)
+	}
+	return blocks, nil
+}
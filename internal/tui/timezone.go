@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// timezoneSetMsg carries the timezone in use after switching it from the About tab
+type timezoneSetMsg struct {
+	timezone *planner.Timezone
+	err      error
+}
+
+// IsSettingTimezone reports whether the About tab is prompting for a timezone
+func (m StatsModel) IsSettingTimezone() bool {
+	return m.timezoneInput != nil
+}
+
+// startTimezoneInput opens the prompt for the timezone to switch to, filled in with the one in use
+func (m *StatsModel) startTimezoneInput() tea.Cmd {
+	ti := textinput.New()
+	ti.Placeholder = "IANA name such as Asia/Tokyo (empty switches back home)"
+	ti.CharLimit = 64
+	ti.Width = 60
+	if tz := m.stats.Timezone; tz != nil && tz.Overridden {
+		ti.SetValue(tz.Active)
+	}
+	ti.CursorEnd()
+	ti.Focus()
+
+	m.timezoneInput = &ti
+	m.timezoneErr = nil
+	return textinput.Blink
+}
+
+func (m StatsModel) updateTimezoneInput(msg tea.KeyMsg) (StatsModel, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.timezoneInput = nil
+		m.timezoneErr = nil
+		return m, nil
+	case "enter":
+		return m, m.setTimezone(strings.TrimSpace(m.timezoneInput.Value()))
+	}
+
+	input, cmd := m.timezoneInput.Update(msg)
+	m.timezoneInput = &input
+	return m, cmd
+}
+
+// setTimezone switches the timezone through the API, which also moves the scheduled briefs, or
+// through the planner, whose choice the agent picks up when it next starts
+func (m StatsModel) setTimezone(name string) tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			timezone, err := m.apiClient.SetTimezone(name)
+			return timezoneSetMsg{timezone: timezone, err: err}
+		}
+		if m.planner == nil {
+			return timezoneSetMsg{err: fmt.Errorf("timezone can't be switched without a planner")}
+		}
+		timezone, err := m.planner.SetTimezone(name)
+		return timezoneSetMsg{timezone: timezone, err: err}
+	}
+}
+
+// renderTimezone shows the timezone in use, the home timezone while travelling, and the prompt
+// for switching when it's open
+func (m StatsModel) renderTimezone(itemStyle lipgloss.Style) string {
+	var b strings.Builder
+	if tz := m.stats.Timezone; tz != nil {
+		b.WriteString(itemStyle.Render(fmt.Sprintf("Active: %s (%s)", tz.Active, tz.Zone)) + "\n")
+		if tz.Overridden {
+			b.WriteString(itemStyle.Render(fmt.Sprintf("Home: %s", tz.Home)) + "\n")
+		}
+	}
+	if m.timezoneInput != nil {
+		b.WriteString(itemStyle.Render("Switch to: "+m.timezoneInput.View()) + "\n")
+	}
+	if m.timezoneErr != nil {
+		b.WriteString(itemStyle.Render(fmt.Sprintf("✗ %v", m.timezoneErr)) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// maxUndoOps bounds the undo stack; older operations drop off the bottom
+const maxUndoOps = 20
+
+type undoKind int
+
+const (
+	undoComplete undoKind = iota
+	undoSnooze
+	undoDelete
+	undoTags
+	undoEdit
+)
+
+// undoOp records a task operation and what is needed to reverse it
+type undoOp struct {
+	kind    undoKind
+	taskID  string
+	title   string
+	dueTS   *time.Time         // Due date before a snooze (nil = none)
+	status  string             // Status before a delete
+	added   []string           // Tags a tag edit added
+	removed []string           // Tags a tag edit removed
+	before  *planner.TaskInput // Fields before an edit
+}
+
+type taskUndoneMsg struct {
+	op  undoOp
+	err error
+}
+
+// label names the operation in the footer
+func (op undoOp) label() string {
+	switch op.kind {
+	case undoComplete:
+		return "complete"
+	case undoSnooze:
+		return "snooze"
+	case undoDelete:
+		return "delete"
+	case undoEdit:
+		return "edit"
+	default:
+		return "tag edit"
+	}
+}
+
+// pushUndo records an operation that u can reverse
+func (m *TasksModel) pushUndo(op undoOp) {
+	m.undo = append(m.undo, op)
+	if len(m.undo) > maxUndoOps {
+		m.undo = m.undo[len(m.undo)-maxUndoOps:]
+	}
+}
+
+// popUndo reverses the most recent operation
+func (m *TasksModel) popUndo() tea.Cmd {
+	if len(m.undo) == 0 {
+		m.feedbackMessage = "Nothing to undo"
+		return nil
+	}
+
+	op := m.undo[len(m.undo)-1]
+	m.undo = m.undo[:len(m.undo)-1]
+	return m.undoTaskOp(op)
+}
+
+func (m TasksModel) undoTaskOp(op undoOp) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		var err error
+
+		switch op.kind {
+		case undoComplete:
+			if m.apiClient != nil {
+				err = m.apiClient.UncompleteTask(op.taskID)
+			} else {
+				err = m.planner.UncompleteTask(ctx, op.taskID)
+			}
+		case undoSnooze:
+			if m.apiClient != nil {
+				err = m.apiClient.SetTaskDue(op.taskID, op.dueTS)
+			} else {
+				err = m.planner.SetTaskDue(ctx, op.taskID, op.dueTS)
+			}
+		case undoDelete:
+			if m.apiClient != nil {
+				err = m.apiClient.RestoreTask(op.taskID, op.status)
+			} else {
+				err = m.planner.RestoreTask(ctx, op.taskID, op.status)
+			}
+		case undoTags:
+			_, err = m.updateTaskTags(op.taskID, op.removed, op.added)
+		case undoEdit:
+			if m.apiClient != nil {
+				_, err = m.apiClient.UpdateTask(op.taskID, *op.before)
+			} else {
+				_, err = m.planner.UpdateTask(ctx, op.taskID, *op.before)
+			}
+		}
+
+		return taskUndoneMsg{op: op, err: err}
+	}
+}
+
+// handleTaskUndone reports the undo and reloads tasks
+func (m *TasksModel) handleTaskUndone(msg taskUndoneMsg) tea.Cmd {
+	if msg.err != nil {
+		m.feedbackMessage = fmt.Sprintf("❌ Failed to undo %s: %v", msg.op.label(), msg.err)
+		return nil
+	}
+
+	m.feedbackMessage = fmt.Sprintf("↶ Undid %s: %s", msg.op.label(), msg.op.title)
+	m.loading = true
+	return m.fetchTasks()
+}
+
+// deleteTask deletes a task, recording it for undo
+func (m *TasksModel) deleteTask(task *db.Task) tea.Cmd {
+	m.pushUndo(undoOp{kind: undoDelete, taskID: task.ID, title: task.Title, status: task.Status})
+	m.feedbackMessage = "🗑 Deleted: " + task.Title + " (u: undo)"
+
+	fetch := m.fetchTasks()
+	return func() tea.Msg {
+		var err error
+		if m.apiClient != nil {
+			err = m.apiClient.DeleteTask(task.ID)
+		} else {
+			_, err = m.planner.DeleteTask(context.Background(), task.ID)
+		}
+		if err != nil {
+			return tasksLoadedMsg{err: err}
+		}
+		return fetch()
+	}
+}
+
+// SetTaskDue sets or, with nil, clears a task's due date via the remote API (undo snooze)
+func (c *APIClient) SetTaskDue(taskID string, due *time.Time) error {
+	body := map[string]string{"due_ts": ""}
+	if due != nil {
+		body["due_ts"] = due.Format(time.RFC3339)
+	}
+	resp, err := c.doRequest("POST", "/api/tasks/"+url.PathEscape(taskID)+"/due", body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// DeleteTask deletes a task via the remote API
+func (c *APIClient) DeleteTask(taskID string) error {
+	resp, err := c.doRequest("POST", "/api/tasks/"+url.PathEscape(taskID)+"/delete", nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// RestoreTask restores a deleted task to its earlier status via the remote API (undo delete)
+func (c *APIClient) RestoreTask(taskID, status string) error {
+	body := map[string]string{"status": status}
+	resp, err := c.doRequest("POST", "/api/tasks/"+url.PathEscape(taskID)+"/restore", body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/alexrabarts/focus-agent/internal/db"
+)
+
+// waitingList holds the state of the waiting-on view: requests the user sent that haven't had a
+// reply yet
+type waitingList struct {
+	items   []*db.WaitingItem
+	cursor  int
+	loading bool
+	err     error
+}
+
+type waitingLoadedMsg struct {
+	items []*db.WaitingItem
+	err   error
+}
+
+type waitingDismissedMsg struct {
+	messageID string
+	err       error
+}
+
+// IsInWaiting reports whether the threads view is showing the waiting-on list
+func (m ThreadsModel) IsInWaiting() bool {
+	return m.waiting != nil
+}
+
+// startWaiting opens the waiting-on list and loads it
+func (m *ThreadsModel) startWaiting() tea.Cmd {
+	m.waiting = &waitingList{loading: true}
+	return m.fetchWaiting()
+}
+
+func (m ThreadsModel) fetchWaiting() tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			items, err := m.apiClient.GetWaitingItems()
+			return waitingLoadedMsg{items: items, err: err}
+		}
+
+		items, err := m.database.GetWaitingItems()
+		return waitingLoadedMsg{items: items, err: err}
+	}
+}
+
+func (m ThreadsModel) dismissWaiting(messageID string) tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient != nil {
+			return waitingDismissedMsg{messageID: messageID, err: m.apiClient.DismissWaitingItem(messageID)}
+		}
+		return waitingDismissedMsg{messageID: messageID, err: m.database.DismissWaitingItem(messageID)}
+	}
+}
+
+func (m ThreadsModel) updateWaiting(msg tea.KeyMsg) (ThreadsModel, tea.Cmd) {
+	w := m.waiting
+
+	switch msg.String() {
+	case "esc", "q", "w":
+		m.waiting = nil
+	case "up", "k":
+		if w.cursor > 0 {
+			w.cursor--
+		}
+	case "down", "j":
+		if w.cursor < len(w.items)-1 {
+			w.cursor++
+		}
+	case "enter":
+		// Open the request's thread
+		if w.cursor < len(w.items) {
+			item := w.items[w.cursor]
+			m.waiting = nil
+			m = m.openSearchResult(&db.SearchResult{Kind: db.ContentKindThread, ID: item.ThreadID, Snippet: item.Subject})
+		}
+	case "x":
+		// Stop waiting on the selected request
+		if !w.loading && w.cursor < len(w.items) {
+			return m, m.dismissWaiting(w.items[w.cursor].MessageID)
+		}
+	case "r":
+		w.loading = true
+		return m, m.fetchWaiting()
+	}
+
+	return m, nil
+}
+
+func (m ThreadsModel) renderWaiting() string {
+	w := m.waiting
+	var b strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Padding(0, 1)
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf("⏳ Waiting On (%d)", len(w.items))) + "\n\n")
+
+	if w.loading {
+		b.WriteString("  Loading waiting items...\n")
+		return b.String()
+	}
+
+	if w.err != nil {
+		errorStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("196")).
+			Padding(0, 1)
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", w.err)) + "\n\n")
+	}
+
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	if len(w.items) == 0 {
+		b.WriteString(dimStyle.Padding(0, 1).Render("Nothing you've asked for is waiting on a reply.") + "\n")
+	}
+
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+	overdueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	now := time.Now()
+	for i, item := range w.items {
+		request := item.Request
+		if len(request) > 70 {
+			request = request[:67] + "..."
+		}
+
+		due := dimStyle.Render("reply due " + item.ExpectedBy.Format("Mon Jan 2"))
+		if item.Overdue(now) {
+			due = overdueStyle.Render("overdue since " + item.ExpectedBy.Format("Mon Jan 2"))
+		}
+
+		line := fmt.Sprintf("%s - %s", item.Recipient, request)
+		if i == w.cursor {
+			line = selectedStyle.Render("▶ " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(" " + line + "\n")
+		b.WriteString(dimStyle.Render(fmt.Sprintf("     %s · sent %s · ", item.Subject, item.SentAt.Format("Mon Jan 2"))))
+		b.WriteString(due + "\n\n")
+	}
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("241")).
+		Padding(1, 0, 0, 1)
+	b.WriteString(helpStyle.Render("enter: open thread | x: stop waiting | r: refresh | esc: back"))
+	return b.String()
+}
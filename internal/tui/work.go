@@ -0,0 +1,184 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/alexrabarts/focus-agent/internal/db"
+	"github.com/alexrabarts/focus-agent/internal/planner"
+)
+
+// Work timer actions a workLoadedMsg reports on
+const (
+	workStarted     = "started"
+	workStopped     = "stopped"
+	workInterrupted = "interrupted"
+)
+
+// workLoadedMsg carries the running work session and the time tracked on each task, after
+// loading them or after starting, stopping or interrupting the timer
+type workLoadedMsg struct {
+	session *db.WorkSession
+	efforts map[string]*db.TaskEffort
+	action  string          // The action taken, if any
+	stopped *db.WorkSession // The session just stopped
+	err     error
+}
+
+// fetchWork loads the running work session and the time tracked on each task
+func (m TasksModel) fetchWork() tea.Cmd {
+	return m.workCmd("", nil)
+}
+
+// toggleWork starts timing work on a task, or stops the timer if it's already timing that task
+func (m TasksModel) toggleWork(task *db.Task) tea.Cmd {
+	if m.work != nil && m.work.TaskID == task.ID {
+		return m.workCmd(workStopped, func() (*db.WorkSession, error) {
+			if m.apiClient != nil {
+				return m.apiClient.StopWork()
+			}
+			return m.planner.StopWork()
+		})
+	}
+	return m.workCmd(workStarted, func() (*db.WorkSession, error) {
+		if m.apiClient != nil {
+			return m.apiClient.StartWork(task.ID)
+		}
+		return m.planner.StartWork(task.ID)
+	})
+}
+
+// interruptWork counts an interruption against the running work session
+func (m TasksModel) interruptWork() tea.Cmd {
+	if m.work == nil {
+		return nil
+	}
+	return m.workCmd(workInterrupted, func() (*db.WorkSession, error) {
+		if m.apiClient != nil {
+			return m.apiClient.InterruptWork()
+		}
+		return m.planner.InterruptWork()
+	})
+}
+
+// workCmd takes a work timer action, if any, then reloads the running session and tracked time
+func (m TasksModel) workCmd(action string, fn func() (*db.WorkSession, error)) tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient == nil && m.planner == nil {
+			return nil
+		}
+
+		msg := workLoadedMsg{action: action}
+		if fn != nil {
+			session, err := fn()
+			if err != nil {
+				return workLoadedMsg{action: action, err: err}
+			}
+			if action == workStopped {
+				msg.stopped = session
+			}
+		}
+
+		var stats *planner.EffortStats
+		if m.apiClient != nil {
+			msg.session, msg.err = m.apiClient.GetWorkSession()
+			if msg.err == nil {
+				stats, msg.err = m.apiClient.GetEffortStats()
+			}
+		} else {
+			msg.session, msg.err = m.planner.GetWorkSession()
+			if msg.err == nil {
+				stats, msg.err = m.planner.GetEffortStats()
+			}
+		}
+		if stats != nil {
+			msg.efforts = stats.Tasks
+		}
+		return msg
+	}
+}
+
+// handleWorkLoaded applies a work timer update and confirms the action taken
+func (m *TasksModel) handleWorkLoaded(msg workLoadedMsg) {
+	if msg.err != nil {
+		if msg.action != "" {
+			m.feedbackMessage = fmt.Sprintf("✗ Work timer: %v", msg.err)
+			m.feedbackMessageTime = 0
+		}
+		return
+	}
+
+	m.work = msg.session
+	if msg.efforts != nil {
+		m.efforts = msg.efforts
+	}
+
+	switch msg.action {
+	case workStarted:
+		if m.work != nil {
+			m.feedbackMessage = fmt.Sprintf("⏱ Timing %q (F to stop, ! to log an interruption)", m.work.Task)
+		}
+	case workStopped:
+		if msg.stopped != nil {
+			m.feedbackMessage = fmt.Sprintf("⏹ Worked %s on %q", formatTracked(msg.stopped.Duration(time.Now())), msg.stopped.Task)
+		}
+	case workInterrupted:
+		if m.work != nil {
+			m.feedbackMessage = fmt.Sprintf("⚡ Interruption logged (%d this session)", m.work.Interruptions)
+		}
+	default:
+		return
+	}
+	m.feedbackMessageTime = 0
+}
+
+// workStatus returns the footer timer while a task is being timed
+func (m TasksModel) workStatus() string {
+	if m.work == nil {
+		return ""
+	}
+
+	elapsed := m.work.Duration(time.Now())
+	title := m.work.Task
+	if len(title) > 30 {
+		title = title[:27] + "..."
+	}
+	return fmt.Sprintf("⏱ %d:%02d:%02d %s", int(elapsed.Hours()), int(elapsed.Minutes())%60, int(elapsed.Seconds())%60, title)
+}
+
+// formatEffort describes the time tracked on a task against its effort estimate, or "" if none
+// has been tracked
+func (m TasksModel) formatEffort(task *db.Task) string {
+	effort, ok := m.efforts[task.ID]
+	if !ok {
+		return ""
+	}
+
+	text := fmt.Sprintf("Time tracked: %s of ~%s estimated (%d session", formatTracked(effort.Actual), formatTracked(effort.Estimate), effort.Sessions)
+	if effort.Sessions != 1 {
+		text += "s"
+	}
+	text += fmt.Sprintf(", %d interruption", effort.Interruptions)
+	if effort.Interruptions != 1 {
+		text += "s"
+	}
+	text += ")"
+	if m.work != nil && m.work.TaskID == task.ID {
+		text += " · timing now"
+	}
+	return text
+}
+
+// formatTracked shows tracked time as e.g. "45m", "3h" or "1h30m"
+func formatTracked(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	default:
+		return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+	}
+}